@@ -3,24 +3,32 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 	"github.com/weaveworks/eksctl/pkg/ctl/set"
 	"github.com/weaveworks/eksctl/pkg/ctl/unset"
 	"github.com/weaveworks/eksctl/pkg/ctl/upgrade"
 
+	"github.com/weaveworks/eksctl/pkg/audit"
+	"github.com/weaveworks/eksctl/pkg/ctl/apply"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/ctl/completion"
 	"github.com/weaveworks/eksctl/pkg/ctl/create"
 	"github.com/weaveworks/eksctl/pkg/ctl/delete"
+	"github.com/weaveworks/eksctl/pkg/ctl/diff"
 	"github.com/weaveworks/eksctl/pkg/ctl/drain"
 	"github.com/weaveworks/eksctl/pkg/ctl/enable"
+	"github.com/weaveworks/eksctl/pkg/ctl/explain"
 	"github.com/weaveworks/eksctl/pkg/ctl/generate"
 	"github.com/weaveworks/eksctl/pkg/ctl/get"
+	"github.com/weaveworks/eksctl/pkg/ctl/register"
 	"github.com/weaveworks/eksctl/pkg/ctl/scale"
 	"github.com/weaveworks/eksctl/pkg/ctl/update"
 	"github.com/weaveworks/eksctl/pkg/ctl/utils"
+	"github.com/weaveworks/eksctl/pkg/ctl/validate"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
@@ -33,12 +41,17 @@ func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
 	rootCmd.AddCommand(unset.Command(flagGrouping))
 	rootCmd.AddCommand(scale.Command(flagGrouping))
 	rootCmd.AddCommand(drain.Command(flagGrouping))
+	rootCmd.AddCommand(register.Command(flagGrouping))
+	rootCmd.AddCommand(diff.Command(flagGrouping))
+	rootCmd.AddCommand(apply.Command(flagGrouping))
 	if os.Getenv("EKSCTL_EXPERIMENTAL") == "true" {
 		rootCmd.AddCommand(generate.Command(flagGrouping))
 		rootCmd.AddCommand(enable.Command(flagGrouping))
 	}
 	rootCmd.AddCommand(utils.Command(flagGrouping))
 	rootCmd.AddCommand(completion.Command(rootCmd))
+	rootCmd.AddCommand(explain.Command())
+	rootCmd.AddCommand(validate.Command(flagGrouping))
 	rootCmd.AddCommand(versionCmd(flagGrouping))
 }
 
@@ -65,18 +78,41 @@ func main() {
 	rootCmd.PersistentFlags().IntVarP(&logger.Level, "verbose", "v", 3, "set log level, use 0 to silence, 4 for debugging and 5 for debugging with AWS debug logging")
 
 	colorValue := rootCmd.PersistentFlags().StringP("color", "C", "true", "toggle colorized logs (valid options: true, false, fabulous)")
+	logFormat := rootCmd.PersistentFlags().String("log-format", string(logger.TextFormat), fmt.Sprintf("format of the logs (valid options: %s), JSON logs are written to stderr", strings.Join(logger.Formats(), ", ")))
+	quiet := rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only log warnings, errors and, with --log-format=json, progress events")
+	auditLogPath := rootCmd.PersistentFlags().String("audit-log", "", "append the AWS and Kubernetes API calls that may change resources to a file, one JSON object per line")
 
 	cobra.OnInitialize(func() {
-		// Control colored output
-		logger.Color = *colorValue == "true"
-		logger.Fabulous = *colorValue == "fabulous"
-		// Add timestamps for debugging
-		logger.Timestamps = logger.Level >= 4
+		err := logger.Configure(logger.Options{
+			Format:   logger.Format(*logFormat),
+			Quiet:    *quiet,
+			Color:    *colorValue == "true",
+			Fabulous: *colorValue == "fabulous",
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+			os.Exit(1)
+		}
+
+		// commands such as apply execute other commands, which must keep writing to the same log
+		if *auditLogPath != "" && audit.Default == nil {
+			if audit.Default, err = audit.Open(*auditLogPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
 	})
 
 	rootCmd.SetUsageFunc(flagGrouping.Usage)
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	eks.DefaultAPICallStats.Log()
+	if audit.Default != nil {
+		if err := audit.Default.Close(); err != nil {
+			logger.Warning("closing the audit log: %s", err.Error())
+		}
+	}
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// main writes the documentation of the fields of the config file types, taken from
+// the comments of the struct fields, to a Go file so that it's available offline
+// to `eksctl explain`
+func main() {
+	if len(os.Args) != 3 {
+		panic("expected two arguments with the API package directory and the output file")
+	}
+	packageDir, outputFile := os.Args[1], os.Args[2]
+
+	isSource := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && !strings.HasPrefix(fi.Name(), "zz_generated")
+	}
+	pkgs, err := parser.ParseDir(token.NewFileSet(), packageDir, isSource, parser.ParseComments)
+	if err != nil {
+		panic(err)
+	}
+
+	var (
+		packageName string
+		docs        = map[string]string{}
+		since       = map[string]string{}
+	)
+	for name, pkg := range pkgs {
+		packageName = name
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				decl, ok := n.(*ast.GenDecl)
+				if !ok || decl.Tok != token.TYPE {
+					return true
+				}
+				for _, spec := range decl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					// the doc comment of a type that isn't in a group belongs to the declaration
+					doc := typeSpec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					if description, _ := parseDoc(doc); description != "" {
+						docs[typeSpec.Name.Name] = description
+					}
+					addFieldDocs(typeSpec, docs, since)
+				}
+				return false
+			})
+		}
+	}
+
+	keys := []string{}
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	for key := range since {
+		if _, ok := docs[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by cmd/fielddocs/generate.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", packageName)
+	out.WriteString("var fieldDocs = map[string]fieldDoc{\n")
+	for _, key := range keys {
+		fmt.Fprintf(&out, "%q: {description: %q, since: %q},\n", key, docs[key], since[key])
+	}
+	out.WriteString("}\n")
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(outputFile, source, 0644); err != nil {
+		panic(err)
+	}
+}
+
+// addFieldDocs adds the documentation of the fields of a struct type, keyed by
+// the name of the type and the name of the field, e.g. NodeGroup.IAM
+func addFieldDocs(typeSpec *ast.TypeSpec, docs, since map[string]string) {
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return
+	}
+	for _, field := range structType.Fields.List {
+		description, sinceVersion := parseDoc(field.Doc)
+		for _, fieldName := range field.Names {
+			key := typeSpec.Name.Name + "." + fieldName.Name
+			if description != "" {
+				docs[key] = description
+			}
+			if sinceVersion != "" {
+				since[key] = sinceVersion
+			}
+		}
+	}
+}
+
+// parseDoc returns the text of a doc comment without its markers, e.g. `+optional`,
+// and the version set with the `+since=X.Y.Z` marker
+func parseDoc(doc *ast.CommentGroup) (string, string) {
+	var (
+		lines []string
+		since string
+	)
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "+since="):
+			since = strings.TrimPrefix(line, "+since=")
+		case strings.HasPrefix(line, "+"), line == "":
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " "), since
+}
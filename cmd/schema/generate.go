@@ -5,8 +5,7 @@ import (
 	"os"
 	"strings"
 
-	"github.com/alecthomas/jsonschema"
-	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/explain"
 	"sigs.k8s.io/yaml"
 )
 
@@ -23,7 +22,7 @@ func main() {
 `)
 	document.WriteString("```yaml\n")
 
-	schema := jsonschema.Reflect(&api.ClusterConfig{})
+	schema := explain.Schema()
 	yamlSchema, err := yaml.Marshal(schema.Definitions)
 	if err != nil {
 		panic(err)
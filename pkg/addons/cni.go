@@ -0,0 +1,127 @@
+package addons
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/git/executor"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// HelmCommand is the Helm binary that installs the CNI plugins
+const HelmCommand = "helm"
+
+const (
+	// CiliumChartVersion is the version of the Cilium chart installed by default, Cilium 1.9 is the
+	// last release that supports all the Kubernetes versions of eksctl
+	CiliumChartVersion = "1.9.18"
+	// CalicoChartVersion is the version of the Tigera operator chart installed by default
+	CalicoChartVersion = "v3.17.6"
+)
+
+// cniChart is the Helm chart of a CNI plugin, and the values it's installed with on EKS; the values
+// are the ones of the default version of the chart, network.chartVersion overrides it
+type cniChart struct {
+	release    string
+	repository string
+	chart      string
+	version    string
+	namespace  string
+	values     []string
+}
+
+func cniCharts(cfg *api.ClusterConfig) map[string]cniChart {
+	return map[string]cniChart{
+		// ENI mode gives pods IPs of the VPC like the VPC CNI, using the network interfaces of the nodes
+		api.CNICilium: {
+			release:    "cilium",
+			repository: "https://helm.cilium.io",
+			chart:      "cilium",
+			version:    CiliumChartVersion,
+			namespace:  "kube-system",
+			values: []string{
+				"eni=true",
+				"ipam.mode=eni",
+				"egressMasqueradeInterfaces=eth0",
+				"tunnel=disabled",
+				fmt.Sprintf("cluster.name=%s", cfg.Metadata.Name),
+			},
+		},
+		// pods get IPs of an overlay network, as the control plane can't reach them, webhooks must use
+		// the network of the host
+		api.CNICalico: {
+			release:    "calico",
+			repository: "https://docs.tigera.io/calico/charts",
+			chart:      "tigera-operator",
+			version:    CalicoChartVersion,
+			namespace:  "tigera-operator",
+			values: []string{
+				"installation.kubernetesProvider=EKS",
+				"installation.cni.type=Calico",
+				"installation.calicoNetwork.bgp=Disabled",
+				fmt.Sprintf("installation.calicoNetwork.ipPools[0].cidr=%s", cfg.PodCIDR()),
+				"installation.calicoNetwork.ipPools[0].encapsulation=VXLAN",
+			},
+		},
+	}
+}
+
+// CheckHelm checks that Helm is available to install the CNI plugin of the cluster, so that the
+// creation of the cluster fails early when it's not
+func CheckHelm(cfg *api.ClusterConfig) error {
+	if !cfg.HasAlternateCNI() {
+		return nil
+	}
+	if _, err := exec.LookPath(HelmCommand); err != nil {
+		return fmt.Errorf("%s must be on the PATH to install network.cni %s: %v", HelmCommand, cfg.CNI(), err)
+	}
+	return nil
+}
+
+// CNIInstaller installs CNI plugins other than the VPC CNI with Helm
+type CNIInstaller struct {
+	executor       executor.Executor
+	kubeconfigPath string
+}
+
+// NewCNIInstaller creates a CNIInstaller running Helm against the cluster of the kubeconfig
+func NewCNIInstaller(executor executor.Executor, kubeconfigPath string) *CNIInstaller {
+	return &CNIInstaller{
+		executor:       executor,
+		kubeconfigPath: kubeconfigPath,
+	}
+}
+
+// Install installs or upgrades the CNI plugin of the cluster; it doesn't wait for the plugin to be
+// ready, as it's installed before the nodes join the cluster
+func (i *CNIInstaller) Install(cfg *api.ClusterConfig) error {
+	chart, ok := cniCharts(cfg)[cfg.CNI()]
+	if !ok {
+		return fmt.Errorf("no Helm chart for network.cni %s", cfg.CNI())
+	}
+	if cfg.Network.ChartVersion != "" {
+		chart.version = cfg.Network.ChartVersion
+	}
+	args := []string{
+		"upgrade", chart.release, chart.chart,
+		"--install",
+		"--repo", chart.repository,
+		"--namespace", chart.namespace,
+		"--create-namespace",
+		"--kubeconfig", i.kubeconfigPath,
+		"--version", chart.version,
+	}
+	for _, value := range chart.values {
+		args = append(args, "--set", value)
+	}
+
+	logger.Info("installing %s with chart %s %s of %s", cfg.CNI(), chart.chart, chart.version, chart.repository)
+	if err := i.executor.Exec(HelmCommand, "", args...); err != nil {
+		return errors.Wrapf(err, "installing %s with Helm", cfg.CNI())
+	}
+	logger.Success("installed %s", cfg.CNI())
+	return nil
+}
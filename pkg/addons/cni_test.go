@@ -0,0 +1,63 @@
+package addons
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/git/executor"
+)
+
+var _ = Describe("CNIInstaller", func() {
+	var (
+		fakeExecutor *executor.FakeExecutor
+		cfg          *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		fakeExecutor = new(executor.FakeExecutor)
+		fakeExecutor.On("Exec", HelmCommand, "", mock.Anything).Return(nil)
+
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Network = &api.ClusterNetwork{CNI: api.CNICilium}
+	})
+
+	install := func() []string {
+		Expect(NewCNIInstaller(fakeExecutor, "/tmp/kubeconfig").Install(cfg)).To(Succeed())
+		fakeExecutor.AssertNumberOfCalls(GinkgoT(), "Exec", 1)
+		return fakeExecutor.Calls[0].Arguments.Get(2).([]string)
+	}
+
+	It("installs the pinned version of the Cilium chart", func() {
+		Expect(install()).To(Equal([]string{
+			"upgrade", "cilium", "cilium",
+			"--install",
+			"--repo", "https://helm.cilium.io",
+			"--namespace", "kube-system",
+			"--create-namespace",
+			"--kubeconfig", "/tmp/kubeconfig",
+			"--version", "1.9.18",
+			"--set", "eni=true",
+			"--set", "ipam.mode=eni",
+			"--set", "egressMasqueradeInterfaces=eth0",
+			"--set", "tunnel=disabled",
+			"--set", "cluster.name=cluster-1",
+		}))
+	})
+
+	It("installs the pinned version of the Tigera operator chart for Calico", func() {
+		cfg.Network.CNI = api.CNICalico
+		args := install()
+		Expect(args).To(ContainElement("tigera-operator"))
+		Expect(args).To(ContainElement(CalicoChartVersion))
+	})
+
+	It("installs network.chartVersion instead of the pinned version", func() {
+		cfg.Network.ChartVersion = "1.9.10"
+		args := install()
+		Expect(args).To(ContainElement("1.9.10"))
+		Expect(args).NotTo(ContainElement(CiliumChartVersion))
+	})
+})
@@ -2,16 +2,20 @@ package defaultaddons
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/addons"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -19,11 +23,15 @@ const (
 	AWSNode = "aws-node"
 
 	awsNodeImageFormatPrefix = "%s.dkr.ecr.%s.%s/amazon-k8s-cni"
+
+	// ciliumAWSNodeSelector keeps aws-node off the nodes without the label, as Cilium recommends,
+	// so that it can be enabled again without reinstalling it
+	ciliumAWSNodeSelector = "io.cilium/aws-node-enabled"
 )
 
 // UpdateAWSNode will update the `aws-node` add-on and returns true
-// if an update is available.
-func UpdateAWSNode(rawClient kubernetes.RawClientInterface, region string, plan bool) (bool, error) {
+// if an update is available. The image is pinned to imageTag when it's set
+func UpdateAWSNode(rawClient kubernetes.RawClientInterface, region, imageTag string, plan bool) (bool, error) {
 	clusterDaemonSet, err := rawClient.ClientSet().AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
 	if err != nil {
 		if apierrs.IsNotFound(err) {
@@ -60,13 +68,23 @@ func UpdateAWSNode(rawClient kubernetes.RawClientInterface, region string, plan
 			if err := addons.UseRegionalImage(&daemonSet.Spec.Template, region); err != nil {
 				return false, err
 			}
-			tagMismatch, err = addons.ImageTagsDiffer(
-				container.Image,
-				clusterDaemonSet.Spec.Template.Spec.Containers[0].Image,
-			)
+			if imageTag != "" {
+				if container.Image, err = addons.SetImageTag(container.Image, imageTag); err != nil {
+					return false, err
+				}
+			}
+			installedImage := clusterDaemonSet.Spec.Template.Spec.Containers[0].Image
+			tagMismatch, err = addons.ImageTagsDiffer(container.Image, installedImage)
 			if err != nil {
 				return false, err
 			}
+			setPreviousImage(&daemonSet.ObjectMeta, clusterDaemonSet.ObjectMeta, installedImage, container.Image)
+			keepCiliumAWSNodeSelector(&daemonSet.Spec.Template.Spec, clusterDaemonSet.Spec.Template.Spec)
+			if plan {
+				if err := logObjectDiff(AWSNode, clusterDaemonSet, daemonSet); err != nil {
+					return false, err
+				}
+			}
 		}
 
 		if resource.GVK.Kind == "CustomResourceDefinition" && plan {
@@ -92,6 +110,122 @@ func UpdateAWSNode(rawClient kubernetes.RawClientInterface, region string, plan
 		return false, nil
 	}
 
-	logger.Info("%q is now up-to-date", AWSNode)
+	logger.Emit(logger.EventAddonInstalled, map[string]string{"addon": AWSNode}, "%q is now up-to-date", AWSNode)
 	return false, nil
 }
+
+// BundledAWSNodeVersion returns the version of the aws-node manifest that eksctl installs,
+// i.e. the tag of the image of its DaemonSet
+func BundledAWSNodeVersion() (string, error) {
+	list, err := LoadAsset(AWSNode, "yaml")
+	if err != nil {
+		return "", err
+	}
+	for _, rawObj := range list.Items {
+		daemonSet, ok := rawObj.Object.(*appsv1.DaemonSet)
+		if !ok {
+			continue
+		}
+		if len(daemonSet.Spec.Template.Spec.Containers) == 0 {
+			return "", fmt.Errorf("%s has no containers", AWSNode)
+		}
+		return addons.ImageTag(daemonSet.Spec.Template.Spec.Containers[0].Image)
+	}
+	return "", fmt.Errorf("no DaemonSet in the manifest of %q", AWSNode)
+}
+
+// SetAWSNodeEnv sets environment variables of the aws-node container, which enable and configure
+// features of the VPC CNI, and returns true if the DaemonSet was changed
+func SetAWSNodeEnv(clientSet kubernetes.Interface, env map[string]string) (bool, error) {
+	daemonSets := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem)
+	awsNode, err := daemonSets.Get(AWSNode, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting %q", AWSNode)
+	}
+
+	container := &awsNode.Spec.Template.Spec.Containers[0]
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changed := false
+	for _, name := range names {
+		value := env[name]
+		found := false
+		for i := range container.Env {
+			if container.Env[i].Name == name {
+				found = true
+				if container.Env[i].Value != value || container.Env[i].ValueFrom != nil {
+					container.Env[i] = corev1.EnvVar{Name: name, Value: value}
+					changed = true
+				}
+			}
+		}
+		if !found {
+			container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: value})
+			changed = true
+		}
+	}
+	if !changed {
+		logger.Info("%q already has %s", AWSNode, formatEnv(names, env))
+		return false, nil
+	}
+
+	if _, err := daemonSets.Update(awsNode); err != nil {
+		return false, errors.Wrapf(err, "updating %q", AWSNode)
+	}
+	logger.Info("set %s on %q", formatEnv(names, env), AWSNode)
+	return true, nil
+}
+
+func formatEnv(names []string, env map[string]string) string {
+	vars := make([]string, 0, len(names))
+	for _, name := range names {
+		vars = append(vars, name+"="+env[name])
+	}
+	return strings.Join(vars, ", ")
+}
+
+// keepCiliumAWSNodeSelector carries over the node selector that keeps aws-node off the nodes of a
+// Cilium cluster, which the manifest doesn't have, so that updating aws-node doesn't enable it again
+func keepCiliumAWSNodeSelector(spec *corev1.PodSpec, installed corev1.PodSpec) {
+	value, ok := installed.NodeSelector[ciliumAWSNodeSelector]
+	if !ok {
+		return
+	}
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	spec.NodeSelector[ciliumAWSNodeSelector] = value
+}
+
+// DisableAWSNode stops aws-node from running on the nodes that will join the cluster, so that the given
+// CNI plugin can manage the network of the pods: it's patched with a node selector that no node has for
+// Cilium, and deleted for other plugins
+func DisableAWSNode(clientSet kubernetes.Interface, cni string) error {
+	daemonSets := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem)
+	if cni == api.CNICilium {
+		patch := fmt.Sprintf(`{"spec":{"template":{"spec":{"nodeSelector":{%q:"true"}}}}}`, ciliumAWSNodeSelector)
+		if _, err := daemonSets.Patch(AWSNode, types.MergePatchType, []byte(patch)); err != nil {
+			if apierrs.IsNotFound(err) {
+				logger.Info("%q was not found, nothing to disable", AWSNode)
+				return nil
+			}
+			return errors.Wrapf(err, "patching %q", AWSNode)
+		}
+		logger.Info("%q will only run on nodes labelled %s=true", AWSNode, ciliumAWSNodeSelector)
+		return nil
+	}
+
+	if err := daemonSets.Delete(AWSNode, &metav1.DeleteOptions{}); err != nil {
+		if apierrs.IsNotFound(err) {
+			logger.Info("%q was not found, nothing to disable", AWSNode)
+			return nil
+		}
+		return errors.Wrapf(err, "deleting %q", AWSNode)
+	}
+	logger.Info("deleted %q, replaced by %s", AWSNode, cni)
+	return nil
+}
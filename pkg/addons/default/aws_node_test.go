@@ -5,9 +5,12 @@ import (
 	. "github.com/onsi/gomega"
 
 	. "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 
 	"github.com/weaveworks/eksctl/pkg/testutils"
 
+	appsv1 "k8s.io/api/apps/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -54,7 +57,7 @@ var _ = Describe("default addons - aws-node", func() {
 		It("can update 1.12 sample to latest", func() {
 			rawClient.AssumeObjectsMissing = false
 
-			_, err := UpdateAWSNode(rawClient, "eu-west-2", false)
+			_, err := UpdateAWSNode(rawClient, "eu-west-2", "", false)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(rawClient.Collection.UpdatedItems()).To(HaveLen(4))
 			Expect(rawClient.Collection.CreatedItems()).To(HaveLen(10))
@@ -74,7 +77,7 @@ var _ = Describe("default addons - aws-node", func() {
 		It("can update 1.12 sample for different region", func() {
 			rawClient.ClientSetUseUpdatedObjects = false // must be set for subsequent UpdateAWSNode
 
-			_, err := UpdateAWSNode(rawClient, "us-east-1", false)
+			_, err := UpdateAWSNode(rawClient, "us-east-1", "", false)
 			Expect(err).ToNot(HaveOccurred())
 
 			rawClient.ClientSetUseUpdatedObjects = true // for verification of updated objects
@@ -88,9 +91,79 @@ var _ = Describe("default addons - aws-node", func() {
 		})
 		It("detects matching image version when determining plan", func() {
 			// updating from latest to latest needs no updating
-			needsUpdate, err := UpdateAWSNode(rawClient, "eu-west-2", true)
+			needsUpdate, err := UpdateAWSNode(rawClient, "eu-west-2", "", true)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(needsUpdate).To(BeFalse())
 		})
 	})
+
+	Describe("can update aws-node disabled by Cilium", func() {
+		It("keeps the node selector of Cilium", func() {
+			rawClient := testutils.NewFakeRawClient()
+			rawClient.AssumeObjectsMissing = true
+			for _, item := range testutils.LoadSamples("testdata/sample-1.12.json") {
+				if daemonSet, ok := item.(*appsv1.DaemonSet); ok && daemonSet.Name == AWSNode {
+					daemonSet.Spec.Template.Spec.NodeSelector = map[string]string{"io.cilium/aws-node-enabled": "true"}
+				}
+				rc, err := rawClient.NewRawResource(item)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = rc.CreateOrReplace(false)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			rawClient.AssumeObjectsMissing = false
+
+			_, err := UpdateAWSNode(rawClient, "eu-west-2", "", false)
+			Expect(err).ToNot(HaveOccurred())
+
+			rawClient.ClientSetUseUpdatedObjects = true
+			awsNode, err := rawClient.ClientSet().AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(awsNode.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("io.cilium/aws-node-enabled", "true"))
+		})
+	})
+
+	Describe("can set environment variables of aws-node", func() {
+		It("adds and updates variables, and only updates the DaemonSet when they change", func() {
+			clientSet, _ := testutils.NewFakeClientSetWithSamples("testdata/sample-1.12.json")
+
+			changed, err := SetAWSNodeEnv(clientSet, map[string]string{"ENABLE_POD_ENI": "true", "AWS_VPC_K8S_CNI_LOGLEVEL": "INFO"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+
+			awsNode, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			env := map[string]string{}
+			for _, e := range awsNode.Spec.Template.Spec.Containers[0].Env {
+				env[e.Name] = e.Value
+			}
+			Expect(env).To(HaveKeyWithValue("ENABLE_POD_ENI", "true"))
+			Expect(env).To(HaveKeyWithValue("AWS_VPC_K8S_CNI_LOGLEVEL", "INFO"))
+
+			changed, err = SetAWSNodeEnv(clientSet, map[string]string{"ENABLE_POD_ENI": "true"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+	})
+
+	Describe("can disable aws-node for other CNI plugins", func() {
+		It("keeps aws-node off the nodes for Cilium", func() {
+			clientSet, _ := testutils.NewFakeClientSetWithSamples("testdata/sample-1.12.json")
+			Expect(DisableAWSNode(clientSet, api.CNICilium)).To(Succeed())
+
+			awsNode, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(awsNode.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("io.cilium/aws-node-enabled", "true"))
+		})
+
+		It("deletes aws-node for Calico", func() {
+			clientSet, _ := testutils.NewFakeClientSetWithSamples("testdata/sample-1.12.json")
+			Expect(DisableAWSNode(clientSet, api.CNICalico)).To(Succeed())
+
+			_, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+			By("ignoring aws-node once it's deleted")
+			Expect(DisableAWSNode(clientSet, api.CNICalico)).To(Succeed())
+		})
+	})
 })
@@ -0,0 +1,168 @@
+package defaultaddons
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/weaveworks/eksctl/pkg/addons"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// MaxKubeProxyVersionSkew is the number of minor versions kube-proxy can be older than the control plane
+const MaxKubeProxyVersionSkew = 2
+
+// minimumCoreDNSVersions are the oldest CoreDNS versions supported with each Kubernetes
+// version, they are the versions of the bundled manifests
+var minimumCoreDNSVersions = map[string]string{
+	api.Version1_12: "v1.2.2",
+	api.Version1_13: "v1.2.6",
+	api.Version1_14: "v1.6.6",
+	api.Version1_15: "v1.6.6",
+}
+
+// Incompatibility describes an installed default add-on that doesn't support a Kubernetes version
+type Incompatibility struct {
+	Name              string
+	InstalledVersion  string
+	MinimumVersion    string
+	KubernetesVersion string
+}
+
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("%s %s (Kubernetes %s requires %s or later)", i.Name, i.InstalledVersion, i.KubernetesVersion, i.MinimumVersion)
+}
+
+// MinimumVersion returns the oldest version of the add-on of the given name that supports
+// kubernetesVersion, a minor version, e.g. 1.15; it's empty when the version of the
+// add-on isn't tied to the Kubernetes version, as it's the case for AWSNode
+func MinimumVersion(name, kubernetesVersion string) (string, error) {
+	switch name {
+	case AWSNode:
+		return "", nil
+	case CoreDNS:
+		minimumVersion, ok := minimumCoreDNSVersions[kubernetesVersion]
+		if !ok {
+			return "", fmt.Errorf("the %s versions supported with Kubernetes %s are not known", CoreDNS, kubernetesVersion)
+		}
+		return minimumVersion, nil
+	case KubeProxy:
+		v, err := semver.ParseTolerant(kubernetesVersion)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing Kubernetes version %q", kubernetesVersion)
+		}
+		if v.Minor < MaxKubeProxyVersionSkew {
+			return fmt.Sprintf("v%d.0.0", v.Major), nil
+		}
+		return fmt.Sprintf("v%d.%d.0", v.Major, v.Minor-MaxKubeProxyVersionSkew), nil
+	default:
+		return "", fmt.Errorf("unknown add-on %q", name)
+	}
+}
+
+// CheckCompatibility returns the installed default add-ons whose version doesn't
+// support kubernetesVersion, a minor version, e.g. 1.15; add-ons that aren't
+// installed are ignored, and AWSNode isn't checked, as its version isn't tied
+// to the Kubernetes version
+func CheckCompatibility(clientSet kubernetes.Interface, kubernetesVersion string) ([]Incompatibility, error) {
+	var incompatibilities []Incompatibility
+	for _, name := range []string{CoreDNS, KubeProxy} {
+		installedVersion, err := InstalledVersion(clientSet, name)
+		if err != nil {
+			if apierrs.IsNotFound(errors.Cause(err)) {
+				continue
+			}
+			return nil, err
+		}
+		incompatibility, err := checkVersion(name, installedVersion, kubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+		if incompatibility != nil {
+			incompatibilities = append(incompatibilities, *incompatibility)
+		}
+	}
+	return incompatibilities, nil
+}
+
+func checkVersion(name, installedVersion, kubernetesVersion string) (*Incompatibility, error) {
+	minimumVersion, err := MinimumVersion(name, kubernetesVersion)
+	if err != nil || minimumVersion == "" {
+		return nil, err
+	}
+
+	installed, err := releaseVersion(installedVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing the version of %q", name)
+	}
+	minimum, err := releaseVersion(minimumVersion)
+	if err != nil {
+		return nil, err
+	}
+	if installed.GE(minimum) {
+		return nil, nil
+	}
+	return &Incompatibility{
+		Name:              name,
+		InstalledVersion:  installedVersion,
+		MinimumVersion:    minimumVersion,
+		KubernetesVersion: kubernetesVersion,
+	}, nil
+}
+
+// IsMinVersion returns true if version, the image tag of an add-on, is minimumVersion or later,
+// the build suffix of EKS images being ignored
+func IsMinVersion(minimumVersion, version string) (bool, error) {
+	v, err := releaseVersion(version)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing version %q", version)
+	}
+	minimum, err := releaseVersion(minimumVersion)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing version %q", minimumVersion)
+	}
+	return v.GE(minimum), nil
+}
+
+// releaseVersion parses an image tag, ignoring the build suffix of EKS
+// images, e.g. v1.14.9-eksbuild.1 is parsed as 1.14.9
+func releaseVersion(tag string) (semver.Version, error) {
+	v, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}, nil
+}
+
+// InstalledVersion returns the version of the installed default add-on of the given name,
+// i.e. the tag of the image of its first container
+func InstalledVersion(clientSet kubernetes.Interface, name string) (string, error) {
+	var image string
+	switch name {
+	case AWSNode, KubeProxy:
+		daemonSet, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "getting %q", name)
+		}
+		if len(daemonSet.Spec.Template.Spec.Containers) == 0 {
+			return "", fmt.Errorf("%s has no containers", name)
+		}
+		image = daemonSet.Spec.Template.Spec.Containers[0].Image
+	case CoreDNS:
+		deployment, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "getting %q", name)
+		}
+		if len(deployment.Spec.Template.Spec.Containers) == 0 {
+			return "", fmt.Errorf("%s has no containers", name)
+		}
+		image = deployment.Spec.Template.Spec.Containers[0].Image
+	default:
+		return "", fmt.Errorf("unknown add-on %q", name)
+	}
+	return addons.ImageTag(image)
+}
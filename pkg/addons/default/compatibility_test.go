@@ -0,0 +1,71 @@
+package defaultaddons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("default addons - compatibility", func() {
+	var (
+		clientSet *fake.Clientset
+	)
+
+	BeforeEach(func() {
+		clientSet, _ = testutils.NewFakeClientSetWithSamples("testdata/sample-1.13.json")
+	})
+
+	It("accepts the add-ons of the installed version", func() {
+		incompatibilities, err := CheckCompatibility(clientSet, "1.13")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(incompatibilities).To(BeEmpty())
+	})
+
+	It("reports the add-ons too old for a version", func() {
+		incompatibilities, err := CheckCompatibility(clientSet, "1.14")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(incompatibilities).To(Equal([]Incompatibility{
+			{Name: CoreDNS, InstalledVersion: "v1.2.6", MinimumVersion: "v1.6.6", KubernetesVersion: "1.14"},
+		}))
+		Expect(incompatibilities[0].String()).To(Equal("coredns v1.2.6 (Kubernetes 1.14 requires v1.6.6 or later)"))
+	})
+
+	It("allows kube-proxy to be older than the control plane by the maximum skew", func() {
+		minimumVersion, err := MinimumVersion(KubeProxy, "1.15")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(minimumVersion).To(Equal("v1.13.0"))
+
+		minimumVersion, err = MinimumVersion(AWSNode, "1.15")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(minimumVersion).To(BeEmpty())
+	})
+
+	It("ignores the add-ons that aren't installed", func() {
+		Expect(DisableAWSNode(clientSet, api.CNICalico)).To(Succeed())
+		Expect(clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Delete(CoreDNS, &metav1.DeleteOptions{})).To(Succeed())
+
+		incompatibilities, err := CheckCompatibility(clientSet, "1.14")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(incompatibilities).To(BeEmpty())
+	})
+
+	It("rejects unknown versions", func() {
+		_, err := CheckCompatibility(clientSet, "1.99")
+		Expect(err).To(MatchError("the coredns versions supported with Kubernetes 1.99 are not known"))
+	})
+
+	It("compares versions of add-ons without their EKS build suffix", func() {
+		Expect(IsMinVersion("v1.9.0", "v1.9.0-eksbuild.1")).To(BeTrue())
+		Expect(IsMinVersion("v1.9.0", "v1.7.5")).To(BeFalse())
+	})
+
+	It("reads the version of the aws-node manifest of eksctl", func() {
+		Expect(BundledAWSNodeVersion()).To(Equal("v1.6.0"))
+	})
+})
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/addons"
 	appsv1 "k8s.io/api/apps/v1"
@@ -14,6 +13,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -24,8 +24,8 @@ const (
 )
 
 // UpdateCoreDNS will update the `coredns` add-on and returns true
-// if an update is available
-func UpdateCoreDNS(rawClient kubernetes.RawClientInterface, region, controlPlaneVersion string, plan bool) (bool, error) {
+// if an update is available. The image is pinned to imageTag when it's set
+func UpdateCoreDNS(rawClient kubernetes.RawClientInterface, region, controlPlaneVersion, imageTag string, plan bool) (bool, error) {
 	kubeDNSSevice, err := rawClient.ClientSet().CoreV1().Services(metav1.NamespaceSystem).Get(KubeDNS, metav1.GetOptions{})
 	if err != nil {
 		if apierrs.IsNotFound(err) {
@@ -68,13 +68,23 @@ func UpdateCoreDNS(rawClient kubernetes.RawClientInterface, region, controlPlane
 			if err := addons.UseRegionalImage(&deployment.Spec.Template, region); err != nil {
 				return false, err
 			}
-			tagMismatch, err = addons.ImageTagsDiffer(
-				deployment.Spec.Template.Spec.Containers[0].Image,
-				kubeDNSDeployment.Spec.Template.Spec.Containers[0].Image,
-			)
+			image := &deployment.Spec.Template.Spec.Containers[0].Image
+			if imageTag != "" {
+				if *image, err = addons.SetImageTag(*image, imageTag); err != nil {
+					return false, err
+				}
+			}
+			installedImage := kubeDNSDeployment.Spec.Template.Spec.Containers[0].Image
+			tagMismatch, err = addons.ImageTagsDiffer(*image, installedImage)
 			if err != nil {
 				return false, err
 			}
+			setPreviousImage(&deployment.ObjectMeta, kubeDNSDeployment.ObjectMeta, installedImage, *image)
+			if plan {
+				if err := logObjectDiff(CoreDNS, kubeDNSDeployment, deployment); err != nil {
+					return false, err
+				}
+			}
 		case "Service":
 			resource.Info.Object.(*corev1.Service).SetResourceVersion(kubeDNSSevice.GetResourceVersion())
 			resource.Info.Object.(*corev1.Service).Spec.ClusterIP = kubeDNSSevice.Spec.ClusterIP
@@ -96,7 +106,7 @@ func UpdateCoreDNS(rawClient kubernetes.RawClientInterface, region, controlPlane
 		return false, nil
 	}
 
-	logger.Info("%q is now up-to-date", CoreDNS)
+	logger.Emit(logger.EventAddonInstalled, map[string]string{"addon": CoreDNS}, "%q is now up-to-date", CoreDNS)
 	return false, nil
 }
 
@@ -84,7 +84,7 @@ var _ = Describe("default addons - coredns", func() {
 		})
 
 		It("can update to correct version", func() {
-			_, err := UpdateCoreDNS(rawClient, "eu-west-2", "1.12.x", false)
+			_, err := UpdateCoreDNS(rawClient, "eu-west-2", "1.12.x", "", false)
 			Expect(err).ToNot(HaveOccurred())
 			checkCoreDNSImage(rawClient, "eu-west-2", "v1.2.2", false)
 
@@ -133,17 +133,17 @@ var _ = Describe("default addons - coredns", func() {
 		})
 
 		It("detects coredns version match local vs cluster", func() {
-			needsUpdate, err := UpdateCoreDNS(rawClient, "eu-west-2", "1.12.x", true)
+			needsUpdate, err := UpdateCoreDNS(rawClient, "eu-west-2", "1.12.x", "", true)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(needsUpdate).To(BeFalse())
 
-			needsUpdate, err = UpdateCoreDNS(rawClient, "eu-west-2", "1.13.x", true)
+			needsUpdate, err = UpdateCoreDNS(rawClient, "eu-west-2", "1.13.x", "", true)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(needsUpdate).To(BeTrue())
 		})
 
 		It("can update to correct version", func() {
-			_, err := UpdateCoreDNS(rawClient, "eu-west-2", "1.13.x", false)
+			_, err := UpdateCoreDNS(rawClient, "eu-west-2", "1.13.x", "", false)
 			Expect(err).ToNot(HaveOccurred())
 			checkCoreDNSImage(rawClient, "eu-west-2", "v1.2.6", false)
 
@@ -0,0 +1,323 @@
+package defaultaddons
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/weaveworks/eksctl/pkg/addons"
+)
+
+// deprecatedKeys lists the configuration keys of each addon that are no longer
+// supported by the defaults of recent Kubernetes versions, and what replaces them
+var deprecatedKeys = map[string]map[string]string{
+	CoreDNS: {
+		".:53/proxy": `the "proxy" plugin was removed in CoreDNS 1.6, use "forward" instead`,
+	},
+	KubeProxy: {
+		"--resource-container": "the flag was removed in kube-proxy 1.16",
+	},
+}
+
+// ConfigChange describes a configuration key of an addon that differs from the default
+type ConfigChange struct {
+	Key string
+	// Installed is the installed value, it's nil if the key is not set in the cluster
+	Installed *string
+	// Default is the default value, it's nil if the key is not set in the defaults
+	Default *string
+}
+
+func (c ConfigChange) String() string {
+	switch {
+	case c.Installed == nil:
+		return fmt.Sprintf("%s: missing, the default is %q", c.Key, *c.Default)
+	case c.Default == nil:
+		return fmt.Sprintf("%s: set to %q, it's not set by default", c.Key, *c.Installed)
+	default:
+		return fmt.Sprintf("%s: set to %q, the default is %q", c.Key, *c.Installed, *c.Default)
+	}
+}
+
+// AddonDiff describes how an installed default addon differs from
+// the defaults for a Kubernetes version
+type AddonDiff struct {
+	Name             string
+	InstalledVersion string
+	DefaultVersion   string
+	// Changes lists the configuration drift, it's empty for addons
+	// whose configuration isn't bundled with eksctl
+	Changes []ConfigChange
+	// Deprecated maps the deprecated keys set in the cluster to a description of their replacement
+	Deprecated map[string]string
+}
+
+// VersionDiffers reports whether the installed version differs from the default
+func (d *AddonDiff) VersionDiffers() bool {
+	return d.InstalledVersion != d.DefaultVersion
+}
+
+// HasDrift reports whether the installed addon differs from the defaults in any way
+func (d *AddonDiff) HasDrift() bool {
+	return d.VersionDiffers() || len(d.Changes) > 0 || len(d.Deprecated) > 0
+}
+
+// Diff compares the installed add-on of the given name, AWSNode, CoreDNS or KubeProxy, with
+// its defaults for kubernetesVersion, which can be a minor version, e.g. 1.15
+func Diff(clientSet kubernetes.Interface, name, kubernetesVersion string) (*AddonDiff, error) {
+	switch name {
+	case AWSNode:
+		return DiffAWSNode(clientSet)
+	case CoreDNS:
+		// the bundled manifests are selected by minor version
+		if strings.Count(kubernetesVersion, ".") == 1 {
+			kubernetesVersion += ".0"
+		}
+		return DiffCoreDNS(clientSet, kubernetesVersion)
+	case KubeProxy:
+		return DiffKubeProxy(clientSet, kubernetesVersion)
+	default:
+		return nil, fmt.Errorf("unknown add-on %q", name)
+	}
+}
+
+// DiffAWSNode compares the installed `aws-node` add-on with the bundled manifest;
+// the configuration consists of the environment variables of the container
+func DiffAWSNode(clientSet kubernetes.Interface) (*AddonDiff, error) {
+	installed, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %q", AWSNode)
+	}
+
+	list, err := LoadAsset(AWSNode, "yaml")
+	if err != nil {
+		return nil, err
+	}
+	var defaults *appsv1.DaemonSet
+	for _, item := range list.Items {
+		if daemonSet, ok := item.Object.(*appsv1.DaemonSet); ok {
+			defaults = daemonSet
+		}
+	}
+	if defaults == nil {
+		return nil, fmt.Errorf("no DaemonSet found in the manifest of %q", AWSNode)
+	}
+
+	diff, err := diffImages(AWSNode, &installed.Spec.Template, &defaults.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	diff.Changes = diffConfig(
+		envConfig(installed.Spec.Template.Spec.Containers[0].Env),
+		envConfig(defaults.Spec.Template.Spec.Containers[0].Env),
+	)
+	return diff, nil
+}
+
+// DiffCoreDNS compares the installed `coredns` add-on with the bundled manifest for
+// controlPlaneVersion; the configuration consists of the directives of the Corefile
+func DiffCoreDNS(clientSet kubernetes.Interface, controlPlaneVersion string) (*AddonDiff, error) {
+	installed, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Get(CoreDNS, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %q", CoreDNS)
+	}
+	var installedCorefile string
+	configMap, err := clientSet.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(CoreDNS, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		installedCorefile = configMap.Data["Corefile"]
+	case !apierrs.IsNotFound(err):
+		return nil, errors.Wrapf(err, "getting %q config map", CoreDNS)
+	}
+
+	list, err := loadAssetCoreDNS(controlPlaneVersion)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		defaults         *appsv1.Deployment
+		defaultsCorefile string
+	)
+	for _, item := range list.Items {
+		switch obj := item.Object.(type) {
+		case *appsv1.Deployment:
+			if obj.Name == CoreDNS {
+				defaults = obj
+			}
+		case *corev1.ConfigMap:
+			if obj.Name == CoreDNS {
+				defaultsCorefile = obj.Data["Corefile"]
+			}
+		}
+	}
+	if defaults == nil {
+		return nil, fmt.Errorf("no Deployment found in the manifest of %q", CoreDNS)
+	}
+
+	diff, err := diffImages(CoreDNS, &installed.Spec.Template, &defaults.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	installedConfig := corefileConfig(installedCorefile)
+	diff.Changes = diffConfig(installedConfig, corefileConfig(defaultsCorefile))
+	diff.Deprecated = findDeprecated(CoreDNS, installedConfig)
+	return diff, nil
+}
+
+// DiffKubeProxy compares the image tag of the installed `kube-proxy` add-on with
+// controlPlaneVersion, which can be a minor version, e.g. 1.15, and checks its
+// command for deprecated flags
+func DiffKubeProxy(clientSet kubernetes.Interface, controlPlaneVersion string) (*AddonDiff, error) {
+	installed, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(KubeProxy, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %q", KubeProxy)
+	}
+	if len(installed.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("%s has no containers", KubeProxy)
+	}
+	container := installed.Spec.Template.Spec.Containers[0]
+
+	tag, err := addons.ImageTag(container.Image)
+	if err != nil {
+		return nil, err
+	}
+	diff := &AddonDiff{
+		Name:             KubeProxy,
+		InstalledVersion: tag,
+		DefaultVersion:   "v" + controlPlaneVersion,
+	}
+	if strings.Count(controlPlaneVersion, ".") == 1 && strings.HasPrefix(tag, diff.DefaultVersion+".") {
+		// any patch version matches a minor version
+		diff.DefaultVersion = tag
+	}
+	diff.Deprecated = findDeprecated(KubeProxy, flagsConfig(append(container.Command, container.Args...)))
+	return diff, nil
+}
+
+func diffImages(name string, installed, defaults *corev1.PodTemplateSpec) (*AddonDiff, error) {
+	if len(installed.Spec.Containers) == 0 || len(defaults.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("%s has no containers", name)
+	}
+	installedTag, err := addons.ImageTag(installed.Spec.Containers[0].Image)
+	if err != nil {
+		return nil, err
+	}
+	defaultTag, err := addons.ImageTag(defaults.Spec.Containers[0].Image)
+	if err != nil {
+		return nil, err
+	}
+	return &AddonDiff{
+		Name:             name,
+		InstalledVersion: installedTag,
+		DefaultVersion:   defaultTag,
+	}, nil
+}
+
+func diffConfig(installed, defaults map[string]string) []ConfigChange {
+	changes := []ConfigChange{}
+	for key := range installed {
+		value := installed[key]
+		if defaultValue, ok := defaults[key]; !ok {
+			changes = append(changes, ConfigChange{Key: key, Installed: &value})
+		} else if value != defaultValue {
+			changes = append(changes, ConfigChange{Key: key, Installed: &value, Default: &defaultValue})
+		}
+	}
+	for key := range defaults {
+		if _, ok := installed[key]; !ok {
+			defaultValue := defaults[key]
+			changes = append(changes, ConfigChange{Key: key, Default: &defaultValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+func findDeprecated(name string, config map[string]string) map[string]string {
+	deprecated := map[string]string{}
+	for key, description := range deprecatedKeys[name] {
+		if _, ok := config[key]; ok {
+			deprecated[key] = description
+		}
+	}
+	return deprecated
+}
+
+// envConfig maps environment variables to their values, variables
+// set from other sources are represented by the source
+func envConfig(env []corev1.EnvVar) map[string]string {
+	config := map[string]string{}
+	for _, e := range env {
+		value := e.Value
+		if e.ValueFrom != nil {
+			switch {
+			case e.ValueFrom.FieldRef != nil:
+				value = "fieldRef:" + e.ValueFrom.FieldRef.FieldPath
+			default:
+				value = "valueFrom"
+			}
+		}
+		config[e.Name] = value
+	}
+	return config
+}
+
+// corefileConfig maps the directives of a Corefile to their arguments; nested
+// directives are prefixed with the directives of their enclosing blocks, e.g.
+// .:53/kubernetes/pods
+func corefileConfig(corefile string) map[string]string {
+	config := map[string]string{}
+	var blocks []string
+	for _, line := range strings.Split(corefile, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "}" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+		opensBlock := strings.HasSuffix(line, "{")
+		fields := strings.Fields(strings.TrimSuffix(line, "{"))
+		if len(fields) == 0 {
+			continue
+		}
+		key := strings.Join(append(blocks, fields[0]), "/")
+		config[key] = strings.Join(fields[1:], " ")
+		if opensBlock {
+			blocks = append(blocks, fields[0])
+		}
+	}
+	return config
+}
+
+// flagsConfig maps the flags found in a command to their values,
+// shell commands such as `/bin/sh -c "kube-proxy ..."` are split as well
+func flagsConfig(command []string) map[string]string {
+	config := map[string]string{}
+	for _, arg := range command {
+		for _, field := range strings.Fields(arg) {
+			if !strings.HasPrefix(field, "--") {
+				continue
+			}
+			parts := strings.SplitN(field, "=", 2)
+			value := ""
+			if len(parts) == 2 {
+				value = parts[1]
+			}
+			config[parts[0]] = value
+		}
+	}
+	return config
+}
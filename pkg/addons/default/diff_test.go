@@ -0,0 +1,84 @@
+package defaultaddons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/addons/default"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("default addons - diff", func() {
+	var (
+		clientSet *fake.Clientset
+	)
+
+	strPtr := func(s string) *string { return &s }
+
+	BeforeEach(func() {
+		clientSet, _ = testutils.NewFakeClientSetWithSamples("testdata/sample-1.13.json")
+	})
+
+	It("reports the drift of aws-node from the bundled manifest", func() {
+		diff, err := DiffAWSNode(clientSet)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(diff.InstalledVersion).To(Equal("v1.4.1"))
+		Expect(diff.DefaultVersion).To(Equal("v1.6.0"))
+		Expect(diff.Changes).To(Equal([]ConfigChange{
+			{Key: "AWS_VPC_ENI_MTU", Default: strPtr("9001")},
+			{Key: "AWS_VPC_K8S_CNI_VETHPREFIX", Default: strPtr("eni")},
+			{Key: "WATCH_NAMESPACE", Installed: strPtr("fieldRef:metadata.namespace")},
+		}))
+		Expect(diff.Deprecated).To(BeEmpty())
+	})
+
+	It("reports deprecated coredns plugins before an upgrade", func() {
+		diff, err := DiffCoreDNS(clientSet, "1.14.0")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(diff.InstalledVersion).To(Equal("v1.2.6"))
+		Expect(diff.DefaultVersion).To(Equal("v1.6.6"))
+		Expect(diff.Changes).To(Equal([]ConfigChange{
+			{Key: ".:53/forward", Default: strPtr(". /etc/resolv.conf")},
+			{Key: ".:53/proxy", Installed: strPtr(". /etc/resolv.conf")},
+		}))
+		Expect(diff.Deprecated).To(HaveKey(".:53/proxy"))
+		Expect(diff.HasDrift()).To(BeTrue())
+	})
+
+	It("matches coredns with the defaults of the installed version", func() {
+		diff, err := DiffCoreDNS(clientSet, "1.13.0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Changes).To(BeEmpty())
+		Expect(diff.VersionDiffers()).To(BeFalse())
+	})
+
+	It("matches kube-proxy with any patch version of a minor version", func() {
+		diff, err := DiffKubeProxy(clientSet, "1.13")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.HasDrift()).To(BeFalse())
+
+		diff, err = DiffKubeProxy(clientSet, "1.14.9")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.InstalledVersion).To(Equal("v1.13.7"))
+		Expect(diff.DefaultVersion).To(Equal("v1.14.9"))
+	})
+
+	It("reports deprecated kube-proxy flags", func() {
+		kubeProxy, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(KubeProxy, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		kubeProxy.Spec.Template.Spec.Containers[0].Command = []string{
+			"/bin/sh", "-c", `kube-proxy --v=2 --resource-container="" --config=/var/lib/kube-proxy-config/config`,
+		}
+		_, err = clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Update(kubeProxy)
+		Expect(err).ToNot(HaveOccurred())
+
+		diff, err := DiffKubeProxy(clientSet, "1.13")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Deprecated).To(HaveKey("--resource-container"))
+	})
+})
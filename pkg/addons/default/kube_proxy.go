@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -19,8 +19,9 @@ const (
 	KubeProxy = "kube-proxy"
 )
 
-// UpdateKubeProxyImageTag updates image tag for kube-system:damoneset/kube-proxy based to match controlPlaneVersion
-func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion string, plan bool) (bool, error) {
+// UpdateKubeProxyImageTag updates image tag for kube-system:damoneset/kube-proxy based to match controlPlaneVersion,
+// or to imageTag when it's set
+func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion, imageTag string, plan bool) (bool, error) {
 	printer := printers.NewJSONPrinter()
 
 	d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(KubeProxy, metav1.GetOptions{})
@@ -47,6 +48,9 @@ func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion
 	}
 
 	desiredTag := "v" + controlPlaneVersion
+	if imageTag != "" {
+		desiredTag = imageTag
+	}
 
 	if imageParts[1] == desiredTag {
 		logger.Debug("imageParts = %v, desiredTag = %s", imageParts, desiredTag)
@@ -54,14 +58,19 @@ func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion
 		return false, nil
 	}
 
+	installed := d.DeepCopy()
+	imageParts[1] = desiredTag
+	*image = strings.Join(imageParts, ":")
+	setPreviousImage(&d.ObjectMeta, installed.ObjectMeta, installed.Spec.Template.Spec.Containers[0].Image, *image)
+
 	if plan {
+		if err := logObjectDiff(KubeProxy, installed, d); err != nil {
+			return false, err
+		}
 		logger.Critical("(plan) %q is not up-to-date", KubeProxy)
 		return true, nil
 	}
 
-	imageParts[1] = desiredTag
-	*image = strings.Join(imageParts, ":")
-
 	if err := printer.LogObj(logger.Debug, KubeProxy+" [updated] = \\\n%s\n", d); err != nil {
 		return false, err
 	}
@@ -69,6 +78,6 @@ func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion
 		return false, err
 	}
 
-	logger.Info("%q is now up-to-date", KubeProxy)
+	logger.Emit(logger.EventAddonInstalled, map[string]string{"addon": KubeProxy}, "%q is now up-to-date", KubeProxy)
 	return false, nil
 }
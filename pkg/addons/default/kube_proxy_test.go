@@ -38,15 +38,39 @@ var _ = Describe("default addons - kube-proxy", func() {
 		})
 
 		It("can update based on control plane version", func() {
-			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.0", false)
+			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.0", "", false)
 			Expect(err).ToNot(HaveOccurred())
 			check("v1.13.0")
 		})
 
 		It("can dry-run update based on control plane version", func() {
-			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.1", true)
+			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.1", "", true)
 			Expect(err).ToNot(HaveOccurred())
 			check("v1.12.6")
 		})
+
+		It("can pin the image tag and roll back", func() {
+			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.0", "v1.13.7", false)
+			Expect(err).ToNot(HaveOccurred())
+			check("v1.13.7")
+
+			kubeProxy, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(KubeProxy, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeProxy.Annotations).To(HaveKeyWithValue(PreviousImageAnnotation,
+				"602401143452.dkr.ecr.eu-west-1.amazonaws.com/eks/kube-proxy:v1.12.6"))
+
+			_, err = RollbackAddon(clientSet, KubeProxy, false)
+			Expect(err).ToNot(HaveOccurred())
+			check("v1.12.6")
+
+			_, err = RollbackAddon(clientSet, KubeProxy, false)
+			Expect(err).ToNot(HaveOccurred())
+			check("v1.13.7")
+		})
+
+		It("can't roll back an addon that wasn't updated", func() {
+			_, err := RollbackAddon(clientSet, KubeProxy, false)
+			Expect(err).To(MatchError(`"kube-proxy" has no previous image, it wasn't updated by eksctl`))
+		})
 	})
 })
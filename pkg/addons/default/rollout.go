@@ -0,0 +1,254 @@
+package defaultaddons
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// PreviousImageAnnotation is set on the DaemonSet or Deployment of a default addon when its image
+// is updated, so that the update can be rolled back
+const PreviousImageAnnotation = "eksctl.io/previous-image"
+
+// setPreviousImage records the image an addon is updated from, the annotation of the installed
+// addon is kept when its image doesn't change
+func setPreviousImage(updated *metav1.ObjectMeta, installed metav1.ObjectMeta, installedImage, updatedImage string) {
+	previous := installed.Annotations[PreviousImageAnnotation]
+	if installedImage != updatedImage {
+		previous = installedImage
+	}
+	if previous == "" {
+		return
+	}
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[PreviousImageAnnotation] = previous
+}
+
+// RollbackAddon sets the image of a default addon back to the one it had before its last update
+// by eksctl, only the image is restored; it returns true in plan mode if a rollback is possible
+func RollbackAddon(clientSet kubernetes.Interface, name string, plan bool) (bool, error) {
+	var (
+		object    runtime.Object
+		meta      *metav1.ObjectMeta
+		container *string
+		update    func() error
+	)
+	switch name {
+	case AWSNode, KubeProxy:
+		ds, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, addonGetError(name, err)
+		}
+		object, meta, container = ds, &ds.ObjectMeta, &ds.Spec.Template.Spec.Containers[0].Image
+		update = func() error {
+			_, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Update(ds)
+			return err
+		}
+	case CoreDNS:
+		deployment, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, addonGetError(name, err)
+		}
+		object, meta, container = deployment, &deployment.ObjectMeta, &deployment.Spec.Template.Spec.Containers[0].Image
+		update = func() error {
+			_, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Update(deployment)
+			return err
+		}
+	default:
+		return false, fmt.Errorf("cannot roll back %q, only %s, %s and %s can be rolled back", name, AWSNode, KubeProxy, CoreDNS)
+	}
+
+	previous := meta.Annotations[PreviousImageAnnotation]
+	if previous == "" {
+		return false, fmt.Errorf("%q has no previous image, it wasn't updated by eksctl", name)
+	}
+	installed := object.DeepCopyObject()
+
+	// the image being rolled back from becomes the previous image, so that the rollback can itself be rolled back
+	meta.Annotations[PreviousImageAnnotation] = *container
+	*container = previous
+
+	if err := logObjectDiff(name, installed, object); err != nil {
+		return false, err
+	}
+	if plan {
+		logger.Critical("(plan) %q would be rolled back to %s", name, previous)
+		return true, nil
+	}
+	if err := update(); err != nil {
+		return false, errors.Wrapf(err, "rolling back %q", name)
+	}
+	logger.Success("%q was rolled back to %s", name, previous)
+	return false, nil
+}
+
+func addonGetError(name string, err error) error {
+	if apierrs.IsNotFound(err) {
+		return fmt.Errorf("%q was not found", name)
+	}
+	return errors.Wrapf(err, "getting %q", name)
+}
+
+// logObjectDiff logs the changes an update makes to the DaemonSet or Deployment of an addon;
+// the fields of the installed object that the updated object doesn't set, e.g. defaults set by
+// the API server, are left out
+func logObjectDiff(name string, installed, updated runtime.Object) error {
+	diff, err := objectDiff(installed, updated)
+	if err != nil {
+		return errors.Wrapf(err, "comparing %q", name)
+	}
+	if diff == "" {
+		logger.Info("no changes to %q", name)
+		return nil
+	}
+	logger.Info("changes to %q:\n%s", name, diff)
+	return nil
+}
+
+func objectDiff(installed, updated runtime.Object) (string, error) {
+	installedFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(installed)
+	if err != nil {
+		return "", err
+	}
+	updatedFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(updated)
+	if err != nil {
+		return "", err
+	}
+	for _, fields := range []map[string]interface{}{installedFields, updatedFields} {
+		delete(fields, "status")
+		if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+			for _, key := range []string{"resourceVersion", "uid", "generation", "creationTimestamp", "selfLink"} {
+				delete(metadata, key)
+			}
+		}
+	}
+	installedYAML, err := yaml.Marshal(prune(installedFields, updatedFields))
+	if err != nil {
+		return "", err
+	}
+	updatedYAML, err := yaml.Marshal(updatedFields)
+	if err != nil {
+		return "", err
+	}
+	return lineDiff(string(installedYAML), string(updatedYAML)), nil
+}
+
+// prune keeps the fields of the installed value that are also set in the updated value
+func prune(installed, updated interface{}) interface{} {
+	switch updatedValue := updated.(type) {
+	case map[string]interface{}:
+		installedMap, ok := installed.(map[string]interface{})
+		if !ok {
+			return installed
+		}
+		pruned := map[string]interface{}{}
+		for key, value := range installedMap {
+			if updatedField, ok := updatedValue[key]; ok {
+				pruned[key] = prune(value, updatedField)
+			}
+		}
+		return pruned
+	case []interface{}:
+		installedList, ok := installed.([]interface{})
+		if !ok {
+			return installed
+		}
+		pruned := make([]interface{}, len(installedList))
+		for i, value := range installedList {
+			if i < len(updatedValue) {
+				pruned[i] = prune(value, updatedValue[i])
+			} else {
+				pruned[i] = value
+			}
+		}
+		return pruned
+	default:
+		return installed
+	}
+}
+
+// diffContext is the number of unchanged lines shown around the changed lines
+const diffContext = 3
+
+// lineDiff returns the lines that differ between a and b, prefixed with - and +, surrounded
+// by a few unchanged lines; it's empty when a and b are the same
+func lineDiff(a, b string) string {
+	as, bs := strings.Split(strings.TrimSuffix(a, "\n"), "\n"), strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of as[i:] and bs[j:]
+	lcs := make([][]int, len(as)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bs)+1)
+	}
+	for i := len(as) - 1; i >= 0; i-- {
+		for j := len(bs) - 1; j >= 0; j-- {
+			if as[i] == bs[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		prefix string
+		text   string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(as) || j < len(bs) {
+		switch {
+		case i < len(as) && j < len(bs) && as[i] == bs[j]:
+			lines = append(lines, line{" ", as[i]})
+			i++
+			j++
+		case i < len(as) && (j == len(bs) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{"-", as[i]})
+			i++
+		default:
+			lines = append(lines, line{"+", bs[j]})
+			j++
+		}
+	}
+
+	show := make([]bool, len(lines))
+	changed := false
+	for k, l := range lines {
+		if l.prefix == " " {
+			continue
+		}
+		changed = true
+		for c := k - diffContext; c <= k+diffContext; c++ {
+			if c >= 0 && c < len(lines) {
+				show[c] = true
+			}
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	for k, l := range lines {
+		if !show[k] {
+			if k == 0 || show[k-1] {
+				out.WriteString("  ...\n")
+			}
+			continue
+		}
+		fmt.Fprintf(&out, "%s %s\n", l.prefix, l.text)
+	}
+	return out.String()
+}
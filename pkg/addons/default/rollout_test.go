@@ -0,0 +1,43 @@
+package defaultaddons
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("default addons - rollout", func() {
+	It("shows the changed lines with their context", func() {
+		a := "a\nb\nc\nd\ne\nf\ng\nh\ni\n"
+		b := "a\nb\nc\nd\nE\nf\ng\nh\ni\n"
+		Expect(lineDiff(a, b)).To(Equal("  ...\n  b\n  c\n  d\n- e\n+ E\n  f\n  g\n  h\n  ...\n"))
+		Expect(lineDiff(a, a)).To(BeEmpty())
+	})
+
+	It("ignores the fields that the updated object doesn't set", func() {
+		daemonSet := func(image string) *appsv1.DaemonSet {
+			return &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: KubeProxy, Namespace: metav1.NamespaceSystem},
+				Spec: appsv1.DaemonSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: KubeProxy, Image: image}},
+						},
+					},
+				},
+			}
+		}
+		installed := daemonSet("kube-proxy:v1.14.9")
+		installed.ResourceVersion = "42"
+		installed.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/termination-log"
+		installed.Status.NumberReady = 3
+
+		diff, err := objectDiff(installed, daemonSet("kube-proxy:v1.15.11"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff).To(MatchRegexp(`(?m)^-\s+- image: kube-proxy:v1.14.9\n\+\s+- image: kube-proxy:v1.15.11$`))
+		Expect(diff).ToNot(ContainSubstring("terminationMessagePath"))
+		Expect(diff).ToNot(ContainSubstring("resourceVersion"))
+	})
+})
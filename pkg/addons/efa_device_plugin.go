@@ -0,0 +1,115 @@
+package addons
+
+import (
+	"github.com/pkg/errors"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	efaDevicePluginName  = "aws-efa-k8s-device-plugin"
+	efaDevicePluginImage = "%s.dkr.ecr.%s.%s/eks/aws-efa-k8s-device-plugin:v0.3.3"
+
+	kubeletDevicePluginsPath = "/var/lib/kubelet/device-plugins"
+)
+
+// NewEFADevicePlugin creates the DaemonSet of the EFA device plugin, which advertises the
+// vpc.amazonaws.com/efa resource on the nodes of the instance types supporting EFA
+func NewEFADevicePlugin(region string) (*appsv1.DaemonSet, error) {
+	labels := map[string]string{"name": efaDevicePluginName}
+	hostPathType := corev1.HostPathDirectory
+
+	daemonSet := &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      efaDevicePluginName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					PriorityClassName: "system-node-critical",
+					HostNetwork:       true,
+					Affinity: &corev1.Affinity{
+						NodeAffinity: &corev1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+								NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+									MatchExpressions: []corev1.NodeSelectorRequirement{{
+										Key:      "beta.kubernetes.io/instance-type",
+										Operator: corev1.NodeSelectorOpIn,
+										Values:   api.EFAInstanceTypes(),
+									}},
+								}},
+							},
+						},
+					},
+					Tolerations: []corev1.Toleration{
+						{Key: "CriticalAddonsOnly", Operator: corev1.TolerationOpExists},
+						{Key: "aws.amazon.com/efa", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+						{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+					},
+					Containers: []corev1.Container{{
+						Name:  efaDevicePluginName,
+						Image: efaDevicePluginImage,
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: new(bool),
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{"ALL"},
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "device-plugin",
+							MountPath: kubeletDevicePluginsPath,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "device-plugin",
+						VolumeSource: corev1.VolumeSource{
+							HostPath: &corev1.HostPathVolumeSource{
+								Path: kubeletDevicePluginsPath,
+								Type: &hostPathType,
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	if err := UseRegionalImage(&daemonSet.Spec.Template, region); err != nil {
+		return nil, err
+	}
+	return daemonSet, nil
+}
+
+// InstallEFADevicePlugin creates or replaces the EFA device plugin, so that pods can request
+// EFA devices on the nodes of EFA-enabled nodegroups
+func InstallEFADevicePlugin(rawClient kubernetes.RawClientInterface, region string) error {
+	daemonSet, err := NewEFADevicePlugin(region)
+	if err != nil {
+		return err
+	}
+	resource, err := rawClient.NewRawResource(daemonSet)
+	if err != nil {
+		return err
+	}
+	status, err := resource.CreateOrReplace(false)
+	if err != nil {
+		return errors.Wrap(err, "installing EFA device plugin")
+	}
+	logger.Info(status)
+	return nil
+}
@@ -32,8 +32,8 @@ func UseRegionalImage(spec *corev1.PodTemplateSpec, region string) error {
 	return nil
 }
 
-// imageTag extracts the container image's tag.
-func imageTag(image string) (string, error) {
+// ImageTag extracts the container image's tag.
+func ImageTag(image string) (string, error) {
 	parts := strings.Split(image, ":")
 	if len(parts) != 2 {
 		return "", fmt.Errorf("unexpected image format %q", image)
@@ -45,13 +45,22 @@ func imageTag(image string) (string, error) {
 // ImageTagsDiffer returns true if the image tags are not the same
 // while ignoring the image name.
 func ImageTagsDiffer(image1, image2 string) (bool, error) {
-	tag1, err := imageTag(image1)
+	tag1, err := ImageTag(image1)
 	if err != nil {
 		return false, err
 	}
-	tag2, err := imageTag(image2)
+	tag2, err := ImageTag(image2)
 	if err != nil {
 		return false, err
 	}
 	return tag1 != tag2, nil
 }
+
+// SetImageTag replaces the tag of a container image
+func SetImageTag(image, tag string) (string, error) {
+	parts := strings.Split(image, ":")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("unexpected image format %q", image)
+	}
+	return parts[0] + ":" + tag, nil
+}
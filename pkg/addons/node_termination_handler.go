@@ -0,0 +1,132 @@
+package addons
+
+import (
+	"github.com/pkg/errors"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const nodeTerminationHandlerImage = "public.ecr.aws/aws-ec2/aws-node-termination-handler:v1.19.0"
+
+// NewNodeTerminationHandler creates the Kubernetes objects of aws-node-termination-handler: in imds
+// mode a DaemonSet polling the instance metadata service of every node, in queue mode a Deployment
+// consuming the queue, whose service account is an IAM service account created beforehand
+func NewNodeTerminationHandler(mode, region, queueURL string) []runtime.Object {
+	name := api.NodeTerminationHandlerName
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: metav1.NamespaceSystem,
+	}
+	labels := map[string]string{"app.kubernetes.io/name": name}
+
+	objects := []runtime.Object{
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list", "patch", "update"}},
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}},
+				{APIGroups: []string{""}, Resources: []string{"pods/eviction"}, Verbs: []string{"create"}},
+				{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"create"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"daemonsets"}, Verbs: []string{"get"}},
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: name},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: name, Namespace: metav1.NamespaceSystem}},
+		},
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+		{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		{Name: "NAMESPACE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+		{Name: "DELETE_LOCAL_DATA", Value: "true"},
+		{Name: "IGNORE_DAEMON_SETS", Value: "true"},
+	}
+	podSpec := corev1.PodSpec{
+		ServiceAccountName: name,
+		PriorityClassName:  "system-node-critical",
+		NodeSelector:       map[string]string{"kubernetes.io/os": "linux"},
+		Containers: []corev1.Container{{
+			Name:  name,
+			Image: nodeTerminationHandlerImage,
+			SecurityContext: &corev1.SecurityContext{
+				ReadOnlyRootFilesystem:   api.Enabled(),
+				AllowPrivilegeEscalation: api.Disabled(),
+			},
+		}},
+	}
+
+	if mode == api.NodeTerminationHandlerModeQueue {
+		podSpec.Containers[0].Env = append(env,
+			corev1.EnvVar{Name: "ENABLE_SQS_TERMINATION_DRAINING", Value: "true"},
+			corev1.EnvVar{Name: "QUEUE_URL", Value: queueURL},
+			corev1.EnvVar{Name: "AWS_REGION", Value: region},
+		)
+		replicas := int32(1)
+		return append(objects, &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: meta,
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec:       podSpec,
+				},
+			},
+		})
+	}
+
+	podSpec.Containers[0].Env = append(env,
+		corev1.EnvVar{Name: "ENABLE_SPOT_INTERRUPTION_DRAINING", Value: "true"},
+		corev1.EnvVar{Name: "ENABLE_SCHEDULED_EVENT_DRAINING", Value: "true"},
+	)
+	// using the host network, the instance metadata service is reachable whatever the hop limit of the nodes
+	podSpec.HostNetwork = true
+	podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	podSpec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	return append(objects,
+		&corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: meta,
+		},
+		&appsv1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+			ObjectMeta: meta,
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec:       podSpec,
+				},
+			},
+		},
+	)
+}
+
+// InstallNodeTerminationHandler creates or replaces the objects of aws-node-termination-handler
+func InstallNodeTerminationHandler(rawClient kubernetes.RawClientInterface, mode, region, queueURL string) error {
+	for _, object := range NewNodeTerminationHandler(mode, region, queueURL) {
+		resource, err := rawClient.NewRawResource(object)
+		if err != nil {
+			return err
+		}
+		status, err := resource.CreateOrReplace(false)
+		if err != nil {
+			return errors.Wrap(err, "installing aws-node-termination-handler")
+		}
+		logger.Info(status)
+	}
+	return nil
+}
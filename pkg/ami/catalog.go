@@ -0,0 +1,244 @@
+package ami
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils"
+)
+
+const (
+	// CatalogPathEnvVar overrides the path of the catalog, setting it to an empty value disables the catalog
+	CatalogPathEnvVar = "EKSCTL_CATALOG_PATH"
+	// OfflineEnvVar makes AMIs resolve from the catalog only, when set to true
+	OfflineEnvVar = "EKSCTL_OFFLINE"
+
+	// DefaultCatalogTTL is how long an AMI of the catalog is used before being resolved again
+	DefaultCatalogTTL = 24 * time.Hour
+)
+
+// DefaultCatalogPath returns the path of the catalog, ~/.eksctl/cache/catalog.json unless
+// overridden by EKSCTL_CATALOG_PATH; it's empty when the catalog is disabled
+func DefaultCatalogPath() string {
+	if path, ok := os.LookupEnv(CatalogPathEnvVar); ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		logger.Debug("not using the AMI catalog: %v", err)
+		return ""
+	}
+	return filepath.Join(home, ".eksctl", "cache", "catalog.json")
+}
+
+// IsOffline returns true when AMIs must only be resolved from the catalog
+func IsOffline() bool {
+	return os.Getenv(OfflineEnvVar) == "true"
+}
+
+// CatalogImage is an AMI of the catalog
+type CatalogImage struct {
+	ImageID    string    `json:"imageID"`
+	ResolvedAt time.Time `json:"resolvedAt"`
+}
+
+// Catalog is a local cache of resolved AMIs, stored as a JSON file, so that repeated commands don't
+// query SSM and EC2 for the same AMIs and can run offline
+type Catalog struct {
+	Images map[string]CatalogImage `json:"images"`
+
+	path  string
+	mutex sync.Mutex
+}
+
+// LoadCatalog reads the catalog at path, an empty catalog is returned when the file doesn't exist
+func LoadCatalog(path string) (*Catalog, error) {
+	catalog := &Catalog{
+		Images: map[string]CatalogImage{},
+		path:   path,
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return catalog, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading catalog %q", path)
+	}
+	if err := json.Unmarshal(data, catalog); err != nil {
+		return nil, errors.Wrapf(err, "parsing catalog %q", path)
+	}
+	if catalog.Images == nil {
+		catalog.Images = map[string]CatalogImage{}
+	}
+	return catalog, nil
+}
+
+// Path returns the path of the catalog file
+func (c *Catalog) Path() string {
+	return c.path
+}
+
+// Lookup returns the AMI of the catalog resolved by the given kind of resolver for the given region,
+// version, instance type and image family
+func (c *Catalog) Lookup(resolverKind, region, version, instanceType, imageFamily string) (CatalogImage, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	image, ok := c.Images[catalogKey(resolverKind, region, version, instanceType, imageFamily)]
+	return image, ok
+}
+
+// Store adds an AMI to the catalog and saves it
+func (c *Catalog) Store(resolverKind, region, version, instanceType, imageFamily, imageID string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Images[catalogKey(resolverKind, region, version, instanceType, imageFamily)] = CatalogImage{
+		ImageID:    imageID,
+		ResolvedAt: time.Now().UTC(),
+	}
+	return c.save()
+}
+
+// Keys returns the keys of the AMIs of the catalog, sorted
+func (c *Catalog) Keys() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	keys := make([]string, 0, len(c.Images))
+	for key := range c.Images {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *Catalog) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return errors.Wrapf(err, "creating directory of catalog %q", c.path)
+	}
+	// write to a temporary file first, so that concurrent commands never read a partial catalog
+	tmp := c.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Wrapf(err, "writing catalog %q", c.path)
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// defaultResolverKind is the kind of resolver of the keys of the AMIs resolved by the default
+// resolver, whose kind is empty
+const defaultResolverKind = "default"
+
+// catalogKey identifies the AMIs resolved by the same kind of resolver for the same arguments, as
+// e.g. SSM and EC2 don't resolve to the same AMIs; instance types are reduced to their image class
+// and architecture as they resolve to the same AMI
+func catalogKey(resolverKind, region, version, instanceType, imageFamily string) string {
+	if resolverKind == "" {
+		resolverKind = defaultResolverKind
+	}
+	class := "general"
+	if utils.IsGPUInstanceType(instanceType) {
+		class = "gpu"
+	}
+	return strings.Join([]string{resolverKind, region, version, imageFamily, class, instanceEC2ArchName(instanceType)}, "/")
+}
+
+// ParseCatalogKey returns the kind of resolver, the region, version, a representative instance type
+// and the image family of a key
+func ParseCatalogKey(key string) (resolverKind, region, version, instanceType, imageFamily string, err error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 6 {
+		return "", "", "", "", "", fmt.Errorf("invalid catalog key %q", key)
+	}
+	instanceType, ok := catalogInstanceTypes[parts[4]+"/"+parts[5]]
+	if !ok {
+		return "", "", "", "", "", fmt.Errorf("invalid catalog key %q", key)
+	}
+	resolverKind = parts[0]
+	if resolverKind == defaultResolverKind {
+		resolverKind = ""
+	}
+	return resolverKind, parts[1], parts[2], instanceType, parts[3], nil
+}
+
+// catalogInstanceTypes are instance types of each image class and architecture of the keys
+var catalogInstanceTypes = map[string]string{
+	"general/x86_64": "m5.large",
+	"general/arm64":  "a1.large",
+	"gpu/x86_64":     "p3.2xlarge",
+}
+
+// CatalogInstanceTypes returns an instance type of each image class and architecture that
+// has its own AMI in the image family
+func CatalogInstanceTypes(imageFamily string) []string {
+	switch imageFamily {
+	case api.NodeImageFamilyAmazonLinux2:
+		return []string{"m5.large", "a1.large", "p3.2xlarge"}
+	case api.NodeImageFamilyBottlerocket:
+		return []string{"m5.large", "a1.large"}
+	default:
+		return []string{"m5.large"}
+	}
+}
+
+// CachingResolver is a Resolver that stores the AMIs resolved by its delegate in a Catalog; AMIs of
+// the catalog are used until they're older than the TTL, and whatever their age when offline or
+// when the delegate fails
+type CachingResolver struct {
+	delegate     Resolver
+	resolverKind string
+	catalog      *Catalog
+	ttl          time.Duration
+	offline      bool
+}
+
+// NewCachingResolver creates a CachingResolver, resolverKind is the kind of the delegate, i.e. the
+// value of nodeGroups[].ami it was chosen for, e.g. auto-ssm, and empty for the default resolver
+func NewCachingResolver(delegate Resolver, resolverKind string, catalog *Catalog, ttl time.Duration, offline bool) *CachingResolver {
+	return &CachingResolver{
+		delegate:     delegate,
+		resolverKind: resolverKind,
+		catalog:      catalog,
+		ttl:          ttl,
+		offline:      offline,
+	}
+}
+
+// Resolve returns the AMI of the catalog, or resolves it and stores it in the catalog
+func (r *CachingResolver) Resolve(region, version, instanceType, imageFamily string) (string, error) {
+	cached, found := r.catalog.Lookup(r.resolverKind, region, version, instanceType, imageFamily)
+	if found && (r.offline || time.Since(cached.ResolvedAt) < r.ttl) {
+		logger.Debug("using AMI %s of catalog %q resolved at %s", cached.ImageID, r.catalog.Path(), cached.ResolvedAt)
+		return cached.ImageID, nil
+	}
+	if r.offline {
+		return "", fmt.Errorf("no AMI in catalog %q for region %s, version %s, instance type %s and image family %s; "+
+			"run eksctl utils refresh-catalog while online", r.catalog.Path(), region, version, instanceType, imageFamily)
+	}
+
+	id, err := r.delegate.Resolve(region, version, instanceType, imageFamily)
+	if err != nil || id == "" {
+		if found {
+			logger.Warning("unable to resolve AMI, using AMI %s of catalog %q resolved at %s", cached.ImageID, r.catalog.Path(), cached.ResolvedAt)
+			return cached.ImageID, nil
+		}
+		return id, err
+	}
+
+	if err := r.catalog.Store(r.resolverKind, region, version, instanceType, imageFamily, id); err != nil {
+		logger.Warning("unable to store AMI in catalog: %v", err)
+	}
+	return id, nil
+}
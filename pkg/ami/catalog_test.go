@@ -0,0 +1,130 @@
+package ami_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/ami"
+)
+
+type fakeResolver struct {
+	ids   []string
+	err   error
+	calls int
+}
+
+func (r *fakeResolver) Resolve(_, _, _, _ string) (string, error) {
+	r.calls++
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.ids[r.calls-1], nil
+}
+
+var _ = Describe("AMI catalog", func() {
+	var (
+		dir      string
+		path     string
+		delegate *fakeResolver
+	)
+
+	newKindResolver := func(resolverKind string, ttl time.Duration, offline bool) Resolver {
+		catalog, err := LoadCatalog(path)
+		Expect(err).NotTo(HaveOccurred())
+		return NewCachingResolver(delegate, resolverKind, catalog, ttl, offline)
+	}
+	newResolver := func(ttl time.Duration, offline bool) Resolver {
+		return newKindResolver("", ttl, offline)
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "catalog")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "cache", "catalog.json")
+		delegate = &fakeResolver{ids: []string{"ami-1", "ami-2"}}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("stores resolved AMIs and uses them until they expire", func() {
+		id, err := newResolver(time.Hour, false).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ami-1"))
+
+		By("sharing the AMI between instance types of the same class")
+		id, err = newResolver(time.Hour, false).Resolve("us-west-2", "1.15", "t3.medium", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ami-1"))
+		Expect(delegate.calls).To(Equal(1))
+
+		By("resolving the AMI again once it's expired")
+		id, err = newResolver(0, false).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ami-2"))
+		Expect(delegate.calls).To(Equal(2))
+	})
+
+	It("uses expired AMIs when the resolution fails or when offline", func() {
+		_, err := newResolver(time.Hour, false).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+
+		delegate.err = errors.New("no network")
+		id, err := newResolver(0, false).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ami-1"))
+
+		id, err = newResolver(0, true).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ami-1"))
+
+		_, err = newResolver(0, true).Resolve("us-west-2", "1.15", "p3.2xlarge", "AmazonLinux2")
+		Expect(err).To(MatchError(ContainSubstring("no AMI in catalog")))
+		Expect(delegate.calls).To(Equal(2))
+	})
+
+	It("doesn't share AMIs between kinds of resolvers", func() {
+		id, err := newResolver(time.Hour, false).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ami-1"))
+
+		id, err = newKindResolver("auto", time.Hour, false).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ami-2"))
+
+		_, err = newKindResolver("auto-ssm", time.Hour, true).Resolve("us-west-2", "1.15", "m5.large", "AmazonLinux2")
+		Expect(err).To(MatchError(ContainSubstring("no AMI in catalog")))
+		Expect(delegate.calls).To(Equal(2))
+	})
+
+	It("parses the keys of the catalog", func() {
+		_, err := newResolver(time.Hour, false).Resolve("us-west-2", "1.15", "a1.medium", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = newKindResolver("auto-ssm", time.Hour, false).Resolve("us-west-2", "1.15", "p3.8xlarge", "AmazonLinux2")
+		Expect(err).NotTo(HaveOccurred())
+
+		catalog, err := LoadCatalog(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(catalog.Keys()).To(Equal([]string{
+			"auto-ssm/us-west-2/1.15/AmazonLinux2/gpu/x86_64",
+			"default/us-west-2/1.15/AmazonLinux2/general/arm64",
+		}))
+
+		resolverKind, region, version, instanceType, imageFamily, err := ParseCatalogKey(catalog.Keys()[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect([]string{resolverKind, region, version, instanceType, imageFamily}).To(Equal([]string{"auto-ssm", "us-west-2", "1.15", "p3.2xlarge", "AmazonLinux2"}))
+
+		resolverKind, _, _, instanceType, _, err = ParseCatalogKey(catalog.Keys()[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolverKind).To(BeEmpty())
+		Expect(instanceType).To(Equal("a1.large"))
+	})
+})
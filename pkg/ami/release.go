@@ -0,0 +1,37 @@
+package ami
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// releaseVersionRegex matches the release versions of managed nodegroups, e.g. 1.15.10-20200228
+	releaseVersionRegex = regexp.MustCompile(`^(\d+\.\d+)(?:\.\d+)?-(\d+)$`)
+	// releaseImageNameRegex matches the names of EKS-optimized AMIs, e.g. amazon-eks-gpu-node-1.15-v20200228
+	releaseImageNameRegex = regexp.MustCompile(`^(amazon-eks(?:-gpu)?-node)-\d+\.\d+-v\d+$`)
+)
+
+// ParseReleaseVersion returns the Kubernetes minor version and the AMI version of a release
+// version, e.g. 1.15 and 20200228 for 1.15.10-20200228
+func ParseReleaseVersion(releaseVersion string) (kubernetesVersion, amiVersion string, err error) {
+	match := releaseVersionRegex.FindStringSubmatch(releaseVersion)
+	if match == nil {
+		return "", "", fmt.Errorf("invalid release version %q, expected a version such as 1.15.10-20200228", releaseVersion)
+	}
+	return match[1], match[2], nil
+}
+
+// ReleaseImageName returns the name of the EKS-optimized AMI of releaseVersion that has
+// the same class as the image named imageName, e.g. GPU images stay GPU images
+func ReleaseImageName(imageName, releaseVersion string) (string, error) {
+	kubernetesVersion, amiVersion, err := ParseReleaseVersion(releaseVersion)
+	if err != nil {
+		return "", err
+	}
+	match := releaseImageNameRegex.FindStringSubmatch(imageName)
+	if match == nil {
+		return "", fmt.Errorf("image %q is not an EKS-optimized Amazon Linux 2 AMI, it can't be upgraded to a release version", imageName)
+	}
+	return fmt.Sprintf("%s-%s-v%s", match[1], kubernetesVersion, amiVersion), nil
+}
@@ -0,0 +1,26 @@
+package ami_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/ami"
+)
+
+var _ = Describe("Release image names", func() {
+	DescribeTable("ReleaseImageName", func(imageName, releaseVersion, expected string) {
+		name, err := ReleaseImageName(imageName, releaseVersion)
+		if expected == "" {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal(expected))
+	},
+		Entry("general image", "amazon-eks-node-1.14-v20190927", "1.15.10-20200228", "amazon-eks-node-1.15-v20200228"),
+		Entry("GPU image", "amazon-eks-gpu-node-1.15-v20200228", "1.15-20200312", "amazon-eks-gpu-node-1.15-v20200312"),
+		Entry("invalid release version", "amazon-eks-node-1.15-v20200228", "v20200312", ""),
+		Entry("image that isn't EKS-optimized", "ubuntu-eks/k8s_1.15/images/hvm-ssd/ubuntu-bionic-18.04-amd64-server-20200318", "1.15.10-20200228", ""),
+	)
+})
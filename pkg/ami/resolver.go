@@ -3,7 +3,8 @@ package ami
 import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
-	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // MultiResolver is a Resolver that delegates to one or more Resolvers.
@@ -33,6 +34,31 @@ func (r *MultiResolver) Resolve(region, version, instanceType, imageFamily strin
 	return "", NewErrFailedResolution(region, version, instanceType, imageFamily)
 }
 
+// FallbackResolver is a Resolver that resolves AMIs with its primary Resolver, and with its
+// fallback Resolver when the primary one fails, e.g. the static resolver when AWS can't be queried
+type FallbackResolver struct {
+	primary, fallback Resolver
+}
+
+// Resolve will resolve an AMI with the primary resolver, falling back
+// to the fallback resolver when it returns an error or no AMI
+func (r *FallbackResolver) Resolve(region, version, instanceType, imageFamily string) (string, error) {
+	ami, err := r.primary.Resolve(region, version, instanceType, imageFamily)
+	if err == nil && ami != "" {
+		return ami, nil
+	}
+	if err == nil {
+		err = NewErrFailedResolution(region, version, instanceType, imageFamily)
+	}
+
+	fallbackAMI, fallbackErr := r.fallback.Resolve(region, version, instanceType, imageFamily)
+	if fallbackErr != nil || fallbackAMI == "" {
+		return "", err
+	}
+	logger.Warning("%v, using AMI %s embedded in eksctl, which may not be the latest", err, fallbackAMI)
+	return fallbackAMI, nil
+}
+
 // Resolver provides an interface to enable implementing multiple
 // ways to determine which AMI to use from the region/instance type/image family.
 type Resolver interface {
@@ -54,6 +80,14 @@ func NewMultiResolver(delegates ...Resolver) *MultiResolver {
 	}
 }
 
+// NewFallbackResolver creates and returns a FallbackResolver
+func NewFallbackResolver(primary, fallback Resolver) *FallbackResolver {
+	return &FallbackResolver{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
 // NewAutoResolver creates a new AutoResolver
 func NewAutoResolver(api ec2iface.EC2API) Resolver {
 	return &AutoResolver{api: api}
@@ -7,11 +7,11 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
@@ -65,7 +65,7 @@ func MakeSSMParameterName(version, instanceType, imageFamily string) (string, er
 	case api.NodeImageFamilyBottlerocket:
 		return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/%s/latest/image_id", version, instanceEC2ArchName(instanceType)), nil
 	case api.NodeImageFamilyUbuntu1804:
-		return "", &UnsupportedQueryError{msg: fmt.Sprintf("SSM Parameter lookups for %s AMIs is not supported yet", imageFamily)}
+		return fmt.Sprintf("/aws/service/canonical/ubuntu/eks/18.04/%s/stable/current/%s/hvm/ebs-gp2/ami-id", version, ubuntuArchName(instanceType)), nil
 	default:
 		return "", fmt.Errorf("unknown image family %s", imageFamily)
 	}
@@ -81,6 +81,15 @@ func instanceEC2ArchName(instanceType string) string {
 	return "x86_64"
 }
 
+// ubuntuArchName returns the name of the architecture as used by the
+// parameters of Ubuntu AMIs.
+func ubuntuArchName(instanceType string) string {
+	if instanceEC2ArchName(instanceType) == "arm64" {
+		return "arm64"
+	}
+	return "amd64"
+}
+
 func imageType(imageFamily, instanceType string) string {
 	family := utils.ToKebabCase(imageFamily)
 	if utils.IsGPUInstanceType(instanceType) {
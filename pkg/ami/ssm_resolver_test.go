@@ -160,14 +160,28 @@ var _ = Describe("AMI Auto Resolution", func() {
 
 			Context("and Ubuntu family", func() {
 				BeforeEach(func() {
+					imageFamily = "Ubuntu1804"
 					_, p = createProviders()
 				})
 
-				It("should return an error", func() {
+				It("should return a valid image for amd64 instance types", func() {
+					addMockGetParameter(p, "/aws/service/canonical/ubuntu/eks/18.04/1.15/stable/current/amd64/hvm/ebs-gp2/ami-id", expectedAmi)
+
+					resolver := NewSSMResolver(p.MockSSM())
+					resolvedAmi, err = resolver.Resolve(region, "1.15", "t3.medium", imageFamily)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+				})
+
+				It("should return a valid image for arm64 instance types", func() {
+					addMockGetParameter(p, "/aws/service/canonical/ubuntu/eks/18.04/1.15/stable/current/arm64/hvm/ebs-gp2/ami-id", expectedAmi)
+
 					resolver := NewSSMResolver(p.MockSSM())
-					resolvedAmi, err = resolver.Resolve(region, version, instanceType, imageFamily)
+					resolvedAmi, err = resolver.Resolve(region, "1.15", "a1.large", imageFamily)
 
-					Expect(err).To(HaveOccurred())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
 				})
 			})
 
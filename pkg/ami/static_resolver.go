@@ -3,7 +3,7 @@ package ami
 import (
 	"fmt"
 
-	"github.com/kris-nova/logger"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
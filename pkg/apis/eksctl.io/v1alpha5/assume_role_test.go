@@ -0,0 +1,34 @@
+package v1alpha5
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Assume role", func() {
+	assumeRole := &ClusterAssumeRole{
+		RoleARNs:    []string{"arn:aws:iam::123456789012:role/a", "arn:aws:iam::123456789012:role/b"},
+		ExternalID:  "config-id",
+		SessionName: "config-session",
+	}
+
+	It("uses the settings of the config file", func() {
+		p := &ProviderConfig{}
+		p.SetAssumeRole(assumeRole)
+		Expect(p.AssumeRoleARNs).To(Equal(assumeRole.RoleARNs))
+		Expect(p.AssumeRoleExternalID).To(Equal("config-id"))
+		Expect(p.AssumeRoleSessionName).To(Equal("config-session"))
+	})
+
+	It("gives precedence to the flags", func() {
+		p := &ProviderConfig{
+			AssumeRoleARNs:        []string{"arn:aws:iam::123456789012:role/flag"},
+			AssumeRoleExternalID:  "flag-id",
+			AssumeRoleSessionName: "",
+		}
+		p.SetAssumeRole(assumeRole)
+		Expect(p.AssumeRoleARNs).To(Equal([]string{"arn:aws:iam::123456789012:role/flag"}))
+		Expect(p.AssumeRoleExternalID).To(Equal("flag-id"))
+		Expect(p.AssumeRoleSessionName).To(Equal("config-session"))
+	})
+})
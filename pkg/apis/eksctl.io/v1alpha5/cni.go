@@ -0,0 +1,104 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SupportedCNIs are the CNI plugins that eksctl can set up
+func SupportedCNIs() []string {
+	return []string{CNIAWSVPC, CNICilium, CNICalico}
+}
+
+// CNI returns the CNI plugin of the pods
+func (c *ClusterConfig) CNI() string {
+	if c.Network == nil || c.Network.CNI == "" {
+		return CNIAWSVPC
+	}
+	return c.Network.CNI
+}
+
+// HasAlternateCNI returns true if the pods use a CNI plugin that eksctl installs instead of the VPC CNI
+func (c *ClusterConfig) HasAlternateCNI() bool {
+	return c.CNI() != CNIAWSVPC
+}
+
+// PodCIDR returns the CIDR of the overlay network of the pods
+func (c *ClusterConfig) PodCIDR() string {
+	if c.Network == nil || c.Network.PodCIDR == "" {
+		return DefaultPodCIDR
+	}
+	return c.Network.PodCIDR
+}
+
+// validateNetwork checks the CNI plugin, and the features of the VPC CNI that other plugins don't have
+func validateNetwork(cfg *ClusterConfig) error {
+	cni := cfg.CNI()
+	supported := false
+	for _, s := range SupportedCNIs() {
+		if cni == s {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%q is not a valid network.cni, valid options: %s", cni, strings.Join(SupportedCNIs(), ", "))
+	}
+	if !cfg.HasAlternateCNI() {
+		if cfg.Network != nil && cfg.Network.ChartVersion != "" {
+			return fmt.Errorf("network.chartVersion can only be set with network.cni %s or %s", CNICilium, CNICalico)
+		}
+	}
+	if cni != CNICalico {
+		if cfg.Network != nil && cfg.Network.PodCIDR != "" {
+			return fmt.Errorf("network.podCIDR can only be set with network.cni %s", CNICalico)
+		}
+		if cni == CNIAWSVPC {
+			return nil
+		}
+	} else if err := validatePodCIDR(cfg); err != nil {
+		return err
+	}
+
+	var unsupported string
+	switch {
+	case IsEnabled(cfg.PodSecurityGroups):
+		unsupported = "podSecurityGroups"
+	case IsEnabled(cfg.PrefixDelegation):
+		unsupported = "prefixDelegation"
+	case cfg.IsLocalCluster():
+		unsupported = "outpost"
+	case len(cfg.FargateProfiles) > 0:
+		// Fargate pods always use the VPC CNI, and can't be reached from an overlay network
+		unsupported = "fargateProfiles"
+	}
+	if unsupported != "" {
+		return fmt.Errorf("%s is not supported with network.cni %s", unsupported, cni)
+	}
+	for i, ng := range cfg.NodeGroups {
+		if IsWindowsImage(ng.AMIFamily) {
+			return fmt.Errorf("nodeGroups[%d]: Windows nodegroups are not supported with network.cni %s", i, cni)
+		}
+		if cni == CNICalico && ng.MaxPodsPerNode.IsAuto() {
+			return fmt.Errorf("nodeGroups[%d].maxPodsPerNode cannot be %s with network.cni %s, which doesn't use the network interfaces of the nodes", i, maxPodsPerNodeAutoValue, cni)
+		}
+	}
+	return nil
+}
+
+// validatePodCIDR checks that the overlay network of the pods doesn't overlap the VPC, as the
+// nodes couldn't route to the pods or to the VPC
+func validatePodCIDR(cfg *ClusterConfig) error {
+	_, podCIDR, err := net.ParseCIDR(cfg.PodCIDR())
+	if err != nil {
+		return fmt.Errorf("network.podCIDR %q is not a valid CIDR: %v", cfg.PodCIDR(), err)
+	}
+	if cfg.VPC == nil || cfg.VPC.CIDR == nil {
+		return nil
+	}
+	vpcCIDR := cfg.VPC.CIDR.IPNet
+	if podCIDR.Contains(vpcCIDR.IP) || vpcCIDR.Contains(podCIDR.IP) {
+		return fmt.Errorf("network.podCIDR %s overlaps vpc.cidr %s", podCIDR, cfg.VPC.CIDR)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PreviousGroupVersions are the versions of the config file that are converted to the current version
+// when they're loaded
+func PreviousGroupVersions() []string {
+	return []string{
+		"eksctl.io/v1alpha4",
+		"eksctl.io/v1alpha3",
+	}
+}
+
+// deprecatedField is a field that has been moved, it's still read from config files of all versions
+type deprecatedField struct {
+	// objects is the path of the objects holding the field, [] expanding lists
+	objects string
+	// from and to are the paths of the field relatively to the objects
+	from, to string
+}
+
+var deprecatedFields = []deprecatedField{
+	{objects: "nodeGroups[]", from: "allowSSH", to: "ssh.allow"},
+	{objects: "nodeGroups[]", from: "sshPublicKeyPath", to: "ssh.publicKeyPath"},
+	{objects: "nodeGroups[]", from: "sshPublicKey", to: "ssh.publicKey"},
+	{objects: "nodeGroups[]", from: "sshPublicKeyName", to: "ssh.publicKeyName"},
+}
+
+// ConvertConfig converts a config file decoded as a map to the current version: the apiVersion
+// of previous versions is replaced and deprecated fields are moved to their replacement. It
+// returns a description of each change, so that they can be logged as warnings
+func ConvertConfig(config map[string]interface{}) ([]string, error) {
+	var changes []string
+
+	if apiVersion, ok := config["apiVersion"].(string); ok {
+		for _, previous := range PreviousGroupVersions() {
+			if apiVersion == previous {
+				config["apiVersion"] = SchemeGroupVersion.String()
+				changes = append(changes, fmt.Sprintf("apiVersion %s is deprecated, converted to %s", previous, SchemeGroupVersion))
+			}
+		}
+	}
+
+	for _, field := range deprecatedFields {
+		for _, object := range findObjects(config, field.objects) {
+			moved, err := moveField(object.value, field.from, field.to)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", object.path, field.from, err)
+			}
+			if moved {
+				changes = append(changes, fmt.Sprintf("%s.%s is deprecated, moved to %s.%s", object.path, field.from, object.path, field.to))
+			}
+		}
+	}
+	return changes, nil
+}
+
+type objectAtPath struct {
+	path  string
+	value map[string]interface{}
+}
+
+// findObjects returns the objects at path in config, e.g. nodeGroups[] returns each nodegroup
+func findObjects(config map[string]interface{}, path string) []objectAtPath {
+	objects := []objectAtPath{{value: config}}
+	for _, key := range strings.Split(path, ".") {
+		isList := strings.HasSuffix(key, "[]")
+		key = strings.TrimSuffix(key, "[]")
+
+		var next []objectAtPath
+		for _, object := range objects {
+			fieldPath := key
+			if object.path != "" {
+				fieldPath = object.path + "." + key
+			}
+			if !isList {
+				if value, ok := object.value[key].(map[string]interface{}); ok {
+					next = append(next, objectAtPath{path: fieldPath, value: value})
+				}
+				continue
+			}
+			list, _ := object.value[key].([]interface{})
+			for i, item := range list {
+				if value, ok := item.(map[string]interface{}); ok {
+					next = append(next, objectAtPath{path: fmt.Sprintf("%s[%d]", fieldPath, i), value: value})
+				}
+			}
+		}
+		objects = next
+	}
+	return objects
+}
+
+// moveField moves the field at from to to in object, creating the objects leading to it;
+// it fails if both fields are set to different values
+func moveField(object map[string]interface{}, from, to string) (bool, error) {
+	value, ok := object[from]
+	if !ok {
+		return false, nil
+	}
+
+	keys := strings.Split(to, ".")
+	parent := object
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := parent[key].(map[string]interface{})
+		if !ok {
+			if parent[key] != nil {
+				return false, fmt.Errorf("cannot move to %s, %s is not an object", to, key)
+			}
+			child = map[string]interface{}{}
+			parent[key] = child
+		}
+		parent = child
+	}
+
+	last := keys[len(keys)-1]
+	if existing, ok := parent[last]; ok && existing != nil && !reflect.DeepEqual(existing, value) {
+		return false, fmt.Errorf("cannot move to %s, which is set to a different value", to)
+	}
+	parent[last] = value
+	delete(object, from)
+	return true, nil
+}
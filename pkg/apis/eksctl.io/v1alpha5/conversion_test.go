@@ -0,0 +1,54 @@
+package v1alpha5
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConvertConfig", func() {
+	It("converts previous versions and moves deprecated fields", func() {
+		config := map[string]interface{}{
+			"apiVersion": "eksctl.io/v1alpha4",
+			"kind":       "ClusterConfig",
+			"nodeGroups": []interface{}{
+				map[string]interface{}{"name": "ng-1", "allowSSH": true, "sshPublicKeyName": "key-1"},
+				map[string]interface{}{"name": "ng-2"},
+			},
+		}
+
+		changes, err := ConvertConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(ConsistOf(
+			"apiVersion eksctl.io/v1alpha4 is deprecated, converted to eksctl.io/v1alpha5",
+			"nodeGroups[0].allowSSH is deprecated, moved to nodeGroups[0].ssh.allow",
+			"nodeGroups[0].sshPublicKeyName is deprecated, moved to nodeGroups[0].ssh.publicKeyName",
+		))
+		Expect(config["apiVersion"]).To(Equal("eksctl.io/v1alpha5"))
+		Expect(config["nodeGroups"]).To(Equal([]interface{}{
+			map[string]interface{}{"name": "ng-1", "ssh": map[string]interface{}{"allow": true, "publicKeyName": "key-1"}},
+			map[string]interface{}{"name": "ng-2"},
+		}))
+	})
+
+	It("leaves configs of the current version as they are", func() {
+		config := map[string]interface{}{
+			"apiVersion": "eksctl.io/v1alpha5",
+			"nodeGroups": []interface{}{
+				map[string]interface{}{"name": "ng-1", "ssh": map[string]interface{}{"allow": true}},
+			},
+		}
+		changes, err := ConvertConfig(config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("rejects deprecated fields conflicting with their replacement", func() {
+		config := map[string]interface{}{
+			"nodeGroups": []interface{}{
+				map[string]interface{}{"name": "ng-1", "allowSSH": true, "ssh": map[string]interface{}{"allow": false}},
+			},
+		}
+		_, err := ConvertConfig(config)
+		Expect(err).To(MatchError("nodeGroups[0].allowSSH: cannot move to ssh.allow, which is set to a different value"))
+	})
+})
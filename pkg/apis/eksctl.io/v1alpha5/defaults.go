@@ -26,6 +26,49 @@ func SetClusterConfigDefaults(cfg *ClusterConfig) {
 			cfg.CloudWatch.ClusterLogging.EnableTypes = SupportedCloudWatchClusterLogTypes()
 		}
 	}
+
+	if cfg.BudgetAlarms != nil {
+		if cfg.BudgetAlarms.NATGatewayGigabytesPerDay == nil {
+			natGatewayGigabytesPerDay := DefaultNATGatewayGigabytesPerDay
+			cfg.BudgetAlarms.NATGatewayGigabytesPerDay = &natGatewayGigabytesPerDay
+		}
+		if cfg.BudgetAlarms.InterAZTransferDollarsPerMonth == nil {
+			interAZTransferDollarsPerMonth := DefaultInterAZTransferDollarsPerMonth
+			cfg.BudgetAlarms.InterAZTransferDollarsPerMonth = &interAZTransferDollarsPerMonth
+		}
+	}
+
+	if cfg.NodeTerminationHandler != nil {
+		if cfg.NodeTerminationHandler.Mode == "" {
+			cfg.NodeTerminationHandler.Mode = NodeTerminationHandlerModeIMDS
+		}
+		if cfg.HasNodeTerminationHandlerQueue() && !hasServiceAccount(cfg.IAM.ServiceAccounts, metav1.NamespaceSystem, NodeTerminationHandlerName) {
+			cfg.IAM.ServiceAccounts = append(cfg.IAM.ServiceAccounts, newNodeTerminationHandlerServiceAccount(cfg.NodeTerminationHandlerQueueName()))
+		}
+	}
+
+	if cfg.IsLocalCluster() {
+		if cfg.Outpost.ControlPlaneInstanceType == "" {
+			cfg.Outpost.ControlPlaneInstanceType = DefaultOutpostControlPlaneInstanceType
+		}
+		for _, ng := range cfg.NodeGroups {
+			if ng.OutpostARN == "" {
+				ng.OutpostARN = cfg.Outpost.ControlPlaneOutpostARN
+			}
+		}
+		if cfg.VPC != nil {
+			setLocalClusterEndpointAccessDefaults(cfg.VPC)
+		}
+	}
+}
+
+func hasServiceAccount(serviceAccounts []*ClusterIAMServiceAccount, namespace, name string) bool {
+	for _, sa := range serviceAccounts {
+		if sa.Namespace == namespace && sa.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // SetNodeGroupDefaults will set defaults for a given nodegroup
@@ -75,6 +118,20 @@ func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
 		ng.Labels = make(map[string]string)
 	}
 	setDefaultNodeLabels(ng.Labels, meta.Name, ng.Name)
+	if zone := ng.Zone(); zone != "" {
+		// kubelet only sets this label itself from Kubernetes 1.17
+		if _, ok := ng.Labels[TopologyZoneLabel]; !ok {
+			ng.Labels[TopologyZoneLabel] = zone
+		}
+	}
+
+	if ng.DisableIMDSv1 == nil {
+		ng.DisableIMDSv1 = defaultDisableIMDSv1(meta)
+	}
+
+	if ng.MemoryConfig != nil && ng.MemoryConfig.Swap != nil && ng.MemoryConfig.Swap.Behavior == "" {
+		ng.MemoryConfig.Swap.Behavior = SwapBehaviorLimited
+	}
 
 	switch ng.AMIFamily {
 	case NodeImageFamilyBottlerocket:
@@ -87,7 +144,8 @@ func SetManagedNodeGroupDefaults(ng *ManagedNodeGroup, meta *ClusterMeta) {
 	if ng.AMIFamily == "" {
 		ng.AMIFamily = NodeImageFamilyAmazonLinux2
 	}
-	if ng.InstanceType == "" {
+	// the instance type can be set in the launch template instead
+	if ng.InstanceType == "" && ng.LaunchTemplate == nil {
 		ng.InstanceType = DefaultNodeType
 	}
 	if ng.ScalingConfig == nil {
@@ -110,6 +168,11 @@ func SetManagedNodeGroupDefaults(ng *ManagedNodeGroup, meta *ClusterMeta) {
 	}
 	setDefaultNodeLabels(ng.Labels, meta.Name, ng.Name)
 
+	// the metadata options of nodegroups with a launch template are set in the launch template
+	if ng.DisableIMDSv1 == nil && ng.LaunchTemplate == nil {
+		ng.DisableIMDSv1 = defaultDisableIMDSv1(meta)
+	}
+
 	if ng.Tags == nil {
 		ng.Tags = make(map[string]string)
 	}
@@ -117,6 +180,16 @@ func SetManagedNodeGroupDefaults(ng *ManagedNodeGroup, meta *ClusterMeta) {
 	ng.Tags[NodeGroupTypeTag] = string(NodeGroupTypeManaged)
 }
 
+// defaultDisableIMDSv1 returns a copy of the cluster-wide setting, which is nil when it's
+// not set so that nodegroups keep the defaults of EC2
+func defaultDisableIMDSv1(meta *ClusterMeta) *bool {
+	if meta.DisableIMDSv1 == nil {
+		return nil
+	}
+	disableIMDSv1 := *meta.DisableIMDSv1
+	return &disableIMDSv1
+}
+
 func setIAMDefaults(iamConfig *NodeGroupIAM) {
 	if iamConfig.WithAddonPolicies.ImageBuilder == nil {
 		iamConfig.WithAddonPolicies.ImageBuilder = Disabled()
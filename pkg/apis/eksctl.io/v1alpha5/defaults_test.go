@@ -102,6 +102,68 @@ var _ = Describe("ClusterConfig validation", func() {
 		})
 	})
 
+	Context("Zone label", func() {
+
+		It("labels the nodes of a nodegroup in a single availability zone", func() {
+			testNodeGroup := NodeGroup{
+				AvailabilityZones: []string{"us-west-2a"},
+			}
+
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+
+			Expect(testNodeGroup.Labels).To(HaveKeyWithValue(TopologyZoneLabel, "us-west-2a"))
+		})
+
+		It("doesn't label the nodes of a nodegroup in several availability zones", func() {
+			testNodeGroup := NodeGroup{
+				AvailabilityZones: []string{"us-west-2a", "us-west-2b"},
+			}
+
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+
+			Expect(testNodeGroup.Labels).NotTo(HaveKey(TopologyZoneLabel))
+		})
+	})
+
+	Context("IMDSv1", func() {
+
+		It("keeps IMDSv1 enabled by default", func() {
+			testNodeGroup := NodeGroup{}
+
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+
+			Expect(IsEnabled(testNodeGroup.DisableIMDSv1)).To(BeFalse())
+		})
+
+		It("uses the cluster-wide default", func() {
+			testNodeGroup := NodeGroup{}
+			testManagedNodeGroup := ManagedNodeGroup{}
+			meta := &ClusterMeta{DisableIMDSv1: Enabled()}
+
+			SetNodeGroupDefaults(&testNodeGroup, meta)
+			SetManagedNodeGroupDefaults(&testManagedNodeGroup, meta)
+
+			Expect(*testNodeGroup.DisableIMDSv1).To(BeTrue())
+			Expect(*testManagedNodeGroup.DisableIMDSv1).To(BeTrue())
+		})
+
+		It("doesn't override the setting of a nodegroup", func() {
+			testNodeGroup := NodeGroup{DisableIMDSv1: Disabled()}
+
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{DisableIMDSv1: Enabled()})
+
+			Expect(*testNodeGroup.DisableIMDSv1).To(BeFalse())
+		})
+
+		It("leaves the setting to the launch template of managed nodegroups", func() {
+			testManagedNodeGroup := ManagedNodeGroup{LaunchTemplate: &LaunchTemplate{ID: "lt-1234"}}
+
+			SetManagedNodeGroupDefaults(&testManagedNodeGroup, &ClusterMeta{DisableIMDSv1: Enabled()})
+
+			Expect(testManagedNodeGroup.DisableIMDSv1).To(BeNil())
+		})
+	})
+
 	Context("Bottlerocket Settings", func() {
 		It("enables SSH with NodeGroup", func() {
 			testNodeGroup := NodeGroup{
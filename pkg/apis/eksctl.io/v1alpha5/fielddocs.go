@@ -0,0 +1,51 @@
+package v1alpha5
+
+type fieldDoc struct {
+	description string
+	since       string
+}
+
+// FieldDoc returns the documentation of a field of a config file type, e.g. ("NodeGroup", "IAM"),
+// and the version of eksctl that introduced the field if it's known; the documentation of the
+// type itself is returned when fieldName is empty
+func FieldDoc(typeName, fieldName string) (description, since string) {
+	key := typeName
+	if fieldName != "" {
+		key += "." + fieldName
+	}
+	doc := fieldDocs[key]
+	return doc.description, doc.since
+}
+
+// fieldEnums returns the valid values of the fields of config file types that only accept a few values,
+// keyed like the field docs; the values of list fields are the valid values of their items
+func fieldEnums() map[string][]string {
+	versions := append([]string{"auto", "default", "latest"}, SupportedVersions()...)
+	return map[string][]string{
+		"ClusterMeta.Region":                                    SupportedRegions(),
+		"ClusterMeta.Version":                                   versions,
+		"ClusterCloudWatchLogging.EnableTypes":                  append([]string{"all", "*"}, SupportedCloudWatchClusterLogTypes()...),
+		"ClusterNodeTerminationHandler.Mode":                    {NodeTerminationHandlerModeIMDS, NodeTerminationHandlerModeQueue},
+		"ClusterNetwork.CNI":                                    SupportedCNIs(),
+		"Flux.GitProvider":                                      SupportedGitProviders(),
+		"NodeGroup.AMIFamily":                                   supportedAMIFamilies(),
+		"NodeGroup.VolumeType":                                  SupportedNodeVolumeTypes(),
+		"NodeGroup.Tenancy":                                     supportedTenancies(),
+		"NodeGroup.ContainerRuntime":                            supportedContainerRuntimes(),
+		"NodeGroup.SuspendProcesses":                            SuspendableProcesses(),
+		"NodeGroupInstancesDistribution.SpotAllocationStrategy": supportedSpotAllocationStrategies(),
+		"NodeGroupPlacement.Strategy":                           supportedPlacementStrategies(),
+		"NodeGroupCapacityReservation.Preference":               supportedCapacityReservationPreferences(),
+		"NodeGroupWarmPool.State":                               supportedWarmPoolStates(),
+		"NodeGroupSwap.Behavior":                                supportedSwapBehaviors(),
+		"NodeGroupVolume.VolumeType":                            SupportedNodeVolumeTypes(),
+		"ManagedNodeGroup.AMIFamily":                            {NodeImageFamilyAmazonLinux2},
+		"ManagedNodeGroup.VolumeType":                           SupportedNodeVolumeTypes(),
+	}
+}
+
+// FieldEnum returns the valid values of a field of a config file type, e.g. ("NodeGroup", "Tenancy"),
+// if it only accepts a few values
+func FieldEnum(typeName, fieldName string) []string {
+	return fieldEnums()[typeName+"."+fieldName]
+}
@@ -0,0 +1,121 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultFluxNamespace is the namespace Flux is installed in by default
+	DefaultFluxNamespace = "flux-system"
+	// DefaultFluxBranch is the branch of the repository Flux syncs from by default
+	DefaultFluxBranch = "main"
+)
+
+// SupportedGitProviders are the providers of the repositories Flux can be bootstrapped with
+func SupportedGitProviders() []string {
+	return []string{GitProviderGitHub, GitProviderGitLab, GitProviderGit}
+}
+
+// fluxFlagsSetByEksctl are the flags of `flux bootstrap` set from the fields of Flux, which
+// can't be set in flags
+var fluxFlagsSetByEksctl = map[string]string{
+	"owner":            "owner",
+	"repository":       "repository",
+	"url":              "repository",
+	"personal":         "personal",
+	"hostname":         "hostname",
+	"branch":           "branch",
+	"path":             "path",
+	"namespace":        "namespace",
+	"private-key-file": "privateKeyFile",
+	"kubeconfig":       "",
+	"context":          "",
+}
+
+// HasFlux returns true if Flux is configured in gitops.flux
+func (c *ClusterConfig) HasFlux() bool {
+	return c.GitOps != nil && c.GitOps.Flux != nil
+}
+
+// RepositoryURL returns the SSH URL of the repository Flux syncs from
+func (f *Flux) RepositoryURL() string {
+	if f.GitProvider == GitProviderGit {
+		return f.Repository
+	}
+	hostname := f.Hostname
+	if hostname == "" {
+		hostname = f.GitProvider + ".com"
+	}
+	return fmt.Sprintf("ssh://git@%s/%s/%s", hostname, f.Owner, f.Repository)
+}
+
+// SetFluxDefaults sets the branch, path and namespace of Flux, the path being specific to the
+// cluster so that a repository can hold the manifests of several clusters
+func SetFluxDefaults(cfg *ClusterConfig) {
+	if !cfg.HasFlux() {
+		return
+	}
+	flux := cfg.GitOps.Flux
+	if flux.Branch == "" {
+		flux.Branch = DefaultFluxBranch
+	}
+	if flux.Path == "" {
+		flux.Path = "clusters/" + cfg.Metadata.Name
+	}
+	if flux.Namespace == "" {
+		flux.Namespace = DefaultFluxNamespace
+	}
+}
+
+// ValidateFlux checks the settings of gitops.flux
+func ValidateFlux(cfg *ClusterConfig) error {
+	if !cfg.HasFlux() {
+		return fmt.Errorf("gitops.flux must be set to bootstrap Flux")
+	}
+	flux := cfg.GitOps.Flux
+
+	supported := false
+	for _, p := range SupportedGitProviders() {
+		if flux.GitProvider == p {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("gitops.flux.gitProvider must be one of %s, got %q", strings.Join(SupportedGitProviders(), ", "), flux.GitProvider)
+	}
+	if flux.Repository == "" {
+		return fmt.Errorf("gitops.flux.repository must be set")
+	}
+
+	if flux.GitProvider == GitProviderGit {
+		if !strings.HasPrefix(flux.Repository, "ssh://") {
+			return fmt.Errorf("gitops.flux.repository must be an SSH URL, e.g. ssh://git@example.com/org/repo, for gitProvider %s", GitProviderGit)
+		}
+		for field, set := range map[string]bool{"owner": flux.Owner != "", "personal": flux.Personal != nil, "hostname": flux.Hostname != ""} {
+			if set {
+				return fmt.Errorf("gitops.flux.%s is not supported for gitProvider %s", field, GitProviderGit)
+			}
+		}
+	} else {
+		if flux.Owner == "" {
+			return fmt.Errorf("gitops.flux.owner must be set for gitProvider %s", flux.GitProvider)
+		}
+		if strings.Contains(flux.Repository, "/") {
+			return fmt.Errorf("gitops.flux.repository must be the name of the repository for gitProvider %s, got %q", flux.GitProvider, flux.Repository)
+		}
+		if flux.PrivateKeyFile != "" {
+			return fmt.Errorf("gitops.flux.privateKeyFile is not supported for gitProvider %s, which creates the deploy key", flux.GitProvider)
+		}
+	}
+
+	for flag := range flux.Flags {
+		if field, ok := fluxFlagsSetByEksctl[strings.TrimLeft(flag, "-")]; ok {
+			if field == "" {
+				return fmt.Errorf("gitops.flux.flags cannot set %q, which eksctl sets", flag)
+			}
+			return fmt.Errorf("gitops.flux.flags cannot set %q, use gitops.flux.%s instead", flag, field)
+		}
+	}
+	return nil
+}
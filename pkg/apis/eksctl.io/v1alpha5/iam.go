@@ -26,6 +26,58 @@ type ClusterIAM struct {
 	WithOIDC *bool `json:"withOIDC,omitempty"`
 	// +optional
 	ServiceAccounts []*ClusterIAMServiceAccount `json:"serviceAccounts,omitempty"`
+	// PermissionsBoundary is the ARN of the permissions boundary of all the roles
+	// created by eksctl, i.e. the service role, the Fargate pod execution role, the
+	// instance roles of nodegroups and the roles of iamserviceaccounts, unless the
+	// role sets its own permissions boundary
+	// +since=0.19.0
+	// +optional
+	PermissionsBoundary *string `json:"permissionsBoundary,omitempty"`
+}
+
+// RolePermissionsBoundary returns the permissions boundary of a role created by eksctl, which is
+// roleBoundary when it's set, and iam.permissionsBoundary otherwise
+func (c *ClusterConfig) RolePermissionsBoundary(roleBoundary string) string {
+	if roleBoundary != "" || c.IAM == nil || c.IAM.PermissionsBoundary == nil {
+		return roleBoundary
+	}
+	return *c.IAM.PermissionsBoundary
+}
+
+// AssumeRole is an IAM role assumed for specific operations instead of the
+// credentials of eksctl, e.g. to read resources of another account
+type AssumeRole struct {
+	// RoleARN of the role to assume, with the credentials of eksctl
+	RoleARN string `json:"roleARN"`
+	// ExternalID required by the trust policy of the role
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+}
+
+// ClusterAssumeRole holds the IAM roles assumed for all AWS API calls instead of
+// the credentials of eksctl
+type ClusterAssumeRole struct {
+	// RoleARNs are the roles to assume, in order, each role being assumed with
+	// the credentials of the previous one
+	RoleARNs []string `json:"roleARNs"`
+	// ExternalID used to assume the last role
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+	// SessionName used to assume the roles, generated if unset
+	// +optional
+	SessionName string `json:"sessionName,omitempty"`
+}
+
+// IAMIdentityMapping maps an IAM role or user to a Kubernetes user and groups
+type IAMIdentityMapping struct {
+	// ARN of the IAM role or user
+	ARN string `json:"arn"`
+	// Username within Kubernetes
+	// +optional
+	Username string `json:"username,omitempty"`
+	// Groups within Kubernetes, the username or at least one group must be set
+	// +optional
+	Groups []string `json:"groups,omitempty"`
 }
 
 // ClusterIAMServiceAccount holds an iamserviceaccount metadata and configuration
@@ -0,0 +1,133 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IsWavelengthZone returns true if the zone is a Wavelength Zone, whose subnets reach the
+// internet through a carrier gateway instead of an internet gateway
+func IsWavelengthZone(zone string) bool {
+	return strings.Contains(zone, "-wlz-")
+}
+
+// LocalZones returns the zones of vpc.localZoneSubnets, sorted
+func (c *ClusterConfig) LocalZones() []string {
+	if c.VPC == nil {
+		return nil
+	}
+	zones := make([]string, 0, len(c.VPC.LocalZoneSubnets))
+	for zone := range c.VPC.LocalZoneSubnets {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// HasWavelengthZoneSubnets returns true if any of the subnets of vpc.localZoneSubnets is in a Wavelength Zone
+func (c *ClusterConfig) HasWavelengthZoneSubnets() bool {
+	for _, zone := range c.LocalZones() {
+		if IsWavelengthZone(zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWavelengthZones returns true if the nodegroup runs in any Wavelength Zone
+func (n *NodeGroup) HasWavelengthZones() bool {
+	for _, zone := range n.LocalZones {
+		if IsWavelengthZone(zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// LocalZoneSubnetIDs returns the IDs of the subnets of vpc.localZoneSubnets in the given zones
+func (c *ClusterConfig) LocalZoneSubnetIDs(zones []string) ([]string, error) {
+	subnetIDs := make([]string, 0, len(zones))
+	for _, zone := range zones {
+		subnet, ok := c.VPC.LocalZoneSubnets[zone]
+		if !ok || subnet.ID == "" {
+			return nil, fmt.Errorf("VPC doesn't have a subnet in local zone %s", zone)
+		}
+		subnetIDs = append(subnetIDs, subnet.ID)
+	}
+	return subnetIDs, nil
+}
+
+// ImportLocalZoneSubnet loads a given subnet of a Local Zone or Wavelength Zone into cluster config
+func (c *ClusterConfig) ImportLocalZoneSubnet(zone, subnetID, cidr string) error {
+	if c.VPC.LocalZoneSubnets == nil {
+		c.VPC.LocalZoneSubnets = make(map[string]Network)
+	}
+	return doImportSubnet(c.VPC.LocalZoneSubnets, zone, subnetID, cidr)
+}
+
+// validateLocalZones checks that subnets of Local Zones and Wavelength Zones are only used by
+// self-managed nodegroups, as the control plane and managed nodegroups don't support them
+func validateLocalZones(cfg *ClusterConfig) error {
+	localZones := cfg.LocalZones()
+	isLocalZone := func(zone string) bool {
+		for _, localZone := range localZones {
+			if zone == localZone {
+				return true
+			}
+		}
+		return false
+	}
+	isLocalZoneSubnet := func(subnetID string) bool {
+		for _, subnet := range cfg.VPC.LocalZoneSubnets {
+			if subnet.ID != "" && subnet.ID == subnetID {
+				return true
+			}
+		}
+		return false
+	}
+
+	if cfg.VPC != nil && (cfg.VPC.ID != "" || cfg.HasAnySubnets()) {
+		for _, zone := range localZones {
+			if cfg.VPC.LocalZoneSubnets[zone].ID == "" {
+				return fmt.Errorf("vpc.localZoneSubnets[%s].id must be set when using an existing VPC", zone)
+			}
+		}
+	}
+
+	for _, zone := range cfg.AvailabilityZones {
+		if isLocalZone(zone) {
+			return fmt.Errorf("availabilityZones cannot include local zone %s of vpc.localZoneSubnets", zone)
+		}
+	}
+
+	for i, ng := range cfg.NodeGroups {
+		if len(ng.LocalZones) == 0 {
+			continue
+		}
+		path := fmt.Sprintf("nodeGroups[%d]", i)
+		if len(ng.AvailabilityZones) > 0 || len(ng.Subnets) > 0 {
+			return fmt.Errorf("%s.localZones cannot be set with availabilityZones or subnets", path)
+		}
+		for _, zone := range ng.LocalZones {
+			if !isLocalZone(zone) {
+				return fmt.Errorf("%s.localZones: zone %s is not in vpc.localZoneSubnets", path, zone)
+			}
+		}
+	}
+
+	for i, ng := range cfg.ManagedNodeGroups {
+		path := fmt.Sprintf("managedNodeGroups[%d]", i)
+		for _, zone := range ng.AvailabilityZones {
+			if isLocalZone(zone) {
+				return fmt.Errorf("%s.availabilityZones: managed nodegroups are not supported in local zone %s", path, zone)
+			}
+		}
+		for _, subnetID := range ng.Subnets {
+			if isLocalZoneSubnet(subnetID) {
+				return fmt.Errorf("%s.subnets: managed nodegroups are not supported in local zone subnet %s", path, subnetID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package v1alpha5
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// PodsPerNode is the maximum number of pods per node, either a number or auto
+type PodsPerNode int
+
+const (
+	// MaxPodsPerNodeAuto computes the maximum number of pods per node from the network interfaces
+	// of the instance type, and from the prefixes they can have when prefix delegation is enabled
+	MaxPodsPerNodeAuto PodsPerNode = -1
+
+	maxPodsPerNodeAutoValue = "auto"
+)
+
+// IsAuto returns true if the maximum number of pods is computed from the instance type
+func (p PodsPerNode) IsAuto() bool {
+	return p == MaxPodsPerNodeAuto
+}
+
+// String returns the number of pods or auto
+func (p PodsPerNode) String() string {
+	if p.IsAuto() {
+		return maxPodsPerNodeAutoValue
+	}
+	return strconv.Itoa(int(p))
+}
+
+// Set parses a number of pods or auto, so that PodsPerNode can be used as a flag
+func (p *PodsPerNode) Set(value string) error {
+	if value == maxPodsPerNodeAutoValue {
+		*p = MaxPodsPerNodeAuto
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid number of pods per node %q, must be a positive number or %s", value, maxPodsPerNodeAutoValue)
+	}
+	*p = PodsPerNode(n)
+	return nil
+}
+
+// Type returns the type of the flag
+func (p *PodsPerNode) Type() string {
+	return "int|auto"
+}
+
+// MarshalJSON encodes auto as a string, and numbers as numbers
+func (p PodsPerNode) MarshalJSON() ([]byte, error) {
+	if p.IsAuto() {
+		return json.Marshal(maxPodsPerNodeAutoValue)
+	}
+	return json.Marshal(int(p))
+}
+
+// UnmarshalJSON decodes a number or auto
+func (p *PodsPerNode) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*p = PodsPerNode(n)
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil || value != maxPodsPerNodeAutoValue {
+		return fmt.Errorf("maxPodsPerNode must be a number or %s, got %s", maxPodsPerNodeAutoValue, string(data))
+	}
+	*p = MaxPodsPerNodeAuto
+	return nil
+}
+
+// validateMaxPodsPerNode checks maxPodsPerNode, and the instance types of nodegroups when prefix
+// delegation is enabled, as only instances of the Nitro system support prefixes
+func validateMaxPodsPerNode(cfg *ClusterConfig) error {
+	for i, ng := range cfg.NodeGroups {
+		path := fmt.Sprintf("nodeGroups[%d]", i)
+		if ng.MaxPodsPerNode < 0 && !ng.MaxPodsPerNode.IsAuto() {
+			return fmt.Errorf("%s.maxPodsPerNode must be a positive number or %s", path, maxPodsPerNodeAutoValue)
+		}
+		if ng.MaxPodsPerNode.IsAuto() && IsWindowsImage(ng.AMIFamily) {
+			return fmt.Errorf("%s.maxPodsPerNode cannot be %s for Windows nodegroups", path, maxPodsPerNodeAutoValue)
+		}
+		if !IsEnabled(cfg.PrefixDelegation) {
+			continue
+		}
+		if IsWindowsImage(ng.AMIFamily) {
+			return fmt.Errorf("%s: prefixDelegation is not supported by Windows nodegroups", path)
+		}
+		instanceTypes := []string{ng.InstanceType}
+		if HasMixedInstances(ng) {
+			instanceTypes = ng.InstancesDistribution.InstanceTypes
+		}
+		for _, instanceType := range instanceTypes {
+			if instanceType != "" && instanceType != "mixed" && !IsNitroInstanceType(instanceType) {
+				return fmt.Errorf("%s: instance type %s doesn't support prefix delegation, which requires instance types of the Nitro system", path, instanceType)
+			}
+		}
+	}
+	if IsEnabled(cfg.PrefixDelegation) {
+		for i, ng := range cfg.ManagedNodeGroups {
+			if ng.InstanceType != "" && !IsNitroInstanceType(ng.InstanceType) {
+				return fmt.Errorf("managedNodeGroups[%d]: instance type %s doesn't support prefix delegation, which requires instance types of the Nitro system", i, ng.InstanceType)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package v1alpha5
+
+import "sort"
+
+// instanceNetworking holds the network interface limits of an instance type
+type instanceNetworking struct {
+	networkCards  int
+	maxInterfaces int
+	enaExpress    bool
+	efa           bool
+}
+
+// networkIntensiveInstanceTypes lists the instance types that have several network cards or
+// support ENA Express or EFA, any other instance type is assumed to have a single network
+// card and no support for ENA Express nor EFA
+var networkIntensiveInstanceTypes = map[string]instanceNetworking{
+	"c5n.18xlarge":   {networkCards: 1, maxInterfaces: 15, efa: true},
+	"c6gn.16xlarge":  {networkCards: 1, maxInterfaces: 15, enaExpress: true, efa: true},
+	"c6i.32xlarge":   {networkCards: 1, maxInterfaces: 15, enaExpress: true, efa: true},
+	"c6in.32xlarge":  {networkCards: 2, maxInterfaces: 16, enaExpress: true, efa: true},
+	"c7gn.16xlarge":  {networkCards: 1, maxInterfaces: 15, enaExpress: true, efa: true},
+	"g5.48xlarge":    {networkCards: 1, maxInterfaces: 7, efa: true},
+	"hpc6a.48xlarge": {networkCards: 1, maxInterfaces: 2, efa: true},
+	"m6i.32xlarge":   {networkCards: 1, maxInterfaces: 15, enaExpress: true, efa: true},
+	"m6idn.32xlarge": {networkCards: 2, maxInterfaces: 16, enaExpress: true, efa: true},
+	"m6in.32xlarge":  {networkCards: 2, maxInterfaces: 16, enaExpress: true, efa: true},
+	"p3dn.24xlarge":  {networkCards: 1, maxInterfaces: 15, efa: true},
+	"p4d.24xlarge":   {networkCards: 4, maxInterfaces: 60, efa: true},
+	"p4de.24xlarge":  {networkCards: 4, maxInterfaces: 60, efa: true},
+	"p5.48xlarge":    {networkCards: 32, maxInterfaces: 64, enaExpress: true, efa: true},
+	"r6i.32xlarge":   {networkCards: 1, maxInterfaces: 15, enaExpress: true, efa: true},
+	"r6idn.32xlarge": {networkCards: 2, maxInterfaces: 16, enaExpress: true, efa: true},
+	"r6in.32xlarge":  {networkCards: 2, maxInterfaces: 16, enaExpress: true, efa: true},
+	"trn1.32xlarge":  {networkCards: 8, maxInterfaces: 40, efa: true},
+	"trn1n.32xlarge": {networkCards: 16, maxInterfaces: 80, efa: true},
+}
+
+// EFAInstanceTypes returns the instance types known to support EFA, in alphabetical order
+func EFAInstanceTypes() []string {
+	var instanceTypes []string
+	for instanceType, networking := range networkIntensiveInstanceTypes {
+		if networking.efa {
+			instanceTypes = append(instanceTypes, instanceType)
+		}
+	}
+	sort.Strings(instanceTypes)
+	return instanceTypes
+}
+
+// HasEFANodeGroups returns true if any nodegroup of the cluster has EFA enabled
+func (c *ClusterConfig) HasEFANodeGroups() bool {
+	for _, ng := range c.NodeGroups {
+		if IsEnabled(ng.EFAEnabled) {
+			return true
+		}
+	}
+	return false
+}
+
+func getInstanceNetworking(instanceType string) (instanceNetworking, bool) {
+	n, ok := networkIntensiveInstanceTypes[instanceType]
+	if !ok {
+		return instanceNetworking{networkCards: 1}, false
+	}
+	return n, true
+}
+
+// NetworkInterfaceCount returns the number of network interfaces of the instances of a nodegroup
+func (n *NodeGroupNetworkInterfaces) NetworkInterfaceCount() int {
+	if n == nil || n.Count == nil {
+		return 1
+	}
+	return *n.Count
+}
+
+// NetworkCardIndices returns the network card of each network interface of the instances of a
+// nodegroup, the interfaces being spread over the network cards of the instance type unless
+// networkCardIndices is set
+func NetworkCardIndices(ng *NodeGroup) []int {
+	if ng.NetworkInterfaces != nil && len(ng.NetworkInterfaces.NetworkCardIndices) > 0 {
+		return ng.NetworkInterfaces.NetworkCardIndices
+	}
+
+	instanceType := ng.InstanceType
+	if HasMixedInstances(ng) {
+		instanceType = ng.InstancesDistribution.InstanceTypes[0]
+	}
+	networking, _ := getInstanceNetworking(instanceType)
+
+	indices := make([]int, ng.NetworkInterfaces.NetworkInterfaceCount())
+	for i := range indices {
+		indices[i] = i % networking.networkCards
+	}
+	return indices
+}
@@ -0,0 +1,25 @@
+package v1alpha5
+
+import "strings"
+
+// xenInstanceFamilies are the instance families of the previous generations, which run on the Xen
+// hypervisor instead of the Nitro system
+var xenInstanceFamilies = map[string]bool{
+	"c1": true, "c3": true, "c4": true,
+	"d2": true,
+	"f1": true,
+	"g2": true, "g3": true, "g3s": true,
+	"h1": true,
+	"i2": true, "i3": true,
+	"m1": true, "m2": true, "m3": true, "m4": true,
+	"p2": true, "p3": true,
+	"r3": true, "r4": true,
+	"t1": true, "t2": true,
+	"x1": true, "x1e": true,
+}
+
+// IsNitroInstanceType returns true if the instance type runs on the Nitro system, which features such
+// as ENI trunking and prefix delegation require
+func IsNitroInstanceType(instanceType string) bool {
+	return !xenInstanceFamilies[strings.Split(instanceType, ".")[0]]
+}
@@ -0,0 +1,77 @@
+package v1alpha5
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeTerminationHandlerName is the name of the Kubernetes objects of aws-node-termination-handler,
+// including its IAM service account in queue mode
+const NodeTerminationHandlerName = "aws-node-termination-handler"
+
+// maxQueueNameLength is the maximum length of the name of an SQS queue
+const maxQueueNameLength = 80
+
+// HasSpotInstances returns true if a nodegroup launches Spot instances above its On-Demand capacity
+func HasSpotInstances(ng *NodeGroup) bool {
+	return ng.InstancesDistribution != nil && ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity != nil &&
+		*ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity < 100
+}
+
+// HasSpotNodeGroups returns true if any nodegroup of the cluster launches Spot instances
+func (c *ClusterConfig) HasSpotNodeGroups() bool {
+	for _, ng := range c.NodeGroups {
+		if HasSpotInstances(ng) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNodeTerminationHandlerQueue returns true if aws-node-termination-handler consumes the
+// interruption events from an SQS queue
+func (c *ClusterConfig) HasNodeTerminationHandlerQueue() bool {
+	return c.NodeTerminationHandler != nil && c.NodeTerminationHandler.Mode == NodeTerminationHandlerModeQueue
+}
+
+// NodeTerminationHandlerQueueName returns the name of the SQS queue of aws-node-termination-handler,
+// the name being known in advance so that the IAM policy of the handler can refer to it
+func (c *ClusterConfig) NodeTerminationHandlerQueueName() string {
+	return "eksctl-" + c.Metadata.Name + "-nth"
+}
+
+// newNodeTerminationHandlerServiceAccount creates the IAM service account of aws-node-termination-handler
+// in queue mode, allowing it to consume the queue and to complete the lifecycle actions of the nodegroups
+func newNodeTerminationHandlerServiceAccount(queueName string) *ClusterIAMServiceAccount {
+	return &ClusterIAMServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NodeTerminationHandlerName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		AttachPolicy: InlineDocument{
+			"Version": "2012-10-17",
+			"Statement": []interface{}{
+				map[string]interface{}{
+					"Effect": "Allow",
+					"Action": []interface{}{
+						"autoscaling:CompleteLifecycleAction",
+						"autoscaling:DescribeAutoScalingInstances",
+						"autoscaling:DescribeTags",
+						"ec2:DescribeInstances",
+					},
+					"Resource": "*",
+				},
+				map[string]interface{}{
+					"Effect": "Allow",
+					"Action": []interface{}{
+						"sqs:DeleteMessage",
+						"sqs:ReceiveMessage",
+					},
+					// the policy is part of the stack of the service account
+					"Resource": map[string]interface{}{
+						"Fn::Sub": "arn:${AWS::Partition}:sqs:${AWS::Region}:${AWS::AccountId}:" + queueName,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+)
+
+// IsLocalCluster returns true if the control plane of the cluster runs on an AWS Outpost
+func (c *ClusterConfig) IsLocalCluster() bool {
+	return c.Outpost != nil
+}
+
+// setLocalClusterEndpointAccessDefaults makes the API endpoint of local clusters private, as
+// it's only reachable from the network of the Outpost, unless the endpoint access is customised
+func setLocalClusterEndpointAccessDefaults(vpc *ClusterVPC) {
+	if vpc.ClusterEndpoints == nil || EndpointsEqual(*vpc.ClusterEndpoints, *ClusterEndpointAccessDefaults()) {
+		vpc.ClusterEndpoints = &ClusterEndpoints{
+			PrivateAccess: Enabled(),
+			PublicAccess:  Disabled(),
+		}
+	}
+}
+
+func validateOutpostARN(outpostARN, path string) error {
+	parsed, err := arn.Parse(outpostARN)
+	if err != nil || parsed.Service != "outposts" || !strings.HasPrefix(parsed.Resource, "outpost/") {
+		return fmt.Errorf("%s must be the ARN of an Outpost, e.g. arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0, got %q", path, outpostARN)
+	}
+	return nil
+}
+
+// validateOutpost checks the settings of local clusters, and of the nodegroups on Outposts,
+// as Outposts don't support all the features of EKS and EC2
+func validateOutpost(cfg *ClusterConfig) error {
+	if cfg.IsLocalCluster() {
+		if err := validateOutpostARN(cfg.Outpost.ControlPlaneOutpostARN, "outpost.controlPlaneOutpostARN"); err != nil {
+			return err
+		}
+		var unsupported string
+		switch {
+		case len(cfg.ManagedNodeGroups) > 0:
+			unsupported = "managedNodeGroups"
+		case len(cfg.FargateProfiles) > 0:
+			unsupported = "fargateProfiles"
+		case cfg.IAM != nil && IsEnabled(cfg.IAM.WithOIDC):
+			unsupported = "iam.withOIDC"
+		case len(cfg.AvailabilityZones) > 0:
+			unsupported = "availabilityZones"
+		}
+		if unsupported != "" {
+			return fmt.Errorf("%s is not supported for local clusters on Outposts", unsupported)
+		}
+		if cfg.VPC == nil || !cfg.HasAnySubnets() {
+			return fmt.Errorf("local clusters on Outposts must use existing subnets of the Outpost, set in vpc.subnets")
+		}
+		if cfg.VPC.ClusterEndpoints != nil && IsEnabled(cfg.VPC.ClusterEndpoints.PublicAccess) {
+			return fmt.Errorf("vpc.clusterEndpoints.publicAccess is not supported for local clusters on Outposts")
+		}
+	}
+
+	for i, ng := range cfg.NodeGroups {
+		if ng.OutpostARN == "" {
+			continue
+		}
+		path := fmt.Sprintf("nodeGroups[%d]", i)
+		if err := validateOutpostARN(ng.OutpostARN, path+".outpostARN"); err != nil {
+			return err
+		}
+		if cfg.IsLocalCluster() && ng.OutpostARN != cfg.Outpost.ControlPlaneOutpostARN {
+			return fmt.Errorf("%s.outpostARN must be the Outpost of the control plane of local clusters", path)
+		}
+		if len(ng.Subnets) == 0 {
+			return fmt.Errorf("%s.subnets must be set to subnets of the Outpost", path)
+		}
+		if ng.InstancesDistribution != nil {
+			return fmt.Errorf("%s.instancesDistribution is not supported on Outposts, which have no Spot instances", path)
+		}
+		if ng.VolumeType != nil && *ng.VolumeType != NodeVolumeTypeGP2 {
+			return fmt.Errorf("%s.volumeType must be %s on Outposts", path, NodeVolumeTypeGP2)
+		}
+		if ng.AMIFamily != "" && ng.AMIFamily != NodeImageFamilyAmazonLinux2 {
+			return fmt.Errorf("%s.amiFamily must be %s on Outposts", path, NodeImageFamilyAmazonLinux2)
+		}
+	}
+	return nil
+}
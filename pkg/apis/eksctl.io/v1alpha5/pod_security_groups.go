@@ -0,0 +1,59 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PodSecurityGroupsMinVersion is the first Kubernetes version that EKS supports security groups for pods on
+const PodSecurityGroupsMinVersion = "1.17"
+
+// SupportsENITrunking returns true if the instance type can have a trunk network interface, which
+// security groups for pods require; only instances of the Nitro system other than burstable
+// instances (t*) support it
+func SupportsENITrunking(instanceType string) bool {
+	return IsNitroInstanceType(instanceType) && !strings.HasPrefix(instanceType, "t")
+}
+
+// validatePodSecurityGroups checks that the nodegroups can run pods with security groups
+func validatePodSecurityGroups(cfg *ClusterConfig) error {
+	if !IsEnabled(cfg.PodSecurityGroups) {
+		return nil
+	}
+	if cfg.IsLocalCluster() {
+		return fmt.Errorf("podSecurityGroups is not supported for local clusters on Outposts")
+	}
+	if version, older := isOlderVersion(cfg.Metadata, PodSecurityGroupsMinVersion); older {
+		return fmt.Errorf("podSecurityGroups requires Kubernetes %s or newer, the cluster uses %s", PodSecurityGroupsMinVersion, version)
+	}
+
+	checkInstanceType := func(path, instanceType string) error {
+		if instanceType == "" || instanceType == "mixed" || SupportsENITrunking(instanceType) {
+			return nil
+		}
+		return fmt.Errorf("%s: instance type %s doesn't support ENI trunking, which podSecurityGroups requires", path, instanceType)
+	}
+
+	for i, ng := range cfg.NodeGroups {
+		path := fmt.Sprintf("nodeGroups[%d]", i)
+		if IsWindowsImage(ng.AMIFamily) {
+			return fmt.Errorf("%s: podSecurityGroups is not supported by Windows nodegroups", path)
+		}
+		if err := checkInstanceType(path+".instanceType", ng.InstanceType); err != nil {
+			return err
+		}
+		if HasMixedInstances(ng) {
+			for j, instanceType := range ng.InstancesDistribution.InstanceTypes {
+				if err := checkInstanceType(fmt.Sprintf("%s.instancesDistribution.instanceTypes[%d]", path, j), instanceType); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for i, ng := range cfg.ManagedNodeGroups {
+		if err := checkInstanceType(fmt.Sprintf("managedNodeGroups[%d].instanceType", i), ng.InstanceType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
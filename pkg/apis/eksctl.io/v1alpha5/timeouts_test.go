@@ -0,0 +1,63 @@
+package v1alpha5
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Timeouts", func() {
+	var p *ProviderConfig
+
+	BeforeEach(func() {
+		p = &ProviderConfig{WaitTimeout: DefaultWaitTimeout}
+	})
+
+	It("uses the defaults of the phases", func() {
+		Expect(p.Timeout(TimeoutPhaseControlPlane)).To(Equal(40 * time.Minute))
+		Expect(p.Timeout(TimeoutPhaseDrain)).To(Equal(25 * time.Minute))
+	})
+
+	It("uses WaitTimeout when it's set explicitly", func() {
+		p.WaitTimeout = 5 * time.Minute
+		p.WaitTimeoutSet = true
+		Expect(p.Timeout(TimeoutPhaseControlPlane)).To(Equal(5 * time.Minute))
+	})
+
+	It("gives precedence to the timeouts of the phases", func() {
+		p.WaitTimeout = 5 * time.Minute
+		p.WaitTimeoutSet = true
+		p.PhaseTimeouts = map[TimeoutPhase]time.Duration{TimeoutPhaseNodeGroup: time.Hour}
+		Expect(p.Timeout(TimeoutPhaseNodeGroup)).To(Equal(time.Hour))
+		Expect(p.Timeout(TimeoutPhaseAddon)).To(Equal(5 * time.Minute))
+	})
+
+	It("doesn't override the timeouts of the phases with the ones of the config file", func() {
+		p.PhaseTimeouts = map[TimeoutPhase]time.Duration{TimeoutPhaseNodeGroup: time.Hour}
+		timeouts := &ClusterTimeouts{
+			NodeGroup: &metav1.Duration{Duration: 30 * time.Minute},
+			Drain:     &metav1.Duration{Duration: 2 * time.Minute},
+		}
+		p.SetPhaseTimeouts(timeouts.Phases())
+		Expect(p.Timeout(TimeoutPhaseNodeGroup)).To(Equal(time.Hour))
+		Expect(p.Timeout(TimeoutPhaseDrain)).To(Equal(2 * time.Minute))
+	})
+
+	It("doesn't override an explicit WaitTimeout with the timeouts of the config file", func() {
+		p.WaitTimeout = 5 * time.Minute
+		p.WaitTimeoutSet = true
+		timeouts := &ClusterTimeouts{NodeGroup: &metav1.Duration{Duration: 30 * time.Minute}}
+		p.SetPhaseTimeouts(timeouts.Phases())
+		Expect(p.Timeout(TimeoutPhaseNodeGroup)).To(Equal(5 * time.Minute))
+	})
+
+	It("rejects timeouts that aren't positive", func() {
+		cfg := NewClusterConfig()
+		cfg.Timeouts = &ClusterTimeouts{Addon: &metav1.Duration{}}
+		SetClusterConfigDefaults(cfg)
+		err := ValidateClusterConfig(cfg)
+		Expect(err).To(MatchError("timeouts.addon must be a positive duration, got 0s"))
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
@@ -113,13 +114,20 @@ const (
 	// DefaultNodeType is the default instance type to use for nodes
 	DefaultNodeType = "m5.large"
 
+	// DefaultOutpostControlPlaneInstanceType is the default instance type of the control plane of local clusters
+	DefaultOutpostControlPlaneInstanceType = "m5.large"
+
 	// DefaultNodeCount defines the default number of nodes to be created
 	DefaultNodeCount = 2
 
 	// NodeVolumeTypeGP2 is General Purpose SSD
 	NodeVolumeTypeGP2 = "gp2"
+	// NodeVolumeTypeGP3 is General Purpose SSD with configurable IOPS and throughput
+	NodeVolumeTypeGP3 = "gp3"
 	// NodeVolumeTypeIO1 is Provisioned IOPS SSD
 	NodeVolumeTypeIO1 = "io1"
+	// NodeVolumeTypeIO2 is Provisioned IOPS SSD with higher durability
+	NodeVolumeTypeIO2 = "io2"
 	// NodeVolumeTypeSC1 is Throughput Optimized HDD
 	NodeVolumeTypeSC1 = "sc1"
 	// NodeVolumeTypeST1 is Cold HDD
@@ -157,6 +165,10 @@ const (
 	// OldClusterNameTag defines the tag of the cluster name
 	OldClusterNameTag = "eksctl.cluster.k8s.io/v1alpha1/cluster-name"
 
+	// ClusterRegisteredTag marks a cluster stack that was created by
+	// `eksctl register cluster` for a cluster that eksctl did not create
+	ClusterRegisteredTag = "alpha.eksctl.io/cluster-registered"
+
 	// NodeGroupNameTag defines the tag of the nodegroup name
 	NodeGroupNameTag = "alpha.eksctl.io/nodegroup-name"
 
@@ -178,6 +190,21 @@ const (
 	// NodeGroupNameLabel defines the label of the nodegroup name
 	NodeGroupNameLabel = "alpha.eksctl.io/nodegroup-name"
 
+	// TopologyZoneLabel defines the label of the availability zone of a node
+	TopologyZoneLabel = "topology.kubernetes.io/zone"
+
+	// FailureDomainZoneLabel defines the deprecated label of the availability zone of a node,
+	// it's the one used by the EBS volume provisioner
+	FailureDomainZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+
+	// NodeTemplateLabelTagPrefix is the prefix of the ASG tags telling cluster-autoscaler
+	// the labels of the nodes, so that it can scale a nodegroup up from zero
+	NodeTemplateLabelTagPrefix = "k8s.io/cluster-autoscaler/node-template/label/"
+
+	// NodeTemplateTaintTagPrefix is the prefix of the ASG tags telling cluster-autoscaler
+	// the taints of the nodes, so that it can scale a nodegroup up from zero
+	NodeTemplateTaintTagPrefix = "k8s.io/cluster-autoscaler/node-template/taint/"
+
 	// ClusterHighlyAvailableNAT defines the highly available NAT configuration option
 	ClusterHighlyAvailableNAT = "HighlyAvailable"
 
@@ -193,6 +220,83 @@ const (
 	// SpotAllocationStrategyCapacityOptimized defines the ASG spot allocation strategy of capacity-optimized
 	SpotAllocationStrategyCapacityOptimized = "capacity-optimized"
 
+	// SwapBehaviorLimited lets Burstable pods use swap up to their memory limit
+	SwapBehaviorLimited = "LimitedSwap"
+
+	// SwapBehaviorUnlimited lets workloads use as much swap as they request
+	SwapBehaviorUnlimited = "UnlimitedSwap"
+
+	// SwapMinVersion is the first Kubernetes version whose kubelet can run on nodes with swap
+	SwapMinVersion = "1.22"
+
+	// PlacementStrategyCluster packs instances close together in a single availability zone
+	PlacementStrategyCluster = "cluster"
+
+	// PlacementStrategySpread places instances on distinct hardware
+	PlacementStrategySpread = "spread"
+
+	// PlacementStrategyPartition spreads instances over partitions that don't share hardware
+	PlacementStrategyPartition = "partition"
+
+	// TenancyDefault runs instances on shared hardware
+	TenancyDefault = "default"
+
+	// TenancyDedicated runs instances on hardware dedicated to the account
+	TenancyDedicated = "dedicated"
+
+	// TenancyHost runs instances on Dedicated Hosts
+	TenancyHost = "host"
+
+	// CapacityReservationPreferenceOpen runs instances in any open capacity reservation matching
+	// their attributes, and as On-Demand instances if there's none
+	CapacityReservationPreferenceOpen = "open"
+
+	// CapacityReservationPreferenceNone never runs instances in a capacity reservation
+	CapacityReservationPreferenceNone = "none"
+
+	// CapacityReservationPreferenceCapacityReservationsOnly only runs instances in capacity reservations,
+	// they fail to launch if no capacity is available
+	CapacityReservationPreferenceCapacityReservationsOnly = "capacity-reservations-only"
+
+	// WarmPoolStateStopped keeps the instances of a warm pool stopped
+	WarmPoolStateStopped = "Stopped"
+
+	// WarmPoolStateRunning keeps the instances of a warm pool running
+	WarmPoolStateRunning = "Running"
+
+	// WarmPoolStateHibernated keeps the instances of a warm pool hibernated
+	WarmPoolStateHibernated = "Hibernated"
+
+	// HugePageSize2Mi defines the 2MiB huge page size
+	HugePageSize2Mi = "2Mi"
+
+	// HugePageSize1Gi defines the 1GiB huge page size
+	HugePageSize1Gi = "1Gi"
+
+	// ContainerRuntimeDocker defines the docker container runtime
+	ContainerRuntimeDocker = "docker"
+
+	// ContainerRuntimeContainerd defines the containerd container runtime, used through its CRI plugin
+	ContainerRuntimeContainerd = "containerd"
+
+	// ProcessAZRebalance defines the ASG process that balances instances across availability zones
+	ProcessAZRebalance = "AZRebalance"
+
+	// ProcessReplaceUnhealthy defines the ASG process that replaces unhealthy instances
+	ProcessReplaceUnhealthy = "ReplaceUnhealthy"
+
+	// ProcessHealthCheck defines the ASG process that checks the health of instances
+	ProcessHealthCheck = "HealthCheck"
+
+	// ProcessAlarmNotification defines the ASG process that runs the actions of CloudWatch alarms
+	ProcessAlarmNotification = "AlarmNotification"
+
+	// ProcessScheduledActions defines the ASG process that runs scheduled actions
+	ProcessScheduledActions = "ScheduledActions"
+
+	// ProcessAddToLoadBalancer defines the ASG process that registers instances with load balancers
+	ProcessAddToLoadBalancer = "AddToLoadBalancer"
+
 	// eksResourceAccountStandard defines the AWS EKS account ID that provides node resources in default regions
 	// for standard AWS partition
 	eksResourceAccountStandard = "602401143452"
@@ -220,6 +324,43 @@ const (
 	NodeGroupTypeUnmanaged NodeGroupType = "unmanaged"
 )
 
+// TimeoutPhase is a phase of operations that has its own timeout
+type TimeoutPhase string
+
+const (
+	// TimeoutPhaseControlPlane is the creation, update and deletion of the control plane
+	TimeoutPhaseControlPlane TimeoutPhase = "controlPlane"
+	// TimeoutPhaseNodeGroup is the creation, update and deletion of nodegroups, and waiting for their nodes
+	TimeoutPhaseNodeGroup TimeoutPhase = "nodeGroup"
+	// TimeoutPhaseAddon is the creation and deletion of the stacks of addons, such as IAM service accounts
+	TimeoutPhaseAddon TimeoutPhase = "addon"
+	// TimeoutPhaseDrain is the draining of each node
+	TimeoutPhaseDrain TimeoutPhase = "drain"
+)
+
+const (
+	// StackOnFailureRollback rolls back the stacks that fail to be created, deleting their resources
+	StackOnFailureRollback = "rollback"
+	// StackOnFailurePreserve keeps the stacks that fail to be created and their resources, for debugging
+	StackOnFailurePreserve = "preserve"
+	// StackOnFailureDelete deletes the stacks that fail to be created
+	StackOnFailureDelete = "delete"
+)
+
+// SupportedStackOnFailureValues are the values of ProviderConfig.StackOnFailure
+func SupportedStackOnFailureValues() []string {
+	return []string{StackOnFailureRollback, StackOnFailurePreserve, StackOnFailureDelete}
+}
+
+// DefaultPhaseTimeouts are the timeouts of the phases of operations when
+// neither them nor the global timeout are set
+var DefaultPhaseTimeouts = map[TimeoutPhase]time.Duration{
+	TimeoutPhaseControlPlane: 40 * time.Minute,
+	TimeoutPhaseNodeGroup:    25 * time.Minute,
+	TimeoutPhaseAddon:        10 * time.Minute,
+	TimeoutPhaseDrain:        25 * time.Minute,
+}
+
 var (
 	// DefaultWaitTimeout defines the default wait timeout
 	DefaultWaitTimeout = 25 * time.Minute
@@ -303,16 +444,40 @@ func SupportedVersions() []string {
 	}
 }
 
+// SupportedNATGatewayModes are the modes of the NAT gateways of the VPC: HighlyAvailable creates a NAT
+// gateway and a private route table per availability zone, Single shares one NAT gateway between the
+// availability zones, and Disable creates no NAT gateway
+func SupportedNATGatewayModes() []string {
+	return []string{
+		ClusterHighlyAvailableNAT,
+		ClusterSingleNAT,
+		ClusterDisableNAT,
+	}
+}
+
 // SupportedNodeVolumeTypes are the volume types that can be used for a node root volume
 func SupportedNodeVolumeTypes() []string {
 	return []string{
 		NodeVolumeTypeGP2,
+		NodeVolumeTypeGP3,
 		NodeVolumeTypeIO1,
+		NodeVolumeTypeIO2,
 		NodeVolumeTypeSC1,
 		NodeVolumeTypeST1,
 	}
 }
 
+// supportedAMIFamilies are the AMI families of self-managed nodegroups
+func supportedAMIFamilies() []string {
+	return []string{
+		NodeImageFamilyAmazonLinux2,
+		NodeImageFamilyUbuntu1804,
+		NodeImageFamilyBottlerocket,
+		NodeImageFamilyWindowsServer2019CoreContainer,
+		NodeImageFamilyWindowsServer2019FullContainer,
+	}
+}
+
 // supportedSpotAllocationStrategies are the spot allocation strategies supported by ASG
 func supportedSpotAllocationStrategies() []string {
 	return []string{
@@ -321,6 +486,79 @@ func supportedSpotAllocationStrategies() []string {
 	}
 }
 
+// supportedSwapBehaviors are the kubelet swap behaviors
+func supportedSwapBehaviors() []string {
+	return []string{
+		SwapBehaviorLimited,
+		SwapBehaviorUnlimited,
+	}
+}
+
+// supportedPlacementStrategies are the strategies of placement groups
+func supportedPlacementStrategies() []string {
+	return []string{
+		PlacementStrategyCluster,
+		PlacementStrategySpread,
+		PlacementStrategyPartition,
+	}
+}
+
+// supportedTenancies are the tenancies of instances
+func supportedTenancies() []string {
+	return []string{
+		TenancyDefault,
+		TenancyDedicated,
+		TenancyHost,
+	}
+}
+
+// supportedCapacityReservationPreferences are the capacity reservation preferences of instances
+func supportedCapacityReservationPreferences() []string {
+	return []string{
+		CapacityReservationPreferenceOpen,
+		CapacityReservationPreferenceNone,
+		CapacityReservationPreferenceCapacityReservationsOnly,
+	}
+}
+
+// supportedWarmPoolStates are the states of the instances of a warm pool
+func supportedWarmPoolStates() []string {
+	return []string{
+		WarmPoolStateStopped,
+		WarmPoolStateRunning,
+		WarmPoolStateHibernated,
+	}
+}
+
+// supportedHugePageSizes are the huge page sizes that can be pre-allocated on nodes
+func supportedHugePageSizes() []string {
+	return []string{
+		HugePageSize2Mi,
+		HugePageSize1Gi,
+	}
+}
+
+// SuspendableProcesses returns the ASG processes of nodegroups that can be suspended, Launch
+// and Terminate are left out as scaling nodegroups relies on them
+func SuspendableProcesses() []string {
+	return []string{
+		ProcessAZRebalance,
+		ProcessReplaceUnhealthy,
+		ProcessHealthCheck,
+		ProcessAlarmNotification,
+		ProcessScheduledActions,
+		ProcessAddToLoadBalancer,
+	}
+}
+
+// supportedContainerRuntimes are the container runtimes that kubelet can be configured with
+func supportedContainerRuntimes() []string {
+	return []string{
+		ContainerRuntimeDocker,
+		ContainerRuntimeContainerd,
+	}
+}
+
 // isSpotAllocationStrategySupported returns true if the spot allocation strategy is supported for ASG
 func isSpotAllocationStrategySupported(allocationStrategy string) bool {
 	return slice.Contains(supportedSpotAllocationStrategies(), allocationStrategy)
@@ -345,12 +583,24 @@ func EKSResourceAccountID(region string) string {
 
 // ClusterMeta is what identifies a cluster
 type ClusterMeta struct {
-	Name   string `json:"name"`
+	// Name of the cluster
+	Name string `json:"name"`
+	// Region of the cluster
 	Region string `json:"region"`
+	// Version of Kubernetes, e.g. "1.15"
 	// +optional
 	Version string `json:"version,omitempty"`
+	// Tags are added to all the AWS resources created by eksctl
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
+	// DisableIMDSv1 is the default of `disableIMDSv1` for all the nodegroups
+	// +since=0.19.0
+	// +optional
+	DisableIMDSv1 *bool `json:"disableIMDSv1,omitempty"`
+	// AssumeRole holds the IAM roles assumed for all AWS API calls, the `--assume-role-*` flags take precedence
+	// +since=0.19.0
+	// +optional
+	AssumeRole *ClusterAssumeRole `json:"assumeRole,omitempty"`
 }
 
 // ClusterStatus hold read-only attributes of a cluster
@@ -404,9 +654,12 @@ type ClusterProvider interface {
 	SSM() ssmiface.SSMAPI
 	IAM() iamiface.IAMAPI
 	CloudTrail() cloudtrailiface.CloudTrailAPI
+	ASG() autoscalingiface.AutoScalingAPI
 	Region() string
 	Profile() string
 	WaitTimeout() time.Duration
+	Timeout(phase TimeoutPhase) time.Duration
+	StackOnFailure() string
 }
 
 // ProviderConfig holds global parameters for all interactions with AWS APIs
@@ -416,6 +669,93 @@ type ProviderConfig struct {
 	Region      string
 	Profile     string
 	WaitTimeout time.Duration
+
+	// WaitTimeoutSet is true when WaitTimeout was set explicitly, it then applies to
+	// the phases whose timeout isn't set instead of their default timeout
+	WaitTimeoutSet bool
+	// PhaseTimeouts are the timeouts of the phases of operations that were set explicitly
+	PhaseTimeouts map[TimeoutPhase]time.Duration
+
+	// AssumeRoleARNs are the IAM roles to assume, in order,
+	// each role is assumed with the credentials of the previous one
+	AssumeRoleARNs []string
+	// AssumeRoleExternalID is the external ID used to assume the last role
+	AssumeRoleExternalID string
+	// AssumeRoleSessionName is the session name used to assume the roles
+	AssumeRoleSessionName string
+
+	// StackOnFailure is what happens to the stacks that fail to be created, valid variants
+	// are `StackOnFailure` constants, they're rolled back when it's empty
+	StackOnFailure string
+
+	// MaxAPIQPS is the maximum rate of EC2, CloudFormation and EKS API calls per second,
+	// they're not rate limited when it's 0
+	MaxAPIQPS float64
+	// APIBurst is the number of calls that can be made at once within MaxAPIQPS
+	APIBurst int
+}
+
+// Timeout returns the timeout of a phase of operations, which is its own timeout when it's set,
+// then WaitTimeout when it's set explicitly, and then the default timeout of the phase
+func (p *ProviderConfig) Timeout(phase TimeoutPhase) time.Duration {
+	if timeout, ok := p.PhaseTimeouts[phase]; ok && timeout > 0 {
+		return timeout
+	}
+	if p.WaitTimeoutSet {
+		return p.WaitTimeout
+	}
+	if timeout, ok := DefaultPhaseTimeouts[phase]; ok {
+		return timeout
+	}
+	return p.WaitTimeout
+}
+
+// ValidateStackOnFailure checks StackOnFailure
+func (p *ProviderConfig) ValidateStackOnFailure() error {
+	if p.StackOnFailure != "" && !slice.Contains(SupportedStackOnFailureValues(), p.StackOnFailure) {
+		return fmt.Errorf("invalid value %q for --on-failure, valid options: %s", p.StackOnFailure, strings.Join(SupportedStackOnFailureValues(), ", "))
+	}
+	return nil
+}
+
+// ValidateAPIRateLimit validates the rate limit of AWS API calls
+func (p *ProviderConfig) ValidateAPIRateLimit() error {
+	if p.MaxAPIQPS < 0 {
+		return fmt.Errorf("invalid value %v for --max-api-qps, it must not be negative", p.MaxAPIQPS)
+	}
+	if p.APIBurst < 0 {
+		return fmt.Errorf("invalid value %d for --api-burst, it must not be negative", p.APIBurst)
+	}
+	return nil
+}
+
+// SetPhaseTimeouts sets the timeouts of the phases that aren't set yet, unless WaitTimeout was
+// set explicitly, as the timeouts are set from the config file and flags take precedence
+func (p *ProviderConfig) SetPhaseTimeouts(timeouts map[TimeoutPhase]time.Duration) {
+	if p.WaitTimeoutSet {
+		return
+	}
+	if p.PhaseTimeouts == nil {
+		p.PhaseTimeouts = map[TimeoutPhase]time.Duration{}
+	}
+	for phase, timeout := range timeouts {
+		if _, ok := p.PhaseTimeouts[phase]; !ok {
+			p.PhaseTimeouts[phase] = timeout
+		}
+	}
+}
+
+// SetAssumeRole sets the assume-role settings that aren't set yet
+func (p *ProviderConfig) SetAssumeRole(assumeRole *ClusterAssumeRole) {
+	if len(p.AssumeRoleARNs) == 0 {
+		p.AssumeRoleARNs = assumeRole.RoleARNs
+	}
+	if p.AssumeRoleExternalID == "" {
+		p.AssumeRoleExternalID = assumeRole.ExternalID
+	}
+	if p.AssumeRoleSessionName == "" {
+		p.AssumeRoleSessionName = assumeRole.SessionName
+	}
 }
 
 // +genclient
@@ -425,35 +765,270 @@ type ProviderConfig struct {
 type ClusterConfig struct {
 	metav1.TypeMeta `json:",inline"`
 
+	// Metadata identifies the cluster
 	Metadata *ClusterMeta `json:"metadata"`
 
+	// IAM holds the IAM settings of the cluster, such as its service role and OIDC provider
 	// +optional
 	IAM *ClusterIAM `json:"iam,omitempty"`
 
+	// VPC holds the network settings of the cluster, a new VPC is created unless
+	// the IDs of existing subnets are set
 	// +optional
 	VPC *ClusterVPC `json:"vpc,omitempty"`
 
+	// NodeGroups are the self-managed nodegroups of the cluster
 	// +optional
 	NodeGroups []*NodeGroup `json:"nodeGroups,omitempty"`
 
+	// ManagedNodeGroups are the EKS-managed nodegroups of the cluster
 	// +optional
 	ManagedNodeGroups []*ManagedNodeGroup `json:"managedNodeGroups,omitempty"`
 
+	// FargateProfiles select the pods that run on Fargate
 	// +optional
 	FargateProfiles []*FargateProfile `json:"fargateProfiles,omitempty"`
 
+	// AvailabilityZones of the subnets of a new VPC, chosen automatically if unset
 	// +optional
 	AvailabilityZones []string `json:"availabilityZones,omitempty"`
 
+	// CloudWatch holds the logging settings of the control plane
 	// +optional
 	CloudWatch *ClusterCloudWatch `json:"cloudWatch,omitempty"`
 
+	// SecretsEncryption enables the encryption of Kubernetes secrets with a KMS key
 	// +optional
 	SecretsEncryption *SecretsEncryption `json:"secretsEncryption,omitempty"`
 
+	// Timeouts of the phases of operations, the flags of the phases take precedence
+	// +since=0.19.0
+	// +optional
+	Timeouts *ClusterTimeouts `json:"timeouts,omitempty"`
+
+	// RegistryMirrors maps a registry host (e.g. docker.io) to the endpoints of its
+	// mirrors, which all the nodes pull images through; self-managed nodegroups must
+	// use containerd or Bottlerocket, and nodegroup mirrors take precedence
+	// +since=0.19.0
+	// +optional
+	RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
+
+	// BudgetAlarms notifies of the data processed by the NAT gateways and of the
+	// cost of the inter-AZ data transfer of the cluster exceeding thresholds
+	// +since=0.19.0
+	// +optional
+	BudgetAlarms *ClusterBudgetAlarms `json:"budgetAlarms,omitempty"`
+
+	// NodeTerminationHandler deploys aws-node-termination-handler, which drains the nodes
+	// of Spot nodegroups before they're interrupted
+	// +since=0.19.0
+	// +optional
+	NodeTerminationHandler *ClusterNodeTerminationHandler `json:"nodeTerminationHandler,omitempty"`
+
+	// Outpost creates a local cluster, whose control plane runs on an AWS Outpost
+	// in existing subnets of the Outpost, set in vpc.subnets
+	// +since=0.19.0
+	// +optional
+	Outpost *Outpost `json:"outpost,omitempty"`
+
+	// PodSecurityGroups enables security groups for pods, which run in branch network
+	// interfaces of a trunk interface of the nodes; the instance types of the nodegroups
+	// must support ENI trunking
+	// +since=0.19.0
+	// +optional
+	PodSecurityGroups *bool `json:"podSecurityGroups,omitempty"`
+
+	// PrefixDelegation makes the VPC CNI assign /28 prefixes instead of single IPs to the network
+	// interfaces of the nodes, which can then run more pods; the instance types of the nodegroups
+	// must be of the Nitro system, and maxPodsPerNode defaults to auto
+	// +since=0.19.0
+	// +optional
+	PrefixDelegation *bool `json:"prefixDelegation,omitempty"`
+
+	// Network holds the settings of the pod network, such as the CNI plugin
+	// +since=0.19.0
+	// +optional
+	Network *ClusterNetwork `json:"network,omitempty"`
+
+	// GitOps holds the settings of the GitOps tooling of the cluster, set up with `eksctl enable flux`
+	// +since=0.19.0
+	// +optional
+	GitOps *GitOps `json:"gitops,omitempty"`
+
+	// IAMIdentityMappings map IAM roles and users to Kubernetes users and groups in the
+	// aws-auth ConfigMap, they're reconciled by `eksctl apply`
+	// +since=0.19.0
+	// +optional
+	IAMIdentityMappings []*IAMIdentityMapping `json:"iamIdentityMappings,omitempty"`
+
 	Status *ClusterStatus `json:"status,omitempty"`
 }
 
+// ClusterTimeouts holds the timeouts of the phases of operations, e.g. `40m`
+type ClusterTimeouts struct {
+	// ControlPlane is the timeout of the creation, update and deletion of the control plane
+	// +optional
+	ControlPlane *metav1.Duration `json:"controlPlane,omitempty"`
+	// NodeGroup is the timeout of the creation, update and deletion of nodegroups, and of waiting for their nodes
+	// +optional
+	NodeGroup *metav1.Duration `json:"nodeGroup,omitempty"`
+	// Addon is the timeout of the creation and deletion of the stacks of addons, such as IAM service accounts
+	// +optional
+	Addon *metav1.Duration `json:"addon,omitempty"`
+	// Drain is the timeout of draining each node
+	// +optional
+	Drain *metav1.Duration `json:"drain,omitempty"`
+}
+
+// Phases returns the timeouts that are set by phase
+func (t *ClusterTimeouts) Phases() map[TimeoutPhase]time.Duration {
+	timeouts := map[TimeoutPhase]time.Duration{}
+	for phase, timeout := range map[TimeoutPhase]*metav1.Duration{
+		TimeoutPhaseControlPlane: t.ControlPlane,
+		TimeoutPhaseNodeGroup:    t.NodeGroup,
+		TimeoutPhaseAddon:        t.Addon,
+		TimeoutPhaseDrain:        t.Drain,
+	} {
+		if timeout != nil {
+			timeouts[phase] = timeout.Duration
+		}
+	}
+	return timeouts
+}
+
+// Default thresholds of the budget alarms
+const (
+	DefaultNATGatewayGigabytesPerDay      = 100
+	DefaultInterAZTransferDollarsPerMonth = 50
+)
+
+// ClusterBudgetAlarms holds the thresholds of the budget alarms and where they're sent
+type ClusterBudgetAlarms struct {
+	// SNSTopicARN is the SNS topic the alarms are sent to, a topic is created
+	// in the cluster stack if it's not set
+	// +optional
+	SNSTopicARN string `json:"snsTopicARN,omitempty"`
+	// NATGatewayGigabytesPerDay is the amount of data processed by each NAT gateway
+	// created by eksctl in a day above which an alarm is sent
+	// +optional
+	NATGatewayGigabytesPerDay *int `json:"natGatewayGigabytesPerDay,omitempty"`
+	// InterAZTransferDollarsPerMonth is the monthly cost in USD of the data transferred
+	// between availability zones by the nodes of the cluster above which an alarm is sent
+	// +optional
+	InterAZTransferDollarsPerMonth *int `json:"interAZTransferDollarsPerMonth,omitempty"`
+}
+
+// Values for `NodeTerminationHandlerMode`
+const (
+	// NodeTerminationHandlerModeIMDS runs the handler on every node, polling the instance metadata service
+	NodeTerminationHandlerModeIMDS = "imds"
+	// NodeTerminationHandlerModeQueue runs the handler as a deployment consuming the
+	// interruption events that EventBridge sends to an SQS queue
+	NodeTerminationHandlerModeQueue = "queue"
+)
+
+// ClusterNodeTerminationHandler holds the configuration of aws-node-termination-handler
+type ClusterNodeTerminationHandler struct {
+	// Mode of the handler, valid variants are `NodeTerminationHandlerMode` constants, defaults
+	// to `imds`; `queue` requires `iam.withOIDC`, and creates the SQS queue and the EventBridge
+	// rules in the cluster stack, and an IAM service account for the handler
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// Values for `CNI`
+const (
+	// CNIAWSVPC is the VPC CNI, which gives pods IPs of the VPC, installed by EKS
+	CNIAWSVPC = "aws-vpc-cni"
+	// CNICilium is Cilium, in ENI mode, installed with Helm
+	CNICilium = "cilium"
+	// CNICalico is Calico, with a VXLAN overlay network, installed with Helm by the Tigera operator
+	CNICalico = "calico"
+
+	// DefaultPodCIDR is the CIDR of the overlay network of Calico, it doesn't overlap the default
+	// CIDR of the VPC, nor the CIDRs EKS picks for the services
+	DefaultPodCIDR = "10.244.0.0/16"
+)
+
+// ClusterNetwork holds the settings of the pod network
+type ClusterNetwork struct {
+	// CNI is the CNI plugin of the pods, valid variants are `CNI` constants, defaults to `aws-vpc-cni`;
+	// other CNIs are installed with Helm, which must be on the PATH, before the nodes join the cluster
+	// +optional
+	CNI string `json:"cni,omitempty"`
+	// ChartVersion is the version of the Helm chart of the CNI, overriding the version that eksctl
+	// installs by default; the chart must accept the values of the default version
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// PodCIDR is the CIDR of the overlay network of the pods with `calico`, it must not overlap
+	// `vpc.cidr`, defaults to `10.244.0.0/16`
+	// +optional
+	PodCIDR string `json:"podCIDR,omitempty"`
+}
+
+// Values for `GitProvider`
+const (
+	// GitProviderGitHub creates the repository and its deploy key on GitHub, with the token of GITHUB_TOKEN
+	GitProviderGitHub = "github"
+	// GitProviderGitLab creates the repository and its deploy key on GitLab, with the token of GITLAB_TOKEN
+	GitProviderGitLab = "gitlab"
+	// GitProviderGit uses an existing repository of any Git server over SSH
+	GitProviderGit = "git"
+)
+
+// GitOps holds the settings of the GitOps tooling of the cluster
+type GitOps struct {
+	// Flux holds the settings Flux v2 is bootstrapped with
+	// +optional
+	Flux *Flux `json:"flux,omitempty"`
+}
+
+// Flux holds the settings of `flux bootstrap`, which installs Flux v2 in the cluster and commits its
+// manifests to the repository the cluster is synced from
+type Flux struct {
+	// GitProvider is the provider of the repository, valid variants are `GitProvider` constants
+	GitProvider string `json:"gitProvider"`
+	// Repository is the name of the repository for github and gitlab, or its SSH URL for git,
+	// e.g. `ssh://git@example.com/org/repo`
+	Repository string `json:"repository"`
+	// Owner is the user or organization owning the repository, for github and gitlab
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Personal is true if the owner of the repository is a user rather than an organization
+	// +optional
+	Personal *bool `json:"personal,omitempty"`
+	// Hostname is the hostname of self-hosted GitHub and GitLab servers
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// Branch is the branch of the repository, defaults to `main`
+	// +optional
+	Branch string `json:"branch,omitempty"`
+	// Path is the directory of the repository the cluster is synced from, defaults to `clusters/<cluster name>`
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Namespace is the namespace Flux is installed in, defaults to `flux-system`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// PrivateKeyFile is the path of the SSH private key Flux uses as the deploy key of git repositories;
+	// Flux generates one when it's not set, which must then be given access to the repository
+	// +optional
+	PrivateKeyFile string `json:"privateKeyFile,omitempty"`
+	// Flags are extra flags of `flux bootstrap`, without the leading dashes, e.g.
+	// `components-extra: image-reflector-controller,image-automation-controller`
+	// +optional
+	Flags map[string]string `json:"flags,omitempty"`
+}
+
+// Outpost holds the configuration of a local cluster on AWS Outposts
+type Outpost struct {
+	// ControlPlaneOutpostARN is the ARN of the Outpost the control plane runs on
+	ControlPlaneOutpostARN string `json:"controlPlaneOutpostARN"`
+	// ControlPlaneInstanceType is the instance type of the control plane instances,
+	// which must be available on the Outpost, defaults to `m5.large`
+	// +optional
+	ControlPlaneInstanceType string `json:"controlPlaneInstanceType,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ClusterConfigList is a list of ClusterConfigs
@@ -605,6 +1180,22 @@ type NodeGroup struct {
 	InstancesDistribution *NodeGroupInstancesDistribution `json:"instancesDistribution,omitempty"`
 	// +optional
 	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+	// Subnets are the IDs of existing subnets of the cluster VPC to launch the nodes in,
+	// instead of the subnets of availabilityZones. A single subnet makes a single-AZ
+	// nodegroup, e.g. for workloads bound to EBS volumes
+	// +since=0.19.0
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+	// LocalZones are the Local Zones or Wavelength Zones of vpc.localZoneSubnets the
+	// nodes run in, instead of availabilityZones
+	// +since=0.19.0
+	// +optional
+	LocalZones []string `json:"localZones,omitempty"`
+	// OutpostARN is the ARN of the Outpost the nodes run on, subnets must be
+	// subnets of the Outpost; it defaults to the Outpost of local clusters
+	// +since=0.19.0
+	// +optional
+	OutpostARN string `json:"outpostARN,omitempty"`
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
 	// +optional
@@ -635,9 +1226,26 @@ type NodeGroup struct {
 	VolumeKmsKeyID *string `json:"volumeKmsKeyID,omitempty"`
 	// +optional
 	VolumeIOPS *int `json:"volumeIOPS"`
+	// VolumeThroughput is the throughput of gp3 volumes, in MiB/s
+	// +since=0.19.0
+	// +optional
+	VolumeThroughput *int `json:"volumeThroughput,omitempty"`
 
+	// AdditionalVolumes are EBS volumes attached to each node, in addition to the root volume
+	// +since=0.19.0
 	// +optional
-	MaxPodsPerNode int `json:"maxPodsPerNode,omitempty"`
+	AdditionalVolumes []*NodeGroupVolume `json:"additionalVolumes,omitempty"`
+
+	// InstanceStore formats the NVMe instance store volumes of the nodes and mounts them
+	// before kubelet starts, on instance types that have such volumes (e.g. i3)
+	// +since=0.19.0
+	// +optional
+	InstanceStore *NodeGroupInstanceStore `json:"instanceStore,omitempty"`
+
+	// MaxPodsPerNode is the maximum number of pods per node, or auto to compute it from the
+	// network interfaces of the instance type and prefix delegation
+	// +optional
+	MaxPodsPerNode PodsPerNode `json:"maxPodsPerNode,omitempty"`
 
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
@@ -671,6 +1279,100 @@ type NodeGroup struct {
 
 	// +optional
 	KubeletExtraConfig *InlineDocument `json:"kubeletExtraConfig,omitempty"`
+
+	// +since=0.19.0
+	// +optional
+	MemoryConfig *NodeGroupMemoryConfig `json:"memoryConfig,omitempty"`
+
+	// OverrideSysctls are kernel parameters set on each node, namespaced ones
+	// are also allowed as unsafe sysctls in pods
+	// +since=0.19.0
+	// +optional
+	OverrideSysctls map[string]string `json:"overrideSysctls,omitempty"`
+
+	// KernelModules are loaded on each node at boot
+	// +since=0.19.0
+	// +optional
+	KernelModules []string `json:"kernelModules,omitempty"`
+
+	// ContainerRuntime is the container runtime used by kubelet, either
+	// `docker` (default) or `containerd`
+	// +since=0.19.0
+	// +optional
+	ContainerRuntime *string `json:"containerRuntime,omitempty"`
+
+	// ContainerdConfig overrides the configuration of containerd when it's
+	// the container runtime
+	// +since=0.19.0
+	// +optional
+	ContainerdConfig *NodeGroupContainerdConfig `json:"containerdConfig,omitempty"`
+
+	// CloudFormationParameters exposes the size and instance type of the nodegroup
+	// as parameters of its CloudFormation template when set, the values of the map
+	// override the ones from the config, e.g. `MaxSize: "10"`
+	// +since=0.19.0
+	// +optional
+	CloudFormationParameters map[string]string `json:"cloudFormationParameters,omitempty"`
+
+	// SuspendProcesses are the processes of the Auto Scaling group of the nodegroup that are
+	// suspended once it's created, e.g. `AZRebalance` to keep it from terminating instances
+	// without draining their nodes
+	// +since=0.19.0
+	// +optional
+	SuspendProcesses []string `json:"suspendProcesses,omitempty"`
+
+	// ZonalStorageClass creates a StorageClass named after the availability zone of the
+	// nodegroup, e.g. gp2-us-west-2a, whose volumes are provisioned in that zone; the
+	// nodegroup must be in a single availability zone
+	// +since=0.19.0
+	// +optional
+	ZonalStorageClass *bool `json:"zonalStorageClass,omitempty"`
+
+	// DisableIMDSv1 requires the nodes to use session tokens to access the
+	// instance metadata service (IMDSv2), it defaults to `metadata.disableIMDSv1`
+	// +since=0.19.0
+	// +optional
+	DisableIMDSv1 *bool `json:"disableIMDSv1,omitempty"`
+
+	// NetworkInterfaces configures the network interfaces attached to the instances, e.g. one
+	// per network card or ENA Express on network-intensive instance types
+	// +since=0.19.0
+	// +optional
+	NetworkInterfaces *NodeGroupNetworkInterfaces `json:"networkInterfaces,omitempty"`
+
+	// Placement puts the instances in a placement group
+	// +since=0.19.0
+	// +optional
+	Placement *NodeGroupPlacement `json:"placement,omitempty"`
+
+	// Tenancy of the instances, valid variants are `Tenancy` constants
+	// +since=0.19.0
+	// +optional
+	Tenancy string `json:"tenancy,omitempty"`
+
+	// EFAEnabled attaches Elastic Fabric Adapters to the instances, allows EFA traffic between
+	// them, installs the EFA software on them and deploys the EFA device plugin
+	// +since=0.19.0
+	// +optional
+	EFAEnabled *bool `json:"efaEnabled,omitempty"`
+
+	// CapacityReservation runs the instances in On-Demand Capacity Reservations or Capacity Blocks
+	// +since=0.19.0
+	// +optional
+	CapacityReservation *NodeGroupCapacityReservation `json:"capacityReservation,omitempty"`
+
+	// WarmPool keeps pre-initialized instances ready to join the nodegroup when it scales out,
+	// nodes only bootstrap once their instance leaves the warm pool
+	// +since=0.19.0
+	// +optional
+	WarmPool *NodeGroupWarmPool `json:"warmPool,omitempty"`
+
+	// WaitForNodes makes eksctl wait for the nodes to join the cluster and become ready once
+	// the nodegroup is created, `eksctl utils wait-nodes` waits for them when it's disabled;
+	// defaults to `true`
+	// +since=0.19.0
+	// +optional
+	WaitForNodes *bool `json:"waitForNodes,omitempty"`
 }
 
 // ListOptions returns metav1.ListOptions with label selector for the nodegroup
@@ -678,6 +1380,43 @@ func (n *NodeGroup) ListOptions() metav1.ListOptions {
 	return makeListOptions(n.Name)
 }
 
+// MaxPods returns the maximum number of pods per node, set either by maxPodsPerNode or by
+// maxPods in kubeletExtraConfig, or 0 if neither is set or if maxPodsPerNode is auto
+func (n *NodeGroup) MaxPods() int {
+	if n.MaxPodsPerNode > 0 {
+		return int(n.MaxPodsPerNode)
+	}
+	maxPods, _ := n.kubeletExtraConfigMaxPods()
+	return maxPods
+}
+
+// kubeletExtraConfigMaxPods returns maxPods from kubeletExtraConfig, and false if it's not a whole number
+func (n *NodeGroup) kubeletExtraConfigMaxPods() (int, bool) {
+	if n.KubeletExtraConfig == nil {
+		return 0, true
+	}
+	switch maxPods := (*n.KubeletExtraConfig)["maxPods"].(type) {
+	case nil:
+		return 0, true
+	case int:
+		return maxPods, true
+	case int64:
+		return int(maxPods), true
+	case float64:
+		return int(maxPods), maxPods == float64(int(maxPods))
+	default:
+		return 0, false
+	}
+}
+
+// Zone returns the availability zone of a nodegroup that is pinned to a single zone, or an empty string
+func (n *NodeGroup) Zone() string {
+	if len(n.AvailabilityZones) != 1 {
+		return ""
+	}
+	return n.AvailabilityZones[0]
+}
+
 // NameString returns common name string
 func (n *NodeGroup) NameString() string {
 	return n.Name
@@ -708,41 +1447,59 @@ type (
 	}
 	// NodeGroupIAM holds all IAM attributes of a NodeGroup
 	NodeGroupIAM struct {
+		// AttachPolicyARNs are the ARNs of the policies attached to the instance role,
+		// they replace the default policies
 		// +optional
 		AttachPolicyARNs []string `json:"attachPolicyARNs,omitempty"`
+		// InstanceProfileARN is the ARN of an existing instance profile to use for the nodes
 		// +optional
 		InstanceProfileARN string `json:"instanceProfileARN,omitempty"`
+		// InstanceRoleARN is the ARN of an existing role to use for the nodes
 		// +optional
 		InstanceRoleARN string `json:"instanceRoleARN,omitempty"`
+		// InstanceRoleName is the name of the instance role created by eksctl
 		// +optional
 		InstanceRoleName string `json:"instanceRoleName,omitempty"`
+		// InstanceRolePermissionsBoundary is the ARN of the permissions boundary of the instance role
 		// +optional
 		InstanceRolePermissionsBoundary string `json:"instanceRolePermissionsBoundary,omitempty"`
+		// WithAddonPolicies attaches the policies needed by common addons to the instance role
 		// +optional
 		WithAddonPolicies NodeGroupIAMAddonPolicies `json:"withAddonPolicies,omitempty"`
 	}
 	// NodeGroupIAMAddonPolicies holds all IAM addon policies
 	NodeGroupIAMAddonPolicies struct {
+		// ImageBuilder allows full access to ECR
 		// +optional
 		ImageBuilder *bool `json:"imageBuilder"`
+		// AutoScaler allows the cluster-autoscaler to manage the ASGs
 		// +optional
 		AutoScaler *bool `json:"autoScaler"`
+		// ExternalDNS allows external-dns to manage Route 53 records
 		// +optional
 		ExternalDNS *bool `json:"externalDNS"`
+		// CertManager allows cert-manager to solve DNS01 challenges with Route 53
 		// +optional
 		CertManager *bool `json:"certManager"`
+		// AppMesh allows full access to App Mesh
 		// +optional
 		AppMesh *bool `json:"appMesh"`
+		// EBS allows the EBS CSI driver to manage volumes
 		// +optional
 		EBS *bool `json:"ebs"`
+		// FSX allows full access to FSx for Lustre
 		// +optional
 		FSX *bool `json:"fsx"`
+		// EFS allows full access to EFS
 		// +optional
 		EFS *bool `json:"efs"`
+		// ALBIngress allows the ALB ingress controller to manage load balancers
 		// +optional
 		ALBIngress *bool `json:"albIngress"`
+		// XRay allows the X-Ray daemon to send traces
 		// +optional
 		XRay *bool `json:"xRay"`
+		// CloudWatch allows the CloudWatch agent to send metrics and logs
 		// +optional
 		CloudWatch *bool `json:"cloudWatch"`
 	}
@@ -759,6 +1516,12 @@ type (
 		PublicKeyName *string `json:"publicKeyName,omitempty"`
 		// +optional
 		SourceSecurityGroupIDs []string `json:"sourceSecurityGroupIds,omitempty"`
+		// EnableSSM attaches the AmazonSSMManagedInstanceCore policy to the nodes, so that
+		// they can be accessed with SSM Session Manager instead of SSH keys; no key pair is
+		// imported and port 22 isn't opened
+		// +since=0.19.0
+		// +optional
+		EnableSSM *bool `json:"enableSSM,omitempty"`
 	}
 
 	// NodeGroupInstancesDistribution holds the configuration for spot instances
@@ -785,6 +1548,133 @@ type (
 		// +optional
 		Settings *InlineDocument `json:"settings,omitempty"`
 	}
+
+	// NodeGroupMemoryConfig holds host-level memory settings of a NodeGroup,
+	// such as swap and huge pages
+	NodeGroupMemoryConfig struct {
+		// +since=0.19.0
+		// +optional
+		Swap *NodeGroupSwap `json:"swap,omitempty"`
+		// HugePages maps a huge page size (2Mi or 1Gi) to the number of
+		// pages to pre-allocate on each node
+		// +since=0.19.0
+		// +optional
+		HugePages map[string]int `json:"hugePages,omitempty"`
+	}
+
+	// NodeGroupVolume holds the configuration of an additional EBS volume of a NodeGroup
+	NodeGroupVolume struct {
+		// VolumeName is the device name the volume is exposed as, e.g. /dev/xvdb
+		VolumeName *string `json:"volumeName"`
+		VolumeSize *int    `json:"volumeSize"`
+		// +optional
+		VolumeType *string `json:"volumeType,omitempty"`
+		// +optional
+		VolumeIOPS *int `json:"volumeIOPS,omitempty"`
+		// +optional
+		VolumeThroughput *int `json:"volumeThroughput,omitempty"`
+		// +optional
+		VolumeEncrypted *bool `json:"volumeEncrypted,omitempty"`
+		// +optional
+		VolumeKmsKeyID *string `json:"volumeKmsKeyID,omitempty"`
+	}
+
+	// NodeGroupInstanceStore holds the configuration of the instance store volumes of a NodeGroup
+	NodeGroupInstanceStore struct {
+		// RAID0 stripes all the instance store volumes of a node into a single
+		// RAID 0 array, otherwise only the first volume is used
+		// +optional
+		RAID0 *bool `json:"raid0,omitempty"`
+		// MountPath is where the volume is mounted, e.g. /var/lib/docker to store
+		// the images and writable layers of containers
+		MountPath string `json:"mountPath"`
+	}
+
+	// NodeGroupContainerdConfig holds the overrides of the containerd configuration of a NodeGroup
+	NodeGroupContainerdConfig struct {
+		// RegistryMirrors maps a registry host (e.g. docker.io) to the endpoints
+		// of its mirrors, which are tried in order before the registry itself
+		// +optional
+		RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
+		// SandboxImage is the image of the pause container of pods, it defaults
+		// to the one of EKS Distro in the Amazon ECR Public Gallery
+		// +optional
+		SandboxImage string `json:"sandboxImage,omitempty"`
+	}
+
+	// NodeGroupNetworkInterfaces holds the network interface settings of the instances of a NodeGroup
+	NodeGroupNetworkInterfaces struct {
+		// Count of network interfaces attached to each instance, the primary one included,
+		// they're spread over the network cards of the instance type (default 1)
+		// +optional
+		Count *int `json:"count,omitempty"`
+		// NetworkCardIndices sets the network card of each interface instead of spreading
+		// them, the primary interface must be on card 0
+		// +optional
+		NetworkCardIndices []int `json:"networkCardIndices,omitempty"`
+		// EnaExpress enables ENA Express for TCP traffic on all the interfaces
+		// +optional
+		EnaExpress *bool `json:"enaExpress,omitempty"`
+		// EnaExpressUDP also enables ENA Express for UDP traffic, it requires enaExpress
+		// +optional
+		EnaExpressUDP *bool `json:"enaExpressUDP,omitempty"`
+	}
+
+	// NodeGroupPlacement holds the placement group of the instances of a NodeGroup, either an
+	// existing one or one created for the NodeGroup
+	NodeGroupPlacement struct {
+		// GroupName of an existing placement group
+		// +optional
+		GroupName string `json:"groupName,omitempty"`
+		// Strategy of a placement group created for the NodeGroup, valid variants are
+		// `PlacementStrategy` constants
+		// +optional
+		Strategy string `json:"strategy,omitempty"`
+	}
+
+	// NodeGroupCapacityReservation targets a capacity reservation or a resource group of capacity
+	// reservations, or sets the capacity reservation preference of the instances of a NodeGroup
+	NodeGroupCapacityReservation struct {
+		// ID of an On-Demand Capacity Reservation or of a Capacity Block
+		// +optional
+		ID string `json:"id,omitempty"`
+		// ResourceGroupARN of a resource group of capacity reservations
+		// +optional
+		ResourceGroupARN string `json:"resourceGroupARN,omitempty"`
+		// Preference of the instances when no reservation is targeted, valid variants are
+		// `CapacityReservationPreference` constants
+		// +optional
+		Preference string `json:"preference,omitempty"`
+		// CapacityBlock must be enabled when ID is a Capacity Block for ML, so that the instances
+		// are launched in the capacity-block market
+		// +optional
+		CapacityBlock *bool `json:"capacityBlock,omitempty"`
+	}
+
+	// NodeGroupWarmPool holds the warm pool configuration of a NodeGroup
+	NodeGroupWarmPool struct {
+		// MinSize is the minimum number of instances kept in the warm pool
+		// +optional
+		MinSize *int `json:"minSize,omitempty"`
+		// MaxPrepared is the maximum number of instances in the nodegroup and in its warm pool,
+		// defaults to the maxSize of the nodegroup
+		// +optional
+		MaxPrepared *int `json:"maxPrepared,omitempty"`
+		// State of the instances in the warm pool, valid variants are `WarmPoolState` constants,
+		// defaults to `Stopped`
+		// +optional
+		State string `json:"state,omitempty"`
+	}
+
+	// NodeGroupSwap holds the swap file configuration of a NodeGroup
+	NodeGroupSwap struct {
+		// Size of the swap file, as a Kubernetes quantity (e.g. 4Gi)
+		Size string `json:"size"`
+		// Behavior is the kubelet swap behavior, LimitedSwap (default)
+		// or UnlimitedSwap
+		// +optional
+		Behavior string `json:"behavior,omitempty"`
+	}
 )
 
 // ScalingConfig defines the scaling config
@@ -810,19 +1700,96 @@ type ManagedNodeGroup struct {
 	*ScalingConfig `json:",inline"`
 	// +optional
 	VolumeSize *int `json:"volumeSize,omitempty"`
+	// VolumeType, VolumeIOPS, VolumeThroughput, VolumeEncrypted and VolumeKmsKeyID
+	// are set in a launch template that eksctl creates for the nodegroup
+	// +since=0.19.0
+	// +optional
+	VolumeType *string `json:"volumeType,omitempty"`
+	// +since=0.19.0
+	// +optional
+	VolumeIOPS *int `json:"volumeIOPS,omitempty"`
+	// VolumeThroughput is the throughput of gp3 volumes, in MiB/s
+	// +since=0.19.0
+	// +optional
+	VolumeThroughput *int `json:"volumeThroughput,omitempty"`
+	// +since=0.19.0
+	// +optional
+	VolumeEncrypted *bool `json:"volumeEncrypted,omitempty"`
+	// +since=0.19.0
+	// +optional
+	VolumeKmsKeyID *string `json:"volumeKmsKeyID,omitempty"`
 	// +optional
 	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+	// Subnets are the IDs of existing subnets of the cluster VPC to launch the nodes in,
+	// instead of the subnets of availabilityZones
+	// +since=0.19.0
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
 	// +optional
 	SSH *NodeGroupSSH `json:"ssh,omitempty"`
 
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
+	// Taints are applied to the nodes by EKS, in the same `value:Effect` format
+	// as the taints of unmanaged nodegroups
+	// +since=0.19.0
+	// +optional
+	Taints map[string]string `json:"taints,omitempty"`
 	// +optional
 	PrivateNetworking bool `json:"privateNetworking"`
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
 	// +optional
 	IAM *NodeGroupIAM `json:"iam,omitempty"`
+
+	// LaunchTemplate is a launch template created outside of eksctl that the nodes
+	// are launched with, e.g. to use a custom AMI; the SSH key and the volume size
+	// must be set in the launch template instead
+	// +since=0.19.0
+	// +optional
+	LaunchTemplate *LaunchTemplate `json:"launchTemplate,omitempty"`
+
+	// PreBootstrapCommands are run on each node before it joins the cluster,
+	// they're set in a launch template that eksctl creates for the nodegroup
+	// +since=0.19.0
+	// +optional
+	PreBootstrapCommands []string `json:"preBootstrapCommands,omitempty"`
+
+	// DisableIMDSv1 requires the nodes to use session tokens to access the
+	// instance metadata service (IMDSv2), it's set in a launch template that
+	// eksctl creates for the nodegroup; it defaults to `metadata.disableIMDSv1`
+	// +since=0.19.0
+	// +optional
+	DisableIMDSv1 *bool `json:"disableIMDSv1,omitempty"`
+
+	// WaitForNodes makes eksctl wait for the nodes to join the cluster and become ready once
+	// the nodegroup is created, `eksctl utils wait-nodes` waits for them when it's disabled;
+	// defaults to `true`
+	// +since=0.19.0
+	// +optional
+	WaitForNodes *bool `json:"waitForNodes,omitempty"`
+}
+
+// LaunchTemplate references an EC2 launch template
+type LaunchTemplate struct {
+	// ID of the launch template
+	ID string `json:"id"`
+	// Version of the launch template, the default version is used if it's not set
+	// +optional
+	Version *string `json:"version,omitempty"`
+}
+
+// NodeTemplateTags returns the ASG tags telling cluster-autoscaler the labels and taints
+// of the nodes of a nodegroup, taints being in the same `value:Effect` format
+func NodeTemplateTags(labels, taints map[string]string) map[string]string {
+	tags := make(map[string]string, len(labels)+len(taints))
+	for key, value := range labels {
+		tags[NodeTemplateLabelTagPrefix+key] = value
+	}
+	for key, taint := range taints {
+		tags[NodeTemplateTaintTagPrefix+key] = taint
+	}
+	return tags
 }
 
 // ListOptions returns metav1.ListOptions with label selector for the managed nodegroup
@@ -843,6 +1810,17 @@ func (n *ManagedNodeGroup) Size() int {
 	return *n.MinSize
 }
 
+// RequiresLaunchTemplate reports whether the nodegroup has settings that the EKS API
+// doesn't expose, and that are set in a launch template created by eksctl
+func (n *ManagedNodeGroup) RequiresLaunchTemplate() bool {
+	return len(n.PreBootstrapCommands) > 0 || IsEnabled(n.DisableIMDSv1) || n.hasVolumeOptions()
+}
+
+func (n *ManagedNodeGroup) hasVolumeOptions() bool {
+	return IsSetAndNonEmptyString(n.VolumeType) || n.VolumeIOPS != nil || n.VolumeThroughput != nil ||
+		n.VolumeEncrypted != nil || IsSetAndNonEmptyString(n.VolumeKmsKeyID)
+}
+
 // GetAMIFamily returns the AMI family
 func (n *ManagedNodeGroup) GetAMIFamily() string {
 	return n.AMIFamily
@@ -3,12 +3,17 @@ package v1alpha5
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kops/util/pkg/slice"
 )
 
 var (
@@ -23,6 +28,20 @@ var (
 		"updates to some AWS resources.  See: " +
 		"https://docs.aws.amazon.com/eks/latest/userguide/cluster-endpoint.html#private-access " +
 		"for more details")
+
+	// sysctlNameRegexp matches sysctl names using either dots or slashes as separators
+	sysctlNameRegexp = regexp.MustCompile(`^([a-z0-9]([-_a-z0-9]*[a-z0-9])?[\./])*[a-z0-9]([-_a-z0-9]*[a-z0-9])?$`)
+
+	kernelModuleRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	// mountPathRegexp matches absolute paths that are safe to use in the shell scripts of nodes
+	mountPathRegexp = regexp.MustCompile(`^(/[a-zA-Z0-9._-]+)+$`)
+
+	// registryHostRegexp matches the hosts of registries, with an optional port, as containerd names their mirrors
+	registryHostRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?(:[0-9]+)?$`)
+
+	// imageRegexp matches image references that are safe to write to the containerd config
+	imageRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9./:@_-]*$`)
 )
 
 // NOTE: we don't use k8s.io/apimachinery/pkg/util/sets here to keep API package free of dependencies
@@ -96,6 +115,76 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		}
 	}
 
+	if err := validateClusterRegistryMirrors(cfg); err != nil {
+		return err
+	}
+
+	if err := validateBudgetAlarms(cfg.BudgetAlarms); err != nil {
+		return err
+	}
+
+	if err := validateNodeTerminationHandler(cfg); err != nil {
+		return err
+	}
+
+	if err := validateOutpost(cfg); err != nil {
+		return err
+	}
+
+	if err := validateLocalZones(cfg); err != nil {
+		return err
+	}
+
+	if cfg.VPC != nil && cfg.VPC.NAT != nil && IsSetAndNonEmptyString(cfg.VPC.NAT.Gateway) {
+		if err := validateNATGatewayMode(*cfg.VPC.NAT.Gateway); err != nil {
+			return err
+		}
+	}
+
+	if err := validateVPCRoutes(cfg); err != nil {
+		return err
+	}
+
+	if cfg.VPC != nil {
+		if err := validateAssumeRole(cfg.VPC.AssumeRole, "vpc.assumeRole"); err != nil {
+			return err
+		}
+	}
+
+	if err := validateIAMIdentityMappings(cfg.IAMIdentityMappings); err != nil {
+		return err
+	}
+
+	if cfg.IAM != nil && IsSetAndNonEmptyString(cfg.IAM.PermissionsBoundary) {
+		if _, err := arn.Parse(*cfg.IAM.PermissionsBoundary); err != nil {
+			return errors.Wrapf(err, "invalid ARN %q in iam.permissionsBoundary", *cfg.IAM.PermissionsBoundary)
+		}
+	}
+
+	if err := validatePodSecurityGroups(cfg); err != nil {
+		return err
+	}
+
+	if err := validateMaxPodsPerNode(cfg); err != nil {
+		return err
+	}
+
+	if err := validateSwapVersion(cfg); err != nil {
+		return err
+	}
+
+	if err := validateNetwork(cfg); err != nil {
+		return err
+	}
+
+	if cfg.Timeouts != nil {
+		for phase, timeout := range cfg.Timeouts.Phases() {
+			if timeout <= 0 {
+				return fmt.Errorf("timeouts.%s must be a positive duration, got %s", phase, timeout)
+			}
+		}
+	}
+
 	if cfg.VPC != nil && len(cfg.VPC.PublicAccessCIDRs) > 0 {
 		cidrs, err := validateCIDRs(cfg.VPC.PublicAccessCIDRs)
 		if err != nil {
@@ -154,18 +243,8 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		}
 	}
 
-	if ng.VolumeType != nil && *ng.VolumeType == NodeVolumeTypeIO1 {
-		if ng.VolumeIOPS == nil {
-			return fmt.Errorf("%s.volumeIOPS is required for %s volume type", path, NodeVolumeTypeIO1)
-		}
-	} else if ng.VolumeIOPS != nil {
-		return fmt.Errorf("%s.volumeIOPS is only supported for %s volume type", path, NodeVolumeTypeIO1)
-	}
-
-	if ng.VolumeEncrypted == nil || IsDisabled(ng.VolumeEncrypted) {
-		if IsSetAndNonEmptyString(ng.VolumeKmsKeyID) {
-			return fmt.Errorf("%s.VolumeKmsKeyID can not be set without %s.VolumeEncrypted enabled explicitly", path, path)
-		}
+	if err := validateVolumeOptions(path, ng.VolumeType, ng.VolumeIOPS, ng.VolumeThroughput, ng.VolumeEncrypted, ng.VolumeKmsKeyID); err != nil {
+		return err
 	}
 
 	if ng.IAM != nil {
@@ -189,10 +268,21 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		return err
 	}
 
+	if err := validateNodeGroupTaints(ng.Taints, path); err != nil {
+		return err
+	}
+
+	if IsEnabled(ng.ZonalStorageClass) && ng.Zone() == "" {
+		return fmt.Errorf("%s.zonalStorageClass requires %s.availabilityZones to have exactly one zone", path, path)
+	}
+
 	if ng.SSH != nil {
 		if err := validateNodeGroupSSH(ng.SSH); err != nil {
 			return err
 		}
+		if err := validateSSMInstanceRole(ng.SSH, ng.IAM, path); err != nil {
+			return err
+		}
 		if len(ng.SSH.SourceSecurityGroupIDs) > 0 {
 			return fmt.Errorf("%s.sourceSecurityGroupIds is not supported for unmanaged nodegroups", path)
 		}
@@ -216,11 +306,40 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		if ng.OverrideBootstrapCommand != nil {
 			return fieldNotSupported("overrideBootstrapCommand")
 		}
+		if ng.MemoryConfig != nil {
+			return fieldNotSupported("memoryConfig")
+		}
+		if ng.OverrideSysctls != nil {
+			return fieldNotSupported("overrideSysctls")
+		}
+		if ng.KernelModules != nil {
+			return fieldNotSupported("kernelModules")
+		}
+		if ng.InstanceStore != nil {
+			return fieldNotSupported("instanceStore")
+		}
+		if ng.ContainerRuntime != nil {
+			return fieldNotSupported("containerRuntime")
+		}
+		if ng.ContainerdConfig != nil {
+			return fieldNotSupported("containerdConfig")
+		}
 
 	} else if err := validateNodeGroupKubeletExtraConfig(ng.KubeletExtraConfig); err != nil {
 		return err
 	}
 
+	maxPods, ok := ng.kubeletExtraConfigMaxPods()
+	if !ok {
+		return fmt.Errorf("%s.kubeletExtraConfig.maxPods must be a whole number", path)
+	}
+	if maxPods != 0 && ng.MaxPodsPerNode.IsAuto() {
+		return fmt.Errorf("%s.kubeletExtraConfig.maxPods cannot be set when %s.maxPodsPerNode is %s", path, path, ng.MaxPodsPerNode)
+	}
+	if maxPods != 0 && ng.MaxPodsPerNode != 0 && maxPods != int(ng.MaxPodsPerNode) {
+		return fmt.Errorf("%s.kubeletExtraConfig.maxPods (%d) and %s.maxPodsPerNode (%d) must be equal when both are set", path, maxPods, path, ng.MaxPodsPerNode)
+	}
+
 	if ng.AMIFamily == NodeImageFamilyBottlerocket && ng.Bottlerocket != nil {
 		err := checkBottlerocketSettings(ng.Bottlerocket.Settings, path)
 		if err != nil {
@@ -232,6 +351,320 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		return err
 	}
 
+	if err := validateNodeGroupMemoryConfig(ng.MemoryConfig, path); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupKernelConfig(ng, path); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupStorage(ng, path); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupContainerRuntime(ng, path); err != nil {
+		return err
+	}
+
+	if err := ValidateSuspendProcesses(ng.SuspendProcesses, path+".suspendProcesses"); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupSecurityGroups(ng.SecurityGroups, path+".securityGroups"); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupNetworkInterfaces(ng, path+".networkInterfaces"); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupSubnets(ng.Subnets, path+".subnets"); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupPlacement(ng, path); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupEFA(ng, path); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupCapacityReservation(ng, path+".capacityReservation"); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupWarmPool(ng, path+".warmPool"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateNodeGroupEFA(ng *NodeGroup, path string) error {
+	if !IsEnabled(ng.EFAEnabled) {
+		return nil
+	}
+	if ng.AMIFamily != NodeImageFamilyAmazonLinux2 {
+		return fmt.Errorf("%s.efaEnabled is only supported with amiFamily %s", path, NodeImageFamilyAmazonLinux2)
+	}
+	instanceTypes := []string{ng.InstanceType}
+	if HasMixedInstances(ng) {
+		instanceTypes = ng.InstancesDistribution.InstanceTypes
+	}
+	for _, instanceType := range instanceTypes {
+		if networking, _ := getInstanceNetworking(instanceType); !networking.efa {
+			return fmt.Errorf("%s.efaEnabled: instance type %q doesn't support EFA, supported instance types are: %s",
+				path, instanceType, strings.Join(EFAInstanceTypes(), ", "))
+		}
+	}
+	// EFA traffic isn't routable, so the instances must be in the same subnet
+	if len(ng.AvailabilityZones) != 1 && len(ng.Subnets) != 1 {
+		return fmt.Errorf("%s.efaEnabled requires a single availability zone, set availabilityZones or subnets to a single value", path)
+	}
+	return nil
+}
+
+func validateNodeGroupCapacityReservation(ng *NodeGroup, path string) error {
+	cr := ng.CapacityReservation
+	if cr == nil {
+		return nil
+	}
+	switch {
+	case cr.ID == "" && cr.ResourceGroupARN == "" && cr.Preference == "":
+		return fmt.Errorf("%[1]s.id, %[1]s.resourceGroupARN or %[1]s.preference must be set", path)
+	case cr.ID != "" && cr.ResourceGroupARN != "":
+		return fmt.Errorf("%[1]s.id and %[1]s.resourceGroupARN cannot both be set", path)
+	case cr.Preference != "" && (cr.ID != "" || cr.ResourceGroupARN != ""):
+		return fmt.Errorf("%s.preference cannot be set when a capacity reservation is targeted", path)
+	case cr.Preference != "" && !slice.Contains(supportedCapacityReservationPreferences(), cr.Preference):
+		return fmt.Errorf("%s.preference should be one of: %s", path, strings.Join(supportedCapacityReservationPreferences(), ", "))
+	case cr.ID != "" && !strings.HasPrefix(cr.ID, "cr-"):
+		return fmt.Errorf("%s.id must be a capacity reservation ID, got %q", path, cr.ID)
+	}
+	if HasMixedInstances(ng) {
+		return fmt.Errorf("%s cannot be used with instancesDistribution, capacity reservations only apply to On-Demand instances of their instance type", path)
+	}
+	if IsEnabled(cr.CapacityBlock) && cr.ID == "" {
+		return fmt.Errorf("%[1]s.capacityBlock requires %[1]s.id to be the ID of the Capacity Block", path)
+	}
+	return nil
+}
+
+func validateNodeGroupWarmPool(ng *NodeGroup, path string) error {
+	wp := ng.WarmPool
+	if wp == nil {
+		return nil
+	}
+	if wp.State != "" && !slice.Contains(supportedWarmPoolStates(), wp.State) {
+		return fmt.Errorf("%s.state should be one of: %s", path, strings.Join(supportedWarmPoolStates(), ", "))
+	}
+	if wp.MinSize != nil && *wp.MinSize < 0 {
+		return fmt.Errorf("%s.minSize cannot be negative", path)
+	}
+	if wp.MaxPrepared != nil {
+		if *wp.MaxPrepared < 0 {
+			return fmt.Errorf("%s.maxPrepared cannot be negative", path)
+		}
+		if wp.MinSize != nil && *wp.MaxPrepared < *wp.MinSize {
+			return fmt.Errorf("%[1]s.maxPrepared cannot be less than %[1]s.minSize", path)
+		}
+	}
+	if HasMixedInstances(ng) {
+		return fmt.Errorf("%s cannot be used with instancesDistribution, Auto Scaling groups with a mixed instances policy don't support warm pools", path)
+	}
+	// nodes must wait for their instance to leave the warm pool before bootstrapping,
+	// which is only done by the user data of Amazon Linux 2
+	if ng.AMIFamily != NodeImageFamilyAmazonLinux2 {
+		return fmt.Errorf("%s is only supported with amiFamily %s", path, NodeImageFamilyAmazonLinux2)
+	}
+	if ng.OverrideBootstrapCommand != nil {
+		return fmt.Errorf("%s cannot be used with overrideBootstrapCommand", path)
+	}
+	return nil
+}
+
+func validateNodeGroupPlacement(ng *NodeGroup, path string) error {
+	if ng.Tenancy != "" && !slice.Contains(supportedTenancies(), ng.Tenancy) {
+		return fmt.Errorf("%s.tenancy should be one of: %s", path, strings.Join(supportedTenancies(), ", "))
+	}
+	if ng.Tenancy == TenancyHost && HasSpotInstances(ng) {
+		return fmt.Errorf("%s.tenancy %s cannot be used with Spot instances", path, TenancyHost)
+	}
+
+	placement := ng.Placement
+	if placement == nil {
+		return nil
+	}
+	path += ".placement"
+	switch {
+	case placement.GroupName == "" && placement.Strategy == "":
+		return fmt.Errorf("%[1]s.groupName or %[1]s.strategy must be set", path)
+	case placement.GroupName != "" && placement.Strategy != "":
+		return fmt.Errorf("%[1]s.groupName and %[1]s.strategy cannot both be set, the strategy of an existing placement group can't be changed", path)
+	case placement.Strategy != "" && !slice.Contains(supportedPlacementStrategies(), placement.Strategy):
+		return fmt.Errorf("%s.strategy should be one of: %s", path, strings.Join(supportedPlacementStrategies(), ", "))
+	}
+	if placement.Strategy == PlacementStrategyCluster && len(ng.AvailabilityZones) != 1 && len(ng.Subnets) != 1 {
+		return fmt.Errorf("%s.strategy %s requires a single availability zone, set availabilityZones or subnets to a single value", path, PlacementStrategyCluster)
+	}
+	return nil
+}
+
+func validateNodeGroupSubnets(subnets []string, path string) error {
+	seen := map[string]bool{}
+	for i, id := range subnets {
+		if !strings.HasPrefix(id, "subnet-") {
+			return fmt.Errorf("%s[%d] must be a subnet ID, got %q", path, i, id)
+		}
+		if seen[id] {
+			return fmt.Errorf("%s[%d]: subnet %q is listed more than once", path, i, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func validateNodeGroupNetworkInterfaces(ng *NodeGroup, path string) error {
+	ni := ng.NetworkInterfaces
+	if ni == nil {
+		return nil
+	}
+
+	count := ni.NetworkInterfaceCount()
+	if count < 1 {
+		return fmt.Errorf("%s.count must be at least 1", path)
+	}
+	if len(ni.NetworkCardIndices) > 0 {
+		if len(ni.NetworkCardIndices) != count {
+			return fmt.Errorf("%s.networkCardIndices must have one entry per network interface (%d)", path, count)
+		}
+		if ni.NetworkCardIndices[0] != 0 {
+			return fmt.Errorf("%s.networkCardIndices[0] must be 0, the primary network interface is on the first network card", path)
+		}
+	}
+	if IsEnabled(ni.EnaExpressUDP) && !IsEnabled(ni.EnaExpress) {
+		return fmt.Errorf("%s.enaExpressUDP requires %s.enaExpress", path, path)
+	}
+
+	instanceTypes := []string{ng.InstanceType}
+	if HasMixedInstances(ng) {
+		instanceTypes = ng.InstancesDistribution.InstanceTypes
+	}
+	for _, instanceType := range instanceTypes {
+		networking, known := getInstanceNetworking(instanceType)
+		if known && count > networking.maxInterfaces {
+			return fmt.Errorf("%s.count (%d) exceeds the %d network interfaces supported by instance type %q", path, count, networking.maxInterfaces, instanceType)
+		}
+		for i, cardIndex := range NetworkCardIndices(ng) {
+			if cardIndex < 0 || cardIndex >= networking.networkCards {
+				return fmt.Errorf("%s.networkCardIndices[%d] (%d) is not a network card of instance type %q, which has %d", path, i, cardIndex, instanceType, networking.networkCards)
+			}
+		}
+		if IsEnabled(ni.EnaExpress) && !networking.enaExpress {
+			return fmt.Errorf("%s.enaExpress is not supported by instance type %q", path, instanceType)
+		}
+	}
+
+	return nil
+}
+
+func validateNodeGroupSecurityGroups(sgs *NodeGroupSGs, path string) error {
+	if sgs == nil {
+		return nil
+	}
+	for i, id := range sgs.AttachIDs {
+		if !strings.HasPrefix(id, "sg-") {
+			return fmt.Errorf("%s.attachIDs[%d] must be a security group ID, got %q", path, i, id)
+		}
+	}
+	if IsDisabled(sgs.WithLocal) && IsDisabled(sgs.WithShared) && len(sgs.AttachIDs) == 0 {
+		return fmt.Errorf("%[1]s.withLocal and %[1]s.withShared cannot both be disabled without setting %[1]s.attachIDs, nodes would not be able to communicate with the control plane", path)
+	}
+	return nil
+}
+
+func validateManagedLaunchTemplate(ng *ManagedNodeGroup, path string) error {
+	if ng.LaunchTemplate.ID == "" {
+		return fmt.Errorf("%s.launchTemplate.id must be set", path)
+	}
+	errSetInLaunchTemplate := func(field string) error {
+		return fmt.Errorf("%s.%s cannot be set with %s.launchTemplate, it must be set in the launch template", path, field, path)
+	}
+	if len(ng.PreBootstrapCommands) > 0 {
+		return errSetInLaunchTemplate("preBootstrapCommands")
+	}
+	if ng.VolumeSize != nil && *ng.VolumeSize > 0 {
+		return errSetInLaunchTemplate("volumeSize")
+	}
+	if ng.SSH != nil && IsEnabled(ng.SSH.Allow) {
+		return errSetInLaunchTemplate("ssh")
+	}
+	if IsEnabled(ng.DisableIMDSv1) {
+		return errSetInLaunchTemplate("disableIMDSv1")
+	}
+	for _, option := range []struct {
+		field string
+		isSet bool
+	}{
+		{"volumeType", IsSetAndNonEmptyString(ng.VolumeType)},
+		{"volumeIOPS", ng.VolumeIOPS != nil},
+		{"volumeThroughput", ng.VolumeThroughput != nil},
+		{"volumeEncrypted", ng.VolumeEncrypted != nil},
+		{"volumeKmsKeyID", IsSetAndNonEmptyString(ng.VolumeKmsKeyID)},
+	} {
+		if option.isSet {
+			return errSetInLaunchTemplate(option.field)
+		}
+	}
+	return nil
+}
+
+// validateVolumeOptions checks the options of the root volume of nodegroups, an unset
+// volume type is the default gp2
+func validateVolumeOptions(path string, volumeType *string, iops, throughput *int, encrypted *bool, kmsKeyID *string) error {
+	if IsSetAndNonEmptyString(volumeType) {
+		supported := false
+		for _, t := range SupportedNodeVolumeTypes() {
+			if *volumeType == t {
+				supported = true
+			}
+		}
+		if !supported {
+			return fmt.Errorf("%s.volumeType must be one of: %s", path, strings.Join(SupportedNodeVolumeTypes(), ", "))
+		}
+	}
+
+	switch {
+	case volumeType != nil && (*volumeType == NodeVolumeTypeIO1 || *volumeType == NodeVolumeTypeIO2):
+		if iops == nil {
+			return fmt.Errorf("%s.volumeIOPS is required for %s volume type", path, *volumeType)
+		}
+	case volumeType != nil && *volumeType == NodeVolumeTypeGP3:
+		if iops != nil && (*iops < 3000 || *iops > 16000) {
+			return fmt.Errorf("%s.volumeIOPS must be between 3000 and 16000 for %s volume type", path, NodeVolumeTypeGP3)
+		}
+	default:
+		if iops != nil {
+			return fmt.Errorf("%s.volumeIOPS is only supported for %s, %s and %s volume types", path, NodeVolumeTypeIO1, NodeVolumeTypeIO2, NodeVolumeTypeGP3)
+		}
+	}
+
+	if throughput != nil {
+		if volumeType == nil || *volumeType != NodeVolumeTypeGP3 {
+			return fmt.Errorf("%s.volumeThroughput is only supported for %s volume type", path, NodeVolumeTypeGP3)
+		}
+		if *throughput < 125 || *throughput > 1000 {
+			return fmt.Errorf("%s.volumeThroughput must be between 125 and 1000", path)
+		}
+	}
+
+	if encrypted == nil || IsDisabled(encrypted) {
+		if IsSetAndNonEmptyString(kmsKeyID) {
+			return fmt.Errorf("%s.VolumeKmsKeyID can not be set without %s.VolumeEncrypted enabled explicitly", path, path)
+		}
+	}
 	return nil
 }
 
@@ -290,7 +723,9 @@ func ValidateNodeGroupLabels(labels map[string]string) error {
 					"failure-domain.beta.kubernetes.io/zone",
 					"failure-domain.beta.kubernetes.io/region",
 					"failure-domain.kubernetes.io/zone",
-					"failure-domain.kubernetes.io/region":
+					"failure-domain.kubernetes.io/region",
+					"topology.kubernetes.io/zone",
+					"topology.kubernetes.io/region":
 				default:
 					unknownKubernetesLabels = append(unknownKubernetesLabels, l)
 				}
@@ -304,6 +739,128 @@ func ValidateNodeGroupLabels(labels map[string]string) error {
 	return nil
 }
 
+func validateAssumeRole(role *AssumeRole, path string) error {
+	if role == nil {
+		return nil
+	}
+	if role.RoleARN == "" {
+		return fmt.Errorf("%s.roleARN must be set", path)
+	}
+	parsed, err := arn.Parse(role.RoleARN)
+	if err != nil {
+		return errors.Wrapf(err, "invalid ARN %q in %s.roleARN", role.RoleARN, path)
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		return fmt.Errorf("%s.roleARN must be the ARN of an IAM role, got %q", path, role.RoleARN)
+	}
+	return nil
+}
+
+func validateIAMIdentityMappings(mappings []*IAMIdentityMapping) error {
+	for i, m := range mappings {
+		path := fmt.Sprintf("iamIdentityMappings[%d]", i)
+		if m.ARN == "" {
+			return fmt.Errorf("%s.arn must be set", path)
+		}
+		parsed, err := arn.Parse(m.ARN)
+		if err != nil {
+			return errors.Wrapf(err, "invalid ARN %q in %s.arn", m.ARN, path)
+		}
+		if parsed.Service != "iam" || !(strings.HasPrefix(parsed.Resource, "role/") || strings.HasPrefix(parsed.Resource, "user/")) {
+			return fmt.Errorf("%s.arn must be the ARN of an IAM role or user, got %q", path, m.ARN)
+		}
+		if m.Username == "" && len(m.Groups) == 0 {
+			return fmt.Errorf("%s.username or %s.groups must be set", path, path)
+		}
+	}
+	return nil
+}
+
+func validateNATGatewayMode(mode string) error {
+	for _, supported := range SupportedNATGatewayModes() {
+		if mode == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a valid vpc.nat.gateway mode, valid options: %s", mode, strings.Join(SupportedNATGatewayModes(), ", "))
+}
+
+func validateBudgetAlarms(alarms *ClusterBudgetAlarms) error {
+	if alarms == nil {
+		return nil
+	}
+	if alarms.SNSTopicARN != "" {
+		if _, err := arn.Parse(alarms.SNSTopicARN); err != nil {
+			return errors.Wrapf(err, "invalid budgetAlarms.snsTopicARN %q", alarms.SNSTopicARN)
+		}
+	}
+	if alarms.NATGatewayGigabytesPerDay != nil && *alarms.NATGatewayGigabytesPerDay <= 0 {
+		return fmt.Errorf("budgetAlarms.natGatewayGigabytesPerDay must be positive, got %d", *alarms.NATGatewayGigabytesPerDay)
+	}
+	if alarms.InterAZTransferDollarsPerMonth != nil && *alarms.InterAZTransferDollarsPerMonth <= 0 {
+		return fmt.Errorf("budgetAlarms.interAZTransferDollarsPerMonth must be positive, got %d", *alarms.InterAZTransferDollarsPerMonth)
+	}
+	return nil
+}
+
+func validateNodeTerminationHandler(cfg *ClusterConfig) error {
+	nth := cfg.NodeTerminationHandler
+	if nth == nil {
+		return nil
+	}
+	supportedModes := []string{NodeTerminationHandlerModeIMDS, NodeTerminationHandlerModeQueue}
+	if nth.Mode != "" && !slice.Contains(supportedModes, nth.Mode) {
+		return fmt.Errorf("nodeTerminationHandler.mode should be one of: %s", strings.Join(supportedModes, ", "))
+	}
+	if !cfg.HasNodeTerminationHandlerQueue() {
+		return nil
+	}
+	if cfg.IAM == nil || !IsEnabled(cfg.IAM.WithOIDC) {
+		return fmt.Errorf("nodeTerminationHandler.mode %s requires iam.withOIDC to be enabled, the handler using an IAM service account", NodeTerminationHandlerModeQueue)
+	}
+	if queueName := cfg.NodeTerminationHandlerQueueName(); len(queueName) > maxQueueNameLength {
+		maxNameLength := maxQueueNameLength - (len(queueName) - len(cfg.Metadata.Name))
+		return fmt.Errorf("nodeTerminationHandler.mode %s cannot be used with a cluster name longer than %d characters, as it's part of the name of the queue",
+			NodeTerminationHandlerModeQueue, maxNameLength)
+	}
+	return nil
+}
+
+// taintEffects are the effects that kubelet accepts for taints
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// ParseTaint splits the value of a taint of a nodegroup, in the `value:Effect`
+// format used by kubelet, into the value, which can be empty, and the effect
+func ParseTaint(taint string) (value, effect string, err error) {
+	i := strings.LastIndex(taint, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("expected the format value:Effect, got %q", taint)
+	}
+	value, effect = taint[:i], taint[i+1:]
+	for _, e := range taintEffects {
+		if effect == e {
+			return value, effect, nil
+		}
+	}
+	return "", "", fmt.Errorf("effect of %q must be one of %s", taint, strings.Join(taintEffects, ", "))
+}
+
+func validateNodeGroupTaints(taints map[string]string, path string) error {
+	for key, taint := range taints {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("%s.taints: key %q is invalid - %v", path, key, errs)
+		}
+		value, _, err := ParseTaint(taint)
+		if err != nil {
+			return fmt.Errorf("%s.taints: taint %q is invalid: %v", path, key, err)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("%s.taints: taint %q has invalid value %q - %v", path, key, value, errs)
+		}
+	}
+	return nil
+}
+
 func validateNodeGroupIAM(iam *NodeGroupIAM, value, fieldName, path string) error {
 	if value != "" {
 		fmtFieldConflictErr := func(conflictingField string) error {
@@ -377,6 +934,41 @@ func ValidateManagedNodeGroup(ng *ManagedNodeGroup, index int) error {
 		}
 	}
 
+	if ng.SSH != nil {
+		if err := validateNodeGroupSSH(ng.SSH); err != nil {
+			return err
+		}
+		if err := validateSSMInstanceRole(ng.SSH, ng.IAM, path); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateNodeGroupLabels(ng.Labels); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupTaints(ng.Taints, path); err != nil {
+		return err
+	}
+
+	if err := validateNodeGroupSubnets(ng.Subnets, path+".subnets"); err != nil {
+		return err
+	}
+
+	if ng.LaunchTemplate != nil {
+		if err := validateManagedLaunchTemplate(ng, path); err != nil {
+			return err
+		}
+	} else {
+		if ng.SSH != nil && len(ng.SSH.SourceSecurityGroupIDs) > 0 && ng.RequiresLaunchTemplate() {
+			return fmt.Errorf("%s.ssh.sourceSecurityGroupIds cannot be used with preBootstrapCommands, disableIMDSv1 or "+
+				"volume options other than volumeSize, as they're set in a launch template", path)
+		}
+		if err := validateVolumeOptions(path, ng.VolumeType, ng.VolumeIOPS, ng.VolumeThroughput, ng.VolumeEncrypted, ng.VolumeKmsKeyID); err != nil {
+			return err
+		}
+	}
+
 	// TODO fix error messages to not use CLI flags
 	if ng.MinSize == nil {
 		if ng.DesiredCapacity == nil {
@@ -465,6 +1057,22 @@ func validateNodeGroupSSH(SSH *NodeGroupSSH) error {
 	if numSSHFlagsEnabled > 1 {
 		return errors.New("only one SSH public key can be specified per node-group")
 	}
+
+	if IsEnabled(SSH.EnableSSM) && IsEnabled(SSH.Allow) {
+		return errors.New("ssh.enableSSM cannot be used with SSH access, as nodes are accessed through SSM instead of an SSH key")
+	}
+	return nil
+}
+
+// validateSSMInstanceRole checks that the policy of SSM can be attached to the instance role
+func validateSSMInstanceRole(SSH *NodeGroupSSH, iam *NodeGroupIAM, path string) error {
+	if !IsEnabled(SSH.EnableSSM) || iam == nil {
+		return nil
+	}
+	if iam.InstanceRoleARN != "" || iam.InstanceProfileARN != "" {
+		return fmt.Errorf("%s.ssh.enableSSM requires the instance role to be created by eksctl, "+
+			"attach the AmazonSSMManagedInstanceCore policy to %s.iam.instanceRoleARN instead", path, path)
+	}
 	return nil
 }
 
@@ -501,6 +1109,166 @@ func validateNodeGroupKubeletExtraConfig(kubeletConfig *InlineDocument) error {
 	return nil
 }
 
+func validateNodeGroupMemoryConfig(memoryConfig *NodeGroupMemoryConfig, path string) error {
+	if memoryConfig == nil {
+		return nil
+	}
+
+	if swap := memoryConfig.Swap; swap != nil {
+		if swap.Size == "" {
+			return fmt.Errorf("%s.memoryConfig.swap.size must be set", path)
+		}
+		size, err := resource.ParseQuantity(swap.Size)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s.memoryConfig.swap.size %q", path, swap.Size)
+		}
+		if size.Value() < 1<<20 {
+			return fmt.Errorf("%s.memoryConfig.swap.size must be at least 1Mi", path)
+		}
+		if swap.Behavior != "" && !slice.Contains(supportedSwapBehaviors(), swap.Behavior) {
+			return fmt.Errorf("%s.memoryConfig.swap.behavior should be one of: %s", path, strings.Join(supportedSwapBehaviors(), ", "))
+		}
+	}
+
+	for pageSize, count := range memoryConfig.HugePages {
+		if !slice.Contains(supportedHugePageSizes(), pageSize) {
+			return fmt.Errorf("huge page size %q (%s.memoryConfig.hugePages) should be one of: %s", pageSize, path, strings.Join(supportedHugePageSizes(), ", "))
+		}
+		if count < 0 {
+			return fmt.Errorf("%s.memoryConfig.hugePages[%s] should be 0 or more", path, pageSize)
+		}
+	}
+	return nil
+}
+
+// validateSwapVersion checks that swap is only set up on nodes whose kubelet supports it; versions that
+// aren't known yet, such as auto, are checked when the kubelet config of the nodes is generated
+func validateSwapVersion(cfg *ClusterConfig) error {
+	version, older := isOlderVersion(cfg.Metadata, SwapMinVersion)
+	if !older {
+		return nil
+	}
+	for i, ng := range cfg.NodeGroups {
+		if ng.MemoryConfig != nil && ng.MemoryConfig.Swap != nil {
+			return fmt.Errorf("nodeGroups[%d].memoryConfig.swap requires Kubernetes %s or newer, the cluster uses %s", i, SwapMinVersion, version)
+		}
+	}
+	return nil
+}
+
+// isOlderVersion returns the Kubernetes version of the cluster, and true if it's older than minVersion;
+// it's false when the version isn't known yet, e.g. when it's inherited from the control plane
+func isOlderVersion(meta *ClusterMeta, minVersion string) (string, bool) {
+	if meta == nil {
+		return "", false
+	}
+	version := meta.Version
+	switch version {
+	case "default":
+		version = DefaultVersion
+	case "latest":
+		version = LatestVersion
+	}
+	clusterVersion, err := semver.ParseTolerant(version)
+	if err != nil {
+		return version, false
+	}
+	return version, clusterVersion.LT(semver.MustParse(minVersion + ".0"))
+}
+
+func validateNodeGroupKernelConfig(ng *NodeGroup, path string) error {
+	for name, value := range ng.OverrideSysctls {
+		if !sysctlNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid sysctl name %q (path=%s.overrideSysctls)", name, path)
+		}
+		if value == "" || strings.ContainsAny(value, "\n") {
+			return fmt.Errorf("invalid value %q for sysctl %q (path=%s.overrideSysctls)", value, name, path)
+		}
+	}
+
+	for i, module := range ng.KernelModules {
+		if !kernelModuleRegexp.MatchString(module) {
+			return fmt.Errorf("invalid kernel module name %q (path=%s.kernelModules[%d])", module, path, i)
+		}
+	}
+	return nil
+}
+
+func validateNodeGroupContainerRuntime(ng *NodeGroup, path string) error {
+	if ng.ContainerRuntime != nil && !slice.Contains(supportedContainerRuntimes(), *ng.ContainerRuntime) {
+		return fmt.Errorf("%s.containerRuntime should be one of: %s", path, strings.Join(supportedContainerRuntimes(), ", "))
+	}
+
+	config := ng.ContainerdConfig
+	if config == nil {
+		return nil
+	}
+	if ng.ContainerRuntime == nil || *ng.ContainerRuntime != ContainerRuntimeContainerd {
+		return fmt.Errorf("%s.containerdConfig can only be set when %s.containerRuntime is %q", path, path, ContainerRuntimeContainerd)
+	}
+	if err := validateRegistryMirrors(config.RegistryMirrors, path+".containerdConfig.registryMirrors"); err != nil {
+		return err
+	}
+	if config.SandboxImage != "" && !imageRegexp.MatchString(config.SandboxImage) {
+		return fmt.Errorf("invalid image %q (path=%s.containerdConfig.sandboxImage)", config.SandboxImage, path)
+	}
+	return nil
+}
+
+// ValidateSuspendProcesses checks that the processes can be suspended
+func ValidateSuspendProcesses(processes []string, path string) error {
+	for _, process := range processes {
+		if !slice.Contains(SuspendableProcesses(), process) {
+			return fmt.Errorf("%s should only contain: %s, got %q", path, strings.Join(SuspendableProcesses(), ", "), process)
+		}
+	}
+	return nil
+}
+
+func validateRegistryMirrors(mirrors map[string][]string, path string) error {
+	for host, endpoints := range mirrors {
+		if !registryHostRegexp.MatchString(host) {
+			return fmt.Errorf("invalid registry host %q (path=%s)", host, path)
+		}
+		if len(endpoints) == 0 {
+			return fmt.Errorf("%s[%s] must have at least one endpoint", path, host)
+		}
+		for i, endpoint := range endpoints {
+			u, err := url.Parse(endpoint)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.ContainsAny(endpoint, `"\`) {
+				return fmt.Errorf("invalid endpoint %q (path=%s[%s][%d]), it must be an http or https URL", endpoint, path, host, i)
+			}
+		}
+	}
+	return nil
+}
+
+// validateClusterRegistryMirrors checks that all the nodegroups can pull through the registry mirrors
+// of the cluster, as the mirrors are typically required to pull images at all
+func validateClusterRegistryMirrors(cfg *ClusterConfig) error {
+	if len(cfg.RegistryMirrors) == 0 {
+		return nil
+	}
+	if err := validateRegistryMirrors(cfg.RegistryMirrors, "registryMirrors"); err != nil {
+		return err
+	}
+
+	for i, ng := range cfg.NodeGroups {
+		path := fmt.Sprintf("nodeGroups[%d]", i)
+		switch {
+		case ng.AMIFamily == NodeImageFamilyBottlerocket:
+		case IsWindowsImage(ng.AMIFamily):
+			return fmt.Errorf("registryMirrors is not supported for %s nodegroups (path=%s)", ng.AMIFamily, path)
+		case ng.ContainerRuntime == nil || *ng.ContainerRuntime != ContainerRuntimeContainerd:
+			return fmt.Errorf("registryMirrors requires %s.containerRuntime to be %q", path, ContainerRuntimeContainerd)
+		}
+	}
+	if len(cfg.ManagedNodeGroups) > 0 {
+		return errors.New("registryMirrors is not supported with managedNodeGroups, as EKS bootstraps their nodes")
+	}
+	return nil
+}
+
 // IsWindowsImage reports whether the AMI family is for Windows
 func IsWindowsImage(imageFamily string) bool {
 	return imageFamily == NodeImageFamilyWindowsServer2019CoreContainer || imageFamily == NodeImageFamilyWindowsServer2019FullContainer
@@ -587,3 +1355,31 @@ func checkBottlerocketSettings(doc *InlineDocument, path string) error {
 
 	return nil
 }
+
+func validateNodeGroupStorage(ng *NodeGroup, path string) error {
+	deviceNames := map[string]bool{}
+	if IsSetAndNonEmptyString(ng.VolumeName) {
+		deviceNames[*ng.VolumeName] = true
+	}
+	for i, v := range ng.AdditionalVolumes {
+		volumePath := fmt.Sprintf("%s.additionalVolumes[%d]", path, i)
+		if !IsSetAndNonEmptyString(v.VolumeName) {
+			return fmt.Errorf("%s.volumeName must be set", volumePath)
+		}
+		if deviceNames[*v.VolumeName] {
+			return fmt.Errorf("%s.volumeName %q is already in use by another volume", volumePath, *v.VolumeName)
+		}
+		deviceNames[*v.VolumeName] = true
+		if v.VolumeSize == nil || *v.VolumeSize <= 0 {
+			return fmt.Errorf("%s.volumeSize must be greater than 0", volumePath)
+		}
+		if err := validateVolumeOptions(volumePath, v.VolumeType, v.VolumeIOPS, v.VolumeThroughput, v.VolumeEncrypted, v.VolumeKmsKeyID); err != nil {
+			return err
+		}
+	}
+
+	if ng.InstanceStore != nil && !mountPathRegexp.MatchString(ng.InstanceStore.MountPath) {
+		return fmt.Errorf("invalid mount path %q (path=%s.instanceStore.mountPath), it must be an absolute path to a directory", ng.InstanceStore.MountPath, path)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package v1alpha5
 
 import (
+	"encoding/json"
+
 	"github.com/bxcodec/faker"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -388,6 +390,25 @@ var _ = Describe("ClusterConfig validation", func() {
 			checkItDetectsError(SSHConfig)
 		})
 
+		It("fails when SSM and SSH access are enabled", func() {
+			SSHConfig := &NodeGroupSSH{
+				Allow:         Enabled(),
+				PublicKeyName: &testKeyName,
+				EnableSSM:     Enabled(),
+			}
+
+			checkItDetectsError(SSHConfig)
+		})
+
+		It("fails when SSM is enabled with an existing instance role", func() {
+			ng := NewNodeGroup()
+			ng.SSH = &NodeGroupSSH{Allow: Disabled(), EnableSSM: Enabled()}
+			ng.IAM.InstanceRoleARN = "arn:aws:iam::123456789012:role/nodes"
+			ng.IAM.InstanceProfileARN = "arn:aws:iam::123456789012:instance-profile/nodes"
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError(ContainSubstring("nodeGroups[0].ssh.enableSSM requires the instance role to be created by eksctl")))
+		})
+
 		Context("Instances distribution", func() {
 
 			var ng *NodeGroup
@@ -535,6 +556,20 @@ var _ = Describe("ClusterConfig validation", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 
+			It("Requires maxPods to match maxPodsPerNode", func() {
+				ng := NewNodeGroup()
+				ng.MaxPodsPerNode = 20
+				ng.KubeletExtraConfig = &InlineDocument{"maxPods": float64(20)}
+				Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+				Expect(ng.MaxPods()).To(Equal(20))
+
+				ng.KubeletExtraConfig = &InlineDocument{"maxPods": float64(30)}
+				Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("must be equal when both are set")))
+
+				ng.KubeletExtraConfig = &InlineDocument{"maxPods": "30"}
+				Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].kubeletExtraConfig.maxPods must be a whole number"))
+			})
+
 		})
 	})
 
@@ -584,6 +619,63 @@ var _ = Describe("ClusterConfig validation", func() {
 		})
 	})
 
+	Describe("ebs volume types", func() {
+		var (
+			volSize    = 50
+			gp3        = NodeVolumeTypeGP3
+			io2        = NodeVolumeTypeIO2
+			gp2        = NodeVolumeTypeGP2
+			iops       = 4000
+			throughput = 250
+		)
+
+		var ng *NodeGroup
+		BeforeEach(func() {
+			ng = &NodeGroup{
+				Name:       "ng1",
+				VolumeSize: &volSize,
+			}
+		})
+
+		It("Allows setting volumeIOPS and volumeThroughput for gp3 volumes", func() {
+			ng.VolumeType = &gp3
+			ng.VolumeIOPS = &iops
+			ng.VolumeThroughput = &throughput
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("Requires volumeIOPS for io2 volumes", func() {
+			ng.VolumeType = &io2
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].volumeIOPS is required for io2 volume type"))
+		})
+
+		It("Forbids setting volumeThroughput for other volume types", func() {
+			ng.VolumeType = &gp2
+			ng.VolumeThroughput = &throughput
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].volumeThroughput is only supported for gp3 volume type"))
+		})
+
+		It("Forbids unsupported volume types", func() {
+			standard := "standard"
+			ng.VolumeType = &standard
+			Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+		})
+
+		It("Validates the volume options of managed nodegroups", func() {
+			mng := &ManagedNodeGroup{
+				Name:             "mng1",
+				ScalingConfig:    &ScalingConfig{},
+				VolumeType:       &gp2,
+				VolumeThroughput: &throughput,
+			}
+			SetManagedNodeGroupDefaults(mng, &ClusterMeta{})
+			err := ValidateManagedNodeGroup(mng, 0)
+			Expect(err).To(MatchError("managedNodeGroups[0].volumeThroughput is only supported for gp3 volume type"))
+		})
+	})
+
 	Describe("FargateProfile", func() {
 		Describe("Validate", func() {
 			It("returns an error when the profile's name is empty", func() {
@@ -696,7 +788,7 @@ var _ = Describe("ClusterConfig validation", func() {
 			x := 32
 			ngs := []*NodeGroup{
 				{Labels: map[string]string{"label": "label-value"}},
-				{MaxPodsPerNode: x},
+				{MaxPodsPerNode: PodsPerNode(x)},
 				{MinSize: &x},
 			}
 
@@ -706,6 +798,1005 @@ var _ = Describe("ClusterConfig validation", func() {
 			}
 		})
 	})
+
+	Describe("nodeGroups[*].memoryConfig", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.AMIFamily = NodeImageFamilyAmazonLinux2
+		})
+
+		It("accepts swap and huge pages", func() {
+			ng.MemoryConfig = &NodeGroupMemoryConfig{
+				Swap: &NodeGroupSwap{Size: "8Gi", Behavior: SwapBehaviorUnlimited},
+				HugePages: map[string]int{
+					HugePageSize2Mi: 1024,
+					HugePageSize1Gi: 4,
+				},
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects an invalid swap size", func() {
+			ng.MemoryConfig = &NodeGroupMemoryConfig{
+				Swap: &NodeGroupSwap{Size: "lots"},
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("invalid nodeGroups[0].memoryConfig.swap.size")))
+		})
+
+		It("rejects an unknown swap behavior", func() {
+			ng.MemoryConfig = &NodeGroupMemoryConfig{
+				Swap: &NodeGroupSwap{Size: "1Gi", Behavior: "Sometimes"},
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("memoryConfig.swap.behavior should be one of")))
+		})
+
+		It("rejects an unknown huge page size", func() {
+			ng.MemoryConfig = &NodeGroupMemoryConfig{
+				HugePages: map[string]int{"16Gi": 1},
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring(`huge page size "16Gi"`)))
+		})
+
+		It("is not supported for Windows nodegroups", func() {
+			ng.AMIFamily = NodeImageFamilyWindowsServer2019FullContainer
+			ng.MemoryConfig = &NodeGroupMemoryConfig{
+				Swap: &NodeGroupSwap{Size: "1Gi"},
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("memoryConfig is not supported")))
+		})
+
+		It("rejects swap when the kubelet of the cluster's version doesn't support it", func() {
+			cfg := NewClusterConfig()
+			cfg.Metadata.Version = Version1_15
+			ng.Name = "ng-1"
+			ng.MemoryConfig = &NodeGroupMemoryConfig{
+				Swap: &NodeGroupSwap{Size: "1Gi"},
+			}
+			cfg.NodeGroups = []*NodeGroup{ng}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0].memoryConfig.swap requires Kubernetes 1.22 or newer, the cluster uses 1.15"))
+
+			cfg.Metadata.Version = "1.22"
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
+	Describe("nodeGroups[*].overrideSysctls and kernelModules", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.AMIFamily = NodeImageFamilyAmazonLinux2
+		})
+
+		It("accepts sysctls and kernel modules", func() {
+			ng.OverrideSysctls = map[string]string{
+				"net.core.somaxconn":           "1024",
+				"net/ipv4/ip_local_port_range": "1024 65535",
+			}
+			ng.KernelModules = []string{"br_netfilter", "nf_conntrack"}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects an invalid sysctl name", func() {
+			ng.OverrideSysctls = map[string]string{"net.core.somaxconn; reboot": "1"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("invalid sysctl name")))
+		})
+
+		It("rejects an empty sysctl value", func() {
+			ng.OverrideSysctls = map[string]string{"vm.swappiness": ""}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring(`invalid value "" for sysctl "vm.swappiness"`)))
+		})
+
+		It("rejects an invalid kernel module name", func() {
+			ng.KernelModules = []string{"ip_vs", "../evil"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].kernelModules[1]")))
+		})
+
+		It("is not supported for Bottlerocket nodegroups", func() {
+			ng.AMIFamily = NodeImageFamilyBottlerocket
+			ng.KernelModules = []string{"ip_vs"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("kernelModules is not supported")))
+		})
+	})
+
+	Describe("nodeGroups[*].zonalStorageClass", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.ZonalStorageClass = Enabled()
+		})
+
+		It("accepts a nodegroup in a single availability zone", func() {
+			ng.AvailabilityZones = []string{"us-west-2a"}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects a nodegroup in several availability zones", func() {
+			ng.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].zonalStorageClass requires")))
+		})
+	})
+
+	Describe("nodeGroups[*].additionalVolumes and instanceStore", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.AMIFamily = NodeImageFamilyAmazonLinux2
+			ng.VolumeName = strings.Pointer("/dev/xvda")
+		})
+
+		It("accepts additional volumes and an instance store mount path", func() {
+			ng.AdditionalVolumes = []*NodeGroupVolume{
+				{VolumeName: strings.Pointer("/dev/xvdb"), VolumeSize: newInt(100)},
+				{VolumeName: strings.Pointer("/dev/xvdc"), VolumeSize: newInt(500), VolumeType: strings.Pointer(NodeVolumeTypeGP3), VolumeThroughput: newInt(500)},
+			}
+			ng.InstanceStore = &NodeGroupInstanceStore{RAID0: Enabled(), MountPath: "/var/lib/docker"}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects a volume without a size", func() {
+			ng.AdditionalVolumes = []*NodeGroupVolume{{VolumeName: strings.Pointer("/dev/xvdb")}}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].additionalVolumes[0].volumeSize must be greater than 0"))
+		})
+
+		It("rejects a device name that is already in use", func() {
+			ng.AdditionalVolumes = []*NodeGroupVolume{{VolumeName: strings.Pointer("/dev/xvda"), VolumeSize: newInt(100)}}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("already in use")))
+		})
+
+		It("validates the options of additional volumes", func() {
+			ng.AdditionalVolumes = []*NodeGroupVolume{{VolumeName: strings.Pointer("/dev/xvdb"), VolumeSize: newInt(100), VolumeType: strings.Pointer(NodeVolumeTypeIO1)}}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].additionalVolumes[0].volumeIOPS is required for io1 volume type"))
+		})
+
+		It("rejects a relative mount path", func() {
+			ng.InstanceStore = &NodeGroupInstanceStore{MountPath: "var/lib/docker"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].instanceStore.mountPath")))
+		})
+
+		It("is not supported for Bottlerocket nodegroups", func() {
+			ng.AMIFamily = NodeImageFamilyBottlerocket
+			ng.InstanceStore = &NodeGroupInstanceStore{MountPath: "/var/lib/docker"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("instanceStore is not supported")))
+		})
+	})
+
+	Describe("nodeGroups[*].containerRuntime and containerdConfig", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.AMIFamily = NodeImageFamilyAmazonLinux2
+			ng.ContainerRuntime = strings.Pointer(ContainerRuntimeContainerd)
+		})
+
+		It("accepts registry mirrors and a sandbox image", func() {
+			ng.ContainerdConfig = &NodeGroupContainerdConfig{
+				RegistryMirrors: map[string][]string{
+					"docker.io":              {"https://mirror.example.com"},
+					"registry.internal:5000": {"http://10.0.0.10:5000"},
+				},
+				SandboxImage: "registry.internal:5000/eks/pause:3.2",
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects an unknown container runtime", func() {
+			ng.ContainerRuntime = strings.Pointer("cri-o")
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].containerRuntime should be one of: docker, containerd"))
+		})
+
+		It("requires containerd to set containerdConfig", func() {
+			ng.ContainerRuntime = nil
+			ng.ContainerdConfig = &NodeGroupContainerdConfig{SandboxImage: "registry.internal/pause:3.2"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("containerdConfig can only be set")))
+		})
+
+		It("rejects invalid mirror endpoints", func() {
+			ng.ContainerdConfig = &NodeGroupContainerdConfig{
+				RegistryMirrors: map[string][]string{"docker.io": {"mirror.example.com"}},
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].containerdConfig.registryMirrors[docker.io][0]")))
+		})
+
+		It("is not supported for Bottlerocket nodegroups", func() {
+			ng.AMIFamily = NodeImageFamilyBottlerocket
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("containerRuntime is not supported")))
+		})
+	})
+
+	Describe("registryMirrors", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.RegistryMirrors = map[string][]string{
+				"docker.io": {"https://artifactory.example.com/v2/docker-remote"},
+			}
+		})
+
+		It("accepts containerd and Bottlerocket nodegroups", func() {
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			ng.ContainerRuntime = strings.Pointer(ContainerRuntimeContainerd)
+			ng = cfg.NewNodeGroup()
+			ng.Name = "ng-2"
+			ng.AMIFamily = NodeImageFamilyBottlerocket
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects nodegroups that use docker", func() {
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`registryMirrors requires nodeGroups[0].containerRuntime to be "containerd"`))
+		})
+
+		It("rejects managed nodegroups", func() {
+			cfg.ManagedNodeGroups = []*ManagedNodeGroup{{Name: "mng-1"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("not supported with managedNodeGroups")))
+		})
+
+		It("rejects invalid endpoints", func() {
+			cfg.RegistryMirrors["quay.io"] = []string{}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("registryMirrors[quay.io] must have at least one endpoint"))
+		})
+	})
+
+	Describe("budgetAlarms", func() {
+		It("sets the default thresholds", func() {
+			cfg := NewClusterConfig()
+			cfg.BudgetAlarms = &ClusterBudgetAlarms{}
+			SetClusterConfigDefaults(cfg)
+			Expect(*cfg.BudgetAlarms.NATGatewayGigabytesPerDay).To(Equal(DefaultNATGatewayGigabytesPerDay))
+			Expect(*cfg.BudgetAlarms.InterAZTransferDollarsPerMonth).To(Equal(DefaultInterAZTransferDollarsPerMonth))
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects thresholds that aren't positive", func() {
+			cfg := NewClusterConfig()
+			cfg.BudgetAlarms = &ClusterBudgetAlarms{NATGatewayGigabytesPerDay: new(int)}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("budgetAlarms.natGatewayGigabytesPerDay must be positive, got 0"))
+		})
+
+		It("rejects invalid SNS topic ARNs", func() {
+			cfg := NewClusterConfig()
+			cfg.BudgetAlarms = &ClusterBudgetAlarms{SNSTopicARN: "alarms"}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring(`invalid budgetAlarms.snsTopicARN "alarms"`)))
+		})
+	})
+
+	Describe("nodeTerminationHandler", func() {
+		It("defaults to imds mode", func() {
+			cfg := NewClusterConfig()
+			cfg.NodeTerminationHandler = &ClusterNodeTerminationHandler{}
+			SetClusterConfigDefaults(cfg)
+			Expect(cfg.NodeTerminationHandler.Mode).To(Equal(NodeTerminationHandlerModeIMDS))
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects unknown modes", func() {
+			cfg := NewClusterConfig()
+			cfg.NodeTerminationHandler = &ClusterNodeTerminationHandler{Mode: "webhook"}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeTerminationHandler.mode should be one of: imds, queue"))
+		})
+
+		It("requires OIDC in queue mode", func() {
+			cfg := NewClusterConfig()
+			cfg.NodeTerminationHandler = &ClusterNodeTerminationHandler{Mode: NodeTerminationHandlerModeQueue}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("nodeTerminationHandler.mode queue requires iam.withOIDC to be enabled")))
+		})
+
+		It("rejects cluster names too long for the name of the queue", func() {
+			cfg := NewClusterConfig()
+			cfg.Metadata.Name = "a-very-long-cluster-name-a-very-long-cluster-name-that-has-70-chars-ab"
+			cfg.IAM.WithOIDC = Enabled()
+			cfg.NodeTerminationHandler = &ClusterNodeTerminationHandler{Mode: NodeTerminationHandlerModeQueue}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("cannot be used with a cluster name longer than 69 characters")))
+		})
+	})
+
+	Describe("outpost", func() {
+		const outpostARN = "arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"
+
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Outpost = &Outpost{ControlPlaneOutpostARN: outpostARN}
+			cfg.VPC.Subnets = &ClusterSubnets{
+				Private: map[string]Network{"us-west-2a": {ID: "subnet-1"}},
+			}
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			ng.Subnets = []string{"subnet-1"}
+		})
+
+		It("defaults the instance type of the control plane and the Outpost of nodegroups", func() {
+			SetClusterConfigDefaults(cfg)
+			Expect(cfg.Outpost.ControlPlaneInstanceType).To(Equal(DefaultOutpostControlPlaneInstanceType))
+			Expect(cfg.NodeGroups[0].OutpostARN).To(Equal(outpostARN))
+			Expect(PrivateOnly(cfg.VPC.ClusterEndpoints)).To(BeTrue())
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(cfg.HasSufficientSubnets()).To(Succeed())
+		})
+
+		It("rejects invalid Outpost ARNs", func() {
+			cfg.Outpost.ControlPlaneOutpostARN = "arn:aws:ec2:us-west-2:123456789012:instance/i-0123"
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("outpost.controlPlaneOutpostARN must be the ARN of an Outpost")))
+		})
+
+		It("rejects the features local clusters don't support", func() {
+			cfg.ManagedNodeGroups = []*ManagedNodeGroup{{Name: "mng-1"}}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("managedNodeGroups is not supported for local clusters on Outposts"))
+
+			cfg.ManagedNodeGroups = nil
+			cfg.VPC.ClusterEndpoints = &ClusterEndpoints{PublicAccess: Enabled(), PrivateAccess: Enabled()}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("publicAccess is not supported")))
+
+			cfg.VPC.ClusterEndpoints = nil
+			cfg.VPC.Subnets = nil
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("must use existing subnets of the Outpost")))
+		})
+
+		It("checks the nodegroups on Outposts", func() {
+			SetClusterConfigDefaults(cfg)
+			cfg.NodeGroups[0].VolumeType = strings.Pointer(NodeVolumeTypeGP3)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0].volumeType must be gp2 on Outposts"))
+
+			cfg.NodeGroups[0].VolumeType = nil
+			cfg.NodeGroups[0].Subnets = nil
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0].subnets must be set to subnets of the Outpost"))
+		})
+
+		It("allows nodegroups on Outposts in regional clusters", func() {
+			cfg.Outpost = nil
+			cfg.NodeGroups[0].OutpostARN = outpostARN
+			cfg.NodeGroups[0].InstancesDistribution = &NodeGroupInstancesDistribution{InstanceTypes: []string{"m5.large"}}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("instancesDistribution is not supported on Outposts")))
+
+			cfg.NodeGroups[0].InstancesDistribution = nil
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
+	Describe("vpc.nat.gateway", func() {
+		It("accepts the supported modes", func() {
+			for _, mode := range SupportedNATGatewayModes() {
+				cfg := NewClusterConfig()
+				cfg.VPC.NAT = &ClusterNAT{Gateway: strings.Pointer(mode)}
+				Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			}
+		})
+
+		It("rejects unknown modes", func() {
+			cfg := NewClusterConfig()
+			cfg.VPC.NAT = &ClusterNAT{Gateway: strings.Pointer("Double")}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`"Double" is not a valid vpc.nat.gateway mode, valid options: HighlyAvailable, Single, Disable`))
+		})
+	})
+
+	Describe("vpc.transitGatewayID and vpc.extraRoutes", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.VPC.NAT = &ClusterNAT{Gateway: strings.Pointer(ClusterDisableNAT)}
+			cfg.VPC.TransitGatewayID = "tgw-1"
+			cfg.VPC.ExtraRoutes = []VPCRoute{{DestinationCIDR: "10.0.0.0/8"}}
+		})
+
+		It("routes the private subnets to the transit gateway by default", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(cfg.HasCustomEgress()).To(BeTrue())
+			Expect(cfg.PrivateSubnetRoutes()).To(Equal([]VPCRoute{
+				{DestinationCIDR: "0.0.0.0/0", TransitGatewayID: "tgw-1"},
+				{DestinationCIDR: "10.0.0.0/8", TransitGatewayID: "tgw-1"},
+			}))
+		})
+
+		It("requires NAT gateways to be disabled", func() {
+			cfg.VPC.NAT.Gateway = strings.Pointer(ClusterSingleNAT)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("vpc.nat.gateway must be Disable")))
+		})
+
+		It("rejects invalid routes", func() {
+			cfg.VPC.ExtraRoutes[0].DestinationCIDR = "10.0.0.0"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`vpc.extraRoutes[0].destinationCIDR must be a CIDR, got "10.0.0.0"`))
+
+			cfg.VPC.ExtraRoutes[0] = VPCRoute{DestinationCIDR: "0.0.0.0/0", InstanceID: "i-1"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("more than one route to 0.0.0.0/0")))
+
+			cfg.VPC.TransitGatewayID = ""
+			cfg.VPC.ExtraRoutes[0] = VPCRoute{DestinationCIDR: "10.0.0.0/8"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.extraRoutes[0] must set a target, or vpc.transitGatewayID must be set"))
+		})
+
+		It("rejects existing VPCs", func() {
+			cfg.VPC.ID = "vpc-1"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("only supported when eksctl creates the VPC")))
+		})
+	})
+
+	Describe("vpc.assumeRole", func() {
+		It("accepts IAM roles", func() {
+			cfg := NewClusterConfig()
+			cfg.VPC.AssumeRole = &AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/network-reader", ExternalID: "eksctl"}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects ARNs of other resources", func() {
+			cfg := NewClusterConfig()
+			cfg.VPC.AssumeRole = &AssumeRole{}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.assumeRole.roleARN must be set"))
+
+			cfg.VPC.AssumeRole.RoleARN = "arn:aws:iam::123456789012:policy/network-reader"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`vpc.assumeRole.roleARN must be the ARN of an IAM role, got "arn:aws:iam::123456789012:policy/network-reader"`))
+		})
+	})
+
+	Describe("iamIdentityMappings", func() {
+		It("accepts IAM roles and users", func() {
+			cfg := NewClusterConfig()
+			cfg.IAMIdentityMappings = []*IAMIdentityMapping{
+				{ARN: "arn:aws:iam::123456789012:role/admin", Groups: []string{"system:masters"}},
+				{ARN: "arn:aws:iam::123456789012:user/alice", Username: "alice"},
+			}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects invalid mappings", func() {
+			cfg := NewClusterConfig()
+			cfg.IAMIdentityMappings = []*IAMIdentityMapping{{Username: "admin"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("iamIdentityMappings[0].arn must be set"))
+
+			cfg.IAMIdentityMappings[0].ARN = "arn:aws:iam::123456789012:policy/admin"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`iamIdentityMappings[0].arn must be the ARN of an IAM role or user, got "arn:aws:iam::123456789012:policy/admin"`))
+
+			cfg.IAMIdentityMappings[0] = &IAMIdentityMapping{ARN: "arn:aws:iam::123456789012:role/admin"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("iamIdentityMappings[0].username or iamIdentityMappings[0].groups must be set"))
+		})
+	})
+
+	Describe("vpc.localZoneSubnets", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+			cfg.VPC.LocalZoneSubnets = map[string]Network{
+				"us-west-2-lax-1a":        {},
+				"us-west-2-wl1-las-wlz-1": {},
+			}
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			ng.LocalZones = []string{"us-west-2-wl1-las-wlz-1"}
+		})
+
+		It("accepts self-managed nodegroups in local zones", func() {
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(cfg.LocalZones()).To(Equal([]string{"us-west-2-lax-1a", "us-west-2-wl1-las-wlz-1"}))
+			Expect(cfg.HasWavelengthZoneSubnets()).To(BeTrue())
+			Expect(cfg.NodeGroups[0].HasWavelengthZones()).To(BeTrue())
+		})
+
+		It("rejects zones that are not local zones of the VPC", func() {
+			cfg.NodeGroups[0].LocalZones = []string{"us-west-2-den-1a"}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0].localZones: zone us-west-2-den-1a is not in vpc.localZoneSubnets"))
+
+			cfg.NodeGroups[0].LocalZones = nil
+			cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2-lax-1a"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("availabilityZones cannot include local zone us-west-2-lax-1a of vpc.localZoneSubnets"))
+		})
+
+		It("rejects managed nodegroups in local zones", func() {
+			cfg.ManagedNodeGroups = []*ManagedNodeGroup{{Name: "mng-1", AvailabilityZones: []string{"us-west-2-lax-1a"}}}
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("managed nodegroups are not supported in local zone us-west-2-lax-1a")))
+		})
+
+		It("requires the IDs of the subnets of existing VPCs", func() {
+			cfg.VPC.ID = "vpc-1"
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("vpc.localZoneSubnets[us-west-2-lax-1a].id must be set")))
+		})
+	})
+
+	Describe("nodeGroups[*].suspendProcesses", func() {
+		It("accepts the processes that can be suspended", func() {
+			ng := NewNodeGroup()
+			ng.SuspendProcesses = []string{ProcessAZRebalance, ProcessReplaceUnhealthy}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects the processes that scaling relies on", func() {
+			ng := NewNodeGroup()
+			ng.SuspendProcesses = []string{"Terminate"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring(`nodeGroups[0].suspendProcesses should only contain: AZRebalance, ReplaceUnhealthy`)))
+		})
+	})
+
+	Describe("nodeGroups[*].taints", func() {
+		It("accepts taints with and without a value", func() {
+			ng := NewNodeGroup()
+			ng.Taints = map[string]string{"dedicated": "backend:NoSchedule", "gpu": ":NoExecute"}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects taints without an effect", func() {
+			ng := NewNodeGroup()
+			ng.Taints = map[string]string{"dedicated": "backend"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(`nodeGroups[0].taints: taint "dedicated" is invalid: expected the format value:Effect, got "backend"`))
+		})
+
+		It("rejects unknown effects", func() {
+			ng := NewManagedNodeGroup()
+			SetManagedNodeGroupDefaults(ng, &ClusterMeta{})
+			ng.Taints = map[string]string{"dedicated": "backend:NoRun"}
+			Expect(ValidateManagedNodeGroup(ng, 0)).To(MatchError(ContainSubstring("must be one of NoSchedule, PreferNoSchedule, NoExecute")))
+		})
+	})
+
+	Describe("nodeGroups[*].securityGroups", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+		})
+
+		It("accepts opting out of the default security groups when others are attached", func() {
+			ng.SecurityGroups = &NodeGroupSGs{
+				AttachIDs:  []string{"sg-1234"},
+				WithShared: Disabled(),
+				WithLocal:  Disabled(),
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects opting out of all security groups", func() {
+			ng.SecurityGroups = &NodeGroupSGs{
+				WithShared: Disabled(),
+				WithLocal:  Disabled(),
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].securityGroups.withLocal and nodeGroups[0].securityGroups.withShared cannot both be disabled")))
+		})
+
+		It("rejects attached security groups that aren't IDs", func() {
+			ng.SecurityGroups = &NodeGroupSGs{AttachIDs: []string{"my-group"}}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(`nodeGroups[0].securityGroups.attachIDs[0] must be a security group ID, got "my-group"`))
+		})
+	})
+
+	Describe("nodeGroups[*].subnets", func() {
+		It("accepts subnet IDs", func() {
+			ng := NewNodeGroup()
+			ng.Subnets = []string{"subnet-1234"}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects subnets that aren't IDs", func() {
+			ng := NewNodeGroup()
+			ng.Subnets = []string{"subnet-1234", "eu-north-1a"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(`nodeGroups[0].subnets[1] must be a subnet ID, got "eu-north-1a"`))
+		})
+
+		It("rejects duplicate subnets of managed nodegroups", func() {
+			ng := NewManagedNodeGroup()
+			SetManagedNodeGroupDefaults(ng, &ClusterMeta{})
+			ng.Subnets = []string{"subnet-1234", "subnet-1234"}
+			Expect(ValidateManagedNodeGroup(ng, 0)).To(MatchError(`managedNodeGroups[0].subnets[1]: subnet "subnet-1234" is listed more than once`))
+		})
+	})
+
+	Describe("podSecurityGroups", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Metadata.Version = PodSecurityGroupsMinVersion
+			cfg.PodSecurityGroups = Enabled()
+			ng := cfg.NewNodeGroup()
+			ng.InstanceType = "m5.large"
+		})
+
+		It("accepts instance types that support ENI trunking", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects instance types without ENI trunking", func() {
+			cfg.NodeGroups[0].InstanceType = "t3.large"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0].instanceType: instance type t3.large doesn't support ENI trunking, which podSecurityGroups requires"))
+
+			cfg.NodeGroups[0].InstanceType = "mixed"
+			cfg.NodeGroups[0].InstancesDistribution = &NodeGroupInstancesDistribution{InstanceTypes: []string{"m5.large", "m4.large"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("nodeGroups[0].instancesDistribution.instanceTypes[1]: instance type m4.large")))
+		})
+
+		It("rejects Windows nodegroups", func() {
+			cfg.NodeGroups[0].AMIFamily = NodeImageFamilyWindowsServer2019CoreContainer
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0]: podSecurityGroups is not supported by Windows nodegroups"))
+		})
+
+		It("rejects Kubernetes versions older than 1.17", func() {
+			cfg.Metadata.Version = Version1_15
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("podSecurityGroups requires Kubernetes 1.17 or newer, the cluster uses 1.15"))
+
+			cfg.Metadata.Version = "default"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("requires Kubernetes 1.17 or newer")))
+
+			cfg.Metadata.Version = "1.18"
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
+	Describe("maxPodsPerNode and prefixDelegation", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			ng := cfg.NewNodeGroup()
+			ng.InstanceType = "t3.large"
+			ng.MaxPodsPerNode = MaxPodsPerNodeAuto
+		})
+
+		It("decodes and encodes numbers and auto", func() {
+			var ng NodeGroup
+			Expect(json.Unmarshal([]byte(`{"maxPodsPerNode": "auto"}`), &ng)).To(Succeed())
+			Expect(ng.MaxPodsPerNode.IsAuto()).To(BeTrue())
+			Expect(json.Marshal(ng.MaxPodsPerNode)).To(MatchJSON(`"auto"`))
+
+			Expect(json.Unmarshal([]byte(`{"maxPodsPerNode": 20}`), &ng)).To(Succeed())
+			Expect(ng.MaxPods()).To(Equal(20))
+
+			Expect(json.Unmarshal([]byte(`{"maxPodsPerNode": "all"}`), &ng)).To(MatchError(ContainSubstring("must be a number or auto")))
+		})
+
+		It("accepts Nitro instance types with prefix delegation", func() {
+			cfg.PrefixDelegation = Enabled()
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects instance types of the Xen hypervisor with prefix delegation", func() {
+			cfg.PrefixDelegation = Enabled()
+			cfg.NodeGroups[0].InstanceType = "m4.large"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("instance type m4.large doesn't support prefix delegation")))
+		})
+
+		It("rejects auto for Windows nodegroups", func() {
+			cfg.NodeGroups[0].AMIFamily = NodeImageFamilyWindowsServer2019FullContainer
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0].maxPodsPerNode cannot be auto for Windows nodegroups"))
+		})
+
+		It("rejects maxPods in kubeletExtraConfig with auto", func() {
+			ng := cfg.NodeGroups[0]
+			ng.KubeletExtraConfig = &InlineDocument{"maxPods": float64(20)}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("cannot be set when nodeGroups[0].maxPodsPerNode is auto")))
+		})
+	})
+
+	Describe("network.cni", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Network = &ClusterNetwork{CNI: CNICalico}
+			cfg.NewNodeGroup()
+		})
+
+		It("accepts the supported CNI plugins", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(cfg.HasAlternateCNI()).To(BeTrue())
+
+			cfg.Network = nil
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(cfg.CNI()).To(Equal(CNIAWSVPC))
+		})
+
+		It("rejects unknown CNI plugins", func() {
+			cfg.Network.CNI = "flannel"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`"flannel" is not a valid network.cni, valid options: aws-vpc-cni, cilium, calico`))
+		})
+
+		It("rejects features of the VPC CNI", func() {
+			cfg.PrefixDelegation = Enabled()
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("prefixDelegation is not supported with network.cni calico"))
+		})
+
+		It("rejects a pod CIDR overlapping the VPC", func() {
+			Expect(cfg.PodCIDR()).To(Equal(DefaultPodCIDR))
+
+			cfg.Network.PodCIDR = "192.168.128.0/17"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("network.podCIDR 192.168.128.0/17 overlaps vpc.cidr 192.168.0.0/16"))
+
+			cfg.Network.PodCIDR = "10.0.0.0/8"
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects a pod CIDR with other CNI plugins", func() {
+			cfg.Network = &ClusterNetwork{CNI: CNICilium, PodCIDR: "10.0.0.0/8"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("network.podCIDR can only be set with network.cni calico"))
+		})
+
+		It("rejects maxPodsPerNode auto with an overlay network", func() {
+			cfg.NodeGroups[0].MaxPodsPerNode = MaxPodsPerNodeAuto
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("nodeGroups[0].maxPodsPerNode cannot be auto with network.cni calico")))
+		})
+	})
+
+	Describe("gitops.flux", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Metadata.Name = "cluster-1"
+			cfg.GitOps = &GitOps{
+				Flux: &Flux{
+					GitProvider: GitProviderGitHub,
+					Repository:  "gitops",
+					Owner:       "weaveworks",
+				},
+			}
+		})
+
+		It("sets the defaults of Flux", func() {
+			SetFluxDefaults(cfg)
+			Expect(ValidateFlux(cfg)).To(Succeed())
+			Expect(cfg.GitOps.Flux.Branch).To(Equal("main"))
+			Expect(cfg.GitOps.Flux.Path).To(Equal("clusters/cluster-1"))
+			Expect(cfg.GitOps.Flux.Namespace).To(Equal("flux-system"))
+			Expect(cfg.GitOps.Flux.RepositoryURL()).To(Equal("ssh://git@github.com/weaveworks/gitops"))
+
+			cfg.GitOps.Flux.Hostname = "git.example.com"
+			Expect(cfg.GitOps.Flux.RepositoryURL()).To(Equal("ssh://git@git.example.com/weaveworks/gitops"))
+		})
+
+		It("rejects unknown Git providers and missing owners", func() {
+			cfg.GitOps.Flux.GitProvider = "bitbucket"
+			Expect(ValidateFlux(cfg)).To(MatchError(`gitops.flux.gitProvider must be one of github, gitlab, git, got "bitbucket"`))
+
+			cfg.GitOps.Flux.GitProvider = GitProviderGitLab
+			cfg.GitOps.Flux.Owner = ""
+			Expect(ValidateFlux(cfg)).To(MatchError("gitops.flux.owner must be set for gitProvider gitlab"))
+		})
+
+		It("requires SSH URLs for git repositories", func() {
+			cfg.GitOps.Flux = &Flux{GitProvider: GitProviderGit, Repository: "https://example.com/org/gitops"}
+			Expect(ValidateFlux(cfg)).To(MatchError(ContainSubstring("gitops.flux.repository must be an SSH URL")))
+
+			cfg.GitOps.Flux.Repository = "ssh://git@example.com/org/gitops"
+			Expect(ValidateFlux(cfg)).To(Succeed())
+		})
+
+		It("rejects flags set from other fields", func() {
+			cfg.GitOps.Flux.Flags = map[string]string{"--branch": "main"}
+			Expect(ValidateFlux(cfg)).To(MatchError(`gitops.flux.flags cannot set "--branch", use gitops.flux.branch instead`))
+		})
+	})
+
+	Describe("nodeGroups[*].placement", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+		})
+
+		It("accepts a cluster placement group in a single availability zone", func() {
+			ng.AvailabilityZones = []string{"us-west-2a"}
+			ng.Placement = &NodeGroupPlacement{Strategy: "cluster"}
+			ng.Tenancy = "dedicated"
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects a cluster placement group in several availability zones", func() {
+			ng.Placement = &NodeGroupPlacement{Strategy: "cluster"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].placement.strategy cluster requires a single availability zone")))
+		})
+
+		It("rejects setting both a placement group and a strategy", func() {
+			ng.Placement = &NodeGroupPlacement{GroupName: "hpc", Strategy: "spread"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].placement.groupName and nodeGroups[0].placement.strategy cannot both be set")))
+		})
+
+		It("rejects unknown strategies", func() {
+			ng.Placement = &NodeGroupPlacement{Strategy: "random"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].placement.strategy should be one of: cluster, spread, partition"))
+		})
+
+		It("rejects unknown tenancies", func() {
+			ng.Tenancy = "shared"
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].tenancy should be one of: default, dedicated, host"))
+		})
+
+		It("rejects host tenancy with Spot instances", func() {
+			ng.Tenancy = "host"
+			ng.InstanceType = "mixed"
+			ng.InstancesDistribution = &NodeGroupInstancesDistribution{
+				InstanceTypes:                       []string{"m5.large", "m5a.large"},
+				OnDemandPercentageAboveBaseCapacity: newInt(0),
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].tenancy host cannot be used with Spot instances"))
+		})
+	})
+
+	Describe("nodeGroups[*].efaEnabled", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.AMIFamily = NodeImageFamilyAmazonLinux2
+			ng.InstanceType = "p4d.24xlarge"
+			ng.AvailabilityZones = []string{"us-west-2a"}
+			ng.EFAEnabled = Enabled()
+		})
+
+		It("accepts an instance type supporting EFA in a single availability zone", func() {
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects instance types that don't support EFA", func() {
+			ng.InstanceType = "m5.large"
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring(`nodeGroups[0].efaEnabled: instance type "m5.large" doesn't support EFA`)))
+		})
+
+		It("rejects several availability zones", func() {
+			ng.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].efaEnabled requires a single availability zone, set availabilityZones or subnets to a single value"))
+		})
+
+		It("rejects other AMI families", func() {
+			ng.AMIFamily = NodeImageFamilyUbuntu1804
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].efaEnabled is only supported with amiFamily AmazonLinux2"))
+		})
+	})
+
+	Describe("nodeGroups[*].capacityReservation", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+		})
+
+		It("accepts a Capacity Block", func() {
+			ng.CapacityReservation = &NodeGroupCapacityReservation{ID: "cr-0123456789abcdef0", CapacityBlock: Enabled()}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("accepts a resource group of capacity reservations", func() {
+			ng.CapacityReservation = &NodeGroupCapacityReservation{
+				ResourceGroupARN: "arn:aws:resource-groups:us-west-2:123456789012:group/gpu-reservations",
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects targeting both a reservation and a resource group", func() {
+			ng.CapacityReservation = &NodeGroupCapacityReservation{
+				ID:               "cr-0123456789abcdef0",
+				ResourceGroupARN: "arn:aws:resource-groups:us-west-2:123456789012:group/gpu-reservations",
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].capacityReservation.id and nodeGroups[0].capacityReservation.resourceGroupARN cannot both be set"))
+		})
+
+		It("rejects a preference with a targeted reservation", func() {
+			ng.CapacityReservation = &NodeGroupCapacityReservation{ID: "cr-0123456789abcdef0", Preference: "open"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].capacityReservation.preference cannot be set when a capacity reservation is targeted"))
+		})
+
+		It("rejects unknown preferences", func() {
+			ng.CapacityReservation = &NodeGroupCapacityReservation{Preference: "always"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].capacityReservation.preference should be one of: open, none, capacity-reservations-only"))
+		})
+
+		It("rejects a Capacity Block without its ID", func() {
+			ng.CapacityReservation = &NodeGroupCapacityReservation{
+				ResourceGroupARN: "arn:aws:resource-groups:us-west-2:123456789012:group/gpu-reservations",
+				CapacityBlock:    Enabled(),
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].capacityReservation.capacityBlock requires nodeGroups[0].capacityReservation.id to be the ID of the Capacity Block"))
+		})
+
+		It("rejects mixed instances", func() {
+			ng.InstanceType = "mixed"
+			ng.InstancesDistribution = &NodeGroupInstancesDistribution{InstanceTypes: []string{"p4d.24xlarge", "p5.48xlarge"}}
+			ng.CapacityReservation = &NodeGroupCapacityReservation{ID: "cr-0123456789abcdef0"}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].capacityReservation cannot be used with instancesDistribution")))
+		})
+	})
+
+	Describe("nodeGroups[*].warmPool", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.AMIFamily = NodeImageFamilyAmazonLinux2
+			ng.WarmPool = &NodeGroupWarmPool{MinSize: newInt(1), MaxPrepared: newInt(5), State: WarmPoolStateStopped}
+		})
+
+		It("accepts a warm pool of stopped instances", func() {
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects unknown states", func() {
+			ng.WarmPool.State = "Paused"
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].warmPool.state should be one of: Stopped, Running, Hibernated"))
+		})
+
+		It("rejects maxPrepared less than minSize", func() {
+			ng.WarmPool.MaxPrepared = newInt(0)
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].warmPool.maxPrepared cannot be less than nodeGroups[0].warmPool.minSize"))
+		})
+
+		It("rejects mixed instances", func() {
+			ng.InstanceType = "mixed"
+			ng.InstancesDistribution = &NodeGroupInstancesDistribution{InstanceTypes: []string{"m5.large", "m5a.large"}}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("nodeGroups[0].warmPool cannot be used with instancesDistribution")))
+		})
+
+		It("rejects other AMI families", func() {
+			ng.AMIFamily = NodeImageFamilyUbuntu1804
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].warmPool is only supported with amiFamily AmazonLinux2"))
+		})
+	})
+
+	Describe("nodeGroups[*].networkInterfaces", func() {
+		var ng *NodeGroup
+
+		BeforeEach(func() {
+			ng = NewNodeGroup()
+			ng.InstanceType = "c6in.32xlarge"
+		})
+
+		It("accepts interfaces on all the network cards of the instance type", func() {
+			ng.NetworkInterfaces = &NodeGroupNetworkInterfaces{
+				Count:              newInt(3),
+				NetworkCardIndices: []int{0, 1, 1},
+				EnaExpress:         Enabled(),
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects more interfaces than the instance type supports", func() {
+			ng.NetworkInterfaces = &NodeGroupNetworkInterfaces{Count: newInt(17)}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(`nodeGroups[0].networkInterfaces.count (17) exceeds the 16 network interfaces supported by instance type "c6in.32xlarge"`))
+		})
+
+		It("rejects network cards the instance type doesn't have", func() {
+			ng.InstanceType = "m5.large"
+			ng.NetworkInterfaces = &NodeGroupNetworkInterfaces{
+				Count:              newInt(2),
+				NetworkCardIndices: []int{0, 1},
+			}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring(`nodeGroups[0].networkInterfaces.networkCardIndices[1] (1) is not a network card of instance type "m5.large"`)))
+		})
+
+		It("rejects a primary interface that isn't on the first network card", func() {
+			ng.NetworkInterfaces = &NodeGroupNetworkInterfaces{NetworkCardIndices: []int{1}}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("networkCardIndices[0] must be 0")))
+		})
+
+		It("rejects ENA Express on instance types that don't support it", func() {
+			ng.InstanceType = "p4d.24xlarge"
+			ng.NetworkInterfaces = &NodeGroupNetworkInterfaces{EnaExpress: Enabled()}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(`nodeGroups[0].networkInterfaces.enaExpress is not supported by instance type "p4d.24xlarge"`))
+		})
+
+		It("rejects ENA Express for UDP without ENA Express", func() {
+			ng.NetworkInterfaces = &NodeGroupNetworkInterfaces{EnaExpressUDP: Enabled()}
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError(ContainSubstring("enaExpressUDP requires")))
+		})
+	})
 })
 
 func checkItDetectsError(SSHConfig *NodeGroupSSH) {
@@ -33,6 +33,59 @@ type (
 		ClusterEndpoints *ClusterEndpoints `json:"clusterEndpoints,omitempty"`
 		// +optional
 		PublicAccessCIDRs []string `json:"publicAccessCIDRs,omitempty"`
+		// AutoTagSubnetsForELB adds the kubernetes.io/role/elb and
+		// kubernetes.io/role/internal-elb tags to existing subnets that lack them
+		// +since=0.19.0
+		// +optional
+		AutoTagSubnetsForELB *bool `json:"autoTagSubnetsForELB,omitempty"`
+		// LocalZoneSubnets are subnets in Local Zones and Wavelength Zones, keyed by zone,
+		// e.g. us-west-2-lax-1a or us-east-1-wl1-bos-wlz-1; new subnets route to the
+		// internet gateway, or to a carrier gateway in Wavelength Zones, and their CIDR
+		// defaults to unused blocks of the VPC CIDR; only self-managed nodegroups can
+		// run in them, set in their localZones
+		// +since=0.19.0
+		// +optional
+		LocalZoneSubnets map[string]Network `json:"localZoneSubnets,omitempty"`
+		// OwnerAccountID is the account that owns the VPC when its subnets are shared
+		// with the account of the cluster through AWS RAM; it's set by eksctl when
+		// the subnets are imported, and resources of that account are never modified
+		// +since=0.19.0
+		// +optional
+		OwnerAccountID string `json:"ownerAccountID,omitempty"`
+		// TransitGatewayID is the ID of an existing transit gateway, the VPC is
+		// attached to it and the private subnets route 0.0.0.0/0 to it instead of
+		// NAT gateways, e.g. to an egress VPC of a hub-and-spoke network
+		// +since=0.19.0
+		// +optional
+		TransitGatewayID string `json:"transitGatewayID,omitempty"`
+		// ExtraRoutes are added to the route tables of the private subnets, e.g. to
+		// route 0.0.0.0/0 to a NAT instance, or the networks of other VPCs to the
+		// transit gateway
+		// +since=0.19.0
+		// +optional
+		ExtraRoutes []VPCRoute `json:"extraRoutes,omitempty"`
+		// AssumeRole is assumed to look up the existing VPC and subnets, e.g. in
+		// the network account of a multi-account landing zone, while the cluster
+		// is created with the credentials of eksctl
+		// +since=0.19.0
+		// +optional
+		AssumeRole *AssumeRole `json:"assumeRole,omitempty"`
+	}
+	// VPCRoute is a route of the private subnets to an existing target, at most
+	// one of the targets can be set
+	VPCRoute struct {
+		// DestinationCIDR of the route, e.g. 0.0.0.0/0
+		DestinationCIDR string `json:"destinationCIDR"`
+		// TransitGatewayID of a transit gateway the VPC is attached to, it
+		// defaults to vpc.transitGatewayID when no other target is set
+		// +optional
+		TransitGatewayID string `json:"transitGatewayID,omitempty"`
+		// InstanceID of a NAT instance
+		// +optional
+		InstanceID string `json:"instanceID,omitempty"`
+		// NetworkInterfaceID of a network interface, e.g. of a firewall appliance
+		// +optional
+		NetworkInterfaceID string `json:"networkInterfaceID,omitempty"`
 	}
 	// ClusterSubnets holds private and public subnets
 	ClusterSubnets struct {
@@ -154,6 +207,11 @@ func doImportSubnet(subnets map[string]Network, az, subnetID, cidr string) error
 	return nil
 }
 
+// IsSharedVPC returns true if the VPC is owned by another account, which shares its subnets through AWS RAM
+func (c *ClusterConfig) IsSharedVPC() bool {
+	return c.VPC != nil && c.VPC.OwnerAccountID != ""
+}
+
 // HasAnySubnets checks if any subnets were set
 func (c *ClusterConfig) HasAnySubnets() bool {
 	return c.VPC.Subnets != nil && len(c.VPC.Subnets.Private)+len(c.VPC.Subnets.Public) != 0
@@ -162,6 +220,10 @@ func (c *ClusterConfig) HasAnySubnets() bool {
 // HasSufficientPrivateSubnets validates if there is a sufficient
 // number of private subnets available to create a cluster
 func (c *ClusterConfig) HasSufficientPrivateSubnets() bool {
+	if c.IsLocalCluster() {
+		// local clusters run in the single availability zone of their Outpost
+		return len(c.PrivateSubnetIDs()) > 0
+	}
 	return len(c.PrivateSubnetIDs()) >= MinRequiredSubnets
 }
 
@@ -181,6 +243,14 @@ var errInsufficientSubnets = fmt.Errorf(
 // less then MinRequiredSubnets of each, but allowing to have
 // public-only or private-only
 func (c *ClusterConfig) HasSufficientSubnets() error {
+	if c.IsLocalCluster() {
+		// local clusters run in the single availability zone of their Outpost
+		if len(c.PublicSubnetIDs())+len(c.PrivateSubnetIDs()) == 0 {
+			return errInsufficientSubnets
+		}
+		return nil
+	}
+
 	numPublic := len(c.PublicSubnetIDs())
 	if numPublic > 0 && numPublic < MinRequiredSubnets {
 		return errInsufficientSubnets
@@ -0,0 +1,88 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"net"
+)
+
+// DefaultRouteCIDR is the destination of the routes to the internet
+const DefaultRouteCIDR = "0.0.0.0/0"
+
+// PrivateSubnetRoutes returns the routes of the private subnets to existing targets, starting with
+// the default route to vpc.transitGatewayID; routes without a target go to vpc.transitGatewayID
+func (c *ClusterConfig) PrivateSubnetRoutes() []VPCRoute {
+	if c.VPC == nil {
+		return nil
+	}
+	var routes []VPCRoute
+	if c.VPC.TransitGatewayID != "" {
+		routes = append(routes, VPCRoute{
+			DestinationCIDR:  DefaultRouteCIDR,
+			TransitGatewayID: c.VPC.TransitGatewayID,
+		})
+	}
+	for _, route := range c.VPC.ExtraRoutes {
+		if route.TransitGatewayID == "" && route.InstanceID == "" && route.NetworkInterfaceID == "" {
+			route.TransitGatewayID = c.VPC.TransitGatewayID
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// HasCustomEgress returns true if the private subnets route 0.0.0.0/0 to an existing target,
+// so that no NAT gateway is needed
+func (c *ClusterConfig) HasCustomEgress() bool {
+	for _, route := range c.PrivateSubnetRoutes() {
+		if route.DestinationCIDR == DefaultRouteCIDR {
+			return true
+		}
+	}
+	return false
+}
+
+// validateVPCRoutes checks vpc.transitGatewayID and vpc.extraRoutes, which are added to the route
+// tables of the private subnets of the VPC created by eksctl
+func validateVPCRoutes(cfg *ClusterConfig) error {
+	routes := cfg.PrivateSubnetRoutes()
+	if len(routes) == 0 {
+		return nil
+	}
+	if cfg.VPC.ID != "" || cfg.HasAnySubnets() {
+		return fmt.Errorf("vpc.transitGatewayID and vpc.extraRoutes are only supported when eksctl creates the VPC, routes of existing VPCs must be managed by their owner")
+	}
+
+	destinations := map[string]bool{}
+	for i, route := range cfg.VPC.ExtraRoutes {
+		path := fmt.Sprintf("vpc.extraRoutes[%d]", i)
+		if _, _, err := net.ParseCIDR(route.DestinationCIDR); err != nil {
+			return fmt.Errorf("%s.destinationCIDR must be a CIDR, got %q", path, route.DestinationCIDR)
+		}
+		targets := 0
+		for _, target := range []string{route.TransitGatewayID, route.InstanceID, route.NetworkInterfaceID} {
+			if target != "" {
+				targets++
+			}
+		}
+		if targets > 1 {
+			return fmt.Errorf("%s must only set one of transitGatewayID, instanceID and networkInterfaceID", path)
+		}
+		if route.TransitGatewayID != "" && route.TransitGatewayID != cfg.VPC.TransitGatewayID {
+			return fmt.Errorf("%s.transitGatewayID must be vpc.transitGatewayID, the VPC is only attached to that transit gateway", path)
+		}
+	}
+	for i, route := range routes {
+		if route.TransitGatewayID == "" && route.InstanceID == "" && route.NetworkInterfaceID == "" {
+			return fmt.Errorf("vpc.extraRoutes[%d] must set a target, or vpc.transitGatewayID must be set", i)
+		}
+		if destinations[route.DestinationCIDR] {
+			return fmt.Errorf("the private subnets have more than one route to %s, in vpc.transitGatewayID and vpc.extraRoutes", route.DestinationCIDR)
+		}
+		destinations[route.DestinationCIDR] = true
+	}
+
+	if cfg.HasCustomEgress() && cfg.VPC.NAT != nil && IsSetAndNonEmptyString(cfg.VPC.NAT.Gateway) && *cfg.VPC.NAT.Gateway != ClusterDisableNAT {
+		return fmt.Errorf("vpc.nat.gateway must be %s, as the private subnets route %s to vpc.transitGatewayID or vpc.extraRoutes", ClusterDisableNAT, DefaultRouteCIDR)
+	}
+	return nil
+}
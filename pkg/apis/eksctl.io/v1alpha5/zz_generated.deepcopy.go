@@ -21,10 +21,76 @@ limitations under the License.
 package v1alpha5
 
 import (
+	time "time"
+
 	ipnet "github.com/weaveworks/eksctl/pkg/utils/ipnet"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssumeRole) DeepCopyInto(out *AssumeRole) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssumeRole.
+func (in *AssumeRole) DeepCopy() *AssumeRole {
+	if in == nil {
+		return nil
+	}
+	out := new(AssumeRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAssumeRole) DeepCopyInto(out *ClusterAssumeRole) {
+	*out = *in
+	if in.RoleARNs != nil {
+		in, out := &in.RoleARNs, &out.RoleARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAssumeRole.
+func (in *ClusterAssumeRole) DeepCopy() *ClusterAssumeRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAssumeRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBudgetAlarms) DeepCopyInto(out *ClusterBudgetAlarms) {
+	*out = *in
+	if in.NATGatewayGigabytesPerDay != nil {
+		in, out := &in.NATGatewayGigabytesPerDay, &out.NATGatewayGigabytesPerDay
+		*out = new(int)
+		**out = **in
+	}
+	if in.InterAZTransferDollarsPerMonth != nil {
+		in, out := &in.InterAZTransferDollarsPerMonth, &out.InterAZTransferDollarsPerMonth
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBudgetAlarms.
+func (in *ClusterBudgetAlarms) DeepCopy() *ClusterBudgetAlarms {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBudgetAlarms)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCloudWatch) DeepCopyInto(out *ClusterCloudWatch) {
 	*out = *in
@@ -134,6 +200,72 @@ func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
 		*out = new(SecretsEncryption)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(ClusterTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.BudgetAlarms != nil {
+		in, out := &in.BudgetAlarms, &out.BudgetAlarms
+		*out = new(ClusterBudgetAlarms)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeTerminationHandler != nil {
+		in, out := &in.NodeTerminationHandler, &out.NodeTerminationHandler
+		*out = new(ClusterNodeTerminationHandler)
+		**out = **in
+	}
+	if in.Outpost != nil {
+		in, out := &in.Outpost, &out.Outpost
+		*out = new(Outpost)
+		**out = **in
+	}
+	if in.PodSecurityGroups != nil {
+		in, out := &in.PodSecurityGroups, &out.PodSecurityGroups
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrefixDelegation != nil {
+		in, out := &in.PrefixDelegation, &out.PrefixDelegation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(ClusterNetwork)
+		**out = **in
+	}
+	if in.GitOps != nil {
+		in, out := &in.GitOps, &out.GitOps
+		*out = new(GitOps)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IAMIdentityMappings != nil {
+		in, out := &in.IAMIdentityMappings, &out.IAMIdentityMappings
+		*out = make([]*IAMIdentityMapping, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(IAMIdentityMapping)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(ClusterStatus)
@@ -258,6 +390,11 @@ func (in *ClusterIAM) DeepCopyInto(out *ClusterIAM) {
 			}
 		}
 	}
+	if in.PermissionsBoundary != nil {
+		in, out := &in.PermissionsBoundary, &out.PermissionsBoundary
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -330,6 +467,16 @@ func (in *ClusterMeta) DeepCopyInto(out *ClusterMeta) {
 			(*out)[key] = val
 		}
 	}
+	if in.DisableIMDSv1 != nil {
+		in, out := &in.DisableIMDSv1, &out.DisableIMDSv1
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AssumeRole != nil {
+		in, out := &in.AssumeRole, &out.AssumeRole
+		*out = new(ClusterAssumeRole)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -364,6 +511,38 @@ func (in *ClusterNAT) DeepCopy() *ClusterNAT {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNetwork) DeepCopyInto(out *ClusterNetwork) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNetwork.
+func (in *ClusterNetwork) DeepCopy() *ClusterNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNodeTerminationHandler) DeepCopyInto(out *ClusterNodeTerminationHandler) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNodeTerminationHandler.
+func (in *ClusterNodeTerminationHandler) DeepCopy() *ClusterNodeTerminationHandler {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNodeTerminationHandler)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
@@ -415,6 +594,42 @@ func (in *ClusterSubnets) DeepCopy() *ClusterSubnets {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTimeouts) DeepCopyInto(out *ClusterTimeouts) {
+	*out = *in
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NodeGroup != nil {
+		in, out := &in.NodeGroup, &out.NodeGroup
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Addon != nil {
+		in, out := &in.Addon, &out.Addon
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTimeouts.
+func (in *ClusterTimeouts) DeepCopy() *ClusterTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterVPC) DeepCopyInto(out *ClusterVPC) {
 	*out = *in
@@ -454,6 +669,28 @@ func (in *ClusterVPC) DeepCopyInto(out *ClusterVPC) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AutoTagSubnetsForELB != nil {
+		in, out := &in.AutoTagSubnetsForELB, &out.AutoTagSubnetsForELB
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LocalZoneSubnets != nil {
+		in, out := &in.LocalZoneSubnets, &out.LocalZoneSubnets
+		*out = make(map[string]Network, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ExtraRoutes != nil {
+		in, out := &in.ExtraRoutes, &out.ExtraRoutes
+		*out = make([]VPCRoute, len(*in))
+		copy(*out, *in)
+	}
+	if in.AssumeRole != nil {
+		in, out := &in.AssumeRole, &out.AssumeRole
+		*out = new(AssumeRole)
+		**out = **in
+	}
 	return
 }
 
@@ -535,6 +772,97 @@ func (in InlineDocument) DeepCopyInto(out *InlineDocument) {
 	}
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Flux) DeepCopyInto(out *Flux) {
+	*out = *in
+	if in.Personal != nil {
+		in, out := &in.Personal, &out.Personal
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Flux.
+func (in *Flux) DeepCopy() *Flux {
+	if in == nil {
+		return nil
+	}
+	out := new(Flux)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOps) DeepCopyInto(out *GitOps) {
+	*out = *in
+	if in.Flux != nil {
+		in, out := &in.Flux, &out.Flux
+		*out = new(Flux)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOps.
+func (in *GitOps) DeepCopy() *GitOps {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOps)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IAMIdentityMapping) DeepCopyInto(out *IAMIdentityMapping) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IAMIdentityMapping.
+func (in *IAMIdentityMapping) DeepCopy() *IAMIdentityMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(IAMIdentityMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchTemplate) DeepCopyInto(out *LaunchTemplate) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchTemplate.
+func (in *LaunchTemplate) DeepCopy() *LaunchTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedNodeGroup) DeepCopyInto(out *ManagedNodeGroup) {
 	*out = *in
@@ -548,11 +876,41 @@ func (in *ManagedNodeGroup) DeepCopyInto(out *ManagedNodeGroup) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.VolumeType != nil {
+		in, out := &in.VolumeType, &out.VolumeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.VolumeIOPS != nil {
+		in, out := &in.VolumeIOPS, &out.VolumeIOPS
+		*out = new(int)
+		**out = **in
+	}
+	if in.VolumeThroughput != nil {
+		in, out := &in.VolumeThroughput, &out.VolumeThroughput
+		*out = new(int)
+		**out = **in
+	}
+	if in.VolumeEncrypted != nil {
+		in, out := &in.VolumeEncrypted, &out.VolumeEncrypted
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeKmsKeyID != nil {
+		in, out := &in.VolumeKmsKeyID, &out.VolumeKmsKeyID
+		*out = new(string)
+		**out = **in
+	}
 	if in.AvailabilityZones != nil {
 		in, out := &in.AvailabilityZones, &out.AvailabilityZones
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.SSH != nil {
 		in, out := &in.SSH, &out.SSH
 		*out = new(NodeGroupSSH)
@@ -565,6 +923,13 @@ func (in *ManagedNodeGroup) DeepCopyInto(out *ManagedNodeGroup) {
 			(*out)[key] = val
 		}
 	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(map[string]string, len(*in))
@@ -577,6 +942,26 @@ func (in *ManagedNodeGroup) DeepCopyInto(out *ManagedNodeGroup) {
 		*out = new(NodeGroupIAM)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LaunchTemplate != nil {
+		in, out := &in.LaunchTemplate, &out.LaunchTemplate
+		*out = new(LaunchTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreBootstrapCommands != nil {
+		in, out := &in.PreBootstrapCommands, &out.PreBootstrapCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisableIMDSv1 != nil {
+		in, out := &in.DisableIMDSv1, &out.DisableIMDSv1
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WaitForNodes != nil {
+		in, out := &in.WaitForNodes, &out.WaitForNodes
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -623,11 +1008,21 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocalZones != nil {
+		in, out := &in.LocalZones, &out.LocalZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	if in.SecurityGroups != nil {
@@ -685,6 +1080,27 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.VolumeThroughput != nil {
+		in, out := &in.VolumeThroughput, &out.VolumeThroughput
+		*out = new(int)
+		**out = **in
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]*NodeGroupVolume, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(NodeGroupVolume)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.InstanceStore != nil {
+		in, out := &in.InstanceStore, &out.InstanceStore
+		*out = new(NodeGroupInstanceStore)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -738,6 +1154,85 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		in, out := &in.KubeletExtraConfig, &out.KubeletExtraConfig
 		*out = (*in).DeepCopy()
 	}
+	if in.MemoryConfig != nil {
+		in, out := &in.MemoryConfig, &out.MemoryConfig
+		*out = new(NodeGroupMemoryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OverrideSysctls != nil {
+		in, out := &in.OverrideSysctls, &out.OverrideSysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(string)
+		**out = **in
+	}
+	if in.ContainerdConfig != nil {
+		in, out := &in.ContainerdConfig, &out.ContainerdConfig
+		*out = new(NodeGroupContainerdConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudFormationParameters != nil {
+		in, out := &in.CloudFormationParameters, &out.CloudFormationParameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SuspendProcesses != nil {
+		in, out := &in.SuspendProcesses, &out.SuspendProcesses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ZonalStorageClass != nil {
+		in, out := &in.ZonalStorageClass, &out.ZonalStorageClass
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableIMDSv1 != nil {
+		in, out := &in.DisableIMDSv1, &out.DisableIMDSv1
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NetworkInterfaces != nil {
+		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
+		*out = new(NodeGroupNetworkInterfaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(NodeGroupPlacement)
+		**out = **in
+	}
+	if in.EFAEnabled != nil {
+		in, out := &in.EFAEnabled, &out.EFAEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(NodeGroupCapacityReservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(NodeGroupWarmPool)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WaitForNodes != nil {
+		in, out := &in.WaitForNodes, &out.WaitForNodes
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -776,6 +1271,58 @@ func (in *NodeGroupBottlerocket) DeepCopy() *NodeGroupBottlerocket {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupCapacityReservation) DeepCopyInto(out *NodeGroupCapacityReservation) {
+	*out = *in
+	if in.CapacityBlock != nil {
+		in, out := &in.CapacityBlock, &out.CapacityBlock
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupCapacityReservation.
+func (in *NodeGroupCapacityReservation) DeepCopy() *NodeGroupCapacityReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupCapacityReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupContainerdConfig) DeepCopyInto(out *NodeGroupContainerdConfig) {
+	*out = *in
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupContainerdConfig.
+func (in *NodeGroupContainerdConfig) DeepCopy() *NodeGroupContainerdConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupContainerdConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroupIAM) DeepCopyInto(out *NodeGroupIAM) {
 	*out = *in
@@ -869,6 +1416,27 @@ func (in *NodeGroupIAMAddonPolicies) DeepCopy() *NodeGroupIAMAddonPolicies {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupInstanceStore) DeepCopyInto(out *NodeGroupInstanceStore) {
+	*out = *in
+	if in.RAID0 != nil {
+		in, out := &in.RAID0, &out.RAID0
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupInstanceStore.
+func (in *NodeGroupInstanceStore) DeepCopy() *NodeGroupInstanceStore {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupInstanceStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroupInstancesDistribution) DeepCopyInto(out *NodeGroupInstancesDistribution) {
 	*out = *in
@@ -915,6 +1483,86 @@ func (in *NodeGroupInstancesDistribution) DeepCopy() *NodeGroupInstancesDistribu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupMemoryConfig) DeepCopyInto(out *NodeGroupMemoryConfig) {
+	*out = *in
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(NodeGroupSwap)
+		**out = **in
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupMemoryConfig.
+func (in *NodeGroupMemoryConfig) DeepCopy() *NodeGroupMemoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupMemoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupNetworkInterfaces) DeepCopyInto(out *NodeGroupNetworkInterfaces) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int)
+		**out = **in
+	}
+	if in.NetworkCardIndices != nil {
+		in, out := &in.NetworkCardIndices, &out.NetworkCardIndices
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnaExpress != nil {
+		in, out := &in.EnaExpress, &out.EnaExpress
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnaExpressUDP != nil {
+		in, out := &in.EnaExpressUDP, &out.EnaExpressUDP
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupNetworkInterfaces.
+func (in *NodeGroupNetworkInterfaces) DeepCopy() *NodeGroupNetworkInterfaces {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupNetworkInterfaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupPlacement) DeepCopyInto(out *NodeGroupPlacement) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupPlacement.
+func (in *NodeGroupPlacement) DeepCopy() *NodeGroupPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroupSGs) DeepCopyInto(out *NodeGroupSGs) {
 	*out = *in
@@ -974,6 +1622,11 @@ func (in *NodeGroupSSH) DeepCopyInto(out *NodeGroupSSH) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EnableSSM != nil {
+		in, out := &in.EnableSSM, &out.EnableSSM
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -987,9 +1640,130 @@ func (in *NodeGroupSSH) DeepCopy() *NodeGroupSSH {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupSwap) DeepCopyInto(out *NodeGroupSwap) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupSwap.
+func (in *NodeGroupSwap) DeepCopy() *NodeGroupSwap {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupSwap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupVolume) DeepCopyInto(out *NodeGroupVolume) {
+	*out = *in
+	if in.VolumeName != nil {
+		in, out := &in.VolumeName, &out.VolumeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.VolumeSize != nil {
+		in, out := &in.VolumeSize, &out.VolumeSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.VolumeType != nil {
+		in, out := &in.VolumeType, &out.VolumeType
+		*out = new(string)
+		**out = **in
+	}
+	if in.VolumeIOPS != nil {
+		in, out := &in.VolumeIOPS, &out.VolumeIOPS
+		*out = new(int)
+		**out = **in
+	}
+	if in.VolumeThroughput != nil {
+		in, out := &in.VolumeThroughput, &out.VolumeThroughput
+		*out = new(int)
+		**out = **in
+	}
+	if in.VolumeEncrypted != nil {
+		in, out := &in.VolumeEncrypted, &out.VolumeEncrypted
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeKmsKeyID != nil {
+		in, out := &in.VolumeKmsKeyID, &out.VolumeKmsKeyID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupVolume.
+func (in *NodeGroupVolume) DeepCopy() *NodeGroupVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupWarmPool) DeepCopyInto(out *NodeGroupWarmPool) {
+	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxPrepared != nil {
+		in, out := &in.MaxPrepared, &out.MaxPrepared
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupWarmPool.
+func (in *NodeGroupWarmPool) DeepCopy() *NodeGroupWarmPool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupWarmPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Outpost) DeepCopyInto(out *Outpost) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Outpost.
+func (in *Outpost) DeepCopy() *Outpost {
+	if in == nil {
+		return nil
+	}
+	out := new(Outpost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
+	if in.PhaseTimeouts != nil {
+		in, out := &in.PhaseTimeouts, &out.PhaseTimeouts
+		*out = make(map[TimeoutPhase]time.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AssumeRoleARNs != nil {
+		in, out := &in.AssumeRoleARNs, &out.AssumeRoleARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1054,3 +1828,19 @@ func (in *SecretsEncryption) DeepCopy() *SecretsEncryption {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCRoute) DeepCopyInto(out *VPCRoute) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCRoute.
+func (in *VPCRoute) DeepCopy() *VPCRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCRoute)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,227 @@
+// Code generated by cmd/fielddocs/generate.go; DO NOT EDIT.
+
+package v1alpha5
+
+var fieldDocs = map[string]fieldDoc{
+	"AssumeRole":                                         {description: "AssumeRole is an IAM role assumed for specific operations instead of the credentials of eksctl, e.g. to read resources of another account", since: ""},
+	"AssumeRole.ExternalID":                              {description: "ExternalID required by the trust policy of the role", since: ""},
+	"AssumeRole.RoleARN":                                 {description: "RoleARN of the role to assume, with the credentials of eksctl", since: ""},
+	"ClusterAssumeRole":                                  {description: "ClusterAssumeRole holds the IAM roles assumed for all AWS API calls instead of the credentials of eksctl", since: ""},
+	"ClusterAssumeRole.ExternalID":                       {description: "ExternalID used to assume the last role", since: ""},
+	"ClusterAssumeRole.RoleARNs":                         {description: "RoleARNs are the roles to assume, in order, each role being assumed with the credentials of the previous one", since: ""},
+	"ClusterAssumeRole.SessionName":                      {description: "SessionName used to assume the roles, generated if unset", since: ""},
+	"ClusterBudgetAlarms":                                {description: "ClusterBudgetAlarms holds the thresholds of the budget alarms and where they're sent", since: ""},
+	"ClusterBudgetAlarms.InterAZTransferDollarsPerMonth": {description: "InterAZTransferDollarsPerMonth is the monthly cost in USD of the data transferred between availability zones by the nodes of the cluster above which an alarm is sent", since: ""},
+	"ClusterBudgetAlarms.NATGatewayGigabytesPerDay":      {description: "NATGatewayGigabytesPerDay is the amount of data processed by each NAT gateway created by eksctl in a day above which an alarm is sent", since: ""},
+	"ClusterBudgetAlarms.SNSTopicARN":                    {description: "SNSTopicARN is the SNS topic the alarms are sent to, a topic is created in the cluster stack if it's not set", since: ""},
+	"ClusterCloudWatch":                                  {description: "ClusterCloudWatch contains config parameters related to CloudWatch", since: ""},
+	"ClusterCloudWatchLogging":                           {description: "ClusterCloudWatchLogging container config parameters related to cluster logging", since: ""},
+	"ClusterConfig":                                      {description: "ClusterConfig is a simple config, to be replaced with Cluster API", since: ""},
+	"ClusterConfig.AvailabilityZones":                    {description: "AvailabilityZones of the subnets of a new VPC, chosen automatically if unset", since: ""},
+	"ClusterConfig.BudgetAlarms":                         {description: "BudgetAlarms notifies of the data processed by the NAT gateways and of the cost of the inter-AZ data transfer of the cluster exceeding thresholds", since: "0.19.0"},
+	"ClusterConfig.CloudWatch":                           {description: "CloudWatch holds the logging settings of the control plane", since: ""},
+	"ClusterConfig.FargateProfiles":                      {description: "FargateProfiles select the pods that run on Fargate", since: ""},
+	"ClusterConfig.GitOps":                               {description: "GitOps holds the settings of the GitOps tooling of the cluster, set up with `eksctl enable flux`", since: "0.19.0"},
+	"ClusterConfig.IAM":                                  {description: "IAM holds the IAM settings of the cluster, such as its service role and OIDC provider", since: ""},
+	"ClusterConfig.IAMIdentityMappings":                  {description: "IAMIdentityMappings map IAM roles and users to Kubernetes users and groups in the aws-auth ConfigMap, they're reconciled by `eksctl apply`", since: "0.19.0"},
+	"ClusterConfig.ManagedNodeGroups":                    {description: "ManagedNodeGroups are the EKS-managed nodegroups of the cluster", since: ""},
+	"ClusterConfig.Metadata":                             {description: "Metadata identifies the cluster", since: ""},
+	"ClusterConfig.Network":                              {description: "Network holds the settings of the pod network, such as the CNI plugin", since: "0.19.0"},
+	"ClusterConfig.NodeGroups":                           {description: "NodeGroups are the self-managed nodegroups of the cluster", since: ""},
+	"ClusterConfig.NodeTerminationHandler":               {description: "NodeTerminationHandler deploys aws-node-termination-handler, which drains the nodes of Spot nodegroups before they're interrupted", since: "0.19.0"},
+	"ClusterConfig.Outpost":                              {description: "Outpost creates a local cluster, whose control plane runs on an AWS Outpost in existing subnets of the Outpost, set in vpc.subnets", since: "0.19.0"},
+	"ClusterConfig.PodSecurityGroups":                    {description: "PodSecurityGroups enables security groups for pods, which run in branch network interfaces of a trunk interface of the nodes; the instance types of the nodegroups must support ENI trunking", since: "0.19.0"},
+	"ClusterConfig.PrefixDelegation":                     {description: "PrefixDelegation makes the VPC CNI assign /28 prefixes instead of single IPs to the network interfaces of the nodes, which can then run more pods; the instance types of the nodegroups must be of the Nitro system, and maxPodsPerNode defaults to auto", since: "0.19.0"},
+	"ClusterConfig.RegistryMirrors":                      {description: "RegistryMirrors maps a registry host (e.g. docker.io) to the endpoints of its mirrors, which all the nodes pull images through; self-managed nodegroups must use containerd or Bottlerocket, and nodegroup mirrors take precedence", since: "0.19.0"},
+	"ClusterConfig.SecretsEncryption":                    {description: "SecretsEncryption enables the encryption of Kubernetes secrets with a KMS key", since: ""},
+	"ClusterConfig.Timeouts":                             {description: "Timeouts of the phases of operations, the flags of the phases take precedence", since: "0.19.0"},
+	"ClusterConfig.VPC":                                  {description: "VPC holds the network settings of the cluster, a new VPC is created unless the IDs of existing subnets are set", since: ""},
+	"ClusterConfigList":                                  {description: "ClusterConfigList is a list of ClusterConfigs", since: ""},
+	"ClusterEndpoints":                                   {description: "ClusterEndpoints holds cluster api server endpoint access information", since: ""},
+	"ClusterIAM":                                         {description: "ClusterIAM holds all IAM attributes of a cluster", since: ""},
+	"ClusterIAM.PermissionsBoundary":                     {description: "PermissionsBoundary is the ARN of the permissions boundary of all the roles created by eksctl, i.e. the service role, the Fargate pod execution role, the instance roles of nodegroups and the roles of iamserviceaccounts, unless the role sets its own permissions boundary", since: "0.19.0"},
+	"ClusterIAMServiceAccount":                           {description: "ClusterIAMServiceAccount holds an iamserviceaccount metadata and configuration", since: ""},
+	"ClusterIAMServiceAccountStatus":                     {description: "ClusterIAMServiceAccountStatus holds status of iamserviceaccount", since: ""},
+	"ClusterMeta":                                        {description: "ClusterMeta is what identifies a cluster", since: ""},
+	"ClusterMeta.AssumeRole":                             {description: "AssumeRole holds the IAM roles assumed for all AWS API calls, the `--assume-role-*` flags take precedence", since: "0.19.0"},
+	"ClusterMeta.DisableIMDSv1":                          {description: "DisableIMDSv1 is the default of `disableIMDSv1` for all the nodegroups", since: "0.19.0"},
+	"ClusterMeta.Name":                                   {description: "Name of the cluster", since: ""},
+	"ClusterMeta.Region":                                 {description: "Region of the cluster", since: ""},
+	"ClusterMeta.Tags":                                   {description: "Tags are added to all the AWS resources created by eksctl", since: ""},
+	"ClusterMeta.Version":                                {description: "Version of Kubernetes, e.g. \"1.15\"", since: ""},
+	"ClusterNAT":                                         {description: "ClusterNAT holds NAT gateway configuration options", since: ""},
+	"ClusterNetwork":                                     {description: "ClusterNetwork holds the settings of the pod network", since: ""},
+	"ClusterNetwork.CNI":                                 {description: "CNI is the CNI plugin of the pods, valid variants are `CNI` constants, defaults to `aws-vpc-cni`; other CNIs are installed with Helm, which must be on the PATH, before the nodes join the cluster", since: ""},
+	"ClusterNetwork.ChartVersion":                        {description: "ChartVersion is the version of the Helm chart of the CNI, overriding the version that eksctl installs by default; the chart must accept the values of the default version", since: ""},
+	"ClusterNetwork.PodCIDR":                             {description: "PodCIDR is the CIDR of the overlay network of the pods with `calico`, it must not overlap `vpc.cidr`, defaults to `10.244.0.0/16`", since: ""},
+	"ClusterNodeTerminationHandler":                      {description: "ClusterNodeTerminationHandler holds the configuration of aws-node-termination-handler", since: ""},
+	"ClusterNodeTerminationHandler.Mode":                 {description: "Mode of the handler, valid variants are `NodeTerminationHandlerMode` constants, defaults to `imds`; `queue` requires `iam.withOIDC`, and creates the SQS queue and the EventBridge rules in the cluster stack, and an IAM service account for the handler", since: ""},
+	"ClusterProvider":                                    {description: "ClusterProvider is the interface to AWS APIs", since: ""},
+	"ClusterStatus":                                      {description: "ClusterStatus hold read-only attributes of a cluster", since: ""},
+	"ClusterSubnets":                                     {description: "ClusterSubnets holds private and public subnets", since: ""},
+	"ClusterTimeouts":                                    {description: "ClusterTimeouts holds the timeouts of the phases of operations, e.g. `40m`", since: ""},
+	"ClusterTimeouts.Addon":                              {description: "Addon is the timeout of the creation and deletion of the stacks of addons, such as IAM service accounts", since: ""},
+	"ClusterTimeouts.ControlPlane":                       {description: "ControlPlane is the timeout of the creation, update and deletion of the control plane", since: ""},
+	"ClusterTimeouts.Drain":                              {description: "Drain is the timeout of draining each node", since: ""},
+	"ClusterTimeouts.NodeGroup":                          {description: "NodeGroup is the timeout of the creation, update and deletion of nodegroups, and of waiting for their nodes", since: ""},
+	"ClusterVPC":                                         {description: "ClusterVPC holds global subnet and all child public/private subnet", since: ""},
+	"ClusterVPC.AssumeRole":                              {description: "AssumeRole is assumed to look up the existing VPC and subnets, e.g. in the network account of a multi-account landing zone, while the cluster is created with the credentials of eksctl", since: "0.19.0"},
+	"ClusterVPC.AutoTagSubnetsForELB":                    {description: "AutoTagSubnetsForELB adds the kubernetes.io/role/elb and kubernetes.io/role/internal-elb tags to existing subnets that lack them", since: "0.19.0"},
+	"ClusterVPC.ExtraCIDRs":                              {description: "for additional CIDR associations, e.g. to use with separate CIDR for private subnets or any ad-hoc subnets", since: ""},
+	"ClusterVPC.ExtraRoutes":                             {description: "ExtraRoutes are added to the route tables of the private subnets, e.g. to route 0.0.0.0/0 to a NAT instance, or the networks of other VPCs to the transit gateway", since: "0.19.0"},
+	"ClusterVPC.LocalZoneSubnets":                        {description: "LocalZoneSubnets are subnets in Local Zones and Wavelength Zones, keyed by zone, e.g. us-west-2-lax-1a or us-east-1-wl1-bos-wlz-1; new subnets route to the internet gateway, or to a carrier gateway in Wavelength Zones, and their CIDR defaults to unused blocks of the VPC CIDR; only self-managed nodegroups can run in them, set in their localZones", since: "0.19.0"},
+	"ClusterVPC.OwnerAccountID":                          {description: "OwnerAccountID is the account that owns the VPC when its subnets are shared with the account of the cluster through AWS RAM; it's set by eksctl when the subnets are imported, and resources of that account are never modified", since: "0.19.0"},
+	"ClusterVPC.SharedNodeSecurityGroup":                 {description: "for pre-defined shared node SG", since: ""},
+	"ClusterVPC.Subnets":                                 {description: "subnets are either public or private for use with separate nodegroups these are keyed by AZ for convenience", since: ""},
+	"ClusterVPC.TransitGatewayID":                        {description: "TransitGatewayID is the ID of an existing transit gateway, the VPC is attached to it and the private subnets route 0.0.0.0/0 to it instead of NAT gateways, e.g. to an egress VPC of a hub-and-spoke network", since: "0.19.0"},
+	"FargateProfile":                                     {description: "FargateProfile defines the settings used to schedule workload onto Fargate.", since: ""},
+	"FargateProfile.Name":                                {description: "Name of the Fargate profile.", since: ""},
+	"FargateProfile.PodExecutionRoleARN":                 {description: "PodExecutionRoleARN is the IAM role's ARN to use to run pods onto Fargate.", since: ""},
+	"FargateProfile.Selectors":                           {description: "Selectors define the rules to select workload to schedule onto Fargate.", since: ""},
+	"FargateProfile.Subnets":                             {description: "Subnets which Fargate should use to do network placement of the selected workload. If none provided, all subnets for the cluster will be used.", since: ""},
+	"FargateProfileSelector":                             {description: "FargateProfileSelector defines rules to select workload to schedule onto Fargate.", since: ""},
+	"FargateProfileSelector.Labels":                      {description: "Labels are the Kubernetes label selectors to use to select workload.", since: ""},
+	"FargateProfileSelector.Namespace":                   {description: "Namespace is the Kubernetes namespace from which to select workload.", since: ""},
+	"Flux":                                               {description: "Flux holds the settings of `flux bootstrap`, which installs Flux v2 in the cluster and commits its manifests to the repository the cluster is synced from", since: ""},
+	"Flux.Branch":                                        {description: "Branch is the branch of the repository, defaults to `main`", since: ""},
+	"Flux.Flags":                                         {description: "Flags are extra flags of `flux bootstrap`, without the leading dashes, e.g. `components-extra: image-reflector-controller,image-automation-controller`", since: ""},
+	"Flux.GitProvider":                                   {description: "GitProvider is the provider of the repository, valid variants are `GitProvider` constants", since: ""},
+	"Flux.Hostname":                                      {description: "Hostname is the hostname of self-hosted GitHub and GitLab servers", since: ""},
+	"Flux.Namespace":                                     {description: "Namespace is the namespace Flux is installed in, defaults to `flux-system`", since: ""},
+	"Flux.Owner":                                         {description: "Owner is the user or organization owning the repository, for github and gitlab", since: ""},
+	"Flux.Path":                                          {description: "Path is the directory of the repository the cluster is synced from, defaults to `clusters/<cluster name>`", since: ""},
+	"Flux.Personal":                                      {description: "Personal is true if the owner of the repository is a user rather than an organization", since: ""},
+	"Flux.PrivateKeyFile":                                {description: "PrivateKeyFile is the path of the SSH private key Flux uses as the deploy key of git repositories; Flux generates one when it's not set, which must then be given access to the repository", since: ""},
+	"Flux.Repository":                                    {description: "Repository is the name of the repository for github and gitlab, or its SSH URL for git, e.g. `ssh://git@example.com/org/repo`", since: ""},
+	"GitOps":                                             {description: "GitOps holds the settings of the GitOps tooling of the cluster", since: ""},
+	"GitOps.Flux":                                        {description: "Flux holds the settings Flux v2 is bootstrapped with", since: ""},
+	"IAMIdentityMapping":                                 {description: "IAMIdentityMapping maps an IAM role or user to a Kubernetes user and groups", since: ""},
+	"IAMIdentityMapping.ARN":                             {description: "ARN of the IAM role or user", since: ""},
+	"IAMIdentityMapping.Groups":                          {description: "Groups within Kubernetes, the username or at least one group must be set", since: ""},
+	"IAMIdentityMapping.Username":                        {description: "Username within Kubernetes", since: ""},
+	"InlineDocument":                                     {description: "InlineDocument holds any arbitrary JSON/YAML documents, such as extra config parameters or IAM policies", since: ""},
+	"LaunchTemplate":                                     {description: "LaunchTemplate references an EC2 launch template", since: ""},
+	"LaunchTemplate.ID":                                  {description: "ID of the launch template", since: ""},
+	"LaunchTemplate.Version":                             {description: "Version of the launch template, the default version is used if it's not set", since: ""},
+	"ManagedNodeGroup":                                   {description: "ManagedNodeGroup defines an EKS-managed nodegroup TODO Validate for unmapped fields and throw an error", since: ""},
+	"ManagedNodeGroup.DisableIMDSv1":                     {description: "DisableIMDSv1 requires the nodes to use session tokens to access the instance metadata service (IMDSv2), it's set in a launch template that eksctl creates for the nodegroup; it defaults to `metadata.disableIMDSv1`", since: "0.19.0"},
+	"ManagedNodeGroup.LaunchTemplate":                    {description: "LaunchTemplate is a launch template created outside of eksctl that the nodes are launched with, e.g. to use a custom AMI; the SSH key and the volume size must be set in the launch template instead", since: "0.19.0"},
+	"ManagedNodeGroup.PreBootstrapCommands":              {description: "PreBootstrapCommands are run on each node before it joins the cluster, they're set in a launch template that eksctl creates for the nodegroup", since: "0.19.0"},
+	"ManagedNodeGroup.Subnets":                           {description: "Subnets are the IDs of existing subnets of the cluster VPC to launch the nodes in, instead of the subnets of availabilityZones", since: "0.19.0"},
+	"ManagedNodeGroup.Taints":                            {description: "Taints are applied to the nodes by EKS, in the same `value:Effect` format as the taints of unmanaged nodegroups", since: "0.19.0"},
+	"ManagedNodeGroup.VolumeEncrypted":                   {description: "", since: "0.19.0"},
+	"ManagedNodeGroup.VolumeIOPS":                        {description: "", since: "0.19.0"},
+	"ManagedNodeGroup.VolumeKmsKeyID":                    {description: "", since: "0.19.0"},
+	"ManagedNodeGroup.VolumeThroughput":                  {description: "VolumeThroughput is the throughput of gp3 volumes, in MiB/s", since: "0.19.0"},
+	"ManagedNodeGroup.VolumeType":                        {description: "VolumeType, VolumeIOPS, VolumeThroughput, VolumeEncrypted and VolumeKmsKeyID are set in a launch template that eksctl creates for the nodegroup", since: "0.19.0"},
+	"ManagedNodeGroup.WaitForNodes":                      {description: "WaitForNodes makes eksctl wait for the nodes to join the cluster and become ready once the nodegroup is created, `eksctl utils wait-nodes` waits for them when it's disabled; defaults to `true`", since: "0.19.0"},
+	"Network":                                            {description: "Network holds ID and CIDR", since: ""},
+	"NodeGroup":                                          {description: "NodeGroup holds all configuration attributes that are specific to a nodegroup", since: ""},
+	"NodeGroup.AdditionalVolumes":                        {description: "AdditionalVolumes are EBS volumes attached to each node, in addition to the root volume", since: "0.19.0"},
+	"NodeGroup.CapacityReservation":                      {description: "CapacityReservation runs the instances in On-Demand Capacity Reservations or Capacity Blocks", since: "0.19.0"},
+	"NodeGroup.CloudFormationParameters":                 {description: "CloudFormationParameters exposes the size and instance type of the nodegroup as parameters of its CloudFormation template when set, the values of the map override the ones from the config, e.g. `MaxSize: \"10\"`", since: "0.19.0"},
+	"NodeGroup.ContainerRuntime":                         {description: "ContainerRuntime is the container runtime used by kubelet, either `docker` (default) or `containerd`", since: "0.19.0"},
+	"NodeGroup.ContainerdConfig":                         {description: "ContainerdConfig overrides the configuration of containerd when it's the container runtime", since: "0.19.0"},
+	"NodeGroup.DisableIMDSv1":                            {description: "DisableIMDSv1 requires the nodes to use session tokens to access the instance metadata service (IMDSv2), it defaults to `metadata.disableIMDSv1`", since: "0.19.0"},
+	"NodeGroup.EFAEnabled":                               {description: "EFAEnabled attaches Elastic Fabric Adapters to the instances, allows EFA traffic between them, installs the EFA software on them and deploys the EFA device plugin", since: "0.19.0"},
+	"NodeGroup.InstanceStore":                            {description: "InstanceStore formats the NVMe instance store volumes of the nodes and mounts them before kubelet starts, on instance types that have such volumes (e.g. i3)", since: "0.19.0"},
+	"NodeGroup.KernelModules":                            {description: "KernelModules are loaded on each node at boot", since: "0.19.0"},
+	"NodeGroup.LocalZones":                               {description: "LocalZones are the Local Zones or Wavelength Zones of vpc.localZoneSubnets the nodes run in, instead of availabilityZones", since: "0.19.0"},
+	"NodeGroup.MaxPodsPerNode":                           {description: "MaxPodsPerNode is the maximum number of pods per node, or auto to compute it from the network interfaces of the instance type and prefix delegation", since: ""},
+	"NodeGroup.MemoryConfig":                             {description: "", since: "0.19.0"},
+	"NodeGroup.NetworkInterfaces":                        {description: "NetworkInterfaces configures the network interfaces attached to the instances, e.g. one per network card or ENA Express on network-intensive instance types", since: "0.19.0"},
+	"NodeGroup.OutpostARN":                               {description: "OutpostARN is the ARN of the Outpost the nodes run on, subnets must be subnets of the Outpost; it defaults to the Outpost of local clusters", since: "0.19.0"},
+	"NodeGroup.OverrideSysctls":                          {description: "OverrideSysctls are kernel parameters set on each node, namespaced ones are also allowed as unsafe sysctls in pods", since: "0.19.0"},
+	"NodeGroup.Placement":                                {description: "Placement puts the instances in a placement group", since: "0.19.0"},
+	"NodeGroup.Subnets":                                  {description: "Subnets are the IDs of existing subnets of the cluster VPC to launch the nodes in, instead of the subnets of availabilityZones. A single subnet makes a single-AZ nodegroup, e.g. for workloads bound to EBS volumes", since: "0.19.0"},
+	"NodeGroup.SuspendProcesses":                         {description: "SuspendProcesses are the processes of the Auto Scaling group of the nodegroup that are suspended once it's created, e.g. `AZRebalance` to keep it from terminating instances without draining their nodes", since: "0.19.0"},
+	"NodeGroup.Tenancy":                                  {description: "Tenancy of the instances, valid variants are `Tenancy` constants", since: "0.19.0"},
+	"NodeGroup.VolumeThroughput":                         {description: "VolumeThroughput is the throughput of gp3 volumes, in MiB/s", since: "0.19.0"},
+	"NodeGroup.WaitForNodes":                             {description: "WaitForNodes makes eksctl wait for the nodes to join the cluster and become ready once the nodegroup is created, `eksctl utils wait-nodes` waits for them when it's disabled; defaults to `true`", since: "0.19.0"},
+	"NodeGroup.WarmPool":                                 {description: "WarmPool keeps pre-initialized instances ready to join the nodegroup when it scales out, nodes only bootstrap once their instance leaves the warm pool", since: "0.19.0"},
+	"NodeGroup.ZonalStorageClass":                        {description: "ZonalStorageClass creates a StorageClass named after the availability zone of the nodegroup, e.g. gp2-us-west-2a, whose volumes are provisioned in that zone; the nodegroup must be in a single availability zone", since: "0.19.0"},
+	"NodeGroupBottlerocket":                              {description: "NodeGroupBottlerocket holds the configuration for Bottlerocket based NodeGroups.", since: ""},
+	"NodeGroupCapacityReservation":                       {description: "NodeGroupCapacityReservation targets a capacity reservation or a resource group of capacity reservations, or sets the capacity reservation preference of the instances of a NodeGroup", since: ""},
+	"NodeGroupCapacityReservation.CapacityBlock":         {description: "CapacityBlock must be enabled when ID is a Capacity Block for ML, so that the instances are launched in the capacity-block market", since: ""},
+	"NodeGroupCapacityReservation.ID":                    {description: "ID of an On-Demand Capacity Reservation or of a Capacity Block", since: ""},
+	"NodeGroupCapacityReservation.Preference":            {description: "Preference of the instances when no reservation is targeted, valid variants are `CapacityReservationPreference` constants", since: ""},
+	"NodeGroupCapacityReservation.ResourceGroupARN":      {description: "ResourceGroupARN of a resource group of capacity reservations", since: ""},
+	"NodeGroupContainerdConfig":                          {description: "NodeGroupContainerdConfig holds the overrides of the containerd configuration of a NodeGroup", since: ""},
+	"NodeGroupContainerdConfig.RegistryMirrors":          {description: "RegistryMirrors maps a registry host (e.g. docker.io) to the endpoints of its mirrors, which are tried in order before the registry itself", since: ""},
+	"NodeGroupContainerdConfig.SandboxImage":             {description: "SandboxImage is the image of the pause container of pods, it defaults to the one of EKS Distro in the Amazon ECR Public Gallery", since: ""},
+	"NodeGroupIAM":                                       {description: "NodeGroupIAM holds all IAM attributes of a NodeGroup", since: ""},
+	"NodeGroupIAM.AttachPolicyARNs":                      {description: "AttachPolicyARNs are the ARNs of the policies attached to the instance role, they replace the default policies", since: ""},
+	"NodeGroupIAM.InstanceProfileARN":                    {description: "InstanceProfileARN is the ARN of an existing instance profile to use for the nodes", since: ""},
+	"NodeGroupIAM.InstanceRoleARN":                       {description: "InstanceRoleARN is the ARN of an existing role to use for the nodes", since: ""},
+	"NodeGroupIAM.InstanceRoleName":                      {description: "InstanceRoleName is the name of the instance role created by eksctl", since: ""},
+	"NodeGroupIAM.InstanceRolePermissionsBoundary":       {description: "InstanceRolePermissionsBoundary is the ARN of the permissions boundary of the instance role", since: ""},
+	"NodeGroupIAM.WithAddonPolicies":                     {description: "WithAddonPolicies attaches the policies needed by common addons to the instance role", since: ""},
+	"NodeGroupIAMAddonPolicies":                          {description: "NodeGroupIAMAddonPolicies holds all IAM addon policies", since: ""},
+	"NodeGroupIAMAddonPolicies.ALBIngress":               {description: "ALBIngress allows the ALB ingress controller to manage load balancers", since: ""},
+	"NodeGroupIAMAddonPolicies.AppMesh":                  {description: "AppMesh allows full access to App Mesh", since: ""},
+	"NodeGroupIAMAddonPolicies.AutoScaler":               {description: "AutoScaler allows the cluster-autoscaler to manage the ASGs", since: ""},
+	"NodeGroupIAMAddonPolicies.CertManager":              {description: "CertManager allows cert-manager to solve DNS01 challenges with Route 53", since: ""},
+	"NodeGroupIAMAddonPolicies.CloudWatch":               {description: "CloudWatch allows the CloudWatch agent to send metrics and logs", since: ""},
+	"NodeGroupIAMAddonPolicies.EBS":                      {description: "EBS allows the EBS CSI driver to manage volumes", since: ""},
+	"NodeGroupIAMAddonPolicies.EFS":                      {description: "EFS allows full access to EFS", since: ""},
+	"NodeGroupIAMAddonPolicies.ExternalDNS":              {description: "ExternalDNS allows external-dns to manage Route 53 records", since: ""},
+	"NodeGroupIAMAddonPolicies.FSX":                      {description: "FSX allows full access to FSx for Lustre", since: ""},
+	"NodeGroupIAMAddonPolicies.ImageBuilder":             {description: "ImageBuilder allows full access to ECR", since: ""},
+	"NodeGroupIAMAddonPolicies.XRay":                     {description: "XRay allows the X-Ray daemon to send traces", since: ""},
+	"NodeGroupInstanceStore":                             {description: "NodeGroupInstanceStore holds the configuration of the instance store volumes of a NodeGroup", since: ""},
+	"NodeGroupInstanceStore.MountPath":                   {description: "MountPath is where the volume is mounted, e.g. /var/lib/docker to store the images and writable layers of containers", since: ""},
+	"NodeGroupInstanceStore.RAID0":                       {description: "RAID0 stripes all the instance store volumes of a node into a single RAID 0 array, otherwise only the first volume is used", since: ""},
+	"NodeGroupInstancesDistribution":                     {description: "NodeGroupInstancesDistribution holds the configuration for spot instances", since: ""},
+	"NodeGroupMemoryConfig":                              {description: "NodeGroupMemoryConfig holds host-level memory settings of a NodeGroup, such as swap and huge pages", since: ""},
+	"NodeGroupMemoryConfig.HugePages":                    {description: "HugePages maps a huge page size (2Mi or 1Gi) to the number of pages to pre-allocate on each node", since: "0.19.0"},
+	"NodeGroupMemoryConfig.Swap":                         {description: "", since: "0.19.0"},
+	"NodeGroupNetworkInterfaces":                         {description: "NodeGroupNetworkInterfaces holds the network interface settings of the instances of a NodeGroup", since: ""},
+	"NodeGroupNetworkInterfaces.Count":                   {description: "Count of network interfaces attached to each instance, the primary one included, they're spread over the network cards of the instance type (default 1)", since: ""},
+	"NodeGroupNetworkInterfaces.EnaExpress":              {description: "EnaExpress enables ENA Express for TCP traffic on all the interfaces", since: ""},
+	"NodeGroupNetworkInterfaces.EnaExpressUDP":           {description: "EnaExpressUDP also enables ENA Express for UDP traffic, it requires enaExpress", since: ""},
+	"NodeGroupNetworkInterfaces.NetworkCardIndices":      {description: "NetworkCardIndices sets the network card of each interface instead of spreading them, the primary interface must be on card 0", since: ""},
+	"NodeGroupPlacement":                                 {description: "NodeGroupPlacement holds the placement group of the instances of a NodeGroup, either an existing one or one created for the NodeGroup", since: ""},
+	"NodeGroupPlacement.GroupName":                       {description: "GroupName of an existing placement group", since: ""},
+	"NodeGroupPlacement.Strategy":                        {description: "Strategy of a placement group created for the NodeGroup, valid variants are `PlacementStrategy` constants", since: ""},
+	"NodeGroupSGs":                                       {description: "NodeGroupSGs holds all SG attributes of a NodeGroup", since: ""},
+	"NodeGroupSSH":                                       {description: "NodeGroupSSH holds all the ssh access configuration to a NodeGroup", since: ""},
+	"NodeGroupSSH.EnableSSM":                             {description: "EnableSSM attaches the AmazonSSMManagedInstanceCore policy to the nodes, so that they can be accessed with SSM Session Manager instead of SSH keys; no key pair is imported and port 22 isn't opened", since: "0.19.0"},
+	"NodeGroupSwap":                                      {description: "NodeGroupSwap holds the swap file configuration of a NodeGroup", since: ""},
+	"NodeGroupSwap.Behavior":                             {description: "Behavior is the kubelet swap behavior, LimitedSwap (default) or UnlimitedSwap", since: ""},
+	"NodeGroupSwap.Size":                                 {description: "Size of the swap file, as a Kubernetes quantity (e.g. 4Gi)", since: ""},
+	"NodeGroupType":                                      {description: "NodeGroupType defines the nodegroup type", since: ""},
+	"NodeGroupVolume":                                    {description: "NodeGroupVolume holds the configuration of an additional EBS volume of a NodeGroup", since: ""},
+	"NodeGroupVolume.VolumeName":                         {description: "VolumeName is the device name the volume is exposed as, e.g. /dev/xvdb", since: ""},
+	"NodeGroupWarmPool":                                  {description: "NodeGroupWarmPool holds the warm pool configuration of a NodeGroup", since: ""},
+	"NodeGroupWarmPool.MaxPrepared":                      {description: "MaxPrepared is the maximum number of instances in the nodegroup and in its warm pool, defaults to the maxSize of the nodegroup", since: ""},
+	"NodeGroupWarmPool.MinSize":                          {description: "MinSize is the minimum number of instances kept in the warm pool", since: ""},
+	"NodeGroupWarmPool.State":                            {description: "State of the instances in the warm pool, valid variants are `WarmPoolState` constants, defaults to `Stopped`", since: ""},
+	"Outpost":                                            {description: "Outpost holds the configuration of a local cluster on AWS Outposts", since: ""},
+	"Outpost.ControlPlaneInstanceType":                   {description: "ControlPlaneInstanceType is the instance type of the control plane instances, which must be available on the Outpost, defaults to `m5.large`", since: ""},
+	"Outpost.ControlPlaneOutpostARN":                     {description: "ControlPlaneOutpostARN is the ARN of the Outpost the control plane runs on", since: ""},
+	"PodsPerNode":                                        {description: "PodsPerNode is the maximum number of pods per node, either a number or auto", since: ""},
+	"ProviderConfig":                                     {description: "ProviderConfig holds global parameters for all interactions with AWS APIs", since: ""},
+	"ProviderConfig.APIBurst":                            {description: "APIBurst is the number of calls that can be made at once within MaxAPIQPS", since: ""},
+	"ProviderConfig.AssumeRoleARNs":                      {description: "AssumeRoleARNs are the IAM roles to assume, in order, each role is assumed with the credentials of the previous one", since: ""},
+	"ProviderConfig.AssumeRoleExternalID":                {description: "AssumeRoleExternalID is the external ID used to assume the last role", since: ""},
+	"ProviderConfig.AssumeRoleSessionName":               {description: "AssumeRoleSessionName is the session name used to assume the roles", since: ""},
+	"ProviderConfig.MaxAPIQPS":                           {description: "MaxAPIQPS is the maximum rate of EC2, CloudFormation and EKS API calls per second, they're not rate limited when it's 0", since: ""},
+	"ProviderConfig.PhaseTimeouts":                       {description: "PhaseTimeouts are the timeouts of the phases of operations that were set explicitly", since: ""},
+	"ProviderConfig.StackOnFailure":                      {description: "StackOnFailure is what happens to the stacks that fail to be created, valid variants are `StackOnFailure` constants, they're rolled back when it's empty", since: ""},
+	"ProviderConfig.WaitTimeoutSet":                      {description: "WaitTimeoutSet is true when WaitTimeout was set explicitly, it then applies to the phases whose timeout isn't set instead of their default timeout", since: ""},
+	"ScalingConfig":                                      {description: "ScalingConfig defines the scaling config", since: ""},
+	"SecretsEncryption":                                  {description: "SecretsEncryption defines the configuration for KMS encryption provider", since: ""},
+	"SubnetTopology":                                     {description: "SubnetTopology can be SubnetTopologyPrivate or SubnetTopologyPublic", since: ""},
+	"TimeoutPhase":                                       {description: "TimeoutPhase is a phase of operations that has its own timeout", since: ""},
+	"VPCRoute":                                           {description: "VPCRoute is a route of the private subnets to an existing target, at most one of the targets can be set", since: ""},
+	"VPCRoute.DestinationCIDR":                           {description: "DestinationCIDR of the route, e.g. 0.0.0.0/0", since: ""},
+	"VPCRoute.InstanceID":                                {description: "InstanceID of a NAT instance", since: ""},
+	"VPCRoute.NetworkInterfaceID":                        {description: "NetworkInterfaceID of a network interface, e.g. of a firewall appliance", since: ""},
+	"VPCRoute.TransitGatewayID":                          {description: "TransitGatewayID of a transit gateway the VPC is attached to, it defaults to vpc.transitGatewayID when no other target is set", since: ""},
+	"deprecatedField":                                    {description: "deprecatedField is a field that has been moved, it's still read from config files of all versions", since: ""},
+	"deprecatedField.from":                               {description: "from and to are the paths of the field relatively to the objects", since: ""},
+	"deprecatedField.objects":                            {description: "objects is the path of the objects holding the field, [] expanding lists", since: ""},
+	"deprecatedField.to":                                 {description: "from and to are the paths of the field relatively to the objects", since: ""},
+	"instanceNetworking":                                 {description: "instanceNetworking holds the network interface limits of an instance type", since: ""},
+	"nameSet":                                            {description: "NOTE: we don't use k8s.io/apimachinery/pkg/util/sets here to keep API package free of dependencies", since: ""},
+}
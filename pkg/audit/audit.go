@@ -0,0 +1,267 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// KubernetesService is the service of the entries of Kubernetes API calls
+const KubernetesService = "kubernetes"
+
+// Default records the mutating calls of all the AWS sessions and Kubernetes clients
+// created by eksctl, it's only set when an audit log was requested with --audit-log
+var Default *Log
+
+// readOnlyOperationPrefixes are the prefixes of the names of the AWS operations that don't change anything
+var readOnlyOperationPrefixes = []string{
+	"AssumeRole",
+	"Describe",
+	"Estimate",
+	"Get",
+	"Head",
+	"List",
+	"Lookup",
+	"Search",
+	"Validate",
+}
+
+// redactedFields are the fields of the inputs of AWS operations whose values aren't recorded, besides
+// the ones the SDK tags as sensitive, as they may hold secrets, e.g. in the user data of nodes
+var redactedFields = map[string]bool{
+	"ParameterValue": true,
+	"SecretBinary":   true,
+	"SecretString":   true,
+	"TemplateBody":   true,
+	"UserData":       true,
+}
+
+// redactedValue replaces the values of redacted fields
+const redactedValue = "<redacted>"
+
+// Entry is a mutating AWS or Kubernetes API call, it's written as a line of JSON
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Service is the name of the AWS service, e.g. cloudformation, or KubernetesService
+	Service string `json:"service"`
+	// Operation is the name of the AWS operation, or the HTTP method of the Kubernetes request
+	Operation string `json:"operation"`
+	Region    string `json:"region,omitempty"`
+	// Endpoint and Path are the API server and path of Kubernetes requests, whose body is not recorded
+	Endpoint string `json:"endpoint,omitempty"`
+	Path     string `json:"path,omitempty"`
+	// Parameters is the input of AWS operations, without the values of sensitive fields
+	Parameters interface{} `json:"parameters,omitempty"`
+	RequestID  string      `json:"requestID,omitempty"`
+	// Result is either "success" or "error"
+	Result     string `json:"result"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"durationMs"`
+}
+
+// Log writes entries to an append-only file
+type Log struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// Open opens the audit log at path, entries are appended to the file if it exists
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening audit log %q", path)
+	}
+	l := New(f)
+	l.closer = f
+	return l, nil
+}
+
+// New returns a log that writes entries to w
+func New(w io.Writer) *Log {
+	return &Log{w: w}
+}
+
+// Close closes the file of the log
+func (l *Log) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// Record writes entry as a line of JSON
+func (l *Log) Record(entry Entry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		// some inputs, e.g. streams, can't be marshalled
+		entry.Parameters = awsutil.Prettify(entry.Parameters)
+		if line, err = json.Marshal(entry); err != nil {
+			logger.Warning("unable to record %s %s in the audit log: %s", entry.Service, entry.Operation, err.Error())
+			return
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := fmt.Fprintf(l.w, "%s\n", line); err != nil {
+		logger.Warning("unable to write to the audit log: %s", err.Error())
+	}
+}
+
+// IsMutatingOperation reports whether the AWS operation of the given name may change a resource
+func IsMutatingOperation(name string) bool {
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddHandlers makes the mutating requests sent with handlers recorded in the log,
+// once they're complete, including their retries
+func (l *Log) AddHandlers(handlers *request.Handlers) {
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "eksctlAuditLogComplete",
+		Fn: func(r *request.Request) {
+			if r.Operation == nil || !IsMutatingOperation(r.Operation.Name) {
+				return
+			}
+			entry := Entry{
+				Time:       r.Time,
+				Service:    r.ClientInfo.ServiceName,
+				Operation:  r.Operation.Name,
+				Region:     aws.StringValue(r.Config.Region),
+				Parameters: Redact(r.Params),
+				RequestID:  r.RequestID,
+				Result:     "success",
+				DurationMS: time.Since(r.Time).Milliseconds(),
+			}
+			if r.Error != nil {
+				entry.Result = "error"
+				entry.Error = r.Error.Error()
+				if awsErr, ok := r.Error.(awserr.Error); ok {
+					entry.Error = fmt.Sprintf("%s: %s", awsErr.Code(), awsErr.Message())
+				}
+			}
+			l.Record(entry)
+		},
+	})
+}
+
+// Redact returns the fields of the input of an AWS operation that are set, as a map, the values of
+// the fields tagged as sensitive by the SDK and of redactedFields being replaced
+func Redact(params interface{}) interface{} {
+	if params == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(params))
+}
+
+func redactValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		if _, ok := v.Interface().(json.Marshaler); ok {
+			return v.Interface()
+		}
+		fields := map[string]interface{}{}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			value := v.Field(i)
+			if field.PkgPath != "" || isUnset(value) {
+				continue
+			}
+			if field.Tag.Get("sensitive") == "true" || redactedFields[field.Name] {
+				fields[field.Name] = redactedValue
+				continue
+			}
+			fields[field.Name] = redactValue(value)
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return redactedValue
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = redactValue(v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		entries := map[string]interface{}{}
+		for _, key := range v.MapKeys() {
+			entries[fmt.Sprint(key.Interface())] = redactValue(v.MapIndex(key))
+		}
+		return entries
+	default:
+		return v.Interface()
+	}
+}
+
+func isUnset(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// WrapTransport makes the mutating requests sent through rt recorded in the log,
+// it's meant to be set as the WrapTransport of the config of Kubernetes clients
+func (l *Log) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &auditTransport{log: l, next: rt}
+}
+
+type auditTransport struct {
+	log  *Log
+	next http.RoundTripper
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	entry := Entry{
+		Time:       start,
+		Service:    KubernetesService,
+		Operation:  req.Method,
+		Endpoint:   req.URL.Host,
+		Path:       req.URL.Path,
+		Result:     "success",
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	switch {
+	case err != nil:
+		entry.Result = "error"
+		entry.Error = err.Error()
+	case resp.StatusCode >= http.StatusBadRequest:
+		entry.Result = "error"
+		entry.Error = resp.Status
+	}
+	t.log.Record(entry)
+	return resp, err
+}
@@ -0,0 +1,157 @@
+package audit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/audit"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("audit log", func() {
+	var (
+		out *bytes.Buffer
+		log *Log
+	)
+
+	entries := func() []map[string]interface{} {
+		var result []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry map[string]interface{}
+			Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		log = New(out)
+	})
+
+	It("tells mutating AWS operations apart", func() {
+		Expect(IsMutatingOperation("CreateStack")).To(BeTrue())
+		Expect(IsMutatingOperation("UpdateNodegroupVersion")).To(BeTrue())
+		Expect(IsMutatingOperation("DescribeStacks")).To(BeFalse())
+		Expect(IsMutatingOperation("ListClusters")).To(BeFalse())
+		Expect(IsMutatingOperation("AssumeRoleWithWebIdentity")).To(BeFalse())
+	})
+
+	It("records the mutating AWS calls with their parameters and result", func() {
+		handlers := request.Handlers{}
+		log.AddHandlers(&handlers)
+
+		newRequest := func(operation string, params interface{}, err error) *request.Request {
+			return &request.Request{
+				Operation:  &request.Operation{Name: operation},
+				ClientInfo: metadata.ClientInfo{ServiceName: "cloudformation"},
+				Config:     aws.Config{Region: aws.String("us-west-2")},
+				Params:     params,
+				RequestID:  "request-1",
+				Time:       time.Now(),
+				Error:      err,
+			}
+		}
+
+		handlers.Complete.Run(newRequest("DescribeStacks", &cloudformation.DescribeStacksInput{}, nil))
+		handlers.Complete.Run(newRequest("CreateStack", &cloudformation.CreateStackInput{StackName: aws.String("eksctl-test-cluster")}, nil))
+		handlers.Complete.Run(newRequest("DeleteStack", &cloudformation.DeleteStackInput{StackName: aws.String("eksctl-test-cluster")},
+			awserr.New("ValidationError", "Stack does not exist", nil)))
+
+		recorded := entries()
+		Expect(recorded).To(HaveLen(2))
+		Expect(recorded[0]).To(HaveKeyWithValue("service", "cloudformation"))
+		Expect(recorded[0]).To(HaveKeyWithValue("operation", "CreateStack"))
+		Expect(recorded[0]).To(HaveKeyWithValue("region", "us-west-2"))
+		Expect(recorded[0]).To(HaveKeyWithValue("requestID", "request-1"))
+		Expect(recorded[0]).To(HaveKeyWithValue("result", "success"))
+		Expect(recorded[0]["parameters"]).To(HaveKeyWithValue("StackName", "eksctl-test-cluster"))
+
+		Expect(recorded[1]).To(HaveKeyWithValue("operation", "DeleteStack"))
+		Expect(recorded[1]).To(HaveKeyWithValue("result", "error"))
+		Expect(recorded[1]).To(HaveKeyWithValue("error", "ValidationError: Stack does not exist"))
+	})
+
+	It("redacts the sensitive parameters of AWS calls", func() {
+		handlers := request.Handlers{}
+		log.AddHandlers(&handlers)
+
+		handlers.Complete.Run(&request.Request{
+			Operation:  &request.Operation{Name: "CreateStack"},
+			ClientInfo: metadata.ClientInfo{ServiceName: "cloudformation"},
+			Params: &cloudformation.CreateStackInput{
+				StackName:    aws.String("eksctl-test-cluster"),
+				TemplateBody: aws.String(`{"Resources": {}}`),
+				Parameters: []*cloudformation.Parameter{
+					{ParameterKey: aws.String("Password"), ParameterValue: aws.String("secret")},
+				},
+			},
+			Time: time.Now(),
+		})
+
+		recorded := entries()
+		Expect(recorded).To(HaveLen(1))
+		Expect(recorded[0]["parameters"]).To(Equal(map[string]interface{}{
+			"StackName":    "eksctl-test-cluster",
+			"TemplateBody": "<redacted>",
+			"Parameters": []interface{}{
+				map[string]interface{}{"ParameterKey": "Password", "ParameterValue": "<redacted>"},
+			},
+		}))
+	})
+
+	It("records the mutating Kubernetes requests without their body", func() {
+		status := http.StatusOK
+		transport := log.WrapTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v1/namespaces/default/pods/unreachable" {
+				return nil, errors.New("connection refused")
+			}
+			return &http.Response{StatusCode: status, Status: http.StatusText(status)}, nil
+		}))
+
+		send := func(method, path string) {
+			req, err := http.NewRequest(method, "https://api.example.com"+path, strings.NewReader(`{"kind":"Secret"}`))
+			Expect(err).NotTo(HaveOccurred())
+			_, _ = transport.RoundTrip(req)
+		}
+
+		send(http.MethodGet, "/api/v1/namespaces/kube-system/configmaps/aws-auth")
+		send(http.MethodPut, "/api/v1/namespaces/kube-system/configmaps/aws-auth")
+		status = http.StatusForbidden
+		send(http.MethodDelete, "/api/v1/namespaces/default/secrets/creds")
+		send(http.MethodDelete, "/api/v1/namespaces/default/pods/unreachable")
+
+		recorded := entries()
+		Expect(recorded).To(HaveLen(3))
+		Expect(recorded[0]).To(HaveKeyWithValue("service", KubernetesService))
+		Expect(recorded[0]).To(HaveKeyWithValue("operation", "PUT"))
+		Expect(recorded[0]).To(HaveKeyWithValue("endpoint", "api.example.com"))
+		Expect(recorded[0]).To(HaveKeyWithValue("path", "/api/v1/namespaces/kube-system/configmaps/aws-auth"))
+		Expect(recorded[0]).To(HaveKeyWithValue("result", "success"))
+		Expect(recorded[0]).NotTo(HaveKey("parameters"))
+
+		Expect(recorded[1]).To(HaveKeyWithValue("result", "error"))
+		Expect(recorded[1]).To(HaveKeyWithValue("error", "Forbidden"))
+		Expect(recorded[2]).To(HaveKeyWithValue("error", "connection refused"))
+	})
+})
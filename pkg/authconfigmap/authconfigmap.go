@@ -8,9 +8,9 @@ package authconfigmap
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/awsutil"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -22,6 +22,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -43,6 +44,10 @@ const (
 	// RoleNodeGroupUsername is the default username for a nodegroup
 	// role mapping.
 	RoleNodeGroupUsername = "system:node:{{EC2PrivateDNSName}}"
+
+	// ManagedIdentitiesAnnotation lists the ARNs of the identities mapped by
+	// `eksctl apply`, which are the only ones it deletes.
+	ManagedIdentitiesAnnotation = "eksctl.io/managed-identities"
 )
 
 // RoleNodeGroupGroups are the groups to allow roles to interact
@@ -155,32 +160,85 @@ func (a *AuthConfigMap) AddIdentity(identity iam.Identity) error {
 	return a.setIdentities(identities)
 }
 
+// IdentityFilter selects the identities of an ARN that are removed from the auth ConfigMap
+type IdentityFilter struct {
+	ARN string
+	// Username and Groups must match exactly when set, the order of the groups doesn't matter
+	Username string
+	Groups   []string
+	// Index selects a single identity among the ones that match the other fields, in
+	// the order they're returned by Identities, starting at 0
+	Index *int
+}
+
+func (f IdentityFilter) matches(identity iam.Identity) bool {
+	if identity.ARN() != f.ARN {
+		return false
+	}
+	if f.Username != "" && identity.Username() != f.Username {
+		return false
+	}
+	if len(f.Groups) > 0 && !sets.NewString(f.Groups...).Equal(sets.NewString(identity.Groups()...)) {
+		return false
+	}
+	return true
+}
+
+func (f IdentityFilter) String() string {
+	s := fmt.Sprintf("ARN %q", f.ARN)
+	if f.Username != "" {
+		s += fmt.Sprintf(", username %q", f.Username)
+	}
+	if len(f.Groups) > 0 {
+		s += fmt.Sprintf(", groups %q", f.Groups)
+	}
+	if f.Index != nil {
+		s += fmt.Sprintf(", index %d", *f.Index)
+	}
+	return s
+}
+
 // RemoveIdentity removes an identity. If `all` is false it will only
 // remove the first it encounters and return an error if it cannot
 // find it.
 // If `all` is true it will remove all of them and not return an
 // error if it cannot be found.
 func (a *AuthConfigMap) RemoveIdentity(arnToDelete string, all bool) error {
+	return a.RemoveMatchingIdentities(IdentityFilter{ARN: arnToDelete}, all)
+}
+
+// RemoveMatchingIdentities removes the identities selected by filter, which is
+// needed when an ARN is mapped more than once. Like RemoveIdentity, only the first
+// identity that matches is removed unless `all` is true, in which case it's not an
+// error if none is found.
+func (a *AuthConfigMap) RemoveMatchingIdentities(filter IdentityFilter, all bool) error {
 	identities, err := a.Identities()
 	if err != nil {
 		return err
 	}
 
-	newidentities := make([]iam.Identity, 0)
-	for i, identity := range identities {
-		arn := identity.ARN()
-		if arn == arnToDelete {
-			logger.Info("removing identity %q from auth ConfigMap (username = %q, groups = %q)", arnToDelete, identity.Username(), identity.Groups())
-			if !all {
-				identities = append(identities[:i], identities[i+1:]...)
-				return a.setIdentities(identities)
+	var (
+		newidentities = make([]iam.Identity, 0)
+		matched       = 0
+		removed       = 0
+	)
+	for _, identity := range identities {
+		if filter.matches(identity) {
+			index := matched
+			matched++
+			if (filter.Index == nil || *filter.Index == index) && (all || removed == 0) {
+				logger.Info("removing identity %q from auth ConfigMap (username = %q, groups = %q)", identity.ARN(), identity.Username(), identity.Groups())
+				removed++
+				continue
 			}
-		} else if all {
-			newidentities = append(newidentities, identity)
 		}
+		newidentities = append(newidentities, identity)
 	}
-	if !all {
-		return fmt.Errorf("instance identity ARN %q not found in auth ConfigMap", arnToDelete)
+	if removed == 0 && !all {
+		if filter.Username == "" && len(filter.Groups) == 0 && filter.Index == nil {
+			return fmt.Errorf("instance identity ARN %q not found in auth ConfigMap", filter.ARN)
+		}
+		return fmt.Errorf("no identity with %s found in auth ConfigMap", filter)
 	}
 	return a.setIdentities(newidentities)
 }
@@ -211,6 +269,9 @@ func (a *AuthConfigMap) setIdentities(identities []iam.Identity) error {
 	// Split identities into list of roles and list of users
 	users, roles := []iam.Identity{}, []iam.Identity{}
 	for _, identity := range identities {
+		if unknownFields := identity.UnknownFields(); len(unknownFields) > 0 {
+			logger.Warning("keeping fields %q of the mapping of %q in auth ConfigMap, which eksctl doesn't know about", unknownFields, identity.ARN())
+		}
 		switch identity.Type() {
 		case iam.ResourceTypeRole:
 			roles = append(roles, identity)
@@ -237,6 +298,27 @@ func (a *AuthConfigMap) setIdentities(identities []iam.Identity) error {
 	return nil
 }
 
+// ManagedIdentityARNs returns the ARNs of the identities mapped by `eksctl apply`.
+func (a *AuthConfigMap) ManagedIdentityARNs() []string {
+	arns := a.cm.Annotations[ManagedIdentitiesAnnotation]
+	if arns == "" {
+		return nil
+	}
+	return strings.Split(arns, ",")
+}
+
+// SetManagedIdentityARNs records the ARNs of the identities mapped by `eksctl apply`.
+func (a *AuthConfigMap) SetManagedIdentityARNs(arns []string) {
+	if len(arns) == 0 {
+		delete(a.cm.Annotations, ManagedIdentitiesAnnotation)
+		return
+	}
+	if a.cm.Annotations == nil {
+		a.cm.Annotations = map[string]string{}
+	}
+	a.cm.Annotations[ManagedIdentitiesAnnotation] = strings.Join(arns, ",")
+}
+
 // Save persists the ConfigMap to the cluster. It determines
 // whether to create or update by looking at the ConfigMap's UID.
 func (a *AuthConfigMap) Save() (err error) {
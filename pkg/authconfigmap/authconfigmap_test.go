@@ -437,6 +437,76 @@ var _ = Describe("AuthConfigMap{}", func() {
 			Expect(client.updated.Data["mapUsers"]).To(MatchYAML("[]"))
 		})
 	})
+	Describe("RemoveMatchingIdentities()", func() {
+		var (
+			client *mockClient
+			acm    *AuthConfigMap
+		)
+
+		BeforeEach(func() {
+			existing := &corev1.ConfigMap{
+				ObjectMeta: ObjectMeta(),
+				Data: map[string]string{
+					"mapRoles": "",
+					"mapUsers": expectedUserA + expectedUserB + makeExpectedUser(userA, "alice-admin", "system:masters") + expectedUserA,
+				},
+			}
+			existing.UID = "123456"
+			client = &mockClient{}
+			acm = New(client, existing)
+		})
+
+		removeAndSave := func(filter IdentityFilter, all bool) *corev1.ConfigMap {
+			Expect(acm.RemoveMatchingIdentities(filter, all)).To(Succeed())
+			Expect(acm.Save()).To(Succeed())
+			return client.updated
+		}
+
+		It("should remove the identity with the given username", func() {
+			cm := removeAndSave(IdentityFilter{ARN: userA, Username: "alice-admin"}, false)
+			Expect(cm.Data["mapUsers"]).To(MatchYAML(expectedUserA + expectedUserB + expectedUserA))
+		})
+		It("should remove the identities with the given groups, in any order", func() {
+			cm := removeAndSave(IdentityFilter{ARN: userA, Groups: []string{"tin-foil-hat-wearers", "cryptographers"}}, true)
+			Expect(cm.Data["mapUsers"]).To(MatchYAML(expectedUserB + makeExpectedUser(userA, "alice-admin", "system:masters")))
+		})
+		It("should remove the identity at the given index", func() {
+			index := 1
+			cm := removeAndSave(IdentityFilter{ARN: userA, Index: &index}, false)
+			Expect(cm.Data["mapUsers"]).To(MatchYAML(expectedUserA + expectedUserB + expectedUserA))
+		})
+		It("should fail if no identity matches", func() {
+			index := 3
+			Expect(acm.RemoveMatchingIdentities(IdentityFilter{ARN: userA, Index: &index}, false)).To(MatchError(ContainSubstring("index 3")))
+			Expect(acm.RemoveMatchingIdentities(IdentityFilter{ARN: userA, Username: "eve"}, false)).To(MatchError(ContainSubstring(`username "eve"`)))
+		})
+	})
+	Describe("unknown fields", func() {
+		It("should keep the fields of mappings that eksctl doesn't know about", func() {
+			roleWithUnknownFields := expectedRoleB + "  comment: added by hand\n  extra:\n    key: value\n"
+			existing := &corev1.ConfigMap{
+				ObjectMeta: ObjectMeta(),
+				Data: map[string]string{
+					"mapRoles": expectedRoleA + roleWithUnknownFields,
+					"mapUsers": expectedUserA,
+				},
+			}
+			existing.UID = "123456"
+			client := &mockClient{}
+			acm := New(client, existing)
+
+			identities, err := acm.Identities()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(identities[1].UnknownFields()).To(Equal([]string{"comment", "extra"}))
+
+			Expect(acm.RemoveIdentity(roleA, false)).To(Succeed())
+			Expect(acm.AddIdentity(mustIdentity(userB, userBUsername, userBGroups))).To(Succeed())
+			Expect(acm.Save()).To(Succeed())
+
+			Expect(client.updated.Data["mapRoles"]).To(MatchYAML(roleWithUnknownFields))
+			Expect(client.updated.Data["mapUsers"]).To(MatchYAML(expectedUserA + expectedUserB))
+		})
+	})
 	Describe("AddAccount()", func() {
 		existing := &corev1.ConfigMap{
 			ObjectMeta: ObjectMeta(),
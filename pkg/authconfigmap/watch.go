@@ -0,0 +1,129 @@
+package authconfigmap
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// IdentityEventType is the type of change of an identity mapping
+type IdentityEventType string
+
+const (
+	// IdentityAdded is sent when a mapping is added to the auth ConfigMap
+	IdentityAdded IdentityEventType = "Added"
+	// IdentityRemoved is sent when a mapping is removed from the auth ConfigMap
+	IdentityRemoved IdentityEventType = "Removed"
+)
+
+// IdentityEvent is a change of an identity mapping of the auth ConfigMap
+type IdentityEvent struct {
+	Type     IdentityEventType
+	Identity iam.Identity
+}
+
+// Watch watches the auth ConfigMap in the cluster and sends the identity mappings
+// added to or removed from it, compared to the mappings it was loaded with. A
+// modified mapping is sent as the removal of the old one followed by the addition
+// of the new one. The channel is closed once ctx is done.
+func (a *AuthConfigMap) Watch(ctx context.Context) (<-chan IdentityEvent, error) {
+	current, err := a.Identities()
+	if err != nil {
+		return nil, err
+	}
+
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", ObjectName).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return a.client.List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return a.client.Watch(options)
+		},
+	}
+
+	events := make(chan IdentityEvent)
+
+	// the handlers are called sequentially by the informer, so current needs no locking
+	update := func(identities []iam.Identity) {
+		for _, event := range diffIdentities(current, identities) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		current = identities
+	}
+	updateFrom := func(obj interface{}) {
+		cm, ok := obj.(*corev1.ConfigMap)
+		if !ok {
+			return
+		}
+		identities, err := New(a.client, cm.DeepCopy()).Identities()
+		if err != nil {
+			logger.Warning("ignoring change of auth ConfigMap: %v", err)
+			return
+		}
+		update(identities)
+	}
+
+	_, controller := cache.NewInformer(listWatch, &corev1.ConfigMap{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: updateFrom,
+		UpdateFunc: func(_, obj interface{}) {
+			updateFrom(obj)
+		},
+		DeleteFunc: func(_ interface{}) {
+			update(nil)
+		},
+	})
+
+	go func() {
+		controller.Run(ctx.Done())
+		close(events)
+	}()
+
+	return events, nil
+}
+
+// diffIdentities returns the events turning previous into current
+func diffIdentities(previous, current []iam.Identity) []IdentityEvent {
+	previousKeys, currentKeys := identityKeys(previous), identityKeys(current)
+
+	var events []IdentityEvent
+	for _, identity := range previous {
+		if !currentKeys.Has(identityKey(identity)) {
+			events = append(events, IdentityEvent{Type: IdentityRemoved, Identity: identity})
+		}
+	}
+	for _, identity := range current {
+		if !previousKeys.Has(identityKey(identity)) {
+			events = append(events, IdentityEvent{Type: IdentityAdded, Identity: identity})
+		}
+	}
+	return events
+}
+
+func identityKeys(identities []iam.Identity) sets.String {
+	keys := sets.NewString()
+	for _, identity := range identities {
+		keys.Insert(identityKey(identity))
+	}
+	return keys
+}
+
+func identityKey(identity iam.Identity) string {
+	return strings.Join([]string{identity.Type(), identity.ARN(), identity.Username(), strings.Join(identity.Groups(), ",")}, "\n")
+}
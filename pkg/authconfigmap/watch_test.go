@@ -0,0 +1,80 @@
+package authconfigmap_test
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/weaveworks/eksctl/pkg/authconfigmap"
+)
+
+var _ = Describe("AuthConfigMap Watch()", func() {
+	var (
+		clientSet *fake.Clientset
+		acm       *AuthConfigMap
+		events    <-chan IdentityEvent
+		cancel    context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: ObjectMeta(),
+			Data: map[string]string{
+				"mapRoles": makeExpectedRole(roleA, RoleNodeGroupGroups),
+			},
+		})
+
+		var err error
+		acm, err = NewFromClientSet(clientSet)
+		Expect(err).ToNot(HaveOccurred())
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		events, err = acm.Watch(ctx)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(events).Should(BeClosed())
+	})
+
+	It("sends the mappings added out-of-band", func() {
+		Expect(acm.AddIdentity(mustIdentity(userA, userAUsername, userAGroups))).To(Succeed())
+		Expect(acm.Save()).To(Succeed())
+
+		var event IdentityEvent
+		Eventually(events, 5*time.Second).Should(Receive(&event))
+		Expect(event.Type).To(Equal(IdentityAdded))
+		Expect(event.Identity.ARN()).To(Equal(userA))
+		Consistently(events, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("sends a modified mapping as a removal and an addition", func() {
+		Expect(acm.RemoveIdentity(roleA, false)).To(Succeed())
+		Expect(acm.AddIdentity(mustIdentity(roleA, RoleNodeGroupUsername, []string{groupB}))).To(Succeed())
+		Expect(acm.Save()).To(Succeed())
+
+		var removed, added IdentityEvent
+		Eventually(events, 5*time.Second).Should(Receive(&removed))
+		Expect(removed.Type).To(Equal(IdentityRemoved))
+		Expect(removed.Identity.Groups()).To(Equal(RoleNodeGroupGroups))
+		Eventually(events, 5*time.Second).Should(Receive(&added))
+		Expect(added.Type).To(Equal(IdentityAdded))
+		Expect(added.Identity.Groups()).To(Equal([]string{groupB}))
+	})
+
+	It("sends all mappings as removed when the ConfigMap is deleted", func() {
+		Expect(clientSet.CoreV1().ConfigMaps(ObjectNamespace).Delete(ObjectName, &metav1.DeleteOptions{})).To(Succeed())
+
+		var event IdentityEvent
+		Eventually(events, 5*time.Second).Should(Receive(&event))
+		Expect(event.Type).To(Equal(IdentityRemoved))
+		Expect(event.Identity.ARN()).To(Equal(roleA))
+	})
+})
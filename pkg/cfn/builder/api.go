@@ -15,6 +15,9 @@ const (
 	templateDescriptionSuffix    = "[created and managed by eksctl]"
 )
 
+// awsCloudFormationResource is a resource rendered as it is; like it, the unexported resource
+// and property types of this package stand in for the ones of the version of goformation in
+// use, which predates them or some of their properties
 type awsCloudFormationResource struct {
 	Type         string
 	Properties   map[string]interface{}
@@ -100,6 +103,18 @@ func (r *resourceSet) newResource(name string, resource interface{}) *gfn.Value
 	return gfn.MakeRef(name)
 }
 
+// newParameter adds a template parameter with a default value, it returns a reference
+func (r *resourceSet) newParameter(name, parameterType, defaultValue string) *gfn.Value {
+	if r.template.Parameters == nil {
+		r.template.Parameters = map[string]interface{}{}
+	}
+	r.template.Parameters[name] = map[string]string{
+		"Type":    parameterType,
+		"Default": defaultValue,
+	}
+	return gfn.MakeRef(name)
+}
+
 // renderJSON renders template as JSON
 func (r *resourceSet) renderJSON() ([]byte, error) {
 	return r.template.JSON()
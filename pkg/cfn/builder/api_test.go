@@ -72,10 +72,17 @@ type Properties struct {
 
 	VPCZoneIdentifier interface{}
 
+	LifecycleHookSpecificationList []struct {
+		LifecycleHookName, LifecycleTransition string
+	}
+
 	LoadBalancerNames                 []string
 	TargetGroupARNs                   []string
 	DesiredCapacity, MinSize, MaxSize string
 
+	AutoScalingGroupName                interface{}
+	MaxGroupPreparedCapacity, PoolState string
+
 	CidrIp, CidrIpv6, IpProtocol string
 	FromPort, ToPort             int
 
@@ -83,6 +90,7 @@ type Properties struct {
 	RouteTableId, AllocationId                 interface{}
 	GatewayId, InternetGatewayId, NatGatewayId interface{}
 	DestinationCidrBlock                       interface{}
+	TransitGatewayId, InstanceId               string
 
 	Ipv6CidrBlock map[string][]interface{}
 
@@ -90,6 +98,7 @@ type Properties struct {
 	AvailabilityZone, Domain, CidrBlock string
 
 	Name, Version      string
+	Strategy           string
 	RoleArn            interface{}
 	ResourcesVpcConfig struct {
 		SecurityGroupIds []interface{}
@@ -123,6 +132,15 @@ type LaunchTemplateData struct {
 	NetworkInterfaces               []struct {
 		DeviceIndex              int
 		AssociatePublicIpAddress bool
+		NetworkCardIndex         *int
+		InterfaceType            string
+		Groups                   []interface{}
+		EnaSrdSpecification      *struct {
+			EnaSrdEnabled          bool
+			EnaSrdUdpSpecification *struct {
+				EnaSrdUdpEnabled bool
+			}
+		}
 	}
 	InstanceMarketOptions *struct {
 		MarketType  string
@@ -131,11 +149,36 @@ type LaunchTemplateData struct {
 			MaxPrice         string
 		}
 	}
+	MetadataOptions *struct {
+		HttpTokens              string
+		HttpPutResponseHopLimit int
+	}
+	Placement *struct {
+		GroupName interface{}
+		Tenancy   string
+	}
+	CapacityReservationSpecification *struct {
+		CapacityReservationPreference string
+		CapacityReservationTarget     *struct {
+			CapacityReservationId               string
+			CapacityReservationResourceGroupArn string
+		}
+	}
+	TagSpecifications []struct {
+		ResourceType string
+		Tags         []struct {
+			Key   string
+			Value string
+		}
+	}
 }
 
 type Template struct {
 	Description string
-	Resources   map[string]struct{ Properties Properties }
+	Resources   map[string]struct {
+		Properties Properties
+		DependsOn  []string
+	}
 }
 
 var kubeconfigTemplate = template.Must(template.New("kubeconfig").Parse(`apiVersion: v1
@@ -325,9 +368,10 @@ func completeKubeletConfig(kubeletConfigAssetContent []byte, clusterDNS string)
 
 var _ = Describe("CloudFormation template builder API", func() {
 	var (
-		cc   *cloudconfig.CloudConfig
-		crs  *ClusterResourceSet
-		ngrs *NodeGroupResourceSet
+		cc      *cloudconfig.CloudConfig
+		scripts []string
+		crs     *ClusterResourceSet
+		ngrs    *NodeGroupResourceSet
 
 		clusterTemplate, ngTemplate *Template
 
@@ -590,7 +634,7 @@ var _ = Describe("CloudFormation template builder API", func() {
 		It("should extract valid cloud-config using our implementation", func() {
 			userData := getLaunchTemplateData(ngTemplate).UserData
 			Expect(userData).ToNot(BeEmpty())
-			cc, err = cloudconfig.DecodeCloudConfig(userData)
+			cc, scripts, err = cloudconfig.DecodeMultiPart(userData)
 			Expect(err).ShouldNot(HaveOccurred())
 
 		})
@@ -674,6 +718,100 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("NodeGroup{CloudFormationParameters={MaxSize=5}}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.DesiredCapacity = nil
+		ng.MaxSize = nil
+		ng.MinSize = nil
+
+		ng.InstanceType = "m5.2xlarge"
+		ng.CloudFormationParameters = map[string]string{"MaxSize": "5"}
+
+		build(cfg, "eksctl-test1-cluster", ng)
+
+		It("should expose the sizes and instance type as parameters", func() {
+			templateBody, err := ngrs.RenderJSON()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			t := struct {
+				Parameters map[string]map[string]string
+				Resources  map[string]struct{ Properties map[string]interface{} }
+			}{}
+			Expect(json.Unmarshal(templateBody, &t)).To(Succeed())
+
+			Expect(t.Parameters).To(HaveLen(3))
+			Expect(t.Parameters).To(HaveKeyWithValue("MinSize", map[string]string{"Type": "Number", "Default": "2"}))
+			Expect(t.Parameters).To(HaveKeyWithValue("MaxSize", map[string]string{"Type": "Number", "Default": "2"}))
+			Expect(t.Parameters).To(HaveKeyWithValue("InstanceType", map[string]string{"Type": "String", "Default": "m5.2xlarge"}))
+
+			ngProps := t.Resources["NodeGroup"].Properties
+			Expect(ngProps).ToNot(HaveKey("DesiredCapacity"))
+			isRefTo(ngProps["MinSize"], "MinSize")
+			isRefTo(ngProps["MaxSize"], "MaxSize")
+
+			launchTemplateData := t.Resources["NodeGroupLaunchTemplate"].Properties["LaunchTemplateData"].(map[string]interface{})
+			isRefTo(launchTemplateData["InstanceType"], "InstanceType")
+		})
+	})
+
+	Context("NodeGroup{CloudFormationParameters={DesiredCapacity=5}} without DesiredCapacity", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.DesiredCapacity = nil
+		ng.CloudFormationParameters = map[string]string{"DesiredCapacity": "5"}
+
+		It("should reject the parameter", func() {
+			ngrs := NewNodeGroupResourceSet(p, cfg, "eksctl-test1-cluster", ng, false)
+			err := ngrs.AddAllResources()
+			Expect(err).To(MatchError(`unsupported CloudFormation parameter "DesiredCapacity" for nodegroup "ng-abcd1234", supported parameters are: MinSize, MaxSize, InstanceType`))
+		})
+	})
+
+	Context("NodeGroup{CloudFormationParameters={MinSize=5}} with MaxSize=3", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.DesiredCapacity = nil
+		ng.MinSize = nil
+		ng.MaxSize = new(int)
+		*ng.MaxSize = 3
+		ng.CloudFormationParameters = map[string]string{"MinSize": "5"}
+
+		It("should reject the parameter", func() {
+			ngrs := NewNodeGroupResourceSet(p, cfg, "eksctl-test1-cluster", ng, false)
+			err := ngrs.AddAllResources()
+			Expect(err).To(MatchError(`MinSize (5) cannot be greater than MaxSize (3) for nodegroup "ng-abcd1234"`))
+		})
+	})
+
+	Context("NodeGroup{CloudFormationParameters={MaxSize=2}} with DesiredCapacity=3", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.DesiredCapacity = new(int)
+		*ng.DesiredCapacity = 3
+		ng.MinSize = nil
+		ng.MaxSize = nil
+		ng.CloudFormationParameters = map[string]string{"MaxSize": "2", "MinSize": "1"}
+
+		It("should reject the parameters", func() {
+			ngrs := NewNodeGroupResourceSet(p, cfg, "eksctl-test1-cluster", ng, false)
+			err := ngrs.AddAllResources()
+			Expect(err).To(MatchError(`DesiredCapacity (3) must be between MinSize (1) and MaxSize (2) for nodegroup "ng-abcd1234"`))
+		})
+	})
+
+	Context("NodeGroup{CloudFormationParameters={MaxSize=many}}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.CloudFormationParameters = map[string]string{"MaxSize": "many"}
+
+		It("should reject the parameter", func() {
+			ngrs := NewNodeGroupResourceSet(p, cfg, "eksctl-test1-cluster", ng, false)
+			err := ngrs.AddAllResources()
+			Expect(err).To(MatchError(`invalid value "many" of CloudFormation parameter "MaxSize" for nodegroup "ng-abcd1234", it must be a non-negative integer`))
+		})
+	})
+
 	Context("NodeGroup DesiredCapacity=10 MaxSize=nil MinSize=nil", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -1578,6 +1716,59 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("Nodegroup{VolumeType=gp3 VolumeIOPS=4000 VolumeThroughput=250}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		volumeType := api.NodeVolumeTypeGP3
+		iops, throughput := 4000, 250
+		ng.VolumeType = &volumeType
+		ng.VolumeIOPS = &iops
+		ng.VolumeThroughput = &throughput
+
+		build(cfg, "eksctl-test-private-ng", ng)
+
+		roundtrip()
+
+		It("should have correct resources and attributes", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.BlockDeviceMappings).To(HaveLen(1))
+
+			rootVolume := ltd.BlockDeviceMappings[0].(map[string]interface{})
+			Expect(rootVolume).To(HaveKey("Ebs"))
+			Expect(rootVolume["Ebs"].(map[string]interface{})).To(HaveKeyWithValue("VolumeType", "gp3"))
+			Expect(rootVolume["Ebs"].(map[string]interface{})).To(HaveKeyWithValue("Iops", 4000.0))
+			Expect(rootVolume["Ebs"].(map[string]interface{})).To(HaveKeyWithValue("Throughput", 250.0))
+		})
+	})
+
+	Context("Nodegroup{AdditionalVolumes}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		volumeName, volumeSize, volumeType := "/dev/xvdb", 500, api.NodeVolumeTypeIO1
+		iops := 1000
+		ng.AdditionalVolumes = []*api.NodeGroupVolume{{
+			VolumeName: &volumeName,
+			VolumeSize: &volumeSize,
+			VolumeType: &volumeType,
+			VolumeIOPS: &iops,
+		}}
+
+		build(cfg, "eksctl-test-private-ng", ng)
+
+		roundtrip()
+
+		It("should map the additional volumes after the root volume", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.BlockDeviceMappings).To(HaveLen(2))
+
+			additionalVolume := ltd.BlockDeviceMappings[1].(map[string]interface{})
+			Expect(additionalVolume).To(HaveKeyWithValue("DeviceName", "/dev/xvdb"))
+			Expect(additionalVolume["Ebs"].(map[string]interface{})).To(HaveKeyWithValue("VolumeType", "io1"))
+			Expect(additionalVolume["Ebs"].(map[string]interface{})).To(HaveKeyWithValue("VolumeSize", 500.0))
+			Expect(additionalVolume["Ebs"].(map[string]interface{})).To(HaveKeyWithValue("Iops", 1000.0))
+		})
+	})
+
 	Context("NodeGroup{PrivateNetworking=true SSH.Allow=true}", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -1775,6 +1966,206 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("NodeGroup{Subnets=[subnet-1]}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.Subnets = []string{"subnet-0123456789abcdef0"}
+		ng.AvailabilityZones = []string{"us-west-2b"}
+		ng.PrivateNetworking = true
+
+		build(cfg, "eksctl-test-ng-in-subnet", ng)
+
+		roundtrip()
+
+		It("should launch the nodes in the given subnet", func() {
+			x, ok := ngTemplate.Resources["NodeGroup"].Properties.VPCZoneIdentifier.([]interface{})
+			Expect(ok).To(BeTrue())
+			Expect(x).To(Equal([]interface{}{"subnet-0123456789abcdef0"}))
+		})
+	})
+
+	Context("NodeGroup{Placement.Strategy=cluster Tenancy=dedicated}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.AvailabilityZones = []string{"us-west-2a"}
+		ng.Placement = &api.NodeGroupPlacement{Strategy: "cluster"}
+		ng.Tenancy = "dedicated"
+
+		build(cfg, "eksctl-test-placement-group", ng)
+
+		roundtrip()
+
+		It("should create a placement group for the instances", func() {
+			Expect(ngTemplate.Resources).To(HaveKey("PlacementGroup"))
+			Expect(ngTemplate.Resources["PlacementGroup"].Properties.Strategy).To(Equal("cluster"))
+
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.Placement).NotTo(BeNil())
+			Expect(ltd.Placement.GroupName).To(Equal(map[string]interface{}{"Ref": "PlacementGroup"}))
+			Expect(ltd.Placement.Tenancy).To(Equal("dedicated"))
+		})
+	})
+
+	Context("NodeGroup{Placement.GroupName=hpc}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.Placement = &api.NodeGroupPlacement{GroupName: "hpc"}
+
+		build(cfg, "eksctl-test-existing-placement-group", ng)
+
+		roundtrip()
+
+		It("should use the existing placement group", func() {
+			Expect(ngTemplate.Resources).NotTo(HaveKey("PlacementGroup"))
+
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.Placement).NotTo(BeNil())
+			Expect(ltd.Placement.GroupName).To(Equal("hpc"))
+			Expect(ltd.Placement.Tenancy).To(BeEmpty())
+		})
+	})
+
+	Context("NodeGroup{EFAEnabled=true}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.InstanceType = "c5n.18xlarge"
+		ng.AvailabilityZones = []string{"us-west-2a"}
+		ng.EFAEnabled = api.Enabled()
+
+		build(cfg, "eksctl-test-efa", ng)
+
+		roundtrip()
+
+		It("should allow all traffic between the instances", func() {
+			Expect(ngTemplate.Resources).To(HaveKey("EFASG"))
+			Expect(ngTemplate.Resources).To(HaveKey("EFAIngressSelf"))
+			Expect(ngTemplate.Resources).To(HaveKey("EFAEgressSelf"))
+			Expect(ngTemplate.Resources["EFAIngressSelf"].Properties.IpProtocol).To(Equal("-1"))
+			Expect(ngTemplate.Resources["EFAEgressSelf"].Properties.IpProtocol).To(Equal("-1"))
+		})
+
+		It("should attach an EFA interface in the EFA security group", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.NetworkInterfaces).To(HaveLen(1))
+			Expect(ltd.NetworkInterfaces[0].InterfaceType).To(Equal("efa"))
+			Expect(ltd.NetworkInterfaces[0].Groups).To(ContainElement(map[string]interface{}{"Ref": "EFASG"}))
+		})
+	})
+
+	Context("NodeGroup{CapacityReservation.ID=cr-1 CapacityBlock=true}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.InstanceType = "p5.48xlarge"
+		ng.CapacityReservation = &api.NodeGroupCapacityReservation{
+			ID:            "cr-0123456789abcdef0",
+			CapacityBlock: api.Enabled(),
+		}
+
+		build(cfg, "eksctl-test-capacity-block", ng)
+
+		roundtrip()
+
+		It("should launch the instances in the Capacity Block", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.CapacityReservationSpecification).NotTo(BeNil())
+			Expect(ltd.CapacityReservationSpecification.CapacityReservationPreference).To(BeEmpty())
+			Expect(ltd.CapacityReservationSpecification.CapacityReservationTarget.CapacityReservationId).To(Equal("cr-0123456789abcdef0"))
+			Expect(ltd.InstanceMarketOptions).NotTo(BeNil())
+			Expect(ltd.InstanceMarketOptions.MarketType).To(Equal("capacity-block"))
+		})
+	})
+
+	Context("NodeGroup{CapacityReservation.Preference=none}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.CapacityReservation = &api.NodeGroupCapacityReservation{Preference: "none"}
+
+		build(cfg, "eksctl-test-capacity-reservation-preference", ng)
+
+		roundtrip()
+
+		It("should only set the preference", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.CapacityReservationSpecification).NotTo(BeNil())
+			Expect(ltd.CapacityReservationSpecification.CapacityReservationPreference).To(Equal("none"))
+			Expect(ltd.CapacityReservationSpecification.CapacityReservationTarget).To(BeNil())
+			Expect(ltd.InstanceMarketOptions).To(BeNil())
+		})
+	})
+
+	Context("NodeGroup{Tags} with metadata.tags", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Tags = map[string]string{"team": "platform", "cost-center": "1234"}
+		ng.Tags = map[string]string{"team": "ml"}
+
+		build(cfg, "eksctl-test-launch-tags", ng)
+
+		roundtrip()
+
+		It("should tag the instances and volumes, the nodegroup tags taking precedence", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.TagSpecifications).To(HaveLen(2))
+			for i, resourceType := range []string{"instance", "volume"} {
+				spec := ltd.TagSpecifications[i]
+				Expect(spec.ResourceType).To(Equal(resourceType))
+				Expect(spec.Tags).To(HaveLen(2))
+				Expect(spec.Tags[0].Key).To(Equal("cost-center"))
+				Expect(spec.Tags[0].Value).To(Equal("1234"))
+				Expect(spec.Tags[1].Key).To(Equal("team"))
+				Expect(spec.Tags[1].Value).To(Equal("ml"))
+			}
+		})
+	})
+
+	Context("NodeGroup{WarmPool.MinSize=1 MaxPrepared=5 State=Stopped}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		minSize, maxPrepared := 1, 5
+		ng.WarmPool = &api.NodeGroupWarmPool{
+			MinSize:     &minSize,
+			MaxPrepared: &maxPrepared,
+			State:       api.WarmPoolStateStopped,
+		}
+
+		build(cfg, "eksctl-test-warm-pool", ng)
+
+		roundtrip()
+
+		It("should add a warm pool to the Auto Scaling Group", func() {
+			Expect(ngTemplate.Resources).To(HaveKey("WarmPool"))
+			warmPool := ngTemplate.Resources["WarmPool"]
+			Expect(warmPool.Properties.AutoScalingGroupName).To(Equal(map[string]interface{}{"Ref": "NodeGroup"}))
+			Expect(warmPool.Properties.MinSize).To(Equal("1"))
+			Expect(warmPool.Properties.MaxGroupPreparedCapacity).To(Equal("5"))
+			Expect(warmPool.Properties.PoolState).To(Equal("Stopped"))
+		})
+	})
+
+	Context("NodeGroup{InstancesDistribution.OnDemandPercentageAboveBaseCapacity=0} with NodeTerminationHandler.Mode=queue", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.IAM.WithOIDC = api.Enabled()
+		cfg.NodeTerminationHandler = &api.ClusterNodeTerminationHandler{Mode: api.NodeTerminationHandlerModeQueue}
+		ng.InstanceType = "mixed"
+		ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{
+			InstanceTypes:                       []string{"t3.medium", "t3a.medium"},
+			OnDemandPercentageAboveBaseCapacity: new(int),
+		}
+
+		build(cfg, "eksctl-test-spot-cluster", ng)
+
+		roundtrip()
+
+		It("should let aws-node-termination-handler drain the terminating instances", func() {
+			props := ngTemplate.Resources["NodeGroup"].Properties
+			Expect(props.LifecycleHookSpecificationList).To(HaveLen(1))
+			Expect(props.LifecycleHookSpecificationList[0].LifecycleHookName).To(Equal("NodeTerminationHandler"))
+			Expect(props.LifecycleHookSpecificationList[0].LifecycleTransition).To(Equal("autoscaling:EC2_INSTANCE_TERMINATING"))
+			Expect(props.Tags).To(ContainElement(Tag{Key: "aws-node-termination-handler/managed", Value: "true", PropagateAtLaunch: "true"}))
+		})
+	})
+
 	Context("NodeGroup{EBSOptimized=nil}", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -1955,12 +2346,10 @@ var _ = Describe("CloudFormation template builder API", func() {
 
 			checkScript(cc, "/var/lib/cloud/scripts/per-instance/bootstrap.al2.sh", true)
 
-			Expect(cc.Commands).To(HaveLen(len(ng.PreBootstrapCommands) + 1))
+			Expect(cc.Commands).To(HaveLen(1))
+			Expect(scripts).To(HaveLen(len(ng.PreBootstrapCommands)))
 			for i, cmd := range ng.PreBootstrapCommands {
-				c := cc.Commands[i].([]interface{})
-				Expect(c[0]).To(Equal("/bin/bash"))
-				Expect(c[1]).To(Equal("-c"))
-				Expect(c[2]).To(Equal(cmd))
+				Expect(scripts[i]).To(Equal("#!/bin/bash\n" + cmd + "\n"))
 			}
 		})
 	})
@@ -1986,6 +2375,20 @@ var _ = Describe("CloudFormation template builder API", func() {
 
 		extractCloudConfig()
 
+		It("should have node template tags for cluster-autoscaler", func() {
+			ngProps := getNodeGroupProperties(ngTemplate)
+			Expect(ngProps.Tags).To(ContainElement(Tag{
+				Key:               "k8s.io/cluster-autoscaler/node-template/label/os",
+				Value:             "al2",
+				PropagateAtLaunch: "false",
+			}))
+			Expect(ngProps.Tags).To(ContainElement(Tag{
+				Key:               "k8s.io/cluster-autoscaler/node-template/taint/key1",
+				Value:             "value1:NoSchedule",
+				PropagateAtLaunch: "false",
+			}))
+		})
+
 		It("should have packages, scripts and commands in cloud-config", func() {
 			Expect(cc.Packages).Should(BeEmpty())
 
@@ -2072,19 +2475,17 @@ var _ = Describe("CloudFormation template builder API", func() {
 			script := getFile(cc, "/var/lib/cloud/scripts/per-instance/bootstrap.al2.sh")
 			Expect(script).To(BeNil())
 
-			Expect(cc.Commands).To(HaveLen(4))
+			Expect(cc.Commands).To(HaveLen(1))
 			Expect(cc.Commands[0]).To(HaveLen(3))
 
+			Expect(scripts).To(HaveLen(len(ng.PreBootstrapCommands)))
 			for i, cmd := range ng.PreBootstrapCommands {
-				c := cc.Commands[i].([]interface{})
-				Expect(c[0]).To(Equal("/bin/bash"))
-				Expect(c[1]).To(Equal("-c"))
-				Expect(c[2]).To(Equal(cmd))
+				Expect(scripts[i]).To(Equal("#!/bin/bash\n" + cmd + "\n"))
 			}
 
-			Expect(cc.Commands[3].([]interface{})[0]).To(Equal("/bin/bash"))
-			Expect(cc.Commands[3].([]interface{})[1]).To(Equal("-c"))
-			Expect(cc.Commands[3].([]interface{})[2]).To(Equal(overrideBootstrapCommand))
+			Expect(cc.Commands[0].([]interface{})[0]).To(Equal("/bin/bash"))
+			Expect(cc.Commands[0].([]interface{})[1]).To(Equal("-c"))
+			Expect(cc.Commands[0].([]interface{})[2]).To(Equal(overrideBootstrapCommand))
 		})
 	})
 
@@ -2190,11 +2591,9 @@ var _ = Describe("CloudFormation template builder API", func() {
 
 			checkScript(cc, "/var/lib/cloud/scripts/per-instance/bootstrap.ubuntu.sh", true)
 
+			Expect(scripts).To(HaveLen(len(ng.PreBootstrapCommands)))
 			for i, cmd := range ng.PreBootstrapCommands {
-				c := cc.Commands[i].([]interface{})
-				Expect(c[0]).To(Equal("/bin/bash"))
-				Expect(c[1]).To(Equal("-c"))
-				Expect(c[2]).To(Equal(cmd))
+				Expect(scripts[i]).To(Equal("#!/bin/bash\n" + cmd + "\n"))
 			}
 		})
 	})
@@ -2317,20 +2716,18 @@ var _ = Describe("CloudFormation template builder API", func() {
 			script := getFile(cc, "/var/lib/cloud/scripts/per-instance/bootstrap.ubuntu.sh")
 			Expect(script).To(BeNil())
 
-			Expect(cc.Commands).To(HaveLen(4))
+			Expect(cc.Commands).To(HaveLen(1))
 			Expect(cc.Commands[0]).To(HaveLen(3))
 
+			Expect(scripts).To(HaveLen(len(ng.PreBootstrapCommands)))
 			for i, cmd := range ng.PreBootstrapCommands {
-				c := cc.Commands[i].([]interface{})
-				Expect(c[0]).To(Equal("/bin/bash"))
-				Expect(c[1]).To(Equal("-c"))
-				Expect(c[2]).To(Equal(cmd))
+				Expect(scripts[i]).To(Equal("#!/bin/bash\n" + cmd + "\n"))
 			}
 
-			c3 := cc.Commands[3].([]interface{})
-			Expect(c3[0]).To(Equal("/bin/bash"))
-			Expect(c3[1]).To(Equal("-c"))
-			Expect(c3[2]).To(Equal(overrideBootstrapCommand))
+			c0 := cc.Commands[0].([]interface{})
+			Expect(c0[0]).To(Equal("/bin/bash"))
+			Expect(c0[1]).To(Equal("-c"))
+			Expect(c0[2]).To(Equal(overrideBootstrapCommand))
 		})
 	})
 
@@ -2547,6 +2944,52 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("VPC with a transit gateway and extra routes", func() {
+		cfg, ng := newClusterConfigAndNodegroup(false)
+
+		cfg.Metadata.Name = "test-TGW"
+		disable := api.ClusterDisableNAT
+		cfg.VPC.NAT = &api.ClusterNAT{Gateway: &disable}
+		cfg.VPC.TransitGatewayID = "tgw-0123456789abcdef0"
+		cfg.VPC.ExtraRoutes = []api.VPCRoute{
+			{DestinationCIDR: "10.0.0.0/8"},
+			{DestinationCIDR: "172.16.0.0/12", InstanceID: "i-0123456789abcdef0"},
+		}
+
+		setSubnets(cfg)
+
+		build(cfg, "eksctl-test-TGW-cluster", ng)
+
+		roundtrip()
+
+		It("should attach the VPC to the transit gateway instead of creating NAT gateways", func() {
+			Expect(clusterTemplate.Resources).ToNot(HaveKey("NATGateway"))
+			Expect(clusterTemplate.Resources).To(HaveKey("TransitGatewayAttachment"))
+			attachment := clusterTemplate.Resources["TransitGatewayAttachment"].Properties
+			Expect(attachment.TransitGatewayId).To(Equal("tgw-0123456789abcdef0"))
+			isRefTo(attachment.VpcId, "VPC")
+		})
+
+		It("should add the routes to the private route table of each zone", func() {
+			for _, zone := range []string{"A", "B", "C"} {
+				suffix := "USWEST2" + zone
+
+				defaultRoute := clusterTemplate.Resources["PrivateSubnetRoute0"+suffix]
+				Expect(defaultRoute.Properties.DestinationCidrBlock).To(Equal("0.0.0.0/0"))
+				Expect(defaultRoute.Properties.TransitGatewayId).To(Equal("tgw-0123456789abcdef0"))
+				Expect(defaultRoute.DependsOn).To(Equal([]string{"TransitGatewayAttachment"}))
+				isRefTo(defaultRoute.Properties.RouteTableId, "PrivateRouteTable"+suffix)
+
+				Expect(clusterTemplate.Resources["PrivateSubnetRoute1"+suffix].Properties.TransitGatewayId).To(Equal("tgw-0123456789abcdef0"))
+
+				natInstanceRoute := clusterTemplate.Resources["PrivateSubnetRoute2"+suffix]
+				Expect(natInstanceRoute.Properties.DestinationCidrBlock).To(Equal("172.16.0.0/12"))
+				Expect(natInstanceRoute.Properties.InstanceId).To(Equal("i-0123456789abcdef0"))
+				Expect(natInstanceRoute.DependsOn).To(BeEmpty())
+			}
+		})
+	})
+
 	Context("VPC with custom CIDR and IPv6", func() {
 		cfg, ng := newClusterConfigAndNodegroup(false)
 
@@ -2831,6 +3274,50 @@ var _ = Describe("CloudFormation template builder API", func() {
 
 		})
 	})
+
+	Context("Nodegroup with IMDSv1 disabled", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.DisableIMDSv1 = api.Enabled()
+
+		build(cfg, "eksctl-test-imdsv2-cluster", ng)
+
+		roundtrip()
+
+		It("should require session tokens in the launch template", func() {
+			launchTemplateData := getLaunchTemplateData(ngTemplate)
+			Expect(launchTemplateData.MetadataOptions).NotTo(BeNil())
+			Expect(launchTemplateData.MetadataOptions.HttpTokens).To(Equal("required"))
+			Expect(launchTemplateData.MetadataOptions.HttpPutResponseHopLimit).To(Equal(2))
+		})
+	})
+
+	Context("Nodegroup with network interfaces on several network cards", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.InstanceType = "c6in.32xlarge"
+		ng.NetworkInterfaces = &api.NodeGroupNetworkInterfaces{
+			Count:         aws.Int(3),
+			EnaExpress:    api.Enabled(),
+			EnaExpressUDP: api.Enabled(),
+		}
+
+		build(cfg, "eksctl-test-network-interfaces-cluster", ng)
+
+		roundtrip()
+
+		It("should spread the interfaces over the network cards", func() {
+			interfaces := getLaunchTemplateData(ngTemplate).NetworkInterfaces
+			Expect(interfaces).To(HaveLen(3))
+
+			for i, expected := range []struct{ card, device int }{{0, 0}, {1, 1}, {0, 1}} {
+				Expect(*interfaces[i].NetworkCardIndex).To(Equal(expected.card))
+				Expect(interfaces[i].DeviceIndex).To(Equal(expected.device))
+				Expect(interfaces[i].EnaSrdSpecification.EnaSrdEnabled).To(BeTrue())
+				Expect(interfaces[i].EnaSrdSpecification.EnaSrdUdpSpecification.EnaSrdUdpEnabled).To(BeTrue())
+			}
+		})
+	})
 })
 
 func setSubnets(cfg *api.ClusterConfig) {
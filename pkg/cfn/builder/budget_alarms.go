@@ -0,0 +1,213 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
+)
+
+// interAZTransferUsageTypeGroup is the usage type group of the cost of the data
+// transferred between availability zones, there is no CloudWatch metric for it
+const interAZTransferUsageTypeGroup = "EC2: Data Transfer - Inter AZ"
+
+const bytesPerGigabyte = 1 << 30
+
+// These types exist because goformation does not support CloudWatch alarms
+// with metric math, nor AWS Budgets
+
+type cloudWatchAlarm struct {
+	AlarmDescription   string        `json:"AlarmDescription"`
+	AlarmActions       []*gfn.Value  `json:"AlarmActions"`
+	ComparisonOperator string        `json:"ComparisonOperator"`
+	EvaluationPeriods  int           `json:"EvaluationPeriods"`
+	Threshold          int64         `json:"Threshold"`
+	TreatMissingData   string        `json:"TreatMissingData"`
+	Metrics            []alarmMetric `json:"Metrics"`
+}
+
+type alarmMetric struct {
+	ID         string           `json:"Id"`
+	Expression string           `json:"Expression,omitempty"`
+	Label      string           `json:"Label,omitempty"`
+	MetricStat *alarmMetricStat `json:"MetricStat,omitempty"`
+	ReturnData bool             `json:"ReturnData"`
+}
+
+type alarmMetricStat struct {
+	Metric struct {
+		Namespace  string           `json:"Namespace"`
+		MetricName string           `json:"MetricName"`
+		Dimensions []alarmDimension `json:"Dimensions"`
+	} `json:"Metric"`
+	Period int    `json:"Period"`
+	Stat   string `json:"Stat"`
+}
+
+type alarmDimension struct {
+	Name  string     `json:"Name"`
+	Value *gfn.Value `json:"Value"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (a *cloudWatchAlarm) MarshalJSON() ([]byte, error) {
+	type Properties cloudWatchAlarm
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::CloudWatch::Alarm",
+		Properties: Properties(*a),
+	})
+}
+
+type budget struct {
+	Budget                       budgetData                 `json:"Budget"`
+	NotificationsWithSubscribers []budgetNotificationConfig `json:"NotificationsWithSubscribers"`
+}
+
+type budgetData struct {
+	BudgetName  *gfn.Value          `json:"BudgetName"`
+	BudgetType  string              `json:"BudgetType"`
+	TimeUnit    string              `json:"TimeUnit"`
+	BudgetLimit budgetSpend         `json:"BudgetLimit"`
+	CostFilters map[string][]string `json:"CostFilters"`
+}
+
+type budgetSpend struct {
+	Amount int    `json:"Amount"`
+	Unit   string `json:"Unit"`
+}
+
+type budgetNotificationConfig struct {
+	Notification struct {
+		NotificationType   string `json:"NotificationType"`
+		ComparisonOperator string `json:"ComparisonOperator"`
+		Threshold          int    `json:"Threshold"`
+		ThresholdType      string `json:"ThresholdType"`
+	} `json:"Notification"`
+	Subscribers []budgetSubscriber `json:"Subscribers"`
+}
+
+type budgetSubscriber struct {
+	SubscriptionType string     `json:"SubscriptionType"`
+	Address          *gfn.Value `json:"Address"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (b *budget) MarshalJSON() ([]byte, error) {
+	type Properties budget
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::Budgets::Budget",
+		Properties: Properties(*b),
+	})
+}
+
+// addResourcesForBudgetAlarms adds the alarms of the data processed by the NAT gateways
+// and the budget of the inter-AZ data transfer, which notify an SNS topic
+func (c *ClusterResourceSet) addResourcesForBudgetAlarms() {
+	alarms := c.spec.BudgetAlarms
+
+	var topic *gfn.Value
+	if alarms.SNSTopicARN != "" {
+		topic = gfn.NewString(alarms.SNSTopicARN)
+	} else {
+		topic = c.newResource("BudgetAlarmsTopic", &gfn.AWSSNSTopic{})
+		// AWS Budgets needs to be allowed to publish to the topic
+		c.newResource("BudgetAlarmsTopicPolicy", &gfn.AWSSNSTopicPolicy{
+			Topics: []*gfn.Value{topic},
+			PolicyDocument: cft.MakePolicyDocument(map[string]interface{}{
+				"Effect":    "Allow",
+				"Principal": map[string]string{"Service": "budgets.amazonaws.com"},
+				"Action":    "SNS:Publish",
+				"Resource":  topic,
+			}),
+		})
+		c.rs.defineOutputWithoutCollector(outputs.ClusterBudgetAlarmsTopic, topic, false)
+	}
+
+	for logicalID, description := range c.natGateways() {
+		c.newResource(logicalID+"BytesAlarm", natGatewayBytesAlarm(logicalID, description, *alarms.NATGatewayGigabytesPerDay, topic))
+	}
+
+	// the nodes are tagged with either of those tags, which must be activated as cost allocation tags
+	clusterName := c.spec.Metadata.Name
+	interAZTransfer := &budget{
+		Budget: budgetData{
+			BudgetName:  gfn.MakeFnSubString("${AWS::StackName}-inter-az-transfer"),
+			BudgetType:  "COST",
+			TimeUnit:    "MONTHLY",
+			BudgetLimit: budgetSpend{Amount: *alarms.InterAZTransferDollarsPerMonth, Unit: "USD"},
+			CostFilters: map[string][]string{
+				"UsageTypeGroup": {interAZTransferUsageTypeGroup},
+				"TagKeyValue": {
+					fmt.Sprintf("user:kubernetes.io/cluster/%s$owned", clusterName),
+					fmt.Sprintf("user:eks:cluster-name$%s", clusterName),
+				},
+			},
+		},
+	}
+	notification := budgetNotificationConfig{
+		Subscribers: []budgetSubscriber{{SubscriptionType: "SNS", Address: topic}},
+	}
+	notification.Notification.NotificationType = "ACTUAL"
+	notification.Notification.ComparisonOperator = "GREATER_THAN"
+	notification.Notification.Threshold = 100
+	notification.Notification.ThresholdType = "PERCENTAGE"
+	interAZTransfer.NotificationsWithSubscribers = []budgetNotificationConfig{notification}
+	c.newResource("InterAZTransferBudget", interAZTransfer)
+}
+
+// natGateways returns the logical IDs of the NAT gateways that eksctl creates,
+// along with a description of them
+func (c *ClusterResourceSet) natGateways() map[string]string {
+	if c.spec.VPC.ID != "" || c.spec.VPC.NAT == nil || c.spec.VPC.NAT.Gateway == nil {
+		return nil
+	}
+	switch *c.spec.VPC.NAT.Gateway {
+	case api.ClusterHighlyAvailableNAT:
+		natGateways := map[string]string{}
+		for _, az := range c.spec.AvailabilityZones {
+			alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
+			natGateways["NATGateway"+alphanumericUpperAZ] = fmt.Sprintf("the NAT gateway in %s", az)
+		}
+		return natGateways
+	case api.ClusterSingleNAT:
+		return map[string]string{"NATGateway": "the NAT gateway"}
+	default:
+		return nil
+	}
+}
+
+// natGatewayBytesAlarm fires when a NAT gateway processes more than the given gigabytes
+// in a day, NAT gateways being charged for the data they process in both directions
+func natGatewayBytesAlarm(logicalID, description string, gigabytesPerDay int, topic *gfn.Value) *cloudWatchAlarm {
+	metric := func(id, name string) alarmMetric {
+		stat := &alarmMetricStat{Period: 86400, Stat: "Sum"}
+		stat.Metric.Namespace = "AWS/NATGateway"
+		stat.Metric.MetricName = name
+		stat.Metric.Dimensions = []alarmDimension{{Name: "NatGatewayId", Value: gfn.MakeRef(logicalID)}}
+		return alarmMetric{ID: id, MetricStat: stat}
+	}
+	return &cloudWatchAlarm{
+		AlarmDescription:   fmt.Sprintf("%s processed more than %d GB in a day [created by eksctl]", description, gigabytesPerDay),
+		AlarmActions:       []*gfn.Value{topic},
+		ComparisonOperator: "GreaterThanThreshold",
+		EvaluationPeriods:  1,
+		Threshold:          int64(gigabytesPerDay) * bytesPerGigabyte,
+		TreatMissingData:   "notBreaching",
+		Metrics: []alarmMetric{
+			metric("outToDestination", "BytesOutToDestination"),
+			metric("inFromDestination", "BytesInFromDestination"),
+			{ID: "processed", Expression: "outToDestination + inFromDestination", Label: "BytesProcessed", ReturnData: true},
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package builder_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tidwall/gjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+var _ = Describe("Budget alarms", func() {
+	var cfg *api.ClusterConfig
+
+	render := func() string {
+		api.SetClusterConfigDefaults(cfg)
+		crs := NewClusterResourceSet(mockprovider.NewMockProvider(), cfg, false, nil)
+		Expect(crs.AddAllResources()).To(Succeed())
+		template, err := crs.RenderJSON()
+		Expect(err).NotTo(HaveOccurred())
+		return string(template)
+	}
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "budget"
+		cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+		highlyAvailable := api.ClusterHighlyAvailableNAT
+		cfg.VPC.NAT = &api.ClusterNAT{Gateway: &highlyAvailable}
+		cfg.BudgetAlarms = &api.ClusterBudgetAlarms{}
+		Expect(vpc.SetSubnets(cfg)).To(Succeed())
+	})
+
+	It("adds alarms of the NAT gateways notifying a new topic", func() {
+		template := render()
+
+		Expect(gjson.Get(template, "Resources.BudgetAlarmsTopic.Type").String()).To(Equal("AWS::SNS::Topic"))
+		Expect(gjson.Get(template, "Outputs.BudgetAlarmsTopicARN").Exists()).To(BeTrue())
+
+		for _, natGateway := range []string{"NATGatewayUSWEST2A", "NATGatewayUSWEST2B"} {
+			alarm := gjson.Get(template, "Resources."+natGateway+"BytesAlarm")
+			Expect(alarm.Get("Type").String()).To(Equal("AWS::CloudWatch::Alarm"))
+			Expect(alarm.Get("Properties.Threshold").Int()).To(Equal(int64(api.DefaultNATGatewayGigabytesPerDay) << 30))
+			Expect(alarm.Get("Properties.AlarmActions.0.Ref").String()).To(Equal("BudgetAlarmsTopic"))
+			Expect(alarm.Get("Properties.Metrics.0.MetricStat.Metric.Dimensions.0.Value.Ref").String()).To(Equal(natGateway))
+		}
+	})
+
+	It("adds a budget of the inter-AZ data transfer of the nodes", func() {
+		cfg.BudgetAlarms.InterAZTransferDollarsPerMonth = new(int)
+		*cfg.BudgetAlarms.InterAZTransferDollarsPerMonth = 20
+		budget := gjson.Get(render(), "Resources.InterAZTransferBudget")
+
+		Expect(budget.Get("Type").String()).To(Equal("AWS::Budgets::Budget"))
+		Expect(budget.Get("Properties.Budget.BudgetLimit.Amount").Int()).To(Equal(int64(20)))
+		Expect(budget.Get("Properties.Budget.CostFilters.UsageTypeGroup.0").String()).To(Equal("EC2: Data Transfer - Inter AZ"))
+		Expect(budget.Get("Properties.Budget.CostFilters.TagKeyValue.0").String()).To(Equal("user:kubernetes.io/cluster/budget$owned"))
+	})
+
+	It("notifies the given topic and doesn't create one", func() {
+		cfg.BudgetAlarms.SNSTopicARN = "arn:aws:sns:us-west-2:123456789012:alarms"
+		template := render()
+
+		Expect(gjson.Get(template, "Resources.BudgetAlarmsTopic").Exists()).To(BeFalse())
+		Expect(gjson.Get(template, "Resources.NATGatewayUSWEST2ABytesAlarm.Properties.AlarmActions.0").String()).To(Equal(cfg.BudgetAlarms.SNSTopicARN))
+		Expect(gjson.Get(template, "Resources.InterAZTransferBudget.Properties.NotificationsWithSubscribers.0.Subscribers.0.Address").String()).To(Equal(cfg.BudgetAlarms.SNSTopicARN))
+	})
+
+	It("doesn't add alarms when there are no NAT gateways", func() {
+		disable := api.ClusterDisableNAT
+		cfg.VPC.NAT.Gateway = &disable
+		template := render()
+
+		Expect(gjson.Get(template, "Resources.NATGatewayUSWEST2ABytesAlarm").Exists()).To(BeFalse())
+		Expect(gjson.Get(template, "Resources.InterAZTransferBudget").Exists()).To(BeTrue())
+	})
+})
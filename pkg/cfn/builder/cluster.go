@@ -22,6 +22,7 @@ type ClusterResourceSet struct {
 	supportsManagedNodes bool
 	vpc                  *gfn.Value
 	subnets              map[api.SubnetTopology][]*gfn.Value
+	localZoneSubnets     []*gfn.Value
 	securityGroups       []*gfn.Value
 }
 
@@ -76,6 +77,14 @@ func (c *ClusterResourceSet) AddAllResources() error {
 		c.addResourcesForFargate()
 	}
 
+	if c.spec.BudgetAlarms != nil {
+		c.addResourcesForBudgetAlarms()
+	}
+
+	if c.spec.HasNodeTerminationHandlerQueue() {
+		c.addResourcesForNodeTerminationHandler()
+	}
+
 	c.rs.defineOutput(outputs.ClusterStackName, gfn.RefStackName, false, func(v string) error {
 		if c.spec.Status == nil {
 			c.spec.Status = &api.ClusterStatus{}
@@ -119,6 +128,7 @@ func (c *ClusterResourceSet) newResource(name string, resource interface{}) *gfn
 type awsEKSClusterKMS struct {
 	*awsEKSCluster   `json:",inline"`
 	EncryptionConfig []*encryptionConfig `json:"EncryptionConfig,omitempty"`
+	OutpostConfig    *outpostConfig      `json:"OutpostConfig,omitempty"`
 }
 
 func (e *awsEKSClusterKMS) MarshalJSON() ([]byte, error) {
@@ -142,6 +152,11 @@ type encryptionConfig struct {
 	Resources []string            `json:"Resources"`
 }
 
+type outpostConfig struct {
+	OutpostArns              []string `json:"OutpostArns"`
+	ControlPlaneInstanceType string   `json:"ControlPlaneInstanceType"`
+}
+
 type awsEKSCluster gfn.AWSEKSCluster
 
 func (c *ClusterResourceSet) addResourcesForControlPlane() {
@@ -169,6 +184,14 @@ func (c *ClusterResourceSet) addResourcesForControlPlane() {
 		}
 	}
 
+	var outpost *outpostConfig
+	if c.spec.IsLocalCluster() {
+		outpost = &outpostConfig{
+			OutpostArns:              []string{c.spec.Outpost.ControlPlaneOutpostARN},
+			ControlPlaneInstanceType: c.spec.Outpost.ControlPlaneInstanceType,
+		}
+	}
+
 	c.newResource("ControlPlane", &awsEKSClusterKMS{
 		awsEKSCluster: &awsEKSCluster{
 			Name:               gfn.NewString(c.spec.Metadata.Name),
@@ -177,6 +200,7 @@ func (c *ClusterResourceSet) addResourcesForControlPlane() {
 			ResourcesVpcConfig: clusterVPC,
 		},
 		EncryptionConfig: encryptionConfigs,
+		OutpostConfig:    outpost,
 	})
 
 	if c.spec.Status == nil {
@@ -3,6 +3,7 @@ package builder
 import (
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
 	gfn "github.com/awslabs/goformation/cloudformation"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
@@ -36,8 +37,8 @@ func AddResourcesForFargate(rs *resourceSet, cfg *api.ClusterConfig) error {
 		),
 	}
 
-	if api.IsSetAndNonEmptyString(cfg.IAM.FargatePodExecutionRolePermissionsBoundary) {
-		role.PermissionsBoundary = gfn.NewString(*cfg.IAM.FargatePodExecutionRolePermissionsBoundary)
+	if boundary := cfg.RolePermissionsBoundary(aws.StringValue(cfg.IAM.FargatePodExecutionRolePermissionsBoundary)); boundary != "" {
+		role.PermissionsBoundary = gfn.NewString(boundary)
 	}
 
 	rs.newResource(fargateRoleName, role)
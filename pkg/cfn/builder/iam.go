@@ -3,6 +3,7 @@ package builder
 import (
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	gfn "github.com/awslabs/goformation/cloudformation"
 
@@ -17,11 +18,16 @@ const (
 	iamPolicyAmazonEKSServicePolicy = "AmazonEKSServicePolicy"
 	iamPolicyAmazonEKSClusterPolicy = "AmazonEKSClusterPolicy"
 
+	iamPolicyAmazonEKSVPCResourceController = "AmazonEKSVPCResourceController"
+
+	iamPolicyAmazonEKSLocalOutpostClusterPolicy = "AmazonEKSLocalOutpostClusterPolicy"
+
 	iamPolicyAmazonEKSWorkerNodePolicy           = "AmazonEKSWorkerNodePolicy"
 	iamPolicyAmazonEKSCNIPolicy                  = "AmazonEKS_CNI_Policy"
 	iamPolicyAmazonEC2ContainerRegistryPowerUser = "AmazonEC2ContainerRegistryPowerUser"
 	iamPolicyAmazonEC2ContainerRegistryReadOnly  = "AmazonEC2ContainerRegistryReadOnly"
 	iamPolicyCloudWatchAgentServerPolicy         = "CloudWatchAgentServerPolicy"
+	iamPolicyAmazonSSMManagedInstanceCore        = "AmazonSSMManagedInstanceCore"
 
 	iamPolicyAmazonEKSFargatePodExecutionRolePolicy = "AmazonEKSFargatePodExecutionRolePolicy"
 )
@@ -83,8 +89,16 @@ func (c *ClusterResourceSet) addResourcesForIAM() {
 			iamPolicyAmazonEKSClusterPolicy,
 		),
 	}
-	if api.IsSetAndNonEmptyString(c.spec.IAM.ServiceRolePermissionsBoundary) {
-		role.PermissionsBoundary = gfn.NewString(*c.spec.IAM.ServiceRolePermissionsBoundary)
+	if c.spec.IsLocalCluster() {
+		// the control plane of local clusters runs on EC2 instances of the Outpost
+		role.AssumeRolePolicyDocument = cft.MakeAssumeRolePolicyDocumentForServices(MakeServiceRef("EC2"))
+		role.ManagedPolicyArns = makePolicyARNs(iamPolicyAmazonEKSLocalOutpostClusterPolicy)
+	} else if api.IsEnabled(c.spec.PodSecurityGroups) {
+		// allows the VPC resource controller to manage the trunk and branch network interfaces of the nodes
+		role.ManagedPolicyArns = append(role.ManagedPolicyArns, makePolicyARNs(iamPolicyAmazonEKSVPCResourceController)...)
+	}
+	if boundary := c.spec.RolePermissionsBoundary(aws.StringValue(c.spec.IAM.ServiceRolePermissionsBoundary)); boundary != "" {
+		role.PermissionsBoundary = gfn.NewString(boundary)
 	}
 	refSR := c.newResource("ServiceRole", role)
 	c.rs.attachAllowPolicy("PolicyNLB", refSR, "*", []string{
@@ -157,7 +171,7 @@ func (n *NodeGroupResourceSet) addResourcesForIAM() error {
 		n.rs.withNamedIAM = true
 	}
 
-	if err := createRole(n.rs, n.spec.IAM, false); err != nil {
+	if err := createRole(n.rs, n.clusterSpec, n.spec.IAM, n.spec.SSH, false); err != nil {
 		return err
 	}
 
@@ -17,8 +17,8 @@ type cfnTemplate interface {
 }
 
 // createRole creates an IAM role with policies required for the worker nodes and addons
-func createRole(cfnTemplate cfnTemplate, iamConfig *api.NodeGroupIAM, managed bool) error {
-	managedPolicyARNs, err := makeManagedPolicies(iamConfig, managed)
+func createRole(cfnTemplate cfnTemplate, clusterConfig *api.ClusterConfig, iamConfig *api.NodeGroupIAM, sshConfig *api.NodeGroupSSH, managed bool) error {
+	managedPolicyARNs, err := makeManagedPolicies(iamConfig, sshConfig, managed)
 	if err != nil {
 		return err
 	}
@@ -32,8 +32,8 @@ func createRole(cfnTemplate cfnTemplate, iamConfig *api.NodeGroupIAM, managed bo
 		role.RoleName = gfn.NewString(iamConfig.InstanceRoleName)
 	}
 
-	if iamConfig.InstanceRolePermissionsBoundary != "" {
-		role.PermissionsBoundary = gfn.NewString(iamConfig.InstanceRolePermissionsBoundary)
+	if boundary := clusterConfig.RolePermissionsBoundary(iamConfig.InstanceRolePermissionsBoundary); boundary != "" {
+		role.PermissionsBoundary = gfn.NewString(boundary)
 	}
 
 	refIR := cfnTemplate.newResource(cfnIAMInstanceRoleName, &role)
@@ -250,7 +250,7 @@ func createRole(cfnTemplate cfnTemplate, iamConfig *api.NodeGroupIAM, managed bo
 	return nil
 }
 
-func makeManagedPolicies(iamConfig *api.NodeGroupIAM, managed bool) ([]*gfn.Value, error) {
+func makeManagedPolicies(iamConfig *api.NodeGroupIAM, sshConfig *api.NodeGroupSSH, managed bool) ([]*gfn.Value, error) {
 	managedPolicyNames := sets.NewString()
 	if len(iamConfig.AttachPolicyARNs) == 0 {
 		managedPolicyNames.Insert(iamDefaultNodePolicies...)
@@ -274,6 +274,10 @@ func makeManagedPolicies(iamConfig *api.NodeGroupIAM, managed bool) ([]*gfn.Valu
 		managedPolicyNames.Insert(iamPolicyCloudWatchAgentServerPolicy)
 	}
 
+	if sshConfig != nil && api.IsEnabled(sshConfig.EnableSSM) {
+		managedPolicyNames.Insert(iamPolicyAmazonSSMManagedInstanceCore)
+	}
+
 	for _, policyARN := range iamConfig.AttachPolicyARNs {
 		parsedARN, err := arn.Parse(policyARN)
 		if err != nil {
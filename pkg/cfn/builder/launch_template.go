@@ -0,0 +1,285 @@
+package builder
+
+import (
+	"encoding/json"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// imdsv2HopLimit allows containers that don't use the host network to reach
+// the instance metadata service with session tokens, which takes an extra hop
+const imdsv2HopLimit = 2
+
+// ec2LaunchTemplate is the launch template of a nodegroup
+type ec2LaunchTemplate struct {
+	LaunchTemplateName *gfn.Value             `json:"LaunchTemplateName,omitempty"`
+	LaunchTemplateData *ec2LaunchTemplateData `json:"LaunchTemplateData,omitempty"`
+}
+
+// ec2LaunchTemplateData embeds the launch template data of goformation, so that its fields
+// can be used as they are
+type ec2LaunchTemplateData struct {
+	*gfn.AWSEC2LaunchTemplate_LaunchTemplateData
+	// BlockDeviceMappings takes precedence over the field of goformation
+	BlockDeviceMappings []blockDeviceMapping `json:"BlockDeviceMappings,omitempty"`
+	MetadataOptions     *metadataOptions     `json:"MetadataOptions,omitempty"`
+	// NetworkInterfaces takes precedence over the field of goformation
+	NetworkInterfaces []networkInterface `json:"NetworkInterfaces,omitempty"`
+	// Placement takes precedence over the field of goformation
+	Placement                        *placement                        `json:"Placement,omitempty"`
+	CapacityReservationSpecification *capacityReservationSpecification `json:"CapacityReservationSpecification,omitempty"`
+	// InstanceMarketOptions takes precedence over the field of goformation
+	InstanceMarketOptions *instanceMarketOptions `json:"InstanceMarketOptions,omitempty"`
+	TagSpecifications     []tagSpecification     `json:"TagSpecifications,omitempty"`
+}
+
+type tagSpecification struct {
+	ResourceType string `json:"ResourceType"`
+	Tags         []tag  `json:"Tags"`
+}
+
+type tag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+type capacityReservationSpecification struct {
+	CapacityReservationPreference string                     `json:"CapacityReservationPreference,omitempty"`
+	CapacityReservationTarget     *capacityReservationTarget `json:"CapacityReservationTarget,omitempty"`
+}
+
+type capacityReservationTarget struct {
+	CapacityReservationID               string `json:"CapacityReservationId,omitempty"`
+	CapacityReservationResourceGroupARN string `json:"CapacityReservationResourceGroupArn,omitempty"`
+}
+
+type instanceMarketOptions struct {
+	MarketType string `json:"MarketType"`
+}
+
+type placement struct {
+	GroupName *gfn.Value `json:"GroupName,omitempty"`
+	Tenancy   string     `json:"Tenancy,omitempty"`
+}
+
+// ec2PlacementGroup is a placement group created for a nodegroup
+type ec2PlacementGroup struct {
+	Strategy string `json:"Strategy"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (g *ec2PlacementGroup) MarshalJSON() ([]byte, error) {
+	type Properties ec2PlacementGroup
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::EC2::PlacementGroup",
+		Properties: Properties(*g),
+	})
+}
+
+type networkInterface struct {
+	*gfn.AWSEC2LaunchTemplate_NetworkInterface
+	NetworkCardIndex          *gfn.Value           `json:"NetworkCardIndex,omitempty"`
+	InterfaceType             string               `json:"InterfaceType,omitempty"`
+	EnaSrdSpecification       *enaSrdSpecification `json:"EnaSrdSpecification,omitempty"`
+	AssociateCarrierIpAddress *gfn.Value           `json:"AssociateCarrierIpAddress,omitempty"`
+}
+
+type enaSrdSpecification struct {
+	EnaSrdEnabled          bool                    `json:"EnaSrdEnabled"`
+	EnaSrdUdpSpecification *enaSrdUDPSpecification `json:"EnaSrdUdpSpecification,omitempty"`
+}
+
+type enaSrdUDPSpecification struct {
+	EnaSrdUDPEnabled bool `json:"EnaSrdUdpEnabled"`
+}
+
+type blockDeviceMapping struct {
+	DeviceName *gfn.Value `json:"DeviceName,omitempty"`
+	Ebs        *ebs       `json:"Ebs,omitempty"`
+}
+
+type ebs struct {
+	*gfn.AWSEC2LaunchTemplate_Ebs
+	Throughput *gfn.Value `json:"Throughput,omitempty"`
+}
+
+// volume holds the settings of an EBS volume of a nodegroup
+type volume struct {
+	name       string
+	size       *int
+	volumeType *string
+	iops       *int
+	throughput *int
+	encrypted  *bool
+	kmsKeyID   *string
+}
+
+type metadataOptions struct {
+	HTTPTokens              string `json:"HttpTokens,omitempty"`
+	HTTPPutResponseHopLimit int    `json:"HttpPutResponseHopLimit,omitempty"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (t *ec2LaunchTemplate) MarshalJSON() ([]byte, error) {
+	type Properties ec2LaunchTemplate
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::EC2::LaunchTemplate",
+		Properties: Properties(*t),
+	})
+}
+
+// setMetadataOptions requires the instances to use IMDSv2 when disableIMDSv1 is enabled
+func (d *ec2LaunchTemplateData) setMetadataOptions(disableIMDSv1 *bool) {
+	if api.IsEnabled(disableIMDSv1) {
+		d.MetadataOptions = &metadataOptions{
+			HTTPTokens:              "required",
+			HTTPPutResponseHopLimit: imdsv2HopLimit,
+		}
+	}
+}
+
+// setPlacement puts the instances in a placement group and sets their tenancy, groupName
+// being nil when the instances aren't in a placement group
+func (d *ec2LaunchTemplateData) setPlacement(groupName *gfn.Value, tenancy string) {
+	if groupName == nil && tenancy == "" {
+		return
+	}
+	d.Placement = &placement{
+		GroupName: groupName,
+		Tenancy:   tenancy,
+	}
+}
+
+// setCapacityReservation targets the capacity reservation of a nodegroup or sets its preference,
+// Capacity Blocks also requiring the instances to be launched in their market
+func (d *ec2LaunchTemplateData) setCapacityReservation(cr *api.NodeGroupCapacityReservation) {
+	if cr == nil {
+		return
+	}
+	d.CapacityReservationSpecification = &capacityReservationSpecification{
+		CapacityReservationPreference: cr.Preference,
+	}
+	if cr.ID != "" || cr.ResourceGroupARN != "" {
+		d.CapacityReservationSpecification.CapacityReservationTarget = &capacityReservationTarget{
+			CapacityReservationID:               cr.ID,
+			CapacityReservationResourceGroupARN: cr.ResourceGroupARN,
+		}
+	}
+	if api.IsEnabled(cr.CapacityBlock) {
+		d.InstanceMarketOptions = &instanceMarketOptions{MarketType: "capacity-block"}
+	}
+}
+
+// setTagSpecifications tags the instances and their EBS volumes when they are launched, as
+// the tags of the stack aren't propagated to them
+func (d *ec2LaunchTemplateData) setTagSpecifications(tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	var launchTags []tag
+	for _, key := range sortedKeys(tags) {
+		launchTags = append(launchTags, tag{Key: key, Value: tags[key]})
+	}
+	for _, resourceType := range []string{"instance", "volume"} {
+		d.TagSpecifications = append(d.TagSpecifications, tagSpecification{
+			ResourceType: resourceType,
+			Tags:         launchTags,
+		})
+	}
+}
+
+// launchTags returns the tags of the instances and volumes of a nodegroup, the tags of the
+// nodegroup taking precedence over the tags of the cluster in metadata.tags
+func launchTags(clusterConfig *api.ClusterConfig, nodeGroupTags map[string]string) map[string]string {
+	tags := map[string]string{}
+	for key, value := range clusterConfig.Metadata.Tags {
+		tags[key] = value
+	}
+	for key, value := range nodeGroupTags {
+		tags[key] = value
+	}
+	return tags
+}
+
+// addVolume maps a device to an EBS volume, gp2 being the default type
+func (d *ec2LaunchTemplateData) addVolume(v volume) {
+	volumeType := api.NodeVolumeTypeGP2
+	if api.IsSetAndNonEmptyString(v.volumeType) {
+		volumeType = *v.volumeType
+	}
+	volumeEBS := &ebs{
+		AWSEC2LaunchTemplate_Ebs: &gfn.AWSEC2LaunchTemplate_Ebs{
+			VolumeType: gfn.NewString(volumeType),
+		},
+	}
+	if v.size != nil && *v.size > 0 {
+		volumeEBS.VolumeSize = gfn.NewInteger(*v.size)
+	}
+	if v.iops != nil {
+		volumeEBS.Iops = gfn.NewInteger(*v.iops)
+	}
+	if v.throughput != nil {
+		volumeEBS.Throughput = gfn.NewInteger(*v.throughput)
+	}
+	if v.encrypted != nil {
+		volumeEBS.Encrypted = gfn.NewBoolean(*v.encrypted)
+	}
+	if api.IsSetAndNonEmptyString(v.kmsKeyID) {
+		volumeEBS.KmsKeyId = gfn.NewString(*v.kmsKeyID)
+	}
+
+	d.BlockDeviceMappings = append(d.BlockDeviceMappings, blockDeviceMapping{
+		DeviceName: gfn.NewString(v.name),
+		Ebs:        volumeEBS,
+	})
+}
+
+// setNetworkInterfaces attaches the network interfaces of a nodegroup to its network cards, the
+// primary interface being the first one of the first card
+func (d *ec2LaunchTemplateData) setNetworkInterfaces(ng *api.NodeGroup, securityGroups []*gfn.Value) {
+	var srd *enaSrdSpecification
+	if ng.NetworkInterfaces != nil && api.IsEnabled(ng.NetworkInterfaces.EnaExpress) {
+		srd = &enaSrdSpecification{EnaSrdEnabled: true}
+		if api.IsEnabled(ng.NetworkInterfaces.EnaExpressUDP) {
+			srd.EnaSrdUdpSpecification = &enaSrdUDPSpecification{EnaSrdUDPEnabled: true}
+		}
+	}
+
+	// device indices are per network card, and only the primary interface can have device index 0
+	nextDeviceIndex := map[int]int{}
+	for _, cardIndex := range api.NetworkCardIndices(ng) {
+		deviceIndex, ok := nextDeviceIndex[cardIndex]
+		if !ok && cardIndex != 0 {
+			deviceIndex = 1
+		}
+		nextDeviceIndex[cardIndex] = deviceIndex + 1
+
+		ni := networkInterface{
+			AWSEC2LaunchTemplate_NetworkInterface: &gfn.AWSEC2LaunchTemplate_NetworkInterface{
+				// Explicitly un-setting this so that it doesn't get defaulted to true
+				AssociatePublicIpAddress: nil,
+				DeviceIndex:              gfn.NewInteger(deviceIndex),
+				Groups:                   securityGroups,
+			},
+			EnaSrdSpecification: srd,
+		}
+		if ng.NetworkInterfaces != nil {
+			ni.NetworkCardIndex = gfn.NewInteger(cardIndex)
+		}
+		if api.IsEnabled(ng.EFAEnabled) {
+			ni.InterfaceType = "efa"
+		}
+		if cardIndex == 0 && deviceIndex == 0 && ng.HasWavelengthZones() && !ng.PrivateNetworking {
+			// nodes in Wavelength Zones are reachable from the carrier network through a carrier IP
+			ni.AssociateCarrierIpAddress = gfn.True()
+		}
+		d.NetworkInterfaces = append(d.NetworkInterfaces, ni)
+	}
+}
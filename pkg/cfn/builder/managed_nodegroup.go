@@ -7,7 +7,9 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/eks"
 	gfn "github.com/awslabs/goformation/cloudformation"
+	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
@@ -23,17 +25,25 @@ type ManagedNodeGroupResourceSet struct {
 // Rather than setting all field types to *gfn.Value, the types are conveniently chosen
 // to allow using values without requiring any conversion
 type managedNodeGroup struct {
-	ClusterName   string              `json:"ClusterName"`
-	NodegroupName string              `json:"NodegroupName"`
-	ScalingConfig *scalingConfig      `json:"ScalingConfig,omitempty"`
-	DiskSize      int                 `json:"DiskSize,omitempty"` // 0 is not a valid value
-	Subnets       interface{}         `json:"Subnets"`
-	InstanceTypes []string            `json:"InstanceTypes"`
-	AmiType       string              `json:"AmiType,omitempty"`
-	RemoteAccess  *remoteAccessConfig `json:"RemoteAccess,omitempty"`
-	NodeRole      *gfn.Value          `json:"NodeRole"`
-	Labels        map[string]string   `json:"Labels,omitempty"`
-	Tags          map[string]string   `json:"Tags,omitempty"`
+	ClusterName    string                       `json:"ClusterName"`
+	NodegroupName  string                       `json:"NodegroupName"`
+	ScalingConfig  *scalingConfig               `json:"ScalingConfig,omitempty"`
+	DiskSize       int                          `json:"DiskSize,omitempty"` // 0 is not a valid value
+	Subnets        interface{}                  `json:"Subnets"`
+	InstanceTypes  []string                     `json:"InstanceTypes,omitempty"`
+	AmiType        string                       `json:"AmiType,omitempty"`
+	RemoteAccess   *remoteAccessConfig          `json:"RemoteAccess,omitempty"`
+	NodeRole       *gfn.Value                   `json:"NodeRole"`
+	Labels         map[string]string            `json:"Labels,omitempty"`
+	Taints         []managedNodeGroupTaint      `json:"Taints,omitempty"`
+	Tags           map[string]string            `json:"Tags,omitempty"`
+	LaunchTemplate *launchTemplateSpecification `json:"LaunchTemplate,omitempty"`
+}
+
+type managedNodeGroupTaint struct {
+	Key    string `json:"Key"`
+	Value  string `json:"Value,omitempty"`
+	Effect string `json:"Effect"`
 }
 
 type scalingConfig struct {
@@ -42,6 +52,11 @@ type scalingConfig struct {
 	DesiredSize *int `json:"DesiredSize,omitempty"`
 }
 
+type launchTemplateSpecification struct {
+	ID      *gfn.Value `json:"Id"`
+	Version *gfn.Value `json:"Version,omitempty"`
+}
+
 type remoteAccessConfig struct {
 	Ec2SshKey            *string   `json:"Ec2SshKey,omitempty"`
 	SourceSecurityGroups []*string `json:"SourceSecurityGroups,omitempty"`
@@ -84,7 +99,7 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources() error {
 
 	var nodeRole *gfn.Value
 	if m.nodeGroup.IAM.InstanceRoleARN == "" {
-		if err := createRole(m.resourceSet, m.nodeGroup.IAM, true); err != nil {
+		if err := createRole(m.resourceSet, m.clusterConfig, m.nodeGroup.IAM, m.nodeGroup.SSH, true); err != nil {
 			return err
 		}
 		nodeRole = gfn.MakeFnGetAttString(fmt.Sprintf("%s.%s", cfnIAMInstanceRoleName, "Arn"))
@@ -92,7 +107,7 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources() error {
 		nodeRole = gfn.NewString(m.nodeGroup.IAM.InstanceRoleARN)
 	}
 
-	subnets, err := AssignSubnets(m.nodeGroup.AvailabilityZones, m.clusterStackName, m.clusterConfig, m.nodeGroup.PrivateNetworking)
+	subnets, err := AssignSubnets(m.nodeGroup.Subnets, m.nodeGroup.AvailabilityZones, m.clusterStackName, m.clusterConfig, m.nodeGroup.PrivateNetworking)
 	if err != nil {
 		return err
 	}
@@ -105,23 +120,53 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources() error {
 			MaxSize:     m.nodeGroup.MaxSize,
 			DesiredSize: m.nodeGroup.DesiredCapacity,
 		},
-		Subnets: subnets,
+		Subnets:  subnets,
+		NodeRole: nodeRole,
+		Labels:   m.nodeGroup.Labels,
+		Tags:     m.nodeGroup.Tags,
+	}
+	taints, err := makeManagedTaints(m.nodeGroup.Taints)
+	if err != nil {
+		return err
+	}
+	managedResource.Taints = taints
+	// the instance type and the AMI can be set in a launch template instead
+	if m.nodeGroup.InstanceType != "" {
 		// Currently the API supports specifying only one instance type
-		InstanceTypes: []string{m.nodeGroup.InstanceType},
-		AmiType:       getAMIType(m.nodeGroup.InstanceType),
-		NodeRole:      nodeRole,
-		Labels:        m.nodeGroup.Labels,
-		Tags:          m.nodeGroup.Tags,
+		managedResource.InstanceTypes = []string{m.nodeGroup.InstanceType}
+		managedResource.AmiType = getAMIType(m.nodeGroup.InstanceType)
 	}
 
-	if api.IsEnabled(m.nodeGroup.SSH.Allow) {
-		managedResource.RemoteAccess = &remoteAccessConfig{
-			Ec2SshKey:            m.nodeGroup.SSH.PublicKeyName,
-			SourceSecurityGroups: aws.StringSlice(m.nodeGroup.SSH.SourceSecurityGroupIDs),
-		}
+	userData, err := nodebootstrap.NewUserDataForManagedNodeGroup(m.nodeGroup)
+	if err != nil {
+		return err
 	}
-	if m.nodeGroup.VolumeSize != nil {
-		managedResource.DiskSize = *m.nodeGroup.VolumeSize
+
+	switch {
+	case m.nodeGroup.LaunchTemplate != nil:
+		managedResource.LaunchTemplate = &launchTemplateSpecification{
+			ID: gfn.NewString(m.nodeGroup.LaunchTemplate.ID),
+		}
+		if version := m.nodeGroup.LaunchTemplate.Version; version != nil {
+			managedResource.LaunchTemplate.Version = gfn.NewString(*version)
+		}
+	case m.nodeGroup.RequiresLaunchTemplate():
+		// EKS doesn't accept the SSH key and the disk size along with a launch template
+		m.addLaunchTemplate(userData)
+		managedResource.LaunchTemplate = &launchTemplateSpecification{
+			ID:      gfn.MakeRef("LaunchTemplate"),
+			Version: gfn.MakeFnGetAttString("LaunchTemplate.LatestVersionNumber"),
+		}
+	default:
+		if api.IsEnabled(m.nodeGroup.SSH.Allow) {
+			managedResource.RemoteAccess = &remoteAccessConfig{
+				Ec2SshKey:            m.nodeGroup.SSH.PublicKeyName,
+				SourceSecurityGroups: aws.StringSlice(m.nodeGroup.SSH.SourceSecurityGroupIDs),
+			}
+		}
+		if m.nodeGroup.VolumeSize != nil {
+			managedResource.DiskSize = *m.nodeGroup.VolumeSize
+		}
 	}
 
 	m.newResource("ManagedNodeGroup", managedResource)
@@ -129,6 +174,61 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources() error {
 	return nil
 }
 
+// addLaunchTemplate adds a launch template with the settings of the nodegroup that
+// can't be passed to EKS along with a launch template
+func (m *ManagedNodeGroupResourceSet) addLaunchTemplate(userData string) {
+	launchTemplateData := &ec2LaunchTemplateData{
+		AWSEC2LaunchTemplate_LaunchTemplateData: &gfn.AWSEC2LaunchTemplate_LaunchTemplateData{},
+	}
+	if userData != "" {
+		launchTemplateData.UserData = gfn.NewString(userData)
+	}
+	if api.IsEnabled(m.nodeGroup.SSH.Allow) && api.IsSetAndNonEmptyString(m.nodeGroup.SSH.PublicKeyName) {
+		launchTemplateData.KeyName = gfn.NewString(*m.nodeGroup.SSH.PublicKeyName)
+	}
+	launchTemplateData.addVolume(volume{
+		name:       "/dev/xvda",
+		size:       m.nodeGroup.VolumeSize,
+		volumeType: m.nodeGroup.VolumeType,
+		iops:       m.nodeGroup.VolumeIOPS,
+		throughput: m.nodeGroup.VolumeThroughput,
+		encrypted:  m.nodeGroup.VolumeEncrypted,
+		kmsKeyID:   m.nodeGroup.VolumeKmsKeyID,
+	})
+
+	launchTemplateData.setMetadataOptions(m.nodeGroup.DisableIMDSv1)
+	launchTemplateData.setTagSpecifications(launchTags(m.clusterConfig, m.nodeGroup.Tags))
+
+	m.newResource("LaunchTemplate", &ec2LaunchTemplate{
+		LaunchTemplateName: gfn.MakeFnSubString(fmt.Sprintf("${%s}", gfn.StackName)),
+		LaunchTemplateData: launchTemplateData,
+	})
+}
+
+// makeManagedTaints converts taints in the `value:Effect` format used by kubelet to the
+// format of EKS, where the effect is e.g. NO_SCHEDULE instead of NoSchedule
+func makeManagedTaints(taints map[string]string) ([]managedNodeGroupTaint, error) {
+	var managedTaints []managedNodeGroupTaint
+	for _, key := range sortedKeys(taints) {
+		value, effect, err := api.ParseTaint(taints[key])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid taint %q", key)
+		}
+		managedTaints = append(managedTaints, managedNodeGroupTaint{
+			Key:    key,
+			Value:  value,
+			Effect: managedTaintEffects[effect],
+		})
+	}
+	return managedTaints, nil
+}
+
+var managedTaintEffects = map[string]string{
+	"NoSchedule":       "NO_SCHEDULE",
+	"PreferNoSchedule": "PREFER_NO_SCHEDULE",
+	"NoExecute":        "NO_EXECUTE",
+}
+
 func getAMIType(instanceType string) string {
 	if utils.IsGPUInstanceType(instanceType) {
 		return eks.AMITypesAl2X8664Gpu
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/awslabs/goformation/v4"
 	"github.com/stretchr/testify/assert"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -13,6 +14,7 @@ func TestManagedPolicyResources(t *testing.T) {
 	iamRoleTests := []struct {
 		addons                  api.NodeGroupIAMAddonPolicies
 		attachPolicyARNs        []string
+		enableSSM               bool
 		expectedManagedPolicies []string
 		description             string
 	}{
@@ -36,6 +38,12 @@ func TestManagedPolicyResources(t *testing.T) {
 				"AmazonEC2ContainerRegistryReadOnly", "CloudWatchAgentServerPolicy"),
 			description: "CloudWatch enabled",
 		},
+		{
+			enableSSM: true,
+			expectedManagedPolicies: makePartitionedPolicies("AmazonEKSWorkerNodePolicy", "AmazonEKS_CNI_Policy",
+				"AmazonEC2ContainerRegistryReadOnly", "AmazonSSMManagedInstanceCore"),
+			description: "SSM enabled",
+		},
 		{
 			attachPolicyARNs:        []string{"AmazonEKSWorkerNodePolicy", "AmazonEKS_CNI_Policy"},
 			expectedManagedPolicies: prefixPolicies("AmazonEKSWorkerNodePolicy", "AmazonEKS_CNI_Policy"),
@@ -75,6 +83,9 @@ func TestManagedPolicyResources(t *testing.T) {
 			ng := api.NewManagedNodeGroup()
 			ng.IAM.WithAddonPolicies = tt.addons
 			ng.IAM.AttachPolicyARNs = prefixPolicies(tt.attachPolicyARNs...)
+			if tt.enableSSM {
+				ng.SSH.EnableSSM = api.Enabled()
+			}
 
 			stack := NewManagedNodeGroup(clusterConfig, ng, "iam-test")
 			err := stack.AddAllResources()
@@ -149,6 +160,47 @@ func TestManagedNodeRole(t *testing.T) {
 	}
 }
 
+func TestManagedNodeRolePermissionsBoundary(t *testing.T) {
+	boundaryTests := []struct {
+		description      string
+		instanceBoundary string
+		expectedBoundary string
+	}{
+		{
+			description:      "iam.permissionsBoundary applies to the instance role",
+			expectedBoundary: "arn:aws:iam::123456789012:policy/cluster-boundary",
+		},
+		{
+			description:      "instanceRolePermissionsBoundary takes precedence",
+			instanceBoundary: "arn:aws:iam::123456789012:policy/node-boundary",
+			expectedBoundary: "arn:aws:iam::123456789012:policy/node-boundary",
+		},
+	}
+
+	for i, tt := range boundaryTests {
+		t.Run(fmt.Sprintf("%d: %s", i, tt.description), func(t *testing.T) {
+			clusterConfig := api.NewClusterConfig()
+			clusterConfig.IAM.PermissionsBoundary = aws.String("arn:aws:iam::123456789012:policy/cluster-boundary")
+
+			ng := api.NewManagedNodeGroup()
+			ng.IAM.InstanceRolePermissionsBoundary = tt.instanceBoundary
+
+			stack := NewManagedNodeGroup(clusterConfig, ng, "iam-test")
+			assert.NoError(t, stack.AddAllResources())
+
+			bytes, err := stack.RenderJSON()
+			assert.NoError(t, err)
+
+			template, err := goformation.ParseJSON(bytes)
+			assert.NoError(t, err)
+
+			role, ok := template.GetAllIAMRoleResources()[cfnIAMInstanceRoleName]
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedBoundary, role.PermissionsBoundary)
+		})
+	}
+}
+
 func makePartitionedPolicies(policies ...string) []string {
 	var partitionedPolicies []string
 	for _, policy := range policies {
@@ -164,3 +216,71 @@ func prefixPolicies(policies ...string) []string {
 	}
 	return prefixedPolicies
 }
+
+func TestManagedLaunchTemplate(t *testing.T) {
+	launchTemplateTests := []struct {
+		description            string
+		launchTemplate         *api.LaunchTemplate
+		preBootstrapCommands   []string
+		volumeType             *string
+		expectedLaunchTemplate string
+		expectedResource       bool
+	}{
+		{
+			description:            "user-supplied launch template",
+			launchTemplate:         &api.LaunchTemplate{ID: "lt-1234", Version: aws.String("3")},
+			expectedLaunchTemplate: `"LaunchTemplate":{"Id":"lt-1234","Version":"3"}`,
+		},
+		{
+			description:            "generated launch template",
+			preBootstrapCommands:   []string{"echo hello"},
+			expectedLaunchTemplate: `"LaunchTemplate":{"Id":{"Ref":"LaunchTemplate"},"Version":{"Fn::GetAtt":"LaunchTemplate.LatestVersionNumber"}}`,
+			expectedResource:       true,
+		},
+		{
+			description:            "generated launch template with a gp3 volume",
+			volumeType:             aws.String(api.NodeVolumeTypeGP3),
+			expectedLaunchTemplate: `"VolumeType":"gp3"`,
+			expectedResource:       true,
+		},
+	}
+
+	for i, tt := range launchTemplateTests {
+		t.Run(fmt.Sprintf("%d: %s", i, tt.description), func(t *testing.T) {
+			ng := api.NewManagedNodeGroup()
+			ng.LaunchTemplate = tt.launchTemplate
+			ng.PreBootstrapCommands = tt.preBootstrapCommands
+			ng.VolumeType = tt.volumeType
+			api.SetManagedNodeGroupDefaults(ng, api.NewClusterConfig().Metadata)
+
+			stack := NewManagedNodeGroup(api.NewClusterConfig(), ng, "lt-test")
+			assert.NoError(t, stack.AddAllResources())
+
+			bytes, err := stack.RenderJSON()
+			assert.NoError(t, err)
+			assert.Contains(t, string(bytes), tt.expectedLaunchTemplate)
+			assert.NotContains(t, string(bytes), "RemoteAccess")
+			assert.NotContains(t, string(bytes), "DiskSize")
+
+			template, err := goformation.ParseJSON(bytes)
+			assert.NoError(t, err)
+			_, ok := template.GetAllEC2LaunchTemplateResources()["LaunchTemplate"]
+			assert.Equal(t, tt.expectedResource, ok)
+		})
+	}
+}
+
+func TestManagedTaints(t *testing.T) {
+	ng := api.NewManagedNodeGroup()
+	ng.Taints = map[string]string{
+		"dedicated": "backend:NoSchedule",
+		"gpu":       ":NoExecute",
+	}
+
+	stack := NewManagedNodeGroup(api.NewClusterConfig(), ng, "taints-test")
+	assert.NoError(t, stack.AddAllResources())
+
+	bytes, err := stack.RenderJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(bytes), `"Taints":[{"Key":"dedicated","Value":"backend","Effect":"NO_SCHEDULE"},{"Key":"gpu","Effect":"NO_EXECUTE"}]`)
+}
@@ -0,0 +1,176 @@
+package builder
+
+import (
+	"encoding/json"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
+)
+
+// nodeTerminationHandlerHookName is the name of the lifecycle hook that lets aws-node-termination-handler
+// drain the nodes of Spot nodegroups before the Auto Scaling Group terminates them
+const nodeTerminationHandlerHookName = "NodeTerminationHandler"
+
+type sqsQueue struct {
+	QueueName              string `json:"QueueName"`
+	MessageRetentionPeriod int    `json:"MessageRetentionPeriod"`
+	SqsManagedSseEnabled   bool   `json:"SqsManagedSseEnabled"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (q *sqsQueue) MarshalJSON() ([]byte, error) {
+	type Properties sqsQueue
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::SQS::Queue",
+		Properties: Properties(*q),
+	})
+}
+
+type sqsQueuePolicy struct {
+	Queues         []*gfn.Value           `json:"Queues"`
+	PolicyDocument map[string]interface{} `json:"PolicyDocument"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (p *sqsQueuePolicy) MarshalJSON() ([]byte, error) {
+	type Properties sqsQueuePolicy
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::SQS::QueuePolicy",
+		Properties: Properties(*p),
+	})
+}
+
+type eventsRule struct {
+	Description  string                 `json:"Description"`
+	EventPattern map[string]interface{} `json:"EventPattern"`
+	Targets      []eventsRuleTarget     `json:"Targets"`
+}
+
+type eventsRuleTarget struct {
+	ID  string     `json:"Id"`
+	Arn *gfn.Value `json:"Arn"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (r *eventsRule) MarshalJSON() ([]byte, error) {
+	type Properties eventsRule
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::Events::Rule",
+		Properties: Properties(*r),
+	})
+}
+
+// nodeTerminationHandlerRules are the EventBridge rules of the events that aws-node-termination-handler
+// handles in queue mode, by logical ID
+var nodeTerminationHandlerRules = []struct {
+	logicalID, description string
+	eventPattern           map[string]interface{}
+}{
+	{
+		logicalID:   "NodeTerminationHandlerASGTerminationRule",
+		description: "Auto Scaling Group instance terminations",
+		eventPattern: map[string]interface{}{
+			"source":      []string{"aws.autoscaling"},
+			"detail-type": []string{"EC2 Instance-terminate Lifecycle Action"},
+		},
+	},
+	{
+		logicalID:   "NodeTerminationHandlerSpotInterruptionRule",
+		description: "Spot instance interruption warnings",
+		eventPattern: map[string]interface{}{
+			"source":      []string{"aws.ec2"},
+			"detail-type": []string{"EC2 Spot Instance Interruption Warning"},
+		},
+	},
+	{
+		logicalID:   "NodeTerminationHandlerRebalanceRule",
+		description: "instance rebalance recommendations",
+		eventPattern: map[string]interface{}{
+			"source":      []string{"aws.ec2"},
+			"detail-type": []string{"EC2 Instance Rebalance Recommendation"},
+		},
+	},
+	{
+		logicalID:   "NodeTerminationHandlerInstanceStateChangeRule",
+		description: "instance state changes",
+		eventPattern: map[string]interface{}{
+			"source":      []string{"aws.ec2"},
+			"detail-type": []string{"EC2 Instance State-change Notification"},
+		},
+	},
+	{
+		logicalID:   "NodeTerminationHandlerScheduledChangeRule",
+		description: "scheduled maintenance of instances",
+		eventPattern: map[string]interface{}{
+			"source":      []string{"aws.health"},
+			"detail-type": []string{"AWS Health Event"},
+			"detail": map[string]interface{}{
+				"service":           []string{"EC2"},
+				"eventTypeCategory": []string{"scheduledChange"},
+			},
+		},
+	},
+}
+
+// addResourcesForNodeTerminationHandler adds the SQS queue that aws-node-termination-handler consumes
+// in queue mode, and the EventBridge rules sending the interruption events to it
+func (c *ClusterResourceSet) addResourcesForNodeTerminationHandler() {
+	queue := c.newResource("NodeTerminationHandlerQueue", &sqsQueue{
+		QueueName: c.spec.NodeTerminationHandlerQueueName(),
+		// the events are stale once the instances are terminated
+		MessageRetentionPeriod: 300,
+		SqsManagedSseEnabled:   true,
+	})
+	queueARN := gfn.MakeFnGetAttString("NodeTerminationHandlerQueue.Arn")
+
+	c.newResource("NodeTerminationHandlerQueuePolicy", &sqsQueuePolicy{
+		Queues: []*gfn.Value{queue},
+		PolicyDocument: cft.MakePolicyDocument(map[string]interface{}{
+			"Effect": "Allow",
+			"Principal": map[string][]string{
+				"Service": {"events.amazonaws.com", "sqs.amazonaws.com"},
+			},
+			"Action":   "sqs:SendMessage",
+			"Resource": queueARN,
+		}),
+	})
+
+	for _, rule := range nodeTerminationHandlerRules {
+		c.newResource(rule.logicalID, &eventsRule{
+			Description:  "Sends " + rule.description + " to aws-node-termination-handler [created by eksctl]",
+			EventPattern: rule.eventPattern,
+			Targets:      []eventsRuleTarget{{ID: "NodeTerminationHandlerQueue", Arn: queueARN}},
+		})
+	}
+
+	c.rs.defineOutputWithoutCollector(outputs.ClusterNodeTerminationHandlerQueueURL, queue, false)
+}
+
+// nodeTerminationHandlerLifecycleHook pauses the termination of the instances of a Spot nodegroup,
+// so that aws-node-termination-handler drains them before completing the lifecycle action
+func nodeTerminationHandlerLifecycleHook() map[string]interface{} {
+	return map[string]interface{}{
+		"LifecycleHookName":   nodeTerminationHandlerHookName,
+		"LifecycleTransition": "autoscaling:EC2_INSTANCE_TERMINATING",
+		"HeartbeatTimeout":    "300",
+		"DefaultResult":       "CONTINUE",
+	}
+}
+
+// usesNodeTerminationHandlerQueue returns true if aws-node-termination-handler handles the
+// terminations of the instances of a nodegroup through the queue
+func usesNodeTerminationHandlerQueue(spec *api.ClusterConfig, ng *api.NodeGroup) bool {
+	return spec.HasNodeTerminationHandlerQueue() && api.HasSpotInstances(ng)
+}
@@ -0,0 +1,69 @@
+package builder_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tidwall/gjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+var _ = Describe("Node termination handler", func() {
+	var cfg *api.ClusterConfig
+
+	render := func() string {
+		api.SetClusterConfigDefaults(cfg)
+		crs := NewClusterResourceSet(mockprovider.NewMockProvider(), cfg, false, nil)
+		Expect(crs.AddAllResources()).To(Succeed())
+		template, err := crs.RenderJSON()
+		Expect(err).NotTo(HaveOccurred())
+		return string(template)
+	}
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "spot"
+		cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+		cfg.IAM.WithOIDC = api.Enabled()
+		cfg.NodeTerminationHandler = &api.ClusterNodeTerminationHandler{Mode: api.NodeTerminationHandlerModeQueue}
+		Expect(vpc.SetSubnets(cfg)).To(Succeed())
+	})
+
+	It("adds the queue and the rules sending the interruption events to it in queue mode", func() {
+		template := render()
+
+		queue := gjson.Get(template, "Resources.NodeTerminationHandlerQueue")
+		Expect(queue.Get("Type").String()).To(Equal("AWS::SQS::Queue"))
+		Expect(queue.Get("Properties.QueueName").String()).To(Equal("eksctl-spot-nth"))
+		Expect(gjson.Get(template, "Resources.NodeTerminationHandlerQueuePolicy.Properties.Queues.0.Ref").String()).To(Equal("NodeTerminationHandlerQueue"))
+		Expect(gjson.Get(template, "Outputs.NodeTerminationHandlerQueueURL.Value.Ref").String()).To(Equal("NodeTerminationHandlerQueue"))
+
+		for _, rule := range []string{"ASGTermination", "SpotInterruption", "Rebalance", "InstanceStateChange", "ScheduledChange"} {
+			rule := gjson.Get(template, "Resources.NodeTerminationHandler"+rule+"Rule")
+			Expect(rule.Get("Type").String()).To(Equal("AWS::Events::Rule"))
+			Expect(rule.Get("Properties.Targets.0.Arn.Fn::GetAtt").String()).To(Equal(`["NodeTerminationHandlerQueue","Arn"]`))
+		}
+		Expect(gjson.Get(template, "Resources.NodeTerminationHandlerSpotInterruptionRule.Properties.EventPattern.detail-type.0").String()).To(Equal("EC2 Spot Instance Interruption Warning"))
+	})
+
+	It("adds the IAM service account of the handler", func() {
+		render()
+
+		Expect(cfg.IAM.ServiceAccounts).To(HaveLen(1))
+		sa := cfg.IAM.ServiceAccounts[0]
+		Expect(sa.NameString()).To(Equal("kube-system/aws-node-termination-handler"))
+		Expect(sa.AttachPolicy).To(HaveKey("Statement"))
+	})
+
+	It("adds nothing to the cluster stack in imds mode", func() {
+		cfg.NodeTerminationHandler.Mode = api.NodeTerminationHandlerModeIMDS
+		template := render()
+
+		Expect(gjson.Get(template, "Resources.NodeTerminationHandlerQueue").Exists()).To(BeFalse())
+		Expect(gjson.Get(template, "Outputs.NodeTerminationHandlerQueueURL").Exists()).To(BeFalse())
+		Expect(cfg.IAM.ServiceAccounts).To(BeEmpty())
+	})
+})
@@ -2,14 +2,16 @@ package builder
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	gfn "github.com/awslabs/goformation/cloudformation"
 
-	"github.com/kris-nova/logger"
-
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 )
 
@@ -95,6 +97,9 @@ func (n *NodeGroupResourceSet) AddAllResources() error {
 		return err
 	}
 	n.addResourcesForSecurityGroups()
+	if api.IsEnabled(n.spec.EFAEnabled) {
+		n.addResourcesForEFASecurityGroup()
+	}
 
 	return n.addResourcesForNodeGroup()
 }
@@ -122,36 +127,57 @@ func (n *NodeGroupResourceSet) addResourcesForNodeGroup() error {
 	}
 
 	if volumeSize := n.spec.VolumeSize; volumeSize != nil && *volumeSize > 0 {
-		var (
-			kmsKeyID   *gfn.Value
-			volumeIOPS *gfn.Value
-		)
-		if api.IsSetAndNonEmptyString(n.spec.VolumeKmsKeyID) {
-			kmsKeyID = gfn.NewString(*n.spec.VolumeKmsKeyID)
-		}
+		launchTemplateData.addVolume(volume{
+			name:       *n.spec.VolumeName,
+			size:       volumeSize,
+			volumeType: n.spec.VolumeType,
+			iops:       n.spec.VolumeIOPS,
+			throughput: n.spec.VolumeThroughput,
+			encrypted:  n.spec.VolumeEncrypted,
+			kmsKeyID:   n.spec.VolumeKmsKeyID,
+		})
+	}
 
-		if *n.spec.VolumeType == api.NodeVolumeTypeIO1 {
-			volumeIOPS = gfn.NewInteger(*n.spec.VolumeIOPS)
-		}
+	for _, v := range n.spec.AdditionalVolumes {
+		launchTemplateData.addVolume(volume{
+			name:       *v.VolumeName,
+			size:       v.VolumeSize,
+			volumeType: v.VolumeType,
+			iops:       v.VolumeIOPS,
+			throughput: v.VolumeThroughput,
+			encrypted:  v.VolumeEncrypted,
+			kmsKeyID:   v.VolumeKmsKeyID,
+		})
+	}
 
-		launchTemplateData.BlockDeviceMappings = []gfn.AWSEC2LaunchTemplate_BlockDeviceMapping{{
-			DeviceName: gfn.NewString(*n.spec.VolumeName),
-			Ebs: &gfn.AWSEC2LaunchTemplate_Ebs{
-				VolumeSize: gfn.NewInteger(*volumeSize),
-				VolumeType: gfn.NewString(*n.spec.VolumeType),
-				Encrypted:  gfn.NewBoolean(*n.spec.VolumeEncrypted),
-				KmsKeyId:   kmsKeyID,
-				Iops:       volumeIOPS,
-			},
-		}}
+	launchTemplateData.setMetadataOptions(n.spec.DisableIMDSv1)
+
+	var placementGroupName *gfn.Value
+	if placement := n.spec.Placement; placement != nil {
+		if placement.Strategy != "" {
+			placementGroupName = n.newResource("PlacementGroup", &ec2PlacementGroup{Strategy: placement.Strategy})
+		} else {
+			placementGroupName = gfn.NewString(placement.GroupName)
+		}
 	}
+	launchTemplateData.setPlacement(placementGroupName, n.spec.Tenancy)
+	launchTemplateData.setCapacityReservation(n.spec.CapacityReservation)
+	launchTemplateData.setTagSpecifications(launchTags(n.clusterSpec, n.spec.Tags))
 
-	n.newResource("NodeGroupLaunchTemplate", &gfn.AWSEC2LaunchTemplate{
+	n.newResource("NodeGroupLaunchTemplate", &ec2LaunchTemplate{
 		LaunchTemplateName: launchTemplateName,
 		LaunchTemplateData: launchTemplateData,
 	})
 
-	vpcZoneIdentifier, err := AssignSubnets(n.spec.AvailabilityZones, n.clusterStackName, n.clusterSpec, n.spec.PrivateNetworking)
+	var (
+		vpcZoneIdentifier interface{}
+		err               error
+	)
+	if len(n.spec.LocalZones) > 0 {
+		vpcZoneIdentifier, err = n.clusterSpec.LocalZoneSubnetIDs(n.spec.LocalZones)
+	} else {
+		vpcZoneIdentifier, err = AssignSubnets(n.spec.Subnets, n.spec.AvailabilityZones, n.clusterStackName, n.clusterSpec, n.spec.PrivateNetworking)
+	}
 	if err != nil {
 		return err
 	}
@@ -181,19 +207,129 @@ func (n *NodeGroupResourceSet) addResourcesForNodeGroup() error {
 				"PropagateAtLaunch": "true",
 			},
 		)
+		if zone := n.spec.Zone(); zone != "" {
+			// lets cluster-autoscaler know the zone of the nodes when scaling up from zero,
+			// so that it picks this nodegroup for pods whose volumes are in that zone
+			for _, label := range []string{api.TopologyZoneLabel, api.FailureDomainZoneLabel} {
+				tags = append(tags, map[string]interface{}{
+					"Key":               api.NodeTemplateLabelTagPrefix + label,
+					"Value":             zone,
+					"PropagateAtLaunch": "true",
+				})
+			}
+		}
+	}
+
+	if usesNodeTerminationHandlerQueue(n.clusterSpec, n.spec) {
+		// aws-node-termination-handler only drains the nodes of the nodegroups with this tag
+		tags = append(tags, map[string]interface{}{
+			"Key":               "aws-node-termination-handler/managed",
+			"Value":             "true",
+			"PropagateAtLaunch": "true",
+		})
+	}
+
+	// lets cluster-autoscaler know the labels and taints of the nodes when scaling up from zero,
+	// the tags are sorted so that the template doesn't change between runs
+	nodeTemplateTags := api.NodeTemplateTags(n.spec.Labels, n.spec.Taints)
+	for _, key := range sortedKeys(nodeTemplateTags) {
+		tags = append(tags, map[string]interface{}{
+			"Key":               key,
+			"Value":             nodeTemplateTags[key],
+			"PropagateAtLaunch": "false",
+		})
 	}
 
 	asg := nodeGroupResource(launchTemplateName, vpcZoneIdentifier, tags, n.spec)
-	n.newResource("NodeGroup", asg)
+	if usesNodeTerminationHandlerQueue(n.clusterSpec, n.spec) {
+		asg.Properties["LifecycleHookSpecificationList"] = []map[string]interface{}{nodeTerminationHandlerLifecycleHook()}
+	}
+	if n.spec.CloudFormationParameters != nil {
+		if err := n.addParameters(asg, launchTemplateData); err != nil {
+			return err
+		}
+	}
+	asgName := n.newResource("NodeGroup", asg)
+
+	if n.spec.WarmPool != nil {
+		n.addWarmPool(asgName, n.spec.WarmPool)
+	}
 
 	return nil
 }
 
-// AssignSubnets subnets based on the specified availability zones
-func AssignSubnets(availabilityZones []string, clusterStackName string, clusterSpec *api.ClusterConfig, privateNetworking bool) (interface{}, error) {
+// Names of the parameters of nodegroup templates, see NodeGroup.CloudFormationParameters
+const (
+	ParameterDesiredCapacity = "DesiredCapacity"
+	ParameterMinSize         = "MinSize"
+	ParameterMaxSize         = "MaxSize"
+	ParameterInstanceType    = "InstanceType"
+)
+
+// addParameters replaces the size and instance type of the nodegroup with template
+// parameters, their defaults are the values from the config
+func (n *NodeGroupResourceSet) addParameters(asg *awsCloudFormationResource, launchTemplateData *ec2LaunchTemplateData) error {
+	var declared []string
+	for _, name := range []string{ParameterDesiredCapacity, ParameterMinSize, ParameterMaxSize} {
+		if value, ok := asg.Properties[name].(string); ok {
+			asg.Properties[name] = n.rs.newParameter(name, "Number", value)
+			declared = append(declared, name)
+		}
+	}
+	// the instance types of mixed instances nodegroups are set by the overrides of the ASG
+	if !api.HasMixedInstances(n.spec) {
+		launchTemplateData.InstanceType = n.rs.newParameter(ParameterInstanceType, "String", n.spec.InstanceType)
+		declared = append(declared, ParameterInstanceType)
+	}
+
+	for name := range n.spec.CloudFormationParameters {
+		if _, ok := n.rs.template.Parameters[name]; !ok {
+			return fmt.Errorf("unsupported CloudFormation parameter %q for nodegroup %q, supported parameters are: %s",
+				name, n.nodeGroupName, strings.Join(declared, ", "))
+		}
+	}
+	return n.validateSizeParameters(asg)
+}
+
+// validateSizeParameters checks that the sizes the stack is created with, i.e. the values of the
+// parameters that are overridden and the defaults of the others, are consistent, as CloudFormation
+// would only reject them once the stack fails to be created
+func (n *NodeGroupResourceSet) validateSizeParameters(asg *awsCloudFormationResource) error {
+	sizes := map[string]int{}
+	for _, name := range []string{ParameterDesiredCapacity, ParameterMinSize, ParameterMaxSize} {
+		if _, ok := asg.Properties[name]; !ok {
+			continue
+		}
+		value, ok := n.spec.CloudFormationParameters[name]
+		if !ok {
+			value = n.rs.template.Parameters[name].(map[string]string)["Default"]
+		}
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return fmt.Errorf("invalid value %q of CloudFormation parameter %q for nodegroup %q, it must be a non-negative integer", value, name, n.nodeGroupName)
+		}
+		sizes[name] = size
+	}
+
+	minSize, maxSize := sizes[ParameterMinSize], sizes[ParameterMaxSize]
+	if minSize > maxSize {
+		return fmt.Errorf("%s (%d) cannot be greater than %s (%d) for nodegroup %q", ParameterMinSize, minSize, ParameterMaxSize, maxSize, n.nodeGroupName)
+	}
+	if desired, ok := sizes[ParameterDesiredCapacity]; ok && (desired < minSize || desired > maxSize) {
+		return fmt.Errorf("%s (%d) must be between %s (%d) and %s (%d) for nodegroup %q", ParameterDesiredCapacity, desired, ParameterMinSize, minSize, ParameterMaxSize, maxSize, n.nodeGroupName)
+	}
+	return nil
+}
+
+// AssignSubnets subnets based on the specified subnet IDs, or on the specified availability zones
+func AssignSubnets(subnetIDs, availabilityZones []string, clusterStackName string, clusterSpec *api.ClusterConfig, privateNetworking bool) (interface{}, error) {
 	// currently goformation type system doesn't allow specifying `VPCZoneIdentifier: { "Fn::ImportValue": ... }`,
 	// and tags don't have `PropagateAtLaunch` field, so we have a custom method here until this gets resolved
 
+	if len(subnetIDs) > 0 {
+		return subnetIDs, nil
+	}
+
 	if numNodeGroupsAZs := len(availabilityZones); numNodeGroupsAZs > 0 {
 		subnets := clusterSpec.VPC.Subnets.Private
 		if !privateNetworking {
@@ -234,20 +370,15 @@ func (n *NodeGroupResourceSet) GetAllOutputs(stack cfn.Stack) error {
 	return n.rs.GetAllOutputs(stack)
 }
 
-func newLaunchTemplateData(n *NodeGroupResourceSet) *gfn.AWSEC2LaunchTemplate_LaunchTemplateData {
-	launchTemplateData := &gfn.AWSEC2LaunchTemplate_LaunchTemplateData{
+func newLaunchTemplateData(n *NodeGroupResourceSet) *ec2LaunchTemplateData {
+	launchTemplateData := &ec2LaunchTemplateData{AWSEC2LaunchTemplate_LaunchTemplateData: &gfn.AWSEC2LaunchTemplate_LaunchTemplateData{
 		IamInstanceProfile: &gfn.AWSEC2LaunchTemplate_IamInstanceProfile{
 			Arn: n.instanceProfileARN,
 		},
 		ImageId:  gfn.NewString(n.spec.AMI),
 		UserData: n.userData,
-		NetworkInterfaces: []gfn.AWSEC2LaunchTemplate_NetworkInterface{{
-			// Explicitly un-setting this so that it doesn't get defaulted to true
-			AssociatePublicIpAddress: nil,
-			DeviceIndex:              gfn.NewInteger(0),
-			Groups:                   n.securityGroups,
-		}},
-	}
+	}}
+	launchTemplateData.setNetworkInterfaces(n.spec, n.securityGroups)
 	if !api.HasMixedInstances(n.spec) {
 		launchTemplateData.InstanceType = gfn.NewString(n.spec.InstanceType)
 	} else {
@@ -345,3 +476,12 @@ func mixedInstancesPolicy(launchTemplateName *gfn.Value, ng *api.NodeGroup) *map
 
 	return &policy
 }
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
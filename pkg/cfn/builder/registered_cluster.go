@@ -0,0 +1,135 @@
+package builder
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	gfn "github.com/awslabs/goformation/cloudformation"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+)
+
+const registeredClusterTemplateDescription = "EKS cluster registration"
+
+// RegisteredClusterResourceSet stores the resource information of a cluster
+// that was created outside of eksctl; it doesn't own any AWS resources, it
+// only exports the cluster attributes that nodegroup stacks import
+type RegisteredClusterResourceSet struct {
+	rs   *resourceSet
+	spec *api.ClusterConfig
+}
+
+// NewRegisteredClusterResourceSet returns a resource set for a registered cluster
+func NewRegisteredClusterResourceSet(spec *api.ClusterConfig) *RegisteredClusterResourceSet {
+	return &RegisteredClusterResourceSet{
+		rs:   newResourceSet(),
+		spec: spec,
+	}
+}
+
+// AddAllResources adds all the information about the cluster to the resource set
+func (c *RegisteredClusterResourceSet) AddAllResources() error {
+	if c.spec.VPC == nil || c.spec.VPC.ID == "" {
+		return fmt.Errorf("VPC of cluster %q must be known", c.spec.Metadata.Name)
+	}
+	if c.spec.VPC.SecurityGroup == "" {
+		return fmt.Errorf("control plane security group of cluster %q must be known", c.spec.Metadata.Name)
+	}
+	if c.spec.Status == nil || c.spec.Status.Endpoint == "" {
+		return fmt.Errorf("status of cluster %q must be known", c.spec.Metadata.Name)
+	}
+
+	// a template must have at least one resource, a wait condition handle
+	// has no cost and no side effects
+	c.rs.newResource("RegistrationHandle", &awsCloudFormationResource{
+		Type:       "AWS::CloudFormation::WaitConditionHandle",
+		Properties: map[string]interface{}{},
+	})
+
+	c.addOutputsForVPC()
+	c.addOutputsForControlPlane()
+
+	c.rs.defineOutput(outputs.ClusterStackName, gfn.RefStackName, false, func(v string) error {
+		c.spec.Status.StackName = v
+		return nil
+	})
+
+	c.rs.template.Description = fmt.Sprintf(
+		"%s (registered cluster: %s) %s",
+		registeredClusterTemplateDescription,
+		c.spec.Metadata.Name,
+		templateDescriptionSuffix)
+
+	return nil
+}
+
+func (c *RegisteredClusterResourceSet) addOutputsForVPC() {
+	c.rs.defineOutput(outputs.ClusterVPC, c.spec.VPC.ID, true, func(v string) error {
+		c.spec.VPC.ID = v
+		return nil
+	})
+	c.rs.defineOutput(outputs.ClusterSecurityGroup, c.spec.VPC.SecurityGroup, true, func(v string) error {
+		c.spec.VPC.SecurityGroup = v
+		return nil
+	})
+	if sg := c.spec.VPC.SharedNodeSecurityGroup; sg != "" {
+		c.rs.defineOutput(outputs.ClusterSharedNodeSecurityGroup, sg, true, func(v string) error {
+			c.spec.VPC.SharedNodeSecurityGroup = v
+			return nil
+		})
+		// EKS attaches the cluster security group to managed nodegroups, unmanaged
+		// nodegroups import it to be able to communicate with them
+		c.rs.defineOutputWithoutCollector(outputs.ClusterDefaultSecurityGroup, sg, true)
+	}
+	if ids := c.spec.PrivateSubnetIDs(); len(ids) > 0 {
+		c.rs.defineOutputWithoutCollector(outputs.ClusterSubnetsPrivate, strings.Join(ids, ","), true)
+	}
+	if ids := c.spec.PublicSubnetIDs(); len(ids) > 0 {
+		c.rs.defineOutputWithoutCollector(outputs.ClusterSubnetsPublic, strings.Join(ids, ","), true)
+	}
+}
+
+func (c *RegisteredClusterResourceSet) addOutputsForControlPlane() {
+	status := c.spec.Status
+
+	c.rs.defineOutput(outputs.ClusterCertificateAuthorityData, base64.StdEncoding.EncodeToString(status.CertificateAuthorityData), false, func(v string) error {
+		caData, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return errors.Wrap(err, "decoding certificate authority data")
+		}
+		status.CertificateAuthorityData = caData
+		return nil
+	})
+	c.rs.defineOutput(outputs.ClusterEndpoint, status.Endpoint, true, func(v string) error {
+		status.Endpoint = v
+		return nil
+	})
+	c.rs.defineOutput(outputs.ClusterARN, status.ARN, true, func(v string) error {
+		status.ARN = v
+		return nil
+	})
+}
+
+// RenderJSON returns the rendered JSON
+func (c *RegisteredClusterResourceSet) RenderJSON() ([]byte, error) {
+	return c.rs.renderJSON()
+}
+
+// WithIAM states, if IAM roles will be created or not
+func (c *RegisteredClusterResourceSet) WithIAM() bool {
+	return false
+}
+
+// WithNamedIAM states, if specifically named IAM roles will be created or not
+func (c *RegisteredClusterResourceSet) WithNamedIAM() bool {
+	return false
+}
+
+// GetAllOutputs collects all outputs of the registered cluster stack
+func (c *RegisteredClusterResourceSet) GetAllOutputs(stack cfn.Stack) error {
+	return c.rs.GetAllOutputs(stack)
+}
@@ -0,0 +1,112 @@
+package builder_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+)
+
+var _ = Describe("Registered cluster template builder", func() {
+	var cfg *api.ClusterConfig
+
+	BeforeEach(func() {
+		caData, err := base64.StdEncoding.DecodeString(caCert)
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = clusterName
+		cfg.VPC.ID = "vpc-0e265ad953062b94b"
+		cfg.VPC.SecurityGroup = "sg-0b44c48bcba5b7362"
+		cfg.VPC.SharedNodeSecurityGroup = "sg-0e8a7a1f0cd1b4b26"
+		cfg.VPC.Subnets = &api.ClusterSubnets{
+			Private: map[string]api.Network{
+				"us-west-2a": {ID: "subnet-0f98135715dfcf55a"},
+			},
+			Public: map[string]api.Network{
+				"us-west-2a": {ID: "subnet-0ade11bad78dced9e"},
+			},
+		}
+		cfg.Status = &api.ClusterStatus{
+			Endpoint:                 endpoint,
+			CertificateAuthorityData: caData,
+			ARN:                      arn,
+		}
+	})
+
+	renderOutputs := func(rs *RegisteredClusterResourceSet) map[string]interface{} {
+		templateBody, err := rs.RenderJSON()
+		Expect(err).NotTo(HaveOccurred())
+
+		template := struct {
+			Resources map[string]interface{}
+			Outputs   map[string]interface{}
+		}{}
+		Expect(json.Unmarshal(templateBody, &template)).To(Succeed())
+		Expect(template.Resources).To(HaveLen(1))
+		return template.Outputs
+	}
+
+	It("should export all outputs imported by nodegroup stacks", func() {
+		rs := NewRegisteredClusterResourceSet(cfg)
+		Expect(rs.AddAllResources()).To(Succeed())
+		Expect(rs.WithIAM()).To(BeFalse())
+
+		templateOutputs := renderOutputs(rs)
+		for _, name := range []string{
+			outputs.ClusterVPC,
+			outputs.ClusterSecurityGroup,
+			outputs.ClusterSharedNodeSecurityGroup,
+			outputs.ClusterDefaultSecurityGroup,
+			outputs.ClusterSubnetsPrivate,
+			outputs.ClusterSubnetsPublic,
+			outputs.ClusterEndpoint,
+			outputs.ClusterARN,
+			outputs.ClusterCertificateAuthorityData,
+			outputs.ClusterStackName,
+		} {
+			Expect(templateOutputs).To(HaveKey(name))
+		}
+		Expect(templateOutputs[outputs.ClusterVPC]).To(HaveKey("Export"))
+		Expect(templateOutputs[outputs.ClusterSubnetsPrivate]).To(HaveKeyWithValue("Value", "subnet-0f98135715dfcf55a"))
+	})
+
+	It("should collect outputs of the registration stack", func() {
+		rs := NewRegisteredClusterResourceSet(cfg)
+		Expect(rs.AddAllResources()).To(Succeed())
+
+		stack := newStackWithOutputs(map[string]string{
+			outputs.ClusterVPC:                      "vpc-0e265ad953062b94b",
+			outputs.ClusterSecurityGroup:            "sg-0b44c48bcba5b7362",
+			outputs.ClusterSharedNodeSecurityGroup:  "sg-0e8a7a1f0cd1b4b26",
+			outputs.ClusterEndpoint:                 endpoint,
+			outputs.ClusterARN:                      arn,
+			outputs.ClusterCertificateAuthorityData: caCert,
+			outputs.ClusterStackName:                "eksctl-" + clusterName + "-cluster",
+		})
+		Expect(rs.GetAllOutputs(stack)).To(Succeed())
+		Expect(cfg.Status.StackName).To(Equal("eksctl-" + clusterName + "-cluster"))
+		Expect(cfg.Status.Endpoint).To(Equal(endpoint))
+	})
+
+	It("should only export subnets of known topologies", func() {
+		cfg.VPC.Subnets.Public = nil
+		rs := NewRegisteredClusterResourceSet(cfg)
+		Expect(rs.AddAllResources()).To(Succeed())
+
+		templateOutputs := renderOutputs(rs)
+		Expect(templateOutputs).To(HaveKey(outputs.ClusterSubnetsPrivate))
+		Expect(templateOutputs).NotTo(HaveKey(outputs.ClusterSubnetsPublic))
+	})
+
+	It("should fail when the cluster status is unknown", func() {
+		cfg.Status = nil
+		rs := NewRegisteredClusterResourceSet(cfg)
+		Expect(rs.AddAllResources()).To(MatchError(ContainSubstring("status of cluster")))
+	})
+})
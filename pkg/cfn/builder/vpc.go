@@ -1,6 +1,7 @@
 package builder
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -44,12 +45,12 @@ func (c *ClusterResourceSet) addSubnets(refRT *gfn.Value, topology api.SubnetTop
 			// Choose the appropriate route table for private subnets
 			refRT = gfn.MakeRef("PrivateRouteTable" + strings.ToUpper(strings.Join(strings.Split(az, "-"), "")))
 			subnet.Tags = []gfn.Tag{{
-				Key:   gfn.NewString("kubernetes.io/role/internal-elb"),
+				Key:   gfn.NewString(vpc.SubnetRoleInternalELBTag),
 				Value: gfn.NewString("1"),
 			}}
 		case api.SubnetTopologyPublic:
 			subnet.Tags = []gfn.Tag{{
-				Key:   gfn.NewString("kubernetes.io/role/elb"),
+				Key:   gfn.NewString(vpc.SubnetRoleELBTag),
 				Value: gfn.NewString("1"),
 			}}
 			subnet.MapPublicIpOnLaunch = gfn.True()
@@ -124,9 +125,142 @@ func (c *ClusterResourceSet) addResourcesForVPC() error {
 	}
 
 	c.addSubnets(nil, api.SubnetTopologyPrivate, c.spec.VPC.Subnets.Private)
+	c.addPrivateSubnetRoutes()
+
+	c.addLocalZoneSubnets(refPublicRT)
 	return nil
 }
 
+// addPrivateSubnetRoutes attaches the VPC to the transit gateway, and adds the routes of the private
+// subnets to existing targets to the private route table of each availability zone
+func (c *ClusterResourceSet) addPrivateSubnetRoutes() {
+	routes := c.spec.PrivateSubnetRoutes()
+	if len(routes) == 0 {
+		return
+	}
+
+	var transitGatewayDependsOn []string
+	if c.spec.VPC.TransitGatewayID != "" {
+		c.newResource("TransitGatewayAttachment", &awsCloudFormationResource{
+			Type: "AWS::EC2::TransitGatewayAttachment",
+			Properties: map[string]interface{}{
+				"TransitGatewayId": c.spec.VPC.TransitGatewayID,
+				"VpcId":            c.vpc,
+				"SubnetIds":        c.subnets[api.SubnetTopologyPrivate],
+			},
+		})
+		// routes to the transit gateway fail until the VPC is attached to it
+		transitGatewayDependsOn = []string{"TransitGatewayAttachment"}
+	}
+
+	for _, az := range c.spec.AvailabilityZones {
+		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
+		for i, route := range routes {
+			properties := map[string]interface{}{
+				"RouteTableId":         gfn.MakeRef("PrivateRouteTable" + alphanumericUpperAZ),
+				"DestinationCidrBlock": route.DestinationCIDR,
+			}
+			var dependsOn []string
+			switch {
+			case route.TransitGatewayID != "":
+				properties["TransitGatewayId"] = route.TransitGatewayID
+				dependsOn = transitGatewayDependsOn
+			case route.InstanceID != "":
+				properties["InstanceId"] = route.InstanceID
+			default:
+				properties["NetworkInterfaceId"] = route.NetworkInterfaceID
+			}
+			c.newResource(fmt.Sprintf("PrivateSubnetRoute%d%s", i, alphanumericUpperAZ), &awsCloudFormationResource{
+				Type:       "AWS::EC2::Route",
+				Properties: properties,
+				DependsOn:  dependsOn,
+			})
+		}
+	}
+}
+
+// ec2CarrierGateway is the gateway of a VPC to the carrier network of Wavelength Zones
+type ec2CarrierGateway struct {
+	VpcId *gfn.Value `json:"VpcId"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (g *ec2CarrierGateway) MarshalJSON() ([]byte, error) {
+	type Properties ec2CarrierGateway
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::EC2::CarrierGateway",
+		Properties: Properties(*g),
+	})
+}
+
+// ec2CarrierRoute is a route to a carrier gateway, which AWS::EC2::Route of goformation doesn't support
+type ec2CarrierRoute struct {
+	RouteTableId         *gfn.Value `json:"RouteTableId"`
+	DestinationCidrBlock *gfn.Value `json:"DestinationCidrBlock"`
+	CarrierGatewayId     *gfn.Value `json:"CarrierGatewayId"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (r *ec2CarrierRoute) MarshalJSON() ([]byte, error) {
+	type Properties ec2CarrierRoute
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::EC2::Route",
+		Properties: Properties(*r),
+	})
+}
+
+// addLocalZoneSubnets adds the subnets of Local Zones, which route to the internet gateway like
+// public subnets, and of Wavelength Zones, which route to a carrier gateway
+func (c *ClusterResourceSet) addLocalZoneSubnets(refPublicRT *gfn.Value) {
+	var refCarrierRT *gfn.Value
+	if c.spec.HasWavelengthZoneSubnets() {
+		refCG := c.newResource("CarrierGateway", &ec2CarrierGateway{
+			VpcId: c.vpc,
+		})
+		refCarrierRT = c.newResource("CarrierRouteTable", &gfn.AWSEC2RouteTable{
+			VpcId: c.vpc,
+		})
+		c.newResource("CarrierSubnetRoute", &ec2CarrierRoute{
+			RouteTableId:         refCarrierRT,
+			DestinationCidrBlock: internetCIDR,
+			CarrierGatewayId:     refCG,
+		})
+	}
+
+	for _, zone := range c.spec.LocalZones() {
+		network := c.spec.VPC.LocalZoneSubnets[zone]
+		if network.ID != "" {
+			c.localZoneSubnets = append(c.localZoneSubnets, gfn.NewString(network.ID))
+			continue
+		}
+		alias := "LocalZone" + strings.ToUpper(strings.Join(strings.Split(zone, "-"), ""))
+		subnet := &gfn.AWSEC2Subnet{
+			AvailabilityZone: gfn.NewString(zone),
+			CidrBlock:        gfn.NewString(network.CIDR.String()),
+			VpcId:            c.vpc,
+		}
+		refRT := refPublicRT
+		if api.IsWavelengthZone(zone) {
+			// instances get a carrier IP from their launch template, as Wavelength Zones don't map public IPs
+			refRT = refCarrierRT
+		} else {
+			subnet.MapPublicIpOnLaunch = gfn.True()
+		}
+		refSubnet := c.newResource("Subnet"+alias, subnet)
+		c.newResource("RouteTableAssociation"+alias, &gfn.AWSEC2SubnetRouteTableAssociation{
+			SubnetId:     refSubnet,
+			RouteTableId: refRT,
+		})
+		c.localZoneSubnets = append(c.localZoneSubnets, refSubnet)
+	}
+}
+
 func (c *ClusterResourceSet) addNATGateways() error {
 
 	switch *c.spec.VPC.NAT.Gateway {
@@ -138,7 +272,7 @@ func (c *ClusterResourceSet) addNATGateways() error {
 	case api.ClusterDisableNAT:
 		c.noNAT()
 	default:
-		// TODO validate this before starting to add resources
+		// this is validated by api.ValidateClusterConfig, unless the config was built programmatically
 		return fmt.Errorf("%s is not a valid NAT gateway mode", *c.spec.VPC.NAT.Gateway)
 	}
 	return nil
@@ -153,6 +287,11 @@ func (c *ClusterResourceSet) importResourcesForVPC() {
 	for _, subnet := range c.spec.PublicSubnetIDs() {
 		c.subnets[api.SubnetTopologyPublic] = append(c.subnets[api.SubnetTopologyPublic], gfn.NewString(subnet))
 	}
+	for _, zone := range c.spec.LocalZones() {
+		if id := c.spec.VPC.LocalZoneSubnets[zone].ID; id != "" {
+			c.localZoneSubnets = append(c.localZoneSubnets, gfn.NewString(id))
+		}
+	}
 
 }
 
@@ -177,6 +316,17 @@ func (c *ClusterResourceSet) addOutputsForVPC() {
 			return vpc.ImportSubnetsFromList(c.provider, c.spec, api.SubnetTopologyPublic, strings.Split(v, ","))
 		})
 	}
+	if c.spec.IsSharedVPC() {
+		c.rs.defineOutput(outputs.ClusterSharedVPCOwnerAccount, c.spec.VPC.OwnerAccountID, false, func(v string) error {
+			c.spec.VPC.OwnerAccountID = v
+			return nil
+		})
+	}
+	if len(c.localZoneSubnets) > 0 {
+		c.rs.defineJoinedOutput(outputs.ClusterSubnetsLocalZones, c.localZoneSubnets, true, func(v string) error {
+			return vpc.ImportLocalZoneSubnetsFromList(c.provider, c.spec, strings.Split(v, ","))
+		})
+	}
 }
 
 var (
@@ -356,6 +506,65 @@ func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
 			})
 		}
 	}
+	for i, port := range cniPorts[n.clusterSpec.CNI()] {
+		n.newResource(fmt.Sprintf("IngressCNI%d", i), &gfn.AWSEC2SecurityGroupIngress{
+			GroupId:     refNodeGroupLocalSG,
+			CidrIp:      allInternalIPv4,
+			Description: gfn.NewString(fmt.Sprintf("Allow %s traffic of %s to %s (%s)", n.clusterSpec.CNI(), port.description, desc, port.protocol)),
+			IpProtocol:  gfn.NewString(port.protocol),
+			FromPort:    gfn.NewInteger(port.port),
+			ToPort:      gfn.NewInteger(port.port),
+		})
+	}
+}
+
+// cniPort is a port the CNI plugin uses between the nodes
+type cniPort struct {
+	protocol    string
+	port        int
+	description string
+}
+
+// cniPorts are the ports of the CNI plugins other than the VPC CNI, the shared security group of the nodes
+// allows all traffic between them, but not the security groups of nodegroups that don't use it
+var cniPorts = map[string][]cniPort{
+	api.CNICilium: {
+		{protocol: "tcp", port: 4240, description: "health checks"},
+	},
+	api.CNICalico: {
+		{protocol: "udp", port: 4789, description: "VXLAN overlay"},
+		{protocol: "tcp", port: 5473, description: "Typha"},
+	},
+}
+
+// addResourcesForEFASecurityGroup adds a security group allowing all traffic between the instances
+// of the nodegroup, which EFA requires, whether or not the nodegroup has its own security group
+func (n *NodeGroupResourceSet) addResourcesForEFASecurityGroup() {
+	desc := "EFA-enabled worker nodes in group " + n.nodeGroupName
+
+	refEFASG := n.newResource("EFASG", &gfn.AWSEC2SecurityGroup{
+		VpcId:            makeImportValue(n.clusterStackName, outputs.ClusterVPC),
+		GroupDescription: gfn.NewString("EFA traffic between " + desc),
+		Tags: []gfn.Tag{{
+			Key:   gfn.NewString("kubernetes.io/cluster/" + n.clusterSpec.Metadata.Name),
+			Value: gfn.NewString("owned"),
+		}},
+	})
+
+	n.securityGroups = append(n.securityGroups, refEFASG)
+
+	n.newResource("EFAIngressSelf", &gfn.AWSEC2SecurityGroupIngress{
+		GroupId:               refEFASG,
+		SourceSecurityGroupId: refEFASG,
+		Description:           gfn.NewString("Allow " + desc + " to receive EFA traffic from each other"),
+		IpProtocol:            gfn.NewString("-1"),
+	})
+	n.newResource("EFAEgressSelf", &gfn.AWSEC2SecurityGroupEgress{
+		GroupId:                    refEFASG,
+		DestinationSecurityGroupId: refEFASG,
+		Description:                gfn.NewString("Allow " + desc + " to send EFA traffic to each other"),
+		IpProtocol:                 gfn.NewString("-1"),
+	})
 }
 
 func (c *ClusterResourceSet) haNAT() {
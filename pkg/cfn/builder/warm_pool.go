@@ -0,0 +1,46 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// autoScalingWarmPool is the warm pool of the Auto Scaling Group of a nodegroup
+type autoScalingWarmPool struct {
+	AutoScalingGroupName     *gfn.Value `json:"AutoScalingGroupName"`
+	MinSize                  string     `json:"MinSize,omitempty"`
+	MaxGroupPreparedCapacity string     `json:"MaxGroupPreparedCapacity,omitempty"`
+	PoolState                string     `json:"PoolState,omitempty"`
+}
+
+// MarshalJSON returns the JSON encoding for this CloudFormation resource
+func (w *autoScalingWarmPool) MarshalJSON() ([]byte, error) {
+	type Properties autoScalingWarmPool
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::AutoScaling::WarmPool",
+		Properties: Properties(*w),
+	})
+}
+
+// addWarmPool adds the warm pool of the Auto Scaling Group of the nodegroup, the sizes are
+// strings like those of the Auto Scaling Group
+func (n *NodeGroupResourceSet) addWarmPool(asgName *gfn.Value, wp *api.NodeGroupWarmPool) {
+	warmPool := &autoScalingWarmPool{
+		AutoScalingGroupName: asgName,
+		PoolState:            wp.State,
+	}
+	if wp.MinSize != nil {
+		warmPool.MinSize = fmt.Sprintf("%d", *wp.MinSize)
+	}
+	if wp.MaxPrepared != nil {
+		warmPool.MaxGroupPreparedCapacity = fmt.Sprintf("%d", *wp.MaxPrepared)
+	}
+	n.newResource("WarmPool", warmPool)
+}
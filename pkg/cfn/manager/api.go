@@ -9,11 +9,12 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudtrail"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -92,6 +93,13 @@ func (c *StackCollection) DoCreateStackRequest(i *Stack, templateBody []byte, ta
 		input = input.SetRoleARN(cfnRole)
 	}
 
+	switch c.provider.StackOnFailure() {
+	case api.StackOnFailurePreserve:
+		input.SetOnFailure(cloudformation.OnFailureDoNothing)
+	case api.StackOnFailureDelete:
+		input.SetOnFailure(cloudformation.OnFailureDelete)
+	}
+
 	for k, v := range parameters {
 		p := &cloudformation.Parameter{
 			ParameterKey:   aws.String(k),
@@ -135,6 +143,15 @@ func (c *StackCollection) CreateStack(name string, stack builder.ResourceSet, ta
 func (c *StackCollection) UpdateStack(stackName, changeSetName, description string, template []byte, parameters map[string]string) error {
 	logger.Info(description)
 	i := &Stack{StackName: &stackName}
+	// the current values of the parameters are only needed when the template declares parameters,
+	// so that the ones that aren't overridden keep their values instead of reverting to their defaults
+	if gjson.GetBytes(template, "Parameters").Exists() {
+		stack, err := c.DescribeStack(i)
+		if err != nil {
+			return err
+		}
+		i = stack
+	}
 	if err := c.doCreateChangeSetRequest(i, changeSetName, description, template, parameters, true); err != nil {
 		return err
 	}
@@ -191,6 +208,33 @@ func (c *StackCollection) GetManagedNodeGroupTemplate(nodeGroupName string) (str
 	return templateBody, nil
 }
 
+// GetNodeGroupTemplate returns the template of a self-managed nodegroup
+func (c *StackCollection) GetNodeGroupTemplate(nodeGroupName string) (string, error) {
+	nodeGroupType, err := c.GetNodeGroupStackType(nodeGroupName)
+	if err != nil {
+		return "", err
+	}
+
+	if nodeGroupType != api.NodeGroupTypeUnmanaged {
+		return "", fmt.Errorf("%q is not a self-managed nodegroup", nodeGroupName)
+	}
+
+	return c.GetStackTemplate(c.makeNodeGroupStackName(nodeGroupName))
+}
+
+// GetNodeGroupStackResourceID returns the physical ID of a resource of the stack of a nodegroup
+func (c *StackCollection) GetNodeGroupStackResourceID(nodeGroupName, logicalResourceID string) (string, error) {
+	stackName := c.makeNodeGroupStackName(nodeGroupName)
+	output, err := c.provider.CloudFormation().DescribeStackResource(&cloudformation.DescribeStackResourceInput{
+		StackName:         &stackName,
+		LogicalResourceId: &logicalResourceID,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "describing resource %q of stack %q", logicalResourceID, stackName)
+	}
+	return *output.StackResourceDetail.PhysicalResourceId, nil
+}
+
 // UpdateNodeGroupStack updates the nodegroup stack with the specified template
 func (c *StackCollection) UpdateNodeGroupStack(nodeGroupName, template string) error {
 	stackName := c.makeNodeGroupStackName(nodeGroupName)
@@ -494,6 +538,16 @@ func (c *StackCollection) doCreateChangeSetRequest(i *Stack, changeSetName strin
 		}
 		input.Parameters = append(input.Parameters, p)
 	}
+	// parameters that are not set keep their current values instead of reverting to their defaults
+	for _, p := range i.Parameters {
+		_, isSet := parameters[*p.ParameterKey]
+		if !isSet && gjson.GetBytes(templateBody, "Parameters."+*p.ParameterKey).Exists() {
+			input.Parameters = append(input.Parameters, &cloudformation.Parameter{
+				ParameterKey:     p.ParameterKey,
+				UsePreviousValue: aws.Bool(true),
+			})
+		}
+	}
 
 	logger.Debug("creating changeSet, input = %#v", input)
 	s, err := c.provider.CloudFormation().CreateChangeSet(input)
@@ -0,0 +1,50 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection DoCreateStackRequest", func() {
+	var (
+		sc    *StackCollection
+		input *cfn.CreateStackInput
+	)
+
+	BeforeEach(func() {
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+
+		p := mockprovider.NewMockProvider()
+		p.MockCloudFormation().On("CreateStack", mock.Anything).Run(func(args mock.Arguments) {
+			input = args.Get(0).(*cfn.CreateStackInput)
+		}).Return(&cfn.CreateStackOutput{StackId: aws.String("stack-id")}, nil)
+
+		sc = NewStackCollection(p, cfg)
+	})
+
+	AfterEach(func() {
+		mockprovider.ProviderConfig.StackOnFailure = ""
+	})
+
+	DescribeTable("sets what happens to the stack when it fails to be created",
+		func(onFailure string, expected *string) {
+			mockprovider.ProviderConfig.StackOnFailure = onFailure
+			stack := &Stack{StackName: aws.String("eksctl-test-cluster")}
+			Expect(sc.DoCreateStackRequest(stack, []byte("{}"), nil, nil, false, false)).To(Succeed())
+			Expect(input.OnFailure).To(Equal(expected))
+			Expect(*stack.StackId).To(Equal("stack-id"))
+		},
+		Entry("rolls back by default", "", nil),
+		Entry("rolls back", api.StackOnFailureRollback, nil),
+		Entry("preserves the stack", api.StackOnFailurePreserve, aws.String(cfn.OnFailureDoNothing)),
+		Entry("deletes the stack", api.StackOnFailureDelete, aws.String(cfn.OnFailureDelete)),
+	)
+})
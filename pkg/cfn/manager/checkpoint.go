@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+)
+
+// CreateCheckpoint holds the stacks left by a previous run of `eksctl create cluster`,
+// so that a new run can resume the creation instead of starting over
+type CreateCheckpoint struct {
+	// ClusterStackComplete is true when the control plane and the VPC were created
+	ClusterStackComplete bool
+	// CompleteNodeGroups are the names of the nodegroups whose stack was created
+	CompleteNodeGroups []string
+	// FailedNodeGroupStacks are the stacks of the nodegroups that failed to be created,
+	// they must be deleted before the nodegroups are created again
+	FailedNodeGroupStacks []*Stack
+}
+
+// GetCreateCheckpoint describes the stacks of the cluster to find out what a previous run of
+// `eksctl create cluster` created, the checkpoint is empty when there's no stack; it fails when
+// a stack is still in progress, or when the cluster stack failed as the cluster must be deleted then
+func (c *StackCollection) GetCreateCheckpoint() (*CreateCheckpoint, error) {
+	stacks, err := c.ListStacks()
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing CloudFormation stacks for %q", c.spec.Metadata.Name)
+	}
+	return c.newCreateCheckpoint(stacks)
+}
+
+func (c *StackCollection) newCreateCheckpoint(stacks []*Stack) (*CreateCheckpoint, error) {
+	checkpoint := &CreateCheckpoint{}
+	for _, s := range stacks {
+		status := *s.StackStatus
+		if status == cfn.StackStatusDeleteComplete {
+			continue
+		}
+		if strings.HasSuffix(status, "_IN_PROGRESS") {
+			return nil, fmt.Errorf("stack %q is in status %s, wait for it to complete before resuming", *s.StackName, status)
+		}
+
+		if getClusterName(s) != "" {
+			if !isCompleteStackStatus(status) {
+				return nil, fmt.Errorf("stack %q of the control plane is in status %s and can't be resumed, "+
+					"delete the cluster with 'eksctl delete cluster --region=%s --name=%s' and create it again",
+					*s.StackName, status, c.spec.Metadata.Region, c.spec.Metadata.Name)
+			}
+			checkpoint.ClusterStackComplete = true
+			continue
+		}
+
+		if name := c.GetNodeGroupName(s); name != "" {
+			if isCompleteStackStatus(status) {
+				checkpoint.CompleteNodeGroups = append(checkpoint.CompleteNodeGroups, name)
+			} else {
+				checkpoint.FailedNodeGroupStacks = append(checkpoint.FailedNodeGroupStacks, s)
+			}
+		}
+	}
+	return checkpoint, nil
+}
+
+// NewTasksToDeleteFailedNodeGroups defines the tasks deleting the stacks of the nodegroups
+// that failed to be created, so that they can be created again
+func (c *StackCollection) NewTasksToDeleteFailedNodeGroups(checkpoint *CreateCheckpoint) *TaskTree {
+	tasks := &TaskTree{Parallel: true}
+	for _, s := range checkpoint.FailedNodeGroupStacks {
+		tasks.Append(&taskWithStackSpec{
+			info:  fmt.Sprintf("delete failed nodegroup %q (%s)", c.GetNodeGroupName(s), *s.StackStatus),
+			stack: s,
+			call:  c.DeleteStackBySpecSync,
+		})
+	}
+	return tasks
+}
+
+func isCompleteStackStatus(status string) bool {
+	switch status {
+	case cfn.StackStatusCreateComplete, cfn.StackStatusUpdateComplete, cfn.StackStatusUpdateRollbackComplete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection create checkpoint", func() {
+	var sc *StackCollection
+
+	BeforeEach(func() {
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		cfg.Metadata.Region = "us-west-2"
+		sc = NewStackCollection(mockprovider.NewMockProvider(), cfg)
+	})
+
+	clusterStack := func(status string) *Stack {
+		return &Stack{
+			StackName:   aws.String("eksctl-test-cluster"),
+			StackStatus: aws.String(status),
+			Tags:        []*cfn.Tag{{Key: aws.String(api.ClusterNameTag), Value: aws.String("test")}},
+		}
+	}
+
+	nodeGroupStack := func(name, status string) *Stack {
+		return &Stack{
+			StackName:   aws.String("eksctl-test-nodegroup-" + name),
+			StackStatus: aws.String(status),
+			Tags: []*cfn.Tag{
+				{Key: aws.String(api.ClusterNameTag), Value: aws.String("test")},
+				{Key: aws.String(api.NodeGroupNameTag), Value: aws.String(name)},
+			},
+		}
+	}
+
+	It("is empty without stacks", func() {
+		checkpoint, err := sc.newCreateCheckpoint(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint).To(Equal(&CreateCheckpoint{}))
+	})
+
+	It("resumes after the nodegroups that were created", func() {
+		failed := nodeGroupStack("ng-2", cfn.StackStatusRollbackComplete)
+		checkpoint, err := sc.newCreateCheckpoint([]*Stack{
+			clusterStack(cfn.StackStatusCreateComplete),
+			nodeGroupStack("ng-1", cfn.StackStatusCreateComplete),
+			failed,
+			nodeGroupStack("ng-3", cfn.StackStatusDeleteComplete),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint.ClusterStackComplete).To(BeTrue())
+		Expect(checkpoint.CompleteNodeGroups).To(Equal([]string{"ng-1"}))
+		Expect(checkpoint.FailedNodeGroupStacks).To(Equal([]*Stack{failed}))
+		Expect(sc.NewTasksToDeleteFailedNodeGroups(checkpoint).Describe()).To(ContainSubstring(`delete failed nodegroup "ng-2" (ROLLBACK_COMPLETE)`))
+	})
+
+	It("can't resume a cluster stack that failed", func() {
+		_, err := sc.newCreateCheckpoint([]*Stack{clusterStack(cfn.StackStatusRollbackComplete)})
+		Expect(err).To(MatchError(ContainSubstring(`stack "eksctl-test-cluster" of the control plane is in status ROLLBACK_COMPLETE and can't be resumed`)))
+	})
+
+	It("waits for stacks in progress", func() {
+		_, err := sc.newCreateCheckpoint([]*Stack{
+			clusterStack(cfn.StackStatusCreateComplete),
+			nodeGroupStack("ng-1", cfn.StackStatusCreateInProgress),
+		})
+		Expect(err).To(MatchError(`stack "eksctl-test-nodegroup-ng-1" is in status CREATE_IN_PROGRESS, wait for it to complete before resuming`))
+	})
+})
@@ -5,7 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -15,6 +14,7 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // MakeChangeSetName builds a consistent name for a changeset.
@@ -39,6 +39,34 @@ func (c *StackCollection) createClusterTask(errs chan error, supportsManagedNode
 	return c.CreateStack(name, stack, nil, nil, errs)
 }
 
+// RegisterCluster creates a cluster stack for a cluster that was not created by eksctl,
+// the stack only exports the attributes of the existing cluster, so that
+// nodegroup stacks can be created for it
+func (c *StackCollection) RegisterCluster(errs chan error) error {
+	name := c.makeClusterStackName()
+	logger.Info("building registration stack %q", name)
+	stack := builder.NewRegisteredClusterResourceSet(c.spec)
+	if err := stack.AddAllResources(); err != nil {
+		return err
+	}
+
+	tags := map[string]string{
+		api.ClusterRegisteredTag: "true",
+	}
+	return c.CreateStack(name, stack, tags, nil, errs)
+}
+
+// IsRegisteredClusterStack reports whether the given cluster stack was created
+// by `eksctl register cluster`, in which case it doesn't own the control plane
+func IsRegisteredClusterStack(s *Stack) bool {
+	for _, tag := range s.Tags {
+		if *tag.Key == api.ClusterRegisteredTag {
+			return *tag.Value == "true"
+		}
+	}
+	return false
+}
+
 // DescribeClusterStack calls DescribeStacks and filters out cluster stack
 func (c *StackCollection) DescribeClusterStack() (*Stack, error) {
 	stacks, err := c.DescribeStacks()
@@ -89,6 +117,26 @@ func (c *StackCollection) RefreshFargatePodExecutionRoleARN() error {
 	return outputs.Collect(*stack, fargateOutputs, nil)
 }
 
+// GetNodeTerminationHandlerQueueURL reads the URL of the queue of aws-node-termination-handler
+// from the outputs of the cluster stack
+func (c *StackCollection) GetNodeTerminationHandlerQueueURL() (string, error) {
+	stack, err := c.DescribeClusterStack()
+	if err != nil {
+		return "", err
+	}
+	var queueURL string
+	queueOutputs := map[string]outputs.Collector{
+		outputs.ClusterNodeTerminationHandlerQueueURL: func(v string) error {
+			queueURL = v
+			return nil
+		},
+	}
+	if err := outputs.Collect(*stack, queueOutputs, nil); err != nil {
+		return "", errors.Wrap(err, "the cluster stack has no queue for aws-node-termination-handler, run 'eksctl update cluster' to add it")
+	}
+	return queueURL, nil
+}
+
 // AppendNewClusterStackResource will update cluster
 // stack with new resources in append-only way
 func (c *StackCollection) AppendNewClusterStackResource(plan, supportsManagedNodes bool) (bool, error) {
@@ -4,13 +4,13 @@ import (
 	"fmt"
 
 	gfn "github.com/awslabs/goformation/cloudformation"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/vpc"
 )
 
@@ -23,6 +23,11 @@ func (c *StackCollection) FixClusterCompatibility() error {
 		return err
 	}
 
+	if IsRegisteredClusterStack(stack) {
+		logger.Info("cluster stack %q was created by 'eksctl register cluster', it cannot be updated", *stack.StackName)
+		return nil
+	}
+
 	var (
 		clusterDefaultSG string
 		fargateRole      string
@@ -83,6 +88,11 @@ func (c *StackCollection) hasManagedToUnmanagedSG() (bool, error) {
 
 // EnsureMapPublicIPOnLaunchEnabled sets this subnet property to true when it is not set or is set to false
 func (c *StackCollection) EnsureMapPublicIPOnLaunchEnabled() error {
+	if c.spec.IsSharedVPC() {
+		logger.Warning("not enabling MapPublicIpOnLaunch on subnets %q, they're shared by account %s, which can only modify them", c.spec.PublicSubnetIDs(), c.spec.VPC.OwnerAccountID)
+		return nil
+	}
+
 	// First, make sure we enable the options in EC2. This is to make sure the settings are applied even
 	// if the stacks in Cloudformation have the setting enabled (since a stack update would produce "nothing to change"
 	// and therefore the setting would not be updated)
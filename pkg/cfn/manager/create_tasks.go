@@ -11,7 +11,7 @@ import (
 // NewTasksToCreateClusterWithNodeGroups defines all tasks required to create a cluster along
 // with some nodegroups; see CreateAllNodeGroups for how onlyNodeGroupSubset works
 func (c *StackCollection) NewTasksToCreateClusterWithNodeGroups(nodeGroups []*api.NodeGroup,
-	managedNodeGroups []*api.ManagedNodeGroup, supportsManagedNodes bool) *TaskTree {
+	managedNodeGroups []*api.ManagedNodeGroup, supportsManagedNodes bool, preNodeGroupTasks ...Task) *TaskTree {
 
 	tasks := &TaskTree{Parallel: false}
 
@@ -23,6 +23,11 @@ func (c *StackCollection) NewTasksToCreateClusterWithNodeGroups(nodeGroups []*ap
 		},
 	)
 
+	// tasks that must run once the control plane is created and before the nodes join the cluster
+	if len(preNodeGroupTasks) > 0 {
+		tasks.Append(&TaskTree{Parallel: false, IsSubTask: true, tasks: preNodeGroupTasks})
+	}
+
 	nodeGroupTasks := c.NewTasksToCreateNodeGroups(nodeGroups, supportsManagedNodes)
 
 	managedNodeGroupTasks := c.NewManagedNodeGroupTask(managedNodeGroups)
@@ -2,8 +2,11 @@ package manager
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
@@ -12,8 +15,14 @@ import (
 
 func deleteAll(_ string) bool { return true }
 
-// NewTasksToDeleteClusterWithNodeGroups defines tasks required to delete the given cluster along with all of its resources
-func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool, cleanup func(chan error, string) error) (*TaskTree, error) {
+// oidcProviderARNPattern matches the ARNs of IAM OIDC providers in the trust policies of iamserviceaccount roles
+var oidcProviderARNPattern = regexp.MustCompile(`arn:aws[a-z-]*:iam::\d{12}:oidc-provider/[^"]+`)
+
+// NewTasksToDeleteClusterWithNodeGroups defines tasks required to delete the given cluster along with all of its resources;
+// when preClusterCleanup is set, it's run after all other stacks were deleted and right before the cluster stack deletion;
+// when the OIDC provider can't be deleted with oidc, deleteOrphanedIAMServiceAccounts deletes the iamserviceaccount roles
+// and the OIDC providers they trust instead
+func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider, deleteOrphanedIAMServiceAccounts bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool, cleanup func(chan error, string) error, preClusterCleanup func() error) (*TaskTree, error) {
 	tasks := &TaskTree{Parallel: false}
 
 	nodeGroupTasks, err := c.NewTasksToDeleteNodeGroups(deleteAll, true, cleanup)
@@ -36,6 +45,16 @@ func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvid
 			serviceAccountAndOIDCTasks.IsSubTask = true
 			tasks.Append(serviceAccountAndOIDCTasks)
 		}
+	} else if deleteOrphanedIAMServiceAccounts {
+		orphanedTasks, err := c.NewTasksToDeleteOrphanedIAMServiceAccounts()
+		if err != nil {
+			return nil, err
+		}
+
+		if orphanedTasks.Len() > 0 {
+			orphanedTasks.IsSubTask = true
+			tasks.Append(orphanedTasks)
+		}
 	}
 
 	clusterStack, err := c.DescribeClusterStack()
@@ -43,7 +62,17 @@ func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvid
 		return nil, err
 	}
 
+	if preClusterCleanup != nil {
+		tasks.Append(&asyncTaskWithoutParams{
+			info: fmt.Sprintf("cleanup resources blocking deletion of cluster %q", c.spec.Metadata.Name),
+			call: preClusterCleanup,
+		})
+	}
+
 	info := fmt.Sprintf("delete cluster control plane %q", c.spec.Metadata.Name)
+	if IsRegisteredClusterStack(clusterStack) {
+		info = fmt.Sprintf("delete registration of cluster %q", c.spec.Metadata.Name)
+	}
 	if wait {
 		tasks.Append(&taskWithStackSpec{
 			info:  info,
@@ -131,6 +160,62 @@ func (c *StackCollection) NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oid
 	return tasks, nil
 }
 
+// NewTasksToDeleteOrphanedIAMServiceAccounts defines tasks required to delete the IAM roles of all of the
+// iamserviceaccounts along with the IAM OIDC providers they trust, when the cluster can't be operated to
+// delete the serviceaccounts or to obtain its OIDC issuer; the providers are found in the trust policies
+// of the roles, so that neither are left behind when deleting clusters that failed to be created
+func (c *StackCollection) NewTasksToDeleteOrphanedIAMServiceAccounts() (*TaskTree, error) {
+	serviceAccountStacks, err := c.DescribeIAMServiceAccountStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := &TaskTree{Parallel: false}
+	roleTasks := &TaskTree{
+		Parallel:  true,
+		IsSubTask: true,
+	}
+	providerARNs := map[string]struct{}{}
+
+	for _, s := range serviceAccountStacks {
+		template, err := c.GetStackTemplate(*s.StackName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting template of stack %q", *s.StackName)
+		}
+		for _, providerARN := range oidcProviderARNPattern.FindAllString(template, -1) {
+			providerARNs[providerARN] = struct{}{}
+		}
+
+		roleTasks.Append(&taskWithStackSpec{
+			info:  fmt.Sprintf("delete IAM role for serviceaccount %q", c.GetIAMServiceAccountName(s)),
+			stack: s,
+			call:  c.DeleteStackBySpecSync,
+		})
+	}
+
+	if roleTasks.Len() > 0 {
+		tasks.Append(roleTasks)
+	}
+
+	sortedARNs := []string{}
+	for providerARN := range providerARNs {
+		sortedARNs = append(sortedARNs, providerARN)
+	}
+	sort.Strings(sortedARNs)
+
+	for _, providerARN := range sortedARNs {
+		providerARN := providerARN
+		tasks.Append(&asyncTaskWithoutParams{
+			info: fmt.Sprintf("delete IAM OIDC provider %q", providerARN),
+			call: func() error {
+				return iamoidc.DeleteProviderByARN(c.provider.IAM(), providerARN)
+			},
+		})
+	}
+
+	return tasks, nil
+}
+
 // NewTasksToDeleteIAMServiceAccounts defines tasks required to delete all of the iamserviceaccounts
 func (c *StackCollection) NewTasksToDeleteIAMServiceAccounts(shouldDelete func(string) bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool) (*TaskTree, error) {
 	serviceAccountStacks, err := c.DescribeIAMServiceAccountStacks()
@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection delete tasks", func() {
+	var (
+		p  *mockprovider.MockProvider
+		sc *StackCollection
+	)
+
+	const (
+		clusterName = "test-cluster"
+		providerARN = "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/A39A2842863C47208955D753DE205E6E"
+	)
+
+	serviceAccountStackName := func(name string) string {
+		return fmt.Sprintf("eksctl-%s-addon-iamserviceaccount-kube-system-%s", clusterName, name)
+	}
+
+	mockServiceAccountStacks := func(names ...string) {
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, name := range names {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{
+					StackName: aws.String(serviceAccountStackName(name)),
+				})
+			}
+			consume(out, true)
+		}).Return(nil)
+
+		for _, name := range names {
+			stackName := serviceAccountStackName(name)
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return input.StackName != nil && *input.StackName == stackName
+			})).Return(&cfn.DescribeStacksOutput{
+				Stacks: []*cfn.Stack{
+					{
+						StackName:   aws.String(stackName),
+						StackStatus: aws.String(cfn.StackStatusCreateComplete),
+						Tags: []*cfn.Tag{
+							{
+								Key:   aws.String(api.IAMServiceAccountNameTag),
+								Value: aws.String("kube-system/" + name),
+							},
+						},
+					},
+				},
+			}, nil)
+			p.MockCloudFormation().On("GetTemplate", mock.MatchedBy(func(input *cfn.GetTemplateInput) bool {
+				return input.StackName != nil && *input.StackName == stackName
+			})).Return(&cfn.GetTemplateOutput{
+				TemplateBody: aws.String(fmt.Sprintf(`{"Resources":{"Role1":{"Properties":{"AssumeRolePolicyDocument":{"Statement":[{"Principal":{"Federated":%q}}]}}}}}`, providerARN)),
+			}, nil)
+		}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = clusterName
+		sc = NewStackCollection(p, cfg)
+	})
+
+	Describe("NewTasksToDeleteOrphanedIAMServiceAccounts", func() {
+		It("deletes the roles of iamserviceaccounts and the OIDC provider their roles trust", func() {
+			mockServiceAccountStacks("s3-reader", "aws-node")
+
+			tasks, err := sc.NewTasksToDeleteOrphanedIAMServiceAccounts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tasks.Describe()).To(Equal(fmt.Sprintf(
+				`2 sequential tasks: { 2 parallel sub-tasks: { delete IAM role for serviceaccount "kube-system/s3-reader", delete IAM role for serviceaccount "kube-system/aws-node" }, delete IAM OIDC provider %q }`,
+				providerARN,
+			)))
+		})
+	})
+})
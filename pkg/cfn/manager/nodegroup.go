@@ -3,19 +3,21 @@ package manager
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 
+	"github.com/aws/aws-sdk-go/aws"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -34,6 +36,27 @@ type NodeGroupSummary struct {
 	ImageID             string
 	CreationTime        *time.Time
 	NodeInstanceRoleARN string
+	Type                api.NodeGroupType
+	StackStatus         string
+	// Status is the live state of the nodegroup, it's only set by nodegroup.GetStatus
+	Status *NodeGroupStatus `json:",omitempty"`
+}
+
+// NodeGroupStatus is the live state of a nodegroup, read from its Auto Scaling group and,
+// for managed nodegroups, from EKS, rather than from its stack
+type NodeGroupStatus struct {
+	// Status is the status of a managed nodegroup, or of the Auto Scaling group of a self-managed one
+	Status          string
+	DesiredCapacity int
+	MinSize         int
+	MaxSize         int
+	// Nodes is the number of instances in service
+	Nodes int
+	// ReleaseVersion is the AMI release version of a managed nodegroup, or the name of the AMI of a self-managed one
+	ReleaseVersion string
+	HealthIssues   []string
+	// Update is the status of the last update of a managed nodegroup, or the status of the stack of a self-managed one
+	Update string
 }
 
 // NodeGroupStack represents a nodegroup and its type
@@ -63,7 +86,7 @@ func (c *StackCollection) createNodeGroupTask(errs chan error, ng *api.NodeGroup
 	ng.Tags[api.OldNodeGroupNameTag] = ng.Name
 	ng.Tags[api.NodeGroupTypeTag] = string(api.NodeGroupTypeUnmanaged)
 
-	return c.CreateStack(name, stack, ng.Tags, nil, errs)
+	return c.CreateStack(name, stack, ng.Tags, ng.CloudFormationParameters, errs)
 }
 
 func (c *StackCollection) createManagedNodeGroupTask(errorCh chan error, ng *api.ManagedNodeGroup) error {
@@ -185,44 +208,72 @@ func (c *StackCollection) ScaleNodeGroup(ng *api.NodeGroup) error {
 
 	// TODO rewrite this using types
 	// Get the current values
-	currentCapacity := gjson.Get(template, desiredCapacityPath)
-	currentMaxSize := gjson.Get(template, maxSizePath)
-	currentMinSize := gjson.Get(template, minSizePath)
+	currentCapacity := getTemplateValue(stack, template, desiredCapacityPath)
+	currentMaxSize := getTemplateValue(stack, template, maxSizePath)
+	currentMinSize := getTemplateValue(stack, template, minSizePath)
+
+	// minSize and maxSize are only set explicitly when scaling from a config file
+	minSizeChanged := ng.MinSize != nil && int64(*ng.MinSize) != currentMinSize.Int()
+	maxSizeChanged := ng.MaxSize != nil && int64(*ng.MaxSize) != currentMaxSize.Int()
 
-	if ng.DesiredCapacity != nil && int64(*ng.DesiredCapacity) == currentCapacity.Int() {
+	if ng.DesiredCapacity != nil && int64(*ng.DesiredCapacity) == currentCapacity.Int() && !minSizeChanged && !maxSizeChanged {
 		logger.Info("desired capacity of nodegroup %q in cluster %q is already %d", ng.Name, clusterName, *ng.DesiredCapacity)
 		return nil
 	}
 
+	// values that are template parameters are updated with the parameters of the stack
+	parameters := map[string]string{}
+	setValue := func(path string, value int) error {
+		if ref := gjson.Get(template, path+".Ref"); ref.Exists() {
+			parameters[ref.String()] = fmt.Sprintf("%d", value)
+			return nil
+		}
+		var err error
+		template, err = sjson.Set(template, path, fmt.Sprintf("%d", value))
+		return err
+	}
+
+	desiredCapacity := int(currentCapacity.Int())
+	if ng.DesiredCapacity != nil {
+		desiredCapacity = *ng.DesiredCapacity
+	}
+	minSize, maxSize := int(currentMinSize.Int()), int(currentMaxSize.Int())
+	if ng.MinSize != nil {
+		minSize = *ng.MinSize
+	}
+	if ng.MaxSize != nil {
+		maxSize = *ng.MaxSize
+	}
+
 	// Set the new values
-	newCapacity := fmt.Sprintf("%d", *ng.DesiredCapacity)
-	template, err = sjson.Set(template, desiredCapacityPath, newCapacity)
-	if err != nil {
+	if err := setValue(desiredCapacityPath, desiredCapacity); err != nil {
 		return errors.Wrap(err, "setting desired capacity")
 	}
-	descriptionBuffer.WriteString(fmt.Sprintf("desired capacity from %s to %d", currentCapacity.Raw, *ng.DesiredCapacity))
+	descriptionBuffer.WriteString(fmt.Sprintf("desired capacity from %s to %d", currentCapacity.String(), desiredCapacity))
 
 	// If the desired number of nodes is less than the min then update the min
-	if int64(*ng.DesiredCapacity) < currentMinSize.Int() {
-		newMinSize := fmt.Sprintf("%d", *ng.DesiredCapacity)
-		template, err = sjson.Set(template, minSizePath, newMinSize)
-		if err != nil {
+	if desiredCapacity < minSize {
+		minSize = desiredCapacity
+	}
+	if int64(minSize) != currentMinSize.Int() {
+		if err := setValue(minSizePath, minSize); err != nil {
 			return errors.Wrap(err, "setting min size")
 		}
-		descriptionBuffer.WriteString(fmt.Sprintf(", min size from %s to %d", currentMinSize.Raw, *ng.DesiredCapacity))
+		descriptionBuffer.WriteString(fmt.Sprintf(", min size from %s to %d", currentMinSize.String(), minSize))
 	}
 	// If the desired number of nodes is greater than the max then update the max
-	if int64(*ng.DesiredCapacity) > currentMaxSize.Int() {
-		newMaxSize := fmt.Sprintf("%d", *ng.DesiredCapacity)
-		template, err = sjson.Set(template, maxSizePath, newMaxSize)
-		if err != nil {
+	if desiredCapacity > maxSize {
+		maxSize = desiredCapacity
+	}
+	if int64(maxSize) != currentMaxSize.Int() {
+		if err := setValue(maxSizePath, maxSize); err != nil {
 			return errors.Wrap(err, "setting max size")
 		}
-		descriptionBuffer.WriteString(fmt.Sprintf(", max size from %s to %d", currentMaxSize.Raw, *ng.DesiredCapacity))
+		descriptionBuffer.WriteString(fmt.Sprintf(", max size from %s to %d", currentMaxSize.String(), maxSize))
 	}
 	logger.Debug("stack template (post-scale change): %s", template)
 
-	return c.UpdateStack(name, c.MakeChangeSetName("scale-nodegroup"), descriptionBuffer.String(), []byte(template), nil)
+	return c.UpdateStack(name, c.MakeChangeSetName("scale-nodegroup"), descriptionBuffer.String(), []byte(template), parameters)
 }
 
 // GetNodeGroupSummaries returns a list of summaries for the nodegroups of a cluster
@@ -337,6 +388,22 @@ func getNodeGroupPaths(tags []*cfn.Tag) (*nodeGroupPaths, error) {
 
 }
 
+// getTemplateValue returns the value at path in the template of a stack,
+// references to template parameters are resolved to their values
+func getTemplateValue(stack *Stack, template, path string) gjson.Result {
+	value := gjson.Get(template, path)
+	ref := value.Get("Ref")
+	if !ref.Exists() {
+		return value
+	}
+	for _, p := range stack.Parameters {
+		if aws.StringValue(p.ParameterKey) == ref.String() {
+			return gjson.Parse(strconv.Quote(aws.StringValue(p.ParameterValue)))
+		}
+	}
+	return gjson.Get(template, fmt.Sprintf("Parameters.%s.Default", ref.String()))
+}
+
 func (c *StackCollection) mapStackToNodeGroupSummary(stack *Stack, ngPaths *nodeGroupPaths) (*NodeGroupSummary, error) {
 	template, err := c.GetStackTemplate(*stack.StackName)
 	if err != nil {
@@ -345,10 +412,10 @@ func (c *StackCollection) mapStackToNodeGroupSummary(stack *Stack, ngPaths *node
 
 	cluster := getClusterNameTag(stack)
 	name := c.GetNodeGroupName(stack)
-	maxSize := gjson.Get(template, ngPaths.MaxSize)
-	minSize := gjson.Get(template, ngPaths.MinSize)
-	desired := gjson.Get(template, ngPaths.DesiredCapacity)
-	instanceType := gjson.Get(template, ngPaths.InstanceType)
+	maxSize := getTemplateValue(stack, template, ngPaths.MaxSize)
+	minSize := getTemplateValue(stack, template, ngPaths.MinSize)
+	desired := getTemplateValue(stack, template, ngPaths.DesiredCapacity)
+	instanceType := getTemplateValue(stack, template, ngPaths.InstanceType)
 	imageID := gjson.Get(template, imageIDPath)
 
 	nodeGroupType, err := GetNodeGroupType(stack.Tags)
@@ -382,6 +449,8 @@ func (c *StackCollection) mapStackToNodeGroupSummary(stack *Stack, ngPaths *node
 		ImageID:             imageID.String(),
 		CreationTime:        stack.CreationTime,
 		NodeInstanceRoleARN: nodeInstanceRoleARN,
+		Type:                nodeGroupType,
+		StackStatus:         aws.StringValue(stack.StackStatus),
 	}
 
 	return summary, nil
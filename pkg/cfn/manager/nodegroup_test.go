@@ -101,6 +101,44 @@ var _ = Describe("StackCollection NodeGroup", func() {
 		})
 	})
 
+	Describe("getTemplateValue", func() {
+		template := `{
+			"Parameters": {
+				"MinSize": {"Type": "Number", "Default": "1"},
+				"MaxSize": {"Type": "Number", "Default": "3"}
+			},
+			"Resources": {
+				"NodeGroup": {
+					"Properties": {
+						"DesiredCapacity": "2",
+						"MinSize": {"Ref": "MinSize"},
+						"MaxSize": {"Ref": "MaxSize"}
+					}
+				}
+			}
+		}`
+		stack := &Stack{
+			Parameters: []*cfn.Parameter{
+				{
+					ParameterKey:   aws.String("MaxSize"),
+					ParameterValue: aws.String("10"),
+				},
+			},
+		}
+
+		It("should return literal values", func() {
+			Expect(getTemplateValue(stack, template, "Resources.NodeGroup.Properties.DesiredCapacity").Int()).To(Equal(int64(2)))
+		})
+
+		It("should resolve references to the parameters of the stack", func() {
+			Expect(getTemplateValue(stack, template, "Resources.NodeGroup.Properties.MaxSize").Int()).To(Equal(int64(10)))
+		})
+
+		It("should fall back to the defaults of the parameters", func() {
+			Expect(getTemplateValue(stack, template, "Resources.NodeGroup.Properties.MinSize").Int()).To(Equal(int64(1)))
+		})
+	})
+
 	Describe("GetNodeGroupSummaries", func() {
 		Context("With a cluster name", func() {
 			var (
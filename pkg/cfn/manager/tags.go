@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// UpdateStackTags adds tags to a stack, or updates their values, without changing its template
+// or its parameters; CloudFormation propagates the tags of the stack to its resources. It returns
+// false when the stack already has the tags
+func (c *StackCollection) UpdateStackTags(s *Stack, tags map[string]string) (bool, error) {
+	input := c.updateStackTagsInput(s, tags)
+	if input == nil {
+		return false, nil
+	}
+
+	logger.Info("updating tags of stack %q", *s.StackName)
+	logger.Debug("updating stack, input = %#v", input)
+	if _, err := c.provider.CloudFormation().UpdateStack(input); err != nil {
+		return false, errors.Wrapf(err, "updating tags of stack %q", *s.StackName)
+	}
+	return true, c.doWaitUntilStackIsUpdated(s)
+}
+
+// updateStackTagsInput returns the input of the stack update setting the tags, or nil when the
+// stack already has them
+func (c *StackCollection) updateStackTagsInput(s *Stack, tags map[string]string) *cloudformation.UpdateStackInput {
+	var (
+		stackTags []*cloudformation.Tag
+		changed   bool
+	)
+	existing := map[string]bool{}
+	for _, t := range s.Tags {
+		key := aws.StringValue(t.Key)
+		existing[key] = true
+		if value, ok := tags[key]; ok && value != aws.StringValue(t.Value) {
+			stackTags = append(stackTags, newTag(key, value))
+			changed = true
+			continue
+		}
+		stackTags = append(stackTags, t)
+	}
+	for _, key := range sortedKeys(tags) {
+		if !existing[key] {
+			stackTags = append(stackTags, newTag(key, tags[key]))
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	input := &cloudformation.UpdateStackInput{
+		StackName:           s.StackName,
+		UsePreviousTemplate: aws.Bool(true),
+		Capabilities:        s.Capabilities,
+		Tags:                stackTags,
+	}
+	for _, p := range s.Parameters {
+		input.Parameters = append(input.Parameters, &cloudformation.Parameter{
+			ParameterKey:     p.ParameterKey,
+			UsePreviousValue: aws.Bool(true),
+		})
+	}
+	if cfnRole := c.provider.CloudFormationRoleARN(); cfnRole != "" {
+		input.SetRoleARN(cfnRole)
+	}
+	return input
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
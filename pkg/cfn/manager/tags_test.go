@@ -0,0 +1,56 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection UpdateStackTags", func() {
+	var (
+		sc    *StackCollection
+		stack *Stack
+	)
+
+	BeforeEach(func() {
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		sc = NewStackCollection(mockprovider.NewMockProvider(), cfg)
+
+		stack = &Stack{
+			StackName:    aws.String("eksctl-test-cluster"),
+			Capabilities: aws.StringSlice([]string{cfn.CapabilityCapabilityIam}),
+			Parameters: []*cfn.Parameter{
+				{ParameterKey: aws.String("DesiredCapacity"), ParameterValue: aws.String("2")},
+			},
+			Tags: []*cfn.Tag{
+				newTag(api.ClusterNameTag, "test"),
+				newTag("team", "platform"),
+			},
+		}
+	})
+
+	It("doesn't update stacks that already have the tags", func() {
+		Expect(sc.updateStackTagsInput(stack, map[string]string{"team": "platform"})).To(BeNil())
+	})
+
+	It("keeps the template, parameters and other tags of the stack", func() {
+		input := sc.updateStackTagsInput(stack, map[string]string{"team": "ml", "cost-center": "1234"})
+		Expect(input).NotTo(BeNil())
+		Expect(*input.UsePreviousTemplate).To(BeTrue())
+		Expect(input.Capabilities).To(Equal(stack.Capabilities))
+		Expect(input.Parameters).To(ConsistOf(&cfn.Parameter{
+			ParameterKey:     aws.String("DesiredCapacity"),
+			UsePreviousValue: aws.Bool(true),
+		}))
+		Expect(input.Tags).To(Equal([]*cfn.Tag{
+			newTag(api.ClusterNameTag, "test"),
+			newTag("team", "ml"),
+			newTag("cost-center", "1234"),
+		}))
+	})
+})
@@ -5,12 +5,12 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/kris-nova/logger"
 	"k8s.io/client-go/kubernetes"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
 	kubewrapper "github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Task is a common interface for the stack manager tasks
@@ -3,8 +3,18 @@ package manager
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 )
 
+// StackTemplate is the template of a stack that is yet to be created
+type StackTemplate struct {
+	StackName string
+	Body      []byte
+}
+
 // GetStackTemplate gets the Cloudformation template for a stack
 func (c *StackCollection) GetStackTemplate(stackName string) (string, error) {
 	input := &cloudformation.GetTemplateInput{
@@ -18,3 +28,42 @@ func (c *StackCollection) GetStackTemplate(stackName string) (string, error) {
 
 	return *output.TemplateBody, nil
 }
+
+// RenderTemplates builds the templates of the stacks that `eksctl create cluster` would create
+// for the cluster and the given nodegroups, without creating them; the cluster template comes
+// first, it includes the resources of the VPC when the VPC is created by eksctl
+func (c *StackCollection) RenderTemplates(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup, supportsManagedNodes bool) ([]StackTemplate, error) {
+	type resourceSet interface {
+		AddAllResources() error
+		RenderJSON() ([]byte, error)
+	}
+
+	clusterStackName := c.makeClusterStackName()
+	stacks := map[string]resourceSet{
+		clusterStackName: builder.NewClusterResourceSet(c.provider, c.spec, supportsManagedNodes, nil),
+	}
+	stackNames := []string{clusterStackName}
+	for _, ng := range nodeGroups {
+		name := c.makeNodeGroupStackName(ng.Name)
+		stacks[name] = builder.NewNodeGroupResourceSet(c.provider, c.spec, clusterStackName, ng, supportsManagedNodes)
+		stackNames = append(stackNames, name)
+	}
+	for _, ng := range managedNodeGroups {
+		name := c.makeNodeGroupStackName(ng.Name)
+		stacks[name] = builder.NewManagedNodeGroup(c.spec, ng, clusterStackName)
+		stackNames = append(stackNames, name)
+	}
+
+	var templates []StackTemplate
+	for _, name := range stackNames {
+		if err := stacks[name].AddAllResources(); err != nil {
+			return nil, errors.Wrapf(err, "building stack %q", name)
+		}
+		body, err := stacks[name].RenderJSON()
+		if err != nil {
+			return nil, errors.Wrapf(err, "rendering template of stack %q", name)
+		}
+		templates = append(templates, StackTemplate{StackName: name, Body: body})
+	}
+	return templates, nil
+}
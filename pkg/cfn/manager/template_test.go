@@ -96,4 +96,60 @@ var _ = Describe("StackCollection Template", func() {
 			})
 		})
 	})
+
+	Describe("RenderTemplates", func() {
+		BeforeEach(func() {
+			p = mockprovider.NewMockProvider()
+
+			cc = newClusterConfig("test-cluster")
+			api.SetClusterConfigDefaults(cc)
+			cc.VPC.ID = "vpc-0e265ad953062b94b"
+			cc.VPC.Subnets = &api.ClusterSubnets{
+				Public: map[string]api.Network{
+					"us-west-2a": {ID: "subnet-0f98135715dfcf55f"},
+					"us-west-2b": {ID: "subnet-0ade11bad78dced9e"},
+				},
+			}
+			cc.Status = &api.ClusterStatus{
+				Endpoint:                 "https://test.eks.amazonaws.com",
+				CertificateAuthorityData: []byte("CA"),
+			}
+
+			ng := cc.NodeGroups[0]
+			ng.Name = "ng-1"
+			ng.AMI = "ami-0123456789"
+			api.SetNodeGroupDefaults(ng, cc.Metadata)
+
+			managedNodeGroup := api.NewManagedNodeGroup()
+			managedNodeGroup.Name = "mng-1"
+			api.SetManagedNodeGroupDefaults(managedNodeGroup, cc.Metadata)
+			cc.ManagedNodeGroups = []*api.ManagedNodeGroup{managedNodeGroup}
+
+			sc = NewStackCollection(p, cc)
+		})
+
+		It("should render the cluster template first, followed by the nodegroup templates", func() {
+			templates, err := sc.RenderTemplates(cc.NodeGroups, cc.ManagedNodeGroups, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			for _, template := range templates {
+				names = append(names, template.StackName)
+				Expect(template.Body).NotTo(BeEmpty())
+			}
+			Expect(names).To(Equal([]string{
+				"eksctl-test-cluster-cluster",
+				"eksctl-test-cluster-nodegroup-ng-1",
+				"eksctl-test-cluster-nodegroup-mng-1",
+			}))
+			Expect(string(templates[0].Body)).To(ContainSubstring("vpc-0e265ad953062b94b"))
+			Expect(string(templates[1].Body)).To(ContainSubstring("ami-0123456789"))
+		})
+
+		It("should not call AWS", func() {
+			_, err := sc.RenderTemplates(cc.NodeGroups, nil, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.MockCloudFormation().Calls).To(BeEmpty())
+		})
+	})
 })
@@ -3,14 +3,15 @@ package manager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/request"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
 )
 
@@ -48,7 +49,21 @@ func (c *StackCollection) waitWithAcceptors(i *Stack, acceptors []request.Waiter
 		return nil
 	}
 
-	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.provider.WaitTimeout(), troubleshoot)
+	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.stackTimeout(*i.StackName), troubleshoot)
+}
+
+// stackTimeout returns the timeout of the operations on a stack, depending on the phase it belongs to
+func (c *StackCollection) stackTimeout(stackName string) time.Duration {
+	switch {
+	case stackName == c.makeClusterStackName():
+		return c.provider.Timeout(api.TimeoutPhaseControlPlane)
+	case strings.HasPrefix(stackName, c.makeNodeGroupStackName("")):
+		return c.provider.Timeout(api.TimeoutPhaseNodeGroup)
+	case strings.HasPrefix(stackName, fmt.Sprintf("eksctl-%s-addon-", c.spec.Metadata.Name)):
+		return c.provider.Timeout(api.TimeoutPhaseAddon)
+	default:
+		return c.provider.WaitTimeout()
+	}
 }
 
 type noChangeError struct {
@@ -86,7 +101,7 @@ func (c *StackCollection) waitWithAcceptorsChangeSet(i *Stack, changesetName str
 		return nil
 	}
 
-	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.provider.WaitTimeout(), troubleshoot)
+	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.stackTimeout(*i.StackName), troubleshoot)
 }
 
 func (c *StackCollection) troubleshootStackFailureCause(i *Stack, desiredStatus string) {
@@ -155,6 +170,7 @@ func (c *StackCollection) waitUntilStackIsCreated(i *Stack, stack builder.Resour
 	defer close(errs)
 
 	if err := c.DoWaitUntilStackIsCreated(i); err != nil {
+		c.handleStackCreationFailure(i)
 		errs <- err
 		return
 	}
@@ -167,9 +183,29 @@ func (c *StackCollection) waitUntilStackIsCreated(i *Stack, stack builder.Resour
 		errs <- errors.Wrapf(err, "getting stack %q outputs", *i.StackName)
 		return
 	}
+	logger.Emit(logger.EventStackCreated, map[string]string{"cluster": c.spec.Metadata.Name, "stack": *i.StackName}, "created stack %q", *i.StackName)
 	errs <- nil
 }
 
+// handleStackCreationFailure tells what happened to a stack that failed to be created, depending
+// on StackOnFailure; deleted stacks are waited for, so that their creation can be retried
+func (c *StackCollection) handleStackCreationFailure(i *Stack) {
+	s, err := c.DescribeStack(i)
+	if err != nil {
+		logger.Debug("describeErr=%v", err)
+		return
+	}
+	switch status := *s.StackStatus; {
+	case status == cfn.StackStatusCreateFailed && c.provider.StackOnFailure() == api.StackOnFailurePreserve:
+		logger.Warning("stack %q and its resources were preserved for debugging, the stack must be deleted before its creation is retried", *i.StackName)
+	case status == cfn.StackStatusDeleteInProgress && c.provider.StackOnFailure() == api.StackOnFailureDelete:
+		logger.Info("waiting for stack %q that failed to be created to get deleted", *i.StackName)
+		if err := c.doWaitUntilStackIsDeleted(i); err != nil {
+			logger.Warning("stack %q that failed to be created wasn't deleted: %v", *i.StackName, err)
+		}
+	}
+}
+
 func (c *StackCollection) doWaitUntilStackIsDeleted(i *Stack) error {
 	return c.waitWithAcceptors(i,
 		waiters.MakeAcceptors(
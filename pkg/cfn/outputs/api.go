@@ -17,6 +17,7 @@ const (
 	ClusterSecurityGroup        = "SecurityGroup"
 	ClusterSubnetsPrivate       = string("Subnets" + api.SubnetTopologyPrivate)
 	ClusterSubnetsPublic        = string("Subnets" + api.SubnetTopologyPublic)
+	ClusterSubnetsLocalZones    = "SubnetsLocalZones"
 
 	ClusterSubnetsPublicLegacy = "Subnets"
 
@@ -28,6 +29,10 @@ const (
 	ClusterServiceRoleARN           = "ServiceRoleARN"
 	ClusterFeatureNATMode           = "FeatureNATMode"
 	ClusterFeatureEndpointAccess    = "FeatureEndpointAccess"
+	ClusterBudgetAlarmsTopic        = "BudgetAlarmsTopicARN"
+	ClusterSharedVPCOwnerAccount    = "SharedVPCOwnerAccount"
+
+	ClusterNodeTerminationHandlerQueueURL = "NodeTerminationHandlerQueueURL"
 
 	// outputs from nodegroup stack
 	NodeGroupInstanceRoleARN    = "InstanceRoleARN"
@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-
-	"github.com/kris-nova/logger"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
 
 	"sigs.k8s.io/yaml"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -24,6 +28,12 @@ const (
 	defaultOwner             = "root:root"
 	defaultScriptPermissions = "0755"
 	defaultFilePermissions   = "0644"
+
+	// multiPartBoundary is fixed, so that the user data only changes when its parts do
+	multiPartBoundary = "//eksctl//"
+
+	cloudConfigContentType = "text/cloud-config"
+	scriptContentType      = "text/x-shellscript"
 )
 
 // CloudConfig stores informaiton of the cloud config
@@ -96,18 +106,68 @@ func (c *CloudConfig) RunScript(name, s string) {
 	c.AddCommand(p)
 }
 
+// gzipMagic are the first bytes of gzipped data
+var gzipMagic = []byte{0x1f, 0x8b}
+
 // Encode encodes the cloud config
 func (c *CloudConfig) Encode() (string, error) {
-	data, err := yaml.Marshal(c)
+	data, err := c.marshal()
 	if err != nil {
 		return "", err
 	}
 
-	data = append([]byte(fmt.Sprintln(header)), data...)
-
 	return encodeUserData(data)
 }
 
+// EncodeMultiPart encodes the cloud config in a MIME multi-part archive, after a part for each of the
+// shell scripts; cloud-init runs the scripts of the parts in order, before the commands of the cloud config
+func (c *CloudConfig) EncodeMultiPart(scripts []string) (string, error) {
+	data, err := c.marshal()
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(multiPartBoundary); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%q\r\n\r\n", multiPartBoundary)
+
+	for _, script := range scripts {
+		if err := writePart(writer, scriptContentType, []byte(script)); err != nil {
+			return "", err
+		}
+	}
+	if err := writePart(writer, cloudConfigContentType, data); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return encodeUserData(buf.Bytes())
+}
+
+func (c *CloudConfig) marshal() ([]byte, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(fmt.Sprintln(header)), data...), nil
+}
+
+func writePart(writer *multipart.Writer, contentType string, content []byte) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {contentType + `; charset="us-ascii"`},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(content)
+	return err
+}
+
 func encodeUserData(data []byte) (string, error) {
 	var (
 		buf bytes.Buffer
@@ -124,34 +184,100 @@ func encodeUserData(data []byte) (string, error) {
 	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
 }
 
-// DecodeCloudConfig decodes the cloud config
+// DecodeCloudConfig decodes the cloud config, leaving out the shell scripts of a multi-part archive
 func DecodeCloudConfig(s string) (*CloudConfig, error) {
+	c, _, err := DecodeMultiPart(s)
+	return c, err
+}
+
+// DecodeMultiPart decodes the cloud config and the shell scripts of user data encoded by EncodeMultiPart,
+// or of a plain cloud config, which has no scripts
+func DecodeMultiPart(s string) (*CloudConfig, []string, error) {
 	if s == "" {
-		return nil, fmt.Errorf("cannot decode empty string")
+		return nil, nil, fmt.Errorf("cannot decode empty string")
 	}
-	c := New()
 
-	data, err := base64.StdEncoding.DecodeString(s)
+	data, err := DecodeUserData(s)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	gr, err := gzip.NewReader(ioutil.NopCloser(bytes.NewBuffer(data)))
+	if !bytes.HasPrefix(data, []byte("MIME-Version:")) {
+		c, err := unmarshal(data)
+		return c, nil, err
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer safeClose(gr)
-	data, err = ioutil.ReadAll(gr)
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
 	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		c       *CloudConfig
+		scripts []string
+		reader  = multipart.NewReader(msg.Body, params["boundary"])
+	)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, nil, err
+		}
+		contentType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, nil, err
+		}
+		switch contentType {
+		case cloudConfigContentType:
+			if c, err = unmarshal(content); err != nil {
+				return nil, nil, err
+			}
+		case scriptContentType:
+			scripts = append(scripts, string(content))
+		}
+	}
+
+	if c == nil {
+		return nil, nil, fmt.Errorf("no cloud config in multi-part user data")
+	}
+	return c, scripts, nil
+}
+
+func unmarshal(data []byte) (*CloudConfig, error) {
+	c := New()
+	if err := yaml.Unmarshal(data, c); err != nil {
 		return nil, err
 	}
+	return c, nil
+}
 
-	err = yaml.Unmarshal(data, c)
+// DecodeUserData decodes base64-encoded user data, which is decompressed when it's gzipped
+// like the user data of cloud configs
+func DecodeUserData(s string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
 		return nil, err
 	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
 
-	return c, nil
+	gr, err := gzip.NewReader(ioutil.NopCloser(bytes.NewBuffer(data)))
+	if err != nil {
+		return nil, err
+	}
+	defer safeClose(gr)
+	return ioutil.ReadAll(gr)
 }
 
 func safeClose(c io.Closer) {
@@ -57,4 +57,21 @@ var _ = Describe("cloudconfig", func() {
 		Expect(file.Content).To(Equal(testScript1))
 		Expect(file.Permissions).To(Equal("0755"))
 	})
+
+	It("decodes the scripts and the cloud config of a multi-part archive", func() {
+		scripts := []string{"#!/bin/bash\necho one\n", "#!/bin/bash\necho two\necho three\n"}
+		result, err := input.EncodeMultiPart(scripts)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := DecodeUserData(result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(HavePrefix("MIME-Version: 1.0\r\n"))
+
+		output, outputScripts, err := DecodeMultiPart(result)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(outputScripts).To(Equal(scripts))
+		Expect(output.Packages).To(Equal(input.Packages))
+		Expect(output.Commands).To(HaveLen(2))
+		Expect(output.WriteFiles).To(HaveLen(1))
+	})
 })
@@ -0,0 +1,475 @@
+package apply
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/create"
+	"github.com/weaveworks/eksctl/pkg/ctl/delete"
+	"github.com/weaveworks/eksctl/pkg/ctl/update"
+	"github.com/weaveworks/eksctl/pkg/ctl/utils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// applyFlags are the flags of apply that aren't passed on to the commands it runs, as
+// they load the config file written by apply, with the variables and --set overrides applied
+var applyFlags = sets.NewString("config-file", "var-file", "set", "no-strict", "plan", "prune")
+
+// Command creates the `apply` command
+func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
+	cmd := &cmdutils.Cmd{
+		CobraCommand:   &cobra.Command{},
+		ProviderConfig: &api.ProviderConfig{},
+		ClusterConfig:  api.NewClusterConfig(),
+		Validate:       true,
+	}
+	cmd.FlagSetGroup = flagGrouping.New(cmd.CobraCommand)
+
+	var prune bool
+
+	cmd.SetDescription("apply", "Reconcile a cluster with a config file",
+		"Compares the cluster, nodegroups, Fargate profiles, iamserviceaccounts, IAM identity mappings and default addons "+
+			"declared in a config file with the ones that exist, then creates the missing resources and updates the ones that differ, "+
+			"using the same operations as the create, scale, update and delete commands; "+
+			"resources that aren't in the config file are only deleted with --prune")
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doApply(cmd, prune)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.BoolVar(&cmd.Plan, "plan", false, "print the changes without applying them")
+		fs.BoolVar(&prune, "prune", false, "delete the nodegroups, Fargate profiles, iamserviceaccounts and IAM identity mappings that aren't in the config file")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, cmd, api.TimeoutPhaseControlPlane, api.TimeoutPhaseNodeGroup, api.TimeoutPhaseAddon, api.TimeoutPhaseDrain)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
+
+	cmd.FlagSetGroup.AddTo(cmd.CobraCommand)
+	return cmd.CobraCommand
+}
+
+func doApply(cmd *cmdutils.Cmd, prune bool) error {
+	if err := cmdutils.NewApplyLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	// the commands applying the changes load the config as it was given, before its defaults are set
+	configFile, err := writeConfigFile(cmd.ClusterConfig)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(configFile)
+
+	if err := cmd.SetDefaultsAndValidate(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	live, err := getLiveState(ctl, cfg)
+	if err != nil {
+		return err
+	}
+
+	p, err := newPlan(cfg, live, prune)
+	if err != nil {
+		return err
+	}
+	p.write(os.Stdout)
+
+	if p.empty() {
+		logger.Success("cluster %q matches config file %q", meta.Name, cmd.ClusterConfigFile)
+		return nil
+	}
+	if cmd.Plan {
+		logger.Warning("no changes were applied, run again without --plan to apply the changes")
+		return nil
+	}
+
+	if err := applyPlan(cmd, ctl, p, configFile); err != nil {
+		return err
+	}
+	logger.Success("applied config file %q to cluster %q", cmd.ClusterConfigFile, meta.Name)
+	return nil
+}
+
+// writeConfigFile writes the config to a temporary file, so that the commands run by apply load the
+// same config, even if it was read from stdin or modified with --set
+func writeConfigFile(cfg *api.ClusterConfig) (string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	f, err := ioutil.TempFile("", "eksctl-apply-*.yaml")
+	if err != nil {
+		return "", errors.Wrap(err, "writing the config of the cluster")
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", errors.Wrap(err, "writing the config of the cluster")
+	}
+	return f.Name(), nil
+}
+
+// getLiveState reads the state of the resources of the cluster, which is empty when the cluster doesn't exist
+func getLiveState(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) (*liveState, error) {
+	live := &liveState{}
+
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		if awsErr, ok := errors.Cause(err).(awserr.Error); ok && awsErr.Code() == awseks.ErrCodeResourceNotFoundException {
+			return live, nil
+		}
+		return nil, err
+	}
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return nil, err
+	}
+	live.clusterVersion = ctl.ControlPlaneVersion()
+
+	stackManager := ctl.NewStackManager(cfg)
+
+	summaries, err := stackManager.GetNodeGroupSummaries("")
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the nodegroups")
+	}
+	live.nodeGroups = map[string]nodeGroupSize{}
+	for _, s := range summaries {
+		live.nodeGroups[s.Name] = nodeGroupSize{desiredCapacity: s.DesiredCapacity, minSize: s.MinSize, maxSize: s.MaxSize}
+	}
+
+	if live.iamServiceAccounts, err = stackManager.ListIAMServiceAccountStacks(); err != nil {
+		return nil, errors.Wrap(err, "getting the iamserviceaccounts")
+	}
+
+	supportsFargate, err := ctl.SupportsFargate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if supportsFargate {
+		profiles, err := fargate.NewClient(cfg.Metadata.Name, ctl.Provider.EKS()).ListProfiles()
+		if err != nil {
+			return nil, errors.Wrap(err, "getting the Fargate profiles")
+		}
+		for _, profile := range profiles {
+			live.fargateProfiles = append(live.fargateProfiles, *profile)
+		}
+	}
+
+	rawClient, err := ctl.NewRawClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	acm, err := authconfigmap.NewFromClientSet(rawClient.ClientSet())
+	if err != nil {
+		return nil, err
+	}
+	if live.iamIdentityMappings, err = liveIAMIdentityMappings(acm); err != nil {
+		return nil, err
+	}
+	live.managedIdentityARNs = acm.ManagedIdentityARNs()
+
+	kubernetesVersion, err := rawClient.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range addonNames(cfg) {
+		diff, err := defaultaddons.Diff(rawClient.ClientSet(), name, kubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+		live.addons = append(live.addons, addonVersion{name: name, installed: diff.InstalledVersion, defaultVersion: diff.DefaultVersion})
+	}
+
+	return live, nil
+}
+
+// liveIAMIdentityMappings returns the mappings of the aws-auth ConfigMap, except the ones of the node roles
+func liveIAMIdentityMappings(acm *authconfigmap.AuthConfigMap) ([]*api.IAMIdentityMapping, error) {
+	identities, err := acm.Identities()
+	if err != nil {
+		return nil, err
+	}
+	var mappings []*api.IAMIdentityMapping
+	for _, identity := range identities {
+		// the roles of nodes and Fargate pods are managed with their nodegroups and profiles
+		if strings.HasPrefix(identity.Username(), "system:node:") {
+			continue
+		}
+		mappings = append(mappings, &api.IAMIdentityMapping{
+			ARN:      identity.ARN(),
+			Username: identity.Username(),
+			Groups:   identity.Groups(),
+		})
+	}
+	return mappings, nil
+}
+
+// addonNames returns the default addons that apply keeps up to date, aws-node is left
+// alone when another CNI plugin replaces it, as it's deleted or kept off the nodes
+func addonNames(cfg *api.ClusterConfig) []string {
+	if cfg.HasAlternateCNI() {
+		return []string{defaultaddons.CoreDNS, defaultaddons.KubeProxy}
+	}
+	return []string{defaultaddons.AWSNode, defaultaddons.CoreDNS, defaultaddons.KubeProxy}
+}
+
+// applyPlan applies the changes in an order that respects the dependencies between resources:
+// the control plane is upgraded first, and resources are deleted once the others have been created
+func applyPlan(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, p *plan, configFile string) error {
+	cfg := cmd.ClusterConfig
+
+	if p.has(kindCluster, actionCreate) {
+		if err := runCommand(cmd.CobraCommand, create.Command, "cluster", "--config-file", configFile); err != nil {
+			return err
+		}
+		return applyIAMIdentityMappings(ctl, cfg, p)
+	}
+
+	if p.has(kindCluster, actionUpdate) {
+		steps, err := upgradeSteps(ctl.ControlPlaneVersion(), p.upgradeVersion)
+		if err != nil {
+			return err
+		}
+		// the control plane is upgraded one minor version at a time
+		for i := 0; i < steps; i++ {
+			if err := runCommand(cmd.CobraCommand, update.Command, "cluster", "--config-file", configFile, "--approve"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.has(kindFargateProfile, actionCreate) {
+		if err := createFargateProfiles(cmd.CobraCommand, cfg, p.names(kindFargateProfile, actionCreate)); err != nil {
+			return err
+		}
+	}
+
+	if p.has(kindNodeGroup, actionCreate) {
+		if err := runCommand(cmd.CobraCommand, create.Command, "nodegroup", "--config-file", configFile); err != nil {
+			return err
+		}
+	}
+	if p.has(kindNodeGroup, actionUpdate) {
+		if err := scaleNodeGroups(ctl, cfg, p.names(kindNodeGroup, actionUpdate)); err != nil {
+			return err
+		}
+	}
+
+	if p.has(kindIAMServiceAccount, actionCreate) {
+		if err := runCommand(cmd.CobraCommand, create.Command, "iamserviceaccount", "--config-file", configFile, "--approve"); err != nil {
+			return err
+		}
+	}
+
+	if err := applyIAMIdentityMappings(ctl, cfg, p); err != nil {
+		return err
+	}
+
+	for _, name := range p.names(kindAddon, actionUpdate) {
+		if err := runCommand(cmd.CobraCommand, utils.Command, "update-"+name, "--config-file", configFile, "--approve"); err != nil {
+			return err
+		}
+	}
+
+	if p.has(kindIAMServiceAccount, actionDelete) {
+		if err := runCommand(cmd.CobraCommand, delete.Command, "iamserviceaccount", "--config-file", configFile, "--only-missing", "--approve"); err != nil {
+			return err
+		}
+	}
+
+	if p.has(kindFargateProfile, actionDelete) {
+		client := fargate.NewClientWithWaitTimeout(cfg.Metadata.Name, ctl.Provider.EKS(), cmd.ProviderConfig.WaitTimeout)
+		for _, name := range p.names(kindFargateProfile, actionDelete) {
+			logger.Info("deleting Fargate profile %q", name)
+			if err := client.DeleteProfile(name, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.has(kindNodeGroup, actionDelete) {
+		if err := runCommand(cmd.CobraCommand, delete.Command, "nodegroup", "--config-file", configFile, "--only-missing", "--approve"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCommand runs an eksctl command, e.g. `create nodegroup`, so that resources are
+// changed the same way as with the command; the flags given to apply, such as the
+// credentials and the timeouts, are passed on, so that the command changes the
+// resources of the account apply compared the config file with
+func runCommand(applyCmd *cobra.Command, command func(*cmdutils.FlagGrouping) *cobra.Command, args ...string) error {
+	c := command(cmdutils.NewGrouping())
+	subCmd, _, err := c.Find(args)
+	if err != nil {
+		return err
+	}
+	c.SetArgs(append(args, forwardedFlags(applyCmd.Flags(), subCmd.Flags())...))
+	c.SilenceUsage = true
+	c.SilenceErrors = true
+	if err := c.Execute(); err != nil {
+		return errors.Wrapf(err, "running 'eksctl %s %s'", c.Name(), args[0])
+	}
+	return nil
+}
+
+// forwardedFlags returns the arguments setting the flags that were set on apply and that
+// the command also has
+func forwardedFlags(applyFlagSet, commandFlagSet *pflag.FlagSet) []string {
+	var args []string
+	applyFlagSet.Visit(func(flag *pflag.Flag) {
+		if applyFlags.Has(flag.Name) || commandFlagSet.Lookup(flag.Name) == nil {
+			return
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range slice.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", flag.Name, value))
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value))
+	})
+	return args
+}
+
+// createFargateProfiles runs `create fargateprofile` with a config file of the missing profiles,
+// as the command creates all the profiles of the config file
+func createFargateProfiles(applyCmd *cobra.Command, cfg *api.ClusterConfig, names []string) error {
+	profilesCfg := cfg.DeepCopy()
+	profilesCfg.FargateProfiles = nil
+	for _, name := range names {
+		for _, profile := range cfg.FargateProfiles {
+			if profile.Name == name {
+				profilesCfg.FargateProfiles = append(profilesCfg.FargateProfiles, profile)
+			}
+		}
+	}
+
+	configFile, err := writeConfigFile(profilesCfg)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(configFile)
+
+	return runCommand(applyCmd, create.Command, "fargateprofile", "--config-file", configFile)
+}
+
+// scaleNodeGroups scales the nodegroups of the given names to the sizes of the config file
+func scaleNodeGroups(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, names []string) error {
+	nodeGroups := map[string]*api.NodeGroup{}
+	for _, ng := range cfg.NodeGroups {
+		nodeGroups[ng.Name] = ng
+	}
+	// managed nodegroups are scaled through their stacks the same way
+	for _, mng := range cfg.ManagedNodeGroups {
+		nodeGroups[mng.Name] = &api.NodeGroup{
+			Name:            mng.Name,
+			DesiredCapacity: mng.DesiredCapacity,
+			MinSize:         mng.MinSize,
+			MaxSize:         mng.MaxSize,
+		}
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+	for _, name := range names {
+		if err := stackManager.ScaleNodeGroup(nodeGroups[name]); err != nil {
+			return fmt.Errorf("failed to scale nodegroup %q for cluster %q, error %v", name, cfg.Metadata.Name, err)
+		}
+	}
+	return nil
+}
+
+// applyIAMIdentityMappings adds, replaces and removes the IAM identity mappings of the plan in the aws-auth ConfigMap
+func applyIAMIdentityMappings(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, p *plan) error {
+	var (
+		created = p.names(kindIAMIdentityMapping, actionCreate)
+		updated = p.names(kindIAMIdentityMapping, actionUpdate)
+		deleted = p.names(kindIAMIdentityMapping, actionDelete)
+	)
+	if len(created)+len(updated)+len(deleted) == 0 {
+		return nil
+	}
+
+	// the cluster may just have been created
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+	acm, err := authconfigmap.NewFromClientSet(clientSet)
+	if err != nil {
+		return err
+	}
+	if err := reconcileIAMIdentityMappings(acm, cfg.IAMIdentityMappings, created, updated, deleted); err != nil {
+		return err
+	}
+	return acm.Save()
+}
+
+// reconcileIAMIdentityMappings replaces the mappings of the given ARNs with the ones of the config file, and
+// records the ARNs of the mappings apply added, which are the only ones --prune deletes
+func reconcileIAMIdentityMappings(acm *authconfigmap.AuthConfigMap, mappings []*api.IAMIdentityMapping, created, updated, deleted []string) error {
+	for _, arn := range append(updated, deleted...) {
+		if err := acm.RemoveIdentity(arn, true); err != nil {
+			return err
+		}
+	}
+	for _, arn := range append(created, updated...) {
+		for _, m := range mappings {
+			if m.ARN != arn {
+				continue
+			}
+			identity, err := iam.NewIdentity(m.ARN, m.Username, m.Groups)
+			if err != nil {
+				return err
+			}
+			if err := acm.AddIdentity(identity); err != nil {
+				return err
+			}
+		}
+	}
+
+	managed := sets.NewString(acm.ManagedIdentityARNs()...)
+	managed.Insert(created...)
+	managed.Insert(updated...)
+	managed.Delete(deleted...)
+	acm.SetManagedIdentityARNs(managed.List())
+	return nil
+}
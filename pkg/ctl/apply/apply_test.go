@@ -0,0 +1,112 @@
+package apply
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/create"
+)
+
+var _ = Describe("apply", func() {
+	Describe("passes its flags on to the commands it runs", func() {
+		It("forwards the flags that were set and that the command has", func() {
+			applyCmd := Command(cmdutils.NewGrouping())
+			Expect(applyCmd.ParseFlags([]string{
+				"--config-file", "cluster.yaml",
+				"--prune",
+				"--profile", "prod",
+				"--assume-role-arn", "arn:aws:iam::123456789012:role/a",
+				"--assume-role-arn", "arn:aws:iam::123456789012:role/b",
+				"--timeout", "1h",
+				"--nodegroup-timeout", "30m",
+				"--control-plane-timeout", "50m",
+				"--max-api-qps", "5",
+			})).To(Succeed())
+
+			createCmd := create.Command(cmdutils.NewGrouping())
+			nodeGroupCmd, _, err := createCmd.Find([]string{"nodegroup"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(forwardedFlags(applyCmd.Flags(), nodeGroupCmd.Flags())).To(ConsistOf(
+				"--profile=prod",
+				"--assume-role-arn=arn:aws:iam::123456789012:role/a",
+				"--assume-role-arn=arn:aws:iam::123456789012:role/b",
+				"--timeout=1h0m0s",
+				"--nodegroup-timeout=30m0s",
+				"--max-api-qps=5",
+			))
+		})
+	})
+
+	Describe("default addons", func() {
+		It("leaves aws-node alone when another CNI plugin replaces it", func() {
+			cfg := api.NewClusterConfig()
+			Expect(addonNames(cfg)).To(ContainElement(defaultaddons.AWSNode))
+
+			cfg.Network = &api.ClusterNetwork{CNI: api.CNICalico}
+			Expect(addonNames(cfg)).To(Equal([]string{defaultaddons.CoreDNS, defaultaddons.KubeProxy}))
+		})
+	})
+
+	Describe("IAM identity mappings", func() {
+		var acm *authconfigmap.AuthConfigMap
+
+		BeforeEach(func() {
+			acm = authconfigmap.New(nil, &corev1.ConfigMap{
+				ObjectMeta: authconfigmap.ObjectMeta(),
+				Data: map[string]string{
+					"mapRoles": `- rolearn: arn:aws:iam::123456789012:role/ng-1-NodeInstanceRole
+  username: system:node:{{EC2PrivateDNSName}}
+  groups:
+  - system:bootstrappers
+  - system:nodes
+- rolearn: arn:aws:iam::123456789012:role/ops
+  username: ops
+  groups:
+  - system:masters
+`,
+				},
+			})
+		})
+
+		It("reads the mappings, except the ones of the node roles", func() {
+			mappings, err := liveIAMIdentityMappings(acm)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mappings).To(Equal([]*api.IAMIdentityMapping{
+				{ARN: "arn:aws:iam::123456789012:role/ops", Username: "ops", Groups: []string{"system:masters"}},
+			}))
+		})
+
+		It("records the mappings it adds, and forgets the ones it deletes", func() {
+			mappings := []*api.IAMIdentityMapping{
+				{ARN: "arn:aws:iam::123456789012:role/admin", Groups: []string{"system:masters"}},
+				{ARN: "arn:aws:iam::123456789012:user/alice", Username: "alice", Groups: []string{"developers"}},
+			}
+			Expect(reconcileIAMIdentityMappings(acm, mappings, []string{mappings[0].ARN, mappings[1].ARN}, nil, nil)).To(Succeed())
+			Expect(acm.ManagedIdentityARNs()).To(Equal([]string{mappings[0].ARN, mappings[1].ARN}))
+
+			Expect(reconcileIAMIdentityMappings(acm, mappings[:1], nil, nil, []string{mappings[1].ARN})).To(Succeed())
+			Expect(acm.ManagedIdentityARNs()).To(Equal([]string{mappings[0].ARN}))
+
+			live, err := liveIAMIdentityMappings(acm)
+			Expect(err).NotTo(HaveOccurred())
+			var arns []string
+			for _, m := range live {
+				arns = append(arns, m.ARN)
+			}
+			Expect(arns).To(ConsistOf("arn:aws:iam::123456789012:role/ops", mappings[0].ARN))
+		})
+
+		It("keeps the mappings it didn't add unmanaged", func() {
+			Expect(acm.ManagedIdentityARNs()).To(BeEmpty())
+			Expect(reconcileIAMIdentityMappings(acm, nil, nil, nil, nil)).To(Succeed())
+			Expect(acm.ManagedIdentityARNs()).To(BeEmpty())
+		})
+	})
+})
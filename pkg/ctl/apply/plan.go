@@ -0,0 +1,351 @@
+package apply
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// action is what apply does to a resource to reconcile it with the config file
+type action string
+
+const (
+	actionCreate action = "create"
+	actionUpdate action = "update"
+	actionDelete action = "delete"
+)
+
+// Kinds of the resources reconciled by apply, named after the resources of the eksctl commands
+const (
+	kindCluster            = "cluster"
+	kindNodeGroup          = "nodegroup"
+	kindFargateProfile     = "fargateprofile"
+	kindIAMServiceAccount  = "iamserviceaccount"
+	kindIAMIdentityMapping = "iamidentitymapping"
+	kindAddon              = "addon"
+)
+
+// change is a difference between the config file and the live state of a resource
+type change struct {
+	kind   string
+	name   string
+	action action
+	// details describes what an update changes
+	details string
+	// skipped is set on the deletions that aren't applied, as --prune isn't set
+	skipped bool
+}
+
+// nodeGroupSize is the size of the Auto Scaling group of a nodegroup
+type nodeGroupSize struct {
+	desiredCapacity, minSize, maxSize int
+}
+
+// addonVersion is the installed version of a default addon and the default version for the cluster
+type addonVersion struct {
+	name, installed, defaultVersion string
+}
+
+// liveState is the state of the resources of a cluster, as found in AWS and in the cluster
+type liveState struct {
+	// clusterVersion is the Kubernetes version of the control plane, it's empty when the cluster doesn't exist
+	clusterVersion string
+	nodeGroups     map[string]nodeGroupSize
+	// iamServiceAccounts are the <namespace>/<name> of the iamserviceaccounts
+	iamServiceAccounts []string
+	fargateProfiles    []string
+	// iamIdentityMappings are the mappings of the aws-auth ConfigMap, except the ones of the node roles
+	iamIdentityMappings []*api.IAMIdentityMapping
+	// managedIdentityARNs are the ARNs of the mappings added by apply, the other mappings are never deleted
+	managedIdentityARNs []string
+	addons              []addonVersion
+}
+
+// plan lists the changes that reconcile the live state with the config file, in the order they're applied
+type plan struct {
+	changes []change
+	// upgradeVersion is the version the control plane is upgraded to, if any
+	upgradeVersion string
+}
+
+// newPlan compares the config file with the live state; resources that aren't in the config file
+// are only deleted if prune is set, the deletions are skipped otherwise
+func newPlan(cfg *api.ClusterConfig, live *liveState, prune bool) (*plan, error) {
+	p := &plan{}
+
+	if live.clusterVersion == "" {
+		// the cluster is created with all the resources of the config file,
+		// but the identity mappings, which are added once it's created
+		p.add(kindCluster, cfg.Metadata.Name, actionCreate, "")
+		for _, name := range declaredNodeGroups(cfg) {
+			p.add(kindNodeGroup, name, actionCreate, "")
+		}
+		for _, profile := range cfg.FargateProfiles {
+			p.add(kindFargateProfile, profile.Name, actionCreate, "")
+		}
+		for _, name := range declaredServiceAccounts(cfg) {
+			p.add(kindIAMServiceAccount, name, actionCreate, "")
+		}
+		for _, m := range cfg.IAMIdentityMappings {
+			p.add(kindIAMIdentityMapping, m.ARN, actionCreate, "")
+		}
+		return p, nil
+	}
+
+	if err := p.planClusterVersion(cfg, live.clusterVersion); err != nil {
+		return nil, err
+	}
+
+	p.planNodeGroups(cfg, live.nodeGroups, prune)
+
+	profiles := make([]string, 0, len(cfg.FargateProfiles))
+	for _, profile := range cfg.FargateProfiles {
+		profiles = append(profiles, profile.Name)
+	}
+	p.planCreateAndDelete(kindFargateProfile, profiles, live.fargateProfiles, prune)
+
+	p.planCreateAndDelete(kindIAMServiceAccount, declaredServiceAccounts(cfg), live.iamServiceAccounts, prune)
+
+	p.planIAMIdentityMappings(cfg.IAMIdentityMappings, live.iamIdentityMappings, live.managedIdentityARNs, prune)
+
+	for _, addon := range live.addons {
+		switch {
+		case p.upgradeVersion != "":
+			p.add(kindAddon, addon.name, actionUpdate, fmt.Sprintf("to the default version for Kubernetes %s", p.upgradeVersion))
+		case addon.installed != addon.defaultVersion:
+			p.add(kindAddon, addon.name, actionUpdate, fmt.Sprintf("version %s -> %s", addon.installed, addon.defaultVersion))
+		}
+	}
+
+	return p, nil
+}
+
+func (p *plan) add(kind, name string, a action, details string) {
+	p.changes = append(p.changes, change{kind: kind, name: name, action: a, details: details})
+}
+
+func (p *plan) addDeletion(kind, name string, prune bool) {
+	p.changes = append(p.changes, change{kind: kind, name: name, action: actionDelete, skipped: !prune})
+}
+
+// planClusterVersion plans the upgrade of the control plane to the version of the config file,
+// the version can't be downgraded
+func (p *plan) planClusterVersion(cfg *api.ClusterConfig, liveVersion string) error {
+	version := cfg.Metadata.Version
+	switch version {
+	case "default":
+		version = api.DefaultVersion
+	case "latest":
+		version = api.LatestVersion
+	}
+	if version == "" || version == "auto" || version == liveVersion {
+		return nil
+	}
+
+	steps, err := upgradeSteps(liveVersion, version)
+	if err != nil {
+		return err
+	}
+	if steps < 0 {
+		return fmt.Errorf("the control plane of cluster %q can't be downgraded from version %s to %s", cfg.Metadata.Name, liveVersion, version)
+	}
+	p.add(kindCluster, cfg.Metadata.Name, actionUpdate, fmt.Sprintf("version %s -> %s", liveVersion, version))
+	p.upgradeVersion = version
+	return nil
+}
+
+// upgradeSteps returns the number of minor versions between two versions supported by EKS,
+// it's negative when the version would be downgraded
+func upgradeSteps(from, to string) (int, error) {
+	indexOf := func(version string) (int, error) {
+		for i, v := range api.SupportedVersions() {
+			if v == version {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("Kubernetes version %s is not known to this version of eksctl, supported versions are: %s",
+			version, strings.Join(api.SupportedVersions(), ", "))
+	}
+
+	fromIndex, err := indexOf(from)
+	if err != nil {
+		return 0, err
+	}
+	toIndex, err := indexOf(to)
+	if err != nil {
+		return 0, err
+	}
+	return toIndex - fromIndex, nil
+}
+
+func declaredNodeGroups(cfg *api.ClusterConfig) []string {
+	var names []string
+	for _, ng := range cfg.NodeGroups {
+		names = append(names, ng.Name)
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		names = append(names, ng.Name)
+	}
+	return names
+}
+
+// declaredServiceAccounts returns the <namespace>/<name> of the iamserviceaccounts of the config file
+func declaredServiceAccounts(cfg *api.ClusterConfig) []string {
+	var names []string
+	for _, sa := range cfg.IAM.ServiceAccounts {
+		names = append(names, sa.NameString())
+	}
+	return names
+}
+
+// planNodeGroups creates the missing nodegroups and scales the existing ones, the other
+// settings of nodegroups can't be changed once they're created
+func (p *plan) planNodeGroups(cfg *api.ClusterConfig, live map[string]nodeGroupSize, prune bool) {
+	var liveNames []string
+	for name := range live {
+		liveNames = append(liveNames, name)
+	}
+
+	scale := func(name string, desiredCapacity, minSize, maxSize *int) {
+		size, ok := live[name]
+		if !ok {
+			return
+		}
+		var diffs []string
+		for _, s := range []struct {
+			field    string
+			declared *int
+			live     int
+		}{
+			{"desiredCapacity", desiredCapacity, size.desiredCapacity},
+			{"minSize", minSize, size.minSize},
+			{"maxSize", maxSize, size.maxSize},
+		} {
+			if s.declared != nil && *s.declared != s.live {
+				diffs = append(diffs, fmt.Sprintf("%s %d -> %d", s.field, s.live, *s.declared))
+			}
+		}
+		if len(diffs) > 0 {
+			p.add(kindNodeGroup, name, actionUpdate, strings.Join(diffs, ", "))
+		}
+	}
+
+	p.planCreateAndDelete(kindNodeGroup, declaredNodeGroups(cfg), liveNames, prune)
+	for _, ng := range cfg.NodeGroups {
+		scale(ng.Name, ng.DesiredCapacity, ng.MinSize, ng.MaxSize)
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		if ng.ScalingConfig != nil {
+			scale(ng.Name, ng.DesiredCapacity, ng.MinSize, ng.MaxSize)
+		}
+	}
+}
+
+// planCreateAndDelete creates the declared resources that don't exist and deletes the ones that aren't declared
+func (p *plan) planCreateAndDelete(kind string, declared, live []string, prune bool) {
+	liveNames := sets.NewString(live...)
+	for _, name := range declared {
+		if !liveNames.Has(name) {
+			p.add(kind, name, actionCreate, "")
+		}
+	}
+	for _, name := range liveNames.Difference(sets.NewString(declared...)).List() {
+		p.addDeletion(kind, name, prune)
+	}
+}
+
+// planIAMIdentityMappings compares the mappings by ARN, the mappings of an ARN are
+// replaced when their username or groups differ; only the mappings added by apply
+// are deleted, as the others may have been added by hand, e.g. for admins
+func (p *plan) planIAMIdentityMappings(declared, live []*api.IAMIdentityMapping, managedARNs []string, prune bool) {
+	liveByARN := map[string][]*api.IAMIdentityMapping{}
+	for _, m := range live {
+		liveByARN[m.ARN] = append(liveByARN[m.ARN], m)
+	}
+
+	declaredARNs := sets.NewString()
+	for _, m := range declared {
+		declaredARNs.Insert(m.ARN)
+		mappings, ok := liveByARN[m.ARN]
+		switch {
+		case !ok:
+			p.add(kindIAMIdentityMapping, m.ARN, actionCreate, "")
+		case len(mappings) != 1 || !sameIdentity(m, mappings[0]):
+			p.add(kindIAMIdentityMapping, m.ARN, actionUpdate, fmt.Sprintf("%s -> %s", describeMappings(mappings...), describeMappings(m)))
+		}
+	}
+
+	managed := sets.NewString(managedARNs...)
+	var undeclared []string
+	for arn := range liveByARN {
+		if !declaredARNs.Has(arn) && managed.Has(arn) {
+			undeclared = append(undeclared, arn)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, arn := range undeclared {
+		p.addDeletion(kindIAMIdentityMapping, arn, prune)
+	}
+}
+
+func sameIdentity(a, b *api.IAMIdentityMapping) bool {
+	return a.Username == b.Username && sets.NewString(a.Groups...).Equal(sets.NewString(b.Groups...))
+}
+
+func describeMappings(mappings ...*api.IAMIdentityMapping) string {
+	var descriptions []string
+	for _, m := range mappings {
+		descriptions = append(descriptions, fmt.Sprintf("username %q, groups %q", m.Username, m.Groups))
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// empty reports whether the live state matches the config file, skipped deletions aside
+func (p *plan) empty() bool {
+	for _, c := range p.changes {
+		if !c.skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// has reports whether the plan applies a change of the given kind and action
+func (p *plan) has(kind string, a action) bool {
+	return len(p.names(kind, a)) > 0
+}
+
+// names returns the names of the resources of the given kind the plan applies the action to
+func (p *plan) names(kind string, a action) []string {
+	var names []string
+	for _, c := range p.changes {
+		if c.kind == kind && c.action == a && !c.skipped {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// write prints the changes, prefixed with +, ~ and - like a diff
+func (p *plan) write(w io.Writer) {
+	prefixes := map[action]string{
+		actionCreate: "+",
+		actionUpdate: "~",
+		actionDelete: "-",
+	}
+	for _, c := range p.changes {
+		line := fmt.Sprintf("%s %s %s %q", prefixes[c.action], c.action, c.kind, c.name)
+		if c.details != "" {
+			line += fmt.Sprintf(" (%s)", c.details)
+		}
+		if c.skipped {
+			line += " [skipped, use --prune to delete]"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,148 @@
+package apply
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("apply plan", func() {
+	var (
+		cfg  *api.ClusterConfig
+		live *liveState
+	)
+
+	intPtr := func(i int) *int { return &i }
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Metadata.Version = api.Version1_14
+
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.DesiredCapacity = intPtr(3)
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng-1"
+		cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, mng)
+
+		cfg.FargateProfiles = []*api.FargateProfile{{Name: "fp-default"}}
+		cfg.IAM.ServiceAccounts = []*api.ClusterIAMServiceAccount{{}}
+		cfg.IAM.ServiceAccounts[0].Namespace = "kube-system"
+		cfg.IAM.ServiceAccounts[0].Name = "cluster-autoscaler"
+		cfg.IAMIdentityMappings = []*api.IAMIdentityMapping{
+			{ARN: "arn:aws:iam::123456789012:role/admin", Groups: []string{"system:masters"}},
+		}
+
+		live = &liveState{
+			clusterVersion: api.Version1_14,
+			nodeGroups: map[string]nodeGroupSize{
+				"ng-1":  {desiredCapacity: 3, minSize: 1, maxSize: 3},
+				"mng-1": {desiredCapacity: 2, minSize: 2, maxSize: 2},
+			},
+			iamServiceAccounts: []string{"kube-system/cluster-autoscaler"},
+			fargateProfiles:    []string{"fp-default"},
+			iamIdentityMappings: []*api.IAMIdentityMapping{
+				{ARN: "arn:aws:iam::123456789012:role/admin", Groups: []string{"system:masters"}},
+			},
+			addons: []addonVersion{{name: "kube-proxy", installed: "v1.14.6", defaultVersion: "v1.14.6"}},
+		}
+	})
+
+	newPlanOf := func(prune bool) *plan {
+		p, err := newPlan(cfg, live, prune)
+		Expect(err).NotTo(HaveOccurred())
+		return p
+	}
+
+	It("is empty when the cluster matches the config", func() {
+		p := newPlanOf(true)
+		Expect(p.changes).To(BeEmpty())
+		Expect(p.empty()).To(BeTrue())
+	})
+
+	It("creates the cluster with its resources when it doesn't exist", func() {
+		live = &liveState{}
+		p := newPlanOf(false)
+		Expect(p.changes).To(Equal([]change{
+			{kind: kindCluster, name: "cluster-1", action: actionCreate},
+			{kind: kindNodeGroup, name: "ng-1", action: actionCreate},
+			{kind: kindNodeGroup, name: "mng-1", action: actionCreate},
+			{kind: kindFargateProfile, name: "fp-default", action: actionCreate},
+			{kind: kindIAMServiceAccount, name: "kube-system/cluster-autoscaler", action: actionCreate},
+			{kind: kindIAMIdentityMapping, name: "arn:aws:iam::123456789012:role/admin", action: actionCreate},
+		}))
+	})
+
+	It("creates, updates and deletes resources", func() {
+		cfg.Metadata.Version = api.Version1_15
+		cfg.NodeGroups[0].DesiredCapacity = intPtr(4)
+		cfg.NodeGroups[0].MaxSize = intPtr(5)
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-2"
+		cfg.FargateProfiles = nil
+		cfg.IAMIdentityMappings[0].Username = "admin"
+		live.iamServiceAccounts = append(live.iamServiceAccounts, "default/s3-reader")
+		live.iamIdentityMappings = append(live.iamIdentityMappings, &api.IAMIdentityMapping{ARN: "arn:aws:iam::123456789012:user/alice", Username: "alice"})
+		live.managedIdentityARNs = []string{"arn:aws:iam::123456789012:user/alice"}
+
+		p := newPlanOf(true)
+		Expect(p.changes).To(Equal([]change{
+			{kind: kindCluster, name: "cluster-1", action: actionUpdate, details: "version 1.14 -> 1.15"},
+			{kind: kindNodeGroup, name: "ng-2", action: actionCreate},
+			{kind: kindNodeGroup, name: "ng-1", action: actionUpdate, details: "desiredCapacity 3 -> 4, maxSize 3 -> 5"},
+			{kind: kindFargateProfile, name: "fp-default", action: actionDelete},
+			{kind: kindIAMServiceAccount, name: "default/s3-reader", action: actionDelete},
+			{kind: kindIAMIdentityMapping, name: "arn:aws:iam::123456789012:role/admin", action: actionUpdate,
+				details: `username "", groups ["system:masters"] -> username "admin", groups ["system:masters"]`},
+			{kind: kindIAMIdentityMapping, name: "arn:aws:iam::123456789012:user/alice", action: actionDelete},
+			{kind: kindAddon, name: "kube-proxy", action: actionUpdate, details: "to the default version for Kubernetes 1.15"},
+		}))
+		Expect(p.upgradeVersion).To(Equal(api.Version1_15))
+		Expect(p.names(kindNodeGroup, actionCreate)).To(Equal([]string{"ng-2"}))
+	})
+
+	It("skips deletions unless pruning", func() {
+		live.nodeGroups["ng-old"] = nodeGroupSize{}
+		live.addons[0].installed = "v1.14.0"
+
+		p := newPlanOf(false)
+		Expect(p.changes).To(Equal([]change{
+			{kind: kindNodeGroup, name: "ng-old", action: actionDelete, skipped: true},
+			{kind: kindAddon, name: "kube-proxy", action: actionUpdate, details: "version v1.14.0 -> v1.14.6"},
+		}))
+		Expect(p.has(kindNodeGroup, actionDelete)).To(BeFalse())
+		Expect(p.empty()).To(BeFalse())
+
+		out := &bytes.Buffer{}
+		p.write(out)
+		Expect(out.String()).To(Equal(`- delete nodegroup "ng-old" [skipped, use --prune to delete]
+~ update addon "kube-proxy" (version v1.14.0 -> v1.14.6)
+`))
+	})
+
+	It("only deletes the IAM identity mappings added by apply", func() {
+		live.iamIdentityMappings = append(live.iamIdentityMappings,
+			&api.IAMIdentityMapping{ARN: "arn:aws:iam::123456789012:user/alice", Username: "alice"},
+			&api.IAMIdentityMapping{ARN: "arn:aws:iam::123456789012:role/ops", Groups: []string{"system:masters"}},
+		)
+		live.managedIdentityARNs = []string{"arn:aws:iam::123456789012:role/admin", "arn:aws:iam::123456789012:user/alice"}
+
+		p := newPlanOf(true)
+		Expect(p.changes).To(Equal([]change{
+			{kind: kindIAMIdentityMapping, name: "arn:aws:iam::123456789012:user/alice", action: actionDelete},
+		}))
+	})
+
+	It("doesn't downgrade the control plane", func() {
+		cfg.Metadata.Version = api.Version1_13
+		_, err := newPlan(cfg, live, false)
+		Expect(err).To(MatchError(`the control plane of cluster "cluster-1" can't be downgraded from version 1.14 to 1.13`))
+
+		cfg.Metadata.Version = "latest"
+		Expect(newPlanOf(false).upgradeVersion).To(Equal(api.LatestVersion))
+	})
+})
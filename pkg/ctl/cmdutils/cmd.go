@@ -1,11 +1,11 @@
 package cmdutils
 
 import (
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Cmd holds attributes that are common between commands;
@@ -18,7 +18,8 @@ type Cmd struct {
 
 	NameArg string
 
-	ClusterConfigFile string
+	ClusterConfigFile        string
+	ClusterConfigFileOptions eks.ConfigFileOptions
 
 	ProviderConfig *api.ProviderConfig
 	ClusterConfig  *api.ClusterConfig
@@ -30,11 +31,51 @@ type Cmd struct {
 // instance of eks.ClusterProvider, it may return an error if configuration
 // is invalid or region is not supported
 func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
+	c.setWaitTimeoutSet()
+
+	if err := c.SetDefaultsAndValidate(); err != nil {
+		return nil, err
+	}
+
+	if err := c.ProviderConfig.ValidateStackOnFailure(); err != nil {
+		return nil, err
+	}
+
+	if err := c.ProviderConfig.ValidateAPIRateLimit(); err != nil {
+		return nil, err
+	}
+
+	ctl := eks.New(c.ProviderConfig, c.ClusterConfig)
+
+	if !ctl.IsSupportedRegion() {
+		return nil, ErrUnsupportedRegion(c.ProviderConfig)
+	}
+
+	return ctl, nil
+}
+
+// setWaitTimeoutSet records whether --timeout was set explicitly, it then applies to all
+// the phases without their own timeout
+func (c *Cmd) setWaitTimeoutSet() {
+	if c.CobraCommand == nil {
+		return
+	}
+	for _, name := range []string{"timeout", "aws-api-timeout"} {
+		if flag := c.CobraCommand.Flag(name); flag != nil && flag.Changed {
+			c.ProviderConfig.WaitTimeoutSet = true
+		}
+	}
+}
+
+// SetDefaultsAndValidate sets the defaults of the cluster config and validates it, without
+// calling AWS; validation errors of the cluster and of nodegroups are only logged unless
+// c.Validate is set
+func (c *Cmd) SetDefaultsAndValidate() error {
 	api.SetClusterConfigDefaults(c.ClusterConfig)
 
 	if err := api.ValidateClusterConfig(c.ClusterConfig); err != nil {
 		if c.Validate {
-			return nil, err
+			return err
 		}
 		logger.Warning("ignoring validation error: %s", err.Error())
 	}
@@ -42,7 +83,7 @@ func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
 	for i, ng := range c.ClusterConfig.NodeGroups {
 		if err := api.ValidateNodeGroup(i, ng); err != nil {
 			if c.Validate {
-				return nil, err
+				return err
 			}
 			logger.Warning("ignoring validation error: %s", err.Error())
 		}
@@ -54,17 +95,10 @@ func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
 	for i, ng := range c.ClusterConfig.ManagedNodeGroups {
 		api.SetManagedNodeGroupDefaults(ng, c.ClusterConfig.Metadata)
 		if err := api.ValidateManagedNodeGroup(ng, i); err != nil {
-			return nil, err
+			return err
 		}
 	}
-
-	ctl := eks.New(c.ProviderConfig, c.ClusterConfig)
-
-	if !ctl.IsSupportedRegion() {
-		return nil, ErrUnsupportedRegion(c.ProviderConfig)
-	}
-
-	return ctl, nil
+	return nil
 }
 
 // AddResourceCmd create a registers a new command under the given verb command
@@ -80,6 +114,7 @@ func AddResourceCmd(flagGrouping *FlagGrouping, parentVerbCmd *cobra.Command, ne
 	c.FlagSetGroup = flagGrouping.New(c.CobraCommand)
 	newCmd(c)
 	c.FlagSetGroup.AddTo(c.CobraCommand)
+	registerResourceCompletions(c, parentVerbCmd.Name())
 	parentVerbCmd.AddCommand(c.CobraCommand)
 }
 
@@ -6,11 +6,11 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 	"github.com/weaveworks/eksctl/pkg/version"
@@ -107,6 +107,12 @@ func GetNameArg(args []string) string {
 func AddCommonFlagsForAWS(group *NamedFlagSetGroup, p *api.ProviderConfig, cfnRole bool) {
 	group.InFlagSet("AWS client", func(fs *pflag.FlagSet) {
 		fs.StringVarP(&p.Profile, "profile", "p", "", "AWS credentials profile to use (overrides the AWS_PROFILE environment variable)")
+		fs.StringSliceVar(&p.AssumeRoleARNs, "assume-role-arn", nil, "IAM role to assume for all AWS API calls, roles are chained when the flag is repeated, each role being assumed with the credentials of the previous one")
+		fs.StringVar(&p.AssumeRoleExternalID, "assume-role-external-id", "", "external ID to use when assuming the (last) role set with --assume-role-arn")
+		fs.StringVar(&p.AssumeRoleSessionName, "assume-role-session-name", "", "session name to use when assuming the roles set with --assume-role-arn (generated if unspecified)")
+
+		fs.Float64Var(&p.MaxAPIQPS, "max-api-qps", 0, "maximum number of EC2, CloudFormation and EKS API calls per second, lowered temporarily when calls are throttled (unlimited if unspecified)")
+		fs.IntVar(&p.APIBurst, "api-burst", 0, "number of API calls that can be made at once within --max-api-qps (defaults to --max-api-qps)")
 
 		fs.DurationVar(&p.WaitTimeout, "aws-api-timeout", api.DefaultWaitTimeout, "")
 		// TODO deprecate in 0.2.0
@@ -129,6 +135,40 @@ func AddTimeoutFlag(fs *pflag.FlagSet, p *time.Duration) {
 	AddTimeoutFlagWithValue(fs, p, api.DefaultWaitTimeout)
 }
 
+// phaseTimeoutFlags are the names and usages of the flags of the per-phase timeouts
+var phaseTimeoutFlags = map[api.TimeoutPhase][2]string{
+	api.TimeoutPhaseControlPlane: {"control-plane-timeout", "maximum waiting time for control plane operations"},
+	api.TimeoutPhaseNodeGroup:    {"nodegroup-timeout", "maximum waiting time for nodegroup operations"},
+	api.TimeoutPhaseAddon:        {"addon-timeout", "maximum waiting time for addon operations"},
+	api.TimeoutPhaseDrain:        {"drain-timeout", "maximum waiting time for draining nodes"},
+}
+
+// AddPhaseTimeoutFlags configures the flags of the timeouts of the given phases, which take
+// precedence over the timeouts of the config file and --timeout
+func AddPhaseTimeoutFlags(fs *pflag.FlagSet, cmd *Cmd, phases ...api.TimeoutPhase) {
+	timeouts := make(map[api.TimeoutPhase]*time.Duration, len(phases))
+	for _, phase := range phases {
+		flag := phaseTimeoutFlags[phase]
+		timeouts[phase] = fs.Duration(flag[0], 0, fmt.Sprintf("%s (default %s)", flag[1], api.DefaultPhaseTimeouts[phase]))
+	}
+	AddPreRun(cmd.CobraCommand, func(cobraCmd *cobra.Command, args []string) {
+		for phase, timeout := range timeouts {
+			if cobraCmd.Flag(phaseTimeoutFlags[phase][0]).Changed {
+				if cmd.ProviderConfig.PhaseTimeouts == nil {
+					cmd.ProviderConfig.PhaseTimeouts = map[api.TimeoutPhase]time.Duration{}
+				}
+				cmd.ProviderConfig.PhaseTimeouts[phase] = *timeout
+			}
+		}
+	})
+}
+
+// AddStackOnFailureFlag adds the --on-failure flag of the commands creating stacks
+func AddStackOnFailureFlag(fs *pflag.FlagSet, p *api.ProviderConfig) {
+	fs.StringVar(&p.StackOnFailure, "on-failure", api.StackOnFailureRollback, fmt.Sprintf("what to do with the stacks that fail to be created, "+
+		"valid options: %s (deletes their resources), %s (keeps them for debugging), %s", api.StackOnFailureRollback, api.StackOnFailurePreserve, api.StackOnFailureDelete))
+}
+
 // AddClusterFlag adds a common --cluster flag for cluster name.
 // Use this for commands whose principal resource is *not* a cluster.
 func AddClusterFlag(fs *pflag.FlagSet, meta *api.ClusterMeta) {
@@ -183,17 +223,26 @@ func AddUpdateAuthConfigMap(fs *pflag.FlagSet, updateAuthConfigMap *bool, descri
 }
 
 // AddCommonFlagsForKubeconfig adds common flags for controlling how output kubeconfig is written
-func AddCommonFlagsForKubeconfig(fs *pflag.FlagSet, outputPath, authenticatorRoleARN *string, setContext, autoPath *bool, exampleName string) {
+func AddCommonFlagsForKubeconfig(fs *pflag.FlagSet, outputPath, authenticator, authenticatorRoleARN, contextNameTemplate *string, setContext, autoPath *bool, exampleName string) {
 	fs.StringVar(outputPath, "kubeconfig", kubeconfig.DefaultPath, "path to write kubeconfig (incompatible with --auto-kubeconfig)")
+	fs.StringVar(authenticator, "authenticator", kubeconfig.AutoAuthenticator, fmt.Sprintf("command used by kubectl to get a token, one of %s, or %q to select the first one found on PATH",
+		strings.Join(kubeconfig.AuthenticatorCommands(), ", "), kubeconfig.AutoAuthenticator))
 	fs.StringVar(authenticatorRoleARN, "authenticator-role-arn", "", "AWS IAM role to assume for authenticator")
+	fs.StringVar(contextNameTemplate, "context-name-template", "", "name of the kubeconfig context, with the {cluster}, {region}, {account} and {user} placeholders, e.g. {cluster}.{region}.{account}; defaults to <user>@<cluster>.<region>.eksctl.io")
 	fs.BoolVar(setContext, "set-kubeconfig-context", true, "if true then current-context will be set in kubeconfig; if a context is already set then it will be overwritten")
 	fs.BoolVar(autoPath, "auto-kubeconfig", false, fmt.Sprintf("save kubeconfig file by cluster name, e.g. %q", kubeconfig.AutoPath(exampleName)))
 }
 
 // AddCommonFlagsForGetCmd adds common flafs for get commands
-func AddCommonFlagsForGetCmd(fs *pflag.FlagSet, chunkSize *int, outputMode *printers.Type) {
+func AddCommonFlagsForGetCmd(fs *pflag.FlagSet, chunkSize *int, outputMode *printers.Type, outputPath *string) {
 	fs.IntVar(chunkSize, "chunk-size", 100, "return large lists in chunks rather than all at once, pass 0 to disable")
-	fs.StringVarP(outputMode, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+	fs.StringVarP(outputMode, "output", "o", "table", "specifies the output format (valid option: table, wide, json, yaml, jsonpath=<template>, go-template=<template>)")
+	fs.StringVar(outputPath, "output-path", "", "write the output to a local file or an S3 object (s3://bucket/key) instead of stdout")
+}
+
+// AddColumnsFlag adds the flag selecting the columns of the table output of get commands
+func AddColumnsFlag(fs *pflag.FlagSet, columns *[]string) {
+	fs.StringSliceVar(columns, "columns", nil, "columns of the table output, e.g. NAME,STATUS,VERSION (all the columns of the wide output can be selected)")
 }
 
 // ErrUnsupportedRegion is a common error message
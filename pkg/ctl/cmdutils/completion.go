@@ -0,0 +1,225 @@
+package cmdutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	// completionTimeout is how long the shell completion of resource names waits for AWS
+	completionTimeout = 5 * time.Second
+	// completionCacheTTL is how long completed resource names are cached
+	completionCacheTTL = 2 * time.Minute
+)
+
+type completionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// registerResourceCompletions completes the names of clusters and nodegroups with the ones found in AWS,
+// for the --cluster and --nodegroup flags and, unless the resource is being created, for the name of the
+// cluster or nodegroup the command operates on
+func registerResourceCompletions(c *Cmd, verb string) {
+	cobraCmd := c.CobraCommand
+
+	completeClusters := func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return c.completeClusterNames(toComplete)
+	}
+	completeNodeGroups := func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return c.completeNodeGroupNames(cmd, toComplete)
+	}
+
+	flags := map[string]completionFunc{
+		"cluster":   completeClusters,
+		"nodegroup": completeNodeGroups,
+	}
+	var completeName completionFunc
+	if verb != "create" {
+		switch cobraCmd.Name() {
+		case "cluster":
+			completeName = completeClusters
+		case "nodegroup":
+			completeName = completeNodeGroups
+		}
+	}
+	if completeName != nil {
+		flags["name"] = completeName
+		cobraCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return completeName(cmd, args, toComplete)
+		}
+	}
+
+	for name, complete := range flags {
+		if cobraCmd.Flags().Lookup(name) == nil {
+			continue
+		}
+		if err := cobraCmd.RegisterFlagCompletionFunc(name, complete); err != nil {
+			logger.Debug("unable to register the completion of flag --%s: %v", name, err)
+		}
+	}
+}
+
+func (c *Cmd) completeClusterNames(toComplete string) ([]string, cobra.ShellCompDirective) {
+	// logs would be mixed up with the completions
+	logger.Level = 0
+
+	ctl := eks.New(c.ProviderConfig, nil)
+	key := fmt.Sprintf("%s/clusters", ctl.Provider.Region())
+	return completeNames(loadCompletionCache(completionCachePath()), key, ctl.ListClusterNames, toComplete)
+}
+
+func (c *Cmd) completeNodeGroupNames(cobraCmd *cobra.Command, toComplete string) ([]string, cobra.ShellCompDirective) {
+	logger.Level = 0
+
+	clusterName, err := cobraCmd.Flags().GetString("cluster")
+	if err != nil || clusterName == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg := api.NewClusterConfig()
+	cfg.Metadata.Name = clusterName
+
+	ctl := eks.New(c.ProviderConfig, cfg)
+	key := fmt.Sprintf("%s/%s/nodegroups", cfg.Metadata.Region, clusterName)
+	return completeNames(loadCompletionCache(completionCachePath()), key, func() ([]string, error) {
+		stacks, err := ctl.NewStackManager(cfg).ListNodeGroupStacks()
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, stack := range stacks {
+			names = append(names, stack.NodeGroupName)
+		}
+		return names, nil
+	}, toComplete)
+}
+
+// completeNames returns the names starting with toComplete, they are listed with list unless
+// they are found in the cache
+func completeNames(cache *completionCache, key string, list func() ([]string, error), toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, found := cache.lookup(key)
+	if !found {
+		var err error
+		if names, err = listWithTimeout(list, completionTimeout); err != nil {
+			return nil, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
+		}
+		cache.store(key, names)
+	}
+
+	var completions []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// listWithTimeout calls list, it gives up once timeout has passed, as completions
+// must not hang the shell when AWS can't be reached
+func listWithTimeout(list func() ([]string, error), timeout time.Duration) ([]string, error) {
+	type result struct {
+		names []string
+		err   error
+	}
+	results := make(chan result, 1)
+	go func() {
+		names, err := list()
+		results <- result{names, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.names, r.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// completionCache is a local cache of the resource names completed by the shell, stored as a
+// JSON file, so that completing the same resources again doesn't call AWS
+type completionCache struct {
+	Entries map[string]completionCacheEntry `json:"entries"`
+
+	path string
+}
+
+type completionCacheEntry struct {
+	Names     []string  `json:"names"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// completionCachePath returns the path of the completion cache, ~/.eksctl/cache/completion.json;
+// it's empty when the home directory is unknown
+func completionCachePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".eksctl", "cache", "completion.json")
+}
+
+// loadCompletionCache reads the cache at path, an empty cache is returned when it can't be read
+func loadCompletionCache(path string) *completionCache {
+	cache := &completionCache{
+		Entries: map[string]completionCacheEntry{},
+		path:    path,
+	}
+	if path == "" {
+		return cache
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, cache); err != nil || cache.Entries == nil {
+		cache.Entries = map[string]completionCacheEntry{}
+	}
+	return cache
+}
+
+func (c *completionCache) lookup(key string) ([]string, bool) {
+	entry, ok := c.Entries[key]
+	if !ok || time.Since(entry.FetchedAt) > completionCacheTTL {
+		return nil, false
+	}
+	return entry.Names, true
+}
+
+// store adds names to the cache and saves it, errors are ignored as the cache is only an optimisation
+func (c *completionCache) store(key string, names []string) {
+	sort.Strings(names)
+	c.Entries[key] = completionCacheEntry{
+		Names:     names,
+		FetchedAt: time.Now().UTC(),
+	}
+	for k, entry := range c.Entries {
+		if time.Since(entry.FetchedAt) > completionCacheTTL {
+			delete(c.Entries, k)
+		}
+	}
+
+	if c.path == "" {
+		return
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(c.path, data, 0600)
+}
@@ -0,0 +1,79 @@
+package cmdutils
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+)
+
+var _ = Describe("Resource completion", func() {
+	var (
+		dir   string
+		calls int
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "completion")
+		Expect(err).NotTo(HaveOccurred())
+		calls = 0
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	listClusters := func() ([]string, error) {
+		calls++
+		return []string{"prod-2", "dev", "prod-1"}, nil
+	}
+
+	It("completes names and caches them", func() {
+		path := filepath.Join(dir, "cache", "completion.json")
+
+		names, directive := completeNames(loadCompletionCache(path), "us-west-2/clusters", listClusters, "prod")
+		Expect(names).To(Equal([]string{"prod-1", "prod-2"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+
+		names, _ = completeNames(loadCompletionCache(path), "us-west-2/clusters", listClusters, "")
+		Expect(names).To(Equal([]string{"dev", "prod-1", "prod-2"}))
+		Expect(calls).To(Equal(1))
+
+		completeNames(loadCompletionCache(path), "eu-west-1/clusters", listClusters, "")
+		Expect(calls).To(Equal(2))
+	})
+
+	It("lists names again once they have expired", func() {
+		cache := loadCompletionCache(filepath.Join(dir, "completion.json"))
+		cache.Entries["us-west-2/clusters"] = completionCacheEntry{
+			Names:     []string{"old"},
+			FetchedAt: time.Now().Add(-completionCacheTTL - time.Second),
+		}
+
+		names, _ := completeNames(cache, "us-west-2/clusters", listClusters, "")
+		Expect(names).To(Equal([]string{"dev", "prod-1", "prod-2"}))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("gives up when listing fails or takes too long", func() {
+		cache := loadCompletionCache("")
+
+		names, directive := completeNames(cache, "us-west-2/clusters", func() ([]string, error) {
+			return nil, errors.New("no credentials")
+		}, "")
+		Expect(names).To(BeEmpty())
+		Expect(directive & cobra.ShellCompDirectiveError).NotTo(BeZero())
+
+		_, err := listWithTimeout(func() ([]string, error) {
+			time.Sleep(time.Second)
+			return nil, nil
+		}, 10*time.Millisecond)
+		Expect(err).To(MatchError("timed out after 10ms"))
+	})
+})
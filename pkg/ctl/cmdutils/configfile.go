@@ -14,9 +14,15 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils/names"
 )
 
-// AddConfigFileFlag adds common --config-file flag
-func AddConfigFileFlag(fs *pflag.FlagSet, path *string) {
+// AddConfigFileFlag adds common --config-file flag, along with the --var-file and --set flags
+// that make a template of the config file and the --no-strict flag
+func AddConfigFileFlag(fs *pflag.FlagSet, path *string, options *eks.ConfigFileOptions) {
 	fs.StringVarP(path, "config-file", "f", "", "load configuration from a file (or stdin if set to '-')")
+	fs.StringArrayVar(&options.VarFiles, "var-file", nil, "file of NAME=value lines whose variables are substituted for ${NAME} in the config file, "+
+		"instead of the environment variables (can be repeated)")
+	fs.StringArrayVar(&options.Set, "set", nil, "override a field of the config file, e.g. --set metadata.region=eu-west-1 "+
+		"or --set nodeGroups[ng-1].desiredCapacity=3 (can be repeated)")
+	fs.BoolVar(&options.NoStrict, "no-strict", false, "ignore unknown fields of the config file instead of rejecting it, e.g. to use a config file written for a newer version of eksctl")
 }
 
 // ClusterConfigLoader is an interface that loaders should implement
@@ -41,6 +47,9 @@ var (
 		"namepace",
 	)
 	defaultFlagsIncompatibleWithoutConfigFile = sets.NewString(
+		"var-file",
+		"set",
+		"no-strict",
 		"only",
 		"include",
 		"exclude",
@@ -81,7 +90,7 @@ func (l *commonClusterConfigLoader) Load() error {
 	// The reference to ClusterConfig should only be reassigned if ClusterConfigFile is specified
 	// because other parts of the code store the pointer locally and access it directly instead of via
 	// the Cmd reference
-	if l.ClusterConfig, err = eks.LoadConfigFromFile(l.ClusterConfigFile); err != nil {
+	if l.ClusterConfig, err = eks.LoadConfigFromFileWithOptions(l.ClusterConfigFile, l.ClusterConfigFileOptions); err != nil {
 		return err
 	}
 	meta := l.ClusterConfig.Metadata
@@ -141,51 +150,117 @@ func NewMetadataLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// NewValidateLoader will load config for 'eksctl validate', which requires a config file
+func NewValidateLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithoutConfigFile = func() error {
+		return ErrMustBeSet("--config-file")
+	}
+
+	l.validateWithConfigFile = func() error {
+		return validateFargateProfiles(l)
+	}
+
+	return l
+}
+
+// NewApplyLoader will load config for 'eksctl apply', which requires a config file
+func NewApplyLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithoutConfigFile = func() error {
+		return ErrMustBeSet("--config-file")
+	}
+
+	l.validateWithConfigFile = func() error {
+		return validateFargateProfiles(l)
+	}
+
+	return l
+}
+
+// createClusterFlagsIncompatibleWithConfigFile are the flags of create cluster setting parts of the
+// config that are given by config files
+var createClusterFlagsIncompatibleWithConfigFile = sets.NewString(
+	"tags",
+	"zones",
+	"managed",
+	"fargate",
+	"nodes",
+	"nodes-min",
+	"nodes-max",
+	"node-type",
+	"node-volume-size",
+	"node-volume-type",
+	"max-pods-per-node",
+	"node-ami",
+	"node-ami-family",
+	"ssh-access",
+	"ssh-public-key",
+	"enable-ssm",
+	"node-private-networking",
+	"node-security-groups",
+	"node-labels",
+	"node-taints",
+	"node-zones",
+	"cfn-parameter",
+	"asg-access",
+	"external-dns-access",
+	"full-ecr-access",
+	"vpc-private-subnets",
+	"vpc-public-subnets",
+	"vpc-cidr",
+	"vpc-nat-mode",
+	"vpc-from-kops-cluster",
+	"enable-budget-alarms",
+)
+
+// ValidateInteractiveCreateCluster checks that the flags of create cluster don't set the parts of the
+// config that are asked for with --interactive
+func ValidateInteractiveCreateCluster(cmd *Cmd) error {
+	if cmd.ClusterConfigFile != "" {
+		return fmt.Errorf("--interactive and --config-file/-f %s", IncompatibleFlags)
+	}
+	if cmd.NameArg != "" {
+		return fmt.Errorf("--interactive and name argument %q %s", cmd.NameArg, IncompatibleFlags)
+	}
+	for _, f := range defaultFlagsIncompatibleWithConfigFile.Union(createClusterFlagsIncompatibleWithConfigFile).List() {
+		if flag := cmd.CobraCommand.Flag(f); flag != nil && flag.Changed {
+			return fmt.Errorf("--interactive and --%s %s", f, IncompatibleFlags)
+		}
+	}
+	return nil
+}
+
 // NewCreateClusterLoader will load config or use flags for 'eksctl create cluster'
 func NewCreateClusterLoader(cmd *Cmd, ngFilter *NodeGroupFilter, ng *api.NodeGroup, params *CreateClusterCmdParams) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
 
-	ngFilter.ExcludeAll = params.WithoutNodeGroup
+	ngFilter.ExcludeAll = params.WithoutNodeGroup || !params.Creates(ClusterPartNodeGroups)
 
-	l.flagsIncompatibleWithConfigFile.Insert(
-		"tags",
-		"zones",
-		"managed",
-		"fargate",
-		"nodes",
-		"nodes-min",
-		"nodes-max",
-		"node-type",
-		"node-volume-size",
-		"node-volume-type",
-		"max-pods-per-node",
-		"node-ami",
-		"node-ami-family",
-		"ssh-access",
-		"ssh-public-key",
-		"node-private-networking",
-		"node-security-groups",
-		"node-labels",
-		"node-zones",
-		"asg-access",
-		"external-dns-access",
-		"full-ecr-access",
-		"vpc-private-subnets",
-		"vpc-public-subnets",
-		"vpc-cidr",
-		"vpc-nat-mode",
-		"vpc-from-kops-cluster",
-	)
+	l.flagsIncompatibleWithConfigFile.Insert(createClusterFlagsIncompatibleWithConfigFile.List()...)
 
-	l.flagsIncompatibleWithoutConfigFile.Insert("install-vpc-controllers")
+	// --only selects parts of the cluster here, rather than nodegroups
+	l.flagsIncompatibleWithoutConfigFile = sets.NewString(defaultFlagsIncompatibleWithoutConfigFile.List()...).Delete("only")
+	l.flagsIncompatibleWithoutConfigFile.Insert("install-vpc-controllers", "resume")
 
 	l.validateWithConfigFile = func() error {
+		if err := params.validateOnly(); err != nil {
+			return err
+		}
+
 		if l.ClusterConfig.VPC == nil {
 			l.ClusterConfig.VPC = api.NewClusterVPC()
 		}
 
 		if l.ClusterConfig.VPC.NAT == nil {
 			l.ClusterConfig.VPC.NAT = api.DefaultClusterNAT()
+			if l.ClusterConfig.HasCustomEgress() {
+				// the private subnets route to the internet through the transit gateway or extra routes
+				disable := api.ClusterDisableNAT
+				l.ClusterConfig.VPC.NAT.Gateway = &disable
+			}
 		}
 
 		if !api.IsSetAndNonEmptyString(l.ClusterConfig.VPC.NAT.Gateway) {
@@ -206,6 +281,10 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *NodeGroupFilter, ng *api.NodeGro
 	}
 
 	l.validateWithoutConfigFile = func() error {
+		if err := params.validateOnly(); err != nil {
+			return err
+		}
+
 		meta := l.ClusterConfig.Metadata
 
 		// generate cluster name or use either flag or argument
@@ -236,6 +315,10 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *NodeGroupFilter, ng *api.NodeGro
 			}
 		}
 
+		if params.EnableBudgetAlarms {
+			l.ClusterConfig.BudgetAlarms = &api.ClusterBudgetAlarms{}
+		}
+
 		if params.Fargate {
 			l.ClusterConfig.SetDefaultFargateProfile()
 			// A Fargate-only cluster should NOT have any un-managed node group:
@@ -277,13 +360,17 @@ func NewCreateNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFi
 		"node-ami-family",
 		"ssh-access",
 		"ssh-public-key",
+		"enable-ssm",
 		"node-private-networking",
 		"node-security-groups",
 		"node-labels",
+		"node-taints",
 		"node-zones",
+		"cfn-parameter",
 		"asg-access",
 		"external-dns-access",
 		"full-ecr-access",
+		"copy-from",
 	)
 
 	l.validateWithConfigFile = func() error {
@@ -341,6 +428,7 @@ func makeManagedNodegroup(nodeGroup *api.NodeGroup) *api.ManagedNodeGroup {
 		SSH:               nodeGroup.SSH,
 		InstanceType:      nodeGroup.InstanceType,
 		Labels:            nodeGroup.Labels,
+		Taints:            nodeGroup.Taints,
 		Tags:              nodeGroup.Tags,
 		AMIFamily:         nodeGroup.AMIFamily,
 		VolumeSize:        nodeGroup.VolumeSize,
@@ -363,24 +451,27 @@ func normalizeNodeGroup(ng *api.NodeGroup, l *commonClusterConfigLoader) error {
 		ng.SSH.PublicKeyPath = nil
 	}
 
-	if *ng.VolumeType == api.NodeVolumeTypeIO1 {
-		return fmt.Errorf("%s volume type is not supported via flag --node-volume-type, please use a config file", api.NodeVolumeTypeIO1)
+	// the IOPS of provisioned IOPS volumes can only be set in a config file
+	if *ng.VolumeType == api.NodeVolumeTypeIO1 || *ng.VolumeType == api.NodeVolumeTypeIO2 {
+		return fmt.Errorf("%s volume type is not supported via flag --node-volume-type, please use a config file", *ng.VolumeType)
 	}
 
 	return nil
 }
 
-// NewDeleteNodeGroupLoader will load config or use flags for 'eksctl delete nodegroup'
-func NewDeleteNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFilter) ClusterConfigLoader {
+// NewDeleteNodeGroupLoader will load config or use flags for 'eksctl delete nodegroup'; with onlyMissing,
+// or without a config file, --include and --exclude select the nodegroups of the cluster
+func NewDeleteNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFilter, onlyMissing bool) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
 
 	l.validateWithConfigFile = func() error {
+		if onlyMissing {
+			return ngFilter.AppendGlobsForExisting(l.Include, l.Exclude)
+		}
 		return ngFilter.AppendGlobs(l.Include, l.Exclude, getAllNodeGroupNames(l.ClusterConfig))
 	}
 
-	l.flagsIncompatibleWithoutConfigFile.Insert(
-		"approve",
-	)
+	l.flagsIncompatibleWithoutConfigFile = sets.NewString(defaultFlagsIncompatibleWithoutConfigFile.List()...).Delete("include", "exclude")
 
 	l.validateWithoutConfigFile = func() error {
 		if l.ClusterConfig.Metadata.Name == "" {
@@ -395,10 +486,21 @@ func NewDeleteNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFi
 			ng.Name = l.NameArg
 		}
 
+		if len(l.Include) > 0 || len(l.Exclude) > 0 {
+			if ng.Name != "" {
+				return fmt.Errorf("nodegroup name %q and --include/--exclude %s", ng.Name, IncompatibleFlags)
+			}
+			return ngFilter.AppendGlobsForExisting(l.Include, l.Exclude)
+		}
+
 		if ng.Name == "" {
 			return ErrMustBeSet("--name")
 		}
 
+		if flag := l.CobraCommand.Flag("approve"); flag != nil && flag.Changed {
+			return fmt.Errorf("cannot use --approve unless a config file or --include/--exclude is specified")
+		}
+
 		ngFilter.AppendIncludeNames(ng.Name)
 
 		l.Plan = false
@@ -409,6 +511,57 @@ func NewDeleteNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFi
 	return l
 }
 
+// NewScaleNodeGroupLoader will load config or use flags for 'eksctl scale nodegroup'
+func NewScaleNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFilter) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert(
+		"nodes",
+	)
+
+	l.validateWithConfigFile = func() error {
+		for _, ng := range l.ClusterConfig.NodeGroups {
+			if ng.DesiredCapacity == nil || *ng.DesiredCapacity < 0 {
+				return ErrMustBeSet(fmt.Sprintf("nodeGroups[%q].desiredCapacity", ng.Name))
+			}
+		}
+		for _, ng := range l.ClusterConfig.ManagedNodeGroups {
+			if ng.ScalingConfig == nil || ng.DesiredCapacity == nil || *ng.DesiredCapacity < 0 {
+				return ErrMustBeSet(fmt.Sprintf("managedNodeGroups[%q].desiredCapacity", ng.Name))
+			}
+		}
+		return ngFilter.AppendGlobs(l.Include, l.Exclude, getAllNodeGroupNames(l.ClusterConfig))
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if l.ClusterConfig.Metadata.Name == "" {
+			return ErrMustBeSet(ClusterNameFlag(cmd))
+		}
+
+		if ng.Name != "" && l.NameArg != "" {
+			return ErrFlagAndArg("--name", ng.Name, l.NameArg)
+		}
+
+		if l.NameArg != "" {
+			ng.Name = l.NameArg
+		}
+
+		if ng.Name == "" {
+			return ErrMustBeSet("--name")
+		}
+
+		if ng.DesiredCapacity == nil || *ng.DesiredCapacity < 0 {
+			return fmt.Errorf("number of nodes must be 0 or greater. Use the --nodes/-N flag")
+		}
+
+		ngFilter.AppendIncludeNames(ng.Name)
+
+		return nil
+	}
+
+	return l
+}
+
 // NewUtilsEnableLoggingLoader will load config or use flags for 'eksctl utils update-cluster-logging'
 func NewUtilsEnableLoggingLoader(cmd *Cmd) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
@@ -473,6 +626,30 @@ func NewUtilsAssociateIAMOIDCProviderLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// NewUtilsEnableSecretsEncryptionLoader loads config or uses flags for 'eksctl utils enable-secrets-encryption'
+func NewUtilsEnableSecretsEncryptionLoader(cmd *Cmd, keyARN string) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert("key-arn")
+
+	l.validateWithoutConfigFile = func() error {
+		if keyARN == "" {
+			return ErrMustBeSet("--key-arn")
+		}
+		l.ClusterConfig.SecretsEncryption = &api.SecretsEncryption{KeyARN: &keyARN}
+		return l.validateMetadataWithoutConfigFile()
+	}
+
+	l.validateWithConfigFile = func() error {
+		if l.ClusterConfig.SecretsEncryption == nil || l.ClusterConfig.SecretsEncryption.KeyARN == nil {
+			return ErrMustBeSet("secretsEncryption.keyARN")
+		}
+		return nil
+	}
+
+	return l
+}
+
 // NewUtilsPublicAccessCIDRsLoader loads config or uses flags for `eksctl utils set-public-access-cidrs <cidrs>`
 func NewUtilsPublicAccessCIDRsLoader(cmd *Cmd) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
@@ -502,6 +679,51 @@ func NewUtilsPublicAccessCIDRsLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// NewUtilsUpdateTagsLoader loads config or uses flags for 'eksctl utils update-tags', the tags
+// being set in metadata.tags or with --tags
+func NewUtilsUpdateTagsLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert("tags")
+
+	l.validateWithConfigFile = func() error {
+		if len(l.ClusterConfig.Metadata.Tags) == 0 {
+			return ErrMustBeSet("metadata.tags")
+		}
+		return nil
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if len(l.ClusterConfig.Metadata.Tags) == 0 {
+			return ErrMustBeSet("--tags")
+		}
+		return l.validateMetadataWithoutConfigFile()
+	}
+
+	return l
+}
+
+// NewUtilsWriteCFNTemplatesLoader loads the config file for 'eksctl utils write-cfn-templates', which
+// requires one; clusterName is the value of --cluster, which must match the name set in the config file
+func NewUtilsWriteCFNTemplatesLoader(cmd *Cmd, clusterName string) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile = sets.NewString(defaultFlagsIncompatibleWithConfigFile.List()...).Delete("cluster")
+
+	l.validateWithConfigFile = func() error {
+		if clusterName != "" && clusterName != l.ClusterConfig.Metadata.Name {
+			return fmt.Errorf("--cluster=%s doesn't match metadata.name %q in %q", clusterName, l.ClusterConfig.Metadata.Name, l.ClusterConfigFile)
+		}
+		return nil
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		return ErrMustBeSet("--config-file/-f")
+	}
+
+	return l
+}
+
 func parseCIDRs(arg string) ([]string, error) {
 	reader := strings.NewReader(arg)
 	csvReader := csv.NewReader(reader)
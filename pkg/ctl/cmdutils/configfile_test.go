@@ -81,7 +81,7 @@ var _ = Describe("cmdutils configfile", func() {
 			examples, err := filepath.Glob(examplesDir + "*.yaml")
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(examples).To(HaveLen(20))
+			Expect(examples).To(HaveLen(21))
 			for _, example := range examples {
 				cmd := &Cmd{
 					CobraCommand:      newCmd(),
@@ -261,6 +261,34 @@ var _ = Describe("cmdutils configfile", func() {
 
 		})
 
+		It("scale loader should filter nodegroups of a config file", func() {
+			cmd := &Cmd{
+				CobraCommand:      newCmd(),
+				ClusterConfigFile: filepath.Join(examplesDir, "03-two-nodegroups.yaml"),
+				ClusterConfig:     api.NewClusterConfig(),
+				ProviderConfig:    &api.ProviderConfig{},
+				Include:           []string{"*-private"},
+			}
+
+			ngFilter := NewNodeGroupFilter()
+			Expect(NewScaleNodeGroupLoader(cmd, nil, ngFilter).Load()).To(Succeed())
+
+			Expect(ngFilter.Match("ng1-public")).To(BeFalse())
+			Expect(ngFilter.Match("ng2-private")).To(BeTrue())
+		})
+
+		It("scale loader should require desiredCapacity for every nodegroup", func() {
+			cmd := &Cmd{
+				CobraCommand:      newCmd(),
+				ClusterConfigFile: filepath.Join(examplesDir, "14-windows-nodes.yaml"),
+				ClusterConfig:     api.NewClusterConfig(),
+				ProviderConfig:    &api.ProviderConfig{},
+			}
+
+			err := NewScaleNodeGroupLoader(cmd, nil, NewNodeGroupFilter()).Load()
+			Expect(err).To(MatchError(ContainSubstring("desiredCapacity")))
+		})
+
 		Describe("should set defaults for cluster endpoint access", func() {
 
 			testClusterEndpointAccessDefaults := func(configFilePath string, expectedPrivAccess, expectedPubAccess bool) {
@@ -1,15 +1,42 @@
 package cmdutils
 
 import (
+	"fmt"
+	"strings"
+
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 )
 
+// Parts of a cluster that can be created on their own with `eksctl create cluster --only`
+const (
+	// ClusterPartControlPlane is the cluster stack, along with the configuration of logging and endpoint access
+	ClusterPartControlPlane = "control-plane"
+	// ClusterPartVPC is created by the cluster stack as well, so it's always created with the control plane
+	ClusterPartVPC = "vpc"
+	// ClusterPartNodeGroups are the nodegroups, managed nodegroups and Fargate profiles
+	ClusterPartNodeGroups = "nodegroups"
+	// ClusterPartAddons are the add-ons installed in the cluster, e.g. the Windows VPC controller
+	ClusterPartAddons = "addons"
+	// ClusterPartIdentity is the IAM OIDC provider and the IAM service accounts
+	ClusterPartIdentity = "identity"
+)
+
+var clusterParts = []string{
+	ClusterPartControlPlane,
+	ClusterPartVPC,
+	ClusterPartNodeGroups,
+	ClusterPartAddons,
+	ClusterPartIdentity,
+}
+
 // CreateClusterCmdParams groups CLI options for the create cluster command.
 type CreateClusterCmdParams struct {
 	WriteKubeconfig             bool
 	KubeconfigPath              string
 	AutoKubeconfigPath          bool
+	Authenticator               string
 	AuthenticatorRoleARN        string
+	ContextNameTemplate         string
 	SetContext                  bool
 	AvailabilityZones           []string
 	InstallWindowsVPCController bool
@@ -18,4 +45,49 @@ type CreateClusterCmdParams struct {
 	WithoutNodeGroup            bool
 	Managed                     bool
 	Fargate                     bool
+	Only                        []string
+	Resume                      bool
+	EnableBudgetAlarms          bool
+	Interactive                 bool
+}
+
+// validateOnly checks the parts of the cluster given with --only, which selects the
+// parts to create itself rather than resuming the previous run
+func (p *CreateClusterCmdParams) validateOnly() error {
+	if p.Resume && len(p.Only) > 0 {
+		return fmt.Errorf("--resume and --only %s", IncompatibleFlags)
+	}
+	for _, part := range p.Only {
+		if !isClusterPart(part) {
+			return fmt.Errorf("invalid value %q for --only, valid options: %s", part, strings.Join(clusterParts, ", "))
+		}
+	}
+	return nil
+}
+
+// Creates reports whether the given part of the cluster is to be created, all parts are
+// created unless --only is set; selecting the VPC or the control plane selects both
+func (p *CreateClusterCmdParams) Creates(part string) bool {
+	if len(p.Only) == 0 {
+		return true
+	}
+	for _, only := range p.Only {
+		if only == part || (isClusterStackPart(only) && isClusterStackPart(part)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isClusterPart(part string) bool {
+	for _, p := range clusterParts {
+		if p == part {
+			return true
+		}
+	}
+	return false
+}
+
+func isClusterStackPart(part string) bool {
+	return part == ClusterPartControlPlane || part == ClusterPartVPC
 }
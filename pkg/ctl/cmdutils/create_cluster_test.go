@@ -0,0 +1,23 @@
+package cmdutils_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+var _ = Describe("CreateClusterCmdParams", func() {
+	DescribeTable("Creates",
+		func(only []string, part string, expected bool) {
+			params := &CreateClusterCmdParams{Only: only}
+			Expect(params.Creates(part)).To(Equal(expected))
+		},
+		Entry("all parts by default", nil, ClusterPartIdentity, true),
+		Entry("a selected part", []string{ClusterPartNodeGroups, ClusterPartAddons}, ClusterPartAddons, true),
+		Entry("a part that isn't selected", []string{ClusterPartNodeGroups}, ClusterPartAddons, false),
+		Entry("the control plane with the VPC", []string{ClusterPartVPC}, ClusterPartControlPlane, true),
+		Entry("the VPC with the control plane", []string{ClusterPartControlPlane}, ClusterPartVPC, true),
+	)
+})
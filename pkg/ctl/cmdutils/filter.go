@@ -5,10 +5,11 @@ import (
 	"strings"
 
 	"github.com/gobwas/glob"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Filter holds filter configuration
@@ -112,6 +113,19 @@ func (f *Filter) Match(name string) bool {
 	return true // biased to include
 }
 
+// matchStrict reports whether name matches the include rules, if any, and doesn't match the exclude rules;
+// unlike Match, it excludes the names that don't match the include rules when there are exclude rules, and
+// the exclude rules take precedence over the include rules
+func (f *Filter) matchStrict(name string) bool {
+	if f.ExcludeAll {
+		return false
+	}
+	if f.hasIncludeRules() && !f.includeNames.Has(name) && !f.matchGlobs(name, f.includeGlobs) {
+		return false
+	}
+	return !f.excludeNames.Has(name) && !f.matchGlobs(name, f.excludeGlobs)
+}
+
 // doMatchAll all names against the filter and return two sets of names - included and excluded
 func (f *Filter) doMatchAll(names []string) (sets.String, sets.String) {
 	included, excluded := sets.NewString(), sets.NewString()
@@ -133,6 +147,14 @@ func (f *Filter) doMatchAll(names []string) (sets.String, sets.String) {
 
 // doAppendIncludeGlobs sets globs for inclusion rules
 func (f *Filter) doAppendIncludeGlobs(names []string, resource string, globExprs ...string) error {
+	if err := f.appendIncludeGlobs(globExprs...); err != nil {
+		return err
+	}
+	return f.includeGlobsMatchAnything(names, resource)
+}
+
+// appendIncludeGlobs sets globs for inclusion rules without checking that they match anything
+func (f *Filter) appendIncludeGlobs(globExprs ...string) error {
 	for _, expr := range globExprs {
 		compiledExpr, err := glob.Compile(expr)
 		if err != nil {
@@ -141,7 +163,7 @@ func (f *Filter) doAppendIncludeGlobs(names []string, resource string, globExprs
 		f.includeGlobs = append(f.includeGlobs, compiledExpr)
 		f.rawIncludeGlobs = append(f.rawIncludeGlobs, expr)
 	}
-	return f.includeGlobsMatchAnything(names, resource)
+	return nil
 }
 
 func (f *Filter) doSetExcludeExistingFilter(names []string, resource string) error {
@@ -53,6 +53,14 @@ func AddCommonFlagsForFlux(fs *pflag.FlagSet, opts *flux.InstallOpts) {
 // AddCommonFlagsForGit configures the flags required to interact with a Git
 // repository.
 func AddCommonFlagsForGit(fs *pflag.FlagSet, opts *git.Options) {
+	AddGitFlags(fs, opts)
+	_ = cobra.MarkFlagRequired(fs, gitURL)
+	_ = cobra.MarkFlagRequired(fs, gitEmail)
+}
+
+// AddGitFlags configures the flags to interact with a Git repository, without
+// requiring them, for commands that can read the repository from the config file.
+func AddGitFlags(fs *pflag.FlagSet, opts *git.Options) {
 	fs.StringVar(&opts.URL, gitURL, "",
 		"SSH URL of the Git repository to be used for GitOps, e.g. git@github.com:<github_org>/<repo_name>")
 	fs.StringVar(&opts.Branch, gitBranch, "master",
@@ -63,8 +71,6 @@ func AddCommonFlagsForGit(fs *pflag.FlagSet, opts *git.Options) {
 		"Email to use as Git committer")
 	fs.StringVar(&opts.PrivateSSHKeyPath, gitPrivateSSHKeyPath, "",
 		"Optional path to the private SSH key to use with Git, e.g. ~/.ssh/id_rsa")
-	_ = cobra.MarkFlagRequired(fs, gitURL)
-	_ = cobra.MarkFlagRequired(fs, gitEmail)
 }
 
 // ValidateGitOptions validates the provided Git options.
@@ -109,7 +115,11 @@ func NewGitOpsConfigLoader(cmd *Cmd) ClusterConfigLoader {
 			"version",
 			"cluster",
 		),
-		flagsIncompatibleWithoutConfigFile: sets.NewString(),
+		flagsIncompatibleWithoutConfigFile: sets.NewString(
+			"var-file",
+			"set",
+			"no-strict",
+		),
 	}
 
 	l.validateWithoutConfigFile = func() error {
@@ -159,7 +169,7 @@ func (l *gitOpsConfigLoader) Load() error {
 	// The reference to ClusterConfig should only be reassigned if ClusterConfigFile is specified
 	// because other parts of the code store the pointer locally and access it directly instead of via
 	// the Cmd reference
-	if l.cmd.ClusterConfig, err = eks.LoadConfigFromFile(l.cmd.ClusterConfigFile); err != nil {
+	if l.cmd.ClusterConfig, err = eks.LoadConfigFromFileWithOptions(l.cmd.ClusterConfigFile, l.cmd.ClusterConfigFileOptions); err != nil {
 		return err
 	}
 	meta := l.cmd.ClusterConfig.Metadata
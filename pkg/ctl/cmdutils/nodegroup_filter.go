@@ -1,11 +1,11 @@
 package cmdutils
 
 import (
-	"github.com/kris-nova/logger"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // NodeGroupFilter holds filter configuration
@@ -37,6 +37,16 @@ func (f *NodeGroupFilter) AppendIncludeGlobs(ngNames []string, globExprs ...stri
 	return f.doAppendIncludeGlobs(ngNames, "nodegroup", globExprs...)
 }
 
+// AppendGlobsForExisting appends globs for inclusion and exclusion rules that select the nodegroups
+// of the cluster rather than the ones of the config file, the include globs are checked once the
+// nodegroup stacks are listed by SetIncludeExistingFilter or SetIncludeOrExcludeMissingFilter
+func (f *NodeGroupFilter) AppendGlobsForExisting(includeGlobExprs, excludeGlobExprs []string) error {
+	if err := f.appendIncludeGlobs(includeGlobExprs...); err != nil {
+		return err
+	}
+	return f.AppendExcludeGlobs(excludeGlobExprs...)
+}
+
 // A stackLister lists nodegroup stacks
 type stackLister interface {
 	ListNodeGroupStacks() ([]manager.NodeGroupStack, error)
@@ -62,6 +72,17 @@ func (f *NodeGroupFilter) SetExcludeExistingFilter(lister stackLister) error {
 	return f.doSetExcludeExistingFilter(ngNames, "nodegroup")
 }
 
+// SetIncludeExistingFilter uses stackLister to list existing nodegroup stacks and adds the nodegroups
+// that match the filter to clusterConfig, so that they can be selected without a config file
+func (f *NodeGroupFilter) SetIncludeExistingFilter(lister stackLister, clusterConfig *api.ClusterConfig) error {
+	stacks, err := lister.ListNodeGroupStacks()
+	if err != nil {
+		return err
+	}
+
+	return f.includeStacks(stacks, clusterConfig)
+}
+
 // SetIncludeOrExcludeMissingFilter uses stackLister to list existing nodegroup stacks and configures
 // the filter to either explicitly exclude or include nodegroups that are missing from given nodeGroups
 func (f *NodeGroupFilter) SetIncludeOrExcludeMissingFilter(lister stackLister, includeOnlyMissing bool, clusterConfig *api.ClusterConfig) error {
@@ -77,29 +98,50 @@ func (f *NodeGroupFilter) SetIncludeOrExcludeMissingFilter(lister stackLister, i
 		if !stackExists(stacks, localNodeGroup) {
 			logger.Info("nodegroup %q present in the given config, but missing in the cluster", localNodeGroup)
 			f.AppendExcludeNames(localNodeGroup)
-		} else if includeOnlyMissing {
-			f.AppendExcludeNames(localNodeGroup)
 		}
 	}
 
+	var missing []manager.NodeGroupStack
 	for _, s := range stacks {
-		remoteNodeGroupName := s.NodeGroupName
-		if !local.Has(remoteNodeGroupName) {
+		if !local.Has(s.NodeGroupName) {
 			logger.Info("nodegroup %q present in the cluster, but missing from the given config", s.NodeGroupName)
-			if includeOnlyMissing {
-				if s.Type == api.NodeGroupTypeManaged {
-					clusterConfig.ManagedNodeGroups = append(clusterConfig.ManagedNodeGroups, &api.ManagedNodeGroup{Name: s.NodeGroupName})
-				} else {
-					clusterConfig.NodeGroups = append(clusterConfig.NodeGroups, &api.NodeGroup{Name: s.NodeGroupName})
-				}
-				// make sure it passes it through the filter, so that one can use `--only-missing` along with `--exclude`
-				if f.Match(remoteNodeGroupName) {
-					f.AppendIncludeNames(remoteNodeGroupName)
-				}
-			}
+			missing = append(missing, s)
 		}
 	}
 
+	if includeOnlyMissing {
+		// the nodegroups of the config file are never selected, even when they match an include glob,
+		// so they are replaced with the missing ones that pass through the filter
+		clusterConfig.NodeGroups = nil
+		clusterConfig.ManagedNodeGroups = nil
+		return f.includeStacks(missing, clusterConfig)
+	}
+
+	return nil
+}
+
+// includeStacks adds the nodegroups of the stacks that match the filter strictly to clusterConfig,
+// so that e.g. `--include 'spot-*' --exclude 'critical'` only selects existing spot nodegroups;
+// the include globs must match at least one of the nodegroups
+func (f *NodeGroupFilter) includeStacks(stacks []manager.NodeGroupStack, clusterConfig *api.ClusterConfig) error {
+	var ngNames []string
+	for _, s := range stacks {
+		ngNames = append(ngNames, s.NodeGroupName)
+	}
+	if err := f.includeGlobsMatchAnything(ngNames, "nodegroup"); err != nil {
+		return err
+	}
+
+	for _, s := range stacks {
+		if !f.matchStrict(s.NodeGroupName) {
+			continue
+		}
+		if s.Type == api.NodeGroupTypeManaged {
+			clusterConfig.ManagedNodeGroups = append(clusterConfig.ManagedNodeGroups, &api.ManagedNodeGroup{Name: s.NodeGroupName})
+		} else {
+			clusterConfig.NodeGroups = append(clusterConfig.NodeGroups, &api.NodeGroup{Name: s.NodeGroupName})
+		}
+	}
 	return nil
 }
 
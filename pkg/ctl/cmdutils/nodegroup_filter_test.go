@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/printers"
 
 	. "github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
@@ -248,8 +249,64 @@ var _ = Describe("nodegroup filter", func() {
 			Expect(names).To(Equal([]string{"test-ng1a", "test-ng3a", "test-ng1b", "test-ng3b"}))
 		})
 	})
+
+	Context("existing nodegroups", func() {
+		var (
+			filter *NodeGroupFilter
+			cfg    *api.ClusterConfig
+			lister fakeStackLister
+		)
+
+		BeforeEach(func() {
+			cfg = newClusterConfig()
+			addGroupA(cfg)
+
+			filter = NewNodeGroupFilter()
+			lister = fakeStackLister{
+				{NodeGroupName: "test-ng1a", Type: api.NodeGroupTypeUnmanaged},
+				{NodeGroupName: "test-ng2a", Type: api.NodeGroupTypeUnmanaged},
+				{NodeGroupName: "spot-ng1", Type: api.NodeGroupTypeUnmanaged},
+				{NodeGroupName: "spot-ng2", Type: api.NodeGroupTypeManaged},
+				{NodeGroupName: "spot-critical", Type: api.NodeGroupTypeUnmanaged},
+				{NodeGroupName: "ondemand-ng1", Type: api.NodeGroupTypeManaged},
+			}
+		})
+
+		It("should include the missing nodegroups that match the globs", func() {
+			cfg.NodeGroups[2].Name = "spot-ng3"
+			Expect(filter.AppendGlobsForExisting([]string{"spot-*", "test-*"}, []string{"*-critical"})).To(Succeed())
+
+			Expect(filter.SetIncludeOrExcludeMissingFilter(lister, true, cfg)).To(Succeed())
+			Expect(getNodeGroupNames(cfg)).To(Equal([]string{"spot-ng1"}))
+			Expect(cfg.ManagedNodeGroups).To(HaveLen(1))
+			Expect(cfg.ManagedNodeGroups[0].Name).To(Equal("spot-ng2"))
+		})
+
+		It("should check the include globs against the missing nodegroups", func() {
+			Expect(filter.AppendGlobsForExisting([]string{"test-ng1?"}, nil)).To(Succeed())
+
+			err := filter.SetIncludeOrExcludeMissingFilter(lister, true, cfg)
+			Expect(err).To(MatchError(`no nodegroups match include glob filter specification: "test-ng1?"`))
+		})
+
+		It("should include the existing nodegroups that match the globs", func() {
+			cfg = newClusterConfig()
+			Expect(filter.AppendGlobsForExisting(nil, []string{"spot-*", "test-ng2?"})).To(Succeed())
+
+			Expect(filter.SetIncludeExistingFilter(lister, cfg)).To(Succeed())
+			Expect(getNodeGroupNames(cfg)).To(Equal([]string{"test-ng1a"}))
+			Expect(cfg.ManagedNodeGroups).To(HaveLen(1))
+			Expect(cfg.ManagedNodeGroups[0].Name).To(Equal("ondemand-ng1"))
+		})
+	})
 })
 
+type fakeStackLister []manager.NodeGroupStack
+
+func (l fakeStackLister) ListNodeGroupStacks() ([]manager.NodeGroupStack, error) {
+	return l, nil
+}
+
 func newClusterConfig() *api.ClusterConfig {
 	cfg := api.NewClusterConfig()
 
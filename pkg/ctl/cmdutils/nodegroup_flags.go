@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/pflag"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // AddCommonCreateNodeGroupFlags adds common flags for creating a nodegroup
@@ -33,10 +35,11 @@ func AddCommonCreateNodeGroupFlags(fs *pflag.FlagSet, cmd *Cmd, ng *api.NodeGrou
 	fs.IntVar(ng.VolumeSize, "node-volume-size", *ng.VolumeSize, "node volume size in GB")
 	fs.StringVar(ng.VolumeType, "node-volume-type", *ng.VolumeType, fmt.Sprintf("node volume type (valid options: %s)", strings.Join(api.SupportedNodeVolumeTypes(), ", ")))
 
-	fs.IntVar(&ng.MaxPodsPerNode, "max-pods-per-node", 0, "maximum number of pods per node (set automatically if unspecified)")
+	fs.Var(&ng.MaxPodsPerNode, "max-pods-per-node", "maximum number of pods per node, or 'auto' to compute it from the instance type (set automatically if unspecified)")
 
 	ng.SSH.Allow = fs.Bool("ssh-access", *ng.SSH.Allow, "control SSH access for nodes. Uses ~/.ssh/id_rsa.pub as default key path if enabled")
 	ng.SSH.PublicKeyPath = fs.String("ssh-public-key", "", "SSH public key to use for nodes (import from local path, or use existing EC2 key pair)")
+	ng.SSH.EnableSSM = fs.Bool("enable-ssm", false, "access nodes through SSM Session Manager instead of SSH keys")
 
 	fs.StringVar(&ng.AMI, "node-ami", "", "Advanced use cases only. If 'ssm' is supplied (default) then eksctl will use SSM Parameter; if 'auto' is supplied then eksctl will automatically set the AMI based on version/region/instance type; if static is supplied (deprecated), then static AMIs will be used; if any other value is supplied it will override the AMI to use for the nodes. Use with extreme care.")
 	fs.StringVar(&ng.AMIFamily, "node-ami-family", api.DefaultNodeImageFamily, "Advanced use cases only. If 'AmazonLinux2' is supplied (default), then eksctl will use the official AWS EKS AMIs (Amazon Linux 2); if 'Ubuntu1804' is supplied, then eksctl will use the official Canonical EKS AMIs (Ubuntu 18.04).")
@@ -46,7 +49,10 @@ func AddCommonCreateNodeGroupFlags(fs *pflag.FlagSet, cmd *Cmd, ng *api.NodeGrou
 	fs.StringSliceVar(&ng.SecurityGroups.AttachIDs, "node-security-groups", []string{}, "Attach additional security groups to nodes, so that it can be used to allow extra ingress/egress access from/to pods")
 
 	fs.StringToStringVar(&ng.Labels, "node-labels", nil, `Extra labels to add when registering the nodes in the nodegroup, e.g. "partition=backend,nodeclass=hugememory"`)
+	fs.StringToStringVar(&ng.Taints, "node-taints", nil, `Taints to add when registering the nodes in the nodegroup, in the value:Effect format, e.g. "dedicated=backend:NoSchedule"`)
 	fs.StringSliceVar(&ng.AvailabilityZones, "node-zones", nil, "(inherited from the cluster if unspecified)")
+
+	fs.StringToStringVar(&ng.CloudFormationParameters, "cfn-parameter", nil, `Expose the size and instance type of the nodegroup as CloudFormation template parameters, and override their values, e.g. "MinSize=2,MaxSize=10"`)
 }
 
 func incompatibleManagedNodesFlags() []string {
@@ -55,6 +61,99 @@ func incompatibleManagedNodesFlags() []string {
 		"max-pods-per-node",
 		"node-ami",
 		"node-security-groups",
+		"cfn-parameter",
+	}
+}
+
+// nodeGroupFlagOverrides set the field of a nodegroup that corresponds to a flag to its value in flags
+var nodeGroupFlagOverrides = map[string]func(ng, flags *api.NodeGroup){
+	"node-type":               func(ng, flags *api.NodeGroup) { ng.InstanceType = flags.InstanceType },
+	"nodes":                   func(ng, flags *api.NodeGroup) { ng.DesiredCapacity = flags.DesiredCapacity },
+	"nodes-min":               func(ng, flags *api.NodeGroup) { ng.MinSize = flags.MinSize },
+	"nodes-max":               func(ng, flags *api.NodeGroup) { ng.MaxSize = flags.MaxSize },
+	"node-volume-size":        func(ng, flags *api.NodeGroup) { ng.VolumeSize = flags.VolumeSize },
+	"node-volume-type":        func(ng, flags *api.NodeGroup) { ng.VolumeType = flags.VolumeType },
+	"max-pods-per-node":       func(ng, flags *api.NodeGroup) { ng.MaxPodsPerNode = flags.MaxPodsPerNode },
+	"ssh-access":              func(ng, flags *api.NodeGroup) { ng.SSH = flags.SSH },
+	"ssh-public-key":          func(ng, flags *api.NodeGroup) { ng.SSH = flags.SSH },
+	"enable-ssm":              func(ng, flags *api.NodeGroup) { ng.SSH = flags.SSH },
+	"node-ami":                func(ng, flags *api.NodeGroup) { ng.AMI = flags.AMI },
+	"node-ami-family":         func(ng, flags *api.NodeGroup) { ng.AMIFamily = flags.AMIFamily },
+	"node-private-networking": func(ng, flags *api.NodeGroup) { ng.PrivateNetworking, ng.Subnets = flags.PrivateNetworking, nil },
+	"node-security-groups":    func(ng, flags *api.NodeGroup) { ng.SecurityGroups.AttachIDs = flags.SecurityGroups.AttachIDs },
+	"node-labels":             func(ng, flags *api.NodeGroup) { ng.Labels = flags.Labels },
+	"node-taints":             func(ng, flags *api.NodeGroup) { ng.Taints = flags.Taints },
+	"node-zones":              func(ng, flags *api.NodeGroup) { ng.AvailabilityZones, ng.Subnets = flags.AvailabilityZones, nil },
+	"cfn-parameter":           func(ng, flags *api.NodeGroup) { ng.CloudFormationParameters = flags.CloudFormationParameters },
+}
+
+// managedNodeGroupFlagOverrides set the field of a managed nodegroup that corresponds to a flag to its value in flags
+var managedNodeGroupFlagOverrides = map[string]func(ng *api.ManagedNodeGroup, flags *api.NodeGroup){
+	"node-type":               func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.InstanceType = flags.InstanceType },
+	"nodes":                   func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.DesiredCapacity = flags.DesiredCapacity },
+	"nodes-min":               func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.MinSize = flags.MinSize },
+	"nodes-max":               func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.MaxSize = flags.MaxSize },
+	"node-volume-size":        func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.VolumeSize = flags.VolumeSize },
+	"ssh-access":              func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.SSH = flags.SSH },
+	"ssh-public-key":          func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.SSH = flags.SSH },
+	"enable-ssm":              func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.SSH = flags.SSH },
+	"node-ami-family":         func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.AMIFamily = flags.AMIFamily },
+	"node-private-networking": func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.PrivateNetworking = flags.PrivateNetworking },
+	"node-labels":             func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.Labels = flags.Labels },
+	"node-taints":             func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.Taints = flags.Taints },
+	"node-zones":              func(ng *api.ManagedNodeGroup, flags *api.NodeGroup) { ng.AvailabilityZones = flags.AvailabilityZones },
+}
+
+// iamAddonsFlagOverrides set the IAM add-on policy of a nodegroup that corresponds to a flag to its value in flags
+var iamAddonsFlagOverrides = map[string]func(policies, flags *api.NodeGroupIAMAddonPolicies){
+	"asg-access":          func(policies, flags *api.NodeGroupIAMAddonPolicies) { policies.AutoScaler = flags.AutoScaler },
+	"external-dns-access": func(policies, flags *api.NodeGroupIAMAddonPolicies) { policies.ExternalDNS = flags.ExternalDNS },
+	"full-ecr-access":     func(policies, flags *api.NodeGroupIAMAddonPolicies) { policies.ImageBuilder = flags.ImageBuilder },
+	"appmesh-access":      func(policies, flags *api.NodeGroupIAMAddonPolicies) { policies.AppMesh = flags.AppMesh },
+	"alb-ingress-access":  func(policies, flags *api.NodeGroupIAMAddonPolicies) { policies.ALBIngress = flags.ALBIngress },
+}
+
+// OverrideNodeGroupFields sets the fields of ng, a copy of an existing nodegroup,
+// that were given by flags to their value in flagsNodeGroup; the copied AMI belongs
+// to the copied AMI family, so it's resolved again when only the family is given
+func OverrideNodeGroupFields(cmd *Cmd, ng, flagsNodeGroup *api.NodeGroup) {
+	changed := func(name string) bool {
+		flag := cmd.CobraCommand.Flag(name)
+		return flag != nil && flag.Changed
+	}
+	for name, override := range nodeGroupFlagOverrides {
+		if changed(name) {
+			override(ng, flagsNodeGroup)
+		}
+	}
+	if changed("node-ami-family") && !changed("node-ami") {
+		ng.AMI = api.NodeImageResolverAuto
+	}
+	overrideIAMAddonPolicies(cmd, ng.IAM, flagsNodeGroup.IAM)
+}
+
+// OverrideManagedNodeGroupFields sets the fields of ng, a copy of an existing managed nodegroup,
+// that were given by flags to their value in flagsNodeGroup
+func OverrideManagedNodeGroupFields(cmd *Cmd, ng *api.ManagedNodeGroup, flagsNodeGroup *api.NodeGroup) error {
+	for _, name := range incompatibleManagedNodesFlags() {
+		if flag := cmd.CobraCommand.Flag(name); flag != nil && flag.Changed {
+			return ErrUnsupportedManagedFlag(fmt.Sprintf("--%s", name))
+		}
+	}
+	for name, override := range managedNodeGroupFlagOverrides {
+		if flag := cmd.CobraCommand.Flag(name); flag != nil && flag.Changed {
+			override(ng, flagsNodeGroup)
+		}
+	}
+	overrideIAMAddonPolicies(cmd, ng.IAM, flagsNodeGroup.IAM)
+	return nil
+}
+
+func overrideIAMAddonPolicies(cmd *Cmd, iam, flagsIAM *api.NodeGroupIAM) {
+	for name, override := range iamAddonsFlagOverrides {
+		if flag := cmd.CobraCommand.Flag(name); flag != nil && flag.Changed {
+			override(&iam.WithAddonPolicies, &flagsIAM.WithAddonPolicies)
+		}
 	}
 }
 
@@ -85,3 +184,23 @@ func AddNodeGroupFilterFlags(fs *pflag.FlagSet, includeGlobs, excludeGlobs *[]st
 	fs.StringSliceVar(excludeGlobs, "exclude", nil,
 		"nodegroups to exclude (list of globs), e.g.: 'ng-team-?,prod-*'")
 }
+
+// AddForceVersionSkewFlag adds a common `--force` flag to ignore the version skew between nodegroups and the control plane
+func AddForceVersionSkewFlag(fs *pflag.FlagSet, force *bool) {
+	fs.BoolVar(force, "force", false, fmt.Sprintf("Create or upgrade nodes whose Kubernetes version is newer than the control plane, "+
+		"or more than %d minor versions older, with a warning instead of an error", eks.MaxKubeletVersionSkew))
+}
+
+// CheckKubeletVersionSkew validates the version skew between the control plane and the nodes of a nodegroup,
+// when force is set the nodegroup is created or upgraded anyway and a warning is logged instead
+func CheckKubeletVersionSkew(controlPlaneVersion, kubeletVersion string, force bool) error {
+	err := eks.ValidateKubeletVersionSkew(controlPlaneVersion, kubeletVersion)
+	if err == nil {
+		return nil
+	}
+	if force {
+		logger.Warning("%v; continuing as --force is set", err)
+		return nil
+	}
+	return fmt.Errorf("%v; use --force to ignore the version skew policy", err)
+}
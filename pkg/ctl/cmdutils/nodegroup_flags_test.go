@@ -0,0 +1,85 @@
+package cmdutils_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+var _ = Describe("nodegroup flags", func() {
+	var (
+		cmd            *Cmd
+		flagsNodeGroup *api.NodeGroup
+	)
+
+	parseFlags := func(args ...string) {
+		cmd = &Cmd{
+			ClusterConfig: api.NewClusterConfig(),
+			CobraCommand: &cobra.Command{
+				Use: "test",
+				Run: func(_ *cobra.Command, _ []string) {},
+			},
+		}
+		flagsNodeGroup = api.NewNodeGroup()
+		AddCommonCreateNodeGroupFlags(cmd.CobraCommand.Flags(), cmd, flagsNodeGroup)
+		AddCommonCreateNodeGroupIAMAddonsFlags(cmd.CobraCommand.Flags(), flagsNodeGroup)
+		Expect(cmd.CobraCommand.ParseFlags(args)).To(Succeed())
+	}
+
+	Context("copying a nodegroup", func() {
+		It("overrides only the fields given by flags", func() {
+			parseFlags("--node-type", "m5.large", "--node-labels", "role=backend", "--asg-access")
+
+			ng := api.NewNodeGroup()
+			ng.InstanceType = "t3.medium"
+			ng.AMI = "ami-123"
+			ng.VolumeSize = aws.Int(100)
+			OverrideNodeGroupFields(cmd, ng, flagsNodeGroup)
+
+			Expect(ng.InstanceType).To(Equal("m5.large"))
+			Expect(ng.Labels).To(Equal(map[string]string{"role": "backend"}))
+			Expect(*ng.IAM.WithAddonPolicies.AutoScaler).To(BeTrue())
+			Expect(ng.AMI).To(Equal("ami-123"))
+			Expect(*ng.VolumeSize).To(Equal(100))
+		})
+
+		It("resolves the AMI again when only the AMI family is given", func() {
+			parseFlags("--node-ami-family", api.NodeImageFamilyUbuntu1804)
+			ng := api.NewNodeGroup()
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			ng.AMI = "ami-123"
+			OverrideNodeGroupFields(cmd, ng, flagsNodeGroup)
+			Expect(ng.AMIFamily).To(Equal(api.NodeImageFamilyUbuntu1804))
+			Expect(ng.AMI).To(Equal(api.NodeImageResolverAuto))
+
+			parseFlags("--node-ami-family", api.NodeImageFamilyUbuntu1804, "--node-ami", "ami-456")
+			ng.AMI = "ami-123"
+			OverrideNodeGroupFields(cmd, ng, flagsNodeGroup)
+			Expect(ng.AMI).To(Equal("ami-456"))
+		})
+
+		It("chooses the subnets again when the zones or the networking are given", func() {
+			parseFlags("--node-zones", "us-west-2a,us-west-2b")
+			ng := api.NewNodeGroup()
+			ng.Subnets = []string{"subnet-1"}
+			OverrideNodeGroupFields(cmd, ng, flagsNodeGroup)
+			Expect(ng.AvailabilityZones).To(Equal([]string{"us-west-2a", "us-west-2b"}))
+			Expect(ng.Subnets).To(BeEmpty())
+		})
+
+		It("rejects the flags that don't apply to managed nodegroups", func() {
+			parseFlags("--node-type", "m5.large")
+			ng := api.NewManagedNodeGroup()
+			Expect(OverrideManagedNodeGroupFields(cmd, ng, flagsNodeGroup)).To(Succeed())
+			Expect(ng.InstanceType).To(Equal("m5.large"))
+
+			parseFlags("--node-ami", "ami-123")
+			err := OverrideManagedNodeGroupFields(cmd, api.NewManagedNodeGroup(), flagsNodeGroup)
+			Expect(err).To(MatchError("--node-ami is not supported for Managed Nodegroups (--managed=true)"))
+		})
+	})
+})
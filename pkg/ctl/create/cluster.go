@@ -2,9 +2,10 @@ package create
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
-	"github.com/kris-nova/logger"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -12,10 +13,18 @@ import (
 	"github.com/weaveworks/eksctl/pkg/ssh"
 	"github.com/weaveworks/eksctl/pkg/utils/kubectl"
 
+	"github.com/weaveworks/eksctl/pkg/addons"
+	"github.com/weaveworks/eksctl/pkg/ami"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/iam"
 	"github.com/weaveworks/eksctl/pkg/kops"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/managed"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
@@ -52,11 +61,17 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
 		fs.StringSliceVar(&params.AvailabilityZones, "zones", nil, "(auto-select if unspecified)")
 		cmdutils.AddVersionFlag(fs, cfg.Metadata, "")
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, cmd, api.TimeoutPhaseControlPlane, api.TimeoutPhaseNodeGroup, api.TimeoutPhaseAddon)
+		cmdutils.AddStackOnFailureFlag(fs, cmd.ProviderConfig)
 		fs.BoolVarP(&params.InstallWindowsVPCController, "install-vpc-controllers", "", false, "Install VPC controller that's required for Windows workloads")
 		fs.BoolVarP(&params.Managed, "managed", "", false, "Create EKS-managed nodegroup")
 		fs.BoolVarP(&params.Fargate, "fargate", "", false, "Create a Fargate profile scheduling pods in the default and kube-system namespaces onto Fargate")
+		fs.BoolVar(&params.Resume, "resume", false, "Resume the creation of a cluster that failed or timed out, keeping the stacks that were created and creating again the nodegroups that failed")
+		fs.StringSliceVar(&params.Only, "only", nil, "Create only the given parts of the cluster, e.g. to retry the ones that failed, valid options: control-plane, vpc, nodegroups, addons, identity (all parts are created by default)")
+		fs.BoolVar(&params.Interactive, "interactive", false, "Ask for the config of the cluster step by step, print it and create the cluster once confirmed")
+		fs.BoolVar(&params.EnableBudgetAlarms, "enable-budget-alarms", false, fmt.Sprintf("Create alarms of the data processed by the NAT gateways (over %d GB a day) and of the cost of the inter-AZ data transfer (over %d USD a month), sent to an SNS topic", api.DefaultNATGatewayGigabytesPerDay, api.DefaultInterAZTransferDollarsPerMonth))
 	})
 
 	cmd.FlagSetGroup.InFlagSet("Initial nodegroup", func(fs *pflag.FlagSet) {
@@ -76,18 +91,27 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 			api.SubnetTopologyPublic:  fs.StringSlice("vpc-public-subnets", nil, "re-use public subnets of an existing VPC"),
 		}
 		fs.StringVar(&params.KopsClusterNameForVPC, "vpc-from-kops-cluster", "", "re-use VPC from a given kops cluster")
-		fs.StringVar(cfg.VPC.NAT.Gateway, "vpc-nat-mode", api.ClusterSingleNAT, "VPC NAT mode, valid options: HighlyAvailable, Single, Disable")
+		fs.StringVar(cfg.VPC.NAT.Gateway, "vpc-nat-mode", api.ClusterSingleNAT, "VPC NAT mode, valid options: "+strings.Join(api.SupportedNATGatewayModes(), ", "))
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
 
 	cmd.FlagSetGroup.InFlagSet("Output kubeconfig", func(fs *pflag.FlagSet) {
-		cmdutils.AddCommonFlagsForKubeconfig(fs, &params.KubeconfigPath, &params.AuthenticatorRoleARN, &params.SetContext, &params.AutoKubeconfigPath, exampleClusterName)
+		cmdutils.AddCommonFlagsForKubeconfig(fs, &params.KubeconfigPath, &params.Authenticator, &params.AuthenticatorRoleARN, &params.ContextNameTemplate, &params.SetContext, &params.AutoKubeconfigPath, exampleClusterName)
 		fs.BoolVar(&params.WriteKubeconfig, "write-kubeconfig", true, "toggle writing of kubeconfig")
 	})
 }
 
 func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.CreateClusterCmdParams) error {
+	if params.Interactive {
+		configFile, err := runClusterWizard(cmd, os.Stdin, os.Stdout)
+		if err != nil || configFile == "" {
+			return err
+		}
+		defer os.Remove(configFile)
+		cmd.ClusterConfigFile = configFile
+	}
+
 	ngFilter := cmdutils.NewNodeGroupFilter()
 	if err := cmdutils.NewCreateClusterLoader(cmd, ngFilter, ng, params).Load(); err != nil {
 		return err
@@ -130,15 +154,49 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		}
 		params.KubeconfigPath = kubeconfig.AutoPath(meta.Name)
 	}
+	if params.WriteKubeconfig {
+		// the authenticator is checked before creating anything
+		if params.Authenticator, err = kubeconfig.ResolveAuthenticator(params.Authenticator); err != nil {
+			return err
+		}
+		if err := kubeconfig.ValidateContextNameTemplate(params.ContextNameTemplate); err != nil {
+			return err
+		}
+	}
 
-	if checkSubnetsGivenAsFlags(params) {
+	createControlPlane := params.Creates(cmdutils.ClusterPartControlPlane)
+	remainingParts := strings.Join(params.Only, ", ")
+	if params.Resume {
+		resumed, err := resumeCreateCluster(ctl, cfg)
+		if err != nil {
+			return err
+		}
+		createControlPlane = !resumed
+		remainingParts = "the remaining parts"
+	}
+	if !createControlPlane {
+		// the control plane was created by a previous run, the other parts are added to it
+		if err := ctl.RefreshClusterStatus(cfg); err != nil {
+			return errors.Wrapf(err, "cluster %q must exist to create only %s", meta.Name, remainingParts)
+		}
+		meta.Version = ctl.ControlPlaneVersion()
+		if err := ctl.LoadClusterVPC(cfg); err != nil {
+			return errors.Wrapf(err, "getting VPC configuration for cluster %q", meta.Name)
+		}
+		// nodegroups created by a previous run are skipped
+		if err := ngFilter.SetExcludeExistingFilter(ctl.NewStackManager(cfg)); err != nil {
+			return err
+		}
+	}
+
+	if createControlPlane && checkSubnetsGivenAsFlags(params) {
 		// undo defaulting and reset it, as it's not set via config file;
 		// default value here causes errors as vpc.ImportVPC doesn't
 		// treat remote state as authority over local state
 		cfg.VPC.CIDR = nil
 		// load subnets from local map created from flags, into the config
 		for topology := range params.Subnets {
-			if err := vpc.ImportSubnetsFromList(ctl.Provider, cfg, topology, *params.Subnets[topology]); err != nil {
+			if err := vpc.ImportSubnetsFromList(ctl.NewVPCLookupProvider(cfg), cfg, topology, *params.Subnets[topology]); err != nil {
 				return err
 			}
 		}
@@ -228,7 +286,7 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 			return fmt.Errorf("--vpc-private-subnets/--vpc-public-subnets and --vpc-cidr %s", cmdutils.IncompatibleFlags)
 		}
 
-		if err := vpc.ImportAllSubnets(ctl.Provider, cfg); err != nil {
+		if err := vpc.ImportAllSubnets(ctl.NewVPCLookupProvider(cfg), cfg); err != nil {
 			return err
 		}
 
@@ -248,13 +306,36 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		return nil
 	}
 
-	if err := createOrImportVPC(); err != nil {
+	if params.Creates(cmdutils.ClusterPartAddons) {
+		if err := addons.CheckHelm(cfg); err != nil {
+			return err
+		}
+	}
+
+	if createControlPlane {
+		if err := createOrImportVPC(); err != nil {
+			return err
+		}
+		if subnetsGiven {
+			if err := vpc.UseSharedVPCOwner(ctl.Provider, cfg); err != nil {
+				return err
+			}
+		}
+		if err := validateNodeGroupSubnets(ctl.Provider.EC2(), cfg, subnetsGiven || params.KopsClusterNameForVPC != ""); err != nil {
+			return err
+		}
+		if err := iam.ValidateExistingClusterRoles(ctl.Provider, cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := iam.ValidateExistingNodeGroupRoles(ctl.Provider, cfg.NodeGroups, cfg.ManagedNodeGroups); err != nil {
 		return err
 	}
 
 	for _, ng := range cfg.NodeGroups {
 		// resolve AMI
-		if err := eks.EnsureAMI(ctl.Provider, meta.Version, ng); err != nil {
+		if err := eks.EnsureAMI(ctl.Provider, meta.Version, ng, ami.DefaultCatalogPath()); err != nil {
 			return err
 		}
 		logger.Info("nodegroup %q will use %q [%s/%s]", ng.Name, ng.AMI, ng.AMIFamily, cfg.Metadata.Version)
@@ -277,6 +358,10 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		return err
 	}
 
+	if err := ctl.CheckVPCCNIVersion(cfg, !createControlPlane); err != nil {
+		return err
+	}
+
 	logger.Info("using Kubernetes version %s", meta.Version)
 	logger.Info("creating %s", cfg.LogString())
 
@@ -290,7 +375,10 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 
 	{ // core action
 		stackManager := ctl.NewStackManager(cfg)
-		if cmd.ClusterConfigFile == "" {
+		if !createControlPlane {
+			logFiltered()
+			logger.Info("will create only %s of cluster %q", remainingParts, meta.Name)
+		} else if cmd.ClusterConfigFile == "" {
 			logMsg := func(resource string) {
 				logger.Info("will create 2 separate CloudFormation stacks for cluster itself and the initial %s", resource)
 			}
@@ -310,22 +398,54 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		}
 
 		logger.Info("if you encounter any issues, check CloudFormation console or try 'eksctl utils describe-stacks --region=%s --cluster=%s'", meta.Region, meta.Name)
-		supportsManagedNodes, err := eks.VersionSupportsManagedNodes(cfg.Metadata.Version)
-		if err != nil {
-			return err
+		var tasks *manager.TaskTree
+		if createControlPlane {
+			supportsManagedNodes, err := eks.VersionSupportsManagedNodes(cfg.Metadata.Version)
+			if err != nil {
+				return err
+			}
+			var cniTasks []manager.Task
+			if params.Creates(cmdutils.ClusterPartAddons) {
+				cniTasks = ctl.NewCNITasks(cfg)
+			}
+			tasks = stackManager.NewTasksToCreateClusterWithNodeGroups(cfg.NodeGroups, cfg.ManagedNodeGroups, supportsManagedNodes, cniTasks...)
+			ctl.AppendExtraClusterConfigTasks(cfg, params.InstallWindowsVPCController && params.Creates(cmdutils.ClusterPartAddons), tasks)
+		} else {
+			supportsManagedNodes, err := ctl.SupportsManagedNodes(cfg)
+			if err != nil {
+				return err
+			}
+			var cniTasks []manager.Task
+			if params.Creates(cmdutils.ClusterPartAddons) {
+				cniTasks = ctl.NewCNITasks(cfg)
+			}
+			tasks = newTasksToCreateNodeGroups(stackManager, cfg, supportsManagedNodes, cniTasks...)
+			if params.Creates(cmdutils.ClusterPartAddons) {
+				ctl.AppendAddonTasks(cfg, params.InstallWindowsVPCController, tasks)
+			}
 		}
-		tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(cfg.NodeGroups, cfg.ManagedNodeGroups, supportsManagedNodes)
-		ctl.AppendExtraClusterConfigTasks(cfg, params.InstallWindowsVPCController, tasks)
 
 		logger.Info(tasks.Describe())
 		if errs := tasks.DoAllSync(); len(errs) > 0 {
 			logger.Warning("%d error(s) occurred and cluster hasn't been created properly, you may wish to check CloudFormation console", len(errs))
 			logger.Info("to cleanup resources, run 'eksctl delete cluster --region=%s --name=%s'", meta.Region, meta.Name)
+			if cmd.ClusterConfigFile != "" {
+				logger.Info("to resume the creation once the issue is fixed, run 'eksctl create cluster --resume --config-file=%s'", cmd.ClusterConfigFile)
+			}
 			for _, err := range errs {
 				logger.Critical("%s\n", err.Error())
 			}
 			return fmt.Errorf("failed to create cluster %q", meta.Name)
 		}
+
+		if params.Creates(cmdutils.ClusterPartNodeGroups) {
+			if err := nodegroup.SuspendConfiguredProcesses(ctl.Provider, stackManager, cfg.NodeGroups); err != nil {
+				return err
+			}
+			if err := managed.NewService(ctl.Provider, stackManager, meta.Name).TagNodeTemplates(cfg.ManagedNodeGroups); err != nil {
+				return err
+			}
+		}
 	}
 
 	logger.Info("waiting for the control plane availability...")
@@ -336,7 +456,10 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		var kubeconfigContextName string
 
 		if params.WriteKubeconfig {
-			kubectlConfig := kubeconfig.NewForKubectl(cfg, ctl.GetUsername(), params.AuthenticatorRoleARN, ctl.Provider.Profile())
+			kubectlConfig := kubeconfig.NewForKubectl(cfg, ctl.GetUsername(), params.Authenticator, params.AuthenticatorRoleARN, ctl.Provider.Profile())
+			if params.ContextNameTemplate != "" {
+				kubeconfig.RenameContext(kubectlConfig, ctl.KubeconfigContextName(params.ContextNameTemplate, meta))
+			}
 			kubeconfigContextName = kubectlConfig.CurrentContext
 
 			params.KubeconfigPath, err = kubeconfig.Write(params.KubeconfigPath, *kubectlConfig, params.SetContext)
@@ -356,11 +479,33 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		}
 
 		if err = ctl.WaitForControlPlane(meta, clientSet); err != nil {
+			// the control plane is usually up by now, so tell apart an endpoint that can't be reached from this host
+			if _, checkErr := eks.CheckEndpoint(cfg, eks.DefaultEndpointCheckTimeout); checkErr != nil {
+				logger.Critical("%s", checkErr.Error())
+				logger.Info("once the issue is fixed, run 'eksctl utils check-endpoint --region=%s --cluster=%s' to check again", meta.Region, meta.Name)
+			}
 			return err
 		}
 
+		if check, err := eks.CheckEndpoint(cfg, eks.DefaultEndpointCheckTimeout); err != nil {
+			logger.Warning("the API endpoint of the cluster may not be reliably reachable from this host: %v", err)
+		} else {
+			check.Log()
+		}
+
 		// tasks depending on the control plane availability
-		tasks := ctl.NewTasksRequiringControlPlane(cfg)
+		tasks := &manager.TaskTree{}
+		if params.Creates(cmdutils.ClusterPartIdentity) {
+			if !createControlPlane {
+				// service accounts created by a previous run are skipped
+				saFilter := cmdutils.NewIAMServiceAccountFilter()
+				if err := saFilter.SetExcludeExistingFilter(ctl.NewStackManager(cfg), clientSet, cfg.IAM.ServiceAccounts, true); err != nil {
+					return err
+				}
+				cfg.IAM.ServiceAccounts = saFilter.FilterMatching(cfg.IAM.ServiceAccounts)
+			}
+			tasks = ctl.NewTasksRequiringControlPlane(cfg)
+		}
 
 		logger.Info(tasks.Describe())
 		if errs := tasks.DoAllSync(); len(errs) > 0 {
@@ -380,10 +525,16 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 			}
 
 			// wait for nodes to join
-			if err = ctl.WaitForNodes(clientSet, ng); err != nil {
+			if err = waitForNodes(ctl, clientSet, meta.Name, ng, ng.WaitForNodes); err != nil {
 				return err
 			}
 
+			if api.IsEnabled(ng.ZonalStorageClass) {
+				if err := kubernetes.MaybeCreateZonalStorageClass(clientSet, ng.Zone()); err != nil {
+					return err
+				}
+			}
+
 			// if GPU instance type, give instructions
 			if utils.IsGPUInstanceType(ng.InstanceType) || (ng.InstancesDistribution != nil && utils.HasGPUInstanceType(ng.InstancesDistribution.InstanceTypes)) {
 				logger.Info("as you are using a GPU optimized instance type you will need to install NVIDIA Kubernetes device plugin.")
@@ -393,12 +544,26 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		}
 
 		for _, ng := range cfg.ManagedNodeGroups {
-			if err := ctl.WaitForNodes(clientSet, ng); err != nil {
+			if err := waitForNodes(ctl, clientSet, meta.Name, ng, ng.WaitForNodes); err != nil {
 				return err
 			}
 		}
 
-		if cfg.IsFargateEnabled() {
+		if cfg.HasEFANodeGroups() {
+			rawClient, err := ctl.NewRawClient(cfg)
+			if err != nil {
+				return err
+			}
+			if err := addons.InstallEFADevicePlugin(rawClient, ctl.Provider.Region()); err != nil {
+				return err
+			}
+		}
+
+		if err := installNodeTerminationHandler(ctl, cfg); err != nil {
+			return err
+		}
+
+		if cfg.IsFargateEnabled() && params.Creates(cmdutils.ClusterPartNodeGroups) {
 			if err := doCreateFargateProfiles(cmd, ctl); err != nil {
 				return err
 			}
@@ -420,7 +585,7 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		}
 	}
 
-	logger.Success("%s is ready", meta.LogString())
+	logger.Emit(logger.EventClusterReady, map[string]string{"cluster": meta.Name, "region": meta.Region}, "%s is ready", meta.LogString())
 
 	if err := printer.LogObj(logger.Debug, "cfg.json = \\\n%s\n", cfg); err != nil {
 		return err
@@ -428,3 +593,51 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 
 	return nil
 }
+
+// newTasksToCreateNodeGroups returns the tasks creating the nodegroups of cfg in an existing cluster
+func newTasksToCreateNodeGroups(stackManager *manager.StackCollection, cfg *api.ClusterConfig, supportsManagedNodes bool, preNodeGroupTasks ...manager.Task) *manager.TaskTree {
+	tasks := &manager.TaskTree{Parallel: false}
+	// e.g. setting up the CNI plugin, which must be done before the nodes join the cluster
+	tasks.Append(preNodeGroupTasks...)
+	if len(cfg.NodeGroups) == 0 && len(cfg.ManagedNodeGroups) == 0 {
+		return tasks
+	}
+	if supportsManagedNodes {
+		tasks.Append(stackManager.NewClusterCompatTask())
+	}
+
+	allNodeGroupTasks := &manager.TaskTree{
+		Parallel:  true,
+		IsSubTask: true,
+	}
+	if nodeGroupTasks := stackManager.NewTasksToCreateNodeGroups(cfg.NodeGroups, supportsManagedNodes); nodeGroupTasks.Len() > 0 {
+		allNodeGroupTasks.Append(nodeGroupTasks)
+	}
+	if managedTasks := stackManager.NewManagedNodeGroupTask(cfg.ManagedNodeGroups); managedTasks.Len() > 0 {
+		allNodeGroupTasks.Append(managedTasks)
+	}
+	tasks.Append(allNodeGroupTasks)
+	return tasks
+}
+
+// validateNodeGroupSubnets checks the subnets given explicitly to nodegroups, which must belong
+// to an existing VPC
+func validateNodeGroupSubnets(ec2API ec2iface.EC2API, cfg *api.ClusterConfig, existingVPC bool) error {
+	for i, ng := range cfg.NodeGroups {
+		if len(ng.Subnets) > 0 && !existingVPC {
+			return fmt.Errorf("nodeGroups[%d].subnets can only be set when using an existing VPC", i)
+		}
+		if err := vpc.ValidateNodeGroupSubnets(ec2API, cfg, ng); err != nil {
+			return err
+		}
+	}
+	for i, ng := range cfg.ManagedNodeGroups {
+		if len(ng.Subnets) > 0 && !existingVPC {
+			return fmt.Errorf("managedNodeGroups[%d].subnets can only be set when using an existing VPC", i)
+		}
+		if err := vpc.ValidateManagedNodeGroupSubnets(ec2API, cfg, ng); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -65,6 +65,7 @@ var _ = Describe("create cluster", func() {
 			Entry("with full-ecr-access flag", "--full-ecr-access", "true"),
 			Entry("with appmesh-access flag", "--appmesh-access", "true"),
 			Entry("with alb-ingress-access flag", "--alb-ingress-access", "true"),
+			Entry("with only flag", "--only", "nodegroups,addons"),
 		)
 
 		DescribeTable("invalid flags or arguments",
@@ -83,6 +84,10 @@ var _ = Describe("create cluster", func() {
 				args:  []string{"cluster", "--invalid", "dummy"},
 				error: fmt.Errorf("unknown flag: --invalid"),
 			}),
+			Entry("with invalid only flag", invalidParamsCase{
+				args:  []string{"--only", "control-plane,network"},
+				error: fmt.Errorf(`invalid value "network" for --only, valid options: control-plane, vpc, nodegroups, addons, identity`),
+			}),
 		)
 	})
 
@@ -1,7 +1,6 @@
 package create
 
 import (
-	"github.com/kris-nova/logger"
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -10,6 +9,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func createIAMIdentityMappingCmd(cmd *cmdutils.Cmd) {
@@ -40,7 +40,7 @@ func createIAMIdentityMappingCmd(cmd *cmdutils.Cmd) {
 		cmdutils.AddIAMIdentityMappingARNFlags(fs, cmd, &arn)
 		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
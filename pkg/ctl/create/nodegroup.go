@@ -3,10 +3,11 @@ package create
 import (
 	"fmt"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/weaveworks/eksctl/pkg/addons"
+	"github.com/weaveworks/eksctl/pkg/ami"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/ssh"
@@ -16,6 +17,11 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/managed"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
@@ -23,6 +29,8 @@ import (
 type createNodeGroupParams struct {
 	updateAuthConfigMap bool
 	managed             bool
+	force               bool
+	copyFrom            string
 }
 
 func createNodeGroupCmd(cmd *cmdutils.Cmd) {
@@ -54,16 +62,20 @@ func createNodeGroupCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils
 		fs.StringToStringVarP(&cfg.Metadata.Tags, "tags", "", map[string]string{}, `A list of KV pairs used to tag the AWS resources (e.g. "Owner=John Doe,Team=Some Team")`)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
 		cmdutils.AddVersionFlag(fs, cfg.Metadata, `for nodegroups "auto" and "latest" can be used to automatically inherit version from the control plane or force latest`)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
 		cmdutils.AddUpdateAuthConfigMap(fs, &params.updateAuthConfigMap, "Add nodegroup IAM role to aws-auth configmap")
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, cmd, api.TimeoutPhaseNodeGroup)
+		cmdutils.AddStackOnFailureFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddForceVersionSkewFlag(fs, &params.force)
 	})
 
 	cmd.FlagSetGroup.InFlagSet("New nodegroup", func(fs *pflag.FlagSet) {
 		fs.StringVarP(&ng.Name, "name", "n", "", fmt.Sprintf("name of the new nodegroup (generated if unspecified, e.g. %q)", exampleNodeGroupName))
 		cmdutils.AddCommonCreateNodeGroupFlags(fs, cmd, ng)
 		fs.BoolVarP(&params.managed, "managed", "", false, "Create EKS-managed nodegroup")
+		fs.StringVar(&params.copyFrom, "copy-from", "", "name of an existing nodegroup to copy the configuration from, the flags that are set override its fields")
 	})
 
 	cmd.FlagSetGroup.InFlagSet("IAM addons", func(fs *pflag.FlagSet) {
@@ -73,9 +85,52 @@ func createNodeGroupCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
 }
 
+// copyNodeGroup replaces the nodegroup given by flags with a copy of the existing nodegroup
+// copyFrom, in which the fields that were set by flags are overridden
+func copyNodeGroup(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, ng *api.NodeGroup, copyFrom string) error {
+	cfg := cmd.ClusterConfig
+
+	source := api.NewClusterConfig()
+	source.Metadata = cfg.Metadata
+	if err := ctl.LoadNodeGroup(source, ctl.NewStackManager(cfg), copyFrom); err != nil {
+		return err
+	}
+
+	if len(source.ManagedNodeGroups) > 0 {
+		copied := source.ManagedNodeGroups[0]
+		copied.Name = ng.Name
+		if err := cmdutils.OverrideManagedNodeGroupFields(cmd, copied, ng); err != nil {
+			return err
+		}
+		api.SetManagedNodeGroupDefaults(copied, cfg.Metadata)
+		if err := api.ValidateManagedNodeGroup(copied, 0); err != nil {
+			return err
+		}
+		cfg.NodeGroups = nil
+		cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{copied}
+		logger.Info("managed nodegroup %q will be a copy of %q", copied.Name, copyFrom)
+		return nil
+	}
+
+	copied := source.NodeGroups[0]
+	copied.Name = ng.Name
+	cmdutils.OverrideNodeGroupFields(cmd, copied, ng)
+	api.SetNodeGroupDefaults(copied, cfg.Metadata)
+	if err := api.ValidateNodeGroup(0, copied); err != nil {
+		return err
+	}
+	cfg.NodeGroups = []*api.NodeGroup{copied}
+	logger.Info("nodegroup %q will be a copy of %q", copied.Name, copyFrom)
+	return nil
+}
+
 func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeGroupParams) error {
 	ngFilter := cmdutils.NewNodeGroupFilter()
 
+	if params.copyFrom != "" && params.managed {
+		return fmt.Errorf("--copy-from and --managed %s, the type of the nodegroup is copied", cmdutils.IncompatibleFlags)
+	}
+
 	if err := cmdutils.NewCreateNodeGroupLoader(cmd, ng, ngFilter, params.managed).Load(); err != nil {
 		return err
 	}
@@ -99,7 +154,13 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return err
 	}
 
-	if err := checkVersion(cmd, ctl, cfg.Metadata); err != nil {
+	if params.copyFrom != "" {
+		if err := copyNodeGroup(cmd, ctl, ng, params.copyFrom); err != nil {
+			return err
+		}
+	}
+
+	if err := checkVersion(cmd, ctl, cfg.Metadata, params.force); err != nil {
 		return err
 	}
 
@@ -129,9 +190,13 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return err
 	}
 
+	if err := iam.ValidateExistingNodeGroupRoles(ctl.Provider, cfg.NodeGroups, cfg.ManagedNodeGroups); err != nil {
+		return err
+	}
+
 	for _, ng := range cfg.NodeGroups {
 		// resolve AMI
-		if err := eks.EnsureAMI(ctl.Provider, meta.Version, ng); err != nil {
+		if err := eks.EnsureAMI(ctl.Provider, meta.Version, ng, ami.DefaultCatalogPath()); err != nil {
 			return err
 		}
 		logger.Info("nodegroup %q will use %q [%s/%s]", ng.Name, ng.AMI, ng.AMIFamily, cfg.Metadata.Version)
@@ -163,10 +228,38 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return errors.Wrap(err, "cluster compatibility check failed")
 	}
 
+	if err := ctl.CheckVPCCNIVersion(cfg, true); err != nil {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+	// the features are only enabled when the cluster is created, and the nodes of the nodegroup rely on them
+	if err := eks.SetVPCCNIEnv(cfg, clientSet); err != nil {
+		return err
+	}
+
 	if err := vpc.ValidateLegacySubnetsForNodeGroups(cfg, ctl.Provider); err != nil {
 		return err
 	}
 
+	for _, ng := range cfg.NodeGroups {
+		if err := vpc.ValidateNodeGroupSecurityGroups(ctl.Provider.EC2(), cfg, ng); err != nil {
+			return err
+		}
+		if err := vpc.ValidateNodeGroupSubnets(ctl.Provider.EC2(), cfg, ng); err != nil {
+			return err
+		}
+	}
+
+	for _, ng := range cfg.ManagedNodeGroups {
+		if err := vpc.ValidateManagedNodeGroupSubnets(ctl.Provider.EC2(), cfg, ng); err != nil {
+			return err
+		}
+	}
+
 	{
 		logFiltered()
 		logMsg := func(resource string, count int) {
@@ -212,14 +305,16 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 			}
 			return fmt.Errorf("failed to create nodegroups for cluster %q", cfg.Metadata.Name)
 		}
-	}
 
-	{ // post-creation action
-		clientSet, err := ctl.NewStdClientSet(cfg)
-		if err != nil {
+		if err := nodegroup.SuspendConfiguredProcesses(ctl.Provider, stackManager, cfg.NodeGroups); err != nil {
 			return err
 		}
+		if err := managed.NewService(ctl.Provider, stackManager, cfg.Metadata.Name).TagNodeTemplates(cfg.ManagedNodeGroups); err != nil {
+			return err
+		}
+	}
 
+	{ // post-creation action
 		for _, ng := range cfg.NodeGroups {
 			if params.updateAuthConfigMap {
 				// authorise nodes to join
@@ -228,7 +323,13 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 				}
 
 				// wait for nodes to join
-				if err = ctl.WaitForNodes(clientSet, ng); err != nil {
+				if err = waitForNodes(ctl, clientSet, cfg.Metadata.Name, ng, ng.WaitForNodes); err != nil {
+					return err
+				}
+			}
+
+			if api.IsEnabled(ng.ZonalStorageClass) {
+				if err := kubernetes.MaybeCreateZonalStorageClass(clientSet, ng.Zone()); err != nil {
 					return err
 				}
 			}
@@ -239,10 +340,23 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 				logger.Info("\t see the following page for instructions: https://github.com/NVIDIA/k8s-device-plugin")
 			}
 		}
+		if cfg.HasEFANodeGroups() {
+			rawClient, err := ctl.NewRawClient(cfg)
+			if err != nil {
+				return err
+			}
+			if err := addons.InstallEFADevicePlugin(rawClient, ctl.Provider.Region()); err != nil {
+				return err
+			}
+		}
+
+		if err := installNodeTerminationHandler(ctl, cfg); err != nil {
+			return err
+		}
 		logger.Success("created %d nodegroup(s) in cluster %q", len(cfg.NodeGroups), cfg.Metadata.Name)
 
 		for _, ng := range cfg.ManagedNodeGroups {
-			if err := ctl.WaitForNodes(clientSet, ng); err != nil {
+			if err := waitForNodes(ctl, clientSet, cfg.Metadata.Name, ng, ng.WaitForNodes); err != nil {
 				return err
 			}
 		}
@@ -256,3 +370,23 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 
 	return nil
 }
+
+// installNodeTerminationHandler deploys aws-node-termination-handler when it's enabled and the
+// cluster has Spot nodegroups, reading the URL of its queue from the cluster stack in queue mode
+func installNodeTerminationHandler(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) error {
+	if cfg.NodeTerminationHandler == nil || !cfg.HasSpotNodeGroups() {
+		return nil
+	}
+	var queueURL string
+	if cfg.HasNodeTerminationHandlerQueue() {
+		var err error
+		if queueURL, err = ctl.NewStackManager(cfg).GetNodeTerminationHandlerQueueURL(); err != nil {
+			return err
+		}
+	}
+	rawClient, err := ctl.NewRawClient(cfg)
+	if err != nil {
+		return err
+	}
+	return addons.InstallNodeTerminationHandler(rawClient, cfg.NodeTerminationHandler.Mode, ctl.Provider.Region(), queueURL)
+}
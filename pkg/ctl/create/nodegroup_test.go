@@ -52,6 +52,7 @@ var _ = Describe("create nodegroup", func() {
 			Entry("with full-ecr-access flag", "--full-ecr-access", "true"),
 			Entry("with appmesh-access flag", "--appmesh-access", "true"),
 			Entry("with alb-ingress-access flag", "--alb-ingress-access", "true"),
+			Entry("with copy-from flag", "--copy-from", "ng-old", "--node-type", "m5.large"),
 		)
 
 		DescribeTable("invalid flags or arguments",
@@ -74,6 +75,10 @@ var _ = Describe("create nodegroup", func() {
 				args:  []string{"nodegroup", "--invalid", "dummy"},
 				error: fmt.Errorf("unknown flag: --invalid"),
 			}),
+			Entry("with copy-from and managed flags", invalidParamsCase{
+				args:  []string{"--cluster", "clusterName", "--copy-from", "ng-old", "--managed"},
+				error: fmt.Errorf("--copy-from and --managed cannot be used at the same time, the type of the nodegroup is copied"),
+			}),
 		)
 	})
 
@@ -0,0 +1,39 @@
+package create
+
+import (
+	"fmt"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// resumeCreateCluster finds out what a previous run of create cluster created and deletes the stacks
+// of the nodegroups that failed, so that they're created again; it reports whether the cluster stack
+// was created, in which case only the remaining parts of the cluster are created
+func resumeCreateCluster(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) (bool, error) {
+	stackManager := ctl.NewStackManager(cfg)
+	checkpoint, err := stackManager.GetCreateCheckpoint()
+	if err != nil {
+		return false, err
+	}
+	if !checkpoint.ClusterStackComplete {
+		logger.Info("no cluster stack was created by a previous run, cluster %q will be created from the start", cfg.Metadata.Name)
+		return false, nil
+	}
+	logger.Info("resuming the creation of cluster %q, its control plane and %d nodegroup(s) %v were already created",
+		cfg.Metadata.Name, len(checkpoint.CompleteNodeGroups), checkpoint.CompleteNodeGroups)
+
+	tasks := stackManager.NewTasksToDeleteFailedNodeGroups(checkpoint)
+	if tasks.Len() == 0 {
+		return true, nil
+	}
+	logger.Info(tasks.Describe())
+	if errs := tasks.DoAllSync(); len(errs) > 0 {
+		for _, err := range errs {
+			logger.Critical("%s\n", err.Error())
+		}
+		return false, fmt.Errorf("failed to delete the stacks of the nodegroups of cluster %q that failed to be created", cfg.Metadata.Name)
+	}
+	return true, nil
+}
@@ -4,18 +4,21 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
+	kubeclient "k8s.io/client-go/kubernetes"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func checkSubnetsGivenAsFlags(params *cmdutils.CreateClusterCmdParams) bool {
 	return len(*params.Subnets[api.SubnetTopologyPrivate])+len(*params.Subnets[api.SubnetTopologyPublic]) != 0
 }
 
-func checkVersion(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, meta *api.ClusterMeta) error {
+// checkVersion resolves the version of new nodegroups and validates its skew from the control plane,
+// nodegroups that break the version skew policy are only created with force
+func checkVersion(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, meta *api.ClusterMeta, force bool) error {
 	switch meta.Version {
 	case "auto":
 		break
@@ -42,6 +45,9 @@ func checkVersion(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, meta *api.Cluster
 		meta.Version = v
 		logger.Info("will use version %s for new nodegroup(s) based on control plane version", meta.Version)
 	} else if meta.Version != v {
+		if err := cmdutils.CheckKubeletVersionSkew(v, meta.Version, force); err != nil {
+			return err
+		}
 		hint := "--version=auto"
 		if cmd.ClusterConfigFile != "" {
 			hint = "metadata.version: auto"
@@ -69,3 +75,13 @@ func isDeprecatedVersion(version string) bool {
 	}
 	return false
 }
+
+// waitForNodes waits for the nodes of a nodegroup to be ready, unless waitForNodes is disabled in its config
+func waitForNodes(ctl *eks.ClusterProvider, clientSet kubeclient.Interface, clusterName string, ng eks.KubeNodeGroup, wait *bool) error {
+	if api.IsDisabled(wait) {
+		logger.Info("not waiting for the nodes of nodegroup %q to be ready, run 'eksctl utils wait-nodes --cluster=%s --nodegroup=%s' to wait for them",
+			ng.NameString(), clusterName, ng.NameString())
+		return nil
+	}
+	return ctl.WaitForNodes(clientSet, ng)
+}
@@ -0,0 +1,44 @@
+package create
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("waitForNodes", func() {
+	var ng *api.NodeGroup
+
+	BeforeEach(func() {
+		ng = api.NewNodeGroup()
+		ng.Name = "ng-1"
+		minSize := 1
+		ng.MinSize = &minSize
+	})
+
+	It("doesn't wait for the nodes when waitForNodes is disabled", func() {
+		// neither the cluster nor the nodes are used
+		Expect(waitForNodes(nil, nil, "cluster-1", ng, api.Disabled())).To(Succeed())
+	})
+
+	It("waits for the nodes by default", func() {
+		ctl := &eks.ClusterProvider{Provider: mockprovider.NewMockProvider()}
+		clientSet := fake.NewSimpleClientset(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-1",
+				Labels: map[string]string{api.NodeGroupNameLabel: "ng-1"},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		})
+		Expect(waitForNodes(ctl, clientSet, "cluster-1", ng, nil)).To(Succeed())
+		Expect(waitForNodes(ctl, clientSet, "cluster-1", ng, api.Enabled())).To(Succeed())
+	})
+})
@@ -0,0 +1,395 @@
+package create
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/ipnet"
+	"github.com/weaveworks/eksctl/pkg/utils/names"
+)
+
+const (
+	wizardNewVPC      = "new"
+	wizardExistingVPC = "existing"
+
+	wizardManagedNodeGroup     = "managed"
+	wizardSelfManagedNodeGroup = "self-managed"
+	wizardNoNodeGroup          = "none"
+)
+
+var clusterNamePattern = regexp.MustCompile(`^[a-zA-Z][-a-zA-Z0-9]*$`)
+
+// wizardAddonPolicies are the IAM policies of addons that can be attached to the nodegroup of the wizard
+var wizardAddonPolicies = map[string]func(*api.NodeGroupIAMAddonPolicies) **bool{
+	"imageBuilder": func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.ImageBuilder },
+	"autoScaler":   func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.AutoScaler },
+	"externalDNS":  func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.ExternalDNS },
+	"certManager":  func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.CertManager },
+	"appMesh":      func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.AppMesh },
+	"ebs":          func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.EBS },
+	"fsx":          func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.FSX },
+	"efs":          func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.EFS },
+	"albIngress":   func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.ALBIngress },
+	"xRay":         func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.XRay },
+	"cloudWatch":   func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.CloudWatch },
+}
+
+// runClusterWizard asks for the config of the cluster, prints it and, once confirmed, writes it to a
+// temporary file that is loaded like the config file of --config-file; the path is empty when the
+// cluster isn't to be created
+func runClusterWizard(cmd *cmdutils.Cmd, in io.Reader, out io.Writer) (string, error) {
+	if err := cmdutils.ValidateInteractiveCreateCluster(cmd); err != nil {
+		return "", err
+	}
+
+	w := newClusterWizard(in, out)
+	cfg, err := w.run(eks.New(cmd.ProviderConfig, nil).Provider.Region())
+	if err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(out, "\n%s\n", data)
+
+	create, err := w.confirm("Create the cluster")
+	if err != nil {
+		return "", err
+	}
+	if !create {
+		logger.Info("the cluster wasn't created, save the config above to a file to create the cluster later with 'eksctl create cluster -f'")
+		return "", nil
+	}
+
+	f, err := ioutil.TempFile("", "eksctl-cluster-*.yaml")
+	if err != nil {
+		return "", errors.Wrap(err, "writing the config of the cluster")
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return "", errors.Wrap(err, "writing the config of the cluster")
+	}
+	return f.Name(), nil
+}
+
+// clusterWizard asks the questions of `create cluster --interactive` and builds
+// the config of the cluster from the answers
+type clusterWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newClusterWizard(in io.Reader, out io.Writer) *clusterWizard {
+	return &clusterWizard{in: bufio.NewReader(in), out: out}
+}
+
+// run asks for each part of the config in turn, defaultRegion is the region of the AWS profile
+func (w *clusterWizard) run(defaultRegion string) (*api.ClusterConfig, error) {
+	if defaultRegion == "" {
+		defaultRegion = api.DefaultRegion
+	}
+
+	cfg := &api.ClusterConfig{
+		TypeMeta: api.ClusterConfigTypeMeta(),
+		Metadata: &api.ClusterMeta{},
+	}
+
+	var err error
+	meta := cfg.Metadata
+	if meta.Name, err = w.ask("Cluster name", names.ForCluster("", ""), validateClusterName); err != nil {
+		return nil, err
+	}
+	if meta.Region, err = w.choose("Region", api.SupportedRegions(), defaultRegion); err != nil {
+		return nil, err
+	}
+	if meta.Version, err = w.choose("Kubernetes version", api.SupportedVersions(), api.DefaultVersion); err != nil {
+		return nil, err
+	}
+
+	if cfg.VPC, err = w.askVPC(); err != nil {
+		return nil, err
+	}
+
+	nodeGroupType, err := w.choose("Nodegroup", []string{wizardManagedNodeGroup, wizardSelfManagedNodeGroup, wizardNoNodeGroup}, wizardManagedNodeGroup)
+	if err != nil {
+		return nil, err
+	}
+	if nodeGroupType != wizardNoNodeGroup {
+		if err := w.askNodeGroup(cfg, nodeGroupType == wizardManagedNodeGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	withOIDC, err := w.choose("Enable IAM roles for service accounts", []string{"yes", "no"}, "no")
+	if err != nil {
+		return nil, err
+	}
+	if withOIDC == "yes" {
+		cfg.IAM = &api.ClusterIAM{WithOIDC: api.Enabled()}
+	}
+
+	return cfg, nil
+}
+
+func (w *clusterWizard) askVPC() (*api.ClusterVPC, error) {
+	vpcType, err := w.choose("VPC", []string{wizardNewVPC, wizardExistingVPC}, wizardNewVPC)
+	if err != nil {
+		return nil, err
+	}
+
+	if vpcType == wizardNewVPC {
+		defaultCIDR := api.DefaultCIDR()
+		cidr, err := w.ask("VPC CIDR", defaultCIDR.String(), func(answer string) error {
+			_, err := ipnet.ParseCIDR(answer)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		nat, err := w.choose("NAT gateways", api.SupportedNATGatewayModes(), api.ClusterSingleNAT)
+		if err != nil {
+			return nil, err
+		}
+		return &api.ClusterVPC{
+			Network: api.Network{CIDR: ipnet.MustParseCIDR(cidr)},
+			NAT:     &api.ClusterNAT{Gateway: &nat},
+		}, nil
+	}
+
+	vpc := &api.ClusterVPC{Subnets: &api.ClusterSubnets{}}
+	if vpc.ID, err = w.ask("VPC ID", "", validateResourceID("vpc-")); err != nil {
+		return nil, err
+	}
+	for {
+		if vpc.Subnets.Private, err = w.askSubnets("Private subnets, as <zone>=<subnet ID> pairs"); err != nil {
+			return nil, err
+		}
+		if vpc.Subnets.Public, err = w.askSubnets("Public subnets, as <zone>=<subnet ID> pairs"); err != nil {
+			return nil, err
+		}
+		if len(vpc.Subnets.Private)+len(vpc.Subnets.Public) >= api.MinRequiredSubnets {
+			return vpc, nil
+		}
+		fmt.Fprintf(w.out, "  at least %d subnets are required\n", api.MinRequiredSubnets)
+	}
+}
+
+func (w *clusterWizard) askSubnets(question string) (map[string]api.Network, error) {
+	subnets := map[string]api.Network{}
+	_, err := w.ask(question, "", func(answer string) error {
+		for zone := range subnets {
+			delete(subnets, zone)
+		}
+		for _, pair := range splitList(answer) {
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return fmt.Errorf("invalid subnet %q, expected <zone>=<subnet ID>", pair)
+			}
+			if err := validateResourceID("subnet-")(parts[1]); err != nil {
+				return err
+			}
+			subnets[parts[0]] = api.Network{ID: parts[1]}
+		}
+		return nil
+	})
+	return subnets, err
+}
+
+func (w *clusterWizard) askNodeGroup(cfg *api.ClusterConfig, managed bool) error {
+	name, err := w.ask("Nodegroup name", names.ForNodeGroup("", ""), validateClusterName)
+	if err != nil {
+		return err
+	}
+	instanceType, err := w.ask("Instance type", api.DefaultNodeType, func(answer string) error {
+		if !strings.Contains(answer, ".") {
+			return fmt.Errorf("invalid instance type %q", answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	minSize, err := w.askInt("Minimum number of nodes", api.DefaultNodeCount, 0)
+	if err != nil {
+		return err
+	}
+	desiredCapacity, err := w.askInt("Desired number of nodes", max(minSize, api.DefaultNodeCount), minSize)
+	if err != nil {
+		return err
+	}
+	maxSize, err := w.askInt("Maximum number of nodes", max(desiredCapacity, 1), max(desiredCapacity, 1))
+	if err != nil {
+		return err
+	}
+
+	policies := api.NodeGroupIAMAddonPolicies{}
+	addons, err := w.ask(fmt.Sprintf("IAM policies of addons, separated by commas (%s)", strings.Join(sortedAddonPolicies(), ", ")), "", func(answer string) error {
+		for _, addon := range splitList(answer) {
+			if _, ok := wizardAddonPolicies[addon]; !ok {
+				return fmt.Errorf("unknown addon %q", addon)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, addon := range splitList(addons) {
+		*wizardAddonPolicies[addon](&policies) = api.Enabled()
+	}
+	var iam *api.NodeGroupIAM
+	if len(addons) > 0 {
+		iam = &api.NodeGroupIAM{WithAddonPolicies: policies}
+	}
+
+	if managed {
+		cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{{
+			Name:         name,
+			InstanceType: instanceType,
+			ScalingConfig: &api.ScalingConfig{
+				MinSize:         &minSize,
+				DesiredCapacity: &desiredCapacity,
+				MaxSize:         &maxSize,
+			},
+			IAM: iam,
+		}}
+		return nil
+	}
+	cfg.NodeGroups = []*api.NodeGroup{{
+		Name:            name,
+		InstanceType:    instanceType,
+		MinSize:         &minSize,
+		DesiredCapacity: &desiredCapacity,
+		MaxSize:         &maxSize,
+		IAM:             iam,
+	}}
+	return nil
+}
+
+// confirm asks a yes or no question, the answer is no by default
+func (w *clusterWizard) confirm(question string) (bool, error) {
+	answer, err := w.ask(question+" (yes/no)", "no", func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "y", "yes", "n", "no":
+			return nil
+		}
+		return fmt.Errorf("answer yes or no")
+	})
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
+
+// ask prints question and reads answers until validate accepts one, an empty answer selects defaultAnswer
+func (w *clusterWizard) ask(question, defaultAnswer string, validate func(string) error) (string, error) {
+	for {
+		if defaultAnswer != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", question, defaultAnswer)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", question)
+		}
+
+		line, err := w.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", errors.Wrap(err, "reading the answer")
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = defaultAnswer
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(w.out, "  %s\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// choose asks for one of options
+func (w *clusterWizard) choose(question string, options []string, defaultOption string) (string, error) {
+	return w.ask(fmt.Sprintf("%s (%s)", question, strings.Join(options, ", ")), defaultOption, func(answer string) error {
+		for _, option := range options {
+			if answer == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid answer %q, valid answers are: %s", answer, strings.Join(options, ", "))
+	})
+}
+
+// askInt asks for a number that isn't lower than min
+func (w *clusterWizard) askInt(question string, defaultAnswer, min int) (int, error) {
+	answer, err := w.ask(question, strconv.Itoa(defaultAnswer), func(answer string) error {
+		n, err := strconv.Atoi(answer)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", answer)
+		}
+		if n < min {
+			return fmt.Errorf("the number must be at least %d", min)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(answer)
+}
+
+func validateClusterName(name string) error {
+	if !clusterNamePattern.MatchString(name) || len(name) > 100 {
+		return fmt.Errorf("invalid name %q, names start with a letter and only contain letters, digits and dashes", name)
+	}
+	return nil
+}
+
+func validateResourceID(prefix string) func(string) error {
+	return func(id string) error {
+		if !strings.HasPrefix(id, prefix) {
+			return fmt.Errorf("invalid ID %q, expected an ID starting with %q", id, prefix)
+		}
+		return nil
+	}
+}
+
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func sortedAddonPolicies() []string {
+	var addons []string
+	for addon := range wizardAddonPolicies {
+		addons = append(addons, addon)
+	}
+	sort.Strings(addons)
+	return addons
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
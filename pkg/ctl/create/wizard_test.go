@@ -0,0 +1,102 @@
+package create
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("create cluster wizard", func() {
+	runWizard := func(answers ...string) (*api.ClusterConfig, string, error) {
+		out := &bytes.Buffer{}
+		w := newClusterWizard(strings.NewReader(strings.Join(answers, "\n")+"\n"), out)
+		cfg, err := w.run("us-west-2")
+		return cfg, out.String(), err
+	}
+
+	It("builds the config of a cluster with a new VPC and a managed nodegroup", func() {
+		cfg, out, err := runWizard(
+			"cluster-1",   // name
+			"eu-west-1",   // region
+			"",            // version
+			"",            // VPC
+			"10.0.0.0/16", // CIDR
+			"",            // NAT
+			"",            // nodegroup type
+			"ng-1",        // nodegroup name
+			"",            // instance type
+			"1",           // min size
+			"0",           // desired capacity
+			"3",
+			"",               // max size
+			"autoScaler,foo", // addons
+			"autoScaler, ebs",
+			"yes", // OIDC
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring("the number must be at least 1"))
+		Expect(out).To(ContainSubstring(`unknown addon "foo"`))
+
+		Expect(cfg.Metadata.Name).To(Equal("cluster-1"))
+		Expect(cfg.Metadata.Region).To(Equal("eu-west-1"))
+		Expect(cfg.Metadata.Version).To(Equal(api.DefaultVersion))
+		Expect(cfg.VPC.CIDR.String()).To(Equal("10.0.0.0/16"))
+		Expect(*cfg.VPC.NAT.Gateway).To(Equal(api.ClusterSingleNAT))
+		Expect(api.IsEnabled(cfg.IAM.WithOIDC)).To(BeTrue())
+
+		Expect(cfg.NodeGroups).To(BeEmpty())
+		Expect(cfg.ManagedNodeGroups).To(HaveLen(1))
+		ng := cfg.ManagedNodeGroups[0]
+		Expect(ng.Name).To(Equal("ng-1"))
+		Expect(ng.InstanceType).To(Equal(api.DefaultNodeType))
+		Expect(*ng.MinSize).To(Equal(1))
+		Expect(*ng.DesiredCapacity).To(Equal(3))
+		Expect(*ng.MaxSize).To(Equal(3))
+		Expect(api.IsEnabled(ng.IAM.WithAddonPolicies.AutoScaler)).To(BeTrue())
+		Expect(api.IsEnabled(ng.IAM.WithAddonPolicies.EBS)).To(BeTrue())
+		Expect(ng.IAM.WithAddonPolicies.ExternalDNS).To(BeNil())
+	})
+
+	It("builds the config of a cluster in an existing VPC without nodegroup", func() {
+		cfg, out, err := runWizard(
+			"1-cluster", // name
+			"cluster-2",
+			"",                    // region
+			"",                    // version
+			"existing",            // VPC
+			"vpc-123",             // VPC ID
+			"us-west-2a=subnet-a", // private subnets
+			"",                    // public subnets
+			"us-west-2a=subnet-a,us-west-2b=subnet-b", // private subnets
+			"us-west-2a=sg-a",                         // public subnets
+			"",
+			"none", // nodegroup type
+			"",     // OIDC
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(ContainSubstring(`invalid name "1-cluster"`))
+		Expect(out).To(ContainSubstring("at least 2 subnets are required"))
+		Expect(out).To(ContainSubstring(`invalid ID "sg-a"`))
+
+		Expect(cfg.Metadata.Name).To(Equal("cluster-2"))
+		Expect(cfg.Metadata.Region).To(Equal("us-west-2"))
+		Expect(cfg.VPC.ID).To(Equal("vpc-123"))
+		Expect(cfg.VPC.Subnets.Private).To(Equal(map[string]api.Network{
+			"us-west-2a": {ID: "subnet-a"},
+			"us-west-2b": {ID: "subnet-b"},
+		}))
+		Expect(cfg.VPC.Subnets.Public).To(BeEmpty())
+		Expect(cfg.NodeGroups).To(BeEmpty())
+		Expect(cfg.ManagedNodeGroups).To(BeEmpty())
+		Expect(cfg.IAM).To(BeNil())
+	})
+
+	It("fails when the answers end", func() {
+		_, _, err := runWizard("cluster-1")
+		Expect(err).To(MatchError(ContainSubstring("reading the answer")))
+	})
+})
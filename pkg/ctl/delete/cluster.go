@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -15,9 +14,11 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ebs"
 	"github.com/weaveworks/eksctl/pkg/elb"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	ssh "github.com/weaveworks/eksctl/pkg/ssh/client"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
@@ -28,11 +29,13 @@ func deleteClusterCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
+	var forceCleanup bool
+
 	cmd.SetDescription("cluster", "Delete a cluster", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doDeleteCluster(cmd)
+		return doDeleteCluster(cmd, forceCleanup)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -41,9 +44,11 @@ func deleteClusterCmd(cmd *cmdutils.Cmd) {
 
 		cmd.Wait = false
 		cmdutils.AddWaitFlag(fs, &cmd.Wait, "deletion of all resources")
+		fs.BoolVar(&forceCleanup, "force-cleanup", false, "Find and delete orphaned resources blocking deletion of the cluster stack (load balancers, network interfaces, security group rules and EBS volumes), and the IAM roles of iamserviceaccounts and the IAM OIDC provider when the cluster can't be operated; implies --wait")
 
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, cmd, api.TimeoutPhaseControlPlane, api.TimeoutPhaseNodeGroup, api.TimeoutPhaseAddon)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
@@ -73,11 +78,16 @@ func deleteDeprecatedStacks(stackManager *manager.StackCollection) (bool, error)
 	return false, nil
 }
 
-func doDeleteCluster(cmd *cmdutils.Cmd) error {
+func doDeleteCluster(cmd *cmdutils.Cmd, forceCleanup bool) error {
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
 
+	if forceCleanup && !cmd.Wait {
+		logger.Info("--force-cleanup requires waiting for all stacks to be deleted, enabling --wait")
+		cmd.Wait = true
+	}
+
 	cfg := cmd.ClusterConfig
 	meta := cmd.ClusterConfig.Metadata
 
@@ -141,6 +151,10 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 		return nil
 	}
 
+	if stack, err := stackManager.DescribeClusterStack(); err == nil && manager.IsRegisteredClusterStack(stack) {
+		logger.Info("cluster %q was registered with eksctl, its EKS control plane will not be deleted", meta.Name)
+	}
+
 	{
 		// only need to cleanup ELBs if the cluster has already been created.
 		if clusterOperable {
@@ -153,8 +167,28 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 			}
 		}
 
+		var preClusterCleanup func() error
+		if forceCleanup {
+			ctx, cleanup := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cleanup()
+
+			logger.Info("cleaning up orphaned load balancers")
+			if err := elb.CleanupOrphans(ctx, ctl.Provider.ELB(), ctl.Provider.ELBV2(), meta.Name); err != nil {
+				return err
+			}
+
+			preClusterCleanup = func() error {
+				return forceCleanupClusterDependencies(ctl, cfg)
+			}
+		}
+
 		deleteOIDCProvider := clusterOperable && oidcSupported
-		tasks, err := stackManager.NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider, oidc, kubernetes.NewCachedClientSet(clientSet), cmd.Wait, func(errs chan error, _ string) error {
+		if !deleteOIDCProvider && !forceCleanup {
+			if names, err := stackManager.ListIAMServiceAccountStacks(); err == nil && len(names) > 0 {
+				logger.Warning("the IAM roles of %d iamserviceaccount(s) and the IAM OIDC provider of cluster %q can't be deleted as the cluster can't be operated, use --force-cleanup to find and delete them", len(names), meta.Name)
+			}
+		}
+		tasks, err := stackManager.NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider, forceCleanup, oidc, kubernetes.NewCachedClientSet(clientSet), cmd.Wait, func(errs chan error, _ string) error {
 			logger.Info("trying to cleanup dangling network interfaces")
 			if err := ctl.LoadClusterVPC(cfg); err != nil {
 				return errors.Wrapf(err, "getting VPC configuration for cluster %q", cfg.Metadata.Name)
@@ -165,7 +199,7 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 				close(errs)
 			}()
 			return nil
-		})
+		}, preClusterCleanup)
 
 		if err != nil {
 			return err
@@ -187,6 +221,30 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 	return nil
 }
 
+// forceCleanupClusterDependencies deletes resources that were created outside of
+// CloudFormation, but which would prevent the cluster stack from being deleted
+func forceCleanupClusterDependencies(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) error {
+	if err := ctl.LoadClusterVPC(cfg); err != nil {
+		return errors.Wrapf(err, "getting VPC configuration for cluster %q", cfg.Metadata.Name)
+	}
+
+	logger.Info("cleaning up security group rules referring to the cluster")
+	if err := vpc.CleanupSecurityGroupReferences(ctl.Provider.EC2(), cfg); err != nil {
+		return err
+	}
+
+	logger.Info("cleaning up dangling network interfaces")
+	if err := vpc.CleanupNetworkInterfaces(ctl.Provider.EC2(), cfg); err != nil {
+		return err
+	}
+	if err := vpc.CleanupCNINetworkInterfaces(ctl.Provider.EC2(), cfg); err != nil {
+		return err
+	}
+
+	logger.Info("cleaning up EBS volumes of dynamically provisioned persistent volumes")
+	return ebs.Cleanup(ctl.Provider.EC2(), cfg.Metadata.Name)
+}
+
 func deleteFargateProfiles(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider) error {
 	awsClient := fargate.NewClientWithWaitTimeout(
 		cmd.ClusterConfig.Metadata.Name,
@@ -1,13 +1,16 @@
 package delete
 
 import (
-	"github.com/kris-nova/logger"
+	"fmt"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func deleteIAMIdentityMappingCmd(cmd *cmdutils.Cmd) {
@@ -15,29 +18,36 @@ func deleteIAMIdentityMappingCmd(cmd *cmdutils.Cmd) {
 	cmd.ClusterConfig = cfg
 
 	var (
-		arn string
-		all bool
+		filter authconfigmap.IdentityFilter
+		index  int
+		all    bool
 	)
 
 	cmd.SetDescription("iamidentitymapping", "Delete a IAM identity mapping", "")
 
-	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
-		return doDeleteIAMIdentityMapping(cmd, arn, all)
+	cmd.CobraCommand.RunE = func(c *cobra.Command, args []string) error {
+		if c.Flags().Changed("index") {
+			filter.Index = &index
+		}
+		return doDeleteIAMIdentityMapping(cmd, filter, all)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		fs.BoolVar(&all, "all", false, "Delete all matching mappings instead of just one")
-		cmdutils.AddIAMIdentityMappingARNFlags(fs, cmd, &arn)
+		fs.StringVar(&filter.Username, "username", "", "Only delete mappings to this Kubernetes user name")
+		fs.StringArrayVar(&filter.Groups, "group", []string{}, "Only delete mappings to exactly these Kubernetes groups")
+		fs.IntVar(&index, "index", 0, "Delete the mapping at this position among the matching ones, starting at 0, as listed by get iamidentitymapping")
+		cmdutils.AddIAMIdentityMappingARNFlags(fs, cmd, &filter.ARN)
 		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doDeleteIAMIdentityMapping(cmd *cmdutils.Cmd, arn string, all bool) error {
+func doDeleteIAMIdentityMapping(cmd *cmdutils.Cmd, filter authconfigmap.IdentityFilter, all bool) error {
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -54,9 +64,17 @@ func doDeleteIAMIdentityMapping(cmd *cmdutils.Cmd, arn string, all bool) error {
 		return err
 	}
 
-	if arn == "" {
+	if filter.ARN == "" {
 		return cmdutils.ErrMustBeSet("--arn")
 	}
+	if filter.Index != nil {
+		if all {
+			return errors.New("--index cannot be used with --all")
+		}
+		if *filter.Index < 0 {
+			return fmt.Errorf("--index must be 0 or more, got %d", *filter.Index)
+		}
+	}
 	if cfg.Metadata.Name == "" {
 		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
 	}
@@ -73,7 +91,7 @@ func doDeleteIAMIdentityMapping(cmd *cmdutils.Cmd, arn string, all bool) error {
 		return err
 	}
 
-	if err := acm.RemoveIdentity(arn, all); err != nil {
+	if err := acm.RemoveMatchingIdentities(filter, all); err != nil {
 		return err
 	}
 	if err := acm.Save(); err != nil {
@@ -89,13 +107,13 @@ func doDeleteIAMIdentityMapping(cmd *cmdutils.Cmd, arn string, all bool) error {
 	duplicates := 0
 	for _, identity := range identities {
 
-		if arn == identity.ARN() {
+		if filter.ARN == identity.ARN() {
 			duplicates++
 		}
 	}
 
 	if duplicates > 0 {
-		logger.Warning("there are %d mappings left with same arn %q (use --all to delete them at once)", duplicates, arn)
+		logger.Warning("there are %d mappings left with same arn %q (use --all to delete them at once, or --username, --group and --index to select the ones to delete)", duplicates, filter.ARN)
 	}
 	return nil
 }
@@ -51,5 +51,13 @@ var _ = Describe("delete", func() {
 			args:  []string{"nodegroup", "ng", "--cluster", "dummy", "--name", "ng"},
 			error: fmt.Errorf("--name=ng and argument ng cannot be used at the same time"),
 		}),
+		Entry("setting --name and --include at the same time", invalidParamsCase{
+			args:  []string{"nodegroup", "--cluster", "dummy", "--name", "ng", "--include", "spot-*"},
+			error: fmt.Errorf(`nodegroup name "ng" and --include/--exclude cannot be used at the same time`),
+		}),
+		Entry("setting --approve with --name", invalidParamsCase{
+			args:  []string{"nodegroup", "--cluster", "dummy", "--name", "ng", "--approve"},
+			error: fmt.Errorf("cannot use --approve unless a config file or --include/--exclude is specified"),
+		}),
 	)
 })
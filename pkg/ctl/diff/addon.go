@@ -0,0 +1,142 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+var addonNames = []string{defaultaddons.AWSNode, defaultaddons.CoreDNS, defaultaddons.KubeProxy}
+
+func diffAddonCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var name, kubernetesVersion string
+
+	cmd.SetDescription("addon", "Compare the default addons of a cluster with the defaults for its Kubernetes version",
+		"Reports the addons whose version or configuration differs from the defaults bundled with eksctl, and the deprecated configuration keys they use; "+
+			"use --kubernetes-version to compare with the defaults of the version the cluster is going to be upgraded to")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doDiffAddon(cmd, name, kubernetesVersion)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		fs.StringVarP(&name, "name", "n", "", fmt.Sprintf("name of the addon (%s), all addons are compared if not set", strings.Join(addonNames, ", ")))
+		fs.StringVar(&kubernetesVersion, "kubernetes-version", "", "Kubernetes version to compare with, defaults to the version of the cluster")
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doDiffAddon(cmd *cmdutils.Cmd, name, kubernetesVersion string) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	if name != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg("--name", name, cmd.NameArg)
+	}
+	if cmd.NameArg != "" {
+		name = cmd.NameArg
+	}
+
+	names := addonNames
+	if name != "" {
+		names = []string{name}
+	}
+	for _, n := range names {
+		if !isAddonName(n) {
+			return fmt.Errorf("unknown addon %q, supported addons are: %s", n, strings.Join(addonNames, ", "))
+		}
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	rawClient, err := ctl.NewRawClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	if kubernetesVersion == "" {
+		if kubernetesVersion, err = rawClient.ServerVersion(); err != nil {
+			return err
+		}
+	}
+
+	drift := false
+	for _, n := range names {
+		diff, err := defaultaddons.Diff(rawClient.ClientSet(), n, kubernetesVersion)
+		if err != nil {
+			return err
+		}
+		logAddonDiff(diff, kubernetesVersion)
+		drift = drift || diff.HasDrift()
+	}
+
+	if !drift {
+		logger.Success("addons of cluster %q match the defaults for Kubernetes %s", meta.Name, kubernetesVersion)
+	}
+	return nil
+}
+
+func isAddonName(name string) bool {
+	for _, n := range addonNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func logAddonDiff(diff *defaultaddons.AddonDiff, kubernetesVersion string) {
+	if !diff.HasDrift() {
+		logger.Info("%q matches the defaults for Kubernetes %s (%s)", diff.Name, kubernetesVersion, diff.InstalledVersion)
+		return
+	}
+
+	if diff.VersionDiffers() {
+		logger.Warning("%q is at version %s, the default for Kubernetes %s is %s", diff.Name, diff.InstalledVersion, kubernetesVersion, diff.DefaultVersion)
+	}
+	for _, change := range diff.Changes {
+		logger.Warning("%q configuration drift: %s", diff.Name, change.String())
+	}
+
+	keys := make([]string, 0, len(diff.Deprecated))
+	for key := range diff.Deprecated {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		logger.Critical("%q uses deprecated configuration %s: %s", diff.Name, key, diff.Deprecated[key])
+	}
+}
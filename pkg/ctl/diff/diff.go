@@ -0,0 +1,15 @@
+package diff
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+// Command creates the `diff` commands
+func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
+	verbCmd := cmdutils.NewVerbCmd("diff", "Compare resources with their defaults", "")
+
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, diffAddonCmd)
+
+	return verbCmd
+}
@@ -32,6 +32,14 @@ var _ = Describe("enable", func() {
 			Expect(out).To(ContainSubstring("usage"))
 		})
 	})
+
+	Describe("flux", func() {
+		It("requires a config file", func() {
+			cmd := newMockCmd("flux")
+			_, err := cmd.execute()
+			Expect(err).To(MatchError("--config-file must be set"))
+		})
+	})
 })
 
 func newMockCmd(args ...string) *mockVerbCmd {
@@ -0,0 +1,77 @@
+package enable
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/git/executor"
+	"github.com/weaveworks/eksctl/pkg/gitops/flux"
+)
+
+func enableFlux(cmd *cmdutils.Cmd) {
+	cmd.ClusterConfig = api.NewClusterConfig()
+	cmd.SetDescription(
+		"flux",
+		"Bootstrap Flux v2 in a cluster, with the gitops.flux settings of the config file",
+		"",
+	)
+	var force bool
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doEnableFlux(cmd, force)
+	}
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.BoolVar(&force, "force", false, "Bootstrap Flux even if it's already bootstrapped with the same settings, e.g. to upgrade it")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+// doEnableFlux bootstraps Flux v2 with the Flux CLI, which reads the credentials of the cluster
+// from a temporary kubeconfig
+func doEnableFlux(cmd *cmdutils.Cmd, force bool) error {
+	if cmd.ClusterConfigFile == "" {
+		return cmdutils.ErrMustBeSet("--config-file")
+	}
+	if err := cmdutils.NewGitOpsConfigLoader(cmd).Load(); err != nil {
+		return err
+	}
+	cfg := cmd.ClusterConfig
+	api.SetFluxDefaults(cfg)
+	if err := api.ValidateFlux(cfg); err != nil {
+		return err
+	}
+	if err := flux.CheckBootstrap(cfg.GitOps.Flux); err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+	client, err := ctl.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	clientSet, err := client.NewClientSet()
+	if err != nil {
+		return err
+	}
+	kubeconfigPath, err := client.WriteTempKubeconfig("eksctl-flux-kubeconfig-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(kubeconfigPath)
+
+	return flux.NewBootstrapper(executor.NewShellExecutor(nil), clientSet, kubeconfigPath).Bootstrap(cfg.GitOps.Flux, force)
+}
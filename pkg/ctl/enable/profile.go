@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -20,6 +19,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/gitops"
 	"github.com/weaveworks/eksctl/pkg/gitops/fileprocessor"
 	"github.com/weaveworks/eksctl/pkg/gitops/profile"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // ProfileOptions groups input for the "enable profile" command.
@@ -28,14 +28,6 @@ type ProfileOptions struct {
 	profileOptions profile.Options
 }
 
-// Validate validates this ProfileOptions object.
-func (opts ProfileOptions) Validate() error {
-	if err := opts.gitOptions.Validate(); err != nil {
-		return err
-	}
-	return opts.profileOptions.Validate()
-}
-
 func enableProfileCmd(cmd *cmdutils.Cmd) {
 	cmd.ClusterConfig = api.NewClusterConfig()
 	cmd.SetDescription(
@@ -57,12 +49,12 @@ func configureProfileCmd(cmd *cmdutils.Cmd) *ProfileOptions {
 	var opts ProfileOptions
 	cmd.FlagSetGroup.InFlagSet("Enable profile", func(fs *pflag.FlagSet) {
 		cmdutils.AddCommonFlagsForProfile(fs, &opts.profileOptions)
-		cmdutils.AddCommonFlagsForGit(fs, &opts.gitOptions)
+		cmdutils.AddGitFlags(fs, &opts.gitOptions)
 	})
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		fs.StringVar(&cmd.ClusterConfig.Metadata.Name, "cluster", "", "name of the EKS cluster to enable this Quick Start profile on")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddTimeoutFlagWithValue(fs, &cmd.ProviderConfig.WaitTimeout, 20*time.Second)
 	})
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
@@ -77,7 +69,7 @@ func doEnableProfile(cmd *cmdutils.Cmd, opts *ProfileOptions) error {
 	if cmd.NameArg != "" {
 		opts.profileOptions.Name = cmd.NameArg
 	}
-	if err := opts.Validate(); err != nil {
+	if err := opts.profileOptions.Validate(); err != nil {
 		return err
 	}
 	profileRepoURL, err := profile.RepositoryURL(opts.profileOptions.Name)
@@ -88,6 +80,34 @@ func doEnableProfile(cmd *cmdutils.Cmd, opts *ProfileOptions) error {
 		return err
 	}
 
+	// With gitops.flux, the profile is committed to the repository Flux syncs from, in a directory
+	// of the path of the cluster, so that Flux applies it
+	profileDir := "base"
+	if cfg := cmd.ClusterConfig; cfg.HasFlux() {
+		api.SetFluxDefaults(cfg)
+		useFluxRepository(cmd, &opts.gitOptions, cfg.GitOps.Flux)
+		profileName, err := git.RepoName(profileRepoURL)
+		if err != nil {
+			return err
+		}
+		profileDir = filepath.Join(cfg.GitOps.Flux.Path, profileName)
+	}
+	if err := cmdutils.ValidateGitOptions(&opts.gitOptions); err != nil {
+		return err
+	}
+
+	// The status of the cluster gives the account the templates are rendered with
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+	if ok, err := ctl.CanOperate(cmd.ClusterConfig); !ok {
+		return err
+	}
+
 	// Clone user's repo to apply Quick Start profile
 	usersRepoName, err := git.RepoName(opts.gitOptions.URL)
 	if err != nil {
@@ -98,7 +118,7 @@ func doEnableProfile(cmd *cmdutils.Cmd, opts *ProfileOptions) error {
 		return errors.Wrapf(err, "unable to create temporary directory for %q", usersRepoName)
 	}
 	logger.Debug("Directory %s will be used to clone the configuration repository and install the profile", usersRepoDir)
-	profileOutputPath := filepath.Join(usersRepoDir, "base")
+	profileOutputPath := filepath.Join(usersRepoDir, profileDir)
 
 	gitClient := git.NewGitClient(git.ClientParams{
 		PrivateSSHKeyPath: opts.gitOptions.PrivateSSHKeyPath,
@@ -153,3 +173,17 @@ func doEnableProfile(cmd *cmdutils.Cmd, opts *ProfileOptions) error {
 	os.RemoveAll(usersRepoDir)
 	return nil
 }
+
+// useFluxRepository sets the repository, branch and private key of the Git options that aren't set by
+// flags from the settings of Flux
+func useFluxRepository(cmd *cmdutils.Cmd, opts *git.Options, flux *api.Flux) {
+	if opts.URL == "" {
+		opts.URL = flux.RepositoryURL()
+	}
+	if flag := cmd.CobraCommand.Flag("git-branch"); flag == nil || !flag.Changed {
+		opts.Branch = flux.Branch
+	}
+	if opts.PrivateSSHKeyPath == "" {
+		opts.PrivateSSHKeyPath = flux.PrivateKeyFile
+	}
+}
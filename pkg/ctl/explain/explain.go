@@ -0,0 +1,32 @@
+package explain
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/weaveworks/eksctl/pkg/explain"
+)
+
+// Command creates the `explain` command
+func Command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain [FIELD]",
+		Short: "Describe the fields of the config file",
+		Long: `Describe a field of the config file, such as its type, default value and documentation,
+and list its own fields; the field is identified by the names of the fields leading to it, e.g.
+
+eksctl explain nodeGroups.iam.withAddonPolicies
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) == 1 {
+				path = args[0]
+			}
+			field, err := explain.Explain(path)
+			if err != nil {
+				return err
+			}
+			return field.Print(cmd.OutOrStdout())
+		},
+	}
+}
@@ -0,0 +1,192 @@
+package get
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+// treeOutput prints the resources of a cluster as a tree
+const treeOutput = "tree"
+
+func getAllCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output, outputPath string
+
+	cmd.SetDescription("all", "Get all the resources eksctl manages for a cluster",
+		"Lists the stacks, nodegroups, iamserviceaccounts, Fargate profiles, IAM OIDC provider, default add-ons and iamidentitymappings of a cluster")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doGetAll(cmd, output, outputPath)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringVarP(&output, "output", "o", treeOutput, "specifies the output format (valid option: tree, json, yaml)")
+		fs.StringVar(&outputPath, "output-path", "", "write the output to a local file or an S3 object (s3://bucket/key) instead of stdout")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doGetAll(cmd *cmdutils.Cmd, output, outputPath string) error {
+	cfg := cmd.ClusterConfig
+
+	if cfg.Metadata.Name != "" && cmd.NameArg != "" {
+		return cmdutils.ErrClusterFlagAndArg(cmd, cfg.Metadata.Name, cmd.NameArg)
+	}
+	if cmd.NameArg != "" {
+		cfg.Metadata.Name = cmd.NameArg
+	}
+	if cfg.Metadata.Name == "" {
+		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	}
+
+	var printer printers.OutputPrinter
+	if output != treeOutput {
+		var err error
+		if output != printers.JSONType && output != printers.YAMLType {
+			return fmt.Errorf("unknown output format %q, it must be one of %q, %q or %q", output, treeOutput, printers.JSONType, printers.YAMLType)
+		}
+		if printer, err = printers.NewPrinter(output); err != nil {
+			return err
+		}
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+
+	var clientSet kubernetes.Interface
+	if ok, err := ctl.CanOperate(cfg); ok {
+		if clientSet, err = ctl.NewStdClientSet(cfg); err != nil {
+			return err
+		}
+	} else {
+		logger.Warning("%s, the IAM OIDC provider, default add-ons and iamidentitymappings are not listed", err.Error())
+	}
+
+	resources, err := ctl.GetClusterResources(cfg, clientSet)
+	if err != nil {
+		return err
+	}
+
+	return printers.WriteOutput(outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		if printer != nil {
+			return printer.PrintObj(resources, w)
+		}
+		return newResourceTree(resources).write(w)
+	})
+}
+
+// treeNode is a node of the tree output
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+func (n *treeNode) add(format string, a ...interface{}) *treeNode {
+	child := &treeNode{label: fmt.Sprintf(format, a...)}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *treeNode) write(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, n.label); err != nil {
+		return err
+	}
+	return n.writeChildren(w, "")
+}
+
+func (n *treeNode) writeChildren(w io.Writer, indent string) error {
+	for i, child := range n.children {
+		branch, childIndent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+		if _, err := fmt.Fprintf(w, "%s%s%s\n", indent, branch, child.label); err != nil {
+			return err
+		}
+		if err := child.writeChildren(w, indent+childIndent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newResourceTree returns the tree of the resources of a cluster, the add-ons and
+// iamidentitymappings are left out when they weren't read from the cluster
+func newResourceTree(r *eks.ClusterResources) *treeNode {
+	root := &treeNode{label: fmt.Sprintf("cluster %s (%s)", r.Cluster, r.Region)}
+
+	stacks := root.add("stacks (%d)", len(r.Stacks))
+	for _, s := range r.Stacks {
+		stacks.add("%s (%s, %s)", s.Name, s.Type, s.Status)
+	}
+
+	nodeGroups := root.add("nodegroups (%d)", len(r.NodeGroups))
+	for _, ng := range r.NodeGroups {
+		nodeGroups.add("%s (%s, stack %s)", ng.Name, ng.Type, ng.StackName)
+	}
+
+	serviceAccounts := root.add("iamserviceaccounts (%d)", len(r.IAMServiceAccounts))
+	for _, sa := range r.IAMServiceAccounts {
+		roleARN := ""
+		if sa.Status != nil {
+			roleARN = aws.StringValue(sa.Status.RoleARN)
+		}
+		serviceAccounts.add("%s (role %s)", sa.NameString(), roleARN)
+	}
+
+	fargateProfiles := root.add("fargateprofiles (%d)", len(r.FargateProfiles))
+	for _, fp := range r.FargateProfiles {
+		fargateProfiles.add("%s", fp.Name)
+	}
+
+	if r.OIDCProviderARN != "" {
+		root.add("iamoidcprovider").add("%s", r.OIDCProviderARN)
+	} else {
+		root.add("iamoidcprovider (none)")
+	}
+
+	if r.Addons != nil {
+		addons := root.add("addons (%d)", len(r.Addons))
+		for _, addon := range r.Addons {
+			addons.add("%s %s", addon.Name, addon.Version)
+		}
+	}
+
+	if r.IAMIdentityMappings != nil {
+		mappings := root.add("iamidentitymappings (%d)", len(r.IAMIdentityMappings))
+		for _, identity := range r.IAMIdentityMappings {
+			mappings.add("%s (username %s, groups %s)", identity.ARN(), identity.Username(), strings.Join(identity.Groups(), ","))
+		}
+	}
+
+	return root
+}
@@ -0,0 +1,73 @@
+package get
+
+import (
+	"bytes"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("get", func() {
+	Describe("all", func() {
+		It("without a cluster name", func() {
+			cmd := newMockCmd("all")
+			_, err := cmd.execute()
+			Expect(err).To(MatchError("--cluster must be set"))
+		})
+
+		It("with a cluster name flag and argument", func() {
+			cmd := newMockCmd("all", "--cluster", "foo", "bar")
+			_, err := cmd.execute()
+			Expect(err).To(MatchError("--cluster=foo and argument bar cannot be used at the same time"))
+		})
+
+		It("with an unsupported output format", func() {
+			cmd := newMockCmd("all", "--cluster", "foo", "-o", "table")
+			_, err := cmd.execute()
+			Expect(err).To(MatchError(`unknown output format "table", it must be one of "tree", "json" or "yaml"`))
+		})
+
+		It("prints the resources as a tree", func() {
+			resources := &eks.ClusterResources{
+				Cluster: "prod",
+				Region:  "us-west-2",
+				Stacks: []*eks.StackResource{
+					{Name: "eksctl-prod-cluster", Type: eks.StackTypeCluster, Status: "CREATE_COMPLETE"},
+					{Name: "eksctl-prod-nodegroup-ng-1", Type: eks.StackTypeNodeGroup, Status: "UPDATE_COMPLETE"},
+				},
+				NodeGroups: []*manager.NodeGroupSummary{
+					{Name: "ng-1", Type: api.NodeGroupTypeUnmanaged, StackName: "eksctl-prod-nodegroup-ng-1"},
+				},
+				IAMServiceAccounts: []*api.ClusterIAMServiceAccount{{
+					Status: &api.ClusterIAMServiceAccountStatus{RoleARN: aws.String("arn:aws:iam::12345:role/s3-reader")},
+				}},
+				OIDCProviderARN: "arn:aws:iam::12345:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/ABC",
+				Addons:          []*eks.AddonResource{{Name: "coredns", Version: "v1.6.6"}},
+			}
+			resources.IAMServiceAccounts[0].Name = "s3-reader"
+			resources.IAMServiceAccounts[0].Namespace = "default"
+
+			out := new(bytes.Buffer)
+			Expect(newResourceTree(resources).write(out)).To(Succeed())
+			Expect(out.String()).To(Equal(`cluster prod (us-west-2)
+├── stacks (2)
+│   ├── eksctl-prod-cluster (cluster, CREATE_COMPLETE)
+│   └── eksctl-prod-nodegroup-ng-1 (nodegroup, UPDATE_COMPLETE)
+├── nodegroups (1)
+│   └── ng-1 (unmanaged, stack eksctl-prod-nodegroup-ng-1)
+├── iamserviceaccounts (1)
+│   └── default/s3-reader (role arn:aws:iam::12345:role/s3-reader)
+├── fargateprofiles (0)
+├── iamoidcprovider
+│   └── arn:aws:iam::12345:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/ABC
+└── addons (1)
+    └── coredns v1.6.6
+`))
+		})
+	})
+})
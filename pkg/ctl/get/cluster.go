@@ -2,19 +2,25 @@ package get
 
 import (
 	"fmt"
+	"io"
 
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
 func getClusterCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
-	var listAllRegions bool
+	var (
+		listAllRegions bool
+		filters        []string
+	)
 
 	params := &getCmdParams{}
 
@@ -22,21 +28,24 @@ func getClusterCmd(cmd *cmdutils.Cmd) {
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doGetCluster(cmd, params, listAllRegions)
+		return doGetCluster(cmd, params, listAllRegions, filters)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		fs.StringVarP(&cfg.Metadata.Name, "name", "n", "", "EKS cluster name")
 		fs.BoolVarP(&listAllRegions, "all-regions", "A", false, "List clusters across all supported regions")
+		fs.StringArrayVar(&filters, "filter", nil, "List only the clusters matching a filter, either name=<pattern>, tag:<key>=<pattern> or tag:<key>, e.g. name=prod-*; it can be given multiple times, the clusters must match all the filters")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output)
+		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output, &params.outputPath)
+		cmdutils.AddColumnsFlag(fs, &params.columns)
+		fs.Lookup("output").Usage = "specifies the output format (valid option: table, wide, json, yaml, prometheus, jsonpath=<template>, go-template=<template>)"
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doGetCluster(cmd *cmdutils.Cmd, params *getCmdParams, listAllRegions bool) error {
+func doGetCluster(cmd *cmdutils.Cmd, params *getCmdParams, listAllRegions bool, filters []string) error {
 	cfg := cmd.ClusterConfig
 	regionGiven := cfg.Metadata.Region != "" // eks.New resets this field, so we need to check if it was set in the fist place
 
@@ -61,9 +70,20 @@ func doGetCluster(cmd *cmdutils.Cmd, params *getCmdParams, listAllRegions bool)
 		return fmt.Errorf("--all-regions is for listing all clusters, it must be used without cluster name flag/argument")
 	}
 
+	if cfg.Metadata.Name != "" && len(filters) > 0 {
+		return fmt.Errorf("--filter is for listing clusters, it must be used without cluster name flag/argument")
+	}
+
+	filter, err := eks.NewClusterFilter(filters)
+	if err != nil {
+		return err
+	}
+
 	if err := ctl.CheckAuth(); err != nil {
 		return err
 	}
 
-	return ctl.ListClusters(cfg.Metadata.Name, params.chunkSize, params.output, listAllRegions)
+	return printers.WriteOutput(params.outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		return ctl.ListClusters(cfg.Metadata.Name, params.chunkSize, params.output, params.columns, listAllRegions, filter, w)
+	})
 }
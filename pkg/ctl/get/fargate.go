@@ -2,14 +2,15 @@ package get
 
 import (
 	"fmt"
-	"os"
+	"io"
 
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/fargate"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
 type options struct {
@@ -48,9 +49,9 @@ func configureGetFargateProfileCmd(cmd *cmdutils.Cmd) *options {
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddClusterFlag(fs, cmd.ClusterConfig.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
-		cmdutils.AddCommonFlagsForGetCmd(fs, &options.chunkSize, &options.output)
+		cmdutils.AddCommonFlagsForGetCmd(fs, &options.chunkSize, &options.output, &options.outputPath)
 	})
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 	return &options
@@ -81,7 +82,9 @@ func doGetFargateProfile(cmd *cmdutils.Cmd, options *options) error {
 	if err != nil {
 		return err
 	}
-	return fargate.PrintProfiles(profiles, os.Stdout, options.output)
+	return printers.WriteOutput(options.outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		return fargate.PrintProfiles(profiles, w, options.output)
+	})
 }
 
 func getProfiles(awsClient *fargate.Client, name string) ([]*api.FargateProfile, error) {
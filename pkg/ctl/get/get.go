@@ -8,8 +8,10 @@ import (
 )
 
 type getCmdParams struct {
-	chunkSize int
-	output    printers.Type
+	chunkSize  int
+	output     printers.Type
+	outputPath string
+	columns    []string
 }
 
 // Command will create the `get` commands
@@ -22,6 +24,7 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getIAMIdentityMappingCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getLabelsCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getFargateProfile)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getAllCmd)
 
 	return verbCmd
 }
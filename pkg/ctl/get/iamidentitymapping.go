@@ -1,7 +1,10 @@
 package get
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -19,7 +22,10 @@ func getIAMIdentityMappingCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
-	var arn string
+	var (
+		arn   string
+		watch bool
+	)
 
 	params := &getCmdParams{}
 
@@ -27,26 +33,32 @@ func getIAMIdentityMappingCmd(cmd *cmdutils.Cmd) {
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doGetIAMIdentityMapping(cmd, params, arn)
+		return doGetIAMIdentityMapping(cmd, params, arn, watch)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddIAMIdentityMappingARNFlags(fs, cmd, &arn)
 		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output, &params.outputPath)
+		cmdutils.AddColumnsFlag(fs, &params.columns)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		fs.BoolVarP(&watch, "watch", "w", false, "after listing the mappings, watch for changes made to them")
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doGetIAMIdentityMapping(cmd *cmdutils.Cmd, params *getCmdParams, arn string) error {
+func doGetIAMIdentityMapping(cmd *cmdutils.Cmd, params *getCmdParams, arn string, watch bool) error {
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
 
+	if watch && params.outputPath != "" {
+		return fmt.Errorf("--output-path cannot be used with --watch")
+	}
+
 	cfg := cmd.ClusterConfig
 
 	ctl, err := cmd.NewCtl()
@@ -88,8 +100,8 @@ func doGetIAMIdentityMapping(cmd *cmdutils.Cmd, params *getCmdParams, arn string
 		}
 
 		identities = selectedIdentities
-		// If a filter was given, we error if none was found
-		if len(identities) == 0 {
+		// If a filter was given, we error if none was found, unless waiting for it to be added
+		if len(identities) == 0 && !watch {
 			return fmt.Errorf("no iamidentitymapping with arn %q found", arn)
 		}
 	}
@@ -98,17 +110,103 @@ func doGetIAMIdentityMapping(cmd *cmdutils.Cmd, params *getCmdParams, arn string
 	if err != nil {
 		return err
 	}
-	if params.output == "table" {
-		addIAMIdentityMappingTableColumns(printer.(*printers.TablePrinter))
+	if table, ok := printer.(*printers.TablePrinter); ok {
+		addIAMIdentityMappingTableColumns(table)
+	}
+	if err := printers.SelectColumns(printer, params.columns); err != nil {
+		return err
 	}
 
-	if err := printer.PrintObjWithKind("iamidentitymappings", identities, os.Stdout); err != nil {
+	err = printers.WriteOutput(params.outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		return printer.PrintObjWithKind("iamidentitymappings", identities, w)
+	})
+	if err != nil {
 		return err
 	}
 
+	if watch {
+		return watchIAMIdentityMappings(acm, params.output, params.columns, arn)
+	}
+
 	return nil
 }
 
+type iamIdentityMappingEvent struct {
+	Event    authconfigmap.IdentityEventType `json:"event"`
+	ARN      string                          `json:"arn"`
+	Username string                          `json:"username,omitempty"`
+	Groups   []string                        `json:"groups,omitempty"`
+}
+
+// watchIAMIdentityMappings prints the changes made to the mappings until the command is interrupted
+func watchIAMIdentityMappings(acm *authconfigmap.AuthConfigMap, output printers.Type, columns []string, arn string) error {
+	events, err := acm.Watch(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+	table, isTable := printer.(*printers.TablePrinter)
+	if isTable {
+		addIAMIdentityMappingEventTableColumns(table)
+	}
+	if err := printers.SelectColumns(printer, columns); err != nil {
+		return err
+	}
+
+	printHeader := true
+	for event := range events {
+		if arn != "" && event.Identity.ARN() != arn {
+			continue
+		}
+		mappingEvent := []iamIdentityMappingEvent{{
+			Event:    event.Type,
+			ARN:      event.Identity.ARN(),
+			Username: event.Identity.Username(),
+			Groups:   event.Identity.Groups(),
+		}}
+		if !isTable {
+			if err := printer.PrintObj(mappingEvent, os.Stdout); err != nil {
+				return err
+			}
+			continue
+		}
+		// only print the header of the table once, as kubectl does when watching
+		buf := &bytes.Buffer{}
+		if err := printer.PrintObj(mappingEvent, buf); err != nil {
+			return err
+		}
+		if !printHeader {
+			if _, err := buf.ReadString('\n'); err != nil {
+				return err
+			}
+		}
+		printHeader = false
+		if _, err := buf.WriteTo(os.Stdout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addIAMIdentityMappingEventTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("EVENT", func(r iamIdentityMappingEvent) string {
+		return string(r.Event)
+	})
+	printer.AddColumn("ARN", func(r iamIdentityMappingEvent) string {
+		return r.ARN
+	})
+	printer.AddColumn("USERNAME", func(r iamIdentityMappingEvent) string {
+		return r.Username
+	})
+	printer.AddColumn("GROUPS", func(r iamIdentityMappingEvent) string {
+		return strings.Join(r.Groups, ",")
+	})
+}
+
 func addIAMIdentityMappingTableColumns(printer *printers.TablePrinter) {
 	printer.AddColumn("ARN", func(r iam.Identity) string {
 		return r.ARN()
@@ -119,4 +217,7 @@ func addIAMIdentityMappingTableColumns(printer *printers.TablePrinter) {
 	printer.AddColumn("GROUPS", func(r iam.Identity) string {
 		return strings.Join(r.Groups(), ",")
 	})
+	printer.AddWideColumn("TYPE", func(r iam.Identity) string {
+		return r.Type()
+	})
 }
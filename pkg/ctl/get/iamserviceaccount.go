@@ -2,7 +2,7 @@ package get
 
 import (
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -39,9 +39,9 @@ func getIAMServiceAccountCmd(cmd *cmdutils.Cmd) {
 		fs.StringVar(&serviceAccount.Namespace, "namespace", "default", "namespace where to delete the iamserviceaccount")
 
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 
-		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output)
+		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output, &params.outputPath)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
@@ -123,7 +123,9 @@ func doGetIAMServiceAccount(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMSer
 	} else {
 		obj = cfg
 	}
-	return printer.PrintObjWithKind("iamserviceaccounts", obj, os.Stdout)
+	return printers.WriteOutput(params.outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		return printer.PrintObjWithKind("iamserviceaccounts", obj, w)
+	})
 }
 
 func addIAMServiceAccountSummaryTableColumns(printer *printers.TablePrinter) {
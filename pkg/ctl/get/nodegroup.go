@@ -1,8 +1,10 @@
 package get
 
 import (
-	"os"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,9 +14,13 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
 	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
+// launchTemplateOutput prints the launch template data and the Auto Scaling group settings of a nodegroup
+const launchTemplateOutput = "launchtemplate"
+
 func getNodeGroupCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	ng := api.NewNodeGroup()
@@ -33,7 +39,9 @@ func getNodeGroupCmd(cmd *cmdutils.Cmd) {
 		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
 		fs.StringVarP(&ng.Name, "name", "n", "", "Name of the nodegroup")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output)
+		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output, &params.outputPath)
+		cmdutils.AddColumnsFlag(fs, &params.columns)
+		fs.Lookup("output").Usage = "specifies the output format (valid option: table, wide, json, yaml, launchtemplate, jsonpath=<template>, go-template=<template>)"
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
@@ -71,25 +79,44 @@ func doGetNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *getCmdParams)
 	}
 
 	manager := ctl.NewStackManager(cfg)
+
+	if params.output == launchTemplateOutput {
+		if ng.Name == "" {
+			return fmt.Errorf("--output=%s requires a nodegroup name", launchTemplateOutput)
+		}
+		config, err := nodegroup.GetLaunchConfiguration(ctl.Provider, manager, ng.Name)
+		if err != nil {
+			return err
+		}
+		return printers.WriteOutput(params.outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+			return printers.NewJSONPrinter().PrintObj(config, w)
+		})
+	}
+
 	summaries, err := manager.GetNodeGroupSummaries(ng.Name)
 	if err != nil {
 		return errors.Wrap(err, "getting nodegroup stack summaries")
 	}
 
+	if err := nodegroup.GetStatus(ctl.Provider, manager, cfg.Metadata.Name, summaries); err != nil {
+		return err
+	}
+
 	printer, err := printers.NewPrinter(params.output)
 	if err != nil {
 		return err
 	}
 
-	if params.output == "table" {
-		addSummaryTableColumns(printer.(*printers.TablePrinter))
+	if table, ok := printer.(*printers.TablePrinter); ok {
+		addSummaryTableColumns(table)
 	}
-
-	if err := printer.PrintObjWithKind("nodegroups", summaries, os.Stdout); err != nil {
+	if err := printers.SelectColumns(printer, params.columns); err != nil {
 		return err
 	}
 
-	return nil
+	return printers.WriteOutput(params.outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		return printer.PrintObjWithKind("nodegroups", summaries, w)
+	})
 }
 
 func addSummaryTableColumns(printer *printers.TablePrinter) {
@@ -102,14 +129,20 @@ func addSummaryTableColumns(printer *printers.TablePrinter) {
 	printer.AddColumn("CREATED", func(s *manager.NodeGroupSummary) string {
 		return s.CreationTime.Format(time.RFC3339)
 	})
+	printer.AddColumn("STATUS", func(s *manager.NodeGroupSummary) string {
+		return statusOf(s).Status
+	})
 	printer.AddColumn("MIN SIZE", func(s *manager.NodeGroupSummary) string {
-		return strconv.Itoa(s.MinSize)
+		return strconv.Itoa(statusOf(s).MinSize)
 	})
 	printer.AddColumn("MAX SIZE", func(s *manager.NodeGroupSummary) string {
-		return strconv.Itoa(s.MaxSize)
+		return strconv.Itoa(statusOf(s).MaxSize)
 	})
 	printer.AddColumn("DESIRED CAPACITY", func(s *manager.NodeGroupSummary) string {
-		return strconv.Itoa(s.DesiredCapacity)
+		return strconv.Itoa(statusOf(s).DesiredCapacity)
+	})
+	printer.AddColumn("NODES", func(s *manager.NodeGroupSummary) string {
+		return strconv.Itoa(statusOf(s).Nodes)
 	})
 	printer.AddColumn("INSTANCE TYPE", func(s *manager.NodeGroupSummary) string {
 		return s.InstanceType
@@ -117,4 +150,39 @@ func addSummaryTableColumns(printer *printers.TablePrinter) {
 	printer.AddColumn("IMAGE ID", func(s *manager.NodeGroupSummary) string {
 		return s.ImageID
 	})
+	printer.AddColumn("RELEASE VERSION", func(s *manager.NodeGroupSummary) string {
+		return statusOf(s).ReleaseVersion
+	})
+	printer.AddColumn("HEALTH", func(s *manager.NodeGroupSummary) string {
+		if issues := statusOf(s).HealthIssues; len(issues) > 0 {
+			return strings.Join(issues, ", ")
+		}
+		return "OK"
+	})
+	printer.AddColumn("UPDATE", func(s *manager.NodeGroupSummary) string {
+		return statusOf(s).Update
+	})
+	printer.AddWideColumn("TYPE", func(s *manager.NodeGroupSummary) string {
+		return string(s.Type)
+	})
+	printer.AddWideColumn("STACK", func(s *manager.NodeGroupSummary) string {
+		return s.StackName
+	})
+	printer.AddWideColumn("NODE INSTANCE ROLE ARN", func(s *manager.NodeGroupSummary) string {
+		return s.NodeInstanceRoleARN
+	})
+}
+
+// statusOf returns the live state of a nodegroup, falling back to the sizes in its stack
+// when the state couldn't be read
+func statusOf(s *manager.NodeGroupSummary) *manager.NodeGroupStatus {
+	if s.Status != nil {
+		return s.Status
+	}
+	return &manager.NodeGroupStatus{
+		MinSize:         s.MinSize,
+		MaxSize:         s.MaxSize,
+		DesiredCapacity: s.DesiredCapacity,
+		Update:          s.StackStatus,
+	}
 }
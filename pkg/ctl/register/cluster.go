@@ -0,0 +1,108 @@
+package register
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+func registerClusterCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("cluster", "Register an existing EKS cluster that was not created by eksctl",
+		"Creates a cluster stack describing an existing EKS cluster, so that eksctl can manage its nodegroups, and prints the resulting ClusterConfig")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doRegisterCluster(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVarP(&cfg.Metadata.Name, "name", "n", "", "EKS cluster name")
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doRegisterCluster(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+	if stack, err := stackManager.DescribeClusterStack(); err == nil {
+		return fmt.Errorf("cluster %q is already managed by eksctl (stack %q)", meta.Name, *stack.StackName)
+	}
+
+	cluster, err := ctl.DescribeControlPlane(meta)
+	if err != nil {
+		return err
+	}
+	if *cluster.Status != awseks.ClusterStatusActive {
+		return fmt.Errorf("cluster %q must be %s, but it is %s", meta.Name, awseks.ClusterStatusActive, *cluster.Status)
+	}
+	meta.Version = *cluster.Version
+
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+	if err := vpc.UseFromUnownedCluster(ctl.NewVPCLookupProvider(cfg), cluster, cfg); err != nil {
+		return errors.Wrapf(err, "importing VPC configuration of cluster %q", meta.Name)
+	}
+
+	cmdutils.LogIntendedAction(cmd.Plan, "register cluster %q in %q with eksctl", meta.Name, meta.Region)
+	if !cmd.Plan {
+		if _, err := ctl.Provider.EKS().TagResource(&awseks.TagResourceInput{
+			ResourceArn: cluster.Arn,
+			Tags: aws.StringMap(map[string]string{
+				api.ClusterNameTag:       meta.Name,
+				api.ClusterRegisteredTag: "true",
+			}),
+		}); err != nil {
+			return errors.Wrapf(err, "tagging cluster %q", meta.Name)
+		}
+
+		errs := make(chan error)
+		if err := stackManager.RegisterCluster(errs); err != nil {
+			return err
+		}
+		if err := <-errs; err != nil {
+			return errors.Wrapf(err, "creating registration stack for cluster %q", meta.Name)
+		}
+		logger.Success("registered cluster %q in %q", meta.Name, meta.Region)
+	}
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	generated := cfg.DeepCopy()
+	generated.Status = nil
+	return printers.NewYAMLPrinter().PrintObj(generated, os.Stdout)
+}
@@ -0,0 +1,15 @@
+package register
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+// Command creates the `register` commands
+func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
+	verbCmd := cmdutils.NewVerbCmd("register", "Register resources created outside of eksctl", "")
+
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, registerClusterCmd)
+
+	return verbCmd
+}
@@ -34,6 +34,8 @@ func scaleNodeGroupCmd(cmd *cmdutils.Cmd) {
 		})
 
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
@@ -41,24 +43,13 @@ func scaleNodeGroupCmd(cmd *cmdutils.Cmd) {
 }
 
 func doScaleNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup) error {
-	cfg := cmd.ClusterConfig
-
-	// TODO: move this into a loader when --config-file gets added to this command
-	if cfg.Metadata.Name == "" {
-		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
-	}
+	ngFilter := cmdutils.NewNodeGroupFilter()
 
-	if ng.Name != "" && cmd.NameArg != "" {
-		return cmdutils.ErrFlagAndArg("--name", ng.Name, cmd.NameArg)
-	}
-
-	if cmd.NameArg != "" {
-		ng.Name = cmd.NameArg
+	if err := cmdutils.NewScaleNodeGroupLoader(cmd, ng, ngFilter).Load(); err != nil {
+		return err
 	}
 
-	if ng.Name == "" {
-		return cmdutils.ErrMustBeSet("--name")
-	}
+	cfg := cmd.ClusterConfig
 
 	ctl, err := cmd.NewCtl()
 	if err != nil {
@@ -69,14 +60,26 @@ func doScaleNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup) error {
 		return err
 	}
 
-	if ng.DesiredCapacity == nil || *ng.DesiredCapacity < 0 {
-		return fmt.Errorf("number of nodes must be 0 or greater. Use the --nodes/-N flag")
-	}
+	logFiltered := cmdutils.ApplyFilter(cfg, ngFilter)
+	logFiltered()
 
 	stackManager := ctl.NewStackManager(cfg)
-	err = stackManager.ScaleNodeGroup(ng)
-	if err != nil {
-		return fmt.Errorf("failed to scale nodegroup for cluster %q, error %v", cfg.Metadata.Name, err)
+
+	nodeGroups := cfg.NodeGroups
+	// managed nodegroups are scaled through their stacks the same way
+	for _, mng := range cfg.ManagedNodeGroups {
+		nodeGroups = append(nodeGroups, &api.NodeGroup{
+			Name:            mng.Name,
+			DesiredCapacity: mng.DesiredCapacity,
+			MinSize:         mng.MinSize,
+			MaxSize:         mng.MaxSize,
+		})
+	}
+
+	for _, ng := range nodeGroups {
+		if err := stackManager.ScaleNodeGroup(ng); err != nil {
+			return fmt.Errorf("failed to scale nodegroup %q for cluster %q, error %v", ng.Name, cfg.Metadata.Name, err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,326 @@
+package upgrade
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/managed"
+)
+
+func upgradeClusterCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	// the version to upgrade to must be given
+	cfg.Metadata.Version = ""
+	cmd.ClusterConfig = cfg
+
+	var fixAddons bool
+
+	cmd.SetDescription("cluster", "Upgrade a cluster to a Kubernetes version",
+		"Upgrade the control plane, the default add-ons and the managed nodegroups of a cluster one minor version at a time, "+
+			"until they reach the given version. Self-managed nodegroups are not upgraded, the upgrade stops before the version "+
+			"of their nodes would be too old for the control plane. The upgrade is refused when the installed default add-ons "+
+			"don't support the next version, unless --fix is used to upgrade them first.")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doUpgradeCluster(cmd, fixAddons)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVarP(&cfg.Metadata.Name, "name", "n", "", "EKS cluster name")
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddVersionFlag(fs, cfg.Metadata, `version to upgrade to, "latest" can be used to upgrade to the latest version`)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		cmdutils.AddApproveFlag(fs, cmd)
+		fs.BoolVar(&fixAddons, "fix", false, "upgrade the default add-ons that don't support the next version before upgrading the control plane")
+
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, cmd, api.TimeoutPhaseControlPlane, api.TimeoutPhaseNodeGroup)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doUpgradeCluster(cmd *cmdutils.Cmd, fixAddons bool) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	targetVersion := meta.Version
+	switch targetVersion {
+	case "":
+		return cmdutils.ErrMustBeSet("--version")
+	case "latest":
+		targetVersion = api.LatestVersion
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanUpdate(cfg); !ok {
+		return err
+	}
+
+	currentVersion := ctl.ControlPlaneVersion()
+	if currentVersion == "" {
+		return errors.New("unable to get control plane version")
+	}
+
+	versions, err := upgradePath(currentVersion, targetVersion)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		logger.Info("cluster %q is already at version %s", meta.Name, currentVersion)
+		return nil
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+	managedNodeGroups, err := listManagedNodeGroups(stackManager)
+	if err != nil {
+		return err
+	}
+
+	cmdutils.LogIntendedAction(cmd.Plan, "upgrade cluster %q from version %s to %s in %d step(s): %s", meta.Name, currentVersion, targetVersion,
+		len(versions), strings.Join(versions, " -> "))
+	for _, version := range versions {
+		cmdutils.LogIntendedAction(cmd.Plan, "upgrade the control plane to %s, then the default add-ons and %d managed nodegroup(s) (%s)",
+			version, len(managedNodeGroups), strings.Join(managedNodeGroups, ", "))
+	}
+
+	// the nodes are checked against each version before the control plane is upgraded to it, as the
+	// managed nodegroups are upgraded along the way; in plan mode only the first version can be checked
+	if err := checkNodeVersionSkew(clientSet, versions[0]); err != nil {
+		return err
+	}
+
+	incompatibilities, err := defaultaddons.CheckCompatibility(clientSet, versions[0])
+	if err != nil {
+		return errors.Wrap(err, "checking the compatibility of the default add-ons")
+	}
+	if len(incompatibilities) > 0 && !fixAddons {
+		if cmd.Plan {
+			logger.Warning("the default add-ons are too old for version %s: %s; use --fix to upgrade them first", versions[0], describeIncompatibilities(incompatibilities))
+		} else {
+			return fmt.Errorf("the default add-ons are too old for version %s: %s; use --fix to upgrade them first", versions[0], describeIncompatibilities(incompatibilities))
+		}
+	}
+
+	if cmd.Plan {
+		cmdutils.LogPlanModeWarning(true)
+		return nil
+	}
+
+	managedService := managed.NewService(ctl.Provider, stackManager, meta.Name)
+
+	for i, version := range versions {
+		if i > 0 {
+			if err := checkNodeVersionSkew(clientSet, version); err != nil {
+				return err
+			}
+			// the default add-ons are upgraded at each step, they're brought up to the next version
+			// as part of the upgrade even without --fix
+			if incompatibilities, err = defaultaddons.CheckCompatibility(clientSet, version); err != nil {
+				return errors.Wrap(err, "checking the compatibility of the default add-ons")
+			}
+		}
+		if len(incompatibilities) > 0 {
+			if err := fixIncompatibleAddons(ctl, cfg, incompatibilities); err != nil {
+				return errors.Wrapf(err, "upgrading the default add-ons for version %s", version)
+			}
+		}
+
+		meta.Version = version
+		logger.Info("upgrading the control plane of cluster %q to version %s", meta.Name, version)
+		if err := ctl.UpdateClusterVersionBlocking(cfg); err != nil {
+			return errors.Wrapf(err, "upgrading the control plane to version %s", version)
+		}
+
+		if err := upgradeAddons(ctl, cfg); err != nil {
+			return errors.Wrapf(err, "upgrading the default add-ons for version %s", version)
+		}
+
+		for _, ng := range managedNodeGroups {
+			logger.Info("upgrading managed nodegroup %q to version %s", ng, version)
+			if err := managedService.UpgradeNodeGroup(ng, version, ""); err != nil {
+				return errors.Wrapf(err, "upgrading managed nodegroup %q to version %s", ng, version)
+			}
+		}
+
+		logger.Success("checkpoint: the control plane, the default add-ons and the managed nodegroups of cluster %q are at version %s; "+
+			"if the upgrade is interrupted, running the same command resumes it from this version", meta.Name, version)
+	}
+
+	logger.Info("self-managed nodegroups are not upgraded, replace them or upgrade them with 'eksctl upgrade nodegroup --release-version'")
+	return nil
+}
+
+// upgradePath returns the minor versions a control plane at currentVersion goes through to reach
+// targetVersion, as EKS only upgrades the control plane one minor version at a time
+func upgradePath(currentVersion, targetVersion string) ([]string, error) {
+	supported := api.SupportedVersions()
+	indexOf := func(version string) (int, error) {
+		for i, v := range supported {
+			if v == version {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("Kubernetes version %s is not known to this version of eksctl, supported versions are: %s",
+			version, strings.Join(supported, ", "))
+	}
+
+	from, err := indexOf(currentVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := indexOf(targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	if to < from {
+		return nil, fmt.Errorf("the control plane can't be downgraded from version %s to %s", currentVersion, targetVersion)
+	}
+	return supported[from+1 : to+1], nil
+}
+
+func listManagedNodeGroups(stackManager *manager.StackCollection) ([]string, error) {
+	stacks, err := stackManager.ListNodeGroupStacks()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, s := range stacks {
+		if s.Type == api.NodeGroupTypeManaged {
+			names = append(names, s.NodeGroupName)
+		}
+	}
+	return names, nil
+}
+
+// upgradeAddons updates the default add-ons to the versions recommended for the new version of the control plane;
+// aws-node is only updated when the pods use the VPC CNI, as it's deleted or kept off the nodes otherwise
+func upgradeAddons(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) error {
+	rawClient, err := ctl.NewRawClient(cfg)
+	if err != nil {
+		return err
+	}
+	kubernetesVersion, err := rawClient.ServerVersion()
+	if err != nil {
+		return err
+	}
+
+	if _, err := defaultaddons.UpdateKubeProxyImageTag(rawClient.ClientSet(), kubernetesVersion, "", false); err != nil {
+		return err
+	}
+	if !cfg.HasAlternateCNI() {
+		if _, err := defaultaddons.UpdateAWSNode(rawClient, cfg.Metadata.Region, "", false); err != nil {
+			return err
+		}
+	}
+	_, err = defaultaddons.UpdateCoreDNS(rawClient, cfg.Metadata.Region, kubernetesVersion, "", false)
+	return err
+}
+
+// fixIncompatibleAddons upgrades the default add-ons that don't support the version the control plane
+// is about to be upgraded to; CoreDNS is upgraded to the defaults of that version, while kube-proxy is
+// upgraded to the current version of the control plane, as it can't be newer than the control plane
+func fixIncompatibleAddons(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, incompatibilities []defaultaddons.Incompatibility) error {
+	rawClient, err := ctl.NewRawClient(cfg)
+	if err != nil {
+		return err
+	}
+	controlPlaneVersion, err := rawClient.ServerVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, incompatibility := range incompatibilities {
+		logger.Info("upgrading %s before upgrading the control plane to %s", incompatibility, incompatibility.KubernetesVersion)
+		switch incompatibility.Name {
+		case defaultaddons.KubeProxy:
+			_, err = defaultaddons.UpdateKubeProxyImageTag(rawClient.ClientSet(), controlPlaneVersion, "", false)
+		case defaultaddons.CoreDNS:
+			_, err = defaultaddons.UpdateCoreDNS(rawClient, cfg.Metadata.Region, incompatibility.KubernetesVersion+".0", "", false)
+		default:
+			err = fmt.Errorf("%s can't be upgraded automatically", incompatibility.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func describeIncompatibilities(incompatibilities []defaultaddons.Incompatibility) string {
+	var descriptions []string
+	for _, incompatibility := range incompatibilities {
+		descriptions = append(descriptions, incompatibility.String())
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// checkNodeVersionSkew checks that the nodes of the cluster will still be supported once
+// the control plane is upgraded to version
+func checkNodeVersionSkew(clientSet kubeclient.Interface, version string) error {
+	nodes, err := clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing nodes")
+	}
+	return validateNodeVersionSkew(nodes.Items, version)
+}
+
+// validateNodeVersionSkew returns an error naming the nodegroups whose nodes would break the
+// version skew policy with a control plane at version
+func validateNodeVersionSkew(nodes []corev1.Node, version string) error {
+	outdated := map[string]string{}
+	for _, node := range nodes {
+		kubeletVersion := node.Status.NodeInfo.KubeletVersion
+		if err := eks.ValidateKubeletVersionSkew(version, kubeletVersion); err != nil {
+			name, ok := node.Labels[api.NodeGroupNameLabel]
+			if !ok {
+				name = "node " + node.Name
+			}
+			outdated[name] = kubeletVersion
+		}
+	}
+	if len(outdated) == 0 {
+		return nil
+	}
+
+	var descriptions []string
+	for name, kubeletVersion := range outdated {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", name, kubeletVersion))
+	}
+	sort.Strings(descriptions)
+	return fmt.Errorf("the nodes of %s would not be supported by a control plane at version %s, "+
+		"upgrade or replace them before upgrading the cluster further", strings.Join(descriptions, ", "), version)
+}
@@ -0,0 +1,57 @@
+package upgrade
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("upgrade cluster", func() {
+	Describe("upgrade path", func() {
+		It("goes through each minor version", func() {
+			versions, err := upgradePath(api.Version1_12, api.Version1_15)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]string{api.Version1_13, api.Version1_14, api.Version1_15}))
+
+			versions, err = upgradePath(api.Version1_15, api.Version1_15)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+		})
+
+		It("rejects downgrades and unknown versions", func() {
+			_, err := upgradePath(api.Version1_15, api.Version1_14)
+			Expect(err).To(MatchError("the control plane can't be downgraded from version 1.15 to 1.14"))
+
+			_, err = upgradePath(api.Version1_14, "1.99")
+			Expect(err).To(MatchError(ContainSubstring("Kubernetes version 1.99 is not known to this version of eksctl")))
+		})
+	})
+
+	Describe("node version skew", func() {
+		newNode := func(name, nodeGroup, kubeletVersion string) corev1.Node {
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+			if nodeGroup != "" {
+				node.Labels[api.NodeGroupNameLabel] = nodeGroup
+			}
+			node.Status.NodeInfo.KubeletVersion = kubeletVersion
+			return node
+		}
+
+		It("names the nodegroups whose nodes would be too old", func() {
+			nodes := []corev1.Node{
+				newNode("node-1", "ng-1", "v1.12.10-eks-ffbd96"),
+				newNode("node-2", "ng-1", "v1.12.10-eks-ffbd96"),
+				newNode("node-3", "ng-2", "v1.14.9-eks-1f0ca9"),
+				newNode("node-4", "", "v1.12.10-eks-ffbd96"),
+			}
+			Expect(validateNodeVersionSkew(nodes, api.Version1_14)).To(Succeed())
+
+			err := validateNodeVersionSkew(nodes, api.Version1_15)
+			Expect(err).To(MatchError("the nodes of ng-1 (v1.12.10-eks-ffbd96), node node-4 (v1.12.10-eks-ffbd96) would not be supported " +
+				"by a control plane at version 1.15, upgrade or replace them before upgrading the cluster further"))
+		})
+	})
+})
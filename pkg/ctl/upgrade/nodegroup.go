@@ -1,10 +1,14 @@
 package upgrade
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/weaveworks/eksctl/pkg/ami"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/managed"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
@@ -14,13 +18,16 @@ import (
 type upgradeOptions struct {
 	nodeGroupName     string
 	kubernetesVersion string
+	releaseVersion    string
+	force             bool
 }
 
 func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
-	cmd.SetDescription("nodegroup", "Upgrade nodegroup", "")
+	cmd.SetDescription("nodegroup", "Upgrade nodegroup", "Managed nodegroups are upgraded by EKS; "+
+		"self-managed nodegroups are upgraded to an AMI release by replacing their instances one at a time, after draining their nodes")
 
 	var options upgradeOptions
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
@@ -32,10 +39,13 @@ func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
 		fs.StringVarP(&cfg.Metadata.Name, "cluster", "", "", "EKS cluster name")
 		fs.StringVarP(&options.nodeGroupName, "name", "", "", "Nodegroup name")
 		fs.StringVarP(&options.kubernetesVersion, "kubernetes-version", "", "", "Kubernetes version")
+		fs.StringVarP(&options.releaseVersion, "release-version", "", "", "AMI release version, e.g. 1.15.10-20200228, required for self-managed nodegroups")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		cmdutils.AddForceVersionSkewFlag(fs, &options.force)
 
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, cmd, api.TimeoutPhaseNodeGroup, api.TimeoutPhaseDrain)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
@@ -60,13 +70,60 @@ func upgradeNodeGroup(cmd *cmdutils.Cmd, options upgradeOptions) error {
 		return cmdutils.ErrMustBeSet("name")
 	}
 
+	if options.kubernetesVersion != "" && options.releaseVersion != "" {
+		return fmt.Errorf("--kubernetes-version and --release-version cannot be used together")
+	}
+
 	ctl := eks.New(cmd.ProviderConfig, cmd.ClusterConfig)
 
 	if err := ctl.CheckAuth(); err != nil {
 		return err
 	}
 
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+
+	if err := checkVersionSkew(ctl.ControlPlaneVersion(), options); err != nil {
+		return err
+	}
+
 	stackCollection := manager.NewStackCollection(ctl.Provider, cfg)
+	if nodeGroupType, err := stackCollection.GetNodeGroupStackType(options.nodeGroupName); err == nil && nodeGroupType == api.NodeGroupTypeUnmanaged {
+		return upgradeSelfManagedNodeGroup(ctl, cfg, stackCollection, options)
+	}
+
 	managedService := managed.NewService(ctl.Provider, stackCollection, cfg.Metadata.Name)
-	return managedService.UpgradeNodeGroup(options.nodeGroupName, options.kubernetesVersion)
+	return managedService.UpgradeNodeGroup(options.nodeGroupName, options.kubernetesVersion, options.releaseVersion)
+}
+
+func upgradeSelfManagedNodeGroup(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, stackCollection *manager.StackCollection, options upgradeOptions) error {
+	if options.releaseVersion == "" {
+		return fmt.Errorf("--release-version must be set to upgrade self-managed nodegroup %q", options.nodeGroupName)
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	ng := cfg.NewNodeGroup()
+	ng.Name = options.nodeGroupName
+	return nodegroup.UpgradeReleaseVersion(ctl.Provider, stackCollection, clientSet, ng, options.releaseVersion)
+}
+
+// checkVersionSkew validates the skew between the control plane and the Kubernetes version the nodegroup
+// is upgraded to, it's only known when a Kubernetes version or a release version is given
+func checkVersionSkew(controlPlaneVersion string, options upgradeOptions) error {
+	kubeletVersion := options.kubernetesVersion
+	if options.releaseVersion != "" {
+		var err error
+		if kubeletVersion, _, err = ami.ParseReleaseVersion(options.releaseVersion); err != nil {
+			return err
+		}
+	}
+	if kubeletVersion == "" {
+		return nil
+	}
+	return cmdutils.CheckKubeletVersionSkew(controlPlaneVersion, kubeletVersion, options.force)
 }
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+// addonUpdateOptions are the options of the commands updating a default addon
+type addonUpdateOptions struct {
+	// imageTag pins the image of the addon, instead of using the version recommended for the cluster
+	imageTag string
+	dryRun   bool
+	rollback bool
+}
+
+func addAddonUpdateFlags(fs *pflag.FlagSet, options *addonUpdateOptions) {
+	fs.StringVar(&options.imageTag, "version", "", "image tag to update the add-on to, instead of the recommended version (e.g. v1.6.3)")
+	fs.BoolVar(&options.dryRun, "dry-run", false, "print the changes to the add-on without applying them, even with --approve")
+	fs.BoolVar(&options.rollback, "rollback", false, "set the image of the add-on back to the one it had before its last update by eksctl")
+}
+
+func (o addonUpdateOptions) validate() error {
+	if o.rollback && o.imageTag != "" {
+		return fmt.Errorf("--rollback and --version cannot be used together")
+	}
+	return nil
+}
+
+// updateAddon updates a default addon with the given function, or rolls it back,
+// and logs a warning in plan mode
+func updateAddon(cmd *cmdutils.Cmd, rawClient kubernetes.RawClientInterface, name string, options addonUpdateOptions, update func(plan bool) (bool, error)) error {
+	if options.dryRun {
+		cmd.Plan = true
+	}
+
+	var (
+		updateRequired bool
+		err            error
+	)
+	if options.rollback {
+		updateRequired, err = defaultaddons.RollbackAddon(rawClient.ClientSet(), name, cmd.Plan)
+	} else {
+		updateRequired, err = update(cmd.Plan)
+	}
+	if err != nil {
+		return err
+	}
+
+	cmdutils.LogPlanModeWarning(cmd.Plan && updateRequired)
+	return nil
+}
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/deprecatedapis"
+	"github.com/weaveworks/eksctl/pkg/health"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+type checkDeprecatedAPIsOptions struct {
+	forVersion  string
+	withMetrics bool
+	output      printers.Type
+	outputPath  string
+}
+
+func checkDeprecatedAPIsCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var options checkDeprecatedAPIsOptions
+
+	cmd.SetDescription("check-deprecated-apis", "Find the objects of a cluster using APIs removed in a Kubernetes version",
+		"Reports the objects last applied by kubectl with a version of an API that the given Kubernetes version no longer serves, "+
+			"and optionally the removed APIs that the API server received requests for, so that they can be migrated before upgrading")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doCheckDeprecatedAPIs(cmd, options)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.StringVar(&options.forVersion, "for-version", "", "Kubernetes version to check the APIs against, defaults to the version after the one of the control plane")
+		fs.BoolVar(&options.withMetrics, "metrics", false, "also report the removed APIs counted by the apiserver_requested_deprecated_apis metric of the API server (Kubernetes 1.19 and above)")
+		fs.StringVarP(&options.output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml, jsonpath=<template>, go-template=<template>)")
+		fs.StringVar(&options.outputPath, "output-path", "", "write the output to a local file or an S3 object (s3://bucket/key) instead of stdout")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doCheckDeprecatedAPIs(cmd *cmdutils.Cmd, options checkDeprecatedAPIsOptions) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	forVersion := options.forVersion
+	if forVersion == "" {
+		if forVersion, err = nextMinorVersion(ctl.ControlPlaneVersion()); err != nil {
+			return err
+		}
+	}
+
+	apis, err := deprecatedapis.RemovedBy(forVersion)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := ctl.NewStdDynamicClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("checking the objects of cluster %q for APIs removed in Kubernetes %s or before", cfg.Metadata.Name, forVersion)
+	findings, err := deprecatedapis.ScanObjects(deprecatedapis.NewResourceLister(dynamicClient), apis)
+	if err != nil {
+		return err
+	}
+
+	if options.withMetrics {
+		clientSet, err := ctl.NewStdClientSet(cfg)
+		if err != nil {
+			return err
+		}
+		metrics, err := health.NewMetricsGetter(clientSet)()
+		if err != nil {
+			return errors.Wrap(err, "reading the metrics of the API server")
+		}
+		requested, err := deprecatedapis.ScanMetrics(string(metrics), forVersion)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, requested...)
+	}
+
+	printer, err := printers.NewPrinter(options.output)
+	if err != nil {
+		return err
+	}
+
+	if err := printers.WriteOutput(options.outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		if options.output == "table" {
+			addDeprecatedAPIsTableColumns(printer.(*printers.TablePrinter))
+		}
+		return printer.PrintObjWithKind("uses of removed APIs", findings, w)
+	}); err != nil {
+		return err
+	}
+
+	if len(findings) == 0 {
+		logger.Success("no uses of APIs removed in Kubernetes %s found in cluster %q", forVersion, cfg.Metadata.Name)
+	} else {
+		logger.Warning("found %d use(s) of APIs removed in Kubernetes %s in cluster %q, migrate them before upgrading", len(findings), forVersion, cfg.Metadata.Name)
+	}
+	return nil
+}
+
+// nextMinorVersion returns the minor version after version, e.g. 1.16 for 1.15
+func nextMinorVersion(version string) (string, error) {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing control plane version %q", version)
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor+1), nil
+}
+
+func addDeprecatedAPIsTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("API", func(f deprecatedapis.Finding) string {
+		return f.API.Describe()
+	})
+	printer.AddColumn("NAMESPACE", func(f deprecatedapis.Finding) string {
+		return f.Namespace
+	})
+	printer.AddColumn("NAME", func(f deprecatedapis.Finding) string {
+		return f.Name
+	})
+	printer.AddColumn("REMOVED IN", func(f deprecatedapis.Finding) string {
+		return f.API.RemovedIn
+	})
+	printer.AddColumn("REPLACEMENT", func(f deprecatedapis.Finding) string {
+		return f.API.Replacement
+	})
+	printer.AddColumn("SOURCE", func(f deprecatedapis.Finding) string {
+		return f.Source
+	})
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
+)
+
+const defaultKeptLaunchTemplateVersions = 5
+
+func cleanupLaunchTemplatesCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		nodeGroupName string
+		keep          int
+	)
+
+	cmd.SetDescription("cleanup-launch-templates", "Delete the launch template versions superseded by nodegroup updates",
+		"Every update of a nodegroup creates a new version of its launch template, the versions before the last ones and "+
+			"the default one are deleted. Only the launch templates created by eksctl are cleaned up")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doCleanupLaunchTemplates(cmd, nodeGroupName, keep)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
+		fs.StringVarP(&nodeGroupName, "name", "n", "", "Name of the nodegroup, all nodegroups of the cluster are cleaned up if not set")
+		fs.IntVar(&keep, "keep", defaultKeptLaunchTemplateVersions, "Number of latest versions to keep")
+
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddApproveFlag(fs, cmd)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doCleanupLaunchTemplates(cmd *cmdutils.Cmd, nodeGroupName string, keep int) error {
+	cfg := cmd.ClusterConfig
+
+	if cfg.Metadata.Name == "" {
+		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	}
+
+	if nodeGroupName != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg("--name", nodeGroupName, cmd.NameArg)
+	}
+
+	if cmd.NameArg != "" {
+		nodeGroupName = cmd.NameArg
+	}
+
+	if keep < 1 {
+		return fmt.Errorf("--keep must be at least 1")
+	}
+
+	ctl := eks.New(cmd.ProviderConfig, cmd.ClusterConfig)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	stackManager := manager.NewStackCollection(ctl.Provider, cfg)
+
+	nodeGroupNames := []string{nodeGroupName}
+	if nodeGroupName == "" {
+		nodeGroupStacks, err := stackManager.ListNodeGroupStacks()
+		if err != nil {
+			return err
+		}
+		nodeGroupNames = nil
+		for _, stack := range nodeGroupStacks {
+			nodeGroupNames = append(nodeGroupNames, stack.NodeGroupName)
+		}
+	}
+
+	pending := false
+	for _, name := range nodeGroupNames {
+		launchTemplateID, versions, err := nodegroup.SupersededLaunchTemplateVersions(ctl.Provider, stackManager, name, keep)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			logger.Info("no superseded launch template versions for nodegroup %q", name)
+			continue
+		}
+
+		pending = true
+		cmdutils.LogIntendedAction(cmd.Plan, "delete %d versions of launch template %q of nodegroup %q", len(versions), launchTemplateID, name)
+		if cmd.Plan {
+			continue
+		}
+		if err := nodegroup.DeleteLaunchTemplateVersions(ctl.Provider, launchTemplateID, versions); err != nil {
+			return err
+		}
+		logger.Success("deleted %d versions of launch template %q of nodegroup %q", len(versions), launchTemplateID, name)
+	}
+
+	cmdutils.LogPlanModeWarning(cmd.Plan && pending)
+	return nil
+}
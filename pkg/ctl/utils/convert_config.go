@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func convertConfigCmd(cmd *cmdutils.Cmd) {
+	cmd.SetDescription("convert-config", "Convert a config file to the current version",
+		"Rewrites a config file of a previous version, or using deprecated fields, for the current version and prints it; "+
+			"each document is converted on its own, variables, includes and nodegroup templates are kept as they are, "+
+			"comments aren't kept and fields are sorted")
+
+	var outputPath string
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doConvertConfig(cmd, outputPath)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVarP(&cmd.ClusterConfigFile, "config-file", "f", "", "config file to convert (or stdin if set to '-')")
+		fs.StringVar(&outputPath, "output-path", "", "write the converted config file to a local file, which can be the config file itself, instead of stdout")
+	})
+}
+
+func doConvertConfig(cmd *cmdutils.Cmd, outputPath string) error {
+	if cmd.ClusterConfigFile == "" {
+		return cmdutils.ErrMustBeSet("--config-file")
+	}
+
+	converted, changes, err := eks.ConvertConfigFile(cmd.ClusterConfigFile)
+	if err != nil {
+		return err
+	}
+	if outputPath == "" {
+		_, err = os.Stdout.Write(converted)
+		return err
+	}
+
+	for _, change := range changes {
+		logger.Info("%s", change)
+	}
+	if err := ioutil.WriteFile(outputPath, converted, 0644); err != nil {
+		return errors.Wrapf(err, "writing %q", outputPath)
+	}
+	logger.Success("wrote converted config file to %q", outputPath)
+	return nil
+}
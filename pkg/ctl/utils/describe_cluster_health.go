@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/health"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+func describeClusterHealthCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output printers.Type
+	var outputPath string
+
+	cmd.SetDescription("describe-cluster-health", "Report the health issues of a cluster",
+		"Aggregates the status of the cluster, the health of its nodegroups, the readiness of its default addons, "+
+			"the requests throttled by its API server and misconfigured entries of its auth ConfigMap into a single report")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doDescribeClusterHealth(cmd, output, outputPath)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml, jsonpath=<template>, go-template=<template>)")
+		fs.StringVar(&outputPath, "output-path", "", "write the output to a local file or an S3 object (s3://bucket/key) instead of stdout")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doDescribeClusterHealth(cmd *cmdutils.Cmd, output printers.Type, outputPath string) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	checker := health.NewChecker(ctl.Provider, ctl.NewStackManager(cfg), clientSet, health.NewMetricsGetter(clientSet), cfg)
+	report, err := checker.Check()
+	if err != nil {
+		return err
+	}
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	if err := printers.WriteOutput(outputPath, ctl.NewS3Uploader, func(w io.Writer) error {
+		if output != "table" {
+			return printer.PrintObjWithKind("report", report, w)
+		}
+		addClusterHealthTableColumns(printer.(*printers.TablePrinter))
+		return printer.PrintObjWithKind("issues", report.Issues, w)
+	}); err != nil {
+		return err
+	}
+
+	if report.Healthy() {
+		logger.Success("no health issues found in cluster %q", cfg.Metadata.Name)
+	} else {
+		logger.Warning("found %d health issue(s) in cluster %q", len(report.Issues), cfg.Metadata.Name)
+	}
+	return nil
+}
+
+func addClusterHealthTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("COMPONENT", func(i health.Issue) string {
+		return i.Component
+	})
+	printer.AddColumn("NAME", func(i health.Issue) string {
+		return i.Name
+	})
+	printer.AddColumn("ISSUE", func(i health.Issue) string {
+		return i.Message
+	})
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func enableSecretsEncryptionCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		keyARN                 string
+		encryptExistingSecrets bool
+	)
+
+	cmd.SetDescription("enable-secrets-encryption", "Enable the encryption of Kubernetes secrets with a KMS key",
+		"Secrets written after the encryption is enabled are encrypted, existing secrets are only encrypted when they're "+
+			"written again, see --encrypt-existing-secrets. The encryption can't be disabled once it's enabled")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doEnableSecretsEncryption(cmd, keyARN, encryptExistingSecrets)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.StringVar(&keyARN, "key-arn", "", "ARN of the KMS key to encrypt secrets with")
+		fs.BoolVar(&encryptExistingSecrets, "encrypt-existing-secrets", true, "write all existing secrets again so that they're encrypted")
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doEnableSecretsEncryption(cmd *cmdutils.Cmd, keyARN string, encryptExistingSecrets bool) error {
+	if err := cmdutils.NewUtilsEnableSecretsEncryptionLoader(cmd, keyARN).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	keyARN = *cfg.SecretsEncryption.KeyARN
+	if cmd.Plan {
+		logger.Info("(plan) would enable the encryption of secrets of cluster %q with KMS key %q", meta.Name, keyARN)
+		if encryptExistingSecrets {
+			logger.Info("(plan) would encrypt the existing secrets of cluster %q", meta.Name)
+		}
+		cmdutils.LogPlanModeWarning(true)
+		return nil
+	}
+
+	if err := ctl.EnableSecretsEncryption(cfg); err != nil {
+		return err
+	}
+	logger.Success("enabled the encryption of secrets of cluster %q with KMS key %q", meta.Name, keyARN)
+
+	if !encryptExistingSecrets {
+		logger.Info("existing secrets are only encrypted when they're written again, e.g. with --encrypt-existing-secrets")
+		return nil
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+	return eks.ReencryptSecrets(clientSet)
+}
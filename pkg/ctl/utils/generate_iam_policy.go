@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/iam"
+)
+
+func generateIAMPolicyCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("generate-iam-policy", "Generate the minimal IAM policy needed to create and delete a cluster",
+		"Prints the IAM policy the caller of eksctl needs to create and delete the cluster described by the config file, "+
+			"with only the actions of the features the config uses, so that e.g. CI systems can be granted least privilege")
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doGenerateIAMPolicy(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+	})
+}
+
+func doGenerateIAMPolicy(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewValidateLoader(cmd).Load(); err != nil {
+		return err
+	}
+	if err := cmd.SetDefaultsAndValidate(); err != nil {
+		return err
+	}
+
+	policy, err := json.MarshalIndent(iam.MinimalPolicyDocument(cmd.ClusterConfig), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(policy))
+	return nil
+}
@@ -1,39 +1,43 @@
 package utils
 
 import (
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"github.com/weaveworks/eksctl/pkg/addons"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/windows"
 )
 
 func installWindowsVPCController(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
-	cmd.SetDescription("install-vpc-controllers", "Install Windows VPC controller to support running Windows workloads", "")
+	var rotateCertificate bool
+
+	cmd.SetDescription("install-vpc-controllers", "Install or upgrade Windows VPC controller to support running Windows workloads",
+		"The certificate of the VPC admission webhook is rotated when it expires within 30 days, or when --rotate-certificate is set")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doInstallWindowsVPCController(cmd)
+		return doInstallWindowsVPCController(cmd, rotateCertificate)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddApproveFlag(fs, cmd)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		fs.BoolVar(&rotateCertificate, "rotate-certificate", false, "Issue a new certificate for the VPC admission webhook even if the current one isn't about to expire")
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doInstallWindowsVPCController(cmd *cmdutils.Cmd) error {
+func doInstallWindowsVPCController(cmd *cmdutils.Cmd, rotateCertificate bool) error {
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -61,9 +65,13 @@ func doInstallWindowsVPCController(cmd *cmdutils.Cmd) error {
 	}
 
 	// TODO cmd.Plan doesn't work as intended for all addons
-	vpcController := addons.NewVPCController(rawClient, cfg.Status, ctl.Provider.Region(), cmd.Plan)
+	vpcController := windows.NewVPCController(rawClient, cfg.Status, ctl.Provider.Region(), cmd.Plan)
 
-	if err := vpcController.Deploy(); err != nil {
+	deploy := vpcController.Deploy
+	if rotateCertificate {
+		deploy = vpcController.RotateCertificate
+	}
+	if err := deploy(); err != nil {
 		return errors.Wrap(err, "error installing VPC controller")
 	}
 
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/printers"
+	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+)
+
+func listKubeconfigContextsCmd(cmd *cmdutils.Cmd) {
+	cmd.ClusterConfig = api.NewClusterConfig()
+
+	var (
+		path   string
+		output printers.Type
+	)
+
+	cmd.SetDescription("list-kubeconfig-contexts", "List the contexts of a kubeconfig file",
+		"Lists the contexts of a kubeconfig file, marking the current one and the ones of clusters written by eksctl")
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doListKubeconfigContexts(path, output)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVar(&path, "kubeconfig", kubeconfig.DefaultPath, "path of the kubeconfig file")
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml, jsonpath=<template>, go-template=<template>)")
+	})
+}
+
+func doListKubeconfigContexts(path string, output printers.Type) error {
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	contexts, err := kubeconfig.ListContexts(path)
+	if err != nil {
+		return err
+	}
+
+	if output == printers.TableType {
+		addKubeconfigContextColumns(printer.(*printers.TablePrinter))
+	}
+	return printer.PrintObjWithKind("contexts", contexts, os.Stdout)
+}
+
+func addKubeconfigContextColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("CURRENT", func(c kubeconfig.ContextInfo) string {
+		if c.Current {
+			return "*"
+		}
+		return ""
+	})
+	printer.AddColumn("NAME", func(c kubeconfig.ContextInfo) string {
+		return c.Name
+	})
+	printer.AddColumn("CLUSTER", func(c kubeconfig.ContextInfo) string {
+		return c.Cluster
+	})
+	printer.AddColumn("USER", func(c kubeconfig.ContextInfo) string {
+		return c.User
+	})
+	printer.AddColumn("NAMESPACE", func(c kubeconfig.ContextInfo) string {
+		return c.Namespace
+	})
+	printer.AddColumn("EKSCTL", func(c kubeconfig.ContextInfo) string {
+		if c.Eksctl {
+			return "true"
+		}
+		return "false"
+	})
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/ami"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func refreshCatalogCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		versions    []string
+		amiFamilies []string
+		catalogPath string
+	)
+
+	cmd.SetDescription("refresh-catalog", "Refresh the local catalog of AMIs",
+		"Resolves the AMIs of the given Kubernetes versions and AMI families, and the AMIs already in the catalog, "+
+			"for the region and stores them in the local catalog; create commands use the AMIs of the catalog for "+
+			fmt.Sprintf("%s, and whatever their age when %s=true", ami.DefaultCatalogTTL, ami.OfflineEnvVar))
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doRefreshCatalog(cmd, versions, amiFamilies, catalogPath)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringSliceVar(&versions, "version", []string{api.DefaultVersion},
+			fmt.Sprintf("Kubernetes versions of the AMIs (valid options: %s)", strings.Join(api.SupportedVersions(), ", ")))
+		fs.StringSliceVar(&amiFamilies, "node-ami-family", []string{api.DefaultNodeImageFamily}, "AMI families of the AMIs")
+		fs.StringVar(&catalogPath, "catalog-path", "", fmt.Sprintf("path of the catalog (default %q, or $%s)", "~/.eksctl/cache/catalog.json", ami.CatalogPathEnvVar))
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doRefreshCatalog(cmd *cmdutils.Cmd, versions, amiFamilies []string, catalogPath string) error {
+	if catalogPath == "" {
+		catalogPath = ami.DefaultCatalogPath()
+	}
+	if catalogPath == "" {
+		return fmt.Errorf("the catalog is disabled, %s is empty", ami.CatalogPathEnvVar)
+	}
+	for _, version := range versions {
+		if !isSupportedVersion(version) {
+			return fmt.Errorf("invalid version %q, supported values: %s", version, strings.Join(api.SupportedVersions(), ", "))
+		}
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cmd.ClusterConfig.Metadata)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	catalog, err := ami.LoadCatalog(catalogPath)
+	if err != nil {
+		return err
+	}
+
+	region := ctl.Provider.Region()
+	type image struct{ resolverKind, version, instanceType, imageFamily string }
+	var images []image
+	seen := map[image]bool{}
+	add := func(i image) {
+		if !seen[i] {
+			seen[i] = true
+			images = append(images, i)
+		}
+	}
+	for _, key := range catalog.Keys() {
+		resolverKind, keyRegion, version, instanceType, imageFamily, err := ami.ParseCatalogKey(key)
+		if err != nil {
+			logger.Warning("%v", err)
+			continue
+		}
+		if keyRegion == region {
+			add(image{resolverKind, version, instanceType, imageFamily})
+		}
+	}
+	for _, version := range versions {
+		for _, imageFamily := range amiFamilies {
+			for _, instanceType := range ami.CatalogInstanceTypes(imageFamily) {
+				add(image{"", version, instanceType, imageFamily})
+			}
+		}
+	}
+
+	failed := 0
+	for _, i := range images {
+		id, err := eks.NewAMIResolver(ctl.Provider, i.resolverKind).Resolve(region, i.version, i.instanceType, i.imageFamily)
+		if err == nil && id == "" {
+			err = ami.NewErrFailedResolution(region, i.version, i.instanceType, i.imageFamily)
+		}
+		if err != nil {
+			logger.Warning("unable to resolve the %s AMI of version %s for %s: %v", i.imageFamily, i.version, i.instanceType, err)
+			failed++
+			continue
+		}
+		if err := catalog.Store(i.resolverKind, region, i.version, i.instanceType, i.imageFamily, id); err != nil {
+			return err
+		}
+		logger.Info("%s AMI of version %s for %s is %s", i.imageFamily, i.version, i.instanceType, id)
+	}
+
+	if failed == len(images) {
+		return fmt.Errorf("unable to resolve any AMI")
+	}
+	logger.Success("refreshed %d AMIs of catalog %q", len(images)-failed, catalogPath)
+	return nil
+}
+
+func isSupportedVersion(version string) bool {
+	for _, supported := range api.SupportedVersions() {
+		if version == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+)
+
+// renderedFile is a file written by 'eksctl utils render', its path is relative to the output directory
+type renderedFile struct {
+	path    string
+	content []byte
+}
+
+func renderCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var outputDir string
+
+	cmd.SetDescription("render", "Write everything eksctl would deploy for a config file into a directory",
+		"The CloudFormation templates, the user data of the nodegroups and the Kubernetes manifests are written to the "+
+			"templates/, userdata/ and manifests/ subdirectories, so that the output of two versions of eksctl can be compared "+
+			"before upgrading. Nodegroups depend on the endpoint of the cluster, so they are only rendered once the cluster exists")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doRender(cmd, outputDir)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.StringVar(&outputDir, "out", ".", "directory to write the files to, it's created if it doesn't exist")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doRender(cmd *cmdutils.Cmd, outputDir string) error {
+	rendered, err := renderStackTemplates(cmd)
+	if err != nil {
+		return err
+	}
+	cfg := cmd.ClusterConfig
+
+	var files []renderedFile
+	for _, template := range rendered.templates {
+		files = append(files, renderedFile{
+			path:    filepath.Join("templates", template.StackName+".json"),
+			content: template.Body,
+		})
+	}
+
+	userData, err := renderUserData(cfg, rendered.nodeGroups, rendered.managedNodeGroups)
+	if err != nil {
+		return err
+	}
+	files = append(files, userData...)
+
+	manifests, err := renderManifests(cfg, rendered.nodeGroups)
+	if err != nil {
+		return err
+	}
+	files = append(files, manifests...)
+
+	for _, file := range files {
+		path := filepath.Join(outputDir, file.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.Wrapf(err, "creating directory %q", filepath.Dir(path))
+		}
+		if err := ioutil.WriteFile(path, file.content, 0644); err != nil {
+			return errors.Wrapf(err, "writing %q", path)
+		}
+	}
+	logger.Success("wrote %d files to %q", len(files), outputDir)
+	return nil
+}
+
+// renderUserData decodes the user data of the launch templates of the nodegroups, managed nodegroups
+// only having user data when they run commands before bootstrapping
+func renderUserData(cfg *api.ClusterConfig, nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup) ([]renderedFile, error) {
+	var files []renderedFile
+
+	addUserData := func(nodeGroupName, userData string) error {
+		if userData == "" {
+			return nil
+		}
+		content, err := cloudconfig.DecodeUserData(userData)
+		if err != nil {
+			return errors.Wrapf(err, "decoding user data of nodegroup %q", nodeGroupName)
+		}
+		files = append(files, renderedFile{
+			path:    filepath.Join("userdata", nodeGroupName),
+			content: content,
+		})
+		return nil
+	}
+
+	for _, ng := range nodeGroups {
+		userData, err := nodebootstrap.NewUserData(cfg, ng)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating user data of nodegroup %q", ng.Name)
+		}
+		if err := addUserData(ng.Name, userData); err != nil {
+			return nil, err
+		}
+	}
+	for _, ng := range managedNodeGroups {
+		userData, err := nodebootstrap.NewUserDataForManagedNodeGroup(ng)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating user data of managed nodegroup %q", ng.Name)
+		}
+		if err := addUserData(ng.Name, userData); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// renderManifests builds the Kubernetes objects eksctl creates in the cluster for its config: the
+// storage classes of single-AZ nodegroups and the service accounts of IAM service accounts, the
+// role annotation of service accounts being only known once their role has been created
+func renderManifests(cfg *api.ClusterConfig, nodeGroups []*api.NodeGroup) ([]renderedFile, error) {
+	var files []renderedFile
+
+	addManifest := func(name string, obj interface{}) error {
+		content, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "encoding %s", name)
+		}
+		files = append(files, renderedFile{
+			path:    filepath.Join("manifests", name+".yaml"),
+			content: content,
+		})
+		return nil
+	}
+
+	zones := map[string]bool{}
+	for _, ng := range nodeGroups {
+		if zone := ng.Zone(); api.IsEnabled(ng.ZonalStorageClass) && !zones[zone] {
+			zones[zone] = true
+			if err := addManifest("storageclass-"+kubernetes.ZonalStorageClassName(zone), kubernetes.NewZonalStorageClass(zone)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if cfg.IAM != nil {
+		for _, sa := range cfg.IAM.ServiceAccounts {
+			sa.SetAnnotations()
+			name := fmt.Sprintf("serviceaccount-%s-%s", sa.Namespace, sa.Name)
+			if err := addManifest(name, kubernetes.NewServiceAccount(sa.ObjectMeta)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
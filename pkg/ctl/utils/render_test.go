@@ -0,0 +1,68 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("render", func() {
+	var cfg *api.ClusterConfig
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+	})
+
+	It("decodes the user data of managed nodegroups running commands before bootstrapping", func() {
+		withCommands := api.NewManagedNodeGroup()
+		withCommands.Name = "mng-1"
+		withCommands.PreBootstrapCommands = []string{"echo hello"}
+		withoutCommands := api.NewManagedNodeGroup()
+		withoutCommands.Name = "mng-2"
+
+		files, err := renderUserData(cfg, nil, []*api.ManagedNodeGroup{withCommands, withoutCommands})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].path).To(Equal("userdata/mng-1"))
+		Expect(string(files[0].content)).To(ContainSubstring("MIME-Version: 1.0"))
+		Expect(string(files[0].content)).To(ContainSubstring("set -o errexit\necho hello\n"))
+	})
+
+	It("renders the storage classes of single-AZ nodegroups once per zone", func() {
+		var nodeGroups []*api.NodeGroup
+		for _, name := range []string{"ng-1", "ng-2"} {
+			ng := cfg.NewNodeGroup()
+			ng.Name = name
+			ng.AvailabilityZones = []string{"us-west-2a"}
+			ng.ZonalStorageClass = api.Enabled()
+			nodeGroups = append(nodeGroups, ng)
+		}
+
+		files, err := renderManifests(cfg, nodeGroups)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].path).To(Equal("manifests/storageclass-gp2-us-west-2a.yaml"))
+		Expect(string(files[0].content)).To(ContainSubstring("kind: StorageClass"))
+		Expect(string(files[0].content)).To(ContainSubstring("- us-west-2a"))
+	})
+
+	It("renders the service accounts of IAM service accounts", func() {
+		roleARN := "arn:aws:iam::123456789012:role/s3-reader"
+		cfg.IAM.ServiceAccounts = []*api.ClusterIAMServiceAccount{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "s3-reader", Namespace: "backend"},
+				Status:     &api.ClusterIAMServiceAccountStatus{RoleARN: &roleARN},
+			},
+		}
+
+		files, err := renderManifests(cfg, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].path).To(Equal("manifests/serviceaccount-backend-s3-reader.yaml"))
+		Expect(string(files[0].content)).To(ContainSubstring("kind: ServiceAccount"))
+		Expect(string(files[0].content)).To(ContainSubstring("eks.amazonaws.com/role-arn: " + roleARN))
+	})
+})
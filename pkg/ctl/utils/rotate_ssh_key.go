@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
+	"github.com/weaveworks/eksctl/pkg/ssh"
+)
+
+func rotateSSHKeyCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var nodeGroupName, newKey string
+
+	cmd.SetDescription("rotate-ssh-key", "Replace the SSH key of a nodegroup and its instances",
+		"The launch template of the nodegroup is updated to use the new key, then the instances are replaced one at a time, "+
+			"after draining their nodes; the previous key pair is deleted if it was imported by eksctl. Only self-managed nodegroups are supported")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doRotateSSHKey(cmd, nodeGroupName, newKey)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
+		fs.StringVarP(&nodeGroupName, "name", "n", "", "Name of the nodegroup")
+		fs.StringVar(&newKey, "new-key", "", "SSH public key to use, a path to a local file or the name of an existing EC2 key pair")
+
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, cmd, api.TimeoutPhaseNodeGroup, api.TimeoutPhaseDrain)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doRotateSSHKey(cmd *cmdutils.Cmd, nodeGroupName, newKey string) error {
+	cfg := cmd.ClusterConfig
+
+	if cfg.Metadata.Name == "" {
+		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	}
+
+	if nodeGroupName != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg("--name", nodeGroupName, cmd.NameArg)
+	}
+
+	if cmd.NameArg != "" {
+		nodeGroupName = cmd.NameArg
+	}
+
+	if nodeGroupName == "" {
+		return cmdutils.ErrMustBeSet("name")
+	}
+
+	if newKey == "" {
+		return cmdutils.ErrMustBeSet("--new-key")
+	}
+
+	ctl := eks.New(cmd.ProviderConfig, cmd.ClusterConfig)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+
+	// check the nodegroup before importing the key
+	stackManager := manager.NewStackCollection(ctl.Provider, cfg)
+	nodeGroupType, err := stackManager.GetNodeGroupStackType(nodeGroupName)
+	if err != nil {
+		return err
+	}
+	if nodeGroupType != api.NodeGroupTypeUnmanaged {
+		return fmt.Errorf("the SSH key of managed nodegroup %q can't be changed, only self-managed nodegroups are supported", nodeGroupName)
+	}
+
+	keyName, err := ssh.LoadKey(&api.NodeGroupSSH{Allow: api.Enabled(), PublicKeyPath: &newKey}, cfg.Metadata.Name, nodeGroupName, ctl.Provider.EC2())
+	if err != nil {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	ng := cfg.NewNodeGroup()
+	ng.Name = nodeGroupName
+	return nodegroup.RotateSSHKey(ctl.Provider, stackManager, clientSet, cfg.Metadata.Name, ng, keyName)
+}
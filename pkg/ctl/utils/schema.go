@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/explain"
+)
+
+func schemaCmd(cmd *cmdutils.Cmd) {
+	cmd.SetDescription("schema", "Output the JSON Schema of the config file",
+		"The schema is generated from the ClusterConfig types of this version of eksctl, with the documentation, "+
+			"the valid values and the defaults of the fields, so that editors and pipelines can validate config files "+
+			"without calling AWS")
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doSchema()
+	}
+}
+
+func doSchema() error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(explain.Schema())
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/ssm"
+)
+
+func ssmSessionCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var nodeName string
+
+	cmd.SetDescription("ssm-session", "Start an SSM session to a node",
+		"The instance of the node is resolved from its provider ID, and the session is started with the session manager plugin, "+
+			"which must be installed. The nodes must run the SSM agent and be allowed to use SSM, e.g. with ssh.enableSSM")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doSSMSession(cmd, nodeName)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
+		fs.StringVar(&nodeName, "node", "", "Name of the node")
+
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doSSMSession(cmd *cmdutils.Cmd, nodeName string) error {
+	cfg := cmd.ClusterConfig
+
+	if cfg.Metadata.Name == "" {
+		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	}
+
+	if nodeName != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg("--node", nodeName, cmd.NameArg)
+	}
+
+	if cmd.NameArg != "" {
+		nodeName = cmd.NameArg
+	}
+
+	if nodeName == "" {
+		return cmdutils.ErrMustBeSet("--node")
+	}
+
+	ctl := eks.New(cmd.ProviderConfig, cmd.ClusterConfig)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	instanceID, err := ssm.InstanceID(clientSet, nodeName)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("starting SSM session to instance %q of node %q", instanceID, nodeName)
+	return ssm.StartSession(ctl.Provider, instanceID)
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
+)
+
+func suspendProcessesCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		nodeGroupName string
+		processes     []string
+		resume        bool
+	)
+
+	cmd.SetDescription("suspend-processes", "Suspend or resume processes of the Auto Scaling group of a nodegroup",
+		"Suspending AZRebalance keeps the Auto Scaling group from terminating instances without draining their nodes "+
+			"to balance availability zones. Only self-managed nodegroups are supported")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doSuspendProcesses(cmd, nodeGroupName, processes, resume)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
+		fs.StringVarP(&nodeGroupName, "name", "n", "", "Name of the nodegroup")
+		fs.StringSliceVar(&processes, "processes", nil, "Processes to suspend or resume, one of: "+strings.Join(api.SuspendableProcesses(), ", "))
+		fs.BoolVar(&resume, "resume", false, "Resume the processes instead of suspending them")
+
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doSuspendProcesses(cmd *cmdutils.Cmd, nodeGroupName string, processes []string, resume bool) error {
+	cfg := cmd.ClusterConfig
+
+	if cfg.Metadata.Name == "" {
+		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+	}
+
+	if nodeGroupName != "" && cmd.NameArg != "" {
+		return cmdutils.ErrFlagAndArg("--name", nodeGroupName, cmd.NameArg)
+	}
+
+	if cmd.NameArg != "" {
+		nodeGroupName = cmd.NameArg
+	}
+
+	if nodeGroupName == "" {
+		return cmdutils.ErrMustBeSet("name")
+	}
+
+	if len(processes) == 0 {
+		return cmdutils.ErrMustBeSet("--processes")
+	}
+
+	if err := api.ValidateSuspendProcesses(processes, "--processes"); err != nil {
+		return err
+	}
+
+	ctl := eks.New(cmd.ProviderConfig, cmd.ClusterConfig)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	stackManager := manager.NewStackCollection(ctl.Provider, cfg)
+	if resume {
+		return nodegroup.ResumeProcesses(ctl.Provider, stackManager, nodeGroupName, processes)
+	}
+	return nodegroup.SuspendProcesses(ctl.Provider, stackManager, nodeGroupName, processes)
+}
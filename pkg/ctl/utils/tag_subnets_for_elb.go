@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+func tagSubnetsForELBCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("tag-subnets-for-elb", "Check and add the subnet tags used for load balancer discovery",
+		"Public subnets need the kubernetes.io/role/elb tag and private subnets the kubernetes.io/role/internal-elb tag for load balancers to be provisioned in them")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doTagSubnetsForELB(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doTagSubnetsForELB(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if err := ctl.LoadClusterVPC(cfg); err != nil {
+		return errors.Wrapf(err, "getting VPC configuration for cluster %q", meta.Name)
+	}
+
+	changes, err := vpc.PlanSubnetTagsForELB(ctl.Provider, cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		logger.Success("all subnets of cluster %q in %q are tagged for load balancers", meta.Name, meta.Region)
+		return nil
+	}
+
+	for _, change := range changes {
+		logger.Info(change.String())
+	}
+
+	if cfg.IsSharedVPC() {
+		return errors.Errorf("subnets of cluster %q are shared by account %s, only that account can tag them", meta.Name, cfg.VPC.OwnerAccountID)
+	}
+
+	cmdutils.LogIntendedAction(cmd.Plan, "add %d load balancer role tag(s) to subnets of cluster %q in %q", len(changes), meta.Name, meta.Region)
+	if !cmd.Plan {
+		if err := vpc.ApplySubnetTagsForELB(ctl.Provider, changes); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			logger.Info("tagged %s subnet %s (%s) with %s=1", change.Topology, change.SubnetID, change.AvailabilityZone, change.Tag)
+		}
+		logger.Success("subnets of cluster %q in %q are tagged for load balancers", meta.Name, meta.Region)
+	}
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	return nil
+}
@@ -13,25 +13,32 @@ func updateAWSNodeCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
+	var options addonUpdateOptions
+
 	cmd.SetDescription("update-aws-node", "Update aws-node add-on to latest released version", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doUpdateAWSNode(cmd)
+		return doUpdateAWSNode(cmd, options)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddApproveFlag(fs, cmd)
+		addAddonUpdateFlags(fs, &options)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doUpdateAWSNode(cmd *cmdutils.Cmd) error {
+func doUpdateAWSNode(cmd *cmdutils.Cmd, options addonUpdateOptions) error {
+	if err := options.validate(); err != nil {
+		return err
+	}
+
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -58,12 +65,7 @@ func doUpdateAWSNode(cmd *cmdutils.Cmd) error {
 		return err
 	}
 
-	updateRequired, err := defaultaddons.UpdateAWSNode(rawClient, meta.Region, cmd.Plan)
-	if err != nil {
-		return err
-	}
-
-	cmdutils.LogPlanModeWarning(cmd.Plan && updateRequired)
-
-	return nil
+	return updateAddon(cmd, rawClient, defaultaddons.AWSNode, options, func(plan bool) (bool, error) {
+		return defaultaddons.UpdateAWSNode(rawClient, meta.Region, options.imageTag, plan)
+	})
 }
@@ -13,25 +13,32 @@ func updateCoreDNSCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
+	var options addonUpdateOptions
+
 	cmd.SetDescription("update-coredns", "Update coredns add-on to ensure image matches the standard Amazon EKS version", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doUpdateCoreDNS(cmd)
+		return doUpdateCoreDNS(cmd, options)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
 		cmdutils.AddApproveFlag(fs, cmd)
+		addAddonUpdateFlags(fs, &options)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doUpdateCoreDNS(cmd *cmdutils.Cmd) error {
+func doUpdateCoreDNS(cmd *cmdutils.Cmd, options addonUpdateOptions) error {
+	if err := options.validate(); err != nil {
+		return err
+	}
+
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -63,12 +70,7 @@ func doUpdateCoreDNS(cmd *cmdutils.Cmd) error {
 		return err
 	}
 
-	updateRequired, err := defaultaddons.UpdateCoreDNS(rawClient, meta.Region, kubernetesVersion, cmd.Plan)
-	if err != nil {
-		return err
-	}
-
-	cmdutils.LogPlanModeWarning(cmd.Plan && updateRequired)
-
-	return nil
+	return updateAddon(cmd, rawClient, defaultaddons.CoreDNS, options, func(plan bool) (bool, error) {
+		return defaultaddons.UpdateCoreDNS(rawClient, meta.Region, kubernetesVersion, options.imageTag, plan)
+	})
 }
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func updateTagsCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("update-tags", "Add or update the tags of the CloudFormation stacks of a cluster",
+		"The tags are set in metadata.tags or with --tags, CloudFormation propagates them to the resources of the stacks")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doUpdateTags(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.StringToStringVarP(&cfg.Metadata.Tags, "tags", "", map[string]string{}, `A list of KV pairs used to tag the AWS resources (e.g. "Owner=John Doe,Team=Some Team")`)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doUpdateTags(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewUtilsUpdateTagsLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+	stacks, err := stackManager.DescribeStacks()
+	if err != nil {
+		return err
+	}
+
+	cmdutils.LogIntendedAction(cmd.Plan, "add or update %d tag(s) of %d CloudFormation stack(s) of cluster %q in %q", len(meta.Tags), len(stacks), meta.Name, meta.Region)
+	if cmd.Plan {
+		cmdutils.LogPlanModeWarning(true)
+		return nil
+	}
+
+	updated := 0
+	for _, s := range stacks {
+		ok, err := stackManager.UpdateStackTags(s, meta.Tags)
+		if err != nil {
+			return err
+		}
+		if ok {
+			updated++
+		}
+	}
+	if updated == 0 {
+		logger.Success("all stacks of cluster %q in %q are already tagged", meta.Name, meta.Region)
+		return nil
+	}
+	logger.Success("updated tags of %d stack(s) of cluster %q in %q", updated, meta.Name, meta.Region)
+	return nil
+}
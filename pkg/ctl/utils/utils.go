@@ -11,6 +11,9 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, waitNodesCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, writeKubeconfigCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, listKubeconfigContextsCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, writeCFNTemplatesCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, renderCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, describeStacksCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateClusterStackCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateKubeProxyCmd)
@@ -20,10 +23,25 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, enableLoggingCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, associateIAMOIDCProviderCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, installWindowsVPCController)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, deleteWindowsVPCController)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateClusterEndpointsCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, publicAccessCIDRsCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, enableSecretsEncryptionCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, tagSubnetsForELBCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateTagsCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, checkEndpointCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, describeClusterHealthCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, checkDeprecatedAPIsCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, schemaCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, generateIAMPolicyCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, convertConfigCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, refreshCatalogCmd)
 
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, nodeGroupHealthCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, rotateSSHKeyCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, cleanupLaunchTemplatesCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, ssmSessionCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, suspendProcessesCmd)
 
 	return verbCmd
 }
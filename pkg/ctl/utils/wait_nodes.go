@@ -1,63 +1,143 @@
 package utils
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/eks"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
+// defaultKubeconfigPath is the kubeconfig file that is used when neither --cluster nor --kubeconfig
+// are set, which was the default of --kubeconfig before the cluster could be given instead
+const defaultKubeconfigPath = "kubeconfig"
+
+// clusterNodes selects all the nodes of a cluster, whatever their nodegroup
+type clusterNodes struct {
+	name    string
+	minSize int
+}
+
+func (n *clusterNodes) NameString() string              { return n.name }
+func (n *clusterNodes) Size() int                       { return n.minSize }
+func (n *clusterNodes) ListOptions() metav1.ListOptions { return metav1.ListOptions{} }
+func (n *clusterNodes) GetAMIFamily() string            { return "" }
+
 func waitNodesCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
-	ng := cfg.NewNodeGroup()
 	cmd.ClusterConfig = cfg
 
-	var kubeconfigPath string
+	var (
+		kubeconfigPath string
+		nodeGroupName  string
+		minSize        int
+	)
 
-	cmd.SetDescription("wait-nodes", "Wait for nodes", "")
+	cmd.SetDescription("wait-nodes", "Wait for nodes to join the cluster and become ready",
+		"Waits for at least --nodes-min nodes of the cluster, or of one of its nodegroups, to be ready, "+
+			"e.g. to wait for the nodegroups created with waitForNodes disabled")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doWaitNodes(cmd, ng, kubeconfigPath)
+		return doWaitNodes(cmd, kubeconfigPath, nodeGroupName, minSize)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
-		fs.StringVar(&kubeconfigPath, "kubeconfig", "kubeconfig", "path to read kubeconfig")
-		minSize := fs.IntP("nodes-min", "m", api.DefaultNodeCount, "minimum number of nodes to wait for")
-		cmdutils.AddPreRun(cmd.CobraCommand, func(cobraCmd *cobra.Command, args []string) {
-			if f := cobraCmd.Flag("nodes-min"); f.Changed {
-				ng.MinSize = minSize
-			}
-		})
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringVarP(&nodeGroupName, "nodegroup", "n", "", "only wait for the nodes of this nodegroup")
+		fs.StringVar(&kubeconfigPath, "kubeconfig", "", "path to read kubeconfig, used when --cluster isn't set (default \""+defaultKubeconfigPath+"\" if it exists)")
+		fs.IntVarP(&minSize, "nodes-min", "m", api.DefaultNodeCount, "minimum number of nodes to wait for")
 		fs.DurationVar(&cmd.ProviderConfig.WaitTimeout, "timeout", api.DefaultWaitTimeout, "how long to wait")
 	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doWaitNodes(cmd *cmdutils.Cmd, ng *api.NodeGroup, kubeconfigPath string) error {
+func doWaitNodes(cmd *cmdutils.Cmd, kubeconfigPath, nodeGroupName string, minSize int) error {
 	cfg := cmd.ClusterConfig
 
-	ctl := eks.New(cmd.ProviderConfig, cfg)
-
-	if kubeconfigPath == "" {
-		return cmdutils.ErrMustBeSet("--kubeconfig")
+	if cfg.Metadata.Name != "" && cmd.NameArg != "" {
+		return cmdutils.ErrClusterFlagAndArg(cmd, cfg.Metadata.Name, cmd.NameArg)
 	}
-
-	clientConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-	if err != nil {
-		return err
+	if cmd.NameArg != "" {
+		cfg.Metadata.Name = cmd.NameArg
 	}
 
-	clientSet, err := kubernetes.NewForConfig(clientConfig)
-	if err != nil {
-		return err
+	waitFor := waitNodesTarget(cfg.Metadata.Name, nodeGroupName, minSize)
+
+	var (
+		ctl       *eks.ClusterProvider
+		clientSet kubernetes.Interface
+		err       error
+	)
+	if cfg.Metadata.Name != "" {
+		// the credentials of the cluster are used instead of a kubeconfig file
+		if ctl, err = cmd.NewCtl(); err != nil {
+			return err
+		}
+		cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+		if err := ctl.CheckAuth(); err != nil {
+			return err
+		}
+		if ok, err := ctl.CanOperate(cfg); !ok {
+			return err
+		}
+		if clientSet, err = ctl.NewStdClientSet(cfg); err != nil {
+			return err
+		}
+	} else {
+		if kubeconfigPath, err = waitNodesKubeconfigPath(kubeconfigPath); err != nil {
+			return err
+		}
+		ctl = eks.New(cmd.ProviderConfig, cfg)
+
+		clientConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+		if err != nil {
+			return err
+		}
+		if clientSet, err = kubernetes.NewForConfig(clientConfig); err != nil {
+			return err
+		}
 	}
 
-	if err := ctl.WaitForNodes(clientSet, ng); err != nil {
-		return err
+	return ctl.WaitForNodes(clientSet, waitFor)
+}
+
+// waitNodesTarget returns the nodes to wait for, the nodes of the nodegroup when it's set, and all the
+// nodes of the cluster otherwise
+func waitNodesTarget(clusterName, nodeGroupName string, minSize int) eks.KubeNodeGroup {
+	if nodeGroupName != "" {
+		// only the name and the size of the nodegroup are needed, it isn't added to the config
+		ng := api.NewNodeGroup()
+		ng.Name = nodeGroupName
+		ng.MinSize = &minSize
+		return ng
+	}
+	nodes := &clusterNodes{name: clusterName, minSize: minSize}
+	if nodes.name == "" {
+		nodes.name = "cluster"
 	}
+	return nodes
+}
 
-	return nil
+// waitNodesKubeconfigPath returns the kubeconfig file to read when --cluster isn't set, falling
+// back to defaultKubeconfigPath when it exists
+func waitNodesKubeconfigPath(kubeconfigPath string) (string, error) {
+	if kubeconfigPath != "" {
+		return kubeconfigPath, nil
+	}
+	if _, err := os.Stat(defaultKubeconfigPath); err != nil {
+		return "", cmdutils.ErrMustBeSet("--cluster or --kubeconfig")
+	}
+	logger.Info("using %q, as neither --cluster nor --kubeconfig are set", defaultKubeconfigPath)
+	return defaultKubeconfigPath, nil
 }
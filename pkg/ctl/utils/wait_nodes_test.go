@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("wait-nodes", func() {
+	Describe("nodes to wait for", func() {
+		var (
+			ctl         *eks.ClusterProvider
+			clientSet   *fake.Clientset
+			waitTimeout time.Duration
+		)
+
+		readyNode := func(name, nodeGroupName string) *corev1.Node {
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{api.NodeGroupNameLabel: nodeGroupName},
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			waitTimeout = mockprovider.ProviderConfig.WaitTimeout
+			mockprovider.ProviderConfig.WaitTimeout = 100 * time.Millisecond
+			ctl = &eks.ClusterProvider{Provider: mockprovider.NewMockProvider()}
+			clientSet = fake.NewSimpleClientset(readyNode("node-1", "ng-1"), readyNode("node-2", "ng-2"))
+		})
+
+		AfterEach(func() {
+			mockprovider.ProviderConfig.WaitTimeout = waitTimeout
+		})
+
+		It("waits for the nodes of all the nodegroups of the cluster", func() {
+			nodes := waitNodesTarget("cluster-1", "", 2)
+			Expect(nodes.NameString()).To(Equal("cluster-1"))
+			Expect(nodes.ListOptions().LabelSelector).To(BeEmpty())
+			Expect(ctl.WaitForNodes(clientSet, nodes)).To(Succeed())
+		})
+
+		It("only waits for the nodes of the nodegroup given with --nodegroup", func() {
+			nodes := waitNodesTarget("cluster-1", "ng-1", 1)
+			Expect(nodes.NameString()).To(Equal("ng-1"))
+			Expect(nodes.ListOptions().LabelSelector).To(Equal(api.NodeGroupNameLabel + "=ng-1"))
+			Expect(ctl.WaitForNodes(clientSet, nodes)).To(Succeed())
+
+			nodes = waitNodesTarget("cluster-1", "ng-1", 2)
+			Expect(ctl.WaitForNodes(clientSet, nodes)).To(MatchError(ContainSubstring(`waiting for at least 2 nodes to join the cluster and become ready in "ng-1"`)))
+		})
+	})
+
+	Describe("kubeconfig", func() {
+		var workDir, dir string
+
+		BeforeEach(func() {
+			var err error
+			workDir, err = os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			dir, err = ioutil.TempDir("", "wait-nodes")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chdir(dir)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Chdir(workDir)).To(Succeed())
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("reads the file given with --kubeconfig", func() {
+			Expect(waitNodesKubeconfigPath("/tmp/kubeconfig")).To(Equal("/tmp/kubeconfig"))
+		})
+
+		It("falls back to the kubeconfig file of the working directory", func() {
+			Expect(ioutil.WriteFile(defaultKubeconfigPath, []byte("apiVersion: v1\n"), 0600)).To(Succeed())
+			Expect(waitNodesKubeconfigPath("")).To(Equal(defaultKubeconfigPath))
+		})
+
+		It("requires --cluster or --kubeconfig without a kubeconfig file in the working directory", func() {
+			_, err := waitNodesKubeconfigPath("")
+			Expect(err).To(MatchError("--cluster or --kubeconfig must be set"))
+
+			_, err = newMockCmd("wait-nodes").execute()
+			Expect(err).To(MatchError("--cluster or --kubeconfig must be set"))
+		})
+	})
+})
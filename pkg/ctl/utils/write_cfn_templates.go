@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/ami"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+func writeCFNTemplatesCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var outputDir string
+
+	cmd.SetDescription("write-cfn-templates", "Write the CloudFormation templates of a cluster and its nodegroups without creating them",
+		"The templates are the ones 'eksctl create cluster' would deploy for the given config file; the VPC is part of the cluster template. "+
+			"Nodegroup templates depend on the endpoint of the cluster, so they are only written once the cluster exists")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doWriteCFNTemplates(cmd, outputDir)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.StringVar(&outputDir, "out", ".", "directory to write the templates to, it's created if it doesn't exist")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doWriteCFNTemplates(cmd *cmdutils.Cmd, outputDir string) error {
+	rendered, err := renderStackTemplates(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return errors.Wrapf(err, "creating directory %q", outputDir)
+	}
+	for _, template := range rendered.templates {
+		path := filepath.Join(outputDir, template.StackName+".json")
+		if err := ioutil.WriteFile(path, template.Body, 0644); err != nil {
+			return errors.Wrapf(err, "writing template of stack %q", template.StackName)
+		}
+		logger.Success("wrote template of stack %q to %q", template.StackName, path)
+	}
+	return nil
+}
+
+// renderedStacks holds the templates of the stacks of a cluster, and the nodegroups they include
+type renderedStacks struct {
+	templates         []manager.StackTemplate
+	nodeGroups        []*api.NodeGroup
+	managedNodeGroups []*api.ManagedNodeGroup
+}
+
+// renderStackTemplates loads the config file of cmd and builds the templates 'eksctl create cluster' would
+// deploy for it, the templates of nodegroups are left out when the cluster doesn't exist yet
+func renderStackTemplates(cmd *cmdutils.Cmd) (*renderedStacks, error) {
+	if err := cmdutils.NewUtilsWriteCFNTemplatesLoader(cmd, cmd.ClusterConfig.Metadata.Name).Load(); err != nil {
+		return nil, err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return nil, err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return nil, err
+	}
+
+	if meta.Version == "" {
+		meta.Version = api.DefaultVersion
+	}
+
+	// nodegroups bootstrap with the endpoint and CA of the cluster, which are
+	// only known once the cluster has been created
+	clusterExists := true
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		if awsErr, ok := errors.Cause(err).(awserr.Error); !ok || awsErr.Code() != awseks.ErrCodeResourceNotFoundException {
+			return nil, err
+		}
+		clusterExists = false
+	}
+
+	if cfg.HasAnySubnets() {
+		if err := vpc.ImportAllSubnets(ctl.NewVPCLookupProvider(cfg), cfg); err != nil {
+			return nil, err
+		}
+		if err := cfg.HasSufficientSubnets(); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := ctl.SetAvailabilityZones(cfg, nil); err != nil {
+			return nil, err
+		}
+		if err := vpc.SetSubnets(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	rendered := &renderedStacks{}
+	if clusterExists {
+		for _, ng := range cfg.NodeGroups {
+			if err := eks.EnsureAMI(ctl.Provider, meta.Version, ng, ami.DefaultCatalogPath()); err != nil {
+				return nil, err
+			}
+			warnIfKeyNotImported(ng.Name, ng.SSH)
+		}
+		for _, ng := range cfg.ManagedNodeGroups {
+			warnIfKeyNotImported(ng.Name, ng.SSH)
+		}
+		rendered.nodeGroups, rendered.managedNodeGroups = cfg.NodeGroups, cfg.ManagedNodeGroups
+	} else if len(cfg.NodeGroups) > 0 || len(cfg.ManagedNodeGroups) > 0 {
+		logger.Warning("cluster %q doesn't exist yet, only the cluster template will be written; nodegroup templates can be written once it's created", meta.Name)
+	}
+
+	supportsManagedNodes, err := eks.VersionSupportsManagedNodes(meta.Version)
+	if err != nil {
+		return nil, err
+	}
+	rendered.templates, err = ctl.NewStackManager(cfg).RenderTemplates(rendered.nodeGroups, rendered.managedNodeGroups, supportsManagedNodes)
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// warnIfKeyNotImported warns about SSH keys given by path or content, as they're only
+// imported into EC2 when the nodegroup is created, and can't be referenced by templates
+func warnIfKeyNotImported(nodeGroupName string, ssh *api.NodeGroupSSH) {
+	if ssh != nil && api.IsEnabled(ssh.Allow) && !api.IsSetAndNonEmptyString(ssh.PublicKeyName) {
+		logger.Warning("the SSH public key of nodegroup %q is imported into EC2 when the nodegroup is created, the template doesn't set a key name", nodeGroupName)
+	}
+}
@@ -3,13 +3,13 @@ package utils
 import (
 	"fmt"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 )
 
@@ -19,7 +19,9 @@ func writeKubeconfigCmd(cmd *cmdutils.Cmd) {
 
 	var (
 		outputPath           string
+		authenticator        string
 		authenticatorRoleARN string
+		contextNameTemplate  string
 		setContext, autoPath bool
 	)
 
@@ -27,7 +29,7 @@ func writeKubeconfigCmd(cmd *cmdutils.Cmd) {
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doWriteKubeconfigCmd(cmd, outputPath, authenticatorRoleARN, setContext, autoPath)
+		return doWriteKubeconfigCmd(cmd, outputPath, authenticator, authenticatorRoleARN, contextNameTemplate, setContext, autoPath)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -37,13 +39,13 @@ func writeKubeconfigCmd(cmd *cmdutils.Cmd) {
 	})
 
 	cmd.FlagSetGroup.InFlagSet("Output kubeconfig", func(fs *pflag.FlagSet) {
-		cmdutils.AddCommonFlagsForKubeconfig(fs, &outputPath, &authenticatorRoleARN, &setContext, &autoPath, "<name>")
+		cmdutils.AddCommonFlagsForKubeconfig(fs, &outputPath, &authenticator, &authenticatorRoleARN, &contextNameTemplate, &setContext, &autoPath, "<name>")
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, roleARN string, setContext, autoPath bool) error {
+func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, authenticator, roleARN, contextNameTemplate string, setContext, autoPath bool) error {
 	cfg := cmd.ClusterConfig
 
 	// TODO: move this into a loader when --config-file gets added to this command
@@ -66,6 +68,14 @@ func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, roleARN string, setCont
 		outputPath = kubeconfig.AutoPath(cfg.Metadata.Name)
 	}
 
+	authenticator, err := kubeconfig.ResolveAuthenticator(authenticator)
+	if err != nil {
+		return err
+	}
+	if err := kubeconfig.ValidateContextNameTemplate(contextNameTemplate); err != nil {
+		return err
+	}
+
 	ctl, err := cmd.NewCtl()
 	if err != nil {
 		return err
@@ -80,7 +90,10 @@ func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, roleARN string, setCont
 		return err
 	}
 
-	kubectlConfig := kubeconfig.NewForKubectl(cfg, ctl.GetUsername(), roleARN, ctl.Provider.Profile())
+	kubectlConfig := kubeconfig.NewForKubectl(cfg, ctl.GetUsername(), authenticator, roleARN, ctl.Provider.Profile())
+	if contextNameTemplate != "" {
+		kubeconfig.RenameContext(kubectlConfig, ctl.KubeconfigContextName(contextNameTemplate, cfg.Metadata))
+	}
 	filename, err := kubeconfig.Write(outputPath, *kubectlConfig, setContext)
 	if err != nil {
 		return errors.Wrap(err, "writing kubeconfig")
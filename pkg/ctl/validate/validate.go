@@ -0,0 +1,77 @@
+package validate
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+// Command creates the `validate` command
+func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
+	cmd := &cmdutils.Cmd{
+		CobraCommand:   &cobra.Command{},
+		ProviderConfig: &api.ProviderConfig{},
+		ClusterConfig:  api.NewClusterConfig(),
+		Validate:       true,
+	}
+	cmd.FlagSetGroup = flagGrouping.New(cmd.CobraCommand)
+
+	var output printers.Type
+
+	cmd.SetDescription("validate", "Validate a config file without calling AWS",
+		"Loads a config file, rejecting unknown fields unless --no-strict is set, then sets its defaults and validates it "+
+			"the same way as the commands using it, so that mistakes are caught before anything is created")
+
+	cmd.CobraCommand.Args = cobra.NoArgs
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doValidate(cmd, output)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile, &cmd.ClusterConfigFileOptions)
+		fs.StringVarP(&output, "output", "o", "", "print the config with its defaults set (valid options: yaml, json)")
+	})
+
+	cmd.FlagSetGroup.AddTo(cmd.CobraCommand)
+	return cmd.CobraCommand
+}
+
+func doValidate(cmd *cmdutils.Cmd, output printers.Type) error {
+	if err := cmdutils.NewValidateLoader(cmd).Load(); err != nil {
+		return err
+	}
+	cfg := cmd.ClusterConfig
+
+	if !isSupportedRegion(cfg.Metadata.Region) {
+		return cmdutils.ErrUnsupportedRegion(cmd.ProviderConfig)
+	}
+
+	if err := cmd.SetDefaultsAndValidate(); err != nil {
+		return err
+	}
+
+	if output == "" {
+		logger.Success("config file %q is valid", cmd.ClusterConfigFile)
+		return nil
+	}
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+	return printer.PrintObj(cfg, os.Stdout)
+}
+
+func isSupportedRegion(region string) bool {
+	for _, supportedRegion := range api.SupportedRegions() {
+		if region == supportedRegion {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+// Package deprecatedapis finds the objects of a cluster, and the requests to its API server,
+// that use versions of Kubernetes APIs which are removed in a later version of Kubernetes
+package deprecatedapis
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// RemovedAPI is a version of an API that is no longer served from a version of Kubernetes on
+type RemovedAPI struct {
+	// GroupVersion is the API version objects are written with, e.g. extensions/v1beta1
+	GroupVersion string
+	Kind         string
+	// Resource is the plural name of the resource, e.g. deployments
+	Resource string
+	// RemovedIn is the Kubernetes version the API version is no longer served from
+	RemovedIn string
+	// Replacement is the API version to migrate to, it's empty when the API is removed altogether
+	Replacement string `json:",omitempty"`
+}
+
+// Describe returns the group, version and kind of api, or its resource when the kind isn't known
+func (api RemovedAPI) Describe() string {
+	if api.Kind == "" {
+		return fmt.Sprintf("%s %s", api.GroupVersion, api.Resource)
+	}
+	return fmt.Sprintf("%s %s", api.GroupVersion, api.Kind)
+}
+
+// removedAPIs are the versions of the built-in APIs removed by Kubernetes,
+// see https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var removedAPIs = []RemovedAPI{
+	{"extensions/v1beta1", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "replicasets", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "networkpolicies", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "podsecuritypolicies", "1.16", "policy/v1beta1"},
+	{"apps/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "daemonsets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "deployments", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "replicasets", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "statefulsets", "1.16", "apps/v1"},
+
+	{"extensions/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "ingresses", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "ingressclasses", "1.22", "networking.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "customresourcedefinitions", "1.22", "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "mutatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "validatingwebhookconfigurations", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "apiservices", "1.22", "apiregistration.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "clusterroles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "clusterrolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "roles", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "rolebindings", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "priorityclasses", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "csidrivers", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", "csinodes", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "storageclasses", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "volumeattachments", "1.22", "storage.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "certificatesigningrequests", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "leases", "1.22", "coordination.k8s.io/v1"},
+
+	{"batch/v1beta1", "CronJob", "cronjobs", "1.25", "batch/v1"},
+	{"policy/v1beta1", "PodDisruptionBudget", "poddisruptionbudgets", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "podsecuritypolicies", "1.25", ""},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.25", "autoscaling/v2"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "endpointslices", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "events", "1.25", "events.k8s.io/v1"},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "runtimeclasses", "1.25", "node.k8s.io/v1"},
+
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "flowschemas", "1.26", "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.26", "flowcontrol.apiserver.k8s.io/v1beta3"},
+
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "csistoragecapacities", "1.27", "storage.k8s.io/v1"},
+
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "flowschemas", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "flowschemas", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// RemovedBy returns the APIs that are no longer served by kubernetesVersion, e.g. 1.22
+func RemovedBy(kubernetesVersion string) ([]RemovedAPI, error) {
+	var apis []RemovedAPI
+	for _, api := range removedAPIs {
+		removed, err := isRemovedBy(api.RemovedIn, kubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+		if removed {
+			apis = append(apis, api)
+		}
+	}
+	return apis, nil
+}
+
+// isRemovedBy compares the minor versions of removedIn and kubernetesVersion
+func isRemovedBy(removedIn, kubernetesVersion string) (bool, error) {
+	removed, err := semver.ParseTolerant(removedIn)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing Kubernetes version %q", removedIn)
+	}
+	target, err := semver.ParseTolerant(kubernetesVersion)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing Kubernetes version %q", kubernetesVersion)
+	}
+	removed.Patch, target.Patch = 0, 0
+	removed.Pre, target.Pre = nil, nil
+	return removed.LTE(target), nil
+}
+
+// Sources of the findings
+const (
+	// SourceLastAppliedConfiguration is used for objects last applied by kubectl with a removed API
+	SourceLastAppliedConfiguration = "last-applied-configuration"
+	// SourceMetrics is used for the removed APIs the API server received requests for
+	SourceMetrics = "apiserver_requested_deprecated_apis"
+)
+
+// Finding is an object, or requests to the API server, using a removed API
+type Finding struct {
+	API RemovedAPI
+	// Namespace and Name are those of the object, they're empty for requests to the API server
+	Namespace string `json:",omitempty"`
+	Name      string `json:",omitempty"`
+	Source    string
+}
+
+// ResourceLister lists the objects of a resource in all namespaces
+type ResourceLister func(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error)
+
+// NewResourceLister returns a ResourceLister using the dynamic client of a cluster
+func NewResourceLister(client dynamic.Interface) ResourceLister {
+	return func(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+		list, err := client.Resource(gvr).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+}
+
+// ScanObjects finds the objects that were last applied with one of apis. As the API server serves an object
+// at all the versions of its API, the version it was written with is taken from the annotation kubectl adds;
+// the APIs that are not served by the cluster are skipped
+func ScanObjects(list ResourceLister, apis []RemovedAPI) ([]Finding, error) {
+	var findings []Finding
+	for _, api := range apis {
+		gv, err := schema.ParseGroupVersion(api.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		objects, err := list(gv.WithResource(api.Resource))
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "listing %s in %s", api.Resource, api.GroupVersion)
+		}
+		for _, o := range objects {
+			if lastAppliedWith(o, api) {
+				findings = append(findings, Finding{
+					API:       api,
+					Namespace: o.GetNamespace(),
+					Name:      o.GetName(),
+					Source:    SourceLastAppliedConfiguration,
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+func lastAppliedWith(o unstructured.Unstructured, api RemovedAPI) bool {
+	lastApplied, ok := o.GetAnnotations()["kubectl.kubernetes.io/last-applied-configuration"]
+	if !ok {
+		return false
+	}
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal([]byte(lastApplied), &typeMeta); err != nil {
+		return false
+	}
+	return typeMeta.APIVersion == api.GroupVersion && typeMeta.Kind == api.Kind
+}
+
+// ScanMetrics finds the removed APIs that the API server received requests for, as counted by the
+// apiserver_requested_deprecated_apis metric of Kubernetes 1.19 and above, metrics being in the
+// Prometheus text format; the APIs not removed by kubernetesVersion are left out
+func ScanMetrics(metrics, kubernetesVersion string) ([]Finding, error) {
+	seen := map[RemovedAPI]bool{}
+	var findings []Finding
+	scanner := bufio.NewScanner(strings.NewReader(metrics))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, SourceMetrics+"{") {
+			continue
+		}
+		labels := parseLabels(line[len(SourceMetrics):])
+		if labels["removed_release"] == "" {
+			continue
+		}
+		removed, err := isRemovedBy(labels["removed_release"], kubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+		if !removed {
+			continue
+		}
+
+		gv := schema.GroupVersion{Group: labels["group"], Version: labels["version"]}.String()
+		api := RemovedAPI{GroupVersion: gv, Resource: labels["resource"], RemovedIn: labels["removed_release"]}
+		for _, known := range removedAPIs {
+			if known.GroupVersion == gv && known.Resource == api.Resource {
+				api = known
+				break
+			}
+		}
+		if seen[api] {
+			continue
+		}
+		seen[api] = true
+		findings = append(findings, Finding{API: api, Source: SourceMetrics})
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i].API, findings[j].API
+		if a.GroupVersion != b.GroupVersion {
+			return a.GroupVersion < b.GroupVersion
+		}
+		return a.Resource < b.Resource
+	})
+	return findings, nil
+}
+
+// parseLabels parses the labels of a Prometheus sample, e.g. {group="apps",version="v1beta1"} 1
+func parseLabels(s string) map[string]string {
+	labels := map[string]string{}
+	end := strings.Index(s, "}")
+	if !strings.HasPrefix(s, "{") || end < 0 {
+		return labels
+	}
+	for _, pair := range strings.Split(s[1:end], ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		labels[strings.TrimSpace(parts[0])] = strings.Trim(parts[1], `"`)
+	}
+	return labels
+}
@@ -0,0 +1,88 @@
+package deprecatedapis
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("deprecated APIs", func() {
+	deployments := RemovedAPI{"extensions/v1beta1", "Deployment", "deployments", "1.16", "apps/v1"}
+	cronJobs := RemovedAPI{"batch/v1beta1", "CronJob", "cronjobs", "1.25", "batch/v1"}
+
+	It("lists the APIs removed by a version", func() {
+		apis, err := RemovedBy("1.16")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apis).To(ContainElement(deployments))
+		Expect(apis).NotTo(ContainElement(cronJobs))
+
+		apis, err = RemovedBy("1.26.3")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(apis).To(ContainElement(deployments))
+		Expect(apis).To(ContainElement(cronJobs))
+
+		_, err = RemovedBy("next")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("finds the objects last applied with a removed API", func() {
+		newObject := func(namespace, name, lastApplied string) unstructured.Unstructured {
+			o := unstructured.Unstructured{Object: map[string]interface{}{}}
+			o.SetNamespace(namespace)
+			o.SetName(name)
+			if lastApplied != "" {
+				o.SetAnnotations(map[string]string{"kubectl.kubernetes.io/last-applied-configuration": lastApplied})
+			}
+			return o
+		}
+
+		listed := map[schema.GroupVersionResource][]unstructured.Unstructured{
+			{Group: "extensions", Version: "v1beta1", Resource: "deployments"}: {
+				newObject("default", "web", `{"apiVersion":"extensions/v1beta1","kind":"Deployment"}`),
+				newObject("default", "api", `{"apiVersion":"apps/v1","kind":"Deployment"}`),
+				newObject("kube-system", "coredns", ""),
+			},
+		}
+		list := func(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+			objects, ok := listed[gvr]
+			if !ok {
+				return nil, apierrors.NewNotFound(gvr.GroupResource(), "")
+			}
+			return objects, nil
+		}
+
+		findings, err := ScanObjects(list, []RemovedAPI{deployments, cronJobs})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{
+			{API: deployments, Namespace: "default", Name: "web", Source: SourceLastAppliedConfiguration},
+		}))
+
+		failingList := func(gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+			return nil, fmt.Errorf("forbidden")
+		}
+		_, err = ScanObjects(failingList, []RemovedAPI{deployments})
+		Expect(err).To(MatchError("listing deployments in extensions/v1beta1: forbidden"))
+	})
+
+	It("finds the removed APIs the API server received requests for", func() {
+		metrics := `# HELP apiserver_requested_deprecated_apis [STABLE] Gauge of deprecated APIs that have been requested
+# TYPE apiserver_requested_deprecated_apis gauge
+apiserver_requested_deprecated_apis{group="batch",removed_release="1.25",resource="cronjobs",subresource="",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="batch",removed_release="1.25",resource="cronjobs",subresource="status",version="v1beta1"} 1
+apiserver_requested_deprecated_apis{group="example.com",removed_release="1.26",resource="widgets",subresource="",version="v1alpha1"} 1
+apiserver_requested_deprecated_apis{group="flowcontrol.apiserver.k8s.io",removed_release="1.29",resource="flowschemas",subresource="",version="v1beta2"} 1
+apiserver_request_total{code="200"} 10
+`
+		findings, err := ScanMetrics(metrics, "1.26")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{
+			{API: cronJobs, Source: SourceMetrics},
+			{API: RemovedAPI{GroupVersion: "example.com/v1alpha1", Resource: "widgets", RemovedIn: "1.26"}, Source: SourceMetrics},
+		}))
+		Expect(findings[1].API.Describe()).To(Equal("example.com/v1alpha1 widgets"))
+	})
+})
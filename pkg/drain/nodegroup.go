@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,9 +36,8 @@ func evictPods(drainer *Helper, node *corev1.Node) (int, error) {
 	return pending, nil
 }
 
-// NodeGroup drains a nodegroup
-func NodeGroup(clientSet kubernetes.Interface, ng eks.KubeNodeGroup, waitTimeout time.Duration, undo bool) error {
-	drainer := &Helper{
+func newDrainer(clientSet kubernetes.Interface) *Helper {
+	return &Helper{
 		Client: clientSet,
 
 		// TODO: Force, DeleteLocalData & IgnoreAllDaemonSets shouldn't
@@ -78,6 +77,11 @@ func NodeGroup(clientSet kubernetes.Interface, ng eks.KubeNodeGroup, waitTimeout
 			},
 		},
 	}
+}
+
+// NodeGroup drains a nodegroup
+func NodeGroup(clientSet kubernetes.Interface, ng eks.KubeNodeGroup, waitTimeout time.Duration, undo bool) error {
+	drainer := newDrainer(clientSet)
 
 	if err := drainer.CanUseEvictions(); err != nil {
 		return errors.Wrap(err, "checking if cluster implements policy API")
@@ -164,6 +168,50 @@ func NodeGroup(clientSet kubernetes.Interface, ng eks.KubeNodeGroup, waitTimeout
 	}
 }
 
+// Node cordons and drains a single node, it's used when nodes are replaced one at a time
+func Node(clientSet kubernetes.Interface, node *corev1.Node, waitTimeout time.Duration) error {
+	drainer := newDrainer(clientSet)
+
+	if err := drainer.CanUseEvictions(); err != nil {
+		return errors.Wrap(err, "checking if cluster implements policy API")
+	}
+
+	c := NewCordonHelper(node, true)
+	if c.IsUpdateRequired() {
+		err, patchErr := c.PatchOrReplace(clientSet)
+		if patchErr != nil {
+			logger.Warning(patchErr.Error())
+		}
+		if err != nil {
+			return errors.Wrapf(err, "cordoning node %q", node.Name)
+		}
+		logger.Info("cordon node %q", node.Name)
+	}
+
+	timer := time.NewTimer(waitTimeout)
+	defer timer.Stop()
+
+	for {
+		pending, err := evictPods(drainer, node)
+		if err == nil && pending == 0 {
+			logger.Success("drained node %q", node.Name)
+			return nil
+		}
+		if err != nil {
+			logger.Warning("pod eviction error (%q) on node %s – will retry after delay of %s", err, node.Name, retryDelay)
+		} else {
+			logger.Debug("%d pods to be evicted from %s", pending, node.Name)
+		}
+		retryTimer := time.NewTimer(retryDelay)
+		select {
+		case <-retryTimer.C:
+		case <-timer.C:
+			retryTimer.Stop()
+			return fmt.Errorf("timed out (after %s) waiting for node %q to be drained", waitTimeout, node.Name)
+		}
+	}
+}
+
 func cordonStatus(desired bool) string {
 	if desired {
 		return "cordon"
@@ -0,0 +1,62 @@
+package ebs
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+	awsprovider "k8s.io/legacy-cloud-providers/aws"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// Cleanup finds and deletes the detached EBS volumes that were dynamically
+// provisioned for PersistentVolumeClaims of the given cluster, which are tagged
+// as owned by it; volumes tagged as shared belong to other owners
+func Cleanup(ec2API ec2iface.EC2API, clusterName string) error {
+	clusterTagKey := awsprovider.TagNameKubernetesClusterPrefix + clusterName
+	input := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:" + clusterTagKey),
+				Values: []*string{aws.String(awsprovider.ResourceLifecycleOwned)},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []*string{aws.String(ec2.VolumeStateAvailable)},
+			},
+		},
+	}
+
+	var volumeIDs []string
+	err := ec2API.DescribeVolumesPages(input, func(output *ec2.DescribeVolumesOutput, _ bool) bool {
+		for _, volume := range output.Volumes {
+			if ownedByCluster(volume.Tags, clusterTagKey) {
+				volumeIDs = append(volumeIDs, aws.StringValue(volume.VolumeId))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list volumes of cluster %q", clusterName)
+	}
+
+	for _, volumeID := range volumeIDs {
+		logger.Info("deleting orphan EBS volume %q", volumeID)
+		if _, err := ec2API.DeleteVolume(&ec2.DeleteVolumeInput{
+			VolumeId: aws.String(volumeID),
+		}); err != nil {
+			return errors.Wrapf(err, "unable to delete volume %q", volumeID)
+		}
+	}
+	return nil
+}
+
+func ownedByCluster(tags []*ec2.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return aws.StringValue(tag.Value) == awsprovider.ResourceLifecycleOwned
+		}
+	}
+	return false
+}
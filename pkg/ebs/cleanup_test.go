@@ -0,0 +1,37 @@
+package ebs
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/eks/mocks"
+)
+
+var _ = Describe("EBS volumes cleanup", func() {
+	const clusterTagKey = "kubernetes.io/cluster/cluster-1"
+
+	It("only deletes the detached volumes owned by the cluster", func() {
+		ec2API := &mocks.EC2API{}
+		ec2API.On("DescribeVolumesPages", mock.MatchedBy(func(input *ec2.DescribeVolumesInput) bool {
+			return aws.StringValue(input.Filters[0].Name) == "tag:"+clusterTagKey &&
+				aws.StringValueSlice(input.Filters[0].Values)[0] == "owned"
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(*ec2.DescribeVolumesOutput, bool) bool)
+			consume(&ec2.DescribeVolumesOutput{
+				Volumes: []*ec2.Volume{
+					{VolumeId: aws.String("vol-owned"), Tags: []*ec2.Tag{{Key: aws.String(clusterTagKey), Value: aws.String("owned")}}},
+					{VolumeId: aws.String("vol-shared"), Tags: []*ec2.Tag{{Key: aws.String(clusterTagKey), Value: aws.String("shared")}}},
+				},
+			}, true)
+		}).Return(nil)
+		ec2API.On("DeleteVolume", mock.Anything).Return(&ec2.DeleteVolumeOutput{}, nil)
+
+		Expect(Cleanup(ec2API, "cluster-1")).To(Succeed())
+
+		Expect(ec2API.AssertNumberOfCalls(GinkgoT(), "DeleteVolume", 1)).To(BeTrue())
+		Expect(ec2API.AssertCalled(GinkgoT(), "DeleteVolume", &ec2.DeleteVolumeInput{VolumeId: aws.String("vol-owned")})).To(BeTrue())
+	})
+})
@@ -1,9 +1,11 @@
 package eks
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -13,6 +15,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudtrail"
@@ -27,11 +31,12 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -39,9 +44,12 @@ import (
 
 	"github.com/weaveworks/eksctl/pkg/ami"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/audit"
 	"github.com/weaveworks/eksctl/pkg/az"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils"
+	"github.com/weaveworks/eksctl/pkg/utils/oci"
 	"github.com/weaveworks/eksctl/pkg/version"
 )
 
@@ -51,6 +59,8 @@ type ClusterProvider struct {
 	Provider api.ClusterProvider
 	// informative fields, i.e. used as outputs
 	Status *ProviderStatus
+
+	session *session.Session
 }
 
 // ProviderServices stores the used APIs
@@ -66,6 +76,7 @@ type ProviderServices struct {
 	iam   iamiface.IAMAPI
 
 	cloudtrail cloudtrailiface.CloudTrailAPI
+	asg        autoscalingiface.AutoScalingAPI
 }
 
 // CloudFormation returns a representation of the CloudFormation API
@@ -98,6 +109,9 @@ func (p ProviderServices) IAM() iamiface.IAMAPI { return p.iam }
 // CloudTrail returns a representation of the CloudTrail API
 func (p ProviderServices) CloudTrail() cloudtrailiface.CloudTrailAPI { return p.cloudtrail }
 
+// ASG returns a representation of the AutoScaling API
+func (p ProviderServices) ASG() autoscalingiface.AutoScalingAPI { return p.asg }
+
 // Region returns provider-level region setting
 func (p ProviderServices) Region() string { return p.spec.Region }
 
@@ -107,6 +121,12 @@ func (p ProviderServices) Profile() string { return p.spec.Profile }
 // WaitTimeout returns provider-level duration after which any wait operation has to timeout
 func (p ProviderServices) WaitTimeout() time.Duration { return p.spec.WaitTimeout }
 
+// Timeout returns the duration after which the wait operations of a phase have to timeout
+func (p ProviderServices) Timeout(phase api.TimeoutPhase) time.Duration { return p.spec.Timeout(phase) }
+
+// StackOnFailure returns what happens to the stacks that fail to be created
+func (p ProviderServices) StackOnFailure() string { return p.spec.StackOnFailure }
+
 // ProviderStatus stores information about the used IAM role and the resulting session
 type ProviderStatus struct {
 	iamRoleARN   string
@@ -116,15 +136,41 @@ type ProviderStatus struct {
 
 // New creates a new setup of the used AWS APIs
 func New(spec *api.ProviderConfig, clusterSpec *api.ClusterConfig) *ClusterProvider {
-	provider := &ProviderServices{
-		spec: spec,
-	}
 	c := &ClusterProvider{
-		Provider: provider,
+		Provider: &ProviderServices{
+			spec: spec,
+		},
+	}
+	if clusterSpec != nil && clusterSpec.Metadata.AssumeRole != nil {
+		// the assume-role flags take precedence over the config file
+		spec.SetAssumeRole(clusterSpec.Metadata.AssumeRole)
 	}
 	// Create a new session and save credentials for possible
 	// later re-use if overriding sessions due to custom URL
 	s := c.newSession(spec)
+	c.session = s
+	c.Provider = newProviderServices(spec, s)
+
+	c.Status = &ProviderStatus{
+		sessionCreds: s.Config.Credentials,
+	}
+
+	if clusterSpec != nil {
+		clusterSpec.Metadata.Region = c.Provider.Region()
+		if clusterSpec.Timeouts != nil {
+			// flags take precedence over the timeouts of the config file
+			spec.SetPhaseTimeouts(clusterSpec.Timeouts.Phases())
+		}
+	}
+
+	return c
+}
+
+// newProviderServices creates the clients of the used AWS APIs with session s
+func newProviderServices(spec *api.ProviderConfig, s *session.Session) *ProviderServices {
+	provider := &ProviderServices{
+		spec: spec,
+	}
 
 	provider.cfn = cloudformation.New(s)
 	provider.eks = awseks.New(s)
@@ -143,10 +189,7 @@ func New(spec *api.ProviderConfig, clusterSpec *api.ClusterConfig) *ClusterProvi
 	provider.ssm = ssm.New(s)
 	provider.iam = iam.New(s)
 	provider.cloudtrail = cloudtrail.New(s)
-
-	c.Status = &ProviderStatus{
-		sessionCreds: s.Config.Credentials,
-	}
+	provider.asg = autoscaling.New(s)
 
 	// override sessions if any custom endpoints specified
 	if endpoint, ok := os.LookupEnv("AWS_CLOUDFORMATION_ENDPOINT"); ok {
@@ -184,19 +227,44 @@ func New(spec *api.ProviderConfig, clusterSpec *api.ClusterConfig) *ClusterProvi
 		logger.Debug("Setting CloudTrail endpoint to %s", endpoint)
 		provider.cloudtrail = cloudtrail.New(s, s.Config.Copy().WithEndpoint(endpoint))
 	}
-
-	if clusterSpec != nil {
-		clusterSpec.Metadata.Region = c.Provider.Region()
+	if endpoint, ok := os.LookupEnv("AWS_AUTOSCALING_ENDPOINT"); ok {
+		logger.Debug("Setting AutoScaling endpoint to %s", endpoint)
+		provider.asg = autoscaling.New(s, s.Config.Copy().WithEndpoint(endpoint))
 	}
 
-	return c
+	return provider
 }
 
 // LoadConfigFromFile loads ClusterConfig from configFile
 func LoadConfigFromFile(configFile string) (*api.ClusterConfig, error) {
-	data, err := readConfig(configFile)
+	return LoadConfigFromFileWithOptions(configFile, ConfigFileOptions{})
+}
+
+// LoadConfigFromFileWithOptions loads ClusterConfig from configFile, merging its documents and the
+// files it includes, substituting the variables and applying the overrides of options; unknown
+// fields are rejected unless options.NoStrict is set
+func LoadConfigFromFileWithOptions(configFile string, options ConfigFileOptions) (*api.ClusterConfig, error) {
+	substitute := func(data []byte) ([]byte, error) {
+		return options.Substitute(data, os.LookupEnv)
+	}
+	data, err := mergeConfigDocuments(configFile, readConfig, substitute)
 	if err != nil {
-		return nil, errors.Wrapf(err, "reading config file %q", configFile)
+		return nil, err
+	}
+
+	data, err = options.Override(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading config file %q", configFile)
+	}
+
+	data, err = resolveNodeGroupTemplates(data, oci.NewClient().Pull)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading config file %q", configFile)
+	}
+
+	data, err = convertConfig(data, configFile)
+	if err != nil {
+		return nil, err
 	}
 
 	// strict mode is not available in runtime.Decode, so we use the parser
@@ -205,7 +273,18 @@ func LoadConfigFromFile(configFile string) (*api.ClusterConfig, error) {
 	// NOTE: we must use sigs.k8s.io/yaml, as it behaves differently from
 	// github.com/ghodss/yaml, which didn't handle nested structs well
 	if err := yaml.UnmarshalStrict(data, &api.ClusterConfig{}); err != nil {
-		return nil, errors.Wrapf(err, "loading config file %q", configFile)
+		unknown := unknownFields(data)
+		switch {
+		case len(unknown) == 0:
+			return nil, errors.Wrapf(err, "loading config file %q", configFile)
+		case options.NoStrict:
+			for _, field := range unknown {
+				logger.Warning("ignoring unknown field %s in config file %q", field, configFile)
+			}
+		default:
+			return nil, fmt.Errorf("loading config file %q: unknown fields %s; use --no-strict to ignore them",
+				configFile, strings.Join(unknown, ", "))
+		}
 	}
 
 	obj, err := runtime.Decode(scheme.Codecs.UniversalDeserializer(), data)
@@ -220,6 +299,61 @@ func LoadConfigFromFile(configFile string) (*api.ClusterConfig, error) {
 	return cfg, nil
 }
 
+// convertConfig converts a config file of a previous version, or using deprecated fields, to the
+// current version, logging a warning for each change
+func convertConfig(data []byte, configFile string) ([]byte, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		// errors are reported when the config is decoded
+		return data, nil
+	}
+	changes, err := api.ConvertConfig(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting config file %q", configFile)
+	}
+	if len(changes) == 0 {
+		return data, nil
+	}
+	for _, change := range changes {
+		logger.Warning("%s", change)
+	}
+	logger.Warning("run 'eksctl utils convert-config -f %s' to convert config file %q to the current version", configFile, configFile)
+	return yaml.Marshal(config)
+}
+
+// ConvertConfigFile converts each document of a config file of a previous version, or using
+// deprecated fields, to the current version, without resolving its variables, includes and
+// templates; it returns the converted documents and a description of the changes
+func ConvertConfigFile(configFile string) ([]byte, []string, error) {
+	data, err := readConfig(configFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading config file %q", configFile)
+	}
+	documents, err := splitDocuments(data)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading config file %q", configFile)
+	}
+
+	var converted [][]byte
+	var changes []string
+	for i, document := range documents {
+		var config map[string]interface{}
+		if err := yaml.Unmarshal(document, &config); err != nil {
+			return nil, nil, errors.Wrapf(err, "parsing document %d of %q", i+1, configFile)
+		}
+		documentChanges, err := api.ConvertConfig(config)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "converting document %d of %q", i+1, configFile)
+		}
+		changes = append(changes, documentChanges...)
+		if document, err = yaml.Marshal(config); err != nil {
+			return nil, nil, err
+		}
+		converted = append(converted, document)
+	}
+	return bytes.Join(converted, []byte("---\n")), changes, nil
+}
+
 func readConfig(configFile string) ([]byte, error) {
 	if configFile == "-" {
 		return ioutil.ReadAll(os.Stdin)
@@ -266,25 +400,20 @@ func (c *ClusterProvider) CheckAuth() error {
 	return nil
 }
 
-// EnsureAMI ensures that the node AMI is set and is available
-func EnsureAMI(provider api.ClusterProvider, version string, ng *api.NodeGroup) error {
+// EnsureAMI ensures that the node AMI is set and is available, the resolved AMIs are cached in the
+// catalog at catalogPath, which is disabled when it's empty
+func EnsureAMI(provider api.ClusterProvider, version string, ng *api.NodeGroup, catalogPath string) error {
 	if api.IsAMI(ng.AMI) {
 		return ami.Use(provider.EC2(), ng)
 	}
 
-	var resolver ami.Resolver
-	switch ng.AMI {
-	case api.NodeImageResolverAuto:
-		resolver = ami.NewAutoResolver(provider.EC2())
-	case api.NodeImageResolverAutoSSM:
-		resolver = ami.NewSSMResolver(provider.SSM())
-	case api.NodeImageResolverStatic:
-		resolver = ami.NewStaticResolver()
-	default:
-		resolver = ami.NewMultiResolver(
-			ami.NewSSMResolver(provider.SSM()),
-			ami.NewAutoResolver(provider.EC2()),
-		)
+	resolver := NewAMIResolver(provider, ng.AMI)
+	if ng.AMI != api.NodeImageResolverStatic {
+		resolver = withCatalog(resolver, ng.AMI, catalogPath)
+	}
+	if ng.AMI == "" {
+		// the static AMIs are only used when AWS can't be queried
+		resolver = ami.NewFallbackResolver(resolver, ami.NewStaticResolver())
 	}
 
 	instanceType := selectInstanceType(ng)
@@ -301,6 +430,37 @@ func EnsureAMI(provider api.ClusterProvider, version string, ng *api.NodeGroup)
 	return ami.Use(provider.EC2(), ng)
 }
 
+// NewAMIResolver returns the resolver of the given kind, i.e. a value of nodeGroups[].ami that isn't
+// an AMI ID; AMIs are resolved with SSM and then EC2 when it's empty
+func NewAMIResolver(provider api.ClusterProvider, resolverKind string) ami.Resolver {
+	switch resolverKind {
+	case api.NodeImageResolverAuto:
+		return ami.NewAutoResolver(provider.EC2())
+	case api.NodeImageResolverAutoSSM:
+		return ami.NewSSMResolver(provider.SSM())
+	case api.NodeImageResolverStatic:
+		return ami.NewStaticResolver()
+	default:
+		return ami.NewMultiResolver(
+			ami.NewSSMResolver(provider.SSM()),
+			ami.NewAutoResolver(provider.EC2()),
+		)
+	}
+}
+
+// withCatalog caches the AMIs resolved by resolver in the catalog at path, unless it's empty
+func withCatalog(resolver ami.Resolver, resolverKind, path string) ami.Resolver {
+	if path == "" {
+		return resolver
+	}
+	catalog, err := ami.LoadCatalog(path)
+	if err != nil {
+		logger.Warning("not using the AMI catalog: %v", err)
+		return resolver
+	}
+	return ami.NewCachingResolver(resolver, resolverKind, catalog, ami.DefaultCatalogTTL, ami.IsOffline())
+}
+
 // selectInstanceType determines which instanceType is relevant for selecting an AMI
 // If the nodegroup has mixed instances it will prefer a GPU instance type over a general class one
 // This is to make sure that the AMI that is selected later is valid for all the types
@@ -392,6 +552,19 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 		Fn: request.MakeAddToUserAgentHandler(
 			"eksctl", version.String()),
 	})
+	if logger.Level >= apiCallStatsLevel {
+		DefaultAPICallStats.AddHandlers(&s.Handlers)
+	}
+	if audit.Default != nil {
+		audit.Default.AddHandlers(&s.Handlers)
+	}
+	if spec.MaxAPIQPS > 0 {
+		NewAPIRateLimiter(spec.MaxAPIQPS, spec.APIBurst).AddHandlers(&s.Handlers)
+	}
+
+	if len(spec.AssumeRoleARNs) > 0 {
+		s = s.Copy(&aws.Config{Credentials: NewAssumeRoleCredentials(s, spec)})
+	}
 
 	if spec.Region == "" {
 		if api.IsSetAndNonEmptyString(s.Config.Region) {
@@ -412,3 +585,9 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 func (c *ClusterProvider) NewStackManager(spec *api.ClusterConfig) *manager.StackCollection {
 	return manager.NewStackCollection(c.Provider, spec)
 }
+
+// NewS3Uploader returns an uploader of S3 objects that uses the session of the provider,
+// it's used to write the output of commands to S3
+func (c *ClusterProvider) NewS3Uploader() s3manageriface.UploaderAPI {
+	return s3manager.NewUploader(c.session)
+}
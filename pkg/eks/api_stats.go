@@ -0,0 +1,136 @@
+package eks
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// apiCallStatsLevel is the log level at which the stats of the AWS API calls are collected and logged
+const apiCallStatsLevel = 4
+
+// DefaultAPICallStats collects the AWS API calls of all the sessions created by eksctl, it's only
+// populated when the log level is at least 4, as the stats are meant to diagnose slow commands
+var DefaultAPICallStats = NewAPICallStats()
+
+// APICallStats aggregates the AWS API calls made by sessions, per service
+type APICallStats struct {
+	mu       sync.Mutex
+	services map[string]*serviceCallStats
+}
+
+type serviceCallStats struct {
+	calls     int
+	retries   int
+	throttles int
+	latencies []time.Duration
+}
+
+// NewAPICallStats returns empty stats
+func NewAPICallStats() *APICallStats {
+	return &APICallStats{
+		services: map[string]*serviceCallStats{},
+	}
+}
+
+// AddHandlers makes the requests sent with handlers count towards the stats; the latency
+// of a call includes its retries
+func (s *APICallStats) AddHandlers(handlers *request.Handlers) {
+	handlers.Retry.PushBackNamed(request.NamedHandler{
+		Name: "eksctlAPICallStatsRetry",
+		Fn: func(r *request.Request) {
+			if request.IsErrorThrottle(r.Error) {
+				s.update(r.ClientInfo.ServiceName, func(stats *serviceCallStats) {
+					stats.throttles++
+				})
+			}
+		},
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "eksctlAPICallStatsComplete",
+		Fn: func(r *request.Request) {
+			latency := time.Since(r.Time)
+			s.update(r.ClientInfo.ServiceName, func(stats *serviceCallStats) {
+				stats.calls++
+				stats.retries += r.RetryCount
+				stats.latencies = append(stats.latencies, latency)
+			})
+		},
+	})
+}
+
+func (s *APICallStats) update(service string, f func(*serviceCallStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats, ok := s.services[service]
+	if !ok {
+		stats = &serviceCallStats{}
+		s.services[service] = stats
+	}
+	f(stats)
+}
+
+// Print writes a table of the calls, retries, throttled responses and
+// 95th percentile latency of each service, sorted by service name
+func (s *APICallStats) Print(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.services))
+	for name := range s.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if _, err := fmt.Fprintf(w, "%-24s %8s %8s %10s %12s\n", "SERVICE", "CALLS", "RETRIES", "THROTTLES", "P95 LATENCY"); err != nil {
+		return err
+	}
+	for _, name := range names {
+		stats := s.services[name]
+		p95 := percentile(stats.latencies, 95).Round(time.Millisecond)
+		if _, err := fmt.Fprintf(w, "%-24s %8d %8d %10d %12s\n", name, stats.calls, stats.retries, stats.throttles, p95); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Log logs the stats at debug level, if any call was made
+func (s *APICallStats) Log() {
+	s.mu.Lock()
+	empty := len(s.services) == 0
+	s.mu.Unlock()
+	if empty || logger.Level < apiCallStatsLevel {
+		return
+	}
+
+	var out strings.Builder
+	if err := s.Print(&out); err != nil {
+		logger.Debug("ignoring error printing AWS API call stats: %s", err.Error())
+		return
+	}
+	logger.Debug("AWS API calls:\n%s", out.String())
+}
+
+// percentile returns the nearest-rank percentile of latencies
+func percentile(latencies []time.Duration, p int) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,55 @@
+package eks_test
+
+import (
+	"bytes"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("APICallStats", func() {
+	It("should count the calls and throttled responses of each service", func() {
+		s := session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-west-2"),
+			Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+			MaxRetries:  aws.Int(0),
+		}))
+		s.Handlers.Send.Clear()
+		s.Handlers.Unmarshal.Clear()
+		s.Handlers.UnmarshalMeta.Clear()
+		s.Handlers.UnmarshalError.Clear()
+		s.Handlers.ValidateResponse.Clear()
+
+		throttle := false
+		s.Handlers.Send.PushBack(func(r *request.Request) {
+			if throttle {
+				r.Error = awserr.New("Throttling", "Rate exceeded", nil)
+			}
+		})
+
+		stats := NewAPICallStats()
+		stats.AddHandlers(&s.Handlers)
+
+		stsAPI := sts.New(s)
+		_, err := stsAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		Expect(err).NotTo(HaveOccurred())
+		throttle = true
+		_, err = stsAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		Expect(err).To(HaveOccurred())
+
+		var out bytes.Buffer
+		Expect(stats.Print(&out)).To(Succeed())
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(2))
+		Expect(string(lines[0])).To(MatchRegexp(`^SERVICE\s+CALLS\s+RETRIES\s+THROTTLES\s+P95 LATENCY$`))
+		Expect(string(lines[1])).To(MatchRegexp(`^sts\s+2\s+0\s+1\s+\S+$`))
+	})
+})
@@ -1,6 +1,15 @@
 package eks_test
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ssm"
@@ -9,6 +18,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
 
+	"github.com/weaveworks/eksctl/pkg/ami"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 
 	. "github.com/weaveworks/eksctl/pkg/eks"
@@ -46,25 +56,60 @@ var _ = Describe("eksctl API", func() {
 		It("should reject unknown field in a YAML config", func() {
 			_, err := LoadConfigFromFile("testdata/bad-field-1.yaml")
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(HavePrefix(`loading config file "testdata/bad-field-1.yaml": error unmarshaling JSON: while decoding JSON: json: unknown field "zone"`))
+			Expect(err.Error()).To(Equal(`loading config file "testdata/bad-field-1.yaml": unknown fields metadata.zone; use --no-strict to ignore them`))
 		})
 
 		It("should reject unknown field in a YAML config", func() {
 			_, err := LoadConfigFromFile("testdata/bad-field-2.yaml")
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(HavePrefix(`loading config file "testdata/bad-field-2.yaml": error unmarshaling JSON: while decoding JSON: json: unknown field "bar"`))
+			Expect(err.Error()).To(Equal(`loading config file "testdata/bad-field-2.yaml": unknown fields nodeGroups[0].iam.withAddonPolicies.bar, ` +
+				`nodeGroups[0].iam.withAddonPolicies.foo (did you mean "fsx"?); use --no-strict to ignore them`))
 		})
 
 		It("should reject unknown field in a JSON config", func() {
 			_, err := LoadConfigFromFile("testdata/bad-field-1.json")
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(HavePrefix(`loading config file "testdata/bad-field-1.json": error unmarshaling JSON: while decoding JSON: json: unknown field "nodes"`))
+			Expect(err.Error()).To(Equal(`loading config file "testdata/bad-field-1.json": unknown fields nodeGroups[0].nodes; use --no-strict to ignore them`))
+		})
+
+		It("should suggest the closest field for misspelled fields, unless unknown fields are ignored", func() {
+			configFile, err := ioutil.TempFile("", "config-*.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(configFile.Name())
+			_, err = configFile.WriteString(`apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: cluster-1
+  regoin: us-west-2
+nodeGroups:
+  - name: ng-1
+    instanceTyp: m5.large
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configFile.Close()).To(Succeed())
+
+			_, err = LoadConfigFromFile(configFile.Name())
+			Expect(err).To(MatchError(fmt.Sprintf(`loading config file %q: unknown fields metadata.regoin (did you mean "region"?), `+
+				`nodeGroups[0].instanceTyp (did you mean "instanceType"?); use --no-strict to ignore them`, configFile.Name())))
+
+			cfg, err := LoadConfigFromFileWithOptions(configFile.Name(), ConfigFileOptions{NoStrict: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Metadata.Name).To(Equal("cluster-1"))
+			Expect(cfg.NodeGroups).To(HaveLen(1))
 		})
 
-		It("should reject old API version", func() {
-			_, err := LoadConfigFromFile("testdata/old-version.json")
+		It("should convert old API versions", func() {
+			cfg, err := LoadConfigFromFile("testdata/old-version.json")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.APIVersion).To(Equal(api.SchemeGroupVersion.String()))
+			Expect(cfg.NodeGroups).To(HaveLen(1))
+			Expect(*cfg.NodeGroups[0].DesiredCapacity).To(Equal(10))
+		})
+
+		It("should reject unknown API versions", func() {
+			_, err := LoadConfigFromFile("testdata/unknown-version.json")
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(HavePrefix(`loading config file "testdata/old-version.json": no kind "ClusterConfig" is registered for version "eksctl.io/v1alpha3" in scheme`))
+			Expect(err.Error()).To(HavePrefix(`loading config file "testdata/unknown-version.json": no kind "ClusterConfig" is registered for version "eksctl.io/v1beta1" in scheme`))
 		})
 
 		It("should error when cannot read a file", func() {
@@ -72,6 +117,46 @@ var _ = Describe("eksctl API", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(`reading config file "../../examples/nothing.xml": open ../../examples/nothing.xml: no such file or directory`))
 		})
+
+		It("should merge nodegroup templates from OCI registries", func() {
+			template := []byte("instanceType: m5.large\nlabels:\n  team: platform\n  tier: default\n")
+			templateDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(template))
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v2/org/ng-template/manifests/v1":
+					fmt.Fprintf(w, `{"layers": [{"mediaType": "application/yaml", "digest": %q}]}`, templateDigest)
+				case "/v2/org/ng-template/blobs/" + templateDigest:
+					_, _ = w.Write(template)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			configFile, err := ioutil.TempFile("", "config-*.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(configFile.Name())
+			_, err = fmt.Fprintf(configFile, `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: cluster-1
+  region: us-west-2
+nodeGroups:
+  - name: ng-1
+    template: oci://%s/org/ng-template:v1
+    labels:
+      tier: frontend
+`, strings.TrimPrefix(server.URL, "http://"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configFile.Close()).To(Succeed())
+
+			cfg, err := LoadConfigFromFile(configFile.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.NodeGroups).To(HaveLen(1))
+			Expect(cfg.NodeGroups[0].Name).To(Equal("ng-1"))
+			Expect(cfg.NodeGroups[0].InstanceType).To(Equal("m5.large"))
+			Expect(cfg.NodeGroups[0].Labels).To(Equal(map[string]string{"team": "platform", "tier": "frontend"}))
+		})
 	})
 
 	Context("Static AMI selection", func() {
@@ -94,7 +179,7 @@ var _ = Describe("eksctl API", func() {
 			ng.AMI = "static"
 			ng.InstanceType = "p2.xlarge"
 
-			err := EnsureAMI(provider, "1.12", ng)
+			err := EnsureAMI(provider, "1.12", ng, "")
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(ng.AMI).To(Equal("ami-02551cb499388bebb"))
@@ -103,7 +188,7 @@ var _ = Describe("eksctl API", func() {
 			ng.AMI = "static"
 			ng.InstanceType = "m5.xlarge"
 
-			err := EnsureAMI(provider, "1.12", ng)
+			err := EnsureAMI(provider, "1.12", ng, "")
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(ng.AMI).To(Equal("ami-0267968f4310157f1"))
@@ -115,7 +200,7 @@ var _ = Describe("eksctl API", func() {
 				InstanceTypes: []string{"t3.large", "m5.large", "m5a.large"},
 			}
 
-			err := EnsureAMI(provider, "1.12", ng)
+			err := EnsureAMI(provider, "1.12", ng, "")
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(ng.AMI).To(Equal("ami-0267968f4310157f1"))
@@ -127,7 +212,7 @@ var _ = Describe("eksctl API", func() {
 				InstanceTypes: []string{"t3.large", "m5.large", "m5a.large", "p3.2xlarge"},
 			}
 
-			err := EnsureAMI(provider, "1.12", ng)
+			err := EnsureAMI(provider, "1.12", ng, "")
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(ng.AMI).To(Equal("ami-02551cb499388bebb"))
@@ -136,11 +221,18 @@ var _ = Describe("eksctl API", func() {
 
 	Context("Dynamic AMI Resolution", func() {
 		var (
-			ng       *api.NodeGroup
-			provider *mockprovider.MockProvider
+			ng          *api.NodeGroup
+			provider    *mockprovider.MockProvider
+			catalogDir  string
+			catalogPath string
 		)
 
 		BeforeEach(func() {
+			var err error
+			catalogDir, err = ioutil.TempDir("", "catalog")
+			Expect(err).NotTo(HaveOccurred())
+			catalogPath = filepath.Join(catalogDir, "catalog.json")
+
 			ng = api.NewNodeGroup()
 			ng.AMIFamily = api.DefaultNodeImageFamily
 
@@ -151,8 +243,12 @@ var _ = Describe("eksctl API", func() {
 
 		})
 
+		AfterEach(func() {
+			Expect(os.RemoveAll(catalogDir)).To(Succeed())
+		})
+
 		testEnsureAMI := func(expectedAMI string) {
-			err := EnsureAMI(provider, "1.14", ng)
+			err := EnsureAMI(provider, "1.14", ng, catalogPath)
 			ExpectWithOffset(1, err).ToNot(HaveOccurred())
 			ExpectWithOffset(1, ng.AMI).To(Equal(expectedAMI))
 		}
@@ -169,19 +265,55 @@ var _ = Describe("eksctl API", func() {
 			testEnsureAMI("ami-ssm")
 		})
 
+		It("should use the AMIs of the catalog", func() {
+			provider.MockSSM().On("GetParameter", mock.Anything).Return(&ssm.GetParameterOutput{
+				Parameter: &ssm.Parameter{
+					Value: aws.String("ami-ssm"),
+				},
+			}, nil)
+
+			testEnsureAMI("ami-ssm")
+			ng.AMI = ""
+			testEnsureAMI("ami-ssm")
+			Expect(provider.MockSSM().AssertNumberOfCalls(GinkgoT(), "GetParameter", 1)).To(BeTrue())
+
+			Expect(os.Setenv(ami.OfflineEnvVar, "true")).To(Succeed())
+			defer os.Unsetenv(ami.OfflineEnvVar)
+			ng.AMI = ""
+			ng.InstanceType = "a1.large"
+			err := EnsureAMI(provider, "1.14", ng, catalogPath)
+			Expect(err).To(MatchError(ContainSubstring("no AMI in catalog")))
+		})
+
 		It("should use static resolution when specified", func() {
 			ng.AMI = "static"
 			testEnsureAMI("ami-0c13bb9cbfd007e56")
 		})
 
-		It("should fall back to auto resolution for Ubuntu", func() {
+		It("should resolve Ubuntu AMIs using SSM Parameter Store", func() {
 			ng.AMIFamily = api.NodeImageFamilyUbuntu1804
-			mockDescribeImages(provider, "ami-ubuntu", func(input *ec2.DescribeImagesInput) bool {
-				return *input.Owners[0] == "099720109477"
-			})
+			provider.MockSSM().On("GetParameter", &ssm.GetParameterInput{
+				Name: aws.String("/aws/service/canonical/ubuntu/eks/18.04/1.14/stable/current/amd64/hvm/ebs-gp2/ami-id"),
+			}).Return(&ssm.GetParameterOutput{
+				Parameter: &ssm.Parameter{
+					Value: aws.String("ami-ubuntu"),
+				},
+			}, nil)
 			testEnsureAMI("ami-ubuntu")
 		})
 
+		It("should fall back to the static AMIs when AWS can't be queried", func() {
+			provider.MockSSM().On("GetParameter", mock.Anything).Return(nil, fmt.Errorf("no network"))
+			testEnsureAMI("ami-0c13bb9cbfd007e56")
+		})
+
+		It("should not fall back to the static AMIs when AMI is auto-ssm", func() {
+			ng.AMI = "auto-ssm"
+			provider.MockSSM().On("GetParameter", mock.Anything).Return(nil, fmt.Errorf("no network"))
+			err := EnsureAMI(provider, "1.14", ng, catalogPath)
+			Expect(err).To(MatchError(ContainSubstring("no network")))
+		})
+
 		It("should retrieve the AMI from EC2 when AMI is auto", func() {
 			ng.AMI = "auto"
 			ng.InstanceType = "p2.xlarge"
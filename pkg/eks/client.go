@@ -1,20 +1,25 @@
 package eks
 
 import (
+	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/pkg/errors"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/audit"
 	kubewrapper "github.com/weaveworks/eksctl/pkg/kubernetes"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 )
@@ -48,6 +53,26 @@ func (c *ClusterProvider) GetUsername() string {
 	return "iam-root-account"
 }
 
+// GetAccountID extracts the account ID from the IAM role ARN
+func (c *ClusterProvider) GetAccountID() string {
+	parsed, err := arn.Parse(c.Status.iamRoleARN)
+	if err != nil {
+		return ""
+	}
+	return parsed.AccountID
+}
+
+// KubeconfigContextName returns the name of the kubeconfig context of a cluster from a
+// context name template, see kubeconfig.ContextName
+func (c *ClusterProvider) KubeconfigContextName(template string, meta *api.ClusterMeta) string {
+	return kubeconfig.ContextName(template, kubeconfig.ContextNameFields{
+		Cluster: meta.Name,
+		Region:  meta.Region,
+		Account: c.GetAccountID(),
+		User:    c.GetUsername(),
+	})
+}
+
 func (c *Client) new(spec *api.ClusterConfig, stsClient stsiface.STSAPI) (*Client, error) {
 	if err := c.useEmbeddedToken(spec, stsClient); err != nil {
 		return nil, err
@@ -57,6 +82,9 @@ func (c *Client) new(spec *api.ClusterConfig, stsClient stsiface.STSAPI) (*Clien
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create API client configuration from client config")
 	}
+	if audit.Default != nil {
+		rawConfig.Wrap(audit.Default.WrapTransport)
+	}
 	c.rawConfig = rawConfig
 
 	return c, nil
@@ -86,6 +114,30 @@ func (c *Client) NewClientSet() (*kubernetes.Clientset, error) {
 	return client, nil
 }
 
+// NewDynamicClient creates a new API client for objects of any kind
+func (c *Client) NewDynamicClient() (dynamic.Interface, error) {
+	client, err := dynamic.NewForConfig(c.rawConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic API client")
+	}
+	return client, nil
+}
+
+// WriteTempKubeconfig writes the config of the client to a temporary kubeconfig, for tools that read the
+// credentials of the cluster from a kubeconfig; as it embeds a token, the caller must remove it once used
+func (c *Client) WriteTempKubeconfig(prefix string) (string, error) {
+	kubeconfigFile, err := ioutil.TempFile("", prefix)
+	if err != nil {
+		return "", errors.Wrap(err, "creating temporary kubeconfig")
+	}
+	kubeconfigFile.Close()
+	if err := clientcmd.WriteToFile(*c.Config, kubeconfigFile.Name()); err != nil {
+		os.Remove(kubeconfigFile.Name())
+		return "", errors.Wrap(err, "writing temporary kubeconfig")
+	}
+	return kubeconfigFile.Name(), nil
+}
+
 // NewStdClientSet creates a new API client in one go with an embedded STS token, this is most commonly used option
 func (c *ClusterProvider) NewStdClientSet(spec *api.ClusterConfig) (*kubernetes.Clientset, error) {
 	_, clientSet, err := c.newClientSetWithEmbeddedToken(spec)
@@ -110,6 +162,16 @@ func (c *ClusterProvider) newClientSetWithEmbeddedToken(spec *api.ClusterConfig)
 	return client, clientSet, nil
 }
 
+// NewStdDynamicClient creates a new dynamic API client in one go with an embedded STS token
+func (c *ClusterProvider) NewStdDynamicClient(spec *api.ClusterConfig) (dynamic.Interface, error) {
+	client, err := c.NewClient(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Kubernetes client config with embedded token")
+	}
+
+	return client.NewDynamicClient()
+}
+
 // NewRawClient creates a new raw REST client in one go with an embedded STS token
 func (c *ClusterProvider) NewRawClient(spec *api.ClusterConfig) (*kubewrapper.RawClient, error) {
 	client, clientSet, err := c.newClientSetWithEmbeddedToken(spec)
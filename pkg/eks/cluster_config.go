@@ -0,0 +1,470 @@
+package eks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+// GetClusterConfig reconstructs the ClusterConfig of an existing cluster from the
+// EKS API and the CloudFormation stacks of the cluster; resources created by eksctl
+// (VPC, security groups, IAM roles) are omitted, so that the result can be passed to
+// `eksctl create cluster -f` to create a copy of the cluster
+func (c *ClusterProvider) GetClusterConfig(meta *api.ClusterMeta) (*api.ClusterConfig, error) {
+	cfg := api.NewClusterConfig()
+	cfg.Metadata.Name = meta.Name
+	cfg.Metadata.Region = meta.Region
+
+	if err := c.RefreshClusterStatus(cfg); err != nil {
+		return nil, err
+	}
+	cluster := c.Status.clusterInfo.cluster
+	if *cluster.Status != awseks.ClusterStatusActive {
+		return nil, errors.Errorf("cluster %q is in %q state, only active clusters can be described", meta.Name, *cluster.Status)
+	}
+
+	cfg.Metadata.Version = *cluster.Version
+	cfg.Metadata.Tags = userTags(cluster.Tags)
+
+	stackManager := c.NewStackManager(cfg)
+	clusterStack, err := stackManager.DescribeClusterStack()
+	if err != nil {
+		// clusters that were not created by eksctl have no stacks
+		logger.Debug("describing stack of cluster %q: %v", meta.Name, err)
+	}
+
+	var (
+		ownedStack      *manager.Stack
+		clusterTemplate string
+	)
+	if clusterStack != nil && !manager.IsRegisteredClusterStack(clusterStack) {
+		ownedStack = clusterStack
+		if clusterTemplate, err = stackManager.GetStackTemplate(*clusterStack.StackName); err != nil {
+			return nil, errors.Wrapf(err, "getting template of stack %q", *clusterStack.StackName)
+		}
+	}
+	if err := c.loadClusterNetworking(cfg, ownedStack, clusterTemplate, cluster); err != nil {
+		return nil, err
+	}
+
+	enabledLogTypes, _, err := c.GetCurrentClusterConfigForLogging(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.CloudWatch.ClusterLogging.EnableTypes = enabledLogTypes.List()
+
+	if !gjson.Get(clusterTemplate, "Resources.ServiceRole").Exists() {
+		cfg.IAM.ServiceRoleARN = cluster.RoleArn
+	}
+
+	oidc, err := c.NewOpenIDConnectManager(cfg)
+	if err != nil {
+		logger.Debug("checking OIDC provider of cluster %q: %v", meta.Name, err)
+	} else if exists, err := oidc.CheckProviderExists(); err != nil {
+		return nil, errors.Wrapf(err, "checking OIDC provider of cluster %q", meta.Name)
+	} else if exists {
+		cfg.IAM.WithOIDC = api.Enabled()
+	}
+
+	for _, encryption := range cluster.EncryptionConfig {
+		if encryption.Provider != nil && encryption.Provider.KeyArn != nil {
+			cfg.SecretsEncryption = &api.SecretsEncryption{KeyARN: encryption.Provider.KeyArn}
+		}
+	}
+
+	if clusterStack != nil {
+		if err := c.loadNodeGroups(cfg, stackManager); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, _ := ClusterSupportsFargate(cluster); ok {
+		profiles, err := fargate.NewClient(meta.Name, c.Provider.EKS()).ReadProfiles()
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting Fargate profiles of cluster %q", meta.Name)
+		}
+		for _, profile := range profiles {
+			profile.PodExecutionRoleARN = ""
+		}
+		cfg.FargateProfiles = profiles
+	}
+
+	cfg.Status = nil
+	return cfg, nil
+}
+
+// loadClusterNetworking sets the VPC and endpoint configuration of cfg from the
+// stack of the cluster, or from the EKS API if the stack was not created by eksctl;
+// a VPC created by the cluster stack is described by its CIDR and NAT mode only,
+// while the subnets of any other VPC are referenced by their IDs
+func (c *ClusterProvider) loadClusterNetworking(cfg *api.ClusterConfig, stack *manager.Stack, template string, cluster *awseks.Cluster) error {
+	if stack == nil {
+		if err := vpc.UseFromUnownedCluster(c.NewVPCLookupProvider(cfg), cluster, cfg); err != nil {
+			return err
+		}
+		// the cluster security group is created by EKS for each cluster
+		cfg.VPC.SharedNodeSecurityGroup = ""
+		if cfg.VPC.SecurityGroup == aws.StringValue(cluster.ResourcesVpcConfig.ClusterSecurityGroupId) {
+			cfg.VPC.SecurityGroup = ""
+		}
+	} else if err := vpc.UseFromCluster(c.NewVPCLookupProvider(cfg), stack, cfg); err != nil {
+		return err
+	}
+
+	vpcConfig := cluster.ResourcesVpcConfig
+	cfg.VPC.ClusterEndpoints = &api.ClusterEndpoints{
+		PublicAccess:  vpcConfig.EndpointPublicAccess,
+		PrivateAccess: vpcConfig.EndpointPrivateAccess,
+	}
+	if cidrs := aws.StringValueSlice(vpcConfig.PublicAccessCidrs); !(len(cidrs) == 1 && cidrs[0] == "0.0.0.0/0") {
+		cfg.VPC.PublicAccessCIDRs = cidrs
+	}
+
+	if stack == nil {
+		return nil
+	}
+
+	if gjson.Get(template, "Resources.ControlPlaneSecurityGroup").Exists() {
+		cfg.VPC.SecurityGroup = ""
+	}
+	if gjson.Get(template, "Resources.ClusterSharedNodeSecurityGroup").Exists() {
+		cfg.VPC.SharedNodeSecurityGroup = ""
+	}
+
+	if gjson.Get(template, "Resources.VPC").Exists() {
+		// the VPC is re-created from its CIDR, the subnets are derived from it
+		cfg.VPC.ID = ""
+		cfg.VPC.Subnets = nil
+		cfg.VPC.NAT = nil
+		optionalCollectors := map[string]outputs.Collector{
+			outputs.ClusterFeatureNATMode: func(v string) error {
+				cfg.VPC.NAT = &api.ClusterNAT{Gateway: &v}
+				return nil
+			},
+		}
+		if err := outputs.Collect(*stack, nil, optionalCollectors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadNodeGroups adds the nodegroups that were created by eksctl to cfg
+func (c *ClusterProvider) loadNodeGroups(cfg *api.ClusterConfig, stackManager *manager.StackCollection) error {
+	nodeGroupStacks, err := stackManager.ListNodeGroupStacks()
+	if err != nil {
+		return errors.Wrapf(err, "listing nodegroups of cluster %q", cfg.Metadata.Name)
+	}
+	if len(nodeGroupStacks) == 0 {
+		return nil
+	}
+
+	summaries, err := stackManager.GetNodeGroupSummaries("")
+	if err != nil {
+		return err
+	}
+	summaryByName := map[string]*manager.NodeGroupSummary{}
+	for _, summary := range summaries {
+		summaryByName[summary.Name] = summary
+	}
+
+	for _, s := range nodeGroupStacks {
+		if s.Type == api.NodeGroupTypeManaged {
+			ng, err := c.describeManagedNodeGroup(cfg.Metadata.Name, s.NodeGroupName)
+			if err != nil {
+				return err
+			}
+			cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, ng)
+			continue
+		}
+
+		summary, ok := summaryByName[s.NodeGroupName]
+		if !ok {
+			continue
+		}
+		cfg.NodeGroups = append(cfg.NodeGroups, newNodeGroupFromSummary(summary))
+	}
+	return nil
+}
+
+// LoadNodeGroup adds the nodegroup of the given name, created by eksctl, to cfg, so that it can
+// be used as a template for a new nodegroup; unlike GetClusterConfig, the settings of a
+// self-managed nodegroup are read from the template of its stack
+func (c *ClusterProvider) LoadNodeGroup(cfg *api.ClusterConfig, stackManager *manager.StackCollection, name string) error {
+	nodeGroupType, err := stackManager.GetNodeGroupStackType(name)
+	if err != nil {
+		return errors.Wrapf(err, "getting nodegroup %q of cluster %q, only nodegroups created by eksctl can be loaded", name, cfg.Metadata.Name)
+	}
+
+	if nodeGroupType == api.NodeGroupTypeManaged {
+		ng, err := c.describeManagedNodeGroup(cfg.Metadata.Name, name)
+		if err != nil {
+			return err
+		}
+		cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, ng)
+		return nil
+	}
+
+	summaries, err := stackManager.GetNodeGroupSummaries(name)
+	if err != nil {
+		return err
+	}
+	if len(summaries) == 0 {
+		return fmt.Errorf("nodegroup %q not found in cluster %q", name, cfg.Metadata.Name)
+	}
+	template, err := stackManager.GetNodeGroupTemplate(name)
+	if err != nil {
+		return errors.Wrapf(err, "getting template of nodegroup %q", name)
+	}
+
+	ng := newNodeGroupFromSummary(summaries[0])
+	if err := setNodeGroupFromTemplate(cfg, ng, template); err != nil {
+		return err
+	}
+	cfg.NodeGroups = append(cfg.NodeGroups, ng)
+	return nil
+}
+
+func newNodeGroupFromSummary(summary *manager.NodeGroupSummary) *api.NodeGroup {
+	ng := api.NewNodeGroup()
+	ng.Name = summary.Name
+	ng.InstanceType = summary.InstanceType
+	ng.DesiredCapacity = aws.Int(summary.DesiredCapacity)
+	ng.MinSize = aws.Int(summary.MinSize)
+	ng.MaxSize = aws.Int(summary.MaxSize)
+	return ng
+}
+
+// amiFamilyPattern matches the AMI family in the description of a nodegroup template
+var amiFamilyPattern = regexp.MustCompile(`\(AMI family: ([^,]+),`)
+
+// addonPolicyResources are the resources of the policies attached to the instance role
+// created by eksctl for each IAM add-on policy of a nodegroup
+var addonPolicyResources = map[string]func(*api.NodeGroupIAMAddonPolicies) **bool{
+	"PolicyAutoScaling":          func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.AutoScaler },
+	"PolicyCertManagerChangeSet": func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.CertManager },
+	"PolicyExternalDNSChangeSet": func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.ExternalDNS },
+	"PolicyAppMesh":              func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.AppMesh },
+	"PolicyEBS":                  func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.EBS },
+	"PolicyFSX":                  func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.FSX },
+	"PolicyEFS":                  func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.EFS },
+	"PolicyALBIngress":           func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.ALBIngress },
+	"PolicyXRay":                 func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.XRay },
+}
+
+// addonManagedPolicies are the AWS managed policies attached to the instance role created
+// by eksctl for IAM add-on policies of a nodegroup
+var addonManagedPolicies = map[string]func(*api.NodeGroupIAMAddonPolicies) **bool{
+	"AmazonEC2ContainerRegistryPowerUser": func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.ImageBuilder },
+	"CloudWatchAgentServerPolicy":         func(p *api.NodeGroupIAMAddonPolicies) **bool { return &p.CloudWatch },
+}
+
+// setNodeGroupFromTemplate sets the fields of a self-managed nodegroup that are
+// only found in the template of its stack
+func setNodeGroupFromTemplate(cfg *api.ClusterConfig, ng *api.NodeGroup, template string) error {
+	if match := amiFamilyPattern.FindStringSubmatch(gjson.Get(template, "Description").String()); match != nil {
+		ng.AMIFamily = match[1]
+	}
+
+	launchTemplateData := gjson.Get(template, "Resources.NodeGroupLaunchTemplate.Properties.LaunchTemplateData")
+	if imageID := launchTemplateData.Get("ImageId"); imageID.Type == gjson.String {
+		ng.AMI = imageID.String()
+	}
+	if keyName := launchTemplateData.Get("KeyName"); keyName.Type == gjson.String {
+		ng.SSH.Allow = api.Enabled()
+		ng.SSH.PublicKeyName = aws.String(keyName.String())
+		ng.SSH.PublicKeyPath = nil
+	}
+	ebs := launchTemplateData.Get("BlockDeviceMappings.0.Ebs")
+	if volumeSize := ebs.Get("VolumeSize"); volumeSize.Exists() {
+		ng.VolumeSize = aws.Int(int(volumeSize.Int()))
+	}
+	if volumeType := ebs.Get("VolumeType"); volumeType.Type == gjson.String {
+		ng.VolumeType = aws.String(volumeType.String())
+	}
+
+	featureOutput := func(name string) bool {
+		return gjson.Get(template, "Outputs."+name+".Value").Bool()
+	}
+	ng.PrivateNetworking = featureOutput(outputs.NodeGroupFeaturePrivateNetworking)
+	ng.SecurityGroups.WithShared = aws.Bool(featureOutput(outputs.NodeGroupFeatureSharedSecurityGroup))
+	ng.SecurityGroups.WithLocal = aws.Bool(featureOutput(outputs.NodeGroupFeatureLocalSecurityGroup))
+	// the shared and local security groups are references, only the attached ones are IDs
+	for _, group := range launchTemplateData.Get("NetworkInterfaces.0.Groups").Array() {
+		if group.Type == gjson.String {
+			ng.SecurityGroups.AttachIDs = append(ng.SecurityGroups.AttachIDs, group.String())
+		}
+	}
+
+	for _, tagSpecification := range launchTemplateData.Get("TagSpecifications").Array() {
+		if tagSpecification.Get("ResourceType").String() != "instance" {
+			continue
+		}
+		for _, tag := range tagSpecification.Get("Tags").Array() {
+			key, value := tag.Get("Key").String(), tag.Get("Value").String()
+			// the tags of the cluster are added to the tags of the nodegroup
+			if clusterValue, ok := cfg.Metadata.Tags[key]; ok && clusterValue == value {
+				continue
+			}
+			if ng.Tags == nil {
+				ng.Tags = map[string]string{}
+			}
+			ng.Tags[key] = value
+		}
+	}
+
+	asg := gjson.Get(template, "Resources.NodeGroup.Properties")
+	// subnets are references to the outputs of the cluster stack unless they were chosen
+	// with subnets or availabilityZones, the IDs of the subnets being used in both cases
+	if subnets := asg.Get("VPCZoneIdentifier"); subnets.IsArray() {
+		for _, subnet := range subnets.Array() {
+			ng.Subnets = append(ng.Subnets, subnet.String())
+		}
+	}
+	for _, tag := range asg.Get("Tags").Array() {
+		key, value := tag.Get("Key").String(), tag.Get("Value").String()
+		switch {
+		case key == api.NodeTemplateLabelTagPrefix+api.TopologyZoneLabel, key == api.NodeTemplateLabelTagPrefix+api.FailureDomainZoneLabel:
+			// added for cluster-autoscaler, the nodes get these labels from the cloud provider
+		case strings.HasPrefix(key, api.NodeTemplateLabelTagPrefix):
+			if ng.Labels == nil {
+				ng.Labels = map[string]string{}
+			}
+			ng.Labels[strings.TrimPrefix(key, api.NodeTemplateLabelTagPrefix)] = value
+		case strings.HasPrefix(key, api.NodeTemplateTaintTagPrefix):
+			if ng.Taints == nil {
+				ng.Taints = map[string]string{}
+			}
+			ng.Taints[strings.TrimPrefix(key, api.NodeTemplateTaintTagPrefix)] = value
+		}
+	}
+
+	setNodeGroupIAMFromTemplate(ng, template)
+
+	// stacks created before the node template tags were added to the ASG only have
+	// the labels and taints in the user data
+	if userData := launchTemplateData.Get("UserData"); userData.Type == gjson.String {
+		if err := nodebootstrap.SetNodeGroupFromUserData(cfg, ng, userData.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setNodeGroupIAMFromTemplate sets the IAM settings of a self-managed nodegroup; the instance
+// profile and role are only copied when they were given, as the ones created by eksctl are
+// deleted with the stack of the nodegroup, their policies and permissions boundary being
+// copied instead
+func setNodeGroupIAMFromTemplate(ng *api.NodeGroup, template string) {
+	resources := gjson.Get(template, "Resources")
+	if profileARN := gjson.Get(template, "Resources.NodeGroupLaunchTemplate.Properties.LaunchTemplateData.IamInstanceProfile.Arn"); profileARN.Type == gjson.String {
+		ng.IAM.InstanceProfileARN = profileARN.String()
+		if roleARN := gjson.Get(template, "Outputs."+outputs.NodeGroupInstanceRoleARN+".Value"); roleARN.Type == gjson.String {
+			ng.IAM.InstanceRoleARN = roleARN.String()
+		}
+		return
+	}
+
+	if roleARN := resources.Get("NodeInstanceProfile.Properties.Roles.0"); roleARN.Type == gjson.String {
+		ng.IAM.InstanceRoleARN = roleARN.String()
+		return
+	}
+
+	role := resources.Get("NodeInstanceRole.Properties")
+	if !role.Exists() {
+		return
+	}
+	if boundary := role.Get("PermissionsBoundary"); boundary.Type == gjson.String {
+		ng.IAM.InstanceRolePermissionsBoundary = boundary.String()
+	}
+	for _, policyARN := range role.Get("ManagedPolicyArns").Array() {
+		// the policies attached by default and for add-ons are AWS managed policies in the partition of the stack
+		if policyARN.Type == gjson.String {
+			ng.IAM.AttachPolicyARNs = append(ng.IAM.AttachPolicyARNs, policyARN.String())
+			continue
+		}
+		policyName := policyARN.Get("Fn::Sub").String()
+		policyName = policyName[strings.LastIndex(policyName, "/")+1:]
+		if addonPolicy, ok := addonManagedPolicies[policyName]; ok {
+			*addonPolicy(&ng.IAM.WithAddonPolicies) = api.Enabled()
+		} else if policyName == "AmazonSSMManagedInstanceCore" {
+			ng.SSH.EnableSSM = api.Enabled()
+		}
+	}
+	for name, addonPolicy := range addonPolicyResources {
+		if resources.Get(name).Exists() {
+			*addonPolicy(&ng.IAM.WithAddonPolicies) = api.Enabled()
+		}
+	}
+	// external-dns shares the hosted zones policy of cert-manager when both are enabled
+	for _, action := range resources.Get("PolicyCertManagerHostedZones.Properties.PolicyDocument.Statement.0.Action").Array() {
+		if action.String() == "route53:ListTagsForResource" {
+			ng.IAM.WithAddonPolicies.ExternalDNS = api.Enabled()
+		}
+	}
+}
+
+func (c *ClusterProvider) describeManagedNodeGroup(clusterName, name string) (*api.ManagedNodeGroup, error) {
+	output, err := c.Provider.EKS().DescribeNodegroup(&awseks.DescribeNodegroupInput{
+		ClusterName:   &clusterName,
+		NodegroupName: &name,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing managed nodegroup %q", name)
+	}
+	nodeGroup := output.Nodegroup
+
+	ng := api.NewManagedNodeGroup()
+	ng.Name = name
+	ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+	if len(nodeGroup.InstanceTypes) > 0 {
+		ng.InstanceType = *nodeGroup.InstanceTypes[0]
+	}
+	if nodeGroup.DiskSize != nil {
+		ng.VolumeSize = aws.Int(int(*nodeGroup.DiskSize))
+	}
+	if scaling := nodeGroup.ScalingConfig; scaling != nil {
+		ng.DesiredCapacity = aws.Int(int(aws.Int64Value(scaling.DesiredSize)))
+		ng.MinSize = aws.Int(int(aws.Int64Value(scaling.MinSize)))
+		ng.MaxSize = aws.Int(int(aws.Int64Value(scaling.MaxSize)))
+	}
+	if remoteAccess := nodeGroup.RemoteAccess; remoteAccess != nil && remoteAccess.Ec2SshKey != nil {
+		ng.SSH.Allow = api.Enabled()
+		ng.SSH.PublicKeyName = remoteAccess.Ec2SshKey
+		ng.SSH.SourceSecurityGroupIDs = aws.StringValueSlice(remoteAccess.SourceSecurityGroups)
+	}
+	if len(nodeGroup.Labels) > 0 {
+		ng.Labels = aws.StringValueMap(nodeGroup.Labels)
+	}
+	ng.Tags = userTags(nodeGroup.Tags)
+	return ng, nil
+}
+
+// userTags returns the tags that were set by the user, omitting the ones
+// that are added by AWS and eksctl
+func userTags(tags map[string]*string) map[string]string {
+	var result map[string]string
+	for k, v := range tags {
+		if strings.HasPrefix(k, "aws:") || strings.HasPrefix(k, "alpha.eksctl.io/") || strings.HasPrefix(k, "eksctl.") {
+			continue
+		}
+		if result == nil {
+			result = map[string]string{}
+		}
+		result[k] = aws.StringValue(v)
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package eks
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("loading a nodegroup", func() {
+	It("reads back the settings of a self-managed nodegroup from the template of its stack", func() {
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		cfg.Metadata.Region = "us-west-2"
+		cfg.Metadata.Tags = map[string]string{"team": "platform"}
+		*cfg.VPC.CIDR = api.DefaultCIDR()
+		cfg.Status = &api.ClusterStatus{
+			Endpoint:                 "https://test.example.com",
+			CertificateAuthorityData: []byte("CertificateAuthorityData"),
+		}
+
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.InstanceType = "m5.large"
+		ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+		ng.AMI = "ami-123"
+		ng.Labels = map[string]string{"role": "workers"}
+		ng.Taints = map[string]string{"dedicated": "workers:NoSchedule"}
+		ng.Tags = map[string]string{"cost-center": "42"}
+		ng.Subnets = []string{"subnet-1", "subnet-2"}
+		ng.SecurityGroups.AttachIDs = []string{"sg-1"}
+		ng.PreBootstrapCommands = []string{"echo hello"}
+		ng.KubeletExtraConfig = &api.InlineDocument{"kubeAPIBurst": float64(20)}
+		ng.IAM.InstanceRolePermissionsBoundary = "arn:aws:iam::123456789012:policy/boundary"
+		ng.IAM.WithAddonPolicies.AutoScaler = api.Enabled()
+		ng.IAM.WithAddonPolicies.ImageBuilder = api.Enabled()
+		api.SetNodeGroupDefaults(ng, cfg.Metadata)
+
+		resourceSet := builder.NewNodeGroupResourceSet(mockprovider.NewMockProvider(), cfg, "eksctl-test-cluster", ng, false)
+		Expect(resourceSet.AddAllResources()).To(Succeed())
+		template, err := resourceSet.RenderJSON()
+		Expect(err).NotTo(HaveOccurred())
+
+		loaded := newNodeGroupFromSummary(&manager.NodeGroupSummary{Name: ng.Name, InstanceType: ng.InstanceType})
+		Expect(setNodeGroupFromTemplate(cfg, loaded, string(template))).To(Succeed())
+
+		Expect(loaded.AMIFamily).To(Equal(ng.AMIFamily))
+		Expect(loaded.AMI).To(Equal(ng.AMI))
+		Expect(loaded.Labels).To(HaveKeyWithValue("role", "workers"))
+		Expect(loaded.Taints).To(Equal(ng.Taints))
+		Expect(loaded.Tags).To(Equal(ng.Tags))
+		Expect(loaded.Subnets).To(Equal(ng.Subnets))
+		Expect(loaded.SecurityGroups.AttachIDs).To(Equal([]string{"sg-1"}))
+		Expect(*loaded.SecurityGroups.WithShared).To(BeTrue())
+		Expect(*loaded.SecurityGroups.WithLocal).To(BeTrue())
+		Expect(loaded.PreBootstrapCommands).To(Equal(ng.PreBootstrapCommands))
+		Expect(loaded.KubeletExtraConfig).To(Equal(ng.KubeletExtraConfig))
+		Expect(loaded.IAM.InstanceProfileARN).To(BeEmpty())
+		Expect(loaded.IAM.InstanceRoleARN).To(BeEmpty())
+		Expect(loaded.IAM.InstanceRolePermissionsBoundary).To(Equal(ng.IAM.InstanceRolePermissionsBoundary))
+		Expect(loaded.IAM.AttachPolicyARNs).To(BeEmpty())
+		Expect(*loaded.IAM.WithAddonPolicies.AutoScaler).To(BeTrue())
+		Expect(*loaded.IAM.WithAddonPolicies.ImageBuilder).To(BeTrue())
+		Expect(*loaded.IAM.WithAddonPolicies.EBS).To(BeFalse())
+	})
+
+	It("keeps the instance profile and role that were given", func() {
+		const template = `{
+  "Resources": {
+    "NodeGroupLaunchTemplate": {
+      "Properties": {
+        "LaunchTemplateData": {
+          "IamInstanceProfile": {"Arn": "arn:aws:iam::123456789012:instance-profile/nodes"}
+        }
+      }
+    }
+  },
+  "Outputs": {
+    "InstanceRoleARN": {"Value": "arn:aws:iam::123456789012:role/nodes"}
+  }
+}`
+		loaded := newNodeGroupFromSummary(&manager.NodeGroupSummary{Name: "ng-1"})
+		Expect(setNodeGroupFromTemplate(api.NewClusterConfig(), loaded, template)).To(Succeed())
+		Expect(loaded.IAM.InstanceProfileARN).To(Equal("arn:aws:iam::123456789012:instance-profile/nodes"))
+		Expect(loaded.IAM.InstanceRoleARN).To(Equal("arn:aws:iam::123456789012:role/nodes"))
+	})
+})
@@ -0,0 +1,84 @@
+package eks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	nameFilter      = "name"
+	tagFilterPrefix = "tag:"
+)
+
+// ClusterFilter selects the listed clusters by name or by tag, the clusters must match
+// all the filters; a nil filter selects all the clusters
+type ClusterFilter struct {
+	namePatterns []string
+	tagPatterns  map[string]string
+}
+
+// NewClusterFilter parses filters of the form name=<pattern>, tag:<key>=<pattern> or tag:<key>,
+// the patterns are shell patterns, e.g. name=prod-*; a tag filter without a pattern selects
+// the clusters that have the tag, whatever its value
+func NewClusterFilter(filters []string) (*ClusterFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	f := &ClusterFilter{tagPatterns: map[string]string{}}
+	for _, filter := range filters {
+		key, pattern := filter, "*"
+		if i := strings.Index(filter, "="); i >= 0 {
+			key, pattern = filter[:i], filter[i+1:]
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in filter %q", pattern, filter)
+		}
+
+		switch {
+		case key == nameFilter && pattern != "":
+			f.namePatterns = append(f.namePatterns, pattern)
+		case strings.HasPrefix(key, tagFilterPrefix) && len(key) > len(tagFilterPrefix):
+			f.tagPatterns[strings.TrimPrefix(key, tagFilterPrefix)] = pattern
+		default:
+			return nil, fmt.Errorf("invalid filter %q, filters must be of the form name=<pattern>, tag:<key>=<pattern> or tag:<key>", filter)
+		}
+	}
+	return f, nil
+}
+
+// HasTagFilters reports whether the tags of the clusters are needed to filter them
+func (f *ClusterFilter) HasTagFilters() bool {
+	return f != nil && len(f.tagPatterns) > 0
+}
+
+// MatchName reports whether name matches all the name filters
+func (f *ClusterFilter) MatchName(name string) bool {
+	if f == nil {
+		return true
+	}
+	for _, pattern := range f.namePatterns {
+		if ok, _ := path.Match(pattern, name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchTags reports whether tags match all the tag filters
+func (f *ClusterFilter) MatchTags(tags map[string]string) bool {
+	if f == nil {
+		return true
+	}
+	for key, pattern := range f.tagPatterns {
+		value, ok := tags[key]
+		if !ok {
+			return false
+		}
+		if ok, _ := path.Match(pattern, value); !ok {
+			return false
+		}
+	}
+	return true
+}
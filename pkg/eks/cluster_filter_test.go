@@ -0,0 +1,43 @@
+package eks_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("cluster filter", func() {
+	It("selects all the clusters without filters", func() {
+		filter, err := NewClusterFilter(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter.MatchName("cluster-1")).To(BeTrue())
+		Expect(filter.HasTagFilters()).To(BeFalse())
+		Expect(filter.MatchTags(nil)).To(BeTrue())
+	})
+
+	It("selects the clusters by name", func() {
+		filter, err := NewClusterFilter([]string{"name=prod-*"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter.MatchName("prod-1")).To(BeTrue())
+		Expect(filter.MatchName("dev-1")).To(BeFalse())
+		Expect(filter.HasTagFilters()).To(BeFalse())
+	})
+
+	It("selects the clusters by tag", func() {
+		filter, err := NewClusterFilter([]string{"tag:team=platform", "tag:env"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filter.HasTagFilters()).To(BeTrue())
+		Expect(filter.MatchTags(map[string]string{"team": "platform", "env": "prod"})).To(BeTrue())
+		Expect(filter.MatchTags(map[string]string{"team": "platform"})).To(BeFalse())
+		Expect(filter.MatchTags(map[string]string{"team": "data", "env": "prod"})).To(BeFalse())
+	})
+
+	It("rejects invalid filters", func() {
+		_, err := NewClusterFilter([]string{"region=us-west-2"})
+		Expect(err).To(MatchError(`invalid filter "region=us-west-2", filters must be of the form name=<pattern>, tag:<key>=<pattern> or tag:<key>`))
+
+		_, err = NewClusterFilter([]string{"name=prod-["})
+		Expect(err).To(MatchError(`invalid pattern "prod-[" in filter "name=prod-["`))
+	})
+})
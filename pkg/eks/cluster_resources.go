@@ -0,0 +1,170 @@
+package eks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// Types of the stacks of a cluster
+const (
+	StackTypeCluster           = "cluster"
+	StackTypeNodeGroup         = "nodegroup"
+	StackTypeIAMServiceAccount = "iamserviceaccount"
+	StackTypeAddon             = "addon"
+	// StackTypeLegacy is the type of the stacks created by the first versions of eksctl
+	StackTypeLegacy = "legacy"
+)
+
+// ClusterResources are the resources eksctl manages for a cluster
+type ClusterResources struct {
+	Cluster             string
+	Region              string
+	Stacks              []*StackResource
+	NodeGroups          []*manager.NodeGroupSummary
+	IAMServiceAccounts  []*api.ClusterIAMServiceAccount
+	FargateProfiles     []*api.FargateProfile
+	OIDCProviderARN     string `json:",omitempty"`
+	Addons              []*AddonResource
+	IAMIdentityMappings []iam.Identity
+}
+
+// StackResource is a CloudFormation stack of a cluster
+type StackResource struct {
+	Name   string
+	Type   string
+	Status string
+}
+
+// AddonResource is a default add-on installed in a cluster
+type AddonResource struct {
+	Name    string
+	Version string
+}
+
+// GetClusterResources returns the resources eksctl manages for the cluster of spec; the add-ons
+// and identity mappings are read from the cluster with clientSet, they're nil when it's nil,
+// and the IAM OIDC provider is only checked when the cluster can be operated
+func (c *ClusterProvider) GetClusterResources(spec *api.ClusterConfig, clientSet kubernetes.Interface) (*ClusterResources, error) {
+	resources := &ClusterResources{
+		Cluster: spec.Metadata.Name,
+		Region:  spec.Metadata.Region,
+	}
+
+	stackManager := c.NewStackManager(spec)
+	stacks, err := stackManager.ListStacks()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing stacks")
+	}
+	for _, s := range stacks {
+		name := aws.StringValue(s.StackName)
+		resources.Stacks = append(resources.Stacks, &StackResource{
+			Name:   name,
+			Type:   stackType(spec.Metadata.Name, name),
+			Status: aws.StringValue(s.StackStatus),
+		})
+	}
+
+	if resources.NodeGroups, err = stackManager.GetNodeGroupSummaries(""); err != nil {
+		return nil, errors.Wrap(err, "getting nodegroups")
+	}
+	if resources.IAMServiceAccounts, err = stackManager.GetIAMServiceAccounts(); err != nil {
+		return nil, errors.Wrap(err, "getting iamserviceaccounts")
+	}
+
+	supportsFargate, err := c.SupportsFargate(spec)
+	if err != nil {
+		return nil, err
+	}
+	if supportsFargate {
+		if resources.FargateProfiles, err = fargate.NewClient(spec.Metadata.Name, c.Provider.EKS()).ReadProfiles(); err != nil {
+			return nil, errors.Wrap(err, "getting Fargate profiles")
+		}
+	}
+
+	if resources.OIDCProviderARN, err = c.getOIDCProviderARN(spec); err != nil {
+		return nil, err
+	}
+
+	if clientSet == nil {
+		return resources, nil
+	}
+
+	resources.Addons = []*AddonResource{}
+	for _, name := range []string{defaultaddons.AWSNode, defaultaddons.CoreDNS, defaultaddons.KubeProxy} {
+		version, err := defaultaddons.InstalledVersion(clientSet, name)
+		if err != nil {
+			// add-ons may have been removed, e.g. CoreDNS from Fargate-only clusters
+			logger.Debug("getting the version of %q: %v", name, err)
+			continue
+		}
+		resources.Addons = append(resources.Addons, &AddonResource{Name: name, Version: version})
+	}
+
+	acm, err := authconfigmap.NewFromClientSet(clientSet)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := acm.Identities()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting iamidentitymappings")
+	}
+	resources.IAMIdentityMappings = append([]iam.Identity{}, identities...)
+
+	return resources, nil
+}
+
+// getOIDCProviderARN returns the ARN of the IAM OIDC provider of the cluster of spec, it's empty
+// when the cluster has none, or can't be operated, e.g. when it's being deleted
+func (c *ClusterProvider) getOIDCProviderARN(spec *api.ClusterConfig) (string, error) {
+	if ok, _ := c.CanOperate(spec); !ok {
+		return "", nil
+	}
+	oidc, err := c.NewOpenIDConnectManager(spec)
+	if err != nil {
+		if _, ok := err.(*UnsupportedOIDCError); ok {
+			logger.Debug("cluster %q has no IAM OIDC provider: %s", spec.Metadata.Name, err.Error())
+			return "", nil
+		}
+		return "", err
+	}
+	exists, err := oidc.CheckProviderExists()
+	if err != nil {
+		return "", errors.Wrap(err, "checking the IAM OIDC provider")
+	}
+	if !exists {
+		return "", nil
+	}
+	return oidc.ProviderARN, nil
+}
+
+// stackType returns the type of the stack of the given name of a cluster
+func stackType(clusterName, stackName string) string {
+	suffix := stackName
+	for _, prefix := range []string{"eksctl", "EKS"} {
+		suffix = strings.TrimPrefix(suffix, fmt.Sprintf("%s-%s-", prefix, clusterName))
+	}
+	switch {
+	case suffix == "cluster":
+		return StackTypeCluster
+	case strings.HasPrefix(suffix, "nodegroup-"):
+		return StackTypeNodeGroup
+	case strings.HasPrefix(suffix, "addon-iamserviceaccount-"):
+		return StackTypeIAMServiceAccount
+	case strings.HasPrefix(suffix, "addon-"):
+		return StackTypeAddon
+	default:
+		return StackTypeLegacy
+	}
+}
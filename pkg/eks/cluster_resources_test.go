@@ -0,0 +1,88 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("cluster resources", func() {
+	var (
+		ctl *ClusterProvider
+		p   *mockprovider.MockProvider
+		cfg *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		ctl = &ClusterProvider{
+			Provider: p,
+			Status:   &ProviderStatus{},
+		}
+
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		cfg.Metadata.Region = "us-west-2"
+
+		cluster := testutils.NewFakeCluster("test", awseks.ClusterStatusActive)
+		cluster.Version = aws.String(api.Version1_14)
+		cluster.PlatformVersion = aws.String("eks.1")
+		p.MockEKS().On("DescribeCluster", mock.Anything).Return(&awseks.DescribeClusterOutput{Cluster: cluster}, nil)
+
+		stackNames := []string{"eksctl-test-cluster", "EKS-test-VPC"}
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, name := range stackNames {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{StackName: aws.String(name)})
+			}
+			consume(out, true)
+		}).Return(nil)
+		for _, name := range stackNames {
+			stackName := name
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return input.StackName != nil && *input.StackName == stackName
+			})).Return(&cfn.DescribeStacksOutput{
+				Stacks: []*cfn.Stack{{
+					StackName:   aws.String(stackName),
+					StackStatus: aws.String(cfn.StackStatusCreateComplete),
+				}},
+			}, nil)
+		}
+	})
+
+	It("lists the stacks of the cluster with their type", func() {
+		resources, err := ctl.GetClusterResources(cfg, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.Stacks).To(Equal([]*StackResource{
+			{Name: "eksctl-test-cluster", Type: StackTypeCluster, Status: cfn.StackStatusCreateComplete},
+			{Name: "EKS-test-VPC", Type: StackTypeLegacy, Status: cfn.StackStatusCreateComplete},
+		}))
+		Expect(resources.NodeGroups).To(BeEmpty())
+		Expect(resources.IAMServiceAccounts).To(BeEmpty())
+		Expect(resources.FargateProfiles).To(BeNil())
+		Expect(resources.OIDCProviderARN).To(BeEmpty())
+		Expect(resources.Addons).To(BeNil())
+		Expect(resources.IAMIdentityMappings).To(BeNil())
+	})
+
+	It("reads the default add-ons and identity mappings from the cluster", func() {
+		clientSet, _ := testutils.NewFakeClientSetWithSamples("../addons/default/testdata/sample-1.13.json")
+		resources, err := ctl.GetClusterResources(cfg, clientSet)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.Addons).To(Equal([]*AddonResource{
+			{Name: "aws-node", Version: "v1.4.1"},
+			{Name: "coredns", Version: "v1.2.6"},
+			{Name: "kube-proxy", Version: "v1.13.7"},
+		}))
+		Expect(resources.IAMIdentityMappings).To(BeEmpty())
+	})
+})
@@ -0,0 +1,215 @@
+package eks
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// includeField lists the files merged into a config file, relatively to the directory of the file;
+// it's resolved before the config is decoded, so it's not a field of ClusterConfig
+const includeField = "include"
+
+// documentLoader reads the documents of config files and their includes
+type documentLoader struct {
+	read       func(path string) ([]byte, error)
+	substitute func([]byte) ([]byte, error)
+	// loading holds the files being loaded, to detect include cycles
+	loading map[string]bool
+}
+
+// mergeConfigDocuments merges the YAML documents of a config file and the files it includes into
+// a single config, so that parts of a config, e.g. nodegroups or IAM service accounts, can be kept
+// in separate files; lists are concatenated, objects are merged recursively and a field cannot be
+// set to different values. The config is returned as it is when it has a single document and no includes
+func mergeConfigDocuments(configFile string, read func(string) ([]byte, error), substitute func([]byte) ([]byte, error)) ([]byte, error) {
+	loader := &documentLoader{
+		read:       read,
+		substitute: substitute,
+		loading:    map[string]bool{},
+	}
+	data, err := read(configFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading config file %q", configFile)
+	}
+	if data, err = substitute(data); err != nil {
+		return nil, err
+	}
+
+	documents, err := splitDocuments(data)
+	if err != nil || (len(documents) == 1 && !hasInclude(documents[0])) {
+		// errors are reported when the config is decoded
+		return data, nil
+	}
+
+	loader.loading[filepath.Clean(configFile)] = true
+	merged := map[string]interface{}{}
+	if err := loader.mergeDocuments(merged, configFile, documents); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(merged)
+}
+
+func (l *documentLoader) mergeDocuments(merged map[string]interface{}, path string, documents [][]byte) error {
+	dir := "."
+	if path != "-" {
+		dir = filepath.Dir(path)
+	}
+
+	for i, document := range documents {
+		var config map[string]interface{}
+		if err := yaml.Unmarshal(document, &config); err != nil {
+			return errors.Wrapf(err, "parsing document %d of %q", i+1, path)
+		}
+
+		includes, err := includedFiles(config)
+		if err != nil {
+			return errors.Wrapf(err, "document %d of %q", i+1, path)
+		}
+		delete(config, includeField)
+
+		if err := mergeConfig(merged, config, ""); err != nil {
+			return errors.Wrapf(err, "merging document %d of %q", i+1, path)
+		}
+
+		for _, include := range includes {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(dir, include)
+			}
+			if err := l.mergeFile(merged, include); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (l *documentLoader) mergeFile(merged map[string]interface{}, path string) error {
+	if l.loading[path] {
+		return fmt.Errorf("config file %q includes itself", path)
+	}
+	l.loading[path] = true
+	defer delete(l.loading, path)
+
+	logger.Debug("including config file %q", path)
+	data, err := l.read(path)
+	if err != nil {
+		return errors.Wrapf(err, "reading included config file %q", path)
+	}
+	if data, err = l.substitute(data); err != nil {
+		return err
+	}
+	documents, err := splitDocuments(data)
+	if err != nil {
+		return errors.Wrapf(err, "reading included config file %q", path)
+	}
+	return l.mergeDocuments(merged, path, documents)
+}
+
+// splitDocuments returns the non-empty YAML documents of data
+func splitDocuments(data []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	var documents [][]byte
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isEmptyDocument(document) {
+			continue
+		}
+		documents = append(documents, document)
+	}
+}
+
+func isEmptyDocument(document []byte) bool {
+	for _, line := range strings.Split(string(document), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && line != "---" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+func hasInclude(document []byte) bool {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(document, &config); err != nil {
+		return false
+	}
+	_, ok := config[includeField]
+	return ok
+}
+
+func includedFiles(config map[string]interface{}) ([]string, error) {
+	value, ok := config[includeField]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of files", includeField)
+	}
+	var files []string
+	for _, item := range list {
+		file, ok := item.(string)
+		if !ok || file == "" {
+			return nil, fmt.Errorf("%s must be a list of files, got %v", includeField, item)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// mergeConfig merges src into dst, path being the path of both in the config for errors
+func mergeConfig(dst, src map[string]interface{}, path string) error {
+	for key, value := range src {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		existing, ok := dst[key]
+		if !ok || existing == nil {
+			dst[key] = value
+			continue
+		}
+
+		switch existingValue := existing.(type) {
+		case map[string]interface{}:
+			if srcMap, ok := value.(map[string]interface{}); ok {
+				if err := mergeConfig(existingValue, srcMap, fieldPath); err != nil {
+					return err
+				}
+				continue
+			}
+		case []interface{}:
+			if srcList, ok := value.([]interface{}); ok {
+				dst[key] = append(existingValue, srcList...)
+				continue
+			}
+		default:
+			if reflect.DeepEqual(existing, value) {
+				continue
+			}
+		}
+		if value == nil {
+			continue
+		}
+		return fmt.Errorf("%s is set to different values: %v and %v", fieldPath, existing, value)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package eks_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("Config files with several documents", func() {
+	var dir string
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "config")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("merges the documents of a file and the files it includes", func() {
+		writeFile("nodegroups/ng-2.yaml", `managedNodeGroups:
+  - name: mng-1
+`)
+		writeFile("nodegroups.yaml", `include:
+  - nodegroups/ng-2.yaml
+nodeGroups:
+  - name: ng-1
+`)
+		configFile := writeFile("cluster.yaml", `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: cluster-1
+  region: us-west-2
+include:
+  - nodegroups.yaml
+---
+metadata:
+  tags:
+    team: platform
+nodeGroups:
+  - name: ng-2
+`)
+
+		cfg, err := LoadConfigFromFile(configFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Metadata.Name).To(Equal("cluster-1"))
+		Expect(cfg.Metadata.Tags).To(Equal(map[string]string{"team": "platform"}))
+		Expect(cfg.NodeGroups).To(HaveLen(2))
+		Expect(cfg.NodeGroups[0].Name).To(Equal("ng-1"))
+		Expect(cfg.NodeGroups[1].Name).To(Equal("ng-2"))
+		Expect(cfg.ManagedNodeGroups).To(HaveLen(1))
+		Expect(cfg.ManagedNodeGroups[0].Name).To(Equal("mng-1"))
+	})
+
+	It("rejects fields set to different values", func() {
+		configFile := writeFile("cluster.yaml", `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: cluster-1
+  region: us-west-2
+---
+metadata:
+  region: eu-west-1
+`)
+
+		_, err := LoadConfigFromFile(configFile)
+		Expect(err).To(MatchError(ContainSubstring("metadata.region is set to different values: us-west-2 and eu-west-1")))
+	})
+
+	It("rejects include cycles", func() {
+		writeFile("nodegroups.yaml", "include: [cluster.yaml]\n")
+		configFile := writeFile("cluster.yaml", `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+include: [nodegroups.yaml]
+`)
+
+		_, err := LoadConfigFromFile(configFile)
+		Expect(err).To(MatchError(ContainSubstring("includes itself")))
+	})
+})
@@ -0,0 +1,413 @@
+package eks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// ConfigFileOptions holds the variables and overrides applied to a config file before it's
+// decoded, so that the same file can be used for several clusters, and how it's decoded
+type ConfigFileOptions struct {
+	// VarFiles are files of NAME=value lines, their variables are substituted for ${NAME} in the
+	// config file instead of the environment variables, later files overriding earlier ones
+	VarFiles []string
+	// Set are overrides of fields of the form path=value, e.g. nodeGroups[ng-1].desiredCapacity=3,
+	// they're applied once the documents of the config file are merged
+	Set []string
+	// NoStrict ignores unknown fields, logging a warning, instead of rejecting the config file
+	NoStrict bool
+}
+
+// variablePattern matches ${NAME}, ${env:NAME} and their ${NAME:-default} forms, $${NAME} being the
+// escaped form of ${NAME}
+var variablePattern = regexp.MustCompile(`\$?\$\{(env:)?([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// bootstrapScriptFieldPattern matches the fields of nodegroups that hold shell scripts, which use the
+// same syntax for their own variables, so variables are never substituted in them
+var bootstrapScriptFieldPattern = regexp.MustCompile(`^(\s*(?:-\s+)?)["']?(?:preBootstrapCommands|overrideBootstrapCommand)["']?\s*:`)
+
+// jsonBootstrapScriptFieldPattern matches the keys of the bootstrap script fields in JSON config files,
+// which can be anywhere in a line
+var jsonBootstrapScriptFieldPattern = regexp.MustCompile(`"(?:preBootstrapCommands|overrideBootstrapCommand)"\s*:`)
+
+// Substitute substitutes the variables of a config file, ${NAME} with the variables of the var files,
+// or the environment variable when it's not in them, and ${env:NAME} with the environment variable;
+// the bootstrap scripts of nodegroups are left as they are, and variables that aren't set and have
+// no default are rejected
+func (o ConfigFileOptions) Substitute(data []byte, lookupEnv func(string) (string, bool)) ([]byte, error) {
+	vars := map[string]string{}
+	for _, path := range o.VarFiles {
+		if err := readVarFile(path, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	var unset []string
+	substitute := func(match []byte) []byte {
+		if bytes.HasPrefix(match, []byte("$$")) {
+			return match[1:]
+		}
+		groups := variablePattern.FindSubmatch(match)
+		var (
+			name       = string(groups[2])
+			hasDefault = len(groups[3]) > 0
+		)
+		value, ok := vars[name]
+		if !ok || len(groups[1]) > 0 {
+			value, ok = lookupEnv(name)
+		}
+		switch {
+		case hasDefault && value == "":
+			return groups[3][len(":-"):]
+		case ok:
+			return []byte(value)
+		default:
+			unset = appendUnique(unset, string(match))
+			return match
+		}
+	}
+	replace := func(data []byte) []byte {
+		return variablePattern.ReplaceAllFunc(data, substitute)
+	}
+
+	var (
+		substituted []byte
+		err         error
+	)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		substituted, err = substituteJSON(data, replace)
+	} else {
+		substituted = substituteYAML(data, replace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(unset) > 0 {
+		return nil, fmt.Errorf("variables that are not set and have no default: %s", strings.Join(unset, ", "))
+	}
+	return substituted, nil
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// substituteYAML replaces the variables of a YAML config file, except in the lines of the bootstrap
+// script fields
+func substituteYAML(data []byte, replace func([]byte) []byte) []byte {
+	var (
+		substituted bytes.Buffer
+		// scriptIndent is the indentation of the bootstrap script field the line is in, -1 outside of them
+		scriptIndent = -1
+	)
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if scriptIndent >= 0 && inField(line, scriptIndent) {
+			substituted.Write(line)
+			continue
+		}
+		scriptIndent = -1
+		if match := bootstrapScriptFieldPattern.FindSubmatch(line); match != nil {
+			scriptIndent = len(match[1])
+			substituted.Write(line)
+			continue
+		}
+		substituted.Write(replace(line))
+	}
+	return substituted.Bytes()
+}
+
+// substituteJSON replaces the variables of a JSON config file, except in the values of the bootstrap
+// script fields, as the whole file can be on a single line
+func substituteJSON(data []byte, replace func([]byte) []byte) ([]byte, error) {
+	var substituted bytes.Buffer
+	for rest := data; ; {
+		key := jsonBootstrapScriptFieldPattern.FindIndex(rest)
+		if key == nil {
+			substituted.Write(replace(rest))
+			return substituted.Bytes(), nil
+		}
+		substituted.Write(replace(rest[:key[0]]))
+		valueLength, err := jsonValueLength(rest[key[1]:])
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing bootstrap script field of config file")
+		}
+		end := key[1] + valueLength
+		substituted.Write(rest[key[0]:end])
+		rest = rest[end:]
+	}
+}
+
+// jsonValueLength returns the length of the JSON value at the start of data, including the
+// whitespace before it
+func jsonValueLength(data []byte) (int, error) {
+	reader := bytes.NewReader(data)
+	decoder := json.NewDecoder(reader)
+	var value json.RawMessage
+	if err := decoder.Decode(&value); err != nil {
+		return 0, err
+	}
+	buffered, err := ioutil.ReadAll(decoder.Buffered())
+	if err != nil {
+		return 0, err
+	}
+	return len(data) - reader.Len() - len(buffered), nil
+}
+
+// inField reports whether a line is part of the value of a field whose key is at the given
+// indentation: blank lines, more indented lines and list items at the same indentation
+func inField(line []byte, indent int) bool {
+	trimmed := bytes.TrimLeft(line, " ")
+	if len(bytes.TrimSpace(trimmed)) == 0 {
+		return true
+	}
+	lineIndent := len(line) - len(trimmed)
+	return lineIndent > indent || (lineIndent == indent && bytes.HasPrefix(trimmed, []byte("- ")))
+}
+
+// Override applies the overrides of the options to a config file
+func (o ConfigFileOptions) Override(data []byte) ([]byte, error) {
+	if len(o.Set) == 0 {
+		return data, nil
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "parsing config file to apply overrides")
+	}
+	for _, override := range o.Set {
+		if err := setConfigField(config, override); err != nil {
+			return nil, err
+		}
+	}
+	return yaml.Marshal(config)
+}
+
+// readVarFile reads the variables of a file of NAME=value lines into vars, in the format of
+// .env files: lines can start with export, values can be quoted and # starts a comment line
+func readVarFile(path string, vars map[string]string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "reading var file %q", path)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || !variableNamePattern.MatchString(name) {
+			return fmt.Errorf("%s:%d: expected NAME=value, got %q", path, lineNumber, line)
+		}
+		value := strings.TrimSpace(parts[1])
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		vars[name] = value
+	}
+	return scanner.Err()
+}
+
+// pathElement is an element of the path of an override, elements in brackets are indexes
+// or names of list items, or keys of maps that contain dots
+type pathElement struct {
+	key       string
+	inBracket bool
+}
+
+func parseFieldPath(path string) ([]pathElement, error) {
+	var elements []pathElement
+	for rest := path; rest != ""; {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 2 {
+				return nil, fmt.Errorf("invalid path %q, expected a key or index in brackets", path)
+			}
+			elements = append(elements, pathElement{key: rest[1:end], inBracket: true})
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			elements = append(elements, pathElement{key: rest[:end]})
+			rest = rest[end:]
+			if strings.HasPrefix(rest, ".") {
+				rest = rest[1:]
+				if rest == "" {
+					return nil, fmt.Errorf("invalid path %q", path)
+				}
+			}
+		}
+	}
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("invalid empty path")
+	}
+	return elements, nil
+}
+
+// setConfigField applies an override of the form path=value to a config; the value is a
+// string if the field is a string, and is parsed as YAML otherwise, e.g. [a, b] for a list
+func setConfigField(config map[string]interface{}, override string) error {
+	parts := strings.SplitN(override, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid override %q, expected path=value", override)
+	}
+	path, err := parseFieldPath(parts[0])
+	if err != nil {
+		return err
+	}
+
+	var value interface{} = parts[1]
+	if !isStringField(reflect.TypeOf(api.ClusterConfig{}), path) {
+		if err := yaml.Unmarshal([]byte(parts[1]), &value); err != nil {
+			return errors.Wrapf(err, "parsing value of override %q", override)
+		}
+	}
+
+	if _, err := setField(config, path, value); err != nil {
+		return errors.Wrapf(err, "applying override %q", override)
+	}
+	return nil
+}
+
+// setField sets the field at path in node to value and returns node, missing objects
+// being created; list items are selected by index or by name, and can be appended by
+// using the length of the list as index
+func setField(node interface{}, path []pathElement, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	element := path[0]
+
+	if node == nil {
+		if index, err := strconv.Atoi(element.key); err == nil && element.inBracket {
+			if index != 0 {
+				return nil, fmt.Errorf("index %d is out of range of missing list", index)
+			}
+			node = []interface{}{}
+		} else {
+			node = map[string]interface{}{}
+		}
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		field, err := setField(n[element.key], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[element.key] = field
+		return n, nil
+	case []interface{}:
+		index, err := listIndex(n, element.key)
+		if err != nil {
+			return nil, err
+		}
+		if index == len(n) {
+			n = append(n, nil)
+		}
+		item, err := setField(n[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = item
+		return n, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q of %v, it's neither an object nor a list", element.key, node)
+	}
+}
+
+func listIndex(list []interface{}, key string) (int, error) {
+	if index, err := strconv.Atoi(key); err == nil {
+		if index < 0 || index > len(list) {
+			return 0, fmt.Errorf("index %d is out of range of list of length %d", index, len(list))
+		}
+		return index, nil
+	}
+	for i, item := range list {
+		if object, ok := item.(map[string]interface{}); ok && object["name"] == key {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no item named %q", key)
+}
+
+// isStringField reports whether the field at path is a string, so that values like 1.20
+// aren't parsed as numbers
+func isStringField(t reflect.Type, path []pathElement) bool {
+	for _, element := range path {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Slice, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			field, ok := jsonField(t, element.key)
+			if !ok {
+				return false
+			}
+			t = field.Type
+		default:
+			return false
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+// jsonField finds the field of struct type t serialised as name, including the fields
+// of embedded structs
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if embeddedType := field.Type; field.Anonymous && fieldName == "" {
+			for embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() != reflect.Struct {
+				continue
+			}
+			if embedded, ok := jsonField(embeddedType, name); ok {
+				return embedded, true
+			}
+			continue
+		}
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+		if fieldName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
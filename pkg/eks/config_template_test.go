@@ -0,0 +1,153 @@
+package eks_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("Config file templates", func() {
+	const config = `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: ${CLUSTER_NAME}
+  region: ${REGION:-us-west-2}
+  tags:
+    owner: ${env:USER}
+    home: ${HOME}
+    escaped: $${CLUSTER_NAME}
+nodeGroups:
+  - name: ng-1
+    desiredCapacity: 2
+    preBootstrapCommands:
+      - "for n in 1 2; do echo ${n}; done"
+      - "echo ${CLUSTER_NAME} ${HOME:-/root}"
+    overrideBootstrapCommand: |
+      #!/bin/bash
+      /etc/eks/bootstrap.sh ${CLUSTER_NAME}
+    labels:
+      env: ${CLUSTER_NAME}
+`
+
+	var env map[string]string
+
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	apply := func(options ConfigFileOptions) map[string]interface{} {
+		data, err := options.Substitute([]byte(config), lookupEnv)
+		Expect(err).NotTo(HaveOccurred())
+		data, err = options.Override(data)
+		Expect(err).NotTo(HaveOccurred())
+		var result map[string]interface{}
+		Expect(yaml.Unmarshal(data, &result)).To(Succeed())
+		return result
+	}
+
+	nodeGroup := func(result map[string]interface{}) map[string]interface{} {
+		return result["nodeGroups"].([]interface{})[0].(map[string]interface{})
+	}
+
+	writeVarFile := func(content string) string {
+		varFile, err := ioutil.TempFile("", "vars-*.env")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = varFile.WriteString(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(varFile.Close()).To(Succeed())
+		return varFile.Name()
+	}
+
+	BeforeEach(func() {
+		env = map[string]string{"CLUSTER_NAME": "from-env", "USER": "alice", "HOME": "/home/alice"}
+	})
+
+	It("substitutes the variables of var files, and environment variables that aren't in them", func() {
+		varFile := writeVarFile("# production\nexport CLUSTER_NAME=prod\nREGION=\"eu-west-1\"\n")
+		defer os.Remove(varFile)
+
+		result := apply(ConfigFileOptions{VarFiles: []string{varFile}})
+		Expect(result["metadata"]).To(Equal(map[string]interface{}{
+			"name":   "prod",
+			"region": "eu-west-1",
+			"tags": map[string]interface{}{
+				"owner":   "alice",
+				"home":    "/home/alice",
+				"escaped": "${CLUSTER_NAME}",
+			},
+		}))
+		Expect(nodeGroup(result)["labels"]).To(Equal(map[string]interface{}{"env": "prod"}))
+	})
+
+	It("substitutes environment variables and defaults without var files", func() {
+		result := apply(ConfigFileOptions{})
+		Expect(result["metadata"]).To(HaveKeyWithValue("name", "from-env"))
+		Expect(result["metadata"]).To(HaveKeyWithValue("region", "us-west-2"))
+	})
+
+	It("leaves the bootstrap scripts of nodegroups as they are", func() {
+		varFile := writeVarFile("CLUSTER_NAME=prod\nHOME=/var/lib\n")
+		defer os.Remove(varFile)
+
+		ng := nodeGroup(apply(ConfigFileOptions{VarFiles: []string{varFile}}))
+		Expect(ng["preBootstrapCommands"]).To(Equal([]interface{}{
+			"for n in 1 2; do echo ${n}; done",
+			"echo ${CLUSTER_NAME} ${HOME:-/root}",
+		}))
+		Expect(ng["overrideBootstrapCommand"]).To(Equal("#!/bin/bash\n/etc/eks/bootstrap.sh ${CLUSTER_NAME}\n"))
+		Expect(ng["labels"]).To(Equal(map[string]interface{}{"env": "prod"}))
+	})
+
+	It("leaves the bootstrap scripts of nodegroups of JSON config files as they are", func() {
+		const jsonConfig = `{"metadata": {"name": "${CLUSTER_NAME}"}, "nodeGroups": [{"name": "ng-1",
+  "preBootstrapCommands": ["for n in 1 2; do echo ${n}; done"], "overrideBootstrapCommand": "/etc/eks/bootstrap.sh ${CLUSTER_NAME}",
+  "labels": {"env": "${CLUSTER_NAME}"}}]}`
+
+		data, err := ConfigFileOptions{}.Substitute([]byte(jsonConfig), lookupEnv)
+		Expect(err).NotTo(HaveOccurred())
+		var result map[string]interface{}
+		Expect(yaml.Unmarshal(data, &result)).To(Succeed())
+		Expect(result["metadata"]).To(HaveKeyWithValue("name", "from-env"))
+
+		ng := nodeGroup(result)
+		Expect(ng["preBootstrapCommands"]).To(Equal([]interface{}{"for n in 1 2; do echo ${n}; done"}))
+		Expect(ng["overrideBootstrapCommand"]).To(Equal("/etc/eks/bootstrap.sh ${CLUSTER_NAME}"))
+		Expect(ng["labels"]).To(Equal(map[string]interface{}{"env": "from-env"}))
+	})
+
+	It("rejects variables that aren't set and have no default", func() {
+		delete(env, "USER")
+		delete(env, "CLUSTER_NAME")
+		_, err := ConfigFileOptions{}.Substitute([]byte(config), lookupEnv)
+		Expect(err).To(MatchError("variables that are not set and have no default: ${CLUSTER_NAME}, ${env:USER}"))
+	})
+
+	It("overrides fields, keeping the values of string fields as strings", func() {
+		result := apply(ConfigFileOptions{Set: []string{
+			"metadata.version=1.20",
+			"metadata.tags[k8s.io/team]=platform",
+			"nodeGroups[ng-1].desiredCapacity=3",
+			"nodeGroups[ng-1].availabilityZones=[us-west-2a, us-west-2b]",
+			"nodeGroups[1].name=ng-2",
+		}})
+		Expect(result["metadata"]).To(HaveKeyWithValue("version", "1.20"))
+		Expect(result["metadata"]).To(HaveKeyWithValue("tags", map[string]interface{}{"k8s.io/team": "platform"}))
+		Expect(nodeGroup(result)).To(HaveKeyWithValue("desiredCapacity", float64(3)))
+		Expect(nodeGroup(result)).To(HaveKeyWithValue("availabilityZones", []interface{}{"us-west-2a", "us-west-2b"}))
+		Expect(result["nodeGroups"]).To(ContainElement(map[string]interface{}{"name": "ng-2"}))
+	})
+
+	It("rejects invalid overrides", func() {
+		_, err := ConfigFileOptions{Set: []string{"nodeGroups[ng-3].desiredCapacity=3"}}.Override([]byte(config))
+		Expect(err).To(MatchError(`applying override "nodeGroups[ng-3].desiredCapacity=3": no item named "ng-3"`))
+
+		_, err = ConfigFileOptions{Set: []string{"metadata.name"}}.Override([]byte(config))
+		Expect(err).To(MatchError(`invalid override "metadata.name", expected path=value`))
+	})
+})
@@ -0,0 +1,96 @@
+package eks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// assumedRoleExpiryWindow is how long before their expiry the credentials of
+// assumed roles are refreshed, so that they don't expire in the middle of
+// long-running operations such as waiting for stacks
+const assumedRoleExpiryWindow = 5 * time.Minute
+
+// NewAssumeRoleCredentials returns the credentials of the last of the roles set in spec,
+// each role is assumed with the credentials of the previous one, starting with the
+// credentials of the session; the credentials are cached and refreshed before they expire
+func NewAssumeRoleCredentials(s *session.Session, spec *api.ProviderConfig) *credentials.Credentials {
+	sessionName := spec.AssumeRoleSessionName
+	if sessionName == "" {
+		sessionName = fmt.Sprintf("eksctl-%d", time.Now().Unix())
+	}
+
+	creds := s.Config.Credentials
+	for i, roleARN := range spec.AssumeRoleARNs {
+		logger.Debug("assuming role %q", roleARN)
+		isLast := i == len(spec.AssumeRoleARNs)-1
+		creds = stscreds.NewCredentials(s.Copy(&aws.Config{Credentials: creds}), roleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = sessionName
+			p.ExpiryWindow = assumedRoleExpiryWindow
+			if isLast && spec.AssumeRoleExternalID != "" {
+				p.ExternalID = aws.String(spec.AssumeRoleExternalID)
+			}
+		})
+	}
+	return creds
+}
+
+// newRegionalProvider returns a provider for region, using the same credentials settings as c
+func (c *ClusterProvider) newRegionalProvider(region string) *ClusterProvider {
+	spec := &api.ProviderConfig{
+		Profile:     c.Provider.Profile(),
+		WaitTimeout: c.Provider.WaitTimeout(),
+	}
+	if p, ok := c.Provider.(*ProviderServices); ok {
+		spec = p.spec.DeepCopy()
+	}
+	spec.Region = region
+	return New(spec, nil)
+}
+
+// NewProviderForRole returns a provider whose AWS API calls are made with the credentials of role,
+// assumed with the credentials of c; it returns the provider of c when role is nil
+func (c *ClusterProvider) NewProviderForRole(role *api.AssumeRole) api.ClusterProvider {
+	services, ok := c.Provider.(*ProviderServices)
+	if role == nil || !ok || c.session == nil {
+		return c.Provider
+	}
+	logger.Debug("assuming role %q", role.RoleARN)
+	creds := stscreds.NewCredentials(c.session, role.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = fmt.Sprintf("eksctl-%d", time.Now().Unix())
+		p.ExpiryWindow = assumedRoleExpiryWindow
+		if role.ExternalID != "" {
+			p.ExternalID = aws.String(role.ExternalID)
+		}
+	})
+	return newProviderServices(services.spec, c.session.Copy(&aws.Config{Credentials: creds}))
+}
+
+// ec2LookupProvider is a provider making EC2 calls with the credentials of another provider,
+// while all other calls are made with its own credentials
+type ec2LookupProvider struct {
+	api.ClusterProvider
+	ec2 ec2iface.EC2API
+}
+
+func (p ec2LookupProvider) EC2() ec2iface.EC2API { return p.ec2 }
+
+// NewVPCLookupProvider returns the provider that looks up the existing VPC and subnets of the
+// cluster, its EC2 calls are made with the credentials of vpc.assumeRole when it's set
+func (c *ClusterProvider) NewVPCLookupProvider(spec *api.ClusterConfig) api.ClusterProvider {
+	if spec.VPC == nil || spec.VPC.AssumeRole == nil {
+		return c.Provider
+	}
+	return ec2LookupProvider{
+		ClusterProvider: c.Provider,
+		ec2:             c.NewProviderForRole(spec.VPC.AssumeRole).EC2(),
+	}
+}
@@ -0,0 +1,95 @@
+package eks_test
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("NewAssumeRoleCredentials", func() {
+	var (
+		s     *session.Session
+		calls []*sts.AssumeRoleInput
+	)
+
+	BeforeEach(func() {
+		calls = nil
+		s = session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-west-2"),
+			Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		}))
+		s.Handlers.Send.Clear()
+		s.Handlers.Unmarshal.Clear()
+		s.Handlers.UnmarshalMeta.Clear()
+		s.Handlers.ValidateResponse.Clear()
+		s.Handlers.Send.PushBack(func(r *request.Request) {
+			input := r.Params.(*sts.AssumeRoleInput)
+			calls = append(calls, input)
+			*r.Data.(*sts.AssumeRoleOutput) = sts.AssumeRoleOutput{
+				Credentials: &sts.Credentials{
+					AccessKeyId:     input.RoleArn,
+					SecretAccessKey: aws.String("secret"),
+					SessionToken:    aws.String("token"),
+					Expiration:      aws.Time(time.Now().Add(time.Hour)),
+				},
+			}
+		})
+	})
+
+	It("should assume the roles in order", func() {
+		creds := NewAssumeRoleCredentials(s, &api.ProviderConfig{
+			AssumeRoleARNs:        []string{"arn:aws:iam::123:role/first", "arn:aws:iam::456:role/second"},
+			AssumeRoleExternalID:  "external",
+			AssumeRoleSessionName: "session",
+		})
+
+		value, err := creds.Get()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value.AccessKeyID).To(Equal("arn:aws:iam::456:role/second"))
+
+		Expect(calls).To(HaveLen(2))
+		Expect(*calls[0].RoleArn).To(Equal("arn:aws:iam::123:role/first"))
+		Expect(calls[0].ExternalId).To(BeNil())
+		Expect(*calls[0].RoleSessionName).To(Equal("session"))
+		Expect(*calls[1].RoleArn).To(Equal("arn:aws:iam::456:role/second"))
+		Expect(*calls[1].ExternalId).To(Equal("external"))
+	})
+
+	It("should cache the credentials", func() {
+		creds := NewAssumeRoleCredentials(s, &api.ProviderConfig{
+			AssumeRoleARNs: []string{"arn:aws:iam::123:role/first"},
+		})
+
+		_, err := creds.Get()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = creds.Get()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(HaveLen(1))
+		Expect(*calls[0].RoleSessionName).To(HavePrefix("eksctl-"))
+	})
+})
+
+var _ = Describe("NewVPCLookupProvider", func() {
+	It("should look up the VPC with the role of vpc.assumeRole", func() {
+		ctl := New(&api.ProviderConfig{Region: "us-west-2"}, nil)
+		cfg := api.NewClusterConfig()
+		Expect(ctl.NewVPCLookupProvider(cfg)).To(BeIdenticalTo(ctl.Provider))
+
+		cfg.VPC.AssumeRole = &api.AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/network-reader"}
+		lookup := ctl.NewVPCLookupProvider(cfg)
+		Expect(lookup.EC2()).NotTo(BeIdenticalTo(ctl.Provider.EC2()))
+		Expect(lookup.EKS()).To(BeIdenticalTo(ctl.Provider.EKS()))
+		Expect(lookup.Region()).To(Equal("us-west-2"))
+	})
+})
@@ -3,16 +3,18 @@ package eks
 import (
 	"encoding/base64"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
+	"github.com/aws/aws-sdk-go/service/ec2"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -21,6 +23,7 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/fargate"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils"
 	"github.com/weaveworks/eksctl/pkg/vpc"
@@ -267,34 +270,78 @@ func (c *ClusterProvider) LoadClusterVPC(spec *api.ClusterConfig) error {
 		return err
 	}
 
-	return vpc.UseFromCluster(c.Provider, stack, spec)
+	return vpc.UseFromCluster(c.NewVPCLookupProvider(spec), stack, spec)
 }
 
-// ListClusters display details of all the EKS cluster in your account
-func (c *ClusterProvider) ListClusters(clusterName string, chunkSize int, output printers.Type, eachRegion bool) error {
+// ListClusters writes details of all the EKS cluster in your account to w,
+// the listed clusters are selected by filter when it's not nil
+func (c *ClusterProvider) ListClusters(clusterName string, chunkSize int, output printers.Type, columns []string, eachRegion bool, filter *ClusterFilter, w io.Writer) error {
 	// NOTE: this needs to be reworked in the future so that the functionality
 	// is combined. This require the ability to return details of all clusters
 	// in a single call.
-	printer, err := printers.NewPrinter(output)
-	if err != nil {
+	// clusters are the only objects exported as Prometheus metrics
+	var printer printers.OutputPrinter
+	if output == printers.PrometheusType {
+		printer = printers.NewPrometheusPrinter()
+	} else {
+		var err error
+		if printer, err = printers.NewPrinter(output); err != nil {
+			return err
+		}
+	}
+	if err := printers.SelectColumns(printer, columns); err != nil {
 		return err
 	}
 
+	if output == printers.PrometheusType {
+		addInventoryMetrics(printer.(*printers.PrometheusPrinter))
+		clusters := []*api.ClusterMeta{{Name: clusterName, Region: c.Provider.Region()}}
+		if clusterName == "" {
+			clusters = []*api.ClusterMeta{}
+			if err := c.doListClusters(int64(chunkSize), filter, &clusters, eachRegion); err != nil {
+				return err
+			}
+		}
+		return c.printClusterInventory(clusters, printer, w)
+	}
+
 	if clusterName != "" {
-		if output == "table" {
-			addSummaryTableColumns(printer.(*printers.TablePrinter))
+		// -o json keeps printing the description of the cluster, which scripts poll for its status,
+		// clusters that aren't active can't be described as a ClusterConfig
+		if output == printers.YAMLType {
+			cfg, err := c.GetClusterConfig(&api.ClusterMeta{Name: clusterName, Region: c.Provider.Region()})
+			if err != nil {
+				return err
+			}
+			return printer.PrintObj(cfg, w)
+		}
+		if table, ok := printer.(*printers.TablePrinter); ok {
+			addSummaryTableColumns(table)
 		}
-		return c.doGetCluster(clusterName, printer)
+		return c.doGetCluster(clusterName, printer, w)
 	}
 
-	if output == "table" {
-		addListTableColumns(printer.(*printers.TablePrinter))
+	if table, ok := printer.(*printers.TablePrinter); ok {
+		addListTableColumns(table)
 	}
 	allClusters := []*api.ClusterMeta{}
-	if err := c.doListClusters(int64(chunkSize), printer, &allClusters, eachRegion); err != nil {
+	if err := c.doListClusters(int64(chunkSize), filter, &allClusters, eachRegion); err != nil {
 		return err
 	}
-	return printer.PrintObjWithKind("clusters", allClusters, os.Stdout)
+	return printer.PrintObjWithKind("clusters", allClusters, w)
+}
+
+// ListClusterNames returns the names of the clusters of the region
+func (c *ClusterProvider) ListClusterNames() ([]string, error) {
+	clusters := []*api.ClusterMeta{}
+	if err := c.doListClusters(100, nil, &clusters, false); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names, nil
 }
 
 func (c *ClusterProvider) getClustersRequest(chunkSize int64, nextToken string) ([]*string, *string, error) {
@@ -309,18 +356,31 @@ func (c *ClusterProvider) getClustersRequest(chunkSize int64, nextToken string)
 	return output.Clusters, output.NextToken, nil
 }
 
-func (c *ClusterProvider) doListClusters(chunkSize int64, printer printers.OutputPrinter, allClusters *[]*api.ClusterMeta, eachRegion bool) error {
+func (c *ClusterProvider) doListClusters(chunkSize int64, filter *ClusterFilter, allClusters *[]*api.ClusterMeta, eachRegion bool) error {
 	if eachRegion {
-		// reset region and re-create the client, then make a recursive call
-		for _, region := range api.SupportedRegions() {
-			spec := &api.ProviderConfig{
-				Region:      region,
-				Profile:     c.Provider.Profile(),
-				WaitTimeout: c.Provider.WaitTimeout(),
-			}
-			if err := New(spec, nil).doListClusters(chunkSize, printer, allClusters, false); err != nil {
-				logger.Critical("error listing clusters in %q region: %s", region, err.Error())
-			}
+		// list the clusters of each region concurrently, with a client for each region,
+		// the clusters are still listed in the order of the regions
+		regions := c.enabledRegions()
+		providers := make([]*ClusterProvider, len(regions))
+		for i, region := range regions {
+			providers[i] = c.newRegionalProvider(region)
+		}
+
+		regionClusters := make([][]*api.ClusterMeta, len(regions))
+		var wg sync.WaitGroup
+		for i := range regions {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := providers[i].doListClusters(chunkSize, filter, &regionClusters[i], false); err != nil {
+					logger.Critical("error listing clusters in %q region: %s", regions[i], err.Error())
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		for _, clusters := range regionClusters {
+			*allClusters = append(*allClusters, clusters...)
 		}
 		return nil
 	}
@@ -333,6 +393,18 @@ func (c *ClusterProvider) doListClusters(chunkSize int64, printer printers.Outpu
 		}
 
 		for _, clusterName := range clusters {
+			if !filter.MatchName(*clusterName) {
+				continue
+			}
+			if filter.HasTagFilters() {
+				cluster, err := c.DescribeControlPlane(&api.ClusterMeta{Name: *clusterName})
+				if err != nil {
+					return err
+				}
+				if !filter.MatchTags(aws.StringValueMap(cluster.Tags)) {
+					continue
+				}
+			}
 			*allClusters = append(*allClusters, &api.ClusterMeta{
 				Name:   *clusterName,
 				Region: c.Provider.Region(),
@@ -349,7 +421,31 @@ func (c *ClusterProvider) doListClusters(chunkSize int64, printer printers.Outpu
 	return nil
 }
 
-func (c *ClusterProvider) doGetCluster(clusterName string, printer printers.OutputPrinter) error {
+// enabledRegions returns the supported regions that are enabled in the account,
+// or all the supported regions when the enabled ones can't be described
+func (c *ClusterProvider) enabledRegions() []string {
+	output, err := c.Provider.EC2().DescribeRegions(&ec2.DescribeRegionsInput{})
+	if err != nil {
+		logger.Warning("unable to describe the regions enabled in the account, listing clusters in all the supported regions: %s", err.Error())
+		return api.SupportedRegions()
+	}
+
+	enabled := sets.NewString()
+	for _, region := range output.Regions {
+		enabled.Insert(aws.StringValue(region.RegionName))
+	}
+	var regions []string
+	for _, region := range api.SupportedRegions() {
+		if enabled.Has(region) {
+			regions = append(regions, region)
+		} else {
+			logger.Debug("not listing clusters in %q region, as it's not enabled in the account", region)
+		}
+	}
+	return regions
+}
+
+func (c *ClusterProvider) doGetCluster(clusterName string, printer printers.OutputPrinter, w io.Writer) error {
 	input := &awseks.DescribeClusterInput{
 		Name: &clusterName,
 	}
@@ -360,7 +456,7 @@ func (c *ClusterProvider) doGetCluster(clusterName string, printer printers.Outp
 	logger.Debug("cluster = %#v", output)
 
 	clusters := []*awseks.Cluster{output.Cluster} // TODO: in the future this will have multiple clusters
-	if err := printer.PrintObjWithKind("clusters", clusters, os.Stdout); err != nil {
+	if err := printer.PrintObjWithKind("clusters", clusters, w); err != nil {
 		return err
 	}
 
@@ -388,7 +484,7 @@ func (c *ClusterProvider) WaitForControlPlane(meta *api.ClusterMeta, clientSet *
 	ticker := time.NewTicker(20 * time.Second)
 	defer ticker.Stop()
 
-	timer := time.NewTimer(c.Provider.WaitTimeout())
+	timer := time.NewTimer(c.Provider.Timeout(api.TimeoutPhaseControlPlane))
 	defer timer.Stop()
 
 	for {
@@ -400,7 +496,7 @@ func (c *ClusterProvider) WaitForControlPlane(meta *api.ClusterMeta, clientSet *
 			}
 			logger.Debug("control plane not ready yet – %s", err.Error())
 		case <-timer.C:
-			return fmt.Errorf("timed out waiting for control plane %q after %s", meta.Name, c.Provider.WaitTimeout())
+			return fmt.Errorf("timed out waiting for control plane %q after %s", meta.Name, c.Provider.Timeout(api.TimeoutPhaseControlPlane))
 		}
 	}
 }
@@ -439,6 +535,15 @@ func addSummaryTableColumns(printer *printers.TablePrinter) {
 		}
 		return strings.Join(groups.List(), ",")
 	})
+	printer.AddWideColumn("PLATFORM VERSION", func(c *awseks.Cluster) string {
+		return aws.StringValue(c.PlatformVersion)
+	})
+	printer.AddWideColumn("ENDPOINT", func(c *awseks.Cluster) string {
+		return aws.StringValue(c.Endpoint)
+	})
+	printer.AddWideColumn("ARN", func(c *awseks.Cluster) string {
+		return aws.StringValue(c.Arn)
+	})
 }
 
 func addListTableColumns(printer *printers.TablePrinter) {
@@ -7,10 +7,9 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 
-	"github.com/kris-nova/logger"
-
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -18,8 +17,10 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/testutils"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/utils/ipnet"
 )
 
 var _ = Describe("EKS API wrapper", func() {
@@ -62,7 +63,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters(clusterName, 100, output, false)
+					err = c.ListClusters(clusterName, 100, output, nil, false, nil, os.Stdout)
 				})
 
 				It("should not error", func() {
@@ -114,7 +115,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters(clusterName, 100, output, false)
+					err = c.ListClusters(clusterName, 100, output, nil, false, nil, os.Stdout)
 				})
 
 				It("should not error", func() {
@@ -162,7 +163,7 @@ var _ = Describe("EKS API wrapper", func() {
 			})
 
 			JustBeforeEach(func() {
-				err = c.ListClusters(clusterName, 100, output, false)
+				err = c.ListClusters(clusterName, 100, output, nil, false, nil, os.Stdout)
 			})
 
 			AfterEach(func() {
@@ -233,7 +234,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters("", chunkSize, output, false)
+					err = c.ListClusters("", chunkSize, output, nil, false, nil, os.Stdout)
 				})
 
 				It("should not error", func() {
@@ -267,7 +268,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters("", chunkSize, output, false)
+					err = c.ListClusters("", chunkSize, output, nil, false, nil, os.Stdout)
 				})
 
 				It("should not error", func() {
@@ -278,10 +279,126 @@ var _ = Describe("EKS API wrapper", func() {
 					Expect(p.MockEKS().AssertNumberOfCalls(GinkgoT(), "ListClusters", 1)).To(BeTrue())
 				})
 			})
+			Context("and filters", func() {
+				BeforeEach(func() {
+					chunkSize = 100
+
+					p = mockprovider.NewMockProvider()
+
+					c = &ClusterProvider{
+						Provider: p,
+					}
+
+					p.MockEKS().On("ListClusters", mock.Anything).Return(&awseks.ListClustersOutput{
+						Clusters: aws.StringSlice([]string{"prod-1", "prod-2", "dev-1"}),
+					}, nil)
+					for name, team := range map[string]string{"prod-1": "platform", "prod-2": "data"} {
+						cluster := testutils.NewFakeCluster(name, awseks.ClusterStatusActive)
+						cluster.Tags = aws.StringMap(map[string]string{"team": team})
+						p.MockEKS().On("DescribeCluster", &awseks.DescribeClusterInput{Name: aws.String(name)}).Return(&awseks.DescribeClusterOutput{Cluster: cluster}, nil)
+					}
+				})
+
+				JustBeforeEach(func() {
+					filter, filterErr := NewClusterFilter([]string{"name=prod-*", "tag:team=platform"})
+					Expect(filterErr).NotTo(HaveOccurred())
+					err = c.ListClusters("", chunkSize, output, nil, false, filter, os.Stdout)
+				})
+
+				It("should not error", func() {
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should only describe the clusters matching the name filter", func() {
+					Expect(p.MockEKS().AssertNumberOfCalls(GinkgoT(), "DescribeCluster", 2)).To(BeTrue())
+				})
+			})
 		})
 
 	})
 
+	Describe("GetClusterConfig", func() {
+		var (
+			ctl *ClusterProvider
+			p   *mockprovider.MockProvider
+		)
+
+		BeforeEach(func() {
+			p = mockprovider.NewMockProvider()
+			ctl = &ClusterProvider{
+				Provider: p,
+				Status:   &ProviderStatus{},
+			}
+
+			cluster := testutils.NewFakeCluster("unowned", awseks.ClusterStatusActive)
+			cluster.Version = aws.String(api.Version1_14)
+			cluster.RoleArn = aws.String("arn:aws:iam::12345:role/eks-service-role")
+			cluster.Tags = aws.StringMap(map[string]string{
+				"team":                   "platform",
+				api.ClusterRegisteredTag: "true",
+			})
+			cluster.ResourcesVpcConfig.ClusterSecurityGroupId = aws.String("sg-cluster")
+			cluster.ResourcesVpcConfig.SecurityGroupIds = aws.StringSlice([]string{"sg-additional"})
+			cluster.ResourcesVpcConfig.EndpointPublicAccess = aws.Bool(true)
+			cluster.ResourcesVpcConfig.EndpointPrivateAccess = aws.Bool(true)
+			cluster.ResourcesVpcConfig.PublicAccessCidrs = aws.StringSlice([]string{"1.2.3.4/32"})
+			cluster.Logging = &awseks.Logging{
+				ClusterLogging: []*awseks.LogSetup{{
+					Enabled: aws.Bool(true),
+					Types:   aws.StringSlice([]string{"api", "audit"}),
+				}},
+			}
+
+			p.MockEKS().On("DescribeCluster", mock.Anything).Return(&awseks.DescribeClusterOutput{Cluster: cluster}, nil)
+			p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Return(nil)
+			p.MockEC2().On("DescribeSubnets", mock.Anything).Return(&ec2.DescribeSubnetsOutput{
+				Subnets: []*ec2.Subnet{
+					{
+						SubnetId:            aws.String("sub1"),
+						VpcId:               aws.String("vpc-1234"),
+						AvailabilityZone:    aws.String("us-west-2a"),
+						CidrBlock:           aws.String("10.0.0.0/24"),
+						MapPublicIpOnLaunch: aws.Bool(true),
+					},
+					{
+						SubnetId:         aws.String("sub2"),
+						VpcId:            aws.String("vpc-1234"),
+						AvailabilityZone: aws.String("us-west-2b"),
+						CidrBlock:        aws.String("10.0.1.0/24"),
+					},
+				},
+			}, nil)
+			p.MockEC2().On("DescribeVpcs", mock.Anything).Return(&ec2.DescribeVpcsOutput{
+				Vpcs: []*ec2.Vpc{{
+					VpcId:     aws.String("vpc-1234"),
+					CidrBlock: aws.String("10.0.0.0/16"),
+				}},
+			}, nil)
+		})
+
+		It("describes a cluster that was not created by eksctl", func() {
+			cfg, err := ctl.GetClusterConfig(&api.ClusterMeta{Name: "unowned", Region: "us-west-2"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cfg.Status).To(BeNil())
+			Expect(cfg.Metadata.Version).To(Equal(api.Version1_14))
+			Expect(cfg.Metadata.Tags).To(Equal(map[string]string{"team": "platform"}))
+
+			Expect(cfg.VPC.ID).To(Equal("vpc-1234"))
+			Expect(cfg.VPC.SecurityGroup).To(Equal("sg-additional"))
+			Expect(cfg.VPC.SharedNodeSecurityGroup).To(BeEmpty())
+			Expect(cfg.VPC.Subnets.Public).To(HaveKeyWithValue("us-west-2a", api.Network{ID: "sub1", CIDR: ipnet.MustParseCIDR("10.0.0.0/24")}))
+			Expect(cfg.VPC.Subnets.Private).To(HaveKeyWithValue("us-west-2b", api.Network{ID: "sub2", CIDR: ipnet.MustParseCIDR("10.0.1.0/24")}))
+			Expect(cfg.VPC.PublicAccessCIDRs).To(Equal([]string{"1.2.3.4/32"}))
+			Expect(*cfg.VPC.ClusterEndpoints.PrivateAccess).To(BeTrue())
+
+			Expect(cfg.CloudWatch.ClusterLogging.EnableTypes).To(Equal([]string{"api", "audit"}))
+			Expect(*cfg.IAM.ServiceRoleARN).To(Equal("arn:aws:iam::12345:role/eks-service-role"))
+			Expect(cfg.IAM.WithOIDC).To(BeNil())
+			Expect(cfg.NodeGroups).To(BeEmpty())
+		})
+	})
+
 	Describe("can get OIDC issuer URL and host fingerprint", func() {
 		var (
 			ctl *ClusterProvider
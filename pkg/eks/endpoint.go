@@ -0,0 +1,123 @@
+package eks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// DefaultEndpointCheckTimeout is the time given to CheckEndpoint to reach the API endpoint of a cluster
+const DefaultEndpointCheckTimeout = 30 * time.Second
+
+// EndpointCheck is the result of checking that the API endpoint of a cluster can be reached
+// from the host running eksctl
+type EndpointCheck struct {
+	Host      string
+	Addresses []string
+	// ServerVersion is the Kubernetes version reported by the endpoint
+	ServerVersion string
+
+	DNSLatency     time.Duration
+	TLSLatency     time.Duration
+	RequestLatency time.Duration
+}
+
+// Log logs the result of an endpoint check
+func (c *EndpointCheck) Log() {
+	logger.Info("endpoint %s resolves to %v (%s)", c.Host, c.Addresses, c.DNSLatency.Round(time.Millisecond))
+	logger.Info("serving certificate is signed by the cluster certificate authority (TLS handshake: %s)", c.TLSLatency.Round(time.Millisecond))
+	logger.Info("API server version %s responded in %s", c.ServerVersion, c.RequestLatency.Round(time.Millisecond))
+}
+
+// CheckEndpoint resolves the API endpoint of a cluster, checks that its serving certificate
+// is signed by the certificate authority of the cluster and measures the latency of an
+// unauthenticated request to it, so that DNS, VPN and proxy misconfigurations are caught
+// before kubectl fails with less helpful errors
+func CheckEndpoint(spec *api.ClusterConfig, timeout time.Duration) (*EndpointCheck, error) {
+	if spec.Status == nil || spec.Status.Endpoint == "" {
+		return nil, fmt.Errorf("the endpoint of cluster %q is unknown, it may not be ready yet", spec.Metadata.Name)
+	}
+	endpoint, err := url.Parse(spec.Status.Endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing endpoint %q", spec.Status.Endpoint)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	check := &EndpointCheck{Host: endpoint.Hostname()}
+
+	start := time.Now()
+	check.Addresses, err = net.DefaultResolver.LookupHost(ctx, check.Host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving endpoint %s; if it only has private access, it can only be resolved "+
+			"from the VPC of the cluster or from networks forwarding DNS queries to it, e.g. through a VPN", check.Host)
+	}
+	check.DNSLatency = time.Since(start)
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(spec.Status.CertificateAuthorityData) {
+		return nil, fmt.Errorf("no certificate found in the certificate authority data of cluster %q", spec.Metadata.Name)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				RootCAs:    roots,
+				ServerName: check.Host,
+			},
+		},
+	}
+
+	var tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			check.TLSLatency = time.Since(tlsStart)
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, spec.Status.Endpoint+"/version", nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
+
+	start = time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.As(err, &x509.UnknownAuthorityError{}) || errors.As(err, &x509.HostnameError{}) {
+			return nil, errors.Wrapf(err, "the serving certificate of endpoint %s isn't valid for the certificate authority of the cluster, "+
+				"a proxy may be intercepting TLS connections", check.Host)
+		}
+		return nil, errors.Wrapf(err, "connecting to endpoint %s (%v); check that your network is allowed by the "+
+			"public access CIDRs of the cluster, or by its security group for private access", check.Host, check.Addresses)
+	}
+	defer resp.Body.Close()
+	check.RequestLatency = time.Since(start)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from endpoint %s: %s", check.Host, resp.Status)
+	}
+	var version struct {
+		GitVersion string `json:"gitVersion"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return nil, errors.Wrapf(err, "decoding version reported by endpoint %s", check.Host)
+	}
+	check.ServerVersion = version.GitVersion
+
+	return check, nil
+}
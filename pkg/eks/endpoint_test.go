@@ -0,0 +1,69 @@
+package eks_test
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("endpoint check", func() {
+	var (
+		server *httptest.Server
+		cfg    *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/version" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"major": "1", "minor": "16", "gitVersion": "v1.16.8-eks-e16311"}`)
+		}))
+
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		cfg.Status = &api.ClusterStatus{
+			Endpoint: server.URL,
+			CertificateAuthorityData: pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: server.Certificate().Raw,
+			}),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reaches an endpoint serving a certificate of the cluster certificate authority", func() {
+		check, err := CheckEndpoint(cfg, 5*time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Host).To(Equal("127.0.0.1"))
+		Expect(check.Addresses).To(Equal([]string{"127.0.0.1"}))
+		Expect(check.ServerVersion).To(Equal("v1.16.8-eks-e16311"))
+		Expect(check.TLSLatency).To(BeNumerically(">", 0))
+	})
+
+	It("rejects a certificate that isn't valid for the endpoint", func() {
+		// the certificate of httptest servers isn't valid for localhost
+		cfg.Status.Endpoint = strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+		_, err := CheckEndpoint(cfg, 5*time.Second)
+		Expect(err).To(MatchError(ContainSubstring("isn't valid for the certificate authority of the cluster")))
+	})
+
+	It("requires the endpoint of the cluster", func() {
+		cfg.Status = nil
+		_, err := CheckEndpoint(cfg, 5*time.Second)
+		Expect(err).To(MatchError(`the endpoint of cluster "test" is unknown, it may not be ready yet`))
+	})
+})
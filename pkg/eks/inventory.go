@@ -0,0 +1,102 @@
+package eks
+
+import (
+	"io"
+
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+// ClusterInventory describes a cluster together with its nodegroups
+type ClusterInventory struct {
+	Region     string
+	Cluster    *awseks.Cluster
+	NodeGroups []*manager.NodeGroupSummary
+}
+
+// printClusterInventory prints the inventory of the given clusters, each
+// cluster is described using a provider for its own region
+func (c *ClusterProvider) printClusterInventory(clusters []*api.ClusterMeta, printer printers.OutputPrinter, w io.Writer) error {
+	inventory := []*ClusterInventory{}
+	for _, meta := range clusters {
+		ctl := c
+		if meta.Region != c.Provider.Region() {
+			ctl = c.newRegionalProvider(meta.Region)
+		}
+		item, err := ctl.getClusterInventory(meta)
+		if err != nil {
+			return err
+		}
+		inventory = append(inventory, item)
+	}
+	return printer.PrintObjWithKind("clusters", inventory, w)
+}
+
+func (c *ClusterProvider) getClusterInventory(meta *api.ClusterMeta) (*ClusterInventory, error) {
+	cluster, err := c.DescribeControlPlane(meta)
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing cluster %q in %q", meta.Name, meta.Region)
+	}
+
+	item := &ClusterInventory{
+		Region:  meta.Region,
+		Cluster: cluster,
+	}
+
+	spec := &api.ClusterConfig{Metadata: &api.ClusterMeta{Name: meta.Name, Region: meta.Region}}
+	summaries, err := c.NewStackManager(spec).GetNodeGroupSummaries("")
+	if err != nil {
+		// clusters that were not created by eksctl have no nodegroup stacks
+		logger.Debug("getting nodegroups of cluster %q: %v", meta.Name, err)
+	} else {
+		item.NodeGroups = summaries
+	}
+	return item, nil
+}
+
+func addInventoryMetrics(printer *printers.PrometheusPrinter) {
+	printer.AddGauge("cluster_info", "Information about the EKS cluster, the value is always 1", func(i *ClusterInventory) []printers.PrometheusSample {
+		labels := map[string]string{
+			"cluster": *i.Cluster.Name,
+			"region":  i.Region,
+			"version": *i.Cluster.Version,
+			"status":  *i.Cluster.Status,
+		}
+		if i.Cluster.PlatformVersion != nil {
+			labels["platform_version"] = *i.Cluster.PlatformVersion
+		}
+		return []printers.PrometheusSample{{Labels: labels, Value: 1}}
+	})
+	addNodeGroupGauge(printer, "nodegroup_desired_capacity", "Desired number of nodes of the nodegroup", func(s *manager.NodeGroupSummary) int {
+		return s.DesiredCapacity
+	})
+	addNodeGroupGauge(printer, "nodegroup_min_size", "Minimum number of nodes of the nodegroup", func(s *manager.NodeGroupSummary) int {
+		return s.MinSize
+	})
+	addNodeGroupGauge(printer, "nodegroup_max_size", "Maximum number of nodes of the nodegroup", func(s *manager.NodeGroupSummary) int {
+		return s.MaxSize
+	})
+}
+
+func addNodeGroupGauge(printer *printers.PrometheusPrinter, name, help string, value func(*manager.NodeGroupSummary) int) {
+	printer.AddGauge(name, help, func(i *ClusterInventory) []printers.PrometheusSample {
+		samples := make([]printers.PrometheusSample, 0, len(i.NodeGroups))
+		for _, ng := range i.NodeGroups {
+			samples = append(samples, printers.PrometheusSample{
+				Labels: map[string]string{
+					"cluster":       *i.Cluster.Name,
+					"region":        i.Region,
+					"nodegroup":     ng.Name,
+					"instance_type": ng.InstanceType,
+				},
+				Value: float64(value(ng)),
+			})
+		}
+		return samples
+	})
+}
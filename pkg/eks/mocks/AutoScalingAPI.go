@@ -0,0 +1,107 @@
+package mocks
+
+import (
+	autoscaling "github.com/aws/aws-sdk-go/service/autoscaling"
+	autoscalingiface "github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AutoScalingAPI is a mock type for the AutoScalingAPI type, it only mocks the functions
+// used by eksctl until it's generated like the other mocks, the others panic
+type AutoScalingAPI struct {
+	autoscalingiface.AutoScalingAPI
+	mock.Mock
+}
+
+// CreateOrUpdateTags provides a mock function with given fields: _a0
+func (_m *AutoScalingAPI) CreateOrUpdateTags(_a0 *autoscaling.CreateOrUpdateTagsInput) (*autoscaling.CreateOrUpdateTagsOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *autoscaling.CreateOrUpdateTagsOutput
+	if rf, ok := ret.Get(0).(func(*autoscaling.CreateOrUpdateTagsInput) *autoscaling.CreateOrUpdateTagsOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*autoscaling.CreateOrUpdateTagsOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*autoscaling.CreateOrUpdateTagsInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTags provides a mock function with given fields: _a0
+func (_m *AutoScalingAPI) DeleteTags(_a0 *autoscaling.DeleteTagsInput) (*autoscaling.DeleteTagsOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *autoscaling.DeleteTagsOutput
+	if rf, ok := ret.Get(0).(func(*autoscaling.DeleteTagsInput) *autoscaling.DeleteTagsOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*autoscaling.DeleteTagsOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*autoscaling.DeleteTagsInput) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResumeProcesses provides a mock function with given fields: _a0
+func (_m *AutoScalingAPI) ResumeProcesses(_a0 *autoscaling.ScalingProcessQuery) (*autoscaling.ResumeProcessesOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *autoscaling.ResumeProcessesOutput
+	if rf, ok := ret.Get(0).(func(*autoscaling.ScalingProcessQuery) *autoscaling.ResumeProcessesOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*autoscaling.ResumeProcessesOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*autoscaling.ScalingProcessQuery) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuspendProcesses provides a mock function with given fields: _a0
+func (_m *AutoScalingAPI) SuspendProcesses(_a0 *autoscaling.ScalingProcessQuery) (*autoscaling.SuspendProcessesOutput, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *autoscaling.SuspendProcessesOutput
+	if rf, ok := ret.Get(0).(func(*autoscaling.ScalingProcessQuery) *autoscaling.SuspendProcessesOutput); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*autoscaling.SuspendProcessesOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*autoscaling.ScalingProcessQuery) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	_ "github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	_ "github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface" // used for testing
 	_ "github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
 	_ "github.com/aws/aws-sdk-go/service/ec2/ec2iface"
@@ -22,3 +23,4 @@ import (
 //go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/iam/iamiface -name=IAMAPI -output=./
 //go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface -name=CloudTrailAPI -output=./
 //go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/ssm/ssmiface -name=SSMAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface -name=AutoScalingAPI -output=./
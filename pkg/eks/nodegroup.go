@@ -5,7 +5,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
-	"github.com/kris-nova/logger"
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
@@ -13,6 +13,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,7 +22,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-func isNodeReady(node *corev1.Node) bool {
+// IsNodeReady reports whether the Ready condition of node is true
+func IsNodeReady(node *corev1.Node) bool {
 	for _, c := range node.Status.Conditions {
 		if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue {
 			return true
@@ -40,7 +42,7 @@ func getNodes(clientSet kubernetes.Interface, ng KubeNodeGroup) (int, error) {
 	for _, node := range nodes.Items {
 		// logger.Debug("node[%d]=%#v", n, node)
 		ready := "not ready"
-		if isNodeReady(&node) {
+		if IsNodeReady(&node) {
 			ready = "ready"
 			counter++
 		}
@@ -87,6 +89,36 @@ func ValidateBottlerocketSupport(controlPlaneVersion string, kubeNodeGroups []Ku
 	return nil
 }
 
+// MaxKubeletVersionSkew is the number of minor versions the kubelets can be older than the control plane,
+// see https://kubernetes.io/docs/setup/release/version-skew-policy/#kubelet
+const MaxKubeletVersionSkew = 2
+
+// ValidateKubeletVersionSkew validates that nodes of kubeletVersion are supported by a control plane of
+// controlPlaneVersion: the kubelets can't be newer than the control plane, and can be at most
+// MaxKubeletVersionSkew minor versions older; the versions can include a patch version, e.g. 1.15.10
+func ValidateKubeletVersionSkew(controlPlaneVersion, kubeletVersion string) error {
+	controlPlane, err := semver.ParseTolerant(controlPlaneVersion)
+	if err != nil {
+		return errors.Wrapf(err, "parsing control plane version %q", controlPlaneVersion)
+	}
+	kubelet, err := semver.ParseTolerant(kubeletVersion)
+	if err != nil {
+		return errors.Wrapf(err, "parsing Kubernetes version %q of the nodes", kubeletVersion)
+	}
+
+	if kubelet.Major != controlPlane.Major {
+		return fmt.Errorf("nodes of Kubernetes version %s are not supported by a control plane of version %s", kubeletVersion, controlPlaneVersion)
+	}
+	if kubelet.Minor > controlPlane.Minor {
+		return fmt.Errorf("nodes of Kubernetes version %s can't be newer than the control plane, which is at version %s", kubeletVersion, controlPlaneVersion)
+	}
+	if controlPlane.Minor-kubelet.Minor > MaxKubeletVersionSkew {
+		return fmt.Errorf("nodes of Kubernetes version %s are more than %d minor versions older than the control plane, which is at version %s",
+			kubeletVersion, MaxKubeletVersionSkew, controlPlaneVersion)
+	}
+	return nil
+}
+
 // ValidateManagedNodesSupport validates support for Managed Nodegroups
 func ValidateManagedNodesSupport(clusterConfig *api.ClusterConfig) error {
 	if len(clusterConfig.ManagedNodeGroups) > 0 {
@@ -204,7 +236,7 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 	if minSize == 0 {
 		return nil
 	}
-	timer := time.After(c.Provider.WaitTimeout())
+	timer := time.After(c.Provider.Timeout(api.TimeoutPhaseNodeGroup))
 	timeout := false
 	readyNodes := sets.NewString()
 	watcher, err := clientSet.CoreV1().Nodes().Watch(ng.ListOptions())
@@ -224,7 +256,7 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 			logger.Debug("event = %#v", event)
 			if event.Object != nil && event.Type != watch.Deleted {
 				if node, ok := event.Object.(*corev1.Node); ok {
-					if isNodeReady(node) {
+					if IsNodeReady(node) {
 						readyNodes.Insert(node.Name)
 						counter = readyNodes.Len()
 						logger.Debug("node %q is ready in %q", node.Name, ng.NameString())
@@ -240,12 +272,13 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 	}
 	watcher.Stop()
 	if timeout {
-		return fmt.Errorf("timed out (after %s) waiting for at least %d nodes to join the cluster and become ready in %q", c.Provider.WaitTimeout(), minSize, ng.NameString())
+		return fmt.Errorf("timed out (after %s) waiting for at least %d nodes to join the cluster and become ready in %q", c.Provider.Timeout(api.TimeoutPhaseNodeGroup), minSize, ng.NameString())
 	}
 
-	if _, err = getNodes(clientSet, ng); err != nil {
+	if counter, err = getNodes(clientSet, ng); err != nil {
 		return errors.Wrap(err, "re-listing nodes")
 	}
+	logger.Emit(logger.EventNodeGroupReady, map[string]string{"nodegroup": ng.NameString(), "readyNodes": fmt.Sprintf("%d", counter)}, "nodegroup %q has %d ready node(s)", ng.NameString(), counter)
 
 	return nil
 }
@@ -0,0 +1,113 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/oci"
+)
+
+// nodeGroupTemplateField references the template of a nodegroup in config files, it's
+// resolved before the config is decoded, so it's not a field of the nodegroup types
+const nodeGroupTemplateField = "template"
+
+type pullFunc func(*oci.Reference) ([]byte, error)
+
+// resolveNodeGroupTemplates merges the templates referenced by the nodegroups of a config
+// file into them, the fields of a nodegroup take precedence over the ones of its template
+// and maps are merged recursively; data is returned as it is when there's no template
+func resolveNodeGroupTemplates(data []byte, pull pullFunc) ([]byte, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		// errors are reported when the config is decoded
+		return data, nil
+	}
+
+	templates := map[string]map[string]interface{}{}
+	resolved := false
+	for _, field := range []string{"nodeGroups", "managedNodeGroups"} {
+		nodeGroups, ok := config[field].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, ng := range nodeGroups {
+			ngConfig, ok := ng.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, ok := ngConfig[nodeGroupTemplateField]
+			if !ok {
+				continue
+			}
+			path := fmt.Sprintf("%s[%d].%s", field, i, nodeGroupTemplateField)
+			refString, ok := ref.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a string", path)
+			}
+
+			template, ok := templates[refString]
+			if !ok {
+				var err error
+				if template, err = pullNodeGroupTemplate(refString, pull); err != nil {
+					return nil, errors.Wrapf(err, "resolving %s", path)
+				}
+				templates[refString] = template
+			}
+
+			delete(ngConfig, nodeGroupTemplateField)
+			nodeGroups[i] = mergeNodeGroupConfig(template, ngConfig)
+			logger.Debug("merged template %s into %s[%d]", refString, field, i)
+			resolved = true
+		}
+	}
+
+	if !resolved {
+		return data, nil
+	}
+	return yaml.Marshal(config)
+}
+
+func pullNodeGroupTemplate(ref string, pull pullFunc) (map[string]interface{}, error) {
+	parsedRef, err := oci.ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	if parsedRef.Digest == "" {
+		logger.Warning("nodegroup template %s isn't pinned to a digest, its content can change whenever the tag is pushed again", ref)
+	}
+	data, err := pull(parsedRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var template map[string]interface{}
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return nil, errors.Wrapf(err, "decoding nodegroup template %s", ref)
+	}
+	if _, ok := template[nodeGroupTemplateField]; ok {
+		return nil, fmt.Errorf("nodegroup template %s cannot reference another template", ref)
+	}
+	return template, nil
+}
+
+// mergeNodeGroupConfig returns a copy of template with the fields of ng, so that
+// a template can be shared by several nodegroups
+func mergeNodeGroupConfig(template, ng map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(template)+len(ng))
+	for k, v := range template {
+		merged[k] = v
+	}
+	for k, v := range ng {
+		templateMap, isTemplateMap := merged[k].(map[string]interface{})
+		ngMap, isNGMap := v.(map[string]interface{})
+		if isTemplateMap && isNGMap {
+			merged[k] = mergeNodeGroupConfig(templateMap, ngMap)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
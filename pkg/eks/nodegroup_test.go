@@ -0,0 +1,32 @@
+package eks_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("kubelet version skew", func() {
+	DescribeTable("supported versions",
+		func(controlPlaneVersion, kubeletVersion string) {
+			Expect(ValidateKubeletVersionSkew(controlPlaneVersion, kubeletVersion)).To(Succeed())
+		},
+		Entry("same version", "1.15", "1.15"),
+		Entry("same version with a patch version", "1.15", "1.15.10"),
+		Entry("older by the maximum skew", "1.15", "1.13"),
+	)
+
+	DescribeTable("unsupported versions",
+		func(controlPlaneVersion, kubeletVersion, expectedErr string) {
+			Expect(ValidateKubeletVersionSkew(controlPlaneVersion, kubeletVersion)).To(MatchError(expectedErr))
+		},
+		Entry("newer than the control plane", "1.14", "1.15.10",
+			"nodes of Kubernetes version 1.15.10 can't be newer than the control plane, which is at version 1.14"),
+		Entry("older than the maximum skew", "1.15", "1.12",
+			"nodes of Kubernetes version 1.12 are more than 2 minor versions older than the control plane, which is at version 1.15"),
+		Entry("different major version", "1.15", "2.0",
+			"nodes of Kubernetes version 2.0 are not supported by a control plane of version 1.15"),
+	)
+})
@@ -0,0 +1,106 @@
+package eks
+
+import (
+	"math"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"golang.org/x/time/rate"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	// minAPIRateFactor is the fraction of the maximum rate the rate of API calls is never lowered below
+	minAPIRateFactor = 0.1
+	// apiRateIncreaseFactor is the fraction of the maximum rate that is added back after each successful call
+	apiRateIncreaseFactor = 0.05
+)
+
+// rateLimitedServices are the services whose calls are rate limited, they're the
+// ones that eksctl calls the most, and whose limits are shared by the whole account
+var rateLimitedServices = map[string]bool{
+	cloudformation.ServiceName: true,
+	ec2.ServiceName:            true,
+	awseks.ServiceName:         true,
+}
+
+// APIRateLimiter limits the rate of the EC2, CloudFormation and EKS API calls of sessions;
+// the rate is halved each time a call is throttled, and raised back gradually towards
+// the maximum as calls succeed
+type APIRateLimiter struct {
+	mu      sync.Mutex
+	limiter *rate.Limiter
+	maxQPS  rate.Limit
+}
+
+// NewAPIRateLimiter returns a limiter of maxQPS calls per second, with bursts of up to burst calls,
+// burst defaults to maxQPS rounded up
+func NewAPIRateLimiter(maxQPS float64, burst int) *APIRateLimiter {
+	if burst <= 0 {
+		burst = int(math.Ceil(maxQPS))
+	}
+	return &APIRateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(maxQPS), burst),
+		maxQPS:  rate.Limit(maxQPS),
+	}
+}
+
+// Limit returns the current rate of calls per second
+func (l *APIRateLimiter) Limit() float64 {
+	return float64(l.limiter.Limit())
+}
+
+// AddHandlers makes the requests sent with handlers wait for the limiter, including their retries
+func (l *APIRateLimiter) AddHandlers(handlers *request.Handlers) {
+	handlers.Send.PushFrontNamed(request.NamedHandler{
+		Name: "eksctlAPIRateLimiterSend",
+		Fn: func(r *request.Request) {
+			if !rateLimitedServices[r.ClientInfo.ServiceName] {
+				return
+			}
+			if err := l.limiter.Wait(r.Context()); err != nil {
+				r.Error = err
+			}
+		},
+	})
+	handlers.Retry.PushBackNamed(request.NamedHandler{
+		Name: "eksctlAPIRateLimiterRetry",
+		Fn: func(r *request.Request) {
+			if rateLimitedServices[r.ClientInfo.ServiceName] && request.IsErrorThrottle(r.Error) {
+				l.adjust(func(limit rate.Limit) rate.Limit {
+					return limit / 2
+				})
+				logger.Debug("%s/%s was throttled, lowering the rate of API calls to %.2f per second",
+					r.ClientInfo.ServiceName, r.Operation.Name, l.Limit())
+			}
+		},
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "eksctlAPIRateLimiterComplete",
+		Fn: func(r *request.Request) {
+			if rateLimitedServices[r.ClientInfo.ServiceName] && r.Error == nil {
+				l.adjust(func(limit rate.Limit) rate.Limit {
+					return limit + l.maxQPS*apiRateIncreaseFactor
+				})
+			}
+		},
+	})
+}
+
+// adjust sets the rate to the result of f, within the minimum and maximum rates
+func (l *APIRateLimiter) adjust(f func(rate.Limit) rate.Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit := f(l.limiter.Limit())
+	if min := l.maxQPS * minAPIRateFactor; limit < min {
+		limit = min
+	}
+	if limit > l.maxQPS {
+		limit = l.maxQPS
+	}
+	l.limiter.SetLimit(limit)
+}
@@ -0,0 +1,60 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("APIRateLimiter", func() {
+	It("should lower the rate of calls when they're throttled and raise it back as they succeed", func() {
+		s := session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-west-2"),
+			Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+			MaxRetries:  aws.Int(0),
+		}))
+		s.Handlers.Send.Clear()
+		s.Handlers.Unmarshal.Clear()
+		s.Handlers.UnmarshalMeta.Clear()
+		s.Handlers.UnmarshalError.Clear()
+		s.Handlers.ValidateResponse.Clear()
+
+		throttle := false
+		s.Handlers.Send.PushBack(func(r *request.Request) {
+			if throttle {
+				r.Error = awserr.New("Throttling", "Rate exceeded", nil)
+			}
+		})
+
+		limiter := NewAPIRateLimiter(10, 0)
+		limiter.AddHandlers(&s.Handlers)
+		cfnAPI := cloudformation.New(s)
+
+		_, err := cfnAPI.DescribeStacks(&cloudformation.DescribeStacksInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limiter.Limit()).To(Equal(10.0))
+
+		throttle = true
+		_, err = cfnAPI.DescribeStacks(&cloudformation.DescribeStacksInput{})
+		Expect(err).To(HaveOccurred())
+		Expect(limiter.Limit()).To(Equal(5.0))
+
+		throttle = false
+		_, err = cfnAPI.DescribeStacks(&cloudformation.DescribeStacksInput{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limiter.Limit()).To(BeNumerically("~", 5.5, 0.001))
+
+		throttle = true
+		for i := 0; i < 4; i++ {
+			_, _ = cfnAPI.DescribeStacks(&cloudformation.DescribeStacksInput{})
+		}
+		Expect(limiter.Limit()).To(BeNumerically("~", 1.0, 0.001))
+	})
+})
@@ -0,0 +1,119 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// associateEncryptionConfigInput is the input of the AssociateEncryptionConfig operation of EKS
+type associateEncryptionConfigInput struct {
+	_ struct{} `type:"structure"`
+
+	ClusterName        *string                    `location:"uri" locationName:"name" type:"string" required:"true"`
+	EncryptionConfig   []*awseks.EncryptionConfig `locationName:"encryptionConfig" type:"list" required:"true"`
+	ClientRequestToken *string                    `locationName:"clientRequestToken" type:"string" idempotencyToken:"true"`
+}
+
+// associateEncryptionConfigOutput is the output of the AssociateEncryptionConfig operation of EKS
+type associateEncryptionConfigOutput struct {
+	_ struct{} `type:"structure"`
+
+	Update *awseks.Update `locationName:"update" type:"structure"`
+}
+
+// associateEncryptionConfig calls AssociateEncryptionConfig, the version of the AWS SDK eksctl uses
+// doesn't have the operation yet, so the request is built with the client of the EKS API
+func associateEncryptionConfig(eksAPI eksiface.EKSAPI, input *associateEncryptionConfigInput) (*associateEncryptionConfigOutput, error) {
+	eksClient, ok := eksAPI.(*awseks.EKS)
+	if !ok {
+		return nil, fmt.Errorf("unexpected EKS client %T", eksAPI)
+	}
+	op := &request.Operation{
+		Name:       "AssociateEncryptionConfig",
+		HTTPMethod: "POST",
+		HTTPPath:   "/clusters/{name}/encryption-config/associate",
+	}
+	output := &associateEncryptionConfigOutput{}
+	req := eksClient.NewRequest(op, input, output)
+	return output, req.Send()
+}
+
+// EnableSecretsEncryption enables the encryption of the Kubernetes secrets of an existing cluster
+// with the KMS key set in secretsEncryption.keyARN, and waits for the update to succeed; the
+// encryption can't be disabled or moved to another key once it's enabled
+func (c *ClusterProvider) EnableSecretsEncryption(spec *api.ClusterConfig) error {
+	if spec.SecretsEncryption == nil || spec.SecretsEncryption.KeyARN == nil {
+		return fmt.Errorf("secretsEncryption.keyARN must be set")
+	}
+
+	if ok, err := c.CanUpdate(spec); !ok {
+		return err
+	}
+	spec.Metadata.Version = c.ControlPlaneVersion()
+	if err := validateKMSSupport(spec); err != nil {
+		return err
+	}
+
+	for _, encryption := range c.Status.clusterInfo.cluster.EncryptionConfig {
+		if encryption.Provider != nil && encryption.Provider.KeyArn != nil {
+			if *encryption.Provider.KeyArn == *spec.SecretsEncryption.KeyARN {
+				logger.Info("secrets encryption is already enabled with KMS key %q", *encryption.Provider.KeyArn)
+				return nil
+			}
+			return fmt.Errorf("secrets of cluster %q are already encrypted with KMS key %q, the key can't be changed",
+				spec.Metadata.Name, *encryption.Provider.KeyArn)
+		}
+	}
+
+	output, err := associateEncryptionConfig(c.Provider.EKS(), &associateEncryptionConfigInput{
+		ClusterName: &spec.Metadata.Name,
+		EncryptionConfig: []*awseks.EncryptionConfig{{
+			Resources: aws.StringSlice([]string{"secrets"}),
+			Provider: &awseks.Provider{
+				KeyArn: spec.SecretsEncryption.KeyARN,
+			},
+		}},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "enabling secrets encryption for cluster %q", spec.Metadata.Name)
+	}
+	return c.waitForUpdateToSucceed(spec.Metadata.Name, output.Update)
+}
+
+// ReencryptSecrets rewrites every secret of a cluster unchanged, so that the API server stores it
+// again, encrypted with the current encryption config; secrets are only encrypted when they're written
+func ReencryptSecrets(clientSet kubernetes.Interface) error {
+	secrets, err := clientSet.CoreV1().Secrets(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing secrets")
+	}
+
+	failed := 0
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if _, err := clientSet.CoreV1().Secrets(secret.Namespace).Update(secret); err != nil {
+			// a secret modified or deleted since it was listed is written again by its own update
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				continue
+			}
+			logger.Warning("re-encrypting secret %s/%s: %v", secret.Namespace, secret.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to re-encrypt %d of %d secrets", failed, len(secrets.Items))
+	}
+	logger.Info("re-encrypted %d secrets", len(secrets.Items))
+	return nil
+}
@@ -0,0 +1,32 @@
+package eks_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("secrets encryption", func() {
+	It("writes every secret again", func() {
+		clientSet := fake.NewSimpleClientset(
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "kube-system"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "password", Namespace: "default"}},
+		)
+
+		Expect(ReencryptSecrets(clientSet)).To(Succeed())
+
+		var updated []string
+		for _, action := range clientSet.Actions() {
+			if update, ok := action.(k8stesting.UpdateAction); ok {
+				secret := update.GetObject().(*corev1.Secret)
+				updated = append(updated, secret.Namespace+"/"+secret.Name)
+			}
+		}
+		Expect(updated).To(ConsistOf("kube-system/token", "default/password"))
+	})
+})
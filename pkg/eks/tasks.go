@@ -1,14 +1,22 @@
 package eks
 
 import (
-	"github.com/kris-nova/logger"
+	"fmt"
+	"os"
+
 	"github.com/pkg/errors"
+
 	"github.com/weaveworks/eksctl/pkg/addons"
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/git/executor"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+	"github.com/weaveworks/eksctl/pkg/windows"
 )
 
 type clusterConfigTask struct {
@@ -39,7 +47,7 @@ func (v *vpcControllerTask) Do(errCh chan error) error {
 	if err != nil {
 		return err
 	}
-	vpcController := addons.NewVPCController(rawClient, v.spec.Status, v.clusterProvider.Provider.Region(), false)
+	vpcController := windows.NewVPCController(rawClient, v.spec.Status, v.clusterProvider.Provider.Region(), false)
 	if err := vpcController.Deploy(); err != nil {
 		return errors.Wrap(err, "error installing VPC controller")
 	}
@@ -74,16 +82,78 @@ func (c *ClusterProvider) AppendExtraClusterConfigTasks(cfg *api.ClusterConfig,
 		})
 	}
 
+	if api.IsEnabled(cfg.VPC.AutoTagSubnetsForELB) && cfg.IsSharedVPC() {
+		logger.Warning("vpc.autoTagSubnetsForELB is ignored, the subnets are shared by account %s, which can only tag them", cfg.VPC.OwnerAccountID)
+	} else if api.IsEnabled(cfg.VPC.AutoTagSubnetsForELB) {
+		newTasks.Append(&clusterConfigTask{
+			info: "tag subnets for load balancer discovery",
+			spec: cfg,
+			call: func(cfg *api.ClusterConfig) error {
+				return vpc.EnsureSubnetTagsForELB(c.Provider, cfg)
+			},
+		})
+	}
+
+	c.AppendAddonTasks(cfg, installVPCController, newTasks)
+	if newTasks.Len() > 0 {
+		tasks.Append(newTasks)
+	}
+}
+
+// AppendAddonTasks appends the tasks installing add-ons in the cluster
+func (c *ClusterProvider) AppendAddonTasks(cfg *api.ClusterConfig, installVPCController bool, tasks *manager.TaskTree) {
 	if installVPCController {
-		newTasks.Append(&vpcControllerTask{
+		tasks.Append(&vpcControllerTask{
 			info:            "install Windows VPC controller",
 			spec:            cfg,
 			clusterProvider: c,
 		})
 	}
-	if newTasks.Len() > 0 {
-		tasks.Append(newTasks)
+	if len(vpcCNIEnv(cfg)) > 0 {
+		tasks.Append(&clusterConfigTask{
+			info: "configure VPC CNI",
+			spec: cfg,
+			call: func(cfg *api.ClusterConfig) error {
+				clientSet, err := c.NewStdClientSet(cfg)
+				if err != nil {
+					return err
+				}
+				return SetVPCCNIEnv(cfg, clientSet)
+			},
+		})
+	}
+}
+
+// NewCNITasks returns the tasks setting up the CNI plugin of the cluster, which must run before the
+// nodes join the cluster; there are none when the pods use the VPC CNI
+func (c *ClusterProvider) NewCNITasks(cfg *api.ClusterConfig) []manager.Task {
+	if !cfg.HasAlternateCNI() {
+		return nil
+	}
+	return []manager.Task{&clusterConfigTask{
+		info: fmt.Sprintf("set up %s as the CNI plugin", cfg.CNI()),
+		spec: cfg,
+		call: c.SetUpCNI,
+	}}
+}
+
+// SetUpCNI stops aws-node from running on the nodes, and installs the CNI plugin of the cluster with Helm
+func (c *ClusterProvider) SetUpCNI(cfg *api.ClusterConfig) error {
+	client, clientSet, err := c.newClientSetWithEmbeddedToken(cfg)
+	if err != nil {
+		return err
+	}
+	if err := defaultaddons.DisableAWSNode(clientSet, cfg.CNI()); err != nil {
+		return err
+	}
+
+	kubeconfigPath, err := client.WriteTempKubeconfig("eksctl-helm-kubeconfig-")
+	if err != nil {
+		return err
 	}
+	defer os.Remove(kubeconfigPath)
+
+	return addons.NewCNIInstaller(executor.NewShellExecutor(nil), kubeconfigPath).Install(cfg)
 }
 
 // NewTasksRequiringControlPlane returns all tasks for updating cluster configuration depending on the control plane availability
@@ -117,9 +187,16 @@ func (c *ClusterProvider) appendCreateTasksForIAMServiceAccounts(cfg *api.Cluste
 			if err != nil {
 				return err
 			}
-			if err := oidc.CreateProvider(); err != nil {
+			// the provider exists when resuming the creation of the cluster
+			exists, err := oidc.CheckProviderExists()
+			if err != nil {
 				return err
 			}
+			if !exists {
+				if err := oidc.CreateProvider(); err != nil {
+					return err
+				}
+			}
 			*eatlyOIDC = *oidc
 			return nil
 		},
@@ -140,6 +217,11 @@ func (c *ClusterProvider) appendCreateTasksForIAMServiceAccounts(cfg *api.Cluste
 }
 
 func (c *ClusterProvider) maybeAppendTasksForEndpointAccessUpdates(cfg *api.ClusterConfig, tasks *manager.TaskTree) {
+	if cfg.IsLocalCluster() {
+		// the API endpoint of local clusters is always private, it cannot be updated
+		logger.Info("the Kubernetes API endpoint of local cluster %q is only reachable from the network of the Outpost", cfg.Metadata.Name)
+		return
+	}
 	// if a cluster config doesn't have the default api endpoint access, append a new task
 	// so that we update the cluster with the new access configuration.  This is a
 	// non-CloudFormation context, so we create a task to send it through the EKS API.
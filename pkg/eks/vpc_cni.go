@@ -0,0 +1,107 @@
+package eks
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// prefixDelegationMinVPCCNIVersion is the first version of the VPC CNI that reads ENABLE_PREFIX_DELEGATION,
+// older versions ignore it and keep assigning single IPs to the pods
+const prefixDelegationMinVPCCNIVersion = "v1.9.0"
+
+// podSecurityGroupsMinVPCCNIVersion is the first version of the VPC CNI that reads ENABLE_POD_ENI
+const podSecurityGroupsMinVPCCNIVersion = "v1.7.7"
+
+// vpcCNIVersion returns the version of aws-node that runs in the cluster, or the version of the
+// manifest of eksctl when clientSet is nil, as the cluster doesn't exist yet; EKS installs a version
+// that isn't newer than it for the Kubernetes versions eksctl supports
+func vpcCNIVersion(clientSet kubernetes.Interface) (string, error) {
+	if clientSet == nil {
+		return defaultaddons.BundledAWSNodeVersion()
+	}
+	return defaultaddons.InstalledVersion(clientSet, defaultaddons.AWSNode)
+}
+
+// CheckVPCCNIVersion checks that the VPC CNI of the cluster supports the features that the config enables,
+// aws-node is only looked up when the cluster exists
+func (c *ClusterProvider) CheckVPCCNIVersion(cfg *api.ClusterConfig, clusterExists bool) error {
+	if !api.IsEnabled(cfg.PrefixDelegation) && !api.IsEnabled(cfg.PodSecurityGroups) {
+		return nil
+	}
+	var clientSet kubernetes.Interface
+	if clusterExists {
+		var err error
+		if clientSet, err = c.NewStdClientSet(cfg); err != nil {
+			return err
+		}
+	}
+	return checkVPCCNIVersion(cfg, clientSet)
+}
+
+// checkVPCCNIVersion fails when aws-node is too old for security groups for pods, and turns prefix delegation
+// off when it's too old for it, so that the nodes keep the maximum number of pods of their network interfaces
+// instead of admitting pods that can't get an IP
+func checkVPCCNIVersion(cfg *api.ClusterConfig, clientSet kubernetes.Interface) error {
+	if !api.IsEnabled(cfg.PrefixDelegation) && !api.IsEnabled(cfg.PodSecurityGroups) {
+		return nil
+	}
+
+	version, err := vpcCNIVersion(clientSet)
+	if err != nil {
+		return err
+	}
+
+	if api.IsEnabled(cfg.PodSecurityGroups) {
+		supported, err := defaultaddons.IsMinVersion(podSecurityGroupsMinVPCCNIVersion, version)
+		if err != nil {
+			return err
+		}
+		if !supported {
+			return fmt.Errorf("podSecurityGroups requires %s %s or later, but the cluster runs %s; "+
+				"update %[1]s with 'eksctl utils update-aws-node --version=%[2]s' before enabling it, new clusters have to be created without it first", defaultaddons.AWSNode, podSecurityGroupsMinVPCCNIVersion, version)
+		}
+	}
+
+	if !api.IsEnabled(cfg.PrefixDelegation) {
+		return nil
+	}
+	supported, err := defaultaddons.IsMinVersion(prefixDelegationMinVPCCNIVersion, version)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		logger.Warning("prefixDelegation requires %s %s or later, but the cluster runs %s, so it won't be enabled and the nodes will use the maximum number of pods of their network interfaces; "+
+			"update %[1]s with 'eksctl utils update-aws-node --version=%[2]s' once the cluster exists, then create the nodegroups", defaultaddons.AWSNode, prefixDelegationMinVPCCNIVersion, version)
+		cfg.PrefixDelegation = api.Disabled()
+	}
+	return nil
+}
+
+// vpcCNIEnv returns the environment variables of aws-node that enable the VPC CNI features of the cluster
+func vpcCNIEnv(cfg *api.ClusterConfig) map[string]string {
+	env := map[string]string{}
+	if api.IsEnabled(cfg.PodSecurityGroups) {
+		env["ENABLE_POD_ENI"] = "true"
+	}
+	if api.IsEnabled(cfg.PrefixDelegation) {
+		env["ENABLE_PREFIX_DELEGATION"] = "true"
+		// keeps a spare prefix attached, instead of the spare network interface of the default WARM_ENI_TARGET
+		env["WARM_PREFIX_TARGET"] = "1"
+	}
+	return env
+}
+
+// SetVPCCNIEnv sets the environment variables of aws-node that enable the VPC CNI features of the cluster
+func SetVPCCNIEnv(cfg *api.ClusterConfig, clientSet kubernetes.Interface) error {
+	env := vpcCNIEnv(cfg)
+	if len(env) == 0 {
+		return nil
+	}
+	_, err := defaultaddons.SetAWSNodeEnv(clientSet, env)
+	return err
+}
@@ -0,0 +1,72 @@
+package eks
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("VPC CNI version", func() {
+	var cfg *api.ClusterConfig
+
+	awsNode := func(image string) *fake.Clientset {
+		return fake.NewSimpleClientset(&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws-node", Namespace: metav1.NamespaceSystem},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "aws-node", Image: image}},
+					},
+				},
+			},
+		})
+	}
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.PrefixDelegation = api.Enabled()
+	})
+
+	It("turns prefix delegation off for new clusters, as the aws-node of eksctl is older than 1.9.0", func() {
+		Expect(checkVPCCNIVersion(cfg, nil)).To(Succeed())
+		Expect(api.IsEnabled(cfg.PrefixDelegation)).To(BeFalse())
+		Expect(vpcCNIEnv(cfg)).To(BeEmpty())
+	})
+
+	It("turns prefix delegation off when the cluster runs an older aws-node", func() {
+		Expect(checkVPCCNIVersion(cfg, awsNode("602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.7.5"))).To(Succeed())
+		Expect(api.IsEnabled(cfg.PrefixDelegation)).To(BeFalse())
+	})
+
+	It("keeps prefix delegation when the cluster runs aws-node 1.9.0 or later", func() {
+		Expect(checkVPCCNIVersion(cfg, awsNode("602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.9.0-eksbuild.1"))).To(Succeed())
+		Expect(api.IsEnabled(cfg.PrefixDelegation)).To(BeTrue())
+		Expect(vpcCNIEnv(cfg)).To(HaveKeyWithValue("ENABLE_PREFIX_DELEGATION", "true"))
+	})
+
+	Context("security groups for pods", func() {
+		BeforeEach(func() {
+			cfg.PrefixDelegation = nil
+			cfg.PodSecurityGroups = api.Enabled()
+		})
+
+		It("fails for new clusters, as the aws-node of eksctl is older than 1.7.7", func() {
+			Expect(checkVPCCNIVersion(cfg, nil)).To(MatchError(ContainSubstring("podSecurityGroups requires aws-node v1.7.7 or later, but the cluster runs v1.6.0")))
+		})
+
+		It("fails when the cluster runs an older aws-node", func() {
+			err := checkVPCCNIVersion(cfg, awsNode("602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.7.5"))
+			Expect(err).To(MatchError(ContainSubstring("but the cluster runs v1.7.5")))
+		})
+
+		It("succeeds when the cluster runs aws-node 1.7.7 or later", func() {
+			Expect(checkVPCCNIVersion(cfg, awsNode("602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.7.7"))).To(Succeed())
+			Expect(vpcCNIEnv(cfg)).To(HaveKeyWithValue("ENABLE_POD_ENI", "true"))
+		})
+	})
+})
@@ -0,0 +1,178 @@
+package elb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/pkg/errors"
+	awsprovider "k8s.io/legacy-cloud-providers/aws"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// describeTagsBatchSize is the maximum number of load balancers that
+// can be passed to a single DescribeTags call
+const describeTagsBatchSize = 20
+
+// CleanupOrphans deletes all load balancers and target groups tagged as owned by
+// the cluster, regardless of whether a Kubernetes Service still refers to them; this
+// covers clusters whose API is no longer reachable, as well as services that were
+// deleted while their load balancer was still being provisioned. Load balancers
+// tagged as shared belong to other owners, and are left alone
+func CleanupOrphans(ctx context.Context, elbAPI elbiface.ELBAPI, elbv2API elbv2iface.ELBV2API, clusterName string) error {
+	if err := deleteOrphanClassicLoadBalancers(ctx, elbAPI, clusterName); err != nil {
+		return err
+	}
+	if err := deleteOrphanV2LoadBalancers(ctx, elbv2API, clusterName); err != nil {
+		return err
+	}
+	return deleteOrphanTargetGroups(ctx, elbv2API, clusterName)
+}
+
+func deleteOrphanClassicLoadBalancers(ctx context.Context, elbAPI elbiface.ELBAPI, clusterName string) error {
+	var names []string
+	err := elbAPI.DescribeLoadBalancersPagesWithContext(ctx, &elb.DescribeLoadBalancersInput{}, func(output *elb.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, lb := range output.LoadBalancerDescriptions {
+			names = append(names, aws.StringValue(lb.LoadBalancerName))
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot list classic load balancers")
+	}
+
+	clusterTagKey := awsprovider.TagNameKubernetesClusterPrefix + clusterName
+	for start := 0; start < len(names); start += describeTagsBatchSize {
+		end := start + describeTagsBatchSize
+		if end > len(names) {
+			end = len(names)
+		}
+		output, err := elbAPI.DescribeTagsWithContext(ctx, &elb.DescribeTagsInput{
+			LoadBalancerNames: aws.StringSlice(names[start:end]),
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot describe tags of classic load balancers")
+		}
+		for _, desc := range output.TagDescriptions {
+			if !elbTagsOwned(desc.Tags, clusterTagKey) {
+				continue
+			}
+			logger.Info("deleting orphan classic load balancer %q", aws.StringValue(desc.LoadBalancerName))
+			if _, err := elbAPI.DeleteLoadBalancerWithContext(ctx, &elb.DeleteLoadBalancerInput{
+				LoadBalancerName: desc.LoadBalancerName,
+			}); err != nil {
+				return errors.Wrapf(err, "cannot delete classic load balancer %q", aws.StringValue(desc.LoadBalancerName))
+			}
+		}
+	}
+	return nil
+}
+
+func deleteOrphanV2LoadBalancers(ctx context.Context, elbv2API elbv2iface.ELBV2API, clusterName string) error {
+	var arns []string
+	err := elbv2API.DescribeLoadBalancersPagesWithContext(ctx, &elbv2.DescribeLoadBalancersInput{}, func(output *elbv2.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, lb := range output.LoadBalancers {
+			arns = append(arns, aws.StringValue(lb.LoadBalancerArn))
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot list load balancers")
+	}
+
+	ownedARNs, err := ownedV2Resources(ctx, elbv2API, arns, clusterName)
+	if err != nil {
+		return errors.Wrap(err, "cannot describe tags of load balancers")
+	}
+	for _, arn := range ownedARNs {
+		logger.Info("deleting orphan load balancer %q", arn)
+		if _, err := elbv2API.DeleteLoadBalancerWithContext(ctx, &elbv2.DeleteLoadBalancerInput{
+			LoadBalancerArn: aws.String(arn),
+		}); err != nil {
+			return errors.Wrapf(err, "cannot delete load balancer %q", arn)
+		}
+	}
+	if len(ownedARNs) == 0 {
+		return nil
+	}
+	// the target groups can only be deleted once the listeners of the load balancers are gone
+	if err := elbv2API.WaitUntilLoadBalancersDeletedWithContext(ctx, &elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: aws.StringSlice(ownedARNs),
+	}); err != nil {
+		return errors.Wrap(err, "waiting for load balancers to be deleted")
+	}
+	return nil
+}
+
+func deleteOrphanTargetGroups(ctx context.Context, elbv2API elbv2iface.ELBV2API, clusterName string) error {
+	var arns []string
+	err := elbv2API.DescribeTargetGroupsPagesWithContext(ctx, &elbv2.DescribeTargetGroupsInput{}, func(output *elbv2.DescribeTargetGroupsOutput, _ bool) bool {
+		for _, tg := range output.TargetGroups {
+			arns = append(arns, aws.StringValue(tg.TargetGroupArn))
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot list target groups")
+	}
+
+	ownedARNs, err := ownedV2Resources(ctx, elbv2API, arns, clusterName)
+	if err != nil {
+		return errors.Wrap(err, "cannot describe tags of target groups")
+	}
+	for _, arn := range ownedARNs {
+		logger.Info("deleting orphan target group %q", arn)
+		if _, err := elbv2API.DeleteTargetGroupWithContext(ctx, &elbv2.DeleteTargetGroupInput{
+			TargetGroupArn: aws.String(arn),
+		}); err != nil {
+			return errors.Wrapf(err, "cannot delete target group %q", arn)
+		}
+	}
+	return nil
+}
+
+// ownedV2Resources returns the ARNs of the load balancers or target groups that are tagged as owned by the cluster
+func ownedV2Resources(ctx context.Context, elbv2API elbv2iface.ELBV2API, arns []string, clusterName string) ([]string, error) {
+	var owned []string
+	clusterTagKey := awsprovider.TagNameKubernetesClusterPrefix + clusterName
+	for start := 0; start < len(arns); start += describeTagsBatchSize {
+		end := start + describeTagsBatchSize
+		if end > len(arns) {
+			end = len(arns)
+		}
+		output, err := elbv2API.DescribeTagsWithContext(ctx, &elbv2.DescribeTagsInput{
+			ResourceArns: aws.StringSlice(arns[start:end]),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, desc := range output.TagDescriptions {
+			if elbv2TagsOwned(desc.Tags, clusterTagKey) {
+				owned = append(owned, aws.StringValue(desc.ResourceArn))
+			}
+		}
+	}
+	return owned, nil
+}
+
+func elbTagsOwned(tags []*elb.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return aws.StringValue(tag.Value) == awsprovider.ResourceLifecycleOwned
+		}
+	}
+	return false
+}
+
+func elbv2TagsOwned(tags []*elbv2.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return aws.StringValue(tag.Value) == awsprovider.ResourceLifecycleOwned
+		}
+	}
+	return false
+}
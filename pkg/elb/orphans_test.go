@@ -0,0 +1,113 @@
+package elb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/eks/mocks"
+)
+
+var _ = Describe("orphan load balancers", func() {
+	const clusterTagKey = "kubernetes.io/cluster/cluster-1"
+
+	var (
+		elbAPI   *mocks.ELBAPI
+		elbv2API *mocks.ELBV2API
+	)
+
+	v2Tags := map[string]string{
+		"arn:lb/owned":  "owned",
+		"arn:lb/shared": "shared",
+		"arn:tg/owned":  "owned",
+		"arn:tg/shared": "shared",
+	}
+
+	BeforeEach(func() {
+		elbAPI = &mocks.ELBAPI{}
+		elbAPI.On("DescribeLoadBalancersPagesWithContext", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[2].(func(*elb.DescribeLoadBalancersOutput, bool) bool)
+			consume(&elb.DescribeLoadBalancersOutput{
+				LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+					{LoadBalancerName: aws.String("owned")},
+					{LoadBalancerName: aws.String("shared")},
+					{LoadBalancerName: aws.String("other-cluster")},
+				},
+			}, true)
+		}).Return(nil)
+		elbAPI.On("DescribeTagsWithContext", mock.Anything, mock.Anything).Return(&elb.DescribeTagsOutput{
+			TagDescriptions: []*elb.TagDescription{
+				{LoadBalancerName: aws.String("owned"), Tags: []*elb.Tag{{Key: aws.String(clusterTagKey), Value: aws.String("owned")}}},
+				{LoadBalancerName: aws.String("shared"), Tags: []*elb.Tag{{Key: aws.String(clusterTagKey), Value: aws.String("shared")}}},
+				{LoadBalancerName: aws.String("other-cluster"), Tags: []*elb.Tag{{Key: aws.String("kubernetes.io/cluster/cluster-2"), Value: aws.String("owned")}}},
+			},
+		}, nil)
+		elbAPI.On("DeleteLoadBalancerWithContext", mock.Anything, mock.Anything).Return(&elb.DeleteLoadBalancerOutput{}, nil)
+
+		elbv2API = &mocks.ELBV2API{}
+		elbv2API.On("DescribeLoadBalancersPagesWithContext", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[2].(func(*elbv2.DescribeLoadBalancersOutput, bool) bool)
+			consume(&elbv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []*elbv2.LoadBalancer{
+					{LoadBalancerArn: aws.String("arn:lb/owned")},
+					{LoadBalancerArn: aws.String("arn:lb/shared")},
+				},
+			}, true)
+		}).Return(nil)
+		elbv2API.On("DescribeTargetGroupsPagesWithContext", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[2].(func(*elbv2.DescribeTargetGroupsOutput, bool) bool)
+			consume(&elbv2.DescribeTargetGroupsOutput{
+				TargetGroups: []*elbv2.TargetGroup{
+					{TargetGroupArn: aws.String("arn:tg/owned")},
+					{TargetGroupArn: aws.String("arn:tg/shared")},
+				},
+			}, true)
+		}).Return(nil)
+		elbv2API.On("DescribeTagsWithContext", mock.Anything, mock.Anything).Return(
+			func(_ context.Context, input *elbv2.DescribeTagsInput, _ ...request.Option) *elbv2.DescribeTagsOutput {
+				output := &elbv2.DescribeTagsOutput{}
+				for _, arn := range input.ResourceArns {
+					output.TagDescriptions = append(output.TagDescriptions, &elbv2.TagDescription{
+						ResourceArn: arn,
+						Tags:        []*elbv2.Tag{{Key: aws.String(clusterTagKey), Value: aws.String(v2Tags[*arn])}},
+					})
+				}
+				return output
+			}, nil)
+		elbv2API.On("DeleteLoadBalancerWithContext", mock.Anything, mock.Anything).Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+		elbv2API.On("WaitUntilLoadBalancersDeletedWithContext", mock.Anything, mock.Anything).Return(nil)
+		elbv2API.On("DeleteTargetGroupWithContext", mock.Anything, mock.Anything).Return(&elbv2.DeleteTargetGroupOutput{}, nil)
+	})
+
+	It("only deletes the load balancers and target groups owned by the cluster", func() {
+		Expect(CleanupOrphans(context.Background(), elbAPI, elbv2API, "cluster-1")).To(Succeed())
+
+		Expect(elbAPI.AssertNumberOfCalls(GinkgoT(), "DeleteLoadBalancerWithContext", 1)).To(BeTrue())
+		Expect(elbAPI.AssertCalled(GinkgoT(), "DeleteLoadBalancerWithContext", mock.Anything,
+			&elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String("owned")})).To(BeTrue())
+
+		Expect(elbv2API.AssertNumberOfCalls(GinkgoT(), "DeleteLoadBalancerWithContext", 1)).To(BeTrue())
+		Expect(elbv2API.AssertCalled(GinkgoT(), "DeleteLoadBalancerWithContext", mock.Anything,
+			&elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String("arn:lb/owned")})).To(BeTrue())
+		Expect(elbv2API.AssertCalled(GinkgoT(), "WaitUntilLoadBalancersDeletedWithContext", mock.Anything,
+			&elbv2.DescribeLoadBalancersInput{LoadBalancerArns: aws.StringSlice([]string{"arn:lb/owned"})})).To(BeTrue())
+
+		Expect(elbv2API.AssertNumberOfCalls(GinkgoT(), "DeleteTargetGroupWithContext", 1)).To(BeTrue())
+		Expect(elbv2API.AssertCalled(GinkgoT(), "DeleteTargetGroupWithContext", mock.Anything,
+			&elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String("arn:tg/owned")})).To(BeTrue())
+	})
+
+	It("doesn't wait when no load balancer is deleted", func() {
+		Expect(CleanupOrphans(context.Background(), elbAPI, elbv2API, "cluster-3")).To(Succeed())
+
+		Expect(elbAPI.AssertNotCalled(GinkgoT(), "DeleteLoadBalancerWithContext", mock.Anything, mock.Anything)).To(BeTrue())
+		Expect(elbv2API.AssertNotCalled(GinkgoT(), "WaitUntilLoadBalancersDeletedWithContext", mock.Anything, mock.Anything)).To(BeTrue())
+		Expect(elbv2API.AssertNotCalled(GinkgoT(), "DeleteTargetGroupWithContext", mock.Anything, mock.Anything)).To(BeTrue())
+	})
+})
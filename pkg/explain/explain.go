@@ -0,0 +1,287 @@
+// Package explain describes the fields of the config file, in the
+// style of `kubectl explain`, without calling any API
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// Field describes a field of the config file
+type Field struct {
+	// Path of the field, e.g. nodeGroups.iam.withAddonPolicies, it's empty for the config file itself
+	Path string
+	// Type of the value, e.g. string or []Object
+	Type string
+	// Description is the documentation of the field or of its type
+	Description string
+	// Since is the version of eksctl that introduced the field, if it's known
+	Since string
+	// Default is the value set by eksctl when the field is omitted, if any
+	Default string
+	// Fields are the fields of an object, or of the objects of a list
+	Fields []*Field
+}
+
+// Name returns the name of the field in the config file
+func (f *Field) Name() string {
+	return f.Path[strings.LastIndex(f.Path, ".")+1:]
+}
+
+// Explain describes the field at path, which is made of the names of the fields
+// separated by dots, e.g. nodeGroups.iam; an empty path describes the config file
+func Explain(path string) (*Field, error) {
+	fieldType := reflect.TypeOf(api.ClusterConfig{})
+	defaults := reflect.ValueOf(defaultConfig())
+	description, _ := api.FieldDoc(fieldType.Name(), "")
+	field := &Field{
+		Path:        path,
+		Type:        typeName(fieldType),
+		Description: description,
+	}
+
+	var walked []string
+	for _, name := range strings.Split(path, ".") {
+		if name == "" {
+			continue
+		}
+		parentType := structType(fieldType)
+		if parentType == nil {
+			return nil, fmt.Errorf("field %q has no fields", strings.Join(walked, "."))
+		}
+		walked = append(walked, name)
+		structField, ok := findField(parentType, name)
+		if !ok {
+			return nil, fmt.Errorf("field %q does not exist", strings.Join(walked, "."))
+		}
+
+		fieldType = structField.Type
+		defaults = fieldValue(defaults, structField.Index)
+		field.Type = typeName(fieldType)
+		field.Description, field.Since = api.FieldDoc(parentType.Name(), structField.Name)
+		if field.Description == "" {
+			if t := structType(fieldType); t != nil {
+				field.Description, _ = api.FieldDoc(t.Name(), "")
+			}
+		}
+	}
+
+	if t := structType(fieldType); t != nil {
+		field.Fields = listFields(t, field.Path)
+	} else if value := indirect(defaults); value.IsValid() && value.Kind() != reflect.Map && !value.IsZero() {
+		// the values of maps, e.g. labels, depend on the names of the cluster and nodegroup
+		if value.Kind() == reflect.String {
+			field.Default = value.String()
+		} else {
+			data, err := json.Marshal(value.Interface())
+			if err != nil {
+				return nil, err
+			}
+			field.Default = string(data)
+		}
+	}
+	return field, nil
+}
+
+// Print writes the description of the field in the format of `kubectl explain`
+func (f *Field) Print(w io.Writer) error {
+	var out strings.Builder
+	fmt.Fprintf(&out, "KIND:     ClusterConfig\nVERSION:  %s\n\n", api.SchemeGroupVersion.String())
+	if f.Path != "" {
+		fmt.Fprintf(&out, "FIELD:    %s <%s>\n", f.Name(), f.Type)
+		if f.Since != "" {
+			fmt.Fprintf(&out, "SINCE:    %s\n", f.Since)
+		}
+		if f.Default != "" {
+			fmt.Fprintf(&out, "DEFAULT:  %s\n", f.Default)
+		}
+		out.WriteString("\n")
+	}
+	fmt.Fprintf(&out, "DESCRIPTION:\n%s\n", wrap(f.Description, "     "))
+	if len(f.Fields) > 0 {
+		out.WriteString("\nFIELDS:\n")
+		for _, field := range f.Fields {
+			fmt.Fprintf(&out, "   %s\t<%s>\n%s\n\n", field.Name(), field.Type, wrap(field.Description, "     "))
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// defaultConfig returns a config with one nodegroup of each kind, where all the defaults are set
+func defaultConfig() *api.ClusterConfig {
+	cfg := api.NewClusterConfig()
+	api.SetClusterConfigDefaults(cfg)
+	api.SetNodeGroupDefaults(cfg.NewNodeGroup(), cfg.Metadata)
+	managedNodeGroup := api.NewManagedNodeGroup()
+	api.SetManagedNodeGroupDefaults(managedNodeGroup, cfg.Metadata)
+	cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, managedNodeGroup)
+	return cfg
+}
+
+func listFields(t reflect.Type, path string) []*Field {
+	var fields []*Field
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		name, inline := jsonName(structField)
+		switch {
+		case inline:
+			fields = append(fields, listFields(structType(structField.Type), path)...)
+		case name != "":
+			if path != "" {
+				name = path + "." + name
+			}
+			description, since := api.FieldDoc(t.Name(), structField.Name)
+			if description == "" {
+				if fieldType := structType(structField.Type); fieldType != nil {
+					description, _ = api.FieldDoc(fieldType.Name(), "")
+				}
+			}
+			fields = append(fields, &Field{
+				Path:        name,
+				Type:        typeName(structField.Type),
+				Description: description,
+				Since:       since,
+			})
+		}
+	}
+	return fields
+}
+
+func findField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		fieldName, inline := jsonName(structField)
+		if inline {
+			if inlined, ok := findField(structType(structField.Type), name); ok {
+				inlined.Index = append([]int{i}, inlined.Index...)
+				return inlined, true
+			}
+		} else if fieldName == name {
+			return structField, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// jsonName returns the name of a field in the config file, it's empty for fields
+// that aren't serialised; inline is true for embedded structs whose fields are
+// serialised as fields of the parent
+func jsonName(f reflect.StructField) (name string, inline bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+	tag := f.Tag.Get("json")
+	name = strings.Split(tag, ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if f.Anonymous && name == "" {
+		return "", structType(f.Type) != nil
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, false
+}
+
+// structType returns the struct type of objects and lists of objects, or nil
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isScalar(t) {
+		return nil
+	}
+	return t
+}
+
+func typeName(t reflect.Type) string {
+	if isScalar(t) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeName(t.Elem())
+	case reflect.Struct, reflect.Interface:
+		return "Object"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "[]" + typeName(t.Elem())
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return "Object"
+		}
+		return fmt.Sprintf("map[%s]%s", typeName(t.Key()), typeName(t.Elem()))
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	default:
+		return "integer"
+	}
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// isScalar reports whether values of type t are serialised as strings by a custom
+// marshaler, e.g. CIDRs
+func isScalar(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && (t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType))
+}
+
+// fieldValue returns the value of a field of v, v being an object or a list of objects
+// whose first item is used; the result is invalid if the object is not set
+func fieldValue(v reflect.Value, index []int) reflect.Value {
+	v = indirect(v)
+	if v.Kind() == reflect.Slice {
+		if v.Len() == 0 {
+			return reflect.Value{}
+		}
+		v = indirect(v.Index(0))
+	}
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.FieldByIndex(index)
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// wrap splits text in lines of at most 80 characters, with the given indentation
+func wrap(text, indent string) string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return indent + "<empty>"
+	}
+	var (
+		lines []string
+		line  = indent + words[0]
+	)
+	for _, word := range words[1:] {
+		if len(line)+1+len(word) > 80 {
+			lines = append(lines, line)
+			line = indent + word
+		} else {
+			line += " " + word
+		}
+	}
+	return strings.Join(append(lines, line), "\n")
+}
@@ -0,0 +1,83 @@
+package explain_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/explain"
+)
+
+var _ = Describe("Explain", func() {
+	It("should describe the config file", func() {
+		field, err := Explain("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Type).To(Equal("Object"))
+
+		var names []string
+		for _, f := range field.Fields {
+			names = append(names, f.Name())
+		}
+		Expect(names).To(ContainElement("apiVersion"))
+		Expect(names).To(ContainElement("nodeGroups"))
+	})
+
+	It("should describe objects in lists", func() {
+		field, err := Explain("nodeGroups.iam.withAddonPolicies")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Type).To(Equal("Object"))
+		Expect(field.Description).To(HavePrefix("WithAddonPolicies attaches"))
+		Expect(field.Fields).NotTo(BeEmpty())
+		Expect(field.Fields[0].Path).To(Equal("nodeGroups.iam.withAddonPolicies.imageBuilder"))
+		Expect(field.Fields[0].Type).To(Equal("boolean"))
+	})
+
+	It("should describe scalar fields with their defaults", func() {
+		field, err := Explain("nodeGroups.iam.withAddonPolicies.autoScaler")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Type).To(Equal("boolean"))
+		Expect(field.Default).To(Equal("false"))
+
+		field, err = Explain("nodeGroups.instanceType")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Type).To(Equal("string"))
+		Expect(field.Default).To(Equal("m5.large"))
+
+		field, err = Explain("vpc.cidr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Type).To(Equal("string"))
+	})
+
+	It("should describe the version that introduced a field", func() {
+		field, err := Explain("nodeGroups.kernelModules")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Type).To(Equal("[]string"))
+		Expect(field.Since).To(Equal("0.19.0"))
+	})
+
+	It("should fail for unknown fields", func() {
+		_, err := Explain("nodeGroups.foo")
+		Expect(err).To(MatchError(`field "nodeGroups.foo" does not exist`))
+
+		_, err = Explain("metadata.name.foo")
+		Expect(err).To(MatchError(`field "metadata.name" has no fields`))
+	})
+
+	It("should print the description", func() {
+		field, err := Explain("nodeGroups.iam.withAddonPolicies.autoScaler")
+		Expect(err).NotTo(HaveOccurred())
+
+		out := &bytes.Buffer{}
+		Expect(field.Print(out)).To(Succeed())
+		Expect(out.String()).To(Equal(`KIND:     ClusterConfig
+VERSION:  eksctl.io/v1alpha5
+
+FIELD:    autoScaler <boolean>
+DEFAULT:  false
+
+DESCRIPTION:
+     AutoScaler allows the cluster-autoscaler to manage the ASGs
+`))
+	})
+})
@@ -0,0 +1,177 @@
+package explain
+
+import (
+	"reflect"
+
+	"github.com/alecthomas/jsonschema"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// requiredFields are the fields that must be set in a config file, the other fields either
+// have defaults or are only required in some cases, which is checked by eksctl
+var requiredFields = map[string][]string{
+	"ClusterConfig":    {"apiVersion", "kind", "metadata"},
+	"ClusterMeta":      {"name"},
+	"NodeGroup":        {"name"},
+	"ManagedNodeGroup": {"name"},
+	"FargateProfile":   {"name"},
+}
+
+// Schema returns the JSON Schema of the config file, generated from the Go types and annotated
+// with the documentation, the valid values and the defaults of the fields
+func Schema() *jsonschema.Schema {
+	schema := jsonschema.Reflect(&api.ClusterConfig{})
+
+	types := map[string]reflect.Type{}
+	collectTypes(reflect.TypeOf(api.ClusterConfig{}), types)
+	defaults := map[string]reflect.Value{}
+	collectDefaults(reflect.ValueOf(defaultConfig()), defaults)
+
+	for name, definition := range schema.Definitions {
+		t, ok := types[name]
+		if !ok {
+			continue
+		}
+		inlineFields(t, definition, schema.Definitions)
+		definition.Required = requiredFields[name]
+		definition.Description, _ = api.FieldDoc(name, "")
+
+		for propertyName, property := range definition.Properties {
+			structField, ok := findField(t, propertyName)
+			if !ok {
+				continue
+			}
+			if len(structField.Index) > 1 {
+				// inlined fields are documented by the definition of their struct
+				continue
+			}
+			property.Description, _ = api.FieldDoc(name, structField.Name)
+			if property.Description == "" {
+				if fieldType := structType(structField.Type); fieldType != nil {
+					property.Description, _ = api.FieldDoc(fieldType.Name(), "")
+				}
+			}
+
+			if enum := api.FieldEnum(name, structField.Name); len(enum) > 0 {
+				setEnum(property, enum...)
+			}
+
+			if value, ok := defaults[name]; ok {
+				if defaultValue, ok := scalarDefault(fieldValue(value, structField.Index)); ok {
+					property.Default = defaultValue
+				}
+			}
+		}
+	}
+
+	if clusterConfig, ok := schema.Definitions["ClusterConfig"]; ok {
+		setEnum(clusterConfig.Properties["apiVersion"], api.SchemeGroupVersion.String())
+		setEnum(clusterConfig.Properties["kind"], api.ClusterConfigKind)
+	}
+	if nodeGroup, ok := schema.Definitions["NodeGroup"]; ok {
+		if maxPods := nodeGroup.Properties["maxPodsPerNode"]; maxPods != nil {
+			// PodsPerNode is serialised as a number or auto
+			maxPods.Type = ""
+			maxPods.OneOf = []*jsonschema.Type{{Type: "integer"}, {Type: "string", Enum: []interface{}{api.MaxPodsPerNodeAuto.String()}}}
+		}
+	}
+	return schema
+}
+
+func setEnum(property *jsonschema.Type, values ...string) {
+	if property == nil {
+		return
+	}
+	if property.Items != nil {
+		property = property.Items
+	}
+	property.Enum = nil
+	for _, value := range values {
+		property.Enum = append(property.Enum, value)
+	}
+}
+
+// inlineFields replaces the properties of the embedded structs that are serialised inline,
+// e.g. the TypeMeta of ClusterConfig, with the properties of the structs
+func inlineFields(t reflect.Type, definition *jsonschema.Type, definitions jsonschema.Definitions) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if _, inline := jsonName(structField); !inline {
+			continue
+		}
+		inlined, ok := definitions[structType(structField.Type).Name()]
+		if !ok {
+			continue
+		}
+		delete(definition.Properties, structField.Name)
+		for name, property := range inlined.Properties {
+			definition.Properties[name] = property
+		}
+	}
+}
+
+// collectTypes collects the struct types of the config file by name
+func collectTypes(t reflect.Type, types map[string]reflect.Type) {
+	t = structType(t)
+	if t == nil {
+		return
+	}
+	if _, ok := types[t.Name()]; ok {
+		return
+	}
+	types[t.Name()] = t
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i).Type
+		if fieldType.Kind() == reflect.Map {
+			fieldType = fieldType.Elem()
+		}
+		collectTypes(fieldType, types)
+	}
+}
+
+// collectDefaults collects the first object of each struct type in a config where all the
+// defaults are set
+func collectDefaults(v reflect.Value, defaults map[string]reflect.Value) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			collectDefaults(v.Index(i), defaults)
+		}
+	case reflect.Struct:
+		if isScalar(v.Type()) {
+			return
+		}
+		if _, ok := defaults[v.Type().Name()]; ok {
+			return
+		}
+		defaults[v.Type().Name()] = v
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				collectDefaults(v.Field(i), defaults)
+			}
+		}
+	}
+}
+
+// scalarDefault returns the value of a field if it's set and is a scalar or a list of scalars;
+// the values of maps, e.g. labels, depend on the names of the cluster and nodegroup
+func scalarDefault(v reflect.Value) (interface{}, bool) {
+	v = indirect(v)
+	if !v.IsValid() || v.IsZero() {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return v.Interface(), true
+	case reflect.Slice:
+		if kind := v.Type().Elem().Kind(); kind == reflect.String || kind == reflect.Int {
+			return v.Interface(), true
+		}
+	}
+	return nil, false
+}
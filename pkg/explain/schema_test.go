@@ -0,0 +1,47 @@
+package explain_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/explain"
+)
+
+var _ = Describe("Schema", func() {
+	It("should replace the embedded TypeMeta with the apiVersion and kind fields", func() {
+		clusterConfig := Schema().Definitions["ClusterConfig"]
+		Expect(clusterConfig.Properties).NotTo(HaveKey("TypeMeta"))
+		Expect(clusterConfig.Properties["apiVersion"].Enum).To(ConsistOf(api.SchemeGroupVersion.String()))
+		Expect(clusterConfig.Properties["kind"].Enum).To(ConsistOf(api.ClusterConfigKind))
+		Expect(clusterConfig.Required).To(ConsistOf("apiVersion", "kind", "metadata"))
+	})
+
+	It("should only require the fields without defaults", func() {
+		nodeGroup := Schema().Definitions["NodeGroup"]
+		Expect(nodeGroup.Required).To(ConsistOf("name"))
+	})
+
+	It("should describe the fields with their valid values and defaults", func() {
+		nodeGroup := Schema().Definitions["NodeGroup"]
+		Expect(nodeGroup.Description).NotTo(BeEmpty())
+
+		volumeType := nodeGroup.Properties["volumeType"]
+		Expect(volumeType.Description).NotTo(BeEmpty())
+		Expect(volumeType.Enum).To(ContainElement(api.NodeVolumeTypeGP2))
+		Expect(volumeType.Default).To(Equal(api.DefaultNodeVolumeType))
+
+		Expect(nodeGroup.Properties["instanceType"].Default).To(Equal(api.DefaultNodeType))
+	})
+
+	It("should accept a number or auto for maxPodsPerNode", func() {
+		maxPods := Schema().Definitions["NodeGroup"].Properties["maxPodsPerNode"]
+		Expect(maxPods.OneOf).To(HaveLen(2))
+		Expect(maxPods.OneOf[1].Enum).To(ConsistOf("auto"))
+	})
+
+	It("should set the valid values of lists on their items", func() {
+		logging := Schema().Definitions["ClusterCloudWatchLogging"]
+		Expect(logging.Properties["enableTypes"].Items.Enum).To(ContainElement("api"))
+	})
+})
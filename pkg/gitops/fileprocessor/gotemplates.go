@@ -5,10 +5,11 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/kris-nova/logger"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -20,14 +21,22 @@ const (
 type TemplateParameters struct {
 	ClusterName string
 	Region      string
+	AccountID   string
 }
 
-// NewTemplateParameters creates a set of variables for templating given a ClusterConfig object
+// NewTemplateParameters creates a set of variables for templating given a ClusterConfig object, the
+// account being the one of the ARN of the cluster, when its status has been fetched
 func NewTemplateParameters(clusterConfig *api.ClusterConfig) TemplateParameters {
-	return TemplateParameters{
+	params := TemplateParameters{
 		ClusterName: clusterConfig.Metadata.Name,
 		Region:      clusterConfig.Metadata.Region,
 	}
+	if clusterConfig.Status != nil {
+		if parsedARN, err := arn.Parse(clusterConfig.Status.ARN); err == nil {
+			params.AccountID = parsedARN.AccountID
+		}
+	}
+	return params
 }
 
 // GoTemplateProcessor is a FileProcessor that executes Go Templates
@@ -0,0 +1,183 @@
+package flux
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeclient "k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/git/executor"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	// Command is the Flux CLI, which bootstraps Flux v2
+	Command = "flux"
+
+	bootstrapAnnotationPrefix = "eksctl.io/flux-"
+	// deployKeySecretName is the secret Flux stores its deploy key in
+	deployKeySecretName = "flux-system"
+)
+
+// tokenEnvVars are the environment variables of the tokens Flux creates repositories and deploy keys with
+var tokenEnvVars = map[string]string{
+	api.GitProviderGitHub: "GITHUB_TOKEN",
+	api.GitProviderGitLab: "GITLAB_TOKEN",
+}
+
+// CheckBootstrap checks that the Flux CLI, and the token of the Git provider, are available, so
+// that bootstrapping Flux fails early when they're not
+func CheckBootstrap(flux *api.Flux) error {
+	if _, err := exec.LookPath(Command); err != nil {
+		return fmt.Errorf("%s must be on the PATH to bootstrap Flux: %v", Command, err)
+	}
+	if env, ok := tokenEnvVars[flux.GitProvider]; ok && os.Getenv(env) == "" {
+		return fmt.Errorf("%s must be set to bootstrap Flux with gitProvider %s", env, flux.GitProvider)
+	}
+	return nil
+}
+
+// Bootstrapper bootstraps Flux v2 with the Flux CLI
+type Bootstrapper struct {
+	executor       executor.Executor
+	clientSet      kubeclient.Interface
+	kubeconfigPath string
+}
+
+// NewBootstrapper creates a Bootstrapper running the Flux CLI against the cluster of the kubeconfig
+func NewBootstrapper(executor executor.Executor, clientSet kubeclient.Interface, kubeconfigPath string) *Bootstrapper {
+	return &Bootstrapper{
+		executor:       executor,
+		clientSet:      clientSet,
+		kubeconfigPath: kubeconfigPath,
+	}
+}
+
+// Bootstrap installs Flux in the cluster and commits its manifests to the repository, unless it's
+// already been bootstrapped with the same settings and force is false; the settings are recorded as
+// annotations of the namespace of Flux, so that running it again is a no-op
+func (b *Bootstrapper) Bootstrap(flux *api.Flux, force bool) error {
+	state := bootstrapState(flux)
+
+	namespace, err := b.clientSet.CoreV1().Namespaces().Get(flux.Namespace, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && !force && isBootstrapped(namespace.Annotations, state) {
+		logger.Info("Flux is already bootstrapped in namespace %q with the same settings, use --force to bootstrap it again", flux.Namespace)
+		return nil
+	}
+
+	if err := b.executor.Exec(Command, "", "check", "--pre", "--kubeconfig", b.kubeconfigPath); err != nil {
+		return errors.Wrap(err, "checking the prerequisites of Flux")
+	}
+	logger.Info("bootstrapping Flux with %s repository %s", flux.GitProvider, flux.Repository)
+	if err := b.executor.Exec(Command, "", bootstrapArgs(flux, b.kubeconfigPath)...); err != nil {
+		return errors.Wrap(err, "bootstrapping Flux")
+	}
+
+	if err := b.recordState(flux.Namespace, state); err != nil {
+		return err
+	}
+	b.logDeployKey(flux)
+	logger.Success("bootstrapped Flux, syncing %s of branch %s", flux.Path, flux.Branch)
+	return nil
+}
+
+func bootstrapArgs(flux *api.Flux, kubeconfigPath string) []string {
+	args := []string{
+		"bootstrap", flux.GitProvider,
+		"--kubeconfig", kubeconfigPath,
+		"--namespace", flux.Namespace,
+		"--branch", flux.Branch,
+		"--path", flux.Path,
+		"--silent",
+	}
+	if flux.GitProvider == api.GitProviderGit {
+		args = append(args, "--url", flux.Repository)
+		if flux.PrivateKeyFile != "" {
+			args = append(args, "--private-key-file", flux.PrivateKeyFile)
+		}
+	} else {
+		args = append(args, "--owner", flux.Owner, "--repository", flux.Repository)
+		if api.IsEnabled(flux.Personal) {
+			args = append(args, "--personal")
+		}
+		if flux.Hostname != "" {
+			args = append(args, "--hostname", flux.Hostname)
+		}
+	}
+	for _, flag := range sortedFlags(flux.Flags) {
+		args = append(args, "--"+flag)
+	}
+	return args
+}
+
+func sortedFlags(flags map[string]string) []string {
+	var sorted []string
+	for name, value := range flags {
+		sorted = append(sorted, fmt.Sprintf("%s=%s", strings.TrimLeft(name, "-"), value))
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// bootstrapState returns the annotations recording the settings Flux is bootstrapped with
+func bootstrapState(flux *api.Flux) map[string]string {
+	return map[string]string{
+		bootstrapAnnotationPrefix + "git-provider": flux.GitProvider,
+		bootstrapAnnotationPrefix + "repository":   flux.Repository,
+		bootstrapAnnotationPrefix + "owner":        flux.Owner,
+		bootstrapAnnotationPrefix + "hostname":     flux.Hostname,
+		bootstrapAnnotationPrefix + "branch":       flux.Branch,
+		bootstrapAnnotationPrefix + "path":         flux.Path,
+		bootstrapAnnotationPrefix + "flags":        strings.Join(sortedFlags(flux.Flags), ","),
+	}
+}
+
+func isBootstrapped(annotations, state map[string]string) bool {
+	for key, value := range state {
+		if recorded, ok := annotations[key]; !ok || recorded != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Bootstrapper) recordState(namespace string, state map[string]string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": state,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := b.clientSet.CoreV1().Namespaces().Patch(namespace, types.MergePatchType, patch); err != nil {
+		return errors.Wrapf(err, "recording the bootstrap state of Flux in namespace %q", namespace)
+	}
+	return nil
+}
+
+// logDeployKey logs where the deploy key of Flux is stored, and its public key when it was generated
+// by Flux for a git repository, as it must then be given access to the repository
+func (b *Bootstrapper) logDeployKey(flux *api.Flux) {
+	secret, err := b.clientSet.CoreV1().Secrets(flux.Namespace).Get(deployKeySecretName, metav1.GetOptions{})
+	if err != nil {
+		logger.Warning("unable to read the deploy key of Flux from secret %s/%s: %v", flux.Namespace, deployKeySecretName, err)
+		return
+	}
+	logger.Info("the deploy key of Flux is stored in secret %s/%s", flux.Namespace, deployKeySecretName)
+	if flux.GitProvider == api.GitProviderGit && flux.PrivateKeyFile == "" {
+		logger.Warning("give the following deploy key read and write access to %s:\n%s", flux.Repository, strings.TrimSpace(string(secret.Data["identity.pub"])))
+	}
+}
@@ -0,0 +1,102 @@
+package flux
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/git/executor"
+)
+
+var _ = Describe("Bootstrapper", func() {
+	var (
+		fakeExecutor *executor.FakeExecutor
+		clientSet    *fake.Clientset
+		cfg          *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		fakeExecutor = new(executor.FakeExecutor)
+		fakeExecutor.On("Exec", Command, "", mock.Anything).Return(nil)
+		clientSet = fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "flux-system"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"}},
+		)
+
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.GitOps = &api.GitOps{
+			Flux: &api.Flux{
+				GitProvider: api.GitProviderGitHub,
+				Repository:  "gitops",
+				Owner:       "weaveworks",
+				Personal:    api.Enabled(),
+				Flags:       map[string]string{"components-extra": "image-reflector-controller"},
+			},
+		}
+		api.SetFluxDefaults(cfg)
+	})
+
+	bootstrap := func(force bool) {
+		Expect(NewBootstrapper(fakeExecutor, clientSet, "/tmp/kubeconfig").Bootstrap(cfg.GitOps.Flux, force)).To(Succeed())
+	}
+
+	It("bootstraps Flux with the flux CLI and records its settings", func() {
+		bootstrap(false)
+
+		fakeExecutor.AssertCalled(GinkgoT(), "Exec", Command, "", []string{"check", "--pre", "--kubeconfig", "/tmp/kubeconfig"})
+		fakeExecutor.AssertCalled(GinkgoT(), "Exec", Command, "", []string{
+			"bootstrap", "github",
+			"--kubeconfig", "/tmp/kubeconfig",
+			"--namespace", "flux-system",
+			"--branch", "main",
+			"--path", "clusters/cluster-1",
+			"--silent",
+			"--owner", "weaveworks",
+			"--repository", "gitops",
+			"--personal",
+			"--components-extra=image-reflector-controller",
+		})
+
+		namespace, err := clientSet.CoreV1().Namespaces().Get("flux-system", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespace.Annotations).To(HaveKeyWithValue("eksctl.io/flux-repository", "gitops"))
+		Expect(namespace.Annotations).To(HaveKeyWithValue("eksctl.io/flux-path", "clusters/cluster-1"))
+	})
+
+	It("doesn't bootstrap Flux again with the same settings unless forced", func() {
+		bootstrap(false)
+		bootstrap(false)
+		fakeExecutor.AssertNumberOfCalls(GinkgoT(), "Exec", 2)
+
+		bootstrap(true)
+		fakeExecutor.AssertNumberOfCalls(GinkgoT(), "Exec", 4)
+
+		cfg.GitOps.Flux.Branch = "production"
+		bootstrap(false)
+		fakeExecutor.AssertNumberOfCalls(GinkgoT(), "Exec", 6)
+	})
+
+	It("passes the URL and private key of git repositories", func() {
+		cfg.GitOps.Flux = &api.Flux{
+			GitProvider:    api.GitProviderGit,
+			Repository:     "ssh://git@example.com/org/gitops",
+			PrivateKeyFile: "/home/user/.ssh/id_ed25519",
+		}
+		api.SetFluxDefaults(cfg)
+		Expect(bootstrapArgs(cfg.GitOps.Flux, "/tmp/kubeconfig")).To(Equal([]string{
+			"bootstrap", "git",
+			"--kubeconfig", "/tmp/kubeconfig",
+			"--namespace", "flux-system",
+			"--branch", "main",
+			"--path", "clusters/cluster-1",
+			"--silent",
+			"--url", "ssh://git@example.com/org/gitops",
+			"--private-key-file", "/home/user/.ssh/id_ed25519",
+		}))
+	})
+})
@@ -9,10 +9,11 @@ import (
 	"time"
 
 	portforward "github.com/justinbarrick/go-k8s-portforward"
-	"github.com/kris-nova/logger"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 type PublicKey struct {
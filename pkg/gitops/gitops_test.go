@@ -53,7 +53,7 @@ var _ = Describe("gitops profile", func() {
 			outputDir, _ = io.TempDir("", "test-output-dir-")
 
 			processor = &fileprocessor.GoTemplateProcessor{
-				Params: fileprocessor.TemplateParameters{ClusterName: "test-cluster"},
+				Params: fileprocessor.TemplateParameters{ClusterName: "test-cluster", Region: "eu-north-1", AccountID: "123456789012"},
 			}
 			profile = &Profile{
 				Path: outputDir,
@@ -84,6 +84,10 @@ var _ = Describe("gitops profile", func() {
 			template2, err := io.ReadFile(filepath.Join(outputDir, "a/b/good-template2.yaml"))
 			Expect(err).ToNot(HaveOccurred())
 			Expect(template2).To(MatchYAML([]byte("name: test-cluster")))
+
+			template3, err := io.ReadFile(filepath.Join(outputDir, "a/good-template3.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(template3).To(MatchYAML([]byte("role: arn:aws:iam::123456789012:role/eu-north-1-test-cluster")))
 		})
 
 		It("can load files and ignore .git/ files", func() {
@@ -93,12 +97,16 @@ var _ = Describe("gitops profile", func() {
 			files, err := profile.loadFiles(testDir)
 
 			Expect(err).ToNot(HaveOccurred())
-			Expect(files).To(HaveLen(4))
+			Expect(files).To(HaveLen(5))
 			Expect(files).To(ConsistOf(
 				fileprocessor.File{
 					Path: filepath.Join(testDir, "a/good-template1.yaml.tmpl"),
 					Data: []byte("cluster: {{ .ClusterName }}"),
 				},
+				fileprocessor.File{
+					Path: filepath.Join(testDir, "a/good-template3.yaml.tmpl"),
+					Data: []byte(accountTemplate),
+				},
 				fileprocessor.File{
 					Path: filepath.Join(testDir, "a/b/good-template2.yaml.tmpl"),
 					Data: []byte("name: {{ .ClusterName }}"),
@@ -177,11 +185,14 @@ metadata:
 	})
 })
 
+const accountTemplate = "role: arn:aws:iam::{{ .AccountID }}:role/{{ .Region }}-{{ .ClusterName }}"
+
 func createTestFiles(testDir string, memFs afero.Fs) {
 	createFile(memFs, filepath.Join(testDir, "not-a-template.yaml"), "somekey: value")
 	createFile(memFs, filepath.Join(testDir, "a/not-a-template2.yaml"), "somekey2: value2")
 	createFile(memFs, filepath.Join(testDir, "a/good-template1.yaml.tmpl"), "cluster: {{ .ClusterName }}")
 	createFile(memFs, filepath.Join(testDir, "a/b/good-template2.yaml.tmpl"), "name: {{ .ClusterName }}")
+	createFile(memFs, filepath.Join(testDir, "a/good-template3.yaml.tmpl"), accountTemplate)
 	memFs.Mkdir(".git", 0755)
 	createFile(memFs, filepath.Join(testDir, ".git/some-git-file"), "this is a git file and should be ignored")
 	createFile(memFs, filepath.Join(testDir, ".git/some-git-file.yaml"), "this is a git file and should be ignored")
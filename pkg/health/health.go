@@ -0,0 +1,292 @@
+// Package health aggregates the problems of a cluster, its nodegroups, its default addons,
+// its control plane and its auth ConfigMap into a single report
+package health
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/nodegroup"
+)
+
+// Components of a cluster that issues are reported for
+const (
+	ComponentCluster      = "cluster"
+	ComponentNodeGroup    = "nodegroup"
+	ComponentAddon        = "addon"
+	ComponentControlPlane = "control-plane"
+	ComponentAuth         = "aws-auth"
+)
+
+// Issue is a problem found in a component of a cluster
+type Issue struct {
+	Component string
+	// Name is the name of the nodegroup, addon or auth ConfigMap entry the issue is about, if any
+	Name    string `json:",omitempty"`
+	Message string
+}
+
+// Report is the result of checking the health of a cluster
+type Report struct {
+	Cluster string
+	Issues  []Issue
+}
+
+// Healthy returns whether no issues were found
+func (r *Report) Healthy() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *Report) add(component, name, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{
+		Component: component,
+		Name:      name,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// MetricsGetter returns the metrics of the API server in the Prometheus text format
+type MetricsGetter func() ([]byte, error)
+
+// NewMetricsGetter returns a MetricsGetter reading the /metrics endpoint of the API server
+func NewMetricsGetter(clientSet kubernetes.Interface) MetricsGetter {
+	return func() ([]byte, error) {
+		return clientSet.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw()
+	}
+}
+
+// Checker checks the health of a cluster
+type Checker struct {
+	provider     api.ClusterProvider
+	stackManager *manager.StackCollection
+	clientSet    kubernetes.Interface
+	getMetrics   MetricsGetter
+	spec         *api.ClusterConfig
+}
+
+// NewChecker creates a new Checker
+func NewChecker(provider api.ClusterProvider, stackManager *manager.StackCollection, clientSet kubernetes.Interface, getMetrics MetricsGetter, spec *api.ClusterConfig) *Checker {
+	return &Checker{
+		provider:     provider,
+		stackManager: stackManager,
+		clientSet:    clientSet,
+		getMetrics:   getMetrics,
+		spec:         spec,
+	}
+}
+
+// Check checks every component of the cluster; a component that can't be checked is reported
+// as an issue rather than failing the whole report, since it's usually a symptom in itself
+func (c *Checker) Check() (*Report, error) {
+	report := &Report{Cluster: c.spec.Metadata.Name}
+
+	output, err := c.provider.EKS().DescribeCluster(&awseks.DescribeClusterInput{
+		Name: &c.spec.Metadata.Name,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing cluster %q", c.spec.Metadata.Name)
+	}
+	if status := aws.StringValue(output.Cluster.Status); status != awseks.ClusterStatusActive {
+		report.add(ComponentCluster, "", "cluster is %s", status)
+	}
+
+	summaries, err := c.stackManager.GetNodeGroupSummaries("")
+	if err != nil {
+		return nil, errors.Wrap(err, "getting nodegroup stack summaries")
+	}
+	c.checkNodeGroups(report, summaries)
+	c.checkAddons(report)
+	c.checkControlPlane(report)
+	c.checkAuthConfigMap(report, summaries)
+
+	return report, nil
+}
+
+func (c *Checker) checkNodeGroups(report *Report, summaries []*manager.NodeGroupSummary) {
+	for _, summary := range summaries {
+		if err := nodegroup.GetStatus(c.provider, c.stackManager, c.spec.Metadata.Name, []*manager.NodeGroupSummary{summary}); err != nil {
+			report.add(ComponentNodeGroup, summary.Name, "%v", err)
+			continue
+		}
+		status := summary.Status
+		if status.Status != awseks.NodegroupStatusActive {
+			report.add(ComponentNodeGroup, summary.Name, "nodegroup is %s", status.Status)
+		}
+		if status.Nodes < status.DesiredCapacity {
+			report.add(ComponentNodeGroup, summary.Name, "%d of %d desired nodes are in service", status.Nodes, status.DesiredCapacity)
+		}
+		for _, issue := range status.HealthIssues {
+			report.add(ComponentNodeGroup, summary.Name, "%s", issue)
+		}
+	}
+}
+
+// checkAddons checks that the pods of the default addons are ready
+func (c *Checker) checkAddons(report *Report) {
+	for _, name := range []string{"aws-node", "kube-proxy"} {
+		ds, err := c.clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(name, metav1.GetOptions{})
+		if err != nil {
+			report.add(ComponentAddon, name, "%s", addonError(err))
+			continue
+		}
+		if ready, desired := ds.Status.NumberReady, ds.Status.DesiredNumberScheduled; ready < desired {
+			report.add(ComponentAddon, name, "%d of %d pods are ready", ready, desired)
+		}
+	}
+
+	deployment, err := c.clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Get("coredns", metav1.GetOptions{})
+	if err != nil {
+		report.add(ComponentAddon, "coredns", "%s", addonError(err))
+		return
+	}
+	if ready, desired := deployment.Status.ReadyReplicas, aws.Int32Value(deployment.Spec.Replicas); ready < desired {
+		report.add(ComponentAddon, "coredns", "%d of %d pods are ready", ready, desired)
+	}
+}
+
+func addonError(err error) string {
+	if apierrors.IsNotFound(err) {
+		return "addon is not installed"
+	}
+	return err.Error()
+}
+
+// throttlingMetrics are the counters of the API server that grow when it throttles requests
+var throttlingMetrics = map[string]string{
+	"apiserver_dropped_requests_total": "requests were dropped by the API server because it had too many requests in flight",
+	"apiserver_request_total":          "requests were answered with 429 Too Many Requests",
+}
+
+func (c *Checker) checkControlPlane(report *Report) {
+	metrics, err := c.getMetrics()
+	if err != nil {
+		report.add(ComponentControlPlane, "", "reading the metrics of the API server: %v", err)
+		return
+	}
+	report.Issues = append(report.Issues, throttlingIssues(string(metrics))...)
+}
+
+// throttlingIssues sums the throttling counters of the API server, requests only being
+// counted as throttled by apiserver_request_total when their code is 429
+func throttlingIssues(metrics string) []Issue {
+	totals := map[string]float64{}
+	scanner := bufio.NewScanner(strings.NewReader(metrics))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		name, labels := fields[0], ""
+		if i := strings.Index(name, "{"); i >= 0 {
+			name, labels = name[:i], name[i:]
+		}
+		if _, ok := throttlingMetrics[name]; !ok {
+			continue
+		}
+		if name == "apiserver_request_total" && !strings.Contains(labels, `code="429"`) {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		totals[name] += value
+	}
+
+	var issues []Issue
+	for _, name := range []string{"apiserver_dropped_requests_total", "apiserver_request_total"} {
+		if total := totals[name]; total > 0 {
+			issues = append(issues, Issue{
+				Component: ComponentControlPlane,
+				Name:      name,
+				Message:   fmt.Sprintf("%.0f %s", total, throttlingMetrics[name]),
+			})
+		}
+	}
+	return issues
+}
+
+func (c *Checker) checkAuthConfigMap(report *Report, summaries []*manager.NodeGroupSummary) {
+	acm, err := authconfigmap.NewFromClientSet(c.clientSet)
+	if err != nil {
+		report.add(ComponentAuth, "", "%v", err)
+		return
+	}
+	identities, err := acm.Identities()
+	if err != nil {
+		report.add(ComponentAuth, "", "%v", err)
+		return
+	}
+	var nodeRoleARNs []string
+	for _, summary := range summaries {
+		if summary.NodeInstanceRoleARN != "" {
+			nodeRoleARNs = append(nodeRoleARNs, summary.NodeInstanceRoleARN)
+		}
+	}
+	report.Issues = append(report.Issues, authIssues(identities, nodeRoleARNs)...)
+}
+
+// authIssues finds the entries of the auth ConfigMap that can't work: invalid ARNs, role ARNs with a path,
+// which the authenticator doesn't match, entries without a Kubernetes identity, duplicated entries, and
+// the instance roles of nodegroups that aren't mapped to the system:nodes group
+func authIssues(identities []iam.Identity, nodeRoleARNs []string) []Issue {
+	var issues []Issue
+	add := func(arn, format string, args ...interface{}) {
+		issues = append(issues, Issue{
+			Component: ComponentAuth,
+			Name:      arn,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	seen := map[string]bool{}
+	nodeRoles := map[string]bool{}
+	for _, identity := range identities {
+		arn := identity.ARN()
+		if seen[arn] {
+			add(arn, "%s is mapped more than once, only the first entry is used", identity.Type())
+		}
+		seen[arn] = true
+
+		parsed, err := iam.Parse(arn)
+		if err != nil {
+			add(arn, "invalid ARN: %v", err)
+			continue
+		}
+		if parsed.IsRole() && strings.Count(parsed.Resource, "/") > 1 {
+			add(arn, "role ARN has a path, which the authenticator doesn't match; remove the path from the ARN")
+		}
+		if identity.Username() == "" && len(identity.Groups()) == 0 {
+			add(arn, "neither username nor groups are set")
+		}
+		for _, group := range identity.Groups() {
+			if group == "system:nodes" {
+				nodeRoles[arn] = true
+			}
+		}
+	}
+
+	for _, arn := range nodeRoleARNs {
+		if !nodeRoles[arn] {
+			add(arn, "instance role of a nodegroup isn't mapped to the system:nodes group, its nodes can't join the cluster")
+		}
+	}
+	return issues
+}
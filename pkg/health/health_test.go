@@ -0,0 +1,110 @@
+package health
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/iam"
+)
+
+var _ = Describe("cluster health", func() {
+	Describe("control plane throttling", func() {
+		It("sums the dropped and the throttled requests", func() {
+			metrics := `# HELP apiserver_dropped_requests_total Number of requests dropped
+# TYPE apiserver_dropped_requests_total counter
+apiserver_dropped_requests_total{requestKind="mutating"} 3
+apiserver_dropped_requests_total{requestKind="readOnly"} 2
+apiserver_request_total{code="200",resource="pods",verb="LIST"} 1000
+apiserver_request_total{code="429",resource="pods",verb="LIST"} 7
+apiserver_request_total{code="429",resource="nodes",verb="GET"} 1
+`
+			Expect(throttlingIssues(metrics)).To(Equal([]Issue{
+				{
+					Component: ComponentControlPlane,
+					Name:      "apiserver_dropped_requests_total",
+					Message:   "5 requests were dropped by the API server because it had too many requests in flight",
+				},
+				{
+					Component: ComponentControlPlane,
+					Name:      "apiserver_request_total",
+					Message:   "8 requests were answered with 429 Too Many Requests",
+				},
+			}))
+		})
+
+		It("reports nothing when no requests were throttled", func() {
+			Expect(throttlingIssues(`apiserver_request_total{code="200"} 10`)).To(BeEmpty())
+		})
+	})
+
+	Describe("aws-auth", func() {
+		const nodeRole = "arn:aws:iam::123456789012:role/eksctl-cluster-nodegroup-ng-1-NodeInstanceRole"
+
+		role := func(arn, username string, groups ...string) iam.Identity {
+			return iam.RoleIdentity{
+				RoleARN: arn,
+				KubernetesIdentity: iam.KubernetesIdentity{
+					KubernetesUsername: username,
+					KubernetesGroups:   groups,
+				},
+			}
+		}
+
+		It("accepts well-formed entries", func() {
+			identities := []iam.Identity{
+				role(nodeRole, "system:node:{{EC2PrivateDNSName}}", "system:bootstrappers", "system:nodes"),
+				role("arn:aws:iam::123456789012:role/admin", "admin", "system:masters"),
+			}
+			Expect(authIssues(identities, []string{nodeRole})).To(BeEmpty())
+		})
+
+		It("reports misconfigured entries", func() {
+			identities := []iam.Identity{
+				role(nodeRole, "system:node:{{EC2PrivateDNSName}}", "system:bootstrappers"),
+				role("arn:aws:iam::123456789012:role/team/admin", "admin", "system:masters"),
+				role("arn:aws:iam::123456789012:role/viewer", ""),
+				role("arn:aws:iam::123456789012:role/viewer", "viewer"),
+				role("not-an-arn", "user"),
+			}
+			var messages []string
+			for _, issue := range authIssues(identities, []string{nodeRole}) {
+				messages = append(messages, issue.Name+": "+issue.Message)
+			}
+			Expect(messages).To(ConsistOf(
+				"arn:aws:iam::123456789012:role/team/admin: role ARN has a path, which the authenticator doesn't match; remove the path from the ARN",
+				"arn:aws:iam::123456789012:role/viewer: neither username nor groups are set",
+				"arn:aws:iam::123456789012:role/viewer: role is mapped more than once, only the first entry is used",
+				"not-an-arn: invalid ARN: arn: invalid prefix",
+				nodeRole+": instance role of a nodegroup isn't mapped to the system:nodes group, its nodes can't join the cluster",
+			))
+		})
+	})
+
+	Describe("addons", func() {
+		It("reports addons whose pods aren't ready", func() {
+			clientSet := fake.NewSimpleClientset(
+				&appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "aws-node", Namespace: metav1.NamespaceSystem},
+					Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+				},
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: metav1.NamespaceSystem},
+					Spec:       appsv1.DeploymentSpec{Replicas: aws.Int32(2)},
+					Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+				},
+			)
+			checker := NewChecker(nil, nil, clientSet, nil, api.NewClusterConfig())
+			report := &Report{}
+			checker.checkAddons(report)
+			Expect(report.Issues).To(Equal([]Issue{
+				{Component: ComponentAddon, Name: "kube-proxy", Message: "addon is not installed"},
+				{Component: ComponentAddon, Name: "coredns", Message: "1 of 2 pods are ready"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,139 @@
+package iam
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// ValidateExistingClusterRoles checks that the roles of the cluster set in iam.serviceRoleARN and
+// iam.fargatePodExecutionRoleARN exist, as eksctl doesn't create them, so that creating the cluster
+// stack doesn't fail after a long wait
+func ValidateExistingClusterRoles(provider api.ClusterProvider, spec *api.ClusterConfig) error {
+	if spec.IAM == nil {
+		return nil
+	}
+	if api.IsSetAndNonEmptyString(spec.IAM.ServiceRoleARN) {
+		if err := checkRoleExists(provider, *spec.IAM.ServiceRoleARN, "iam.serviceRoleARN"); err != nil {
+			return err
+		}
+	}
+	if api.IsSetAndNonEmptyString(spec.IAM.FargatePodExecutionRoleARN) {
+		if err := checkRoleExists(provider, *spec.IAM.FargatePodExecutionRoleARN, "iam.fargatePodExecutionRoleARN"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateExistingNodeGroupRoles checks that the instance roles and instance profiles set in the IAM
+// settings of the nodegroups exist, and that the instance profile of a nodegroup has its instance role
+// when both are set; an instance profile is created by eksctl for nodegroups that only set their role
+func ValidateExistingNodeGroupRoles(provider api.ClusterProvider, nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup) error {
+	for _, ng := range nodeGroups {
+		if ng.IAM == nil {
+			continue
+		}
+		path := fmt.Sprintf("nodegroup %q: iam", ng.Name)
+		if ng.IAM.InstanceRoleARN != "" {
+			if err := checkRoleExists(provider, ng.IAM.InstanceRoleARN, path+".instanceRoleARN"); err != nil {
+				return err
+			}
+		}
+		if ng.IAM.InstanceProfileARN == "" {
+			if ng.IAM.InstanceRoleARN != "" {
+				logger.Info("nodegroup %q will use existing role %q, an instance profile will be created for it", ng.Name, ng.IAM.InstanceRoleARN)
+			}
+			continue
+		}
+		roleARNs, err := instanceProfileRoles(provider, ng.IAM.InstanceProfileARN, path+".instanceProfileARN")
+		if err != nil {
+			return err
+		}
+		if ng.IAM.InstanceRoleARN != "" && !contains(roleARNs, ng.IAM.InstanceRoleARN) {
+			return fmt.Errorf("%s.instanceProfileARN: instance profile %q doesn't have role %q of %s.instanceRoleARN", path, ng.IAM.InstanceProfileARN, ng.IAM.InstanceRoleARN, path)
+		}
+	}
+
+	for _, ng := range managedNodeGroups {
+		if ng.IAM == nil || ng.IAM.InstanceRoleARN == "" {
+			continue
+		}
+		if err := checkRoleExists(provider, ng.IAM.InstanceRoleARN, fmt.Sprintf("managed nodegroup %q: iam.instanceRoleARN", ng.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceName returns the name of an IAM resource of the given type from its ARN, which includes
+// the path of the resource
+func resourceName(resourceARN, resourceType string) (string, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, resourceType+"/") {
+		return "", fmt.Errorf("not the ARN of an IAM %s", resourceType)
+	}
+	parts := strings.Split(parsed.Resource, "/")
+	return parts[len(parts)-1], nil
+}
+
+func checkRoleExists(provider api.ClusterProvider, roleARN, path string) error {
+	name, err := resourceName(roleARN, "role")
+	if err != nil {
+		return errors.Wrapf(err, "invalid ARN %q in %s", roleARN, path)
+	}
+	if _, err := provider.IAM().GetRole(&awsiam.GetRoleInput{RoleName: aws.String(name)}); err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("%s: role %q doesn't exist", path, roleARN)
+		}
+		return errors.Wrapf(err, "checking role %q of %s", roleARN, path)
+	}
+	return nil
+}
+
+func instanceProfileRoles(provider api.ClusterProvider, profileARN, path string) ([]string, error) {
+	name, err := resourceName(profileARN, "instance-profile")
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid ARN %q in %s", profileARN, path)
+	}
+	output, err := provider.IAM().GetInstanceProfile(&awsiam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("%s: instance profile %q doesn't exist", path, profileARN)
+		}
+		return nil, errors.Wrapf(err, "checking instance profile %q of %s", profileARN, path)
+	}
+	if len(output.InstanceProfile.Roles) == 0 {
+		return nil, fmt.Errorf("%s: instance profile %q has no roles", path, profileARN)
+	}
+	var roleARNs []string
+	for _, role := range output.InstanceProfile.Roles {
+		roleARNs = append(roleARNs, aws.StringValue(role.Arn))
+	}
+	return roleARNs, nil
+}
+
+func isNoSuchEntity(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == awsiam.ErrCodeNoSuchEntityException
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package iam_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("existing roles", func() {
+	const (
+		roleARN    = "arn:aws:iam::123456789012:role/eks/nodes"
+		profileARN = "arn:aws:iam::123456789012:instance-profile/eks/nodes"
+	)
+
+	var (
+		provider *mockprovider.MockProvider
+		cfg      *api.ClusterConfig
+		ng       *api.NodeGroup
+	)
+
+	BeforeEach(func() {
+		provider = mockprovider.NewMockProvider()
+		provider.MockIAM().On("GetRole", mock.MatchedBy(func(input *awsiam.GetRoleInput) bool {
+			return *input.RoleName == "nodes"
+		})).Return(&awsiam.GetRoleOutput{}, nil)
+		provider.MockIAM().On("GetRole", mock.Anything).Return(nil, awserr.New(awsiam.ErrCodeNoSuchEntityException, "not found", nil))
+		provider.MockIAM().On("GetInstanceProfile", mock.MatchedBy(func(input *awsiam.GetInstanceProfileInput) bool {
+			return *input.InstanceProfileName == "nodes"
+		})).Return(&awsiam.GetInstanceProfileOutput{
+			InstanceProfile: &awsiam.InstanceProfile{
+				Roles: []*awsiam.Role{{Arn: aws.String(roleARN)}},
+			},
+		}, nil)
+
+		cfg = api.NewClusterConfig()
+		ng = cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+	})
+
+	It("accepts existing roles and instance profiles", func() {
+		cfg.IAM.ServiceRoleARN = aws.String(roleARN)
+		Expect(ValidateExistingClusterRoles(provider, cfg)).To(Succeed())
+
+		ng.IAM.InstanceRoleARN = roleARN
+		ng.IAM.InstanceProfileARN = profileARN
+		Expect(ValidateExistingNodeGroupRoles(provider, cfg.NodeGroups, nil)).To(Succeed())
+	})
+
+	It("rejects roles that don't exist", func() {
+		cfg.IAM.ServiceRoleARN = aws.String("arn:aws:iam::123456789012:role/eks-service")
+		Expect(ValidateExistingClusterRoles(provider, cfg)).To(MatchError(`iam.serviceRoleARN: role "arn:aws:iam::123456789012:role/eks-service" doesn't exist`))
+
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng-1"
+		mng.IAM.InstanceRoleARN = "arn:aws:iam::123456789012:role/managed-nodes"
+		Expect(ValidateExistingNodeGroupRoles(provider, nil, []*api.ManagedNodeGroup{mng})).To(MatchError(ContainSubstring(`managed nodegroup "mng-1": iam.instanceRoleARN: role`)))
+	})
+
+	It("rejects instance profiles without the instance role", func() {
+		ng.IAM.InstanceRoleARN = "arn:aws:iam::123456789012:role/nodes"
+		ng.IAM.InstanceProfileARN = profileARN
+		Expect(ValidateExistingNodeGroupRoles(provider, cfg.NodeGroups, nil)).To(MatchError(ContainSubstring("doesn't have role")))
+	})
+})
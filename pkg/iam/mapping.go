@@ -1,8 +1,10 @@
 package iam
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 )
 
 var (
@@ -21,12 +23,16 @@ type Identity interface {
 	Type() string
 	Username() string
 	Groups() []string
+	UnknownFields() []string
 }
 
 // KubernetesIdentity represents a kubernetes identity to be used in iam mappings
 type KubernetesIdentity struct {
 	KubernetesUsername string   `json:"username,omitempty"`
 	KubernetesGroups   []string `json:"groups,omitempty"`
+	// unknownFields holds the fields of a mapping that eksctl doesn't know about,
+	// e.g. ones added by hand or by other tools, so that they're written back as they are
+	unknownFields map[string]json.RawMessage
 }
 
 // UserIdentity represents a mapping from an IAM user to a kubernetes identity
@@ -51,6 +57,16 @@ func (k KubernetesIdentity) Groups() []string {
 	return k.KubernetesGroups
 }
 
+// UnknownFields returns the sorted names of the fields of the mapping that eksctl doesn't know about
+func (k KubernetesIdentity) UnknownFields() []string {
+	names := make([]string, 0, len(k.unknownFields))
+	for name := range k.unknownFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ARN returns the ARN of the iam mapping
 func (u UserIdentity) ARN() string {
 	return u.UserARN
@@ -71,6 +87,68 @@ func (r RoleIdentity) Type() string {
 	return ResourceTypeRole
 }
 
+// UnmarshalJSON keeps the unknown fields of the mapping
+func (u *UserIdentity) UnmarshalJSON(data []byte) error {
+	type identity UserIdentity
+	if err := json.Unmarshal(data, (*identity)(u)); err != nil {
+		return err
+	}
+	return u.setUnknownFields(data, "userarn")
+}
+
+// MarshalJSON writes the unknown fields of the mapping back
+func (u UserIdentity) MarshalJSON() ([]byte, error) {
+	type identity UserIdentity
+	return marshalWithUnknownFields(identity(u), u.unknownFields)
+}
+
+// UnmarshalJSON keeps the unknown fields of the mapping
+func (r *RoleIdentity) UnmarshalJSON(data []byte) error {
+	type identity RoleIdentity
+	if err := json.Unmarshal(data, (*identity)(r)); err != nil {
+		return err
+	}
+	return r.setUnknownFields(data, "rolearn")
+}
+
+// MarshalJSON writes the unknown fields of the mapping back
+func (r RoleIdentity) MarshalJSON() ([]byte, error) {
+	type identity RoleIdentity
+	return marshalWithUnknownFields(identity(r), r.unknownFields)
+}
+
+func (k *KubernetesIdentity) setUnknownFields(data []byte, arnField string) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, known := range []string{arnField, "username", "groups"} {
+		delete(fields, known)
+	}
+	k.unknownFields = nil
+	if len(fields) > 0 {
+		k.unknownFields = fields
+	}
+	return nil
+}
+
+func marshalWithUnknownFields(identity interface{}, unknownFields map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(identity)
+	if err != nil || len(unknownFields) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range unknownFields {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
 // NewIdentity determines into which field the given arn goes and returns the new identity
 // alongside any error resulting for checking its validity.
 func NewIdentity(arn string, username string, groups []string) (Identity, error) {
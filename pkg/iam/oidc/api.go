@@ -116,6 +116,23 @@ func (m *OpenIDConnectManager) DeleteProvider() error {
 	return nil
 }
 
+// DeleteProviderByARN will delete the provider with the given ARN using IAM API, it's used to
+// delete providers found in the trust policies of IAM roles when the OIDC issuer of the cluster
+// can't be obtained, e.g. when the cluster failed to be created; a provider that doesn't exist
+// is not treated as an error
+func DeleteProviderByARN(iamapi iamiface.IAMAPI, providerARN string) error {
+	input := &awsiam.DeleteOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: &providerARN,
+	}
+	if _, err := iamapi.DeleteOpenIDConnectProvider(input); err != nil {
+		if awsError, ok := err.(awserr.Error); ok && awsError.Code() == awsiam.ErrCodeNoSuchEntityException {
+			return nil
+		}
+		return errors.Wrapf(err, "deleting OIDC provider %q", providerARN)
+	}
+	return nil
+}
+
 // getIssuerCAThumbprint obtains thumbprint of root CA by connecting to the
 // OIDC issuer and parsing certificates
 func (m *OpenIDConnectManager) getIssuerCAThumbprint() error {
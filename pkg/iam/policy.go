@@ -0,0 +1,293 @@
+package iam
+
+import (
+	"sort"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
+)
+
+// policyStatements collects the actions of the statements of a policy by their Sid, keeping
+// the statements in the order they were first added
+type policyStatements struct {
+	sids    []string
+	actions map[string]map[string]bool
+}
+
+func (p *policyStatements) add(sid string, actions ...string) {
+	if p.actions == nil {
+		p.actions = map[string]map[string]bool{}
+	}
+	if _, ok := p.actions[sid]; !ok {
+		p.sids = append(p.sids, sid)
+		p.actions[sid] = map[string]bool{}
+	}
+	for _, action := range actions {
+		p.actions[sid][action] = true
+	}
+}
+
+// MinimalPolicyDocument returns the IAM policy the caller of eksctl needs to create and delete the
+// cluster described by the config, with only the actions of the features the config uses; it's
+// meant to grant least privilege to CI systems running eksctl
+func MinimalPolicyDocument(spec *api.ClusterConfig) cft.MapOfInterfaces {
+	var p policyStatements
+
+	p.add("EksctlCloudFormation",
+		"cloudformation:CreateStack",
+		"cloudformation:DeleteStack",
+		"cloudformation:UpdateStack",
+		"cloudformation:DescribeStacks",
+		"cloudformation:DescribeStackEvents",
+		"cloudformation:DescribeStackResource",
+		"cloudformation:DescribeStackResources",
+		"cloudformation:ListStacks",
+		"cloudformation:GetTemplate",
+		"cloudformation:CreateChangeSet",
+		"cloudformation:DescribeChangeSet",
+		"cloudformation:ExecuteChangeSet",
+		"cloudtrail:LookupEvents",
+	)
+	p.add("EksctlEKS",
+		"eks:CreateCluster",
+		"eks:DeleteCluster",
+		"eks:DescribeCluster",
+		"eks:ListClusters",
+		"eks:UpdateClusterConfig",
+		"eks:DescribeUpdate",
+		"eks:TagResource",
+		"eks:ListNodegroups",
+		"eks:ListFargateProfiles",
+		"sts:GetCallerIdentity",
+	)
+	p.add("EksctlEC2",
+		"ec2:DescribeAvailabilityZones",
+		"ec2:DescribeVpcs",
+		"ec2:DescribeSubnets",
+		"ec2:DescribeSecurityGroups",
+		"ec2:DescribeAccountAttributes",
+		"ec2:CreateSecurityGroup",
+		"ec2:DeleteSecurityGroup",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:AuthorizeSecurityGroupEgress",
+		"ec2:RevokeSecurityGroupIngress",
+		"ec2:RevokeSecurityGroupEgress",
+		"ec2:CreateTags",
+		"ec2:DeleteTags",
+	)
+	// the service-linked roles are created by the services the first time they're used in an account
+	serviceLinkedRoles := []string{"eks.amazonaws.com"}
+
+	if spec.VPC == nil || spec.VPC.ID == "" {
+		p.add("EksctlVPC",
+			"ec2:CreateVpc",
+			"ec2:DeleteVpc",
+			"ec2:ModifyVpcAttribute",
+			"ec2:CreateSubnet",
+			"ec2:DeleteSubnet",
+			"ec2:ModifySubnetAttribute",
+			"ec2:CreateInternetGateway",
+			"ec2:DeleteInternetGateway",
+			"ec2:AttachInternetGateway",
+			"ec2:DetachInternetGateway",
+			"ec2:DescribeInternetGateways",
+			"ec2:CreateRouteTable",
+			"ec2:DeleteRouteTable",
+			"ec2:CreateRoute",
+			"ec2:DeleteRoute",
+			"ec2:AssociateRouteTable",
+			"ec2:DisassociateRouteTable",
+			"ec2:DescribeRouteTables",
+			"ec2:AllocateAddress",
+			"ec2:ReleaseAddress",
+			"ec2:DescribeAddresses",
+			"ec2:CreateNatGateway",
+			"ec2:DeleteNatGateway",
+			"ec2:DescribeNatGateways",
+		)
+	}
+
+	// iam:PassRole is needed for existing roles too, as they're passed to EKS and EC2
+	p.add("EksctlIAMRoles", "iam:GetRole", "iam:PassRole")
+	if createsRoles(spec) {
+		p.add("EksctlIAMRoles",
+			"iam:CreateRole",
+			"iam:DeleteRole",
+			"iam:TagRole",
+			"iam:AttachRolePolicy",
+			"iam:DetachRolePolicy",
+			"iam:PutRolePolicy",
+			"iam:DeleteRolePolicy",
+			"iam:GetRolePolicy",
+		)
+		if hasPermissionsBoundary(spec) {
+			p.add("EksctlIAMRoles", "iam:PutRolePermissionsBoundary")
+		}
+	}
+
+	if api.IsEnabled(spec.IAM.WithOIDC) || len(spec.IAM.ServiceAccounts) > 0 {
+		p.add("EksctlOIDC",
+			"iam:CreateOpenIDConnectProvider",
+			"iam:DeleteOpenIDConnectProvider",
+			"iam:GetOpenIDConnectProvider",
+			"iam:ListOpenIDConnectProviders",
+		)
+	}
+
+	if len(spec.NodeGroups) > 0 || len(spec.ManagedNodeGroups) > 0 {
+		p.add("EksctlAMIs", "ssm:GetParameter", "ec2:DescribeImages")
+	}
+
+	for _, ng := range spec.NodeGroups {
+		p.add("EksctlNodeGroups",
+			"ec2:CreateLaunchTemplate",
+			"ec2:DeleteLaunchTemplate",
+			"ec2:DescribeLaunchTemplateVersions",
+			"ec2:RunInstances",
+			"autoscaling:CreateAutoScalingGroup",
+			"autoscaling:DeleteAutoScalingGroup",
+			"autoscaling:UpdateAutoScalingGroup",
+			"autoscaling:DescribeAutoScalingGroups",
+			"autoscaling:DescribeScalingActivities",
+			"autoscaling:CreateOrUpdateTags",
+		)
+		if ng.IAM == nil || ng.IAM.InstanceProfileARN == "" {
+			p.add("EksctlIAMRoles",
+				"iam:CreateInstanceProfile",
+				"iam:DeleteInstanceProfile",
+				"iam:GetInstanceProfile",
+				"iam:AddRoleToInstanceProfile",
+				"iam:RemoveRoleFromInstanceProfile",
+			)
+		} else {
+			p.add("EksctlIAMRoles", "iam:GetInstanceProfile")
+		}
+		if importsSSHKey(ng.SSH) {
+			p.add("EksctlSSHKeys", "ec2:DescribeKeyPairs", "ec2:ImportKeyPair", "ec2:DeleteKeyPair")
+		}
+		serviceLinkedRoles = append(serviceLinkedRoles, "autoscaling.amazonaws.com")
+		if ng.InstancesDistribution != nil {
+			serviceLinkedRoles = append(serviceLinkedRoles, "spot.amazonaws.com")
+		}
+	}
+
+	for _, ng := range spec.ManagedNodeGroups {
+		p.add("EksctlManagedNodeGroups",
+			"eks:CreateNodegroup",
+			"eks:DeleteNodegroup",
+			"eks:DescribeNodegroup",
+		)
+		if ng.RequiresLaunchTemplate() {
+			p.add("EksctlManagedNodeGroups",
+				"ec2:CreateLaunchTemplate",
+				"ec2:DeleteLaunchTemplate",
+				"ec2:DescribeLaunchTemplateVersions",
+			)
+		}
+		if importsSSHKey(ng.SSH) {
+			p.add("EksctlSSHKeys", "ec2:DescribeKeyPairs", "ec2:ImportKeyPair", "ec2:DeleteKeyPair")
+		}
+		serviceLinkedRoles = append(serviceLinkedRoles, "eks-nodegroup.amazonaws.com")
+	}
+
+	if len(spec.FargateProfiles) > 0 {
+		p.add("EksctlFargate",
+			"eks:CreateFargateProfile",
+			"eks:DeleteFargateProfile",
+			"eks:DescribeFargateProfile",
+		)
+		serviceLinkedRoles = append(serviceLinkedRoles, "eks-fargate.amazonaws.com")
+	}
+
+	if spec.SecretsEncryption != nil {
+		p.add("EksctlKMS", "kms:DescribeKey", "kms:CreateGrant")
+	}
+
+	statements := make([]cft.MapOfInterfaces, 0, len(p.sids)+1)
+	for _, sid := range p.sids {
+		statements = append(statements, cft.MapOfInterfaces{
+			"Sid":      sid,
+			"Effect":   "Allow",
+			"Action":   sortedActions(p.actions[sid]),
+			"Resource": "*",
+		})
+	}
+	statements = append(statements, cft.MapOfInterfaces{
+		"Sid":      "EksctlServiceLinkedRoles",
+		"Effect":   "Allow",
+		"Action":   []string{"iam:CreateServiceLinkedRole"},
+		"Resource": "*",
+		"Condition": cft.MapOfInterfaces{
+			"StringEquals": cft.MapOfInterfaces{
+				"iam:AWSServiceName": uniqueSorted(serviceLinkedRoles),
+			},
+		},
+	})
+	return cft.MakePolicyDocument(statements...)
+}
+
+// createsRoles reports whether eksctl creates any role for the cluster, i.e. whether a role of
+// the cluster or of its nodegroups isn't set, or the cluster has IAM service accounts
+func createsRoles(spec *api.ClusterConfig) bool {
+	if !api.IsSetAndNonEmptyString(spec.IAM.ServiceRoleARN) || len(spec.IAM.ServiceAccounts) > 0 {
+		return true
+	}
+	if len(spec.FargateProfiles) > 0 && !api.IsSetAndNonEmptyString(spec.IAM.FargatePodExecutionRoleARN) {
+		return true
+	}
+	for _, ng := range spec.NodeGroups {
+		if ng.IAM == nil || ng.IAM.InstanceRoleARN == "" {
+			return true
+		}
+	}
+	for _, ng := range spec.ManagedNodeGroups {
+		if ng.IAM == nil || ng.IAM.InstanceRoleARN == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPermissionsBoundary(spec *api.ClusterConfig) bool {
+	if spec.IAM.PermissionsBoundary != nil || spec.IAM.ServiceRolePermissionsBoundary != nil || spec.IAM.FargatePodExecutionRolePermissionsBoundary != nil {
+		return true
+	}
+	for _, sa := range spec.IAM.ServiceAccounts {
+		if sa.PermissionsBoundary != "" {
+			return true
+		}
+	}
+	for _, ng := range spec.NodeGroups {
+		if ng.IAM != nil && ng.IAM.InstanceRolePermissionsBoundary != "" {
+			return true
+		}
+	}
+	for _, ng := range spec.ManagedNodeGroups {
+		if ng.IAM != nil && ng.IAM.InstanceRolePermissionsBoundary != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// importsSSHKey reports whether eksctl imports the public key of a nodegroup into EC2
+func importsSSHKey(ssh *api.NodeGroupSSH) bool {
+	return ssh != nil && api.IsEnabled(ssh.Allow) && !api.IsSetAndNonEmptyString(ssh.PublicKeyName)
+}
+
+func sortedActions(actions map[string]bool) []string {
+	sorted := make([]string, 0, len(actions))
+	for action := range actions {
+		sorted = append(sorted, action)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func uniqueSorted(values []string) []string {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return sortedActions(set)
+}
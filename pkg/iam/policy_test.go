@@ -0,0 +1,64 @@
+package iam_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
+	. "github.com/weaveworks/eksctl/pkg/iam"
+)
+
+var _ = Describe("MinimalPolicyDocument", func() {
+	var cfg *api.ClusterConfig
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+	})
+
+	statementActions := func(sid string) []string {
+		for _, statement := range MinimalPolicyDocument(cfg)["Statement"].([]cft.MapOfInterfaces) {
+			if statement["Sid"] == sid {
+				return statement["Action"].([]string)
+			}
+		}
+		return nil
+	}
+
+	serviceLinkedRoles := func() interface{} {
+		statements := MinimalPolicyDocument(cfg)["Statement"].([]cft.MapOfInterfaces)
+		last := statements[len(statements)-1]
+		Expect(last["Sid"]).To(Equal("EksctlServiceLinkedRoles"))
+		return last["Condition"].(cft.MapOfInterfaces)["StringEquals"].(cft.MapOfInterfaces)["iam:AWSServiceName"]
+	}
+
+	It("grants the actions of the features the config uses", func() {
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		cfg.IAM.WithOIDC = api.Enabled()
+
+		Expect(statementActions("EksctlVPC")).To(ContainElement("ec2:CreateVpc"))
+		Expect(statementActions("EksctlIAMRoles")).To(ContainElement("iam:CreateInstanceProfile"))
+		Expect(statementActions("EksctlOIDC")).To(ContainElement("iam:CreateOpenIDConnectProvider"))
+		Expect(statementActions("EksctlNodeGroups")).To(ContainElement("autoscaling:CreateAutoScalingGroup"))
+		Expect(statementActions("EksctlManagedNodeGroups")).To(BeNil())
+		Expect(statementActions("EksctlFargate")).To(BeNil())
+		Expect(serviceLinkedRoles()).To(Equal([]string{"autoscaling.amazonaws.com", "eks.amazonaws.com"}))
+	})
+
+	It("leaves out the actions of resources that exist already", func() {
+		cfg.VPC.ID = "vpc-0123456789abcdef0"
+		cfg.IAM.ServiceRoleARN = aws.String("arn:aws:iam::123456789012:role/eks-service")
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng-1"
+		mng.IAM.InstanceRoleARN = "arn:aws:iam::123456789012:role/eks-nodes"
+		cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{mng}
+
+		Expect(statementActions("EksctlVPC")).To(BeNil())
+		Expect(statementActions("EksctlIAMRoles")).To(Equal([]string{"iam:GetRole", "iam:PassRole"}))
+		Expect(statementActions("EksctlManagedNodeGroups")).To(ContainElement("eks:CreateNodegroup"))
+		Expect(serviceLinkedRoles()).To(Equal([]string{"eks-nodegroup.amazonaws.com", "eks.amazonaws.com"}))
+	})
+})
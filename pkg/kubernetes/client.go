@@ -6,7 +6,6 @@ import (
 
 	"github.com/blang/semver"
 	jsonpatch "github.com/evanphx/json-patch"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
@@ -24,6 +23,8 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Interface is an alias to avoid having to import k8s.io/client-go/kubernetes
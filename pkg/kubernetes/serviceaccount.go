@@ -1,11 +1,12 @@
 package kubernetes
 
 import (
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // NewServiceAccount creates a corev1.ServiceAccount object using the provided meta.
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// ZonalStorageClassName returns the name of the StorageClass of an availability zone
+func ZonalStorageClassName(zone string) string {
+	return "gp2-" + zone
+}
+
+// NewZonalStorageClass creates a storagev1.StorageClass object for gp2 EBS volumes that are
+// provisioned in zone; binding is delayed until a pod is scheduled, so that pods using
+// the volumes are only scheduled on nodes of that zone
+func NewZonalStorageClass(zone string) *storagev1.StorageClass {
+	bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+	return &storagev1.StorageClass{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StorageClass",
+			APIVersion: storagev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ZonalStorageClassName(zone),
+		},
+		Provisioner: "kubernetes.io/aws-ebs",
+		Parameters: map[string]string{
+			"type":   "gp2",
+			"fsType": "ext4",
+		},
+		VolumeBindingMode: &bindingMode,
+		AllowedTopologies: []corev1.TopologySelectorTerm{
+			{
+				MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+					{
+						Key:    api.FailureDomainZoneLabel,
+						Values: []string{zone},
+					},
+				},
+			},
+		},
+	}
+}
+
+// MaybeCreateZonalStorageClass will only create the StorageClass of zone if it doesn't
+// already exist, as the parameters of a StorageClass can't be updated
+func MaybeCreateZonalStorageClass(clientSet Interface, zone string) error {
+	storageClass := NewZonalStorageClass(zone)
+	_, err := clientSet.StorageV1().StorageClasses().Create(storageClass)
+	if apierrors.IsAlreadyExists(err) {
+		logger.Debug("ignoring failed creation of existing storage class %q", storageClass.Name)
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "creating storage class %q", storageClass.Name)
+	}
+	logger.Info("created storage class %q", storageClass.Name)
+	return nil
+}
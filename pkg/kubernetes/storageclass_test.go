@@ -0,0 +1,43 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+var _ = Describe("Kubernetes storage class helpers", func() {
+	var clientSet *fake.Clientset
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset()
+	})
+
+	It("creates a storage class restricted to the zone", func() {
+		Expect(MaybeCreateZonalStorageClass(clientSet, "us-west-2a")).To(Succeed())
+
+		storageClass, err := clientSet.StorageV1().StorageClasses().Get("gp2-us-west-2a", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storageClass.Provisioner).To(Equal("kubernetes.io/aws-ebs"))
+		Expect(*storageClass.VolumeBindingMode).To(Equal(storagev1.VolumeBindingWaitForFirstConsumer))
+		Expect(storageClass.AllowedTopologies).To(HaveLen(1))
+		Expect(storageClass.AllowedTopologies[0].MatchLabelExpressions).To(HaveLen(1))
+		Expect(storageClass.AllowedTopologies[0].MatchLabelExpressions[0].Key).To(Equal(api.FailureDomainZoneLabel))
+		Expect(storageClass.AllowedTopologies[0].MatchLabelExpressions[0].Values).To(Equal([]string{"us-west-2a"}))
+	})
+
+	It("leaves an existing storage class as is", func() {
+		Expect(MaybeCreateZonalStorageClass(clientSet, "us-west-2a")).To(Succeed())
+		Expect(MaybeCreateZonalStorageClass(clientSet, "us-west-2a")).To(Succeed())
+
+		list, err := clientSet.StorageV1().StorageClasses().List(metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Items).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,36 @@
+package logger
+
+import "fmt"
+
+// EventType identifies a progress event, the values are stable so that
+// tools parsing the output can rely on them
+type EventType string
+
+const (
+	// EventStackCreated is emitted when a CloudFormation stack has been created
+	EventStackCreated = EventType("stack-created")
+	// EventClusterReady is emitted when a cluster has been created and is ready to use
+	EventClusterReady = EventType("cluster-ready")
+	// EventNodeGroupReady is emitted when the nodes of a nodegroup have joined the cluster
+	EventNodeGroupReady = EventType("nodegroup-ready")
+	// EventAddonInstalled is emitted when an addon has been installed or updated
+	EventAddonInstalled = EventType("addon-installed")
+)
+
+// Event describes the progress of an operation
+type Event struct {
+	Type    EventType
+	Message string
+	// Fields identify the resources the event is about, e.g. cluster and nodegroup names
+	Fields map[string]string
+}
+
+// Emit logs a progress event; in text format the message is logged like a call to Success,
+// in JSON format the event type and fields are included as well
+func Emit(eventType EventType, fields map[string]string, format string, a ...interface{}) {
+	backend.Event(Event{
+		Type:    eventType,
+		Message: fmt.Sprintf(format, a...),
+		Fields:  fields,
+	})
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONBackend prints each message and event as a JSON object on its own line
+type JSONBackend struct {
+	mu  sync.Mutex
+	out io.Writer
+	now func() time.Time
+}
+
+type jsonRecord struct {
+	Time    string            `json:"time"`
+	Level   Severity          `json:"level"`
+	Event   EventType         `json:"event,omitempty"`
+	Message string            `json:"msg"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// NewJSONBackend creates a JSONBackend writing to out
+func NewJSONBackend(out io.Writer) *JSONBackend {
+	return &JSONBackend{out: out, now: time.Now}
+}
+
+// Log prints a message
+func (j *JSONBackend) Log(severity Severity, msg string) {
+	j.write(jsonRecord{Level: severity, Message: msg})
+}
+
+// Event prints a progress event, events are printed in quiet mode as well
+func (j *JSONBackend) Event(e Event) {
+	if Level < severityLevels[SeverityCritical] {
+		return
+	}
+	j.write(jsonRecord{Level: SeverityInfo, Event: e.Type, Message: e.Message, Fields: e.Fields})
+}
+
+func (j *JSONBackend) write(r jsonRecord) {
+	r.Time = j.now().UTC().Format(time.RFC3339)
+	data, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, _ = j.out.Write(append(data, '\n'))
+}
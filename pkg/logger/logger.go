@@ -0,0 +1,174 @@
+// Package logger is the logging layer of eksctl; it has the same API as
+// github.com/kris-nova/logger, which it uses to print human-readable logs by
+// default, and it can be switched to print JSON lines for tools parsing the
+// output of eksctl, e.g. in CI pipelines
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	nova "github.com/kris-nova/logger"
+)
+
+// Logger is the signature of the logging functions of this package
+type Logger = nova.Logger
+
+// Format is the format of the logs
+type Format string
+
+const (
+	// TextFormat is the human-readable format of github.com/kris-nova/logger
+	TextFormat = Format("text")
+	// JSONFormat prints each message as a JSON object on its own line
+	JSONFormat = Format("json")
+)
+
+// Severity is the severity of a message
+type Severity string
+
+const (
+	// SeverityCritical is the severity of errors
+	SeverityCritical = Severity("critical")
+	// SeverityWarning is the severity of warnings
+	SeverityWarning = Severity("warning")
+	// SeverityInfo is the severity of informational messages
+	SeverityInfo = Severity("info")
+	// SeveritySuccess is the severity of messages about completed operations
+	SeveritySuccess = Severity("success")
+	// SeverityDebug is the severity of debugging messages
+	SeverityDebug = Severity("debug")
+)
+
+// a message is logged when Level is greater than or equal to the level of its severity
+var severityLevels = map[Severity]int{
+	SeverityCritical: 1,
+	SeverityWarning:  2,
+	SeverityInfo:     3,
+	SeveritySuccess:  3,
+	SeverityDebug:    4,
+}
+
+// Level is the log level, use 0 to silence, 4 for debugging
+var Level = 3
+
+var (
+	backend Backend = &textBackend{}
+	quiet   bool
+)
+
+// Backend prints the messages that pass the log level
+type Backend interface {
+	// Log prints a message of the given severity
+	Log(severity Severity, msg string)
+	// Event prints a progress event
+	Event(event Event)
+}
+
+// Options holds the logging configuration set by the global flags
+type Options struct {
+	// Format of the logs, defaults to TextFormat
+	Format Format
+	// Quiet disables all messages but warnings, errors and progress events
+	Quiet bool
+	// Color enables colorized text logs
+	Color bool
+	// Fabulous enables rainbow colorized text logs
+	Fabulous bool
+	// Writer is where JSON logs are written to, defaults to os.Stderr
+	Writer io.Writer
+}
+
+// Formats returns the supported log formats
+func Formats() []string {
+	return []string{string(TextFormat), string(JSONFormat)}
+}
+
+// Configure sets up the backend for the given options
+func Configure(o Options) error {
+	quiet = o.Quiet
+	switch o.Format {
+	case "", TextFormat:
+		nova.Color = o.Color
+		nova.Fabulous = o.Fabulous
+		// add timestamps for debugging
+		nova.Timestamps = Level >= severityLevels[SeverityDebug]
+		SetBackend(&textBackend{})
+	case JSONFormat:
+		w := o.Writer
+		if w == nil {
+			w = os.Stderr
+		}
+		SetBackend(NewJSONBackend(w))
+	default:
+		return fmt.Errorf("unknown log format %q, supported formats are: %s", o.Format, strings.Join(Formats(), ", "))
+	}
+	return nil
+}
+
+// SetBackend replaces the backend that prints the messages
+func SetBackend(b Backend) {
+	backend = b
+}
+
+func log(severity Severity, format string, a ...interface{}) {
+	level := severityLevels[severity]
+	if Level < level || (quiet && level > severityLevels[SeverityWarning]) {
+		return
+	}
+	backend.Log(severity, fmt.Sprintf(format, a...))
+}
+
+// Critical logs an error
+func Critical(format string, a ...interface{}) {
+	log(SeverityCritical, format, a...)
+}
+
+// Warning logs a warning
+func Warning(format string, a ...interface{}) {
+	log(SeverityWarning, format, a...)
+}
+
+// Info logs an informational message
+func Info(format string, a ...interface{}) {
+	log(SeverityInfo, format, a...)
+}
+
+// Success logs the successful completion of an operation
+func Success(format string, a ...interface{}) {
+	log(SeveritySuccess, format, a...)
+}
+
+// Debug logs a debugging message
+func Debug(format string, a ...interface{}) {
+	log(SeverityDebug, format, a...)
+}
+
+type textBackend struct{}
+
+func (*textBackend) Log(severity Severity, msg string) {
+	nova.Level = Level
+	switch severity {
+	case SeverityCritical:
+		nova.Critical("%s", msg)
+	case SeverityWarning:
+		nova.Warning("%s", msg)
+	case SeveritySuccess:
+		nova.Success("%s", msg)
+	case SeverityDebug:
+		nova.Debug("%s", msg)
+	default:
+		nova.Info("%s", msg)
+	}
+}
+
+// progress events are logged as successful operations, they are omitted in quiet mode
+func (*textBackend) Event(e Event) {
+	if quiet || Level < severityLevels[SeveritySuccess] {
+		return
+	}
+	nova.Level = Level
+	nova.Success("%s", e.Message)
+}
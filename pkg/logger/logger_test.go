@@ -0,0 +1,97 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/logger"
+)
+
+var _ = Describe("Logger", func() {
+	var (
+		out   bytes.Buffer
+		level int
+	)
+
+	records := func() []map[string]interface{} {
+		var result []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			record := map[string]interface{}{}
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			result = append(result, record)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		out.Reset()
+		level = Level
+		Level = 3
+	})
+
+	AfterEach(func() {
+		Level = level
+		Expect(Configure(Options{Format: TextFormat})).To(Succeed())
+	})
+
+	It("rejects unknown formats", func() {
+		Expect(Configure(Options{Format: "xml"})).To(MatchError(`unknown log format "xml", supported formats are: text, json`))
+	})
+
+	Context("with JSON format", func() {
+		BeforeEach(func() {
+			Expect(Configure(Options{Format: JSONFormat, Writer: &out})).To(Succeed())
+		})
+
+		It("prints a JSON object per message", func() {
+			Info("creating cluster %q", "test")
+			Warning("something is off")
+			Debug("hidden at level 3")
+
+			logs := records()
+			Expect(logs).To(HaveLen(2))
+			Expect(logs[0]).To(HaveKeyWithValue("level", "info"))
+			Expect(logs[0]).To(HaveKeyWithValue("msg", `creating cluster "test"`))
+			Expect(logs[0]).To(HaveKey("time"))
+			Expect(logs[0]).NotTo(HaveKey("event"))
+			Expect(logs[1]).To(HaveKeyWithValue("level", "warning"))
+		})
+
+		It("prints progress events with their type and fields", func() {
+			Emit(EventNodeGroupReady, map[string]string{"nodegroup": "ng-1"}, "nodegroup %q is ready", "ng-1")
+
+			logs := records()
+			Expect(logs).To(HaveLen(1))
+			Expect(logs[0]).To(HaveKeyWithValue("event", "nodegroup-ready"))
+			Expect(logs[0]).To(HaveKeyWithValue("msg", `nodegroup "ng-1" is ready`))
+			Expect(logs[0]).To(HaveKeyWithValue("fields", map[string]interface{}{"nodegroup": "ng-1"}))
+		})
+	})
+
+	Context("with JSON format in quiet mode", func() {
+		BeforeEach(func() {
+			Expect(Configure(Options{Format: JSONFormat, Quiet: true, Writer: &out})).To(Succeed())
+		})
+
+		It("prints only warnings, errors and progress events", func() {
+			Info("creating cluster")
+			Success("created cluster")
+			Warning("something is off")
+			Critical("something failed")
+			Emit(EventStackCreated, map[string]string{"stack": "eksctl-test-cluster"}, "created stack")
+
+			logs := records()
+			Expect(logs).To(HaveLen(3))
+			Expect(logs[0]).To(HaveKeyWithValue("level", "warning"))
+			Expect(logs[1]).To(HaveKeyWithValue("level", "critical"))
+			Expect(logs[2]).To(HaveKeyWithValue("event", "stack-created"))
+		})
+	})
+})
@@ -6,17 +6,18 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/blang/semver"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 	"github.com/weaveworks/eksctl/pkg/ami"
 	"github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // A Service provides methods for managing managed nodegroups
@@ -100,7 +101,76 @@ func (m *Service) UpdateLabels(nodeGroupName string, labelsToAdd map[string]stri
 		return err
 	}
 
-	return m.stackCollection.UpdateNodeGroupStack(nodeGroupName, template)
+	if err := m.stackCollection.UpdateNodeGroupStack(nodeGroupName, template); err != nil {
+		return err
+	}
+
+	return m.UpdateNodeTemplateTags(nodeGroupName, labelsToAdd, nil, labelsToRemove)
+}
+
+// TagNodeTemplates tags the ASGs of the managed nodegroups with their labels and taints,
+// see UpdateNodeTemplateTags
+func (m *Service) TagNodeTemplates(nodeGroups []*v1alpha5.ManagedNodeGroup) error {
+	for _, ng := range nodeGroups {
+		if len(ng.Labels) == 0 && len(ng.Taints) == 0 {
+			continue
+		}
+		if err := m.UpdateNodeTemplateTags(ng.Name, ng.Labels, ng.Taints, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateNodeTemplateTags tags the ASGs of a managed nodegroup with the given labels and taints,
+// and deletes the tags of the removed labels, so that cluster-autoscaler can scale the nodegroup
+// up from zero; EKS doesn't propagate the labels and taints of a nodegroup to its ASGs
+func (m *Service) UpdateNodeTemplateTags(nodeGroupName string, labels, taints map[string]string, removedLabels []string) error {
+	output, err := m.provider.EKS().DescribeNodegroup(&eks.DescribeNodegroupInput{
+		ClusterName:   &m.clusterName,
+		NodegroupName: &nodeGroupName,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "describing nodegroup %q", nodeGroupName)
+	}
+	if output.Nodegroup.Resources == nil {
+		return nil
+	}
+
+	nodeTemplateTags := v1alpha5.NodeTemplateTags(labels, taints)
+	for _, asg := range output.Nodegroup.Resources.AutoScalingGroups {
+		var tags []*autoscaling.Tag
+		for key, value := range nodeTemplateTags {
+			tags = append(tags, &autoscaling.Tag{
+				Key:               aws.String(key),
+				Value:             aws.String(value),
+				ResourceId:        asg.Name,
+				ResourceType:      aws.String("auto-scaling-group"),
+				PropagateAtLaunch: aws.Bool(false),
+			})
+		}
+		if len(tags) > 0 {
+			if _, err := m.provider.ASG().CreateOrUpdateTags(&autoscaling.CreateOrUpdateTagsInput{Tags: tags}); err != nil {
+				return errors.Wrapf(err, "tagging auto scaling group %q of nodegroup %q", aws.StringValue(asg.Name), nodeGroupName)
+			}
+		}
+
+		var removedTags []*autoscaling.Tag
+		for _, label := range removedLabels {
+			removedTags = append(removedTags, &autoscaling.Tag{
+				Key:          aws.String(v1alpha5.NodeTemplateLabelTagPrefix + label),
+				ResourceId:   asg.Name,
+				ResourceType: aws.String("auto-scaling-group"),
+			})
+		}
+		if len(removedTags) > 0 {
+			if _, err := m.provider.ASG().DeleteTags(&autoscaling.DeleteTagsInput{Tags: removedTags}); err != nil {
+				return errors.Wrapf(err, "deleting tags of auto scaling group %q of nodegroup %q", aws.StringValue(asg.Name), nodeGroupName)
+			}
+		}
+		logger.Debug("updated the cluster-autoscaler tags of auto scaling group %q of nodegroup %q", aws.StringValue(asg.Name), nodeGroupName)
+	}
+	return nil
 }
 
 // GetLabels fetches the labels for a nodegroup
@@ -113,8 +183,9 @@ func (m *Service) GetLabels(nodeGroupName string) (map[string]string, error) {
 }
 
 // UpgradeNodeGroup upgrades nodegroup to the latest AMI release for the specified Kubernetes version, or
-// the current Kubernetes version if the version isn't specified
-func (m *Service) UpgradeNodeGroup(nodeGroupName, kubernetesVersion string) error {
+// the current Kubernetes version if the version isn't specified; a release version, e.g. 1.15.10-20200228,
+// selects a specific AMI release instead
+func (m *Service) UpgradeNodeGroup(nodeGroupName, kubernetesVersion, releaseVersion string) error {
 	// Use the latest AMI release version
 	output, err := m.provider.EKS().DescribeNodegroup(&eks.DescribeNodegroupInput{
 		ClusterName:   &m.clusterName,
@@ -131,6 +202,13 @@ func (m *Service) UpgradeNodeGroup(nodeGroupName, kubernetesVersion string) erro
 
 	nodeGroup := output.Nodegroup
 
+	if releaseVersion != "" {
+		if _, _, err := ami.ParseReleaseVersion(releaseVersion); err != nil {
+			return err
+		}
+		return m.upgradeToReleaseVersion(nodeGroupName, *nodeGroup.ReleaseVersion, releaseVersion)
+	}
+
 	if kubernetesVersion == "" {
 		// Use the current Kubernetes version
 		kubernetesVersion = *nodeGroup.Version
@@ -138,6 +216,9 @@ func (m *Service) UpgradeNodeGroup(nodeGroupName, kubernetesVersion string) erro
 		return errors.Wrap(err, "invalid Kubernetes version")
 	}
 
+	if len(nodeGroup.InstanceTypes) == 0 {
+		return fmt.Errorf("the instance type of nodegroup %q is set in its launch template, a release version must be specified to upgrade it", nodeGroupName)
+	}
 	instanceType := nodeGroup.InstanceTypes[0]
 	ssmParameterName, err := ami.MakeSSMParameterName(kubernetesVersion, *instanceType, v1alpha5.NodeImageFamilyAmazonLinux2)
 	if err != nil {
@@ -176,8 +257,11 @@ func (m *Service) UpgradeNodeGroup(nodeGroupName, kubernetesVersion string) erro
 	if err != nil {
 		return errors.Wrap(err, "error extracting Kubernetes version")
 	}
-	releaseVersion := makeReleaseVersion(kubernetesVersion, amiReleaseVersion)
-	if releaseVersion == *nodeGroup.ReleaseVersion {
+	return m.upgradeToReleaseVersion(nodeGroupName, *nodeGroup.ReleaseVersion, makeReleaseVersion(kubernetesVersion, amiReleaseVersion))
+}
+
+func (m *Service) upgradeToReleaseVersion(nodeGroupName, currentReleaseVersion, releaseVersion string) error {
+	if releaseVersion == currentReleaseVersion {
 		logger.Info("nodegroup %q is already up-to-date", nodeGroupName)
 		return nil
 	}
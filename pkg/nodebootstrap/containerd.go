@@ -0,0 +1,135 @@
+package nodebootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	containerdConfigDir    = "/etc/containerd/"
+	containerdConfigFile   = "config.toml"
+	containerdDropInDir    = "/etc/containerd/config.d/"
+	containerdDropInFile   = "10-eksctl.toml"
+	containerdHostsDir     = "/etc/containerd/certs.d/"
+	containerdHostsFile    = "hosts.toml"
+	containerdEndpoint     = "unix:///run/containerd/containerd.sock"
+	containerdSandboxImage = "public.ecr.aws/eks-distro/kubernetes/pause:3.2"
+
+	dockerRuntimeFlag = "container-runtime=docker"
+)
+
+// containerdBaseConfig enables the CRI plugin, which the containerd package of the
+// images disables, as they use containerd through docker
+const containerdBaseConfig = `version = 2
+root = "/var/lib/containerd"
+state = "/run/containerd"
+imports = ["` + containerdDropInDir + `*.toml"]
+
+[grpc]
+address = "/run/containerd/containerd.sock"
+`
+
+func usesContainerd(ng *api.NodeGroup) bool {
+	return ng.ContainerRuntime != nil && *ng.ContainerRuntime == api.ContainerRuntimeContainerd
+}
+
+// makeContainerdCRIConfig returns the configuration of the CRI plugin, with the
+// overrides of containerdConfig when it's set
+func makeContainerdCRIConfig(containerdConfig *api.NodeGroupContainerdConfig) string {
+	const plugin = `plugins."io.containerd.grpc.v1.cri"`
+
+	sandboxImage := containerdSandboxImage
+	if containerdConfig != nil && containerdConfig.SandboxImage != "" {
+		sandboxImage = containerdConfig.SandboxImage
+	}
+
+	var config strings.Builder
+	config.WriteString(fmt.Sprintf("[%s]\nsandbox_image = %q\n", plugin, sandboxImage))
+	config.WriteString(fmt.Sprintf("\n[%s.containerd]\ndefault_runtime_name = \"runc\"\n", plugin))
+	config.WriteString(fmt.Sprintf("\n[%s.containerd.runtimes.runc]\nruntime_type = \"io.containerd.runc.v2\"\n", plugin))
+	config.WriteString(fmt.Sprintf("\n[%s.cni]\nbin_dir = \"/opt/cni/bin\"\nconf_dir = \"/etc/cni/net.d\"\n", plugin))
+	config.WriteString(fmt.Sprintf("\n[%s.registry]\nconfig_path = %q\n", plugin, strings.TrimSuffix(containerdHostsDir, "/")))
+	return config.String()
+}
+
+// registryMirrors returns the registry mirrors of the cluster, with the ones of
+// the nodegroup taking precedence
+func registryMirrors(spec *api.ClusterConfig, ng *api.NodeGroup) map[string][]string {
+	mirrors := map[string][]string{}
+	for host, endpoints := range spec.RegistryMirrors {
+		mirrors[host] = endpoints
+	}
+	if ng.ContainerdConfig != nil {
+		for host, endpoints := range ng.ContainerdConfig.RegistryMirrors {
+			mirrors[host] = endpoints
+		}
+	}
+	return mirrors
+}
+
+// makeContainerdHostsConfig returns the hosts file of a registry, which makes
+// containerd try its mirrors in order before the registry itself
+func makeContainerdHostsConfig(endpoints []string) string {
+	var config strings.Builder
+	for i, endpoint := range endpoints {
+		if i > 0 {
+			config.WriteString("\n")
+		}
+		config.WriteString(fmt.Sprintf("[host.%q]\ncapabilities = [\"pull\", \"resolve\"]\n", endpoint))
+	}
+	return config.String()
+}
+
+// addContainerdFiles adds the configuration of containerd when it's the container runtime;
+// the sandbox image goes to a drop-in file, and as containerd replaces the sections of a
+// plugin with the ones of imported files, the drop-in file has all of them. Registry
+// mirrors are configured with a hosts file per registry
+func addContainerdFiles(files configFiles, spec *api.ClusterConfig, ng *api.NodeGroup) {
+	if !usesContainerd(ng) {
+		return
+	}
+
+	files[containerdConfigDir] = map[string]configFile{
+		containerdConfigFile: {content: containerdBaseConfig + "\n" + makeContainerdCRIConfig(nil)},
+	}
+	if ng.ContainerdConfig != nil && ng.ContainerdConfig.SandboxImage != "" {
+		files[containerdDropInDir] = map[string]configFile{
+			containerdDropInFile: {content: makeContainerdCRIConfig(ng.ContainerdConfig)},
+		}
+	}
+	for host, endpoints := range registryMirrors(spec, ng) {
+		files[containerdHostsDir+host+"/"] = map[string]configFile{
+			containerdHostsFile: {content: makeContainerdHostsConfig(endpoints)},
+		}
+	}
+}
+
+// makeContainerdCommands returns the shell commands that restart containerd with its
+// new configuration, they must run before kubelet is started
+func makeContainerdCommands(ng *api.NodeGroup) []string {
+	if !usesContainerd(ng) {
+		return nil
+	}
+	return []string{"systemctl enable containerd", "systemctl restart containerd"}
+}
+
+// setContainerRuntimeFlags replaces the docker runtime flag of kubelet in data, whose
+// flags are formatted with flagFormat, by the flags of the container runtime of ng
+func setContainerRuntimeFlags(data, flagFormat string, ng *api.NodeGroup) (string, error) {
+	if !usesContainerd(ng) {
+		return data, nil
+	}
+
+	dockerFlag := fmt.Sprintf(flagFormat, dockerRuntimeFlag)
+	if !strings.Contains(data, dockerFlag) {
+		return "", errors.Errorf("kubelet flag %q not found", dockerFlag)
+	}
+	containerdFlags := []string{
+		fmt.Sprintf(flagFormat, "container-runtime=remote"),
+		fmt.Sprintf(flagFormat, "container-runtime-endpoint="+containerdEndpoint),
+	}
+	return strings.Replace(data, dockerFlag, strings.Join(containerdFlags, " "), 1), nil
+}
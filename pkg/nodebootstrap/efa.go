@@ -0,0 +1,25 @@
+package nodebootstrap
+
+import (
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	efaInstallerURL     = "https://efa-installer.amazonaws.com/aws-efa-installer-latest.tar.gz"
+	efaInstallerArchive = "/tmp/aws-efa-installer.tar.gz"
+)
+
+// makeEFACommands returns the shell commands that install the EFA software, i.e. the kernel
+// module and the libfabric libraries, before kubelet starts; the installer is run with -g to
+// skip the GPU tests, which would fail on instances without GPUs
+func makeEFACommands(ng *api.NodeGroup) []string {
+	if !api.IsEnabled(ng.EFAEnabled) {
+		return nil
+	}
+	return []string{
+		"curl --silent --show-error --retry 5 --output " + efaInstallerArchive + " " + efaInstallerURL,
+		"tar -xf " + efaInstallerArchive + " -C /tmp",
+		"cd /tmp/aws-efa-installer && ./efa_installer.sh -y -g && cd -",
+		"rm -rf " + efaInstallerArchive + " /tmp/aws-efa-installer",
+	}
+}
@@ -0,0 +1,86 @@
+package nodebootstrap
+
+import (
+	"fmt"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	instanceStoreScript = "setup-instance-store.sh"
+	instanceStoreDevice = "/dev/md/eksctl-instance-store"
+)
+
+// instanceStoreScriptBody formats the instance store volumes, which the NVMe driver names
+// after the "Amazon EC2 NVMe Instance Storage" model, and mounts them; the container runtimes
+// are stopped meanwhile, as the mount path is typically their data directory
+const instanceStoreScriptBody = `
+readarray -t devices < <(
+  for link in /dev/disk/by-id/nvme-Amazon_EC2_NVMe_Instance_Storage_*; do
+    if [[ -e "${link}" ]] && [[ ! "${link}" =~ -part[0-9]+$ ]]; then
+      readlink -f "${link}"
+    fi
+  done | sort -u
+)
+
+if [[ ${#devices[@]} -eq 0 ]]; then
+  echo "no instance store volumes found, ${MOUNT_PATH} is left on the root volume" >&2
+  exit 0
+fi
+
+if [[ "${RAID0}" == "true" ]] && [[ ${#devices[@]} -gt 1 ]]; then
+  if ! command -v mdadm > /dev/null; then
+    yum install -y mdadm || apt-get install -y mdadm
+  fi
+  mdadm --create --force --run --level=0 --raid-devices=${#devices[@]} "${DEVICE}" "${devices[@]}"
+else
+  DEVICE="${devices[0]}"
+fi
+mkfs.xfs -f "${DEVICE}"
+
+stopped=()
+for service in docker containerd; do
+  if systemctl is-active --quiet "${service}"; then
+    systemctl stop "${service}"
+    stopped+=("${service}")
+  fi
+done
+
+mkdir -p "${MOUNT_PATH}"
+# the existing content of the mount path is copied over
+staging_dir="$(mktemp -d)"
+mount -o defaults,noatime "${DEVICE}" "${staging_dir}"
+cp -a "${MOUNT_PATH}/." "${staging_dir}/"
+umount "${staging_dir}"
+rmdir "${staging_dir}"
+mount -o defaults,noatime "${DEVICE}" "${MOUNT_PATH}"
+echo "UUID=$(blkid -s UUID -o value "${DEVICE}") ${MOUNT_PATH} xfs defaults,noatime,nofail 0 2" >> /etc/fstab
+
+# an empty array is an unbound variable for the version of bash of Amazon Linux 2
+for service in ${stopped[@]+"${stopped[@]}"}; do
+  systemctl start "${service}"
+done
+`
+
+// addInstanceStoreFiles adds the script that formats and mounts the instance store volumes
+func addInstanceStoreFiles(files configFiles, ng *api.NodeGroup) {
+	if ng.InstanceStore == nil {
+		return
+	}
+
+	header := fmt.Sprintf("#!/bin/bash\n\nset -o errexit\nset -o pipefail\nset -o nounset\n\nMOUNT_PATH=%q\nRAID0=%q\nDEVICE=%q\n",
+		ng.InstanceStore.MountPath, fmt.Sprint(api.IsEnabled(ng.InstanceStore.RAID0)), instanceStoreDevice)
+	if files[configDir] == nil {
+		files[configDir] = map[string]configFile{}
+	}
+	files[configDir][instanceStoreScript] = configFile{content: header + instanceStoreScriptBody}
+}
+
+// makeInstanceStoreCommands returns the shell commands that run the instance store
+// script, they must run before kubelet is started
+func makeInstanceStoreCommands(ng *api.NodeGroup) []string {
+	if ng.InstanceStore == nil {
+		return nil
+	}
+	return []string{"bash " + configDir + instanceStoreScript}
+}
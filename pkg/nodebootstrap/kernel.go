@@ -0,0 +1,93 @@
+package nodebootstrap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	sysctlDropInDir       = "/etc/sysctl.d/"
+	sysctlDropInFile      = "91-eksctl.conf"
+	modulesLoadDropInDir  = "/etc/modules-load.d/"
+	modulesLoadDropInFile = "eksctl.conf"
+)
+
+// namespacedSysctlPrefixes are the sysctl prefixes that kubelet accepts in
+// allowedUnsafeSysctls, as only namespaced sysctls can be set per pod
+var namespacedSysctlPrefixes = []string{
+	"kernel.shm",
+	"kernel.msg",
+	"kernel.sem",
+	"fs.mqueue.",
+	"net.",
+}
+
+func isNamespacedSysctl(name string) bool {
+	name = strings.Replace(name, "/", ".", -1)
+	for _, prefix := range namespacedSysctlPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedSysctlNames(ng *api.NodeGroup) []string {
+	names := make([]string, 0, len(ng.OverrideSysctls))
+	for name := range ng.OverrideSysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addKernelConfigFiles adds the drop-in files that persist kernel modules
+// and sysctls across reboots
+func addKernelConfigFiles(files configFiles, ng *api.NodeGroup) {
+	if len(ng.KernelModules) > 0 {
+		files[modulesLoadDropInDir] = map[string]configFile{
+			modulesLoadDropInFile: {content: strings.Join(ng.KernelModules, "\n") + "\n"},
+		}
+	}
+
+	if len(ng.OverrideSysctls) > 0 {
+		var content strings.Builder
+		for _, name := range sortedSysctlNames(ng) {
+			content.WriteString(fmt.Sprintf("%s = %s\n", name, ng.OverrideSysctls[name]))
+		}
+		files[sysctlDropInDir] = map[string]configFile{
+			sysctlDropInFile: {content: content.String()},
+		}
+	}
+}
+
+// makeKernelConfigCommands returns the shell commands that apply the kernel
+// configuration on first boot; modules are loaded first, as some sysctls
+// only exist once their module is loaded
+func makeKernelConfigCommands(ng *api.NodeGroup) []string {
+	var commands []string
+	if len(ng.KernelModules) > 0 {
+		commands = append(commands, "modprobe -a "+strings.Join(ng.KernelModules, " "))
+	}
+	if len(ng.OverrideSysctls) > 0 {
+		commands = append(commands, "sysctl -p "+sysctlDropInDir+sysctlDropInFile)
+	}
+	return commands
+}
+
+// setKubeletAllowedUnsafeSysctls allows pods to set the namespaced sysctls
+// that are overridden on the node
+func setKubeletAllowedUnsafeSysctls(obj api.InlineDocument, ng *api.NodeGroup) {
+	var allowed []string
+	for _, name := range sortedSysctlNames(ng) {
+		if isNamespacedSysctl(name) {
+			allowed = append(allowed, name)
+		}
+	}
+	if len(allowed) > 0 {
+		obj["allowedUnsafeSysctls"] = allowed
+	}
+}
@@ -0,0 +1,98 @@
+package nodebootstrap
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	swapFile          = "/swapfile"
+	hugePagesSysctlD  = "/etc/sysctl.d/90-eksctl-hugepages.conf"
+	hugePagesSysfsDir = "/sys/kernel/mm/hugepages/"
+)
+
+// hugePagesSysfsNames maps huge page sizes to their sysfs directory names
+var hugePagesSysfsNames = map[string]string{
+	api.HugePageSize2Mi: "hugepages-2048kB",
+	api.HugePageSize1Gi: "hugepages-1048576kB",
+}
+
+func hasSwap(ng *api.NodeGroup) bool {
+	return ng.MemoryConfig != nil && ng.MemoryConfig.Swap != nil
+}
+
+// makeMemoryConfigCommands returns the shell commands that set up swap and
+// huge pages on the node, they must run before kubelet is started
+func makeMemoryConfigCommands(ng *api.NodeGroup) ([]string, error) {
+	if ng.MemoryConfig == nil {
+		return nil, nil
+	}
+
+	var commands []string
+
+	if swap := ng.MemoryConfig.Swap; swap != nil {
+		size, err := resource.ParseQuantity(swap.Size)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing swap size %q", swap.Size)
+		}
+		sizeMiB := (size.Value() + (1 << 20) - 1) >> 20
+		commands = append(commands, fmt.Sprintf(
+			"fallocate -l %dM %[2]s && chmod 600 %[2]s && mkswap %[2]s && swapon %[2]s && echo '%[2]s none swap sw 0 0' >> /etc/fstab",
+			sizeMiB, swapFile,
+		))
+	}
+
+	pageSizes := make([]string, 0, len(ng.MemoryConfig.HugePages))
+	for pageSize := range ng.MemoryConfig.HugePages {
+		pageSizes = append(pageSizes, pageSize)
+	}
+	sort.Strings(pageSizes)
+
+	for _, pageSize := range pageSizes {
+		count := ng.MemoryConfig.HugePages[pageSize]
+		switch pageSize {
+		case api.HugePageSize2Mi:
+			// 2Mi is the default huge page size, so it can be persisted with sysctl
+			commands = append(commands, fmt.Sprintf(
+				"echo 'vm.nr_hugepages = %d' > %[2]s && sysctl -p %[2]s",
+				count, hugePagesSysctlD,
+			))
+		default:
+			sysfsName, ok := hugePagesSysfsNames[pageSize]
+			if !ok {
+				return nil, fmt.Errorf("unsupported huge page size %q", pageSize)
+			}
+			commands = append(commands, fmt.Sprintf(
+				"echo %d > %s%s/nr_hugepages", count, hugePagesSysfsDir, sysfsName,
+			))
+		}
+	}
+
+	return commands, nil
+}
+
+// setKubeletMemoryConfig sets the kubelet config fields needed to run on
+// nodes with swap enabled
+func setKubeletMemoryConfig(obj api.InlineDocument, ng *api.NodeGroup) {
+	if !hasSwap(ng) {
+		return
+	}
+
+	obj["failSwapOn"] = false
+
+	featureGates, ok := obj["featureGates"].(map[string]interface{})
+	if !ok {
+		featureGates = map[string]interface{}{}
+		obj["featureGates"] = featureGates
+	}
+	featureGates["NodeSwap"] = true
+
+	obj["memorySwap"] = api.InlineDocument{
+		"swapBehavior": ng.MemoryConfig.Swap.Behavior,
+	}
+}
@@ -12,6 +12,8 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 )
 
@@ -20,6 +22,8 @@ import (
 const (
 	configDir            = "/etc/eksctl/"
 	kubeletDropInUnitDir = "/etc/systemd/system/kubelet.service.d/"
+
+	preBootstrapScriptHeader = "#!/bin/bash\n"
 )
 
 type configFile struct {
@@ -65,6 +69,20 @@ func addFilesAndScripts(config *cloudconfig.CloudConfig, files configFiles, scri
 	return nil
 }
 
+// encodeCloudConfig encodes the cloud config of the nodes; each pre-bootstrap command is run by a
+// shell script in its own part of a MIME multi-part archive, so that it can be told apart from the
+// commands that eksctl generates, and cloud-init runs it before them
+func encodeCloudConfig(config *cloudconfig.CloudConfig, ng *api.NodeGroup) (string, error) {
+	if len(ng.PreBootstrapCommands) == 0 {
+		return config.Encode()
+	}
+	scripts := make([]string, len(ng.PreBootstrapCommands))
+	for i, command := range ng.PreBootstrapCommands {
+		scripts[i] = preBootstrapScriptHeader + command + "\n"
+	}
+	return config.EncodeMultiPart(scripts)
+}
+
 func makeClientConfigData(spec *api.ClusterConfig, ng *api.NodeGroup, authenticatorCMD string) ([]byte, error) {
 	clientConfig, _, _ := kubeconfig.New(spec, "kubelet", configDir+"ca.crt")
 	kubeconfig.AppendAuthenticator(clientConfig, spec, authenticatorCMD, "", "")
@@ -114,6 +132,9 @@ func makeKubeletConfigYAML(spec *api.ClusterConfig, ng *api.NodeGroup) ([]byte,
 		obj["kubeReserved"].(api.InlineDocument)["memory"] = info.DefaultMemoryToReserve()
 	}
 
+	setKubeletMemoryConfig(obj, ng)
+	setKubeletAllowedUnsafeSysctls(obj, ng)
+
 	// Add extra configuration from configfile
 	if ng.KubeletExtraConfig != nil {
 		for k, v := range *ng.KubeletExtraConfig {
@@ -121,6 +142,10 @@ func makeKubeletConfigYAML(spec *api.ClusterConfig, ng *api.NodeGroup) ([]byte,
 		}
 	}
 
+	if err := checkKubeletConfigVersion(obj, spec.Metadata.Version); err != nil {
+		return nil, err
+	}
+
 	data, err = yaml.Marshal(obj)
 	if err != nil {
 		return nil, err
@@ -134,6 +159,36 @@ func makeKubeletConfigYAML(spec *api.ClusterConfig, ng *api.NodeGroup) ([]byte,
 	return data, nil
 }
 
+// kubeletConfigFieldVersions are the Kubernetes versions that added fields of KubeletConfiguration
+// after the oldest version supported by EKS, fields newer than the vendored KubeletConfiguration
+// type are rejected when the generated config is validated
+var kubeletConfigFieldVersions = map[string]string{
+	"nodeLeaseDurationSeconds":                  api.Version1_13,
+	"nodeStatusReportFrequency":                 api.Version1_13,
+	"configMapAndSecretChangeDetectionStrategy": api.Version1_14,
+	"memorySwap": api.SwapMinVersion,
+}
+
+// checkKubeletConfigVersion checks that the kubelet of the given Kubernetes version supports
+// the fields of kubeletConfig, the check is skipped if the version isn't known yet
+func checkKubeletConfigVersion(kubeletConfig api.InlineDocument, version string) error {
+	for field := range kubeletConfig {
+		minVersion, ok := kubeletConfigFieldVersions[field]
+		if !ok {
+			continue
+		}
+		supported, err := utils.IsMinVersion(minVersion, version)
+		if err != nil {
+			logger.Debug("skipping check of kubelet config field %q: %v", field, err)
+			continue
+		}
+		if !supported {
+			return fmt.Errorf("kubelet config field %q requires Kubernetes %s or newer, the nodegroup uses %s", field, minVersion, version)
+		}
+	}
+	return nil
+}
+
 func kvs(kv map[string]string) string {
 	var params []string
 	for k, v := range kv {
@@ -156,8 +211,10 @@ func makeCommonKubeletEnvParams(spec *api.ClusterConfig, ng *api.NodeGroup) []st
 		fmt.Sprintf("NODE_TAINTS=%s", kvs(ng.Taints)),
 	}
 
-	if ng.MaxPodsPerNode != 0 {
-		variables = append(variables, fmt.Sprintf("MAX_PODS=%d", ng.MaxPodsPerNode))
+	// the --max-pods flag of kubelet takes precedence over maxPods in its config file,
+	// so it has to be set when maxPods is only set in kubeletExtraConfig
+	if maxPods := maxPodsPerNode(spec, ng); maxPods != 0 {
+		variables = append(variables, fmt.Sprintf("MAX_PODS=%d", maxPods))
 	}
 	return variables
 }
@@ -171,6 +228,62 @@ func makeMetadata(spec *api.ClusterConfig) []string {
 	}
 }
 
+// maxPodsPerNode returns the maximum number of pods of the nodes, computed from the instance types
+// when maxPodsPerNode is auto or when prefix delegation is enabled and it's not set, or 0 to use
+// the default of the AMI
+func maxPodsPerNode(spec *api.ClusterConfig, ng *api.NodeGroup) int {
+	prefixDelegation := api.IsEnabled(spec.PrefixDelegation)
+	if !ng.MaxPodsPerNode.IsAuto() && (ng.MaxPods() != 0 || !prefixDelegation) {
+		return ng.MaxPods()
+	}
+
+	instanceTypes := []string{ng.InstanceType}
+	if api.HasMixedInstances(ng) {
+		instanceTypes = ng.InstancesDistribution.InstanceTypes
+	}
+	maxPods := 0
+	for _, instanceType := range instanceTypes {
+		n, ok := computeMaxPods(instanceType, prefixDelegation)
+		if !ok {
+			logger.Warning("unable to compute the maximum number of pods of instance type %s, using the default of the AMI", instanceType)
+			return 0
+		}
+		// the nodes of mixed instances nodegroups share the same user data
+		if maxPods == 0 || n < maxPods {
+			maxPods = n
+		}
+	}
+	return maxPods
+}
+
+// computeMaxPods returns the maximum number of pods of an instance type, like the max-pods-calculator.sh
+// script of the EKS AMI; with prefix delegation each secondary IP of the network interfaces becomes a
+// /28 prefix, and the number of pods is limited to the recommended 110 for instances with less than 30
+// vCPUs and to 250 otherwise
+func computeMaxPods(instanceType string, prefixDelegation bool) (int, bool) {
+	// the maximum number of pods without prefix delegation is ENIs * (IPs per ENI - 1) + 2
+	maxPods, ok := maxPodsPerNodeType[instanceType]
+	if !ok {
+		return 0, false
+	}
+	if !prefixDelegation {
+		return maxPods, true
+	}
+	info, ok := instanceTypeInfos[instanceType]
+	if !ok {
+		return 0, false
+	}
+	maxPods = (maxPods-2)*16 + 2
+	limit := 110
+	if info.CPU >= 30 {
+		limit = 250
+	}
+	if maxPods > limit {
+		maxPods = limit
+	}
+	return maxPods, true
+}
+
 func makeMaxPodsMapping() string {
 	var text strings.Builder
 	for k, v := range maxPodsPerNodeType {
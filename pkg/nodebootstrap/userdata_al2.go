@@ -3,10 +3,10 @@ package nodebootstrap
 import (
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 )
 
@@ -25,9 +25,18 @@ func makeAmazonLinux2Config(spec *api.ClusterConfig, ng *api.NodeGroup) (configF
 		return nil, err
 	}
 
+	kubeletDropInUnit, err := getAsset("10-eksclt.al2.conf")
+	if err != nil {
+		return nil, err
+	}
+	kubeletDropInUnit, err = setContainerRuntimeFlags(kubeletDropInUnit, "--%s", ng)
+	if err != nil {
+		return nil, errors.Wrap(err, "setting container runtime of kubelet")
+	}
+
 	files := configFiles{
 		kubeletDropInUnitDir: {
-			"10-eksclt.al2.conf": {isAsset: true},
+			"10-eksclt.al2.conf": {content: kubeletDropInUnit},
 		},
 		configDir: {
 			"metadata.env": {content: strings.Join(makeMetadata(spec), "\n")},
@@ -54,12 +63,40 @@ func NewUserDataForAmazonLinux2(spec *api.ClusterConfig, ng *api.NodeGroup) (str
 
 	var scripts []string
 
-	for _, command := range ng.PreBootstrapCommands {
+	addKernelConfigFiles(files, ng)
+	for _, command := range makeKernelConfigCommands(ng) {
+		config.AddShellCommand(command)
+	}
+
+	memoryCommands, err := makeMemoryConfigCommands(ng)
+	if err != nil {
+		return "", err
+	}
+	for _, command := range memoryCommands {
+		config.AddShellCommand(command)
+	}
+
+	addInstanceStoreFiles(files, ng)
+	for _, command := range makeInstanceStoreCommands(ng) {
+		config.AddShellCommand(command)
+	}
+
+	addContainerdFiles(files, spec, ng)
+	for _, command := range makeContainerdCommands(ng) {
+		config.AddShellCommand(command)
+	}
+
+	for _, command := range makeEFACommands(ng) {
 		config.AddShellCommand(command)
 	}
 
 	if ng.OverrideBootstrapCommand != nil {
 		config.AddShellCommand(*ng.OverrideBootstrapCommand)
+	} else if ng.WarmPool != nil {
+		addWarmPoolFiles(files, ng)
+		for _, command := range makeWarmPoolCommands(ng) {
+			config.AddShellCommand(command)
+		}
 	} else {
 		scripts = append(scripts, "bootstrap.al2.sh")
 	}
@@ -68,7 +105,7 @@ func NewUserDataForAmazonLinux2(spec *api.ClusterConfig, ng *api.NodeGroup) (str
 		return "", err
 	}
 
-	body, err := config.Encode()
+	body, err := encodeCloudConfig(config, ng)
 	if err != nil {
 		return "", errors.Wrap(err, "encoding user data")
 	}
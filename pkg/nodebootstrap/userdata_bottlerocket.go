@@ -3,6 +3,7 @@ package nodebootstrap
 import (
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pelletier/go-toml"
@@ -19,7 +20,7 @@ func NewUserDataForBottlerocket(spec *api.ClusterConfig, ng *api.NodeGroup) (str
 	// Update settings based on NodeGroup configuration. Values set here are not
 	// allowed to be set by the user - the values are owned by the NodeGroup and
 	// expressly written into settings.
-	if err := setDerivedBottlerocketSettings(ng); err != nil {
+	if err := setDerivedBottlerocketSettings(spec, ng); err != nil {
 		return "", err
 	}
 
@@ -43,7 +44,7 @@ func NewUserDataForBottlerocket(spec *api.ClusterConfig, ng *api.NodeGroup) (str
 	return base64.StdEncoding.EncodeToString([]byte(data)), nil
 }
 
-func setDerivedBottlerocketSettings(ng *api.NodeGroup) error {
+func setDerivedBottlerocketSettings(spec *api.ClusterConfig, ng *api.NodeGroup) error {
 	settings := *ng.Bottlerocket.Settings
 
 	var kubernetesSettings map[string]interface{}
@@ -64,15 +65,39 @@ func setDerivedBottlerocketSettings(ng *api.NodeGroup) error {
 	if len(ng.Taints) != 0 {
 		kubernetesSettings["node-taints"] = ng.Taints
 	}
-	if ng.MaxPodsPerNode != 0 {
-		kubernetesSettings["max-pods"] = ng.MaxPodsPerNode
+	if maxPods := maxPodsPerNode(spec, ng); maxPods != 0 {
+		kubernetesSettings["max-pods"] = maxPods
 	}
 	if ng.ClusterDNS != "" {
 		kubernetesSettings["cluster-dns-ip"] = ng.ClusterDNS
 	}
+
+	// Don't override the user's mirrors if they provided them in config.
+	if _, ok := settings["container-registry"]; !ok && len(spec.RegistryMirrors) > 0 {
+		settings["container-registry"] = map[string]interface{}{
+			"mirrors": bottlerocketRegistryMirrors(spec.RegistryMirrors),
+		}
+	}
 	return nil
 }
 
+func bottlerocketRegistryMirrors(registryMirrors map[string][]string) []map[string]interface{} {
+	hosts := make([]string, 0, len(registryMirrors))
+	for host := range registryMirrors {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	mirrors := make([]map[string]interface{}, 0, len(hosts))
+	for _, host := range hosts {
+		mirrors = append(mirrors, map[string]interface{}{
+			"registry": host,
+			"endpoint": registryMirrors[host],
+		})
+	}
+	return mirrors
+}
+
 // protectTOMLKeys processes a tree finding and replacing dotted keys
 // with quoted keys to retain the configured settings. This prevents
 // TOML parsers from deserializing keys into nested key-value pairs at
@@ -238,6 +238,24 @@ var _ = Describe("Bottlerocket", func() {
 					Expect(tree.GetPath(append(labelsPath, key))).To(Equal(val))
 				}
 			})
+
+			It("uses the registry mirrors of the cluster", func() {
+				clusterConfig.RegistryMirrors = map[string][]string{
+					"docker.io": {"https://artifactory.example.com"},
+				}
+
+				userdata, err := NewUserDataForBottlerocket(clusterConfig, ng)
+				Expect(err).ToNot(HaveOccurred())
+
+				tree, parseErr := userdataTOML(userdata)
+				Expect(parseErr).ToNot(HaveOccurred())
+
+				mirrors, ok := tree.GetPath([]string{"settings", "container-registry", "mirrors"}).([]*toml.Tree)
+				Expect(ok).To(BeTrue())
+				Expect(mirrors).To(HaveLen(1))
+				Expect(mirrors[0].Get("registry")).To(Equal("docker.io"))
+				Expect(mirrors[0].Get("endpoint")).To(Equal([]interface{}{"https://artifactory.example.com"}))
+			})
 		})
 
 	})
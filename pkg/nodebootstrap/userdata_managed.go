@@ -0,0 +1,46 @@
+package nodebootstrap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// managedBoundary is fixed, so that the user data only changes when the commands do
+const managedBoundary = "//eksctl//"
+
+// NewUserDataForManagedNodeGroup creates the user data of the launch template of a managed nodegroup,
+// it's empty if there's nothing to run; EKS merges it with the part that bootstraps the node,
+// so it must be a MIME multi-part archive
+func NewUserDataForManagedNodeGroup(ng *api.ManagedNodeGroup) (string, error) {
+	if len(ng.PreBootstrapCommands) == 0 {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(managedBoundary); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%q\r\n\r\n", managedBoundary)
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {`text/x-shellscript; charset="us-ascii"`},
+	})
+	if err != nil {
+		return "", err
+	}
+	script := "#!/bin/bash\nset -o errexit\n" + strings.Join(ng.PreBootstrapCommands, "\n") + "\n"
+	if _, err := part.Write([]byte(script)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,47 @@
+package nodebootstrap
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("User data of managed nodegroups", func() {
+	It("is empty without commands", func() {
+		userData, err := NewUserDataForManagedNodeGroup(api.NewManagedNodeGroup())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userData).To(BeEmpty())
+	})
+
+	It("is a MIME multi-part archive with a script running the commands", func() {
+		ng := api.NewManagedNodeGroup()
+		ng.PreBootstrapCommands = []string{"echo hello", "yum install -y htop"}
+
+		userData, err := NewUserDataForManagedNodeGroup(ng)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := base64.StdEncoding.DecodeString(userData)
+		Expect(err).NotTo(HaveOccurred())
+
+		message, err := mail.ReadMessage(strings.NewReader(string(data)))
+		Expect(err).NotTo(HaveOccurred())
+		mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mediaType).To(Equal("multipart/mixed"))
+
+		reader := multipart.NewReader(message.Body, params["boundary"])
+		part, err := reader.NextPart()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(part.Header.Get("Content-Type")).To(HavePrefix("text/x-shellscript"))
+		script, err := ioutil.ReadAll(part)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(script)).To(Equal("#!/bin/bash\nset -o errexit\necho hello\nyum install -y htop\n"))
+	})
+})
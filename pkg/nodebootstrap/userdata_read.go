@@ -0,0 +1,188 @@
+package nodebootstrap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// SetNodeGroupFromUserData sets the fields of an existing nodegroup that are only found in the
+// user data of its nodes, i.e. labels, taints, kubeletExtraConfig and preBootstrapCommands;
+// only the cloud configs of Amazon Linux 2 and Ubuntu nodes are read, along with the scripts of
+// the pre-bootstrap commands when they're in a multi-part archive, the user data of other AMI
+// families is left alone
+func SetNodeGroupFromUserData(spec *api.ClusterConfig, ng *api.NodeGroup, userData string) error {
+	switch ng.AMIFamily {
+	case api.NodeImageFamilyAmazonLinux2, api.NodeImageFamilyUbuntu1804:
+	default:
+		return nil
+	}
+
+	config, scripts, err := cloudconfig.DecodeMultiPart(userData)
+	if err != nil {
+		return errors.Wrapf(err, "decoding user data of nodegroup %q", ng.Name)
+	}
+	var preBootstrapCommands []string
+	for _, script := range scripts {
+		preBootstrapCommands = append(preBootstrapCommands, strings.TrimSuffix(strings.TrimPrefix(script, preBootstrapScriptHeader), "\n"))
+	}
+
+	withWarmPool := false
+	for _, f := range config.WriteFiles {
+		switch f.Path {
+		case configDir + "kubelet.env":
+			setNodeGroupFromKubeletEnv(ng, f.Content)
+		case configDir + "kubelet.yaml":
+			if err := setNodeGroupFromKubeletConfig(spec, ng, f.Content); err != nil {
+				return errors.Wrapf(err, "reading kubelet config of nodegroup %q", ng.Name)
+			}
+		case systemdUnitDir + warmPoolBootstrapUnit:
+			withWarmPool = true
+		}
+	}
+
+	if withWarmPool {
+		logger.Warning("only the pre-bootstrap commands of nodegroup %q are read, as its nodes are bootstrapped for a warm pool", ng.Name)
+		ng.PreBootstrapCommands = preBootstrapCommands
+		return nil
+	}
+	setNodeGroupFromCommands(ng, config.Commands)
+	if len(preBootstrapCommands) > 0 {
+		ng.PreBootstrapCommands = append(preBootstrapCommands, ng.PreBootstrapCommands...)
+	}
+	return nil
+}
+
+// setNodeGroupFromKubeletEnv sets the labels and taints from the variables passed to the bootstrap script
+func setNodeGroupFromKubeletEnv(ng *api.NodeGroup, env string) {
+	for _, line := range strings.Split(env, "\n") {
+		name, value := splitKeyValue(line)
+		switch name {
+		case "NODE_LABELS":
+			ng.Labels = parseKVs(value)
+		case "NODE_TAINTS":
+			ng.Taints = parseKVs(value)
+		}
+	}
+}
+
+// parseKVs is the inverse of kvs
+func parseKVs(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+	kv := map[string]string{}
+	for _, param := range strings.Split(s, ",") {
+		k, v := splitKeyValue(param)
+		kv[k] = v
+	}
+	return kv
+}
+
+func splitKeyValue(s string) (string, string) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// kubeletConfigFieldsNotRead are the fields of the kubelet config generated from settings of the
+// nodegroup that can't be set in kubeletExtraConfig, as they are newer than the vendored type
+var kubeletConfigFieldsNotRead = map[string]bool{
+	"memorySwap": true,
+}
+
+// setNodeGroupFromKubeletConfig sets kubeletExtraConfig to the fields of the kubelet config that
+// differ from the ones generated for the nodegroup, the cluster DNS being read into clusterDNS
+func setNodeGroupFromKubeletConfig(spec *api.ClusterConfig, ng *api.NodeGroup, kubeletConfigYAML string) error {
+	var kubeletConfig api.InlineDocument
+	if err := yaml.Unmarshal([]byte(kubeletConfigYAML), &kubeletConfig); err != nil {
+		return err
+	}
+
+	defaults := *ng
+	defaults.KubeletExtraConfig = nil
+	defaults.ClusterDNS = ""
+	defaultsYAML, err := makeKubeletConfigYAML(spec, &defaults)
+	if err != nil {
+		return err
+	}
+	var defaultConfig api.InlineDocument
+	if err := yaml.Unmarshal(defaultsYAML, &defaultConfig); err != nil {
+		return err
+	}
+
+	extraConfig := api.InlineDocument{}
+	for key, value := range kubeletConfig {
+		if kubeletConfigFieldsNotRead[key] || reflect.DeepEqual(value, defaultConfig[key]) {
+			continue
+		}
+		if key == "clusterDNS" {
+			if servers, ok := value.([]interface{}); ok && len(servers) == 1 {
+				ng.ClusterDNS = fmt.Sprint(servers[0])
+				continue
+			}
+		}
+		extraConfig[key] = value
+	}
+	if len(extraConfig) > 0 {
+		ng.KubeletExtraConfig = &extraConfig
+	} else {
+		ng.KubeletExtraConfig = nil
+	}
+	return nil
+}
+
+// setNodeGroupFromCommands sets preBootstrapCommands to the shell commands of the cloud config that
+// are run before the bootstrap script, and overrideBootstrapCommand to the last one when the script
+// isn't run; the commands that eksctl generates for settings that aren't read back are kept as
+// pre-bootstrap commands, so that the nodes of a copy of the nodegroup are set up the same way, and
+// the pre-bootstrap commands of user data that predates multi-part archives are read this way too
+func setNodeGroupFromCommands(ng *api.NodeGroup, commands []interface{}) {
+	var shellCommands []string
+	for _, command := range commands {
+		args, ok := command.([]interface{})
+		if !ok {
+			continue
+		}
+		if len(args) == 1 && isBootstrapScript(args[0]) {
+			ng.PreBootstrapCommands = shellCommands
+			return
+		}
+		if len(args) == 3 && args[0] == cloudconfig.Shell && args[1] == "-c" {
+			if s, ok := args[2].(string); ok {
+				shellCommands = append(shellCommands, s)
+			}
+		}
+	}
+
+	if len(shellCommands) == 0 {
+		return
+	}
+	last := len(shellCommands) - 1
+	if last > 0 {
+		ng.PreBootstrapCommands = shellCommands[:last]
+	}
+	ng.OverrideBootstrapCommand = &shellCommands[last]
+}
+
+func isBootstrapScript(arg interface{}) bool {
+	path, ok := arg.(string)
+	if !ok {
+		return false
+	}
+	for _, script := range []string{"bootstrap.al2.sh", "bootstrap.ubuntu.sh"} {
+		if strings.HasSuffix(path, "/"+script) {
+			return true
+		}
+	}
+	return false
+}
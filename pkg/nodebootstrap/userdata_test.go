@@ -7,6 +7,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/aws/aws-sdk-go/aws"
+
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	kubeletapi "k8s.io/kubelet/config/v1beta1"
 	"sigs.k8s.io/yaml"
@@ -24,6 +26,33 @@ var _ = Describe("User data", func() {
 				Expect(err).ToNot(HaveOccurred())
 			}
 		})
+
+		It("computes max pods from the network interfaces of the instance type", func() {
+			Expect(computeMaxPods("m5.large", false)).To(Equal(29))
+			Expect(computeMaxPods("t3.micro", true)).To(Equal(34))
+			Expect(computeMaxPods("m5.large", true)).To(Equal(110))
+			Expect(computeMaxPods("m5.24xlarge", true)).To(Equal(250))
+			_, ok := computeMaxPods("m5.unknown", false)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("sets MAX_PODS when maxPodsPerNode is auto or prefix delegation is enabled", func() {
+			clusterConfig := api.NewClusterConfig()
+			ng := &api.NodeGroup{InstanceType: "m5.large"}
+			Expect(makeCommonKubeletEnvParams(clusterConfig, ng)).NotTo(ContainElement(HavePrefix("MAX_PODS=")))
+
+			ng.MaxPodsPerNode = api.MaxPodsPerNodeAuto
+			Expect(makeCommonKubeletEnvParams(clusterConfig, ng)).To(ContainElement("MAX_PODS=29"))
+
+			clusterConfig.PrefixDelegation = api.Enabled()
+			ng.MaxPodsPerNode = 0
+			ng.InstanceType = "mixed"
+			ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{InstanceTypes: []string{"m5.large", "t3.micro"}}
+			Expect(makeCommonKubeletEnvParams(clusterConfig, ng)).To(ContainElement("MAX_PODS=34"))
+
+			ng.MaxPodsPerNode = 50
+			Expect(makeCommonKubeletEnvParams(clusterConfig, ng)).To(ContainElement("MAX_PODS=50"))
+		})
 	})
 
 	Describe("creating kubelet config", func() {
@@ -89,5 +118,329 @@ var _ = Describe("User data", func() {
 			Expect(kubelet.FeatureGates["DynamicKubeletConfig"]).To(Equal(true))
 			Expect(kubelet.FeatureGates["RotateKubeletServerCertificate"]).To(Equal(false))
 		})
+
+		It("the kubelet config allows swap when it's configured", func() {
+			ng.MemoryConfig = &api.NodeGroupMemoryConfig{
+				Swap: &api.NodeGroupSwap{
+					Size:     "4Gi",
+					Behavior: api.SwapBehaviorLimited,
+				},
+			}
+			obj := api.InlineDocument{
+				"featureGates": map[string]interface{}{"RotateKubeletServerCertificate": true},
+			}
+			setKubeletMemoryConfig(obj, ng)
+			Expect(obj["failSwapOn"]).To(Equal(false))
+			Expect(obj["featureGates"]).To(HaveKeyWithValue("NodeSwap", true))
+			Expect(obj["featureGates"]).To(HaveKeyWithValue("RotateKubeletServerCertificate", true))
+			Expect(obj["memorySwap"]).To(HaveKeyWithValue("swapBehavior", api.SwapBehaviorLimited))
+		})
+
+		It("rejects swap when the kubelet of the Kubernetes version doesn't support it", func() {
+			clusterConfig.Metadata.Version = api.Version1_15
+			ng.MemoryConfig = &api.NodeGroupMemoryConfig{
+				Swap: &api.NodeGroupSwap{Size: "4Gi"},
+			}
+			_, err := makeKubeletConfigYAML(clusterConfig, ng)
+			Expect(err).To(MatchError(`kubelet config field "memorySwap" requires Kubernetes 1.22 or newer, the nodegroup uses 1.15`))
+		})
+
+		It("rejects kubelet config fields that are newer than the Kubernetes version", func() {
+			clusterConfig.Metadata.Version = api.Version1_13
+			ng.KubeletExtraConfig = &api.InlineDocument{
+				"configMapAndSecretChangeDetectionStrategy": "Watch",
+			}
+			_, err := makeKubeletConfigYAML(clusterConfig, ng)
+			Expect(err).To(MatchError(ContainSubstring(`"configMapAndSecretChangeDetectionStrategy" requires Kubernetes 1.14 or newer`)))
+
+			clusterConfig.Metadata.Version = api.Version1_14
+			_, err = makeKubeletConfigYAML(clusterConfig, ng)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("sets MAX_PODS from maxPods in the kubelet extra config", func() {
+			ng.KubeletExtraConfig = &api.InlineDocument{
+				"maxPods": float64(58),
+			}
+			Expect(makeCommonKubeletEnvParams(clusterConfig, ng)).To(ContainElement("MAX_PODS=58"))
+		})
+
+		It("the kubelet config allows namespaced sysctls that are overridden", func() {
+			ng.OverrideSysctls = map[string]string{
+				"net.core.somaxconn": "1024",
+				"vm.max_map_count":   "262144",
+			}
+			data, err := makeKubeletConfigYAML(clusterConfig, ng)
+			Expect(err).ToNot(HaveOccurred())
+
+			kubelet := &kubeletapi.KubeletConfiguration{}
+			Expect(yaml.UnmarshalStrict(data, kubelet)).To(Succeed())
+			Expect(kubelet.AllowedUnsafeSysctls).To(Equal([]string{"net.core.somaxconn"}))
+		})
+	})
+
+	Describe("configuring memory", func() {
+		It("creates no commands by default", func() {
+			commands, err := makeMemoryConfigCommands(&api.NodeGroup{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commands).To(BeEmpty())
+		})
+
+		It("creates swap and huge pages commands", func() {
+			commands, err := makeMemoryConfigCommands(&api.NodeGroup{
+				MemoryConfig: &api.NodeGroupMemoryConfig{
+					Swap: &api.NodeGroupSwap{Size: "1Gi"},
+					HugePages: map[string]int{
+						api.HugePageSize2Mi: 512,
+						api.HugePageSize1Gi: 2,
+					},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commands).To(HaveLen(3))
+			Expect(commands[0]).To(HavePrefix("fallocate -l 1024M /swapfile"))
+			Expect(commands[1]).To(ContainSubstring("echo 2 > /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages"))
+			Expect(commands[2]).To(ContainSubstring("vm.nr_hugepages = 512"))
+		})
+	})
+
+	Describe("configuring the kernel", func() {
+		ng := &api.NodeGroup{
+			OverrideSysctls: map[string]string{
+				"net.ipv4.ip_local_port_range": "1024 65535",
+				"net.core.somaxconn":           "1024",
+			},
+			KernelModules: []string{"br_netfilter", "ip_vs"},
+		}
+
+		It("loads modules before applying sysctls", func() {
+			Expect(makeKernelConfigCommands(ng)).To(Equal([]string{
+				"modprobe -a br_netfilter ip_vs",
+				"sysctl -p /etc/sysctl.d/91-eksctl.conf",
+			}))
+		})
+
+		It("persists modules and sysctls in drop-in files", func() {
+			files := configFiles{}
+			addKernelConfigFiles(files, ng)
+			Expect(files["/etc/modules-load.d/"]["eksctl.conf"].content).To(Equal("br_netfilter\nip_vs\n"))
+			Expect(files["/etc/sysctl.d/"]["91-eksctl.conf"].content).To(Equal("net.core.somaxconn = 1024\nnet.ipv4.ip_local_port_range = 1024 65535\n"))
+		})
+
+		It("creates nothing by default", func() {
+			files := configFiles{}
+			addKernelConfigFiles(files, &api.NodeGroup{})
+			Expect(files).To(BeEmpty())
+			Expect(makeKernelConfigCommands(&api.NodeGroup{})).To(BeEmpty())
+		})
+	})
+
+	Describe("configuring the instance store", func() {
+		It("mounts the RAID 0 array of the instance store volumes", func() {
+			ng := &api.NodeGroup{
+				InstanceStore: &api.NodeGroupInstanceStore{
+					RAID0:     api.Enabled(),
+					MountPath: "/var/lib/docker",
+				},
+			}
+			files := configFiles{}
+			addInstanceStoreFiles(files, ng)
+			script := files["/etc/eksctl/"]["setup-instance-store.sh"].content
+			Expect(script).To(HavePrefix("#!/bin/bash\n"))
+			Expect(script).To(ContainSubstring(`MOUNT_PATH="/var/lib/docker"`))
+			Expect(script).To(ContainSubstring(`RAID0="true"`))
+			Expect(makeInstanceStoreCommands(ng)).To(Equal([]string{"bash /etc/eksctl/setup-instance-store.sh"}))
+		})
+
+		It("creates nothing by default", func() {
+			files := configFiles{}
+			addInstanceStoreFiles(files, &api.NodeGroup{})
+			Expect(files).To(BeEmpty())
+			Expect(makeInstanceStoreCommands(&api.NodeGroup{})).To(BeEmpty())
+		})
+	})
+
+	Describe("configuring containerd", func() {
+		containerd := api.ContainerRuntimeContainerd
+
+		It("configures containerd and kubelet to use it", func() {
+			ng := &api.NodeGroup{
+				ContainerRuntime: &containerd,
+			}
+			files := configFiles{}
+			addContainerdFiles(files, &api.ClusterConfig{}, ng)
+			Expect(files["/etc/containerd/"]["config.toml"].content).To(ContainSubstring(`imports = ["/etc/containerd/config.d/*.toml"]`))
+			Expect(files["/etc/containerd/"]["config.toml"].content).To(ContainSubstring(`sandbox_image = "public.ecr.aws/eks-distro/kubernetes/pause:3.2"`))
+			Expect(files).ToNot(HaveKey("/etc/containerd/config.d/"))
+			Expect(makeContainerdCommands(ng)).To(Equal([]string{"systemctl enable containerd", "systemctl restart containerd"}))
+
+			dropInUnit, err := setContainerRuntimeFlags("  --container-runtime=docker \\\n", "--%s", ng)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dropInUnit).To(Equal("  --container-runtime=remote --container-runtime-endpoint=unix:///run/containerd/containerd.sock \\\n"))
+
+			script, err := setContainerRuntimeFlags(`    "container-runtime=docker"`, "%q", ng)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(script).To(Equal(`    "container-runtime=remote" "container-runtime-endpoint=unix:///run/containerd/containerd.sock"`))
+		})
+
+		It("drops the sandbox image in config.d", func() {
+			ng := &api.NodeGroup{
+				ContainerRuntime: &containerd,
+				ContainerdConfig: &api.NodeGroupContainerdConfig{
+					SandboxImage: "registry.example.com/pause:3.2",
+				},
+			}
+			files := configFiles{}
+			addContainerdFiles(files, &api.ClusterConfig{}, ng)
+			config := files["/etc/containerd/config.d/"]["10-eksctl.toml"].content
+			Expect(config).To(ContainSubstring(`sandbox_image = "registry.example.com/pause:3.2"`))
+			Expect(config).To(ContainSubstring(`bin_dir = "/opt/cni/bin"`))
+		})
+
+		It("writes a hosts file per registry mirrored by the cluster or the nodegroup", func() {
+			spec := &api.ClusterConfig{
+				RegistryMirrors: map[string][]string{
+					"docker.io": {"https://artifactory.example.com"},
+					"quay.io":   {"https://artifactory.example.com"},
+				},
+			}
+			ng := &api.NodeGroup{
+				ContainerRuntime: &containerd,
+				ContainerdConfig: &api.NodeGroupContainerdConfig{
+					RegistryMirrors: map[string][]string{
+						"docker.io": {"https://mirror-1.example.com", "https://mirror-2.example.com"},
+					},
+				},
+			}
+			files := configFiles{}
+			addContainerdFiles(files, spec, ng)
+			Expect(files["/etc/containerd/"]["config.toml"].content).To(ContainSubstring(`config_path = "/etc/containerd/certs.d"`))
+			Expect(files).ToNot(HaveKey("/etc/containerd/config.d/"))
+			Expect(files["/etc/containerd/certs.d/docker.io/"]["hosts.toml"].content).To(Equal(`[host."https://mirror-1.example.com"]
+capabilities = ["pull", "resolve"]
+
+[host."https://mirror-2.example.com"]
+capabilities = ["pull", "resolve"]
+`))
+			Expect(files["/etc/containerd/certs.d/quay.io/"]["hosts.toml"].content).To(ContainSubstring(`[host."https://artifactory.example.com"]`))
+		})
+
+		It("keeps docker by default", func() {
+			files := configFiles{}
+			addContainerdFiles(files, &api.ClusterConfig{}, &api.NodeGroup{})
+			Expect(files).To(BeEmpty())
+			Expect(makeContainerdCommands(&api.NodeGroup{})).To(BeEmpty())
+
+			data, err := setContainerRuntimeFlags("--container-runtime=docker", "--%s", &api.NodeGroup{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(Equal("--container-runtime=docker"))
+		})
+	})
+
+	Describe("configuring EFA", func() {
+		It("installs the EFA software without the GPU tests", func() {
+			commands := makeEFACommands(&api.NodeGroup{EFAEnabled: api.Enabled()})
+			Expect(commands).To(HaveLen(4))
+			Expect(commands[0]).To(HaveSuffix(" https://efa-installer.amazonaws.com/aws-efa-installer-latest.tar.gz"))
+			Expect(commands[2]).To(ContainSubstring("./efa_installer.sh -y -g"))
+		})
+
+		It("creates nothing by default", func() {
+			Expect(makeEFACommands(&api.NodeGroup{})).To(BeEmpty())
+		})
+	})
+
+	Describe("configuring warm pools", func() {
+		It("bootstraps the node once its instance leaves the warm pool", func() {
+			files := configFiles{}
+			addWarmPoolFiles(files, &api.NodeGroup{WarmPool: &api.NodeGroupWarmPool{}})
+			Expect(files[configDir]).To(HaveKeyWithValue("bootstrap.al2.sh", configFile{isAsset: true}))
+			Expect(files[configDir][warmPoolWaitScript].content).To(ContainSubstring("meta-data/autoscaling/target-lifecycle-state"))
+			unit := files[systemdUnitDir][warmPoolBootstrapUnit].content
+			Expect(unit).To(ContainSubstring("ExecStartPre=/bin/bash /etc/eksctl/wait-in-service.sh\n"))
+			Expect(unit).To(ContainSubstring("ExecStart=/bin/bash /etc/eksctl/bootstrap.al2.sh\n"))
+
+			commands := makeWarmPoolCommands(&api.NodeGroup{WarmPool: &api.NodeGroupWarmPool{}})
+			Expect(commands).To(ContainElement("systemctl start --no-block eksctl-bootstrap.service"))
+		})
+
+		It("creates nothing by default", func() {
+			files := configFiles{}
+			addWarmPoolFiles(files, &api.NodeGroup{})
+			Expect(files).To(BeEmpty())
+			Expect(makeWarmPoolCommands(&api.NodeGroup{})).To(BeEmpty())
+		})
+	})
+
+	Describe("reading user data", func() {
+		var clusterConfig *api.ClusterConfig
+
+		BeforeEach(func() {
+			clusterConfig = api.NewClusterConfig()
+			clusterConfig.Metadata.Name = "unit-test"
+			clusterConfig.Status = &api.ClusterStatus{
+				Endpoint:                 "unit-test.example.com",
+				CertificateAuthorityData: []byte(`CertificateAuthorityData`),
+			}
+		})
+
+		It("reads back the settings of the nodegroup from its cloud config", func() {
+			ng := &api.NodeGroup{
+				Name:                 "ng-1",
+				AMIFamily:            api.NodeImageFamilyAmazonLinux2,
+				InstanceType:         "m5.large",
+				Labels:               map[string]string{"role": "workers"},
+				Taints:               map[string]string{"dedicated": "workers:NoSchedule"},
+				ClusterDNS:           "169.254.20.10",
+				PreBootstrapCommands: []string{"echo one", "echo two"},
+				KubeletExtraConfig:   &api.InlineDocument{"kubeAPIBurst": float64(20)},
+			}
+			userData, err := NewUserDataForAmazonLinux2(clusterConfig, ng)
+			Expect(err).NotTo(HaveOccurred())
+
+			loaded := &api.NodeGroup{Name: ng.Name, AMIFamily: ng.AMIFamily, InstanceType: ng.InstanceType}
+			Expect(SetNodeGroupFromUserData(clusterConfig, loaded, userData)).To(Succeed())
+			Expect(loaded.Labels).To(Equal(ng.Labels))
+			Expect(loaded.Taints).To(Equal(ng.Taints))
+			Expect(loaded.ClusterDNS).To(Equal(ng.ClusterDNS))
+			Expect(loaded.PreBootstrapCommands).To(Equal(ng.PreBootstrapCommands))
+			Expect(loaded.OverrideBootstrapCommand).To(BeNil())
+			Expect(loaded.KubeletExtraConfig).To(Equal(ng.KubeletExtraConfig))
+		})
+
+		It("reads back the command overriding the bootstrap script", func() {
+			ng := &api.NodeGroup{
+				Name:                     "ng-1",
+				AMIFamily:                api.NodeImageFamilyUbuntu1804,
+				InstanceType:             "m5.large",
+				OverrideBootstrapCommand: aws.String("/etc/eks/bootstrap.sh unit-test"),
+			}
+			userData, err := NewUserDataForUbuntu1804(clusterConfig, ng)
+			Expect(err).NotTo(HaveOccurred())
+
+			loaded := &api.NodeGroup{Name: ng.Name, AMIFamily: ng.AMIFamily, InstanceType: ng.InstanceType}
+			Expect(SetNodeGroupFromUserData(clusterConfig, loaded, userData)).To(Succeed())
+			Expect(loaded.PreBootstrapCommands).To(BeEmpty())
+			Expect(loaded.OverrideBootstrapCommand).To(Equal(ng.OverrideBootstrapCommand))
+			Expect(loaded.Labels).To(BeNil())
+			Expect(loaded.KubeletExtraConfig).To(BeNil())
+		})
+
+		It("reads back the pre-bootstrap commands run before the command overriding the bootstrap script", func() {
+			ng := &api.NodeGroup{
+				Name:                     "ng-1",
+				AMIFamily:                api.NodeImageFamilyUbuntu1804,
+				InstanceType:             "m5.large",
+				PreBootstrapCommands:     []string{"echo one", "echo two\necho three\n"},
+				OverrideBootstrapCommand: aws.String("/etc/eks/bootstrap.sh unit-test"),
+			}
+			userData, err := NewUserDataForUbuntu1804(clusterConfig, ng)
+			Expect(err).NotTo(HaveOccurred())
+
+			loaded := &api.NodeGroup{Name: ng.Name, AMIFamily: ng.AMIFamily, InstanceType: ng.InstanceType}
+			Expect(SetNodeGroupFromUserData(clusterConfig, loaded, userData)).To(Succeed())
+			Expect(loaded.PreBootstrapCommands).To(Equal(ng.PreBootstrapCommands))
+			Expect(loaded.OverrideBootstrapCommand).To(Equal(ng.OverrideBootstrapCommand))
+		})
 	})
 })
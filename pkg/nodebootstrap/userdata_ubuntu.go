@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 )
 
@@ -56,12 +56,41 @@ func NewUserDataForUbuntu1804(spec *api.ClusterConfig, ng *api.NodeGroup) (strin
 
 	scripts := []string{}
 
-	for _, command := range ng.PreBootstrapCommands {
+	addKernelConfigFiles(files, ng)
+	for _, command := range makeKernelConfigCommands(ng) {
 		config.AddShellCommand(command)
 	}
 
+	memoryCommands, err := makeMemoryConfigCommands(ng)
+	if err != nil {
+		return "", err
+	}
+	for _, command := range memoryCommands {
+		config.AddShellCommand(command)
+	}
+
+	addInstanceStoreFiles(files, ng)
+	for _, command := range makeInstanceStoreCommands(ng) {
+		config.AddShellCommand(command)
+	}
+
+	addContainerdFiles(files, spec, ng)
+	for _, command := range makeContainerdCommands(ng) {
+		config.AddShellCommand(command)
+	}
+
+	// the kubelet flags are set by the bootstrap script, so it's only run as it
+	// is with the default container runtime
+	var bootstrapScript string
 	if ng.OverrideBootstrapCommand != nil {
 		config.AddShellCommand(*ng.OverrideBootstrapCommand)
+	} else if usesContainerd(ng) {
+		if bootstrapScript, err = getAsset("bootstrap.ubuntu.sh"); err != nil {
+			return "", err
+		}
+		if bootstrapScript, err = setContainerRuntimeFlags(bootstrapScript, "%q", ng); err != nil {
+			return "", errors.Wrap(err, "setting container runtime of kubelet")
+		}
 	} else {
 		scripts = append(scripts, "bootstrap.ubuntu.sh")
 	}
@@ -69,8 +98,11 @@ func NewUserDataForUbuntu1804(spec *api.ClusterConfig, ng *api.NodeGroup) (strin
 	if err = addFilesAndScripts(config, files, scripts); err != nil {
 		return "", err
 	}
+	if bootstrapScript != "" {
+		config.RunScript("bootstrap.ubuntu.sh", bootstrapScript)
+	}
 
-	body, err := config.Encode()
+	body, err := encodeCloudConfig(config, ng)
 	if err != nil {
 		return "", errors.Wrap(err, "encoding user data")
 	}
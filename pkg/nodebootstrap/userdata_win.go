@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/kris-nova/logger"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func newUserDataForWindows(spec *api.ClusterConfig, ng *api.NodeGroup) (string, error) {
@@ -18,8 +18,8 @@ func newUserDataForWindows(spec *api.ClusterConfig, ng *api.NodeGroup) (string,
 		"node-labels":          kvs(ng.Labels),
 		"register-with-taints": kvs(ng.Taints),
 	}
-	if ng.MaxPodsPerNode != 0 {
-		kubeletOptions["max-pods"] = strconv.Itoa(ng.MaxPodsPerNode)
+	if ng.MaxPodsPerNode > 0 {
+		kubeletOptions["max-pods"] = strconv.Itoa(int(ng.MaxPodsPerNode))
 	}
 
 	kubeletArgs := toCLIArgs(kubeletOptions)
@@ -0,0 +1,76 @@
+package nodebootstrap
+
+import (
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	systemdUnitDir        = "/etc/systemd/system/"
+	warmPoolBootstrapUnit = "eksctl-bootstrap.service"
+	warmPoolWaitScript    = "wait-in-service.sh"
+)
+
+// warmPoolWaitScriptBody waits for the instance to leave the warm pool, the target lifecycle
+// state being Warmed:* while it's prepared for the warm pool
+const warmPoolWaitScriptBody = `#!/bin/bash
+
+set -o errexit
+set -o pipefail
+set -o nounset
+
+while true; do
+  token="$(curl --silent --request PUT --header "X-aws-ec2-metadata-token-ttl-seconds: 60" http://169.254.169.254/latest/api/token)"
+  state="$(curl --silent --header "X-aws-ec2-metadata-token: ${token}" http://169.254.169.254/latest/meta-data/autoscaling/target-lifecycle-state || true)"
+  if [[ "${state}" == "InService" ]]; then
+    exit 0
+  fi
+  sleep 5
+done
+`
+
+// warmPoolBootstrapUnitBody runs the bootstrap script once the instance is in service, on every
+// boot since instances of stopped and hibernated warm pools are started again when they leave it
+const warmPoolBootstrapUnitBody = `[Unit]
+Description=Bootstrap the node once its instance leaves the warm pool
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+TimeoutStartSec=infinity
+ExecStartPre=/bin/bash ` + configDir + warmPoolWaitScript + `
+ExecStart=/bin/bash ` + configDir + `bootstrap.al2.sh
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// addWarmPoolFiles adds the bootstrap script and the systemd unit running it once the instance
+// is in service, so that instances don't join the cluster while they're prepared for the warm pool
+func addWarmPoolFiles(files configFiles, ng *api.NodeGroup) {
+	if ng.WarmPool == nil {
+		return
+	}
+	if files[configDir] == nil {
+		files[configDir] = map[string]configFile{}
+	}
+	files[configDir]["bootstrap.al2.sh"] = configFile{isAsset: true}
+	files[configDir][warmPoolWaitScript] = configFile{content: warmPoolWaitScriptBody}
+	files[systemdUnitDir] = map[string]configFile{
+		warmPoolBootstrapUnit: {content: warmPoolBootstrapUnitBody},
+	}
+}
+
+// makeWarmPoolCommands returns the shell commands that start the bootstrap unit without
+// waiting for it, so that cloud-init completes and the instance can enter the warm pool
+func makeWarmPoolCommands(ng *api.NodeGroup) []string {
+	if ng.WarmPool == nil {
+		return nil
+	}
+	return []string{
+		"systemctl daemon-reload",
+		"systemctl enable " + warmPoolBootstrapUnit,
+		"systemctl start --no-block " + warmPoolBootstrapUnit,
+	}
+}
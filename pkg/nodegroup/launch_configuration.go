@@ -0,0 +1,150 @@
+package nodegroup
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// LaunchConfiguration is the configuration that the instances of a nodegroup are launched with
+type LaunchConfiguration struct {
+	// LaunchTemplate is not set for managed nodegroups that don't use a launch template
+	LaunchTemplate *LaunchTemplateVersion `json:"launchTemplate,omitempty"`
+	// AutoScalingGroup holds the properties of the Auto Scaling group of self-managed
+	// nodegroups, as they're set in the template of their stack
+	AutoScalingGroup map[string]interface{} `json:"autoScalingGroup,omitempty"`
+	// ManagedNodeGroup holds the properties of managed nodegroups, as they're set
+	// in the template of their stack
+	ManagedNodeGroup map[string]interface{} `json:"managedNodeGroup,omitempty"`
+}
+
+// LaunchTemplateVersion is a version of an EC2 launch template
+type LaunchTemplateVersion struct {
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+	// Data is the effective launch template data, as returned by EC2
+	Data map[string]interface{} `json:"data"`
+}
+
+// GetLaunchConfiguration returns the launch template data and the Auto Scaling group
+// settings that eksctl generated for a nodegroup
+func GetLaunchConfiguration(provider api.ClusterProvider, stackManager *manager.StackCollection, nodeGroupName string) (*LaunchConfiguration, error) {
+	nodeGroupType, err := stackManager.GetNodeGroupStackType(nodeGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		template string
+		config   = &LaunchConfiguration{}
+	)
+	switch nodeGroupType {
+	case api.NodeGroupTypeManaged:
+		template, err = stackManager.GetManagedNodeGroupTemplate(nodeGroupName)
+	default:
+		template, err = stackManager.GetNodeGroupTemplate(nodeGroupName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		launchTemplateID      string
+		launchTemplateVersion = "$Latest"
+	)
+	for logicalID, resource := range gjson.Get(template, "Resources").Map() {
+		properties := resource.Get("Properties")
+		switch resource.Get("Type").String() {
+		case "AWS::EC2::LaunchTemplate":
+			if launchTemplateID, err = stackManager.GetNodeGroupStackResourceID(nodeGroupName, logicalID); err != nil {
+				return nil, err
+			}
+		case "AWS::AutoScaling::AutoScalingGroup":
+			if config.AutoScalingGroup, err = toMap(properties.Value()); err != nil {
+				return nil, err
+			}
+		case "AWS::EKS::Nodegroup":
+			if config.ManagedNodeGroup, err = toMap(properties.Value()); err != nil {
+				return nil, err
+			}
+			// launch templates created outside of eksctl are referenced by their ID
+			if id := properties.Get("LaunchTemplate.Id"); id.Type == gjson.String {
+				launchTemplateID = id.String()
+				launchTemplateVersion = "$Default"
+				if version := properties.Get("LaunchTemplate.Version"); version.Type == gjson.String {
+					launchTemplateVersion = version.String()
+				}
+			}
+		}
+	}
+
+	if launchTemplateID != "" {
+		if config.LaunchTemplate, err = describeLaunchTemplateVersion(provider, launchTemplateID, launchTemplateVersion); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+func describeLaunchTemplateVersion(provider api.ClusterProvider, id, version string) (*LaunchTemplateVersion, error) {
+	output, err := provider.EC2().DescribeLaunchTemplateVersions(&ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: &id,
+		Versions:         aws.StringSlice([]string{version}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing launch template %q", id)
+	}
+	if len(output.LaunchTemplateVersions) == 0 {
+		return nil, fmt.Errorf("version %q of launch template %q not found", version, id)
+	}
+
+	launchTemplateVersion := output.LaunchTemplateVersions[0]
+	data, err := toMap(launchTemplateVersion.LaunchTemplateData)
+	if err != nil {
+		return nil, err
+	}
+	return &LaunchTemplateVersion{
+		ID:      id,
+		Version: *launchTemplateVersion.VersionNumber,
+		Data:    data,
+	}, nil
+}
+
+// toMap converts v to a map through its JSON representation, without the fields that are
+// not set, as the fields of the AWS SDK are pointers that are serialised as null otherwise
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	removeNulls(m)
+	return m, nil
+}
+
+func removeNulls(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value == nil {
+				delete(v, key)
+			} else {
+				removeNulls(value)
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			removeNulls(value)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package nodegroup
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("GetLaunchConfiguration", func() {
+	const stackName = "eksctl-test-cluster-nodegroup-ng-1"
+
+	var (
+		p            *mockprovider.MockProvider
+		stackManager *manager.StackCollection
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		stackManager = manager.NewStackCollection(p, cfg)
+
+		p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+			return *input.StackName == stackName
+		})).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName: aws.String(stackName),
+				Tags: []*cfn.Tag{{
+					Key:   aws.String(api.NodeGroupNameTag),
+					Value: aws.String("ng-1"),
+				}},
+			}},
+		}, nil)
+		p.MockCloudFormation().On("GetTemplate", mock.Anything).Return(&cfn.GetTemplateOutput{
+			TemplateBody: aws.String(`{
+				"Resources": {
+					"NodeGroupLaunchTemplate": {
+						"Type": "AWS::EC2::LaunchTemplate",
+						"Properties": {"LaunchTemplateData": {"InstanceType": "m5.large"}}
+					},
+					"NodeGroup": {
+						"Type": "AWS::AutoScaling::AutoScalingGroup",
+						"Properties": {"MinSize": "1", "MaxSize": "3"}
+					}
+				}
+			}`),
+		}, nil)
+		p.MockCloudFormation().On("DescribeStackResource", mock.MatchedBy(func(input *cfn.DescribeStackResourceInput) bool {
+			return *input.LogicalResourceId == "NodeGroupLaunchTemplate"
+		})).Return(&cfn.DescribeStackResourceOutput{
+			StackResourceDetail: &cfn.StackResourceDetail{
+				PhysicalResourceId: aws.String("lt-1234"),
+			},
+		}, nil)
+		p.MockEC2().On("DescribeLaunchTemplateVersions", mock.MatchedBy(func(input *ec2.DescribeLaunchTemplateVersionsInput) bool {
+			return *input.LaunchTemplateId == "lt-1234" && *input.Versions[0] == "$Latest"
+		})).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{
+				VersionNumber: aws.Int64(2),
+				LaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+					InstanceType: aws.String("m5.large"),
+					ImageId:      aws.String("ami-1234"),
+				},
+			}},
+		}, nil)
+	})
+
+	It("should return the effective launch template and the Auto Scaling group settings", func() {
+		config, err := GetLaunchConfiguration(p, stackManager, "ng-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.LaunchTemplate.ID).To(Equal("lt-1234"))
+		Expect(config.LaunchTemplate.Version).To(Equal(int64(2)))
+		Expect(config.LaunchTemplate.Data).To(Equal(map[string]interface{}{
+			"InstanceType": "m5.large",
+			"ImageId":      "ami-1234",
+		}))
+		Expect(config.AutoScalingGroup).To(Equal(map[string]interface{}{
+			"MinSize": "1",
+			"MaxSize": "3",
+		}))
+		Expect(config.ManagedNodeGroup).To(BeNil())
+	})
+})
@@ -0,0 +1,125 @@
+package nodegroup
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// maxDeletedLaunchTemplateVersions is the maximum number of versions of a single
+// DeleteLaunchTemplateVersions call
+const maxDeletedLaunchTemplateVersions = 200
+
+// SupersededLaunchTemplateVersions returns the ID of the launch template that the stack of a
+// nodegroup created, and its versions that were superseded by updates of the nodegroup, i.e. all
+// but the last keep ones and the default one; the ID is empty if the stack has no launch template
+func SupersededLaunchTemplateVersions(provider api.ClusterProvider, stackManager *manager.StackCollection, nodeGroupName string, keep int) (string, []int64, error) {
+	if keep < 1 {
+		return "", nil, fmt.Errorf("at least one launch template version must be kept, got %d", keep)
+	}
+
+	launchTemplateID, err := getStackLaunchTemplateID(stackManager, nodeGroupName)
+	if err != nil || launchTemplateID == "" {
+		return "", nil, err
+	}
+
+	var versions []*ec2.LaunchTemplateVersion
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: &launchTemplateID,
+	}
+	for {
+		output, err := provider.EC2().DescribeLaunchTemplateVersions(input)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "describing versions of launch template %q", launchTemplateID)
+		}
+		versions = append(versions, output.LaunchTemplateVersions...)
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	// the nodegroup uses the latest version, which is the first one that is kept
+	sort.Slice(versions, func(i, j int) bool {
+		return *versions[i].VersionNumber > *versions[j].VersionNumber
+	})
+	var superseded []int64
+	for i, version := range versions {
+		if i < keep || aws.BoolValue(version.DefaultVersion) {
+			continue
+		}
+		superseded = append(superseded, *version.VersionNumber)
+	}
+	return launchTemplateID, superseded, nil
+}
+
+// DeleteLaunchTemplateVersions deletes versions of a launch template, the versions that
+// can't be deleted are logged and reported in the returned error
+func DeleteLaunchTemplateVersions(provider api.ClusterProvider, launchTemplateID string, versions []int64) error {
+	var failed int
+	for start := 0; start < len(versions); start += maxDeletedLaunchTemplateVersions {
+		end := start + maxDeletedLaunchTemplateVersions
+		if end > len(versions) {
+			end = len(versions)
+		}
+		batch := make([]string, 0, end-start)
+		for _, version := range versions[start:end] {
+			batch = append(batch, strconv.FormatInt(version, 10))
+		}
+
+		output, err := provider.EC2().DeleteLaunchTemplateVersions(&ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: &launchTemplateID,
+			Versions:         aws.StringSlice(batch),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "deleting versions of launch template %q", launchTemplateID)
+		}
+		for _, item := range output.UnsuccessfullyDeletedLaunchTemplateVersions {
+			failed++
+			if item.ResponseError != nil {
+				logger.Warning("failed to delete version %d of launch template %q: %s", aws.Int64Value(item.VersionNumber), launchTemplateID, aws.StringValue(item.ResponseError.Message))
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d versions of launch template %q", failed, len(versions), launchTemplateID)
+	}
+	return nil
+}
+
+// getStackLaunchTemplateID returns the ID of the launch template created by the stack of a nodegroup,
+// launch templates created outside of eksctl and referenced by managed nodegroups are ignored
+func getStackLaunchTemplateID(stackManager *manager.StackCollection, nodeGroupName string) (string, error) {
+	nodeGroupType, err := stackManager.GetNodeGroupStackType(nodeGroupName)
+	if err != nil {
+		return "", err
+	}
+
+	var template string
+	switch nodeGroupType {
+	case api.NodeGroupTypeManaged:
+		template, err = stackManager.GetManagedNodeGroupTemplate(nodeGroupName)
+	default:
+		template, err = stackManager.GetNodeGroupTemplate(nodeGroupName)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for logicalID, resource := range gjson.Get(template, "Resources").Map() {
+		if resource.Get("Type").String() == "AWS::EC2::LaunchTemplate" {
+			return stackManager.GetNodeGroupStackResourceID(nodeGroupName, logicalID)
+		}
+	}
+	return "", nil
+}
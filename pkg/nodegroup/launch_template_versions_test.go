@@ -0,0 +1,100 @@
+package nodegroup
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Launch template versions", func() {
+	const stackName = "eksctl-test-cluster-nodegroup-ng-1"
+
+	var (
+		p            *mockprovider.MockProvider
+		stackManager *manager.StackCollection
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		stackManager = manager.NewStackCollection(p, cfg)
+
+		p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+			return *input.StackName == stackName
+		})).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName: aws.String(stackName),
+				Tags: []*cfn.Tag{{
+					Key:   aws.String(api.NodeGroupNameTag),
+					Value: aws.String("ng-1"),
+				}},
+			}},
+		}, nil)
+		p.MockCloudFormation().On("GetTemplate", mock.Anything).Return(&cfn.GetTemplateOutput{
+			TemplateBody: aws.String(`{
+				"Resources": {
+					"NodeGroupLaunchTemplate": {"Type": "AWS::EC2::LaunchTemplate"},
+					"NodeGroup": {"Type": "AWS::AutoScaling::AutoScalingGroup"}
+				}
+			}`),
+		}, nil)
+		p.MockCloudFormation().On("DescribeStackResource", mock.Anything).Return(&cfn.DescribeStackResourceOutput{
+			StackResourceDetail: &cfn.StackResourceDetail{
+				PhysicalResourceId: aws.String("lt-1234"),
+			},
+		}, nil)
+
+		page := func(defaultVersion int64, versions ...int64) *ec2.DescribeLaunchTemplateVersionsOutput {
+			output := &ec2.DescribeLaunchTemplateVersionsOutput{}
+			for _, version := range versions {
+				output.LaunchTemplateVersions = append(output.LaunchTemplateVersions, &ec2.LaunchTemplateVersion{
+					VersionNumber:  aws.Int64(version),
+					DefaultVersion: aws.Bool(version == defaultVersion),
+				})
+			}
+			return output
+		}
+		firstPage := page(1, 6, 5, 4)
+		firstPage.NextToken = aws.String("next")
+		p.MockEC2().On("DescribeLaunchTemplateVersions", mock.MatchedBy(func(input *ec2.DescribeLaunchTemplateVersionsInput) bool {
+			return *input.LaunchTemplateId == "lt-1234" && input.NextToken == nil
+		})).Return(firstPage, nil)
+		p.MockEC2().On("DescribeLaunchTemplateVersions", mock.MatchedBy(func(input *ec2.DescribeLaunchTemplateVersionsInput) bool {
+			return *input.LaunchTemplateId == "lt-1234" && aws.StringValue(input.NextToken) == "next"
+		})).Return(page(1, 3, 2, 1), nil)
+	})
+
+	It("returns the versions before the last ones, except the default one", func() {
+		id, versions, err := SupersededLaunchTemplateVersions(p, stackManager, "ng-1", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("lt-1234"))
+		Expect(versions).To(Equal([]int64{4, 3, 2}))
+	})
+
+	It("requires to keep at least one version", func() {
+		_, _, err := SupersededLaunchTemplateVersions(p, stackManager, "ng-1", 0)
+		Expect(err).To(MatchError("at least one launch template version must be kept, got 0"))
+	})
+
+	It("reports the versions that weren't deleted", func() {
+		p.MockEC2().On("DeleteLaunchTemplateVersions", mock.MatchedBy(func(input *ec2.DeleteLaunchTemplateVersionsInput) bool {
+			return *input.LaunchTemplateId == "lt-1234" && len(input.Versions) == 3 && *input.Versions[0] == "4"
+		})).Return(&ec2.DeleteLaunchTemplateVersionsOutput{
+			UnsuccessfullyDeletedLaunchTemplateVersions: []*ec2.DeleteLaunchTemplateVersionsResponseErrorItem{{
+				VersionNumber: aws.Int64(3),
+				ResponseError: &ec2.ResponseError{Message: aws.String("in use")},
+			}},
+		}, nil)
+
+		err := DeleteLaunchTemplateVersions(p, "lt-1234", []int64{4, 3, 2})
+		Expect(err).To(MatchError(`failed to delete 1 of 3 versions of launch template "lt-1234"`))
+	})
+})
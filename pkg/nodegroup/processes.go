@@ -0,0 +1,75 @@
+package nodegroup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// autoScalingGroupLogicalID is the logical ID of the Auto Scaling group in the stack of self-managed nodegroups
+const autoScalingGroupLogicalID = "NodeGroup"
+
+// SuspendProcesses suspends processes of the Auto Scaling group of a self-managed nodegroup
+func SuspendProcesses(provider api.ClusterProvider, stackManager *manager.StackCollection, nodeGroupName string, processes []string) error {
+	asgName, err := getAutoScalingGroupName(stackManager, nodeGroupName)
+	if err != nil {
+		return err
+	}
+	if _, err := provider.ASG().SuspendProcesses(&autoscaling.ScalingProcessQuery{
+		AutoScalingGroupName: &asgName,
+		ScalingProcesses:     aws.StringSlice(processes),
+	}); err != nil {
+		return errors.Wrapf(err, "suspending processes of Auto Scaling group %q", asgName)
+	}
+	logger.Info("suspended %s processes of nodegroup %q", strings.Join(processes, ", "), nodeGroupName)
+	return nil
+}
+
+// ResumeProcesses resumes processes of the Auto Scaling group of a self-managed nodegroup
+func ResumeProcesses(provider api.ClusterProvider, stackManager *manager.StackCollection, nodeGroupName string, processes []string) error {
+	asgName, err := getAutoScalingGroupName(stackManager, nodeGroupName)
+	if err != nil {
+		return err
+	}
+	if _, err := provider.ASG().ResumeProcesses(&autoscaling.ScalingProcessQuery{
+		AutoScalingGroupName: &asgName,
+		ScalingProcesses:     aws.StringSlice(processes),
+	}); err != nil {
+		return errors.Wrapf(err, "resuming processes of Auto Scaling group %q", asgName)
+	}
+	logger.Info("resumed %s processes of nodegroup %q", strings.Join(processes, ", "), nodeGroupName)
+	return nil
+}
+
+// SuspendConfiguredProcesses suspends the processes set in suspendProcesses of nodegroups
+func SuspendConfiguredProcesses(provider api.ClusterProvider, stackManager *manager.StackCollection, nodeGroups []*api.NodeGroup) error {
+	for _, ng := range nodeGroups {
+		if len(ng.SuspendProcesses) == 0 {
+			continue
+		}
+		if err := SuspendProcesses(provider, stackManager, ng.Name, ng.SuspendProcesses); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAutoScalingGroupName returns the name of the Auto Scaling group of a nodegroup, only the
+// ones of self-managed nodegroups are supported as EKS manages the ones of managed nodegroups
+func getAutoScalingGroupName(stackManager *manager.StackCollection, nodeGroupName string) (string, error) {
+	nodeGroupType, err := stackManager.GetNodeGroupStackType(nodeGroupName)
+	if err != nil {
+		return "", err
+	}
+	if nodeGroupType != api.NodeGroupTypeUnmanaged {
+		return "", fmt.Errorf("the processes of managed nodegroup %q can't be suspended, only self-managed nodegroups are supported", nodeGroupName)
+	}
+	return stackManager.GetNodeGroupStackResourceID(nodeGroupName, autoScalingGroupLogicalID)
+}
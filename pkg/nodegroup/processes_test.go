@@ -0,0 +1,75 @@
+package nodegroup
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Auto Scaling processes", func() {
+	const stackName = "eksctl-test-cluster-nodegroup-ng-1"
+
+	var (
+		p            *mockprovider.MockProvider
+		stackManager *manager.StackCollection
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		stackManager = manager.NewStackCollection(p, cfg)
+
+		p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+			return *input.StackName == stackName
+		})).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName: aws.String(stackName),
+				Tags: []*cfn.Tag{{
+					Key:   aws.String(api.NodeGroupNameTag),
+					Value: aws.String("ng-1"),
+				}},
+			}},
+		}, nil)
+		p.MockCloudFormation().On("DescribeStackResource", mock.MatchedBy(func(input *cfn.DescribeStackResourceInput) bool {
+			return *input.StackName == stackName && *input.LogicalResourceId == "NodeGroup"
+		})).Return(&cfn.DescribeStackResourceOutput{
+			StackResourceDetail: &cfn.StackResourceDetail{
+				PhysicalResourceId: aws.String("eksctl-test-cluster-nodegroup-ng-1-NodeGroup-ABCDEF"),
+			},
+		}, nil)
+	})
+
+	matchQuery := func(processes ...string) interface{} {
+		return mock.MatchedBy(func(input *autoscaling.ScalingProcessQuery) bool {
+			return *input.AutoScalingGroupName == "eksctl-test-cluster-nodegroup-ng-1-NodeGroup-ABCDEF" &&
+				reflect.DeepEqual(aws.StringValueSlice(input.ScalingProcesses), processes)
+		})
+	}
+
+	It("suspends the configured processes of nodegroups", func() {
+		p.MockASG().On("SuspendProcesses", matchQuery("AZRebalance", "ReplaceUnhealthy")).Return(&autoscaling.SuspendProcessesOutput{}, nil)
+
+		ng := api.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.SuspendProcesses = []string{"AZRebalance", "ReplaceUnhealthy"}
+		Expect(SuspendConfiguredProcesses(p, stackManager, []*api.NodeGroup{ng, api.NewNodeGroup()})).To(Succeed())
+		p.MockASG().AssertNumberOfCalls(GinkgoT(), "SuspendProcesses", 1)
+	})
+
+	It("resumes processes", func() {
+		p.MockASG().On("ResumeProcesses", matchQuery("AZRebalance")).Return(&autoscaling.ResumeProcessesOutput{}, nil)
+
+		Expect(ResumeProcesses(p, stackManager, "ng-1", []string{"AZRebalance"})).To(Succeed())
+		p.MockASG().AssertExpectations(GinkgoT())
+	})
+})
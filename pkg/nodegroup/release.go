@@ -0,0 +1,64 @@
+package nodegroup
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/weaveworks/eksctl/pkg/ami"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// UpgradeReleaseVersion upgrades a self-managed nodegroup to the EKS-optimized AMI of releaseVersion,
+// e.g. 1.15.10-20200228; the launch template is updated to use the AMI, then the instances
+// are replaced one at a time, after their nodes have been drained
+func UpgradeReleaseVersion(provider api.ClusterProvider, stackManager *manager.StackCollection, clientSet kubernetes.Interface, ng *api.NodeGroup, releaseVersion string) error {
+	template, err := stackManager.GetNodeGroupTemplate(ng.Name)
+	if err != nil {
+		return err
+	}
+
+	currentImageID := gjson.Get(template, launchTemplateDataPath+".ImageId")
+	if currentImageID.Type != gjson.String {
+		return fmt.Errorf("failed to find the AMI of nodegroup %q in its template", ng.Name)
+	}
+
+	imagesOutput, err := provider.EC2().DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{currentImageID.String()}),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "describing image %q", currentImageID.String())
+	}
+	if len(imagesOutput.Images) != 1 {
+		return fmt.Errorf("expected to find exactly 1 image; got %d", len(imagesOutput.Images))
+	}
+
+	imageName, err := ami.ReleaseImageName(*imagesOutput.Images[0].Name, releaseVersion)
+	if err != nil {
+		return err
+	}
+	imageID, err := ami.FindImage(provider.EC2(), api.EKSResourceAccountID(provider.Region()), imageName)
+	if err != nil {
+		return err
+	}
+	if imageID == "" {
+		return ami.NewErrNotFound(imageName)
+	}
+
+	if imageID != currentImageID.String() {
+		logger.Info("updating the launch template of nodegroup %q to use %s (%s)", ng.Name, imageName, imageID)
+		if err := updateLaunchTemplateData(stackManager, ng.Name, template, "ImageId", imageID); err != nil {
+			return err
+		}
+	}
+
+	return ReplaceInstances(provider, clientSet, ng, func(instance *ec2.Instance) bool {
+		return *instance.ImageId != imageID
+	})
+}
@@ -0,0 +1,151 @@
+// Package nodegroup implements operations on the instances of self-managed nodegroups
+package nodegroup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/drain"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// pollInterval is how often nodes are listed while waiting for a replacement to become ready
+const pollInterval = 10 * time.Second
+
+// ReplaceInstances replaces the instances of a nodegroup for which isOutdated returns true, one
+// at a time; the node of each instance is drained and the instance is terminated, the Auto Scaling
+// group then launches a new instance, which must become ready before the next one is replaced
+// TODO: start an EC2 Auto Scaling instance refresh instead, behind an --use-instance-refresh flag, once
+// aws-sdk-go is bumped past the fork eksctl is pinned to, which has no StartInstanceRefresh
+func ReplaceInstances(provider api.ClusterProvider, clientSet kubernetes.Interface, ng eks.KubeNodeGroup, isOutdated func(*ec2.Instance) bool) error {
+	nodes, err := clientSet.CoreV1().Nodes().List(ng.ListOptions())
+	if err != nil {
+		return errors.Wrapf(err, "listing nodes of nodegroup %q", ng.NameString())
+	}
+	if len(nodes.Items) == 0 {
+		logger.Warning("no nodes found in nodegroup %q (label selector: %q)", ng.NameString(), ng.ListOptions().LabelSelector)
+		return nil
+	}
+
+	knownNodes := sets.NewString()
+	instanceNodes := map[string]*corev1.Node{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		knownNodes.Insert(node.Name)
+		if instanceID := instanceIDOf(node); instanceID != "" {
+			instanceNodes[instanceID] = node
+		}
+	}
+
+	outdated, err := findOutdatedInstances(provider, instanceNodes, isOutdated)
+	if err != nil {
+		return err
+	}
+	if len(outdated) == 0 {
+		logger.Info("all instances of nodegroup %q are up-to-date", ng.NameString())
+		return nil
+	}
+
+	logger.Info("replacing %d instance(s) of nodegroup %q", len(outdated), ng.NameString())
+	for _, instanceID := range outdated {
+		node := instanceNodes[instanceID]
+		if err := drain.Node(clientSet, node, provider.Timeout(api.TimeoutPhaseDrain)); err != nil {
+			return err
+		}
+
+		logger.Info("terminating instance %q of node %q", instanceID, node.Name)
+		if _, err := provider.EC2().TerminateInstances(&ec2.TerminateInstancesInput{
+			InstanceIds: aws.StringSlice([]string{instanceID}),
+		}); err != nil {
+			return errors.Wrapf(err, "terminating instance %q", instanceID)
+		}
+
+		replacement, err := waitForReplacement(provider, clientSet, ng, knownNodes)
+		if err != nil {
+			return err
+		}
+		knownNodes.Insert(replacement)
+		logger.Info("node %q replaced by %q", node.Name, replacement)
+	}
+	logger.Success("replaced %d instance(s) of nodegroup %q", len(outdated), ng.NameString())
+	return nil
+}
+
+// findOutdatedInstances returns the IDs of the outdated instances, sorted so
+// that they're replaced in a predictable order
+func findOutdatedInstances(provider api.ClusterProvider, instanceNodes map[string]*corev1.Node, isOutdated func(*ec2.Instance) bool) ([]string, error) {
+	var instanceIDs []string
+	for instanceID := range instanceNodes {
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	if len(instanceIDs) == 0 {
+		return nil, nil
+	}
+
+	output, err := provider.EC2().DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice(instanceIDs),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "describing instances")
+	}
+
+	var outdated []string
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			if isOutdated(instance) {
+				outdated = append(outdated, *instance.InstanceId)
+			}
+		}
+	}
+	sort.Strings(outdated)
+	return outdated, nil
+}
+
+// waitForReplacement waits for a node that isn't one of knownNodes to become ready, and returns its name
+func waitForReplacement(provider api.ClusterProvider, clientSet kubernetes.Interface, ng eks.KubeNodeGroup, knownNodes sets.String) (string, error) {
+	timer := time.NewTimer(provider.Timeout(api.TimeoutPhaseNodeGroup))
+	defer timer.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	logger.Info("waiting for a new node to become ready in %q", ng.NameString())
+	for {
+		nodes, err := clientSet.CoreV1().Nodes().List(ng.ListOptions())
+		if err != nil {
+			return "", errors.Wrapf(err, "listing nodes of nodegroup %q", ng.NameString())
+		}
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			if !knownNodes.Has(node.Name) && eks.IsNodeReady(node) {
+				return node.Name, nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return "", fmt.Errorf("timed out (after %s) waiting for a new node to become ready in %q", provider.Timeout(api.TimeoutPhaseNodeGroup), ng.NameString())
+		}
+	}
+}
+
+// instanceIDOf returns the EC2 instance ID of a node, which is the last
+// part of its provider ID, e.g. aws:///us-west-2a/i-0123456789abcdef0
+func instanceIDOf(node *corev1.Node) string {
+	providerID := node.Spec.ProviderID
+	if !strings.HasPrefix(providerID, "aws://") {
+		return ""
+	}
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
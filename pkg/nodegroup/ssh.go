@@ -0,0 +1,56 @@
+package nodegroup
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/tidwall/gjson"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/ssh/client"
+)
+
+// RotateSSHKey replaces the SSH key of a self-managed nodegroup with the EC2 key pair keyName; the
+// launch template is updated to use the key pair, the instances are replaced one at a time, after
+// their nodes have been drained, and the previous key pair is deleted if eksctl imported it
+func RotateSSHKey(provider api.ClusterProvider, stackManager *manager.StackCollection, clientSet kubernetes.Interface, clusterName string, ng *api.NodeGroup, keyName string) error {
+	template, err := stackManager.GetNodeGroupTemplate(ng.Name)
+	if err != nil {
+		return err
+	}
+
+	currentKeyName := gjson.Get(template, launchTemplateDataPath+".KeyName")
+	if currentKeyName.Type != gjson.String {
+		return fmt.Errorf("SSH access is not enabled for nodegroup %q, it can't be given a new key", ng.Name)
+	}
+
+	if currentKeyName.String() != keyName {
+		logger.Info("updating the launch template of nodegroup %q to use key pair %q", ng.Name, keyName)
+		if err := updateLaunchTemplateData(stackManager, ng.Name, template, "KeyName", keyName); err != nil {
+			return err
+		}
+	}
+
+	if err := ReplaceInstances(provider, clientSet, ng, func(instance *ec2.Instance) bool {
+		return instance.KeyName == nil || *instance.KeyName != keyName
+	}); err != nil {
+		return err
+	}
+
+	if currentKeyName.String() == keyName {
+		return nil
+	}
+	deleted, err := client.DeleteNodeGroupKey(currentKeyName.String(), clusterName, ng.Name, provider.EC2())
+	if err != nil {
+		return err
+	}
+	if deleted {
+		logger.Info("deleted previous key pair %q", currentKeyName.String())
+	} else {
+		logger.Info("previous key pair %q was not imported by eksctl, it's kept", currentKeyName.String())
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package nodegroup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// GetStatus sets the live state of the nodegroups of the summaries, which only hold the settings of
+// their stacks; it's read from the Auto Scaling groups of self-managed nodegroups and from EKS for
+// managed nodegroups
+func GetStatus(provider api.ClusterProvider, stackManager *manager.StackCollection, clusterName string, summaries []*manager.NodeGroupSummary) error {
+	for _, summary := range summaries {
+		var (
+			status *manager.NodeGroupStatus
+			err    error
+		)
+		if summary.Type == api.NodeGroupTypeManaged {
+			status, err = getManagedStatus(provider, clusterName, summary.Name)
+		} else {
+			status, err = getUnmanagedStatus(provider, stackManager, summary)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "getting status of nodegroup %q", summary.Name)
+		}
+		summary.Status = status
+	}
+	return nil
+}
+
+func getUnmanagedStatus(provider api.ClusterProvider, stackManager *manager.StackCollection, summary *manager.NodeGroupSummary) (*manager.NodeGroupStatus, error) {
+	asgName, err := stackManager.GetNodeGroupStackResourceID(summary.Name, autoScalingGroupLogicalID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := describeAutoScalingGroups(provider, []string{asgName})
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("Auto Scaling group %q not found", asgName)
+	}
+	group := groups[0]
+
+	status := &manager.NodeGroupStatus{
+		Status:          "ACTIVE",
+		DesiredCapacity: int(aws.Int64Value(group.DesiredCapacity)),
+		MinSize:         int(aws.Int64Value(group.MinSize)),
+		MaxSize:         int(aws.Int64Value(group.MaxSize)),
+		Nodes:           countInServiceInstances(groups),
+		HealthIssues:    unhealthyInstances(groups),
+		Update:          summary.StackStatus,
+	}
+	// the Auto Scaling group only has a status while it's being deleted
+	if group.Status != nil {
+		status.Status = *group.Status
+	}
+
+	if strings.HasPrefix(summary.ImageID, "ami-") {
+		output, err := provider.EC2().DescribeImages(&ec2.DescribeImagesInput{
+			ImageIds: aws.StringSlice([]string{summary.ImageID}),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing image %q", summary.ImageID)
+		}
+		if len(output.Images) > 0 {
+			status.ReleaseVersion = aws.StringValue(output.Images[0].Name)
+		}
+	}
+	return status, nil
+}
+
+func getManagedStatus(provider api.ClusterProvider, clusterName, nodeGroupName string) (*manager.NodeGroupStatus, error) {
+	output, err := provider.EKS().DescribeNodegroup(&awseks.DescribeNodegroupInput{
+		ClusterName:   &clusterName,
+		NodegroupName: &nodeGroupName,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "describing managed nodegroup")
+	}
+	ng := output.Nodegroup
+
+	status := &manager.NodeGroupStatus{
+		Status:         aws.StringValue(ng.Status),
+		ReleaseVersion: aws.StringValue(ng.ReleaseVersion),
+	}
+	if sc := ng.ScalingConfig; sc != nil {
+		status.DesiredCapacity = int(aws.Int64Value(sc.DesiredSize))
+		status.MinSize = int(aws.Int64Value(sc.MinSize))
+		status.MaxSize = int(aws.Int64Value(sc.MaxSize))
+	}
+	if ng.Health != nil {
+		for _, issue := range ng.Health.Issues {
+			status.HealthIssues = append(status.HealthIssues, fmt.Sprintf("%s: %s", aws.StringValue(issue.Code), aws.StringValue(issue.Message)))
+		}
+	}
+
+	if ng.Resources != nil {
+		var asgNames []string
+		for _, asg := range ng.Resources.AutoScalingGroups {
+			asgNames = append(asgNames, aws.StringValue(asg.Name))
+		}
+		if len(asgNames) > 0 {
+			groups, err := describeAutoScalingGroups(provider, asgNames)
+			if err != nil {
+				return nil, err
+			}
+			status.Nodes = countInServiceInstances(groups)
+		}
+	}
+
+	update, err := getLastManagedNodeGroupUpdate(provider, clusterName, nodeGroupName)
+	if err != nil {
+		return nil, err
+	}
+	if update != nil {
+		status.Update = fmt.Sprintf("%s %s", aws.StringValue(update.Type), aws.StringValue(update.Status))
+	}
+	return status, nil
+}
+
+// getLastManagedNodeGroupUpdate returns the most recent update of a managed nodegroup, if any,
+// the updates being listed in no particular order
+func getLastManagedNodeGroupUpdate(provider api.ClusterProvider, clusterName, nodeGroupName string) (*awseks.Update, error) {
+	var updateIDs []*string
+	err := provider.EKS().ListUpdatesPages(&awseks.ListUpdatesInput{
+		Name:          &clusterName,
+		NodegroupName: &nodeGroupName,
+	}, func(output *awseks.ListUpdatesOutput, _ bool) bool {
+		updateIDs = append(updateIDs, output.UpdateIds...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing updates of managed nodegroup")
+	}
+
+	var last *awseks.Update
+	for _, id := range updateIDs {
+		output, err := provider.EKS().DescribeUpdate(&awseks.DescribeUpdateInput{
+			Name:          &clusterName,
+			NodegroupName: &nodeGroupName,
+			UpdateId:      id,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing update %q of managed nodegroup", *id)
+		}
+		if last == nil || aws.TimeValue(output.Update.CreatedAt).After(aws.TimeValue(last.CreatedAt)) {
+			last = output.Update
+		}
+	}
+	return last, nil
+}
+
+func describeAutoScalingGroups(provider api.ClusterProvider, names []string) ([]*autoscaling.Group, error) {
+	output, err := provider.ASG().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: aws.StringSlice(names),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing Auto Scaling groups %v", names)
+	}
+	return output.AutoScalingGroups, nil
+}
+
+func countInServiceInstances(groups []*autoscaling.Group) int {
+	count := 0
+	for _, group := range groups {
+		for _, instance := range group.Instances {
+			if aws.StringValue(instance.LifecycleState) == autoscaling.LifecycleStateInService {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func unhealthyInstances(groups []*autoscaling.Group) []string {
+	var issues []string
+	for _, group := range groups {
+		for _, instance := range group.Instances {
+			if aws.StringValue(instance.HealthStatus) != "Healthy" {
+				issues = append(issues, fmt.Sprintf("instance %s is %s", aws.StringValue(instance.InstanceId), aws.StringValue(instance.HealthStatus)))
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,159 @@
+package nodegroup
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("nodegroup status", func() {
+	var (
+		p            *mockprovider.MockProvider
+		stackManager *manager.StackCollection
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		stackManager = manager.NewStackCollection(p, cfg)
+	})
+
+	instance := func(id, lifecycleState, healthStatus string) *autoscaling.Instance {
+		return &autoscaling.Instance{
+			InstanceId:     aws.String(id),
+			LifecycleState: aws.String(lifecycleState),
+			HealthStatus:   aws.String(healthStatus),
+		}
+	}
+
+	It("reads the state of self-managed nodegroups from their Auto Scaling group", func() {
+		const stackName = "eksctl-test-cluster-nodegroup-ng-1"
+		p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+			return *input.StackName == stackName
+		})).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName: aws.String(stackName),
+				Tags: []*cfn.Tag{{
+					Key:   aws.String(api.NodeGroupNameTag),
+					Value: aws.String("ng-1"),
+				}},
+			}},
+		}, nil)
+		p.MockCloudFormation().On("DescribeStackResource", mock.MatchedBy(func(input *cfn.DescribeStackResourceInput) bool {
+			return *input.StackName == stackName && *input.LogicalResourceId == "NodeGroup"
+		})).Return(&cfn.DescribeStackResourceOutput{
+			StackResourceDetail: &cfn.StackResourceDetail{
+				PhysicalResourceId: aws.String("ng-1-asg"),
+			},
+		}, nil)
+		p.MockASG().On("DescribeAutoScalingGroups", mock.MatchedBy(func(input *autoscaling.DescribeAutoScalingGroupsInput) bool {
+			return aws.StringValueSlice(input.AutoScalingGroupNames)[0] == "ng-1-asg"
+		})).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{{
+				DesiredCapacity: aws.Int64(3),
+				MinSize:         aws.Int64(1),
+				MaxSize:         aws.Int64(5),
+				Instances: []*autoscaling.Instance{
+					instance("i-1", autoscaling.LifecycleStateInService, "Healthy"),
+					instance("i-2", autoscaling.LifecycleStateInService, "Unhealthy"),
+					instance("i-3", autoscaling.LifecycleStatePending, "Healthy"),
+				},
+			}},
+		}, nil)
+		p.MockEC2().On("DescribeImages", mock.Anything).Return(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{{Name: aws.String("amazon-eks-node-1.16-v20200423")}},
+		}, nil)
+
+		summary := &manager.NodeGroupSummary{
+			Name:        "ng-1",
+			Type:        api.NodeGroupTypeUnmanaged,
+			ImageID:     "ami-123",
+			StackStatus: cfn.StackStatusUpdateComplete,
+		}
+		Expect(GetStatus(p, stackManager, "test-cluster", []*manager.NodeGroupSummary{summary})).To(Succeed())
+		Expect(summary.Status).To(Equal(&manager.NodeGroupStatus{
+			Status:          "ACTIVE",
+			DesiredCapacity: 3,
+			MinSize:         1,
+			MaxSize:         5,
+			Nodes:           2,
+			ReleaseVersion:  "amazon-eks-node-1.16-v20200423",
+			HealthIssues:    []string{"instance i-2 is Unhealthy"},
+			Update:          cfn.StackStatusUpdateComplete,
+		}))
+	})
+
+	It("reads the state of managed nodegroups from EKS", func() {
+		p.MockEKS().On("DescribeNodegroup", mock.MatchedBy(func(input *awseks.DescribeNodegroupInput) bool {
+			return *input.ClusterName == "test-cluster" && *input.NodegroupName == "mng-1"
+		})).Return(&awseks.DescribeNodegroupOutput{
+			Nodegroup: &awseks.Nodegroup{
+				Status:         aws.String(awseks.NodegroupStatusDegraded),
+				ReleaseVersion: aws.String("1.16.8-20200423"),
+				ScalingConfig: &awseks.NodegroupScalingConfig{
+					DesiredSize: aws.Int64(2),
+					MinSize:     aws.Int64(2),
+					MaxSize:     aws.Int64(4),
+				},
+				Health: &awseks.NodegroupHealth{
+					Issues: []*awseks.Issue{{
+						Code:    aws.String(awseks.NodegroupIssueCodeAccessDenied),
+						Message: aws.String("access denied"),
+					}},
+				},
+				Resources: &awseks.NodegroupResources{
+					AutoScalingGroups: []*awseks.AutoScalingGroup{{Name: aws.String("mng-1-asg")}},
+				},
+			},
+		}, nil)
+		p.MockASG().On("DescribeAutoScalingGroups", mock.Anything).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{{
+				Instances: []*autoscaling.Instance{
+					instance("i-1", autoscaling.LifecycleStateInService, "Healthy"),
+				},
+			}},
+		}, nil)
+		p.MockEKS().On("ListUpdatesPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(*awseks.ListUpdatesOutput, bool) bool)
+			consume(&awseks.ListUpdatesOutput{UpdateIds: aws.StringSlice([]string{"update-1", "update-2"})}, true)
+		}).Return(nil)
+		now := time.Now()
+		for id, update := range map[string]*awseks.Update{
+			"update-1": {Type: aws.String(awseks.UpdateTypeConfigUpdate), Status: aws.String(awseks.UpdateStatusSuccessful), CreatedAt: aws.Time(now.Add(-time.Hour))},
+			"update-2": {Type: aws.String(awseks.UpdateTypeVersionUpdate), Status: aws.String(awseks.UpdateStatusInProgress), CreatedAt: aws.Time(now)},
+		} {
+			id := id
+			p.MockEKS().On("DescribeUpdate", mock.MatchedBy(func(input *awseks.DescribeUpdateInput) bool {
+				return *input.UpdateId == id
+			})).Return(&awseks.DescribeUpdateOutput{Update: update}, nil)
+		}
+
+		summary := &manager.NodeGroupSummary{
+			Name: "mng-1",
+			Type: api.NodeGroupTypeManaged,
+		}
+		Expect(GetStatus(p, stackManager, "test-cluster", []*manager.NodeGroupSummary{summary})).To(Succeed())
+		Expect(summary.Status).To(Equal(&manager.NodeGroupStatus{
+			Status:          awseks.NodegroupStatusDegraded,
+			DesiredCapacity: 2,
+			MinSize:         2,
+			MaxSize:         4,
+			Nodes:           1,
+			ReleaseVersion:  "1.16.8-20200423",
+			HealthIssues:    []string{"AccessDenied: access denied"},
+			Update:          "VersionUpdate InProgress",
+		}))
+	})
+})
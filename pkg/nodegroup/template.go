@@ -0,0 +1,29 @@
+package nodegroup
+
+import (
+	"github.com/tidwall/sjson"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// TODO use goformation types
+const (
+	launchTemplateDataPath = "Resources.NodeGroupLaunchTemplate.Properties.LaunchTemplateData"
+	updatePolicyPath       = "Resources.NodeGroup.UpdatePolicy"
+)
+
+// updateLaunchTemplateData sets a field of the launch template data in the template of a self-managed
+// nodegroup and updates its stack; the rolling update policy of the Auto Scaling group is removed,
+// so that the instances are replaced by ReplaceInstances, once their nodes are drained, rather
+// than by CloudFormation
+func updateLaunchTemplateData(stackManager *manager.StackCollection, nodeGroupName, template, field string, value interface{}) error {
+	template, err := sjson.Set(template, launchTemplateDataPath+"."+field, value)
+	if err != nil {
+		return err
+	}
+	template, err = sjson.Delete(template, updatePolicyPath)
+	if err != nil {
+		return err
+	}
+	return stackManager.UpdateNodeGroupStack(nodeGroupName, template)
+}
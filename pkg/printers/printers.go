@@ -3,8 +3,9 @@ package printers
 import (
 	"fmt"
 	"io"
+	"strings"
 
-	"github.com/kris-nova/logger"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Type is the type representing all supported printer types.
@@ -17,6 +18,16 @@ const (
 	JSONType = Type("json")
 	// TableType represents a printer of Table type.
 	TableType = Type("table")
+	// WideType represents a printer of Table type that also prints the wide columns.
+	WideType = Type("wide")
+	// PrometheusType represents a printer of Prometheus text exposition type, it's not
+	// created by NewPrinter as only the commands that define metrics support it.
+	PrometheusType = Type("prometheus")
+	// JSONPathType represents a printer of the fields selected by a JSONPath template,
+	// it's given as `jsonpath=<template>`.
+	JSONPathType = Type("jsonpath")
+	// GoTemplateType represents a printer of Go template type, it's given as `go-template=<template>`.
+	GoTemplateType = Type("go-template")
 )
 
 // OutputPrinter is the interface that printer must implement. This allows
@@ -29,6 +40,13 @@ type OutputPrinter interface {
 
 // NewPrinter creates a new printer based in the printer type requested.
 func NewPrinter(printerType Type) (OutputPrinter, error) {
+	if tmpl, ok := templateOf(printerType, JSONPathType); ok {
+		return NewJSONPathPrinter(tmpl)
+	}
+	if tmpl, ok := templateOf(printerType, GoTemplateType); ok {
+		return NewGoTemplatePrinter(tmpl)
+	}
+
 	var printer OutputPrinter
 
 	switch printerType {
@@ -38,6 +56,8 @@ func NewPrinter(printerType Type) (OutputPrinter, error) {
 		printer = NewJSONPrinter()
 	case TableType:
 		printer = NewTablePrinter()
+	case WideType:
+		printer = NewWideTablePrinter()
 	default:
 		return nil, errInvalidPrinterType(printerType)
 	}
@@ -45,6 +65,30 @@ func NewPrinter(printerType Type) (OutputPrinter, error) {
 	return printer, nil
 }
 
+// templateOf returns the template of printer types like `jsonpath=<template>`
+func templateOf(printerType, templateType Type) (string, bool) {
+	prefix := templateType + "="
+	if !strings.HasPrefix(printerType, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(printerType, prefix), true
+}
+
 func errInvalidPrinterType(printerType Type) error {
-	return fmt.Errorf("unknown output printer type: expected {%q,%q,%q} but got %q", YAMLType, JSONType, TableType, printerType)
+	return fmt.Errorf("unknown output printer type: expected {%q,%q,%q,%q,%q,%q} but got %q", YAMLType, JSONType, TableType, WideType,
+		JSONPathType+"=<template>", GoTemplateType+"=<template>", printerType)
+}
+
+// SelectColumns selects the columns printed by table printers, it fails for
+// other printers when columns are given
+func SelectColumns(printer OutputPrinter, columns []string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	table, ok := printer.(*TablePrinter)
+	if !ok {
+		return fmt.Errorf("columns can only be selected with the %q and %q output formats", TableType, WideType)
+	}
+	table.SelectColumns(columns)
+	return nil
 }
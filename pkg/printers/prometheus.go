@@ -0,0 +1,150 @@
+package printers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// PrometheusSample is a single sample of a metric, identified by its labels
+type PrometheusSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+type prometheusMetric struct {
+	name       string
+	help       string
+	metricType string
+	getter     reflect.Value
+}
+
+// PrometheusPrinter is a printer that outputs objects as metrics
+// in the Prometheus text exposition format
+type PrometheusPrinter struct {
+	metrics []prometheusMetric
+}
+
+// NewPrometheusPrinter creates a new PrometheusPrinter without any metrics,
+// metrics must be added with AddGauge before printing
+func NewPrometheusPrinter() OutputPrinter {
+	return &PrometheusPrinter{}
+}
+
+// AddGauge adds a gauge metric, the getter must be a function that takes
+// a single item of the printed slice and returns []PrometheusSample
+func (p *PrometheusPrinter) AddGauge(name, help string, getter interface{}) {
+	p.metrics = append(p.metrics, prometheusMetric{
+		name:       name,
+		help:       help,
+		metricType: "gauge",
+		getter:     reflect.ValueOf(getter),
+	})
+}
+
+// PrintObj will print the passed object as Prometheus metrics
+// to the supplied writer.
+func (p *PrometheusPrinter) PrintObj(obj interface{}, writer io.Writer) error {
+	return p.PrintObjWithKind("objects", obj, writer)
+}
+
+// PrintObjWithKind will print the passed object as Prometheus metrics
+// to the supplied writer.
+func (p *PrometheusPrinter) PrintObjWithKind(kind string, obj interface{}, writer io.Writer) error {
+	if len(p.metrics) == 0 {
+		return fmt.Errorf("prometheus output is not supported for %s", strings.ToLower(kind))
+	}
+
+	itemsValue := reflect.ValueOf(obj)
+	if itemsValue.Kind() != reflect.Slice {
+		return errors.Errorf("prometheus printer expects a slice but the kind was %v", itemsValue.Kind())
+	}
+
+	b := &bytes.Buffer{}
+	for _, metric := range p.metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n", metric.name, escapePrometheusHelp(metric.help))
+		fmt.Fprintf(b, "# TYPE %s %s\n", metric.name, metric.metricType)
+
+		for i := 0; i < itemsValue.Len(); i++ {
+			samples, err := metric.samples(itemsValue.Index(i))
+			if err != nil {
+				return err
+			}
+			for _, sample := range samples {
+				fmt.Fprintf(b, "%s%s %s\n", metric.name, formatPrometheusLabels(sample.Labels),
+					strconv.FormatFloat(sample.Value, 'g', -1, 64))
+			}
+		}
+	}
+
+	_, err := writer.Write(b.Bytes())
+	return err
+}
+
+// LogObj will print the passed object as Prometheus metrics to
+// the logger.
+func (p *PrometheusPrinter) LogObj(log logger.Logger, msgFmt string, obj interface{}) error {
+	b := &bytes.Buffer{}
+	if err := p.PrintObj(obj, b); err != nil {
+		return err
+	}
+
+	log(msgFmt, strings.ReplaceAll(b.String(), "%", "%%"))
+
+	return nil
+}
+
+func (m *prometheusMetric) samples(item reflect.Value) ([]PrometheusSample, error) {
+	getterType := m.getter.Type()
+	if getterType.Kind() != reflect.Func || getterType.NumIn() != 1 || getterType.NumOut() != 1 {
+		return nil, errors.Errorf("getter of metric %q must be a function with a single argument and result", m.name)
+	}
+	if !item.Type().AssignableTo(getterType.In(0)) {
+		return nil, errors.Errorf("getter of metric %q expects %v but the item was %v", m.name, getterType.In(0), item.Type())
+	}
+
+	samples, ok := m.getter.Call([]reflect.Value{item})[0].Interface().([]PrometheusSample)
+	if !ok {
+		return nil, errors.Errorf("getter of metric %q must return []PrometheusSample", m.name)
+	}
+	return samples, nil
+}
+
+func formatPrometheusLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapePrometheusLabelValue(labels[name])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var (
+	prometheusHelpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	prometheusLabelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapePrometheusHelp(help string) string {
+	return prometheusHelpEscaper.Replace(help)
+}
+
+func escapePrometheusLabelValue(value string) string {
+	return prometheusLabelValueEscaper.Replace(value)
+}
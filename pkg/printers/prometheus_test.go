@@ -0,0 +1,93 @@
+package printers_test
+
+import (
+	"bytes"
+
+	. "github.com/weaveworks/eksctl/pkg/printers"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prometheus Printer", func() {
+	var (
+		printer OutputPrinter
+		out     bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		out.Reset()
+		printer = NewPrometheusPrinter()
+	})
+
+	Context("with gauges", func() {
+		BeforeEach(func() {
+			printer.(*PrometheusPrinter).AddGauge("cluster_info", "Information about the cluster", func(c *awseks.Cluster) []PrometheusSample {
+				return []PrometheusSample{{
+					Labels: map[string]string{"cluster": *c.Name, "version": *c.Version},
+					Value:  1,
+				}}
+			})
+			printer.(*PrometheusPrinter).AddGauge("cluster_subnets", "Number of subnets", func(c *awseks.Cluster) []PrometheusSample {
+				return []PrometheusSample{{
+					Labels: map[string]string{"cluster": *c.Name},
+					Value:  float64(len(c.ResourcesVpcConfig.SubnetIds)),
+				}}
+			})
+		})
+
+		It("prints each metric family with its samples", func() {
+			clusters := []*awseks.Cluster{
+				{
+					Name:               aws.String("test-1"),
+					Version:            aws.String("1.14"),
+					ResourcesVpcConfig: &awseks.VpcConfigResponse{SubnetIds: aws.StringSlice([]string{"sub1", "sub2"})},
+				},
+				{
+					Name:               aws.String(`quoted"name`),
+					Version:            aws.String("1.15"),
+					ResourcesVpcConfig: &awseks.VpcConfigResponse{},
+				},
+			}
+
+			Expect(printer.PrintObjWithKind("clusters", clusters, &out)).To(Succeed())
+			Expect(out.String()).To(Equal(`# HELP cluster_info Information about the cluster
+# TYPE cluster_info gauge
+cluster_info{cluster="test-1",version="1.14"} 1
+cluster_info{cluster="quoted\"name",version="1.15"} 1
+# HELP cluster_subnets Number of subnets
+# TYPE cluster_subnets gauge
+cluster_subnets{cluster="test-1"} 2
+cluster_subnets{cluster="quoted\"name"} 0
+`))
+		})
+
+		It("prints only the metadata of an empty slice", func() {
+			Expect(printer.PrintObjWithKind("clusters", []*awseks.Cluster{}, &out)).To(Succeed())
+			Expect(out.String()).To(HavePrefix("# HELP cluster_info"))
+			Expect(out.String()).NotTo(ContainSubstring("cluster_info{"))
+		})
+
+		It("fails when the items don't match the getters", func() {
+			err := printer.PrintObjWithKind("clusters", []string{"test-1"}, &out)
+			Expect(err).To(MatchError(ContainSubstring(`getter of metric "cluster_info" expects`)))
+		})
+
+		It("fails when the object is not a slice", func() {
+			err := printer.PrintObjWithKind("clusters", &awseks.Cluster{}, &out)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("is only created by the commands that define metrics", func() {
+		_, err := NewPrinter(PrometheusType)
+		Expect(err).To(MatchError(ContainSubstring(`unknown output printer type`)))
+	})
+
+	It("fails when no metrics are defined", func() {
+		err := printer.PrintObjWithKind("nodegroups", []string{}, &out)
+		Expect(err).To(MatchError("prometheus output is not supported for nodegroups"))
+	})
+})
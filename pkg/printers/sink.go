@@ -0,0 +1,101 @@
+package printers
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// WriteOutput calls print with the destination given by outputPath: stdout when it's empty,
+// an S3 object when it's an s3://bucket/key URL and a local file otherwise. The output only
+// replaces an existing file or object once print succeeds, files being written to a temporary
+// file that is renamed and objects being uploaded in parts as they're written
+func WriteOutput(outputPath string, newUploader func() s3manageriface.UploaderAPI, print func(io.Writer) error) error {
+	switch {
+	case outputPath == "":
+		return print(os.Stdout)
+	case strings.HasPrefix(outputPath, "s3://"):
+		return writeS3Output(outputPath, newUploader(), print)
+	default:
+		return writeFileOutput(outputPath, print)
+	}
+}
+
+func writeFileOutput(path string, print func(io.Writer) error) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".")
+	if err != nil {
+		return errors.Wrapf(err, "creating temporary file for %q", path)
+	}
+	defer os.Remove(f.Name())
+
+	if err := print(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "writing %q", f.Name())
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return errors.Wrapf(err, "renaming %q to %q", f.Name(), path)
+	}
+	logger.Info("output written to %q", path)
+	return nil
+}
+
+func parseS3URL(s3URL string) (bucket, key string, err error) {
+	u, err := url.Parse(s3URL)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "parsing %q", s3URL)
+	}
+	key = strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" || strings.HasSuffix(key, "/") {
+		return "", "", fmt.Errorf("invalid S3 URL %q, expected s3://bucket/key", s3URL)
+	}
+	return u.Host, key, nil
+}
+
+func writeS3Output(s3URL string, uploader s3manageriface.UploaderAPI, print func(io.Writer) error) error {
+	bucket, key, err := parseS3URL(s3URL)
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	uploadErr := make(chan error, 1)
+	go func() {
+		// the uploader aborts the multipart upload when reading the body fails, so the
+		// object is left untouched if print fails
+		_, err := uploader.Upload(&s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   reader,
+		})
+		// unblock print if the upload failed before reading all of the output
+		reader.CloseWithError(err)
+		uploadErr <- err
+	}()
+
+	if err := print(writer); err != nil {
+		writer.CloseWithError(err)
+		<-uploadErr
+		return err
+	}
+	writer.Close()
+
+	if err := <-uploadErr; err != nil {
+		return errors.Wrapf(err, "uploading output to %q", s3URL)
+	}
+	logger.Info("output uploaded to %q", s3URL)
+	return nil
+}
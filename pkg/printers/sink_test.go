@@ -0,0 +1,118 @@
+package printers_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/weaveworks/eksctl/pkg/printers"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeUploader struct {
+	bucket, key string
+	body        []byte
+}
+
+func (u *fakeUploader) Upload(input *s3manager.UploadInput, _ ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	u.bucket, u.key = *input.Bucket, *input.Key
+	body, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	u.body = body
+	return &s3manager.UploadOutput{}, nil
+}
+
+func (u *fakeUploader) UploadWithContext(_ aws.Context, input *s3manager.UploadInput, opts ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	return u.Upload(input, opts...)
+}
+
+var _ = Describe("WriteOutput", func() {
+	var (
+		uploader    *fakeUploader
+		newUploader func() s3manageriface.UploaderAPI
+	)
+
+	BeforeEach(func() {
+		uploader = &fakeUploader{}
+		newUploader = func() s3manageriface.UploaderAPI { return uploader }
+	})
+
+	printHello := func(w io.Writer) error {
+		_, err := fmt.Fprint(w, "hello")
+		return err
+	}
+
+	Context("with a local file", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "output")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("writes the output to the file", func() {
+			path := filepath.Join(dir, "clusters.json")
+			Expect(WriteOutput(path, newUploader, printHello)).To(Succeed())
+
+			content, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("hello"))
+		})
+
+		It("keeps the existing file when printing fails", func() {
+			path := filepath.Join(dir, "clusters.json")
+			Expect(ioutil.WriteFile(path, []byte("previous"), 0644)).To(Succeed())
+
+			err := WriteOutput(path, newUploader, func(w io.Writer) error {
+				_ = printHello(w)
+				return errors.New("printing failed")
+			})
+			Expect(err).To(MatchError("printing failed"))
+
+			content, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("previous"))
+
+			files, err := ioutil.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+		})
+	})
+
+	Context("with an S3 URL", func() {
+		It("uploads the output to the object", func() {
+			Expect(WriteOutput("s3://my-bucket/reports/clusters.json", newUploader, printHello)).To(Succeed())
+			Expect(uploader.bucket).To(Equal("my-bucket"))
+			Expect(uploader.key).To(Equal("reports/clusters.json"))
+			Expect(string(uploader.body)).To(Equal("hello"))
+		})
+
+		It("rejects URLs without a key", func() {
+			err := WriteOutput("s3://my-bucket/", newUploader, printHello)
+			Expect(err).To(MatchError(`invalid S3 URL "s3://my-bucket/", expected s3://bucket/key`))
+		})
+
+		It("fails the upload when printing fails", func() {
+			err := WriteOutput("s3://my-bucket/clusters.json", newUploader, func(w io.Writer) error {
+				return errors.New("printing failed")
+			})
+			Expect(err).To(MatchError("printing failed"))
+			Expect(uploader.body).To(BeNil())
+		})
+	})
+})
@@ -8,9 +8,10 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"k8s.io/kops/util/pkg/tables"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // TablePrinter is a printer that outputs an object formatted
@@ -18,6 +19,11 @@ import (
 type TablePrinter struct {
 	table      *tables.Table
 	columnames []string
+	// widecolumnames are the names of the columns only printed by wide printers
+	widecolumnames []string
+	wide           bool
+	// selectedcolumnames are the names of the columns selected with SelectColumns
+	selectedcolumnames []string
 }
 
 // NewTablePrinter creates a new TablePrinter with defaults.
@@ -25,6 +31,12 @@ func NewTablePrinter() OutputPrinter {
 	return &TablePrinter{table: &tables.Table{}}
 }
 
+// NewWideTablePrinter creates a new TablePrinter that also prints
+// the columns added with AddWideColumn.
+func NewWideTablePrinter() OutputPrinter {
+	return &TablePrinter{table: &tables.Table{}, wide: true}
+}
+
 // PrintObj will print the passed object formatted as textual
 // table to the supplied writer.
 func (t *TablePrinter) PrintObj(obj interface{}, writer io.Writer) error {
@@ -50,7 +62,11 @@ func (t *TablePrinter) PrintObjWithKind(kind string, obj interface{}, writer io.
 		return nil
 	}
 
-	return t.table.Render(obj, writer, t.columnames...)
+	columns, err := t.columns()
+	if err != nil {
+		return err
+	}
+	return t.table.Render(obj, writer, columns...)
 }
 
 // LogObj will print the passed object formatted as a table to
@@ -71,3 +87,52 @@ func (t *TablePrinter) AddColumn(name string, getter interface{}) {
 	t.columnames = append(t.columnames, name)
 	t.table.AddColumn(name, getter)
 }
+
+// AddWideColumn adds a column to the table that will only be printed
+// by wide printers, or when it's selected
+func (t *TablePrinter) AddWideColumn(name string, getter interface{}) {
+	t.widecolumnames = append(t.widecolumnames, name)
+	t.table.AddColumn(name, getter)
+}
+
+// SelectColumns selects the columns to print by name, whatever the way they
+// were added, e.g. `NAME`, `instance-type` or `INSTANCE TYPE`; the names are
+// checked when the table is printed
+func (t *TablePrinter) SelectColumns(names []string) {
+	t.selectedcolumnames = names
+}
+
+// ColumnNames returns the names of the columns that can be selected
+func (t *TablePrinter) ColumnNames() []string {
+	return append(append([]string{}, t.columnames...), t.widecolumnames...)
+}
+
+func (t *TablePrinter) columns() ([]string, error) {
+	if len(t.selectedcolumnames) == 0 {
+		if t.wide {
+			return t.ColumnNames(), nil
+		}
+		return t.columnames, nil
+	}
+
+	columns := make([]string, 0, len(t.selectedcolumnames))
+	for _, name := range t.selectedcolumnames {
+		column, ok := t.columnNamed(name)
+		if !ok {
+			return nil, errors.Errorf("unknown column %q, valid columns are: %s", name, strings.Join(t.ColumnNames(), ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func (t *TablePrinter) columnNamed(name string) (string, bool) {
+	normalize := strings.NewReplacer("-", " ", "_", " ")
+	name = normalize.Replace(strings.ToUpper(strings.TrimSpace(name)))
+	for _, column := range t.ColumnNames() {
+		if column == name {
+			return column, true
+		}
+	}
+	return "", false
+}
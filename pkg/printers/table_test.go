@@ -208,4 +208,62 @@ var _ = Describe("Table Printer", func() {
 			})
 		})
 	})
+
+	Describe("When selecting the columns of a Table printer", func() {
+		clusters := []*awseks.Cluster{{
+			Name:    aws.String("test-cluster"),
+			Arn:     aws.String("arn-12345678"),
+			Version: aws.String("1.15"),
+		}}
+
+		addColumns := func(printer OutputPrinter) *TablePrinter {
+			table := printer.(*TablePrinter)
+			table.AddColumn("NAME", func(c *awseks.Cluster) string {
+				return *c.Name
+			})
+			table.AddColumn("ARN", func(c *awseks.Cluster) string {
+				return *c.Arn
+			})
+			table.AddWideColumn("PLATFORM VERSION", func(c *awseks.Cluster) string {
+				return *c.Version
+			})
+			return table
+		}
+
+		render := func(printer OutputPrinter) (string, error) {
+			var out bytes.Buffer
+			err := printer.PrintObjWithKind("clusters", clusters, &out)
+			return out.String(), err
+		}
+
+		It("only prints the wide columns with the wide printer", func() {
+			printer, err := NewPrinter(TableType)
+			Expect(err).NotTo(HaveOccurred())
+			addColumns(printer)
+			Expect(render(printer)).To(Equal("NAME\t\tARN\ntest-cluster\tarn-12345678\n"))
+
+			printer, err = NewPrinter(WideType)
+			Expect(err).NotTo(HaveOccurred())
+			addColumns(printer)
+			Expect(render(printer)).To(Equal("NAME\t\tARN\t\tPLATFORM VERSION\ntest-cluster\tarn-12345678\t1.15\n"))
+		})
+
+		It("prints the selected columns in order", func() {
+			printer := NewTablePrinter()
+			Expect(SelectColumns(printer, []string{"platform-version", "NAME"})).To(Succeed())
+			addColumns(printer)
+			Expect(render(printer)).To(Equal("PLATFORM VERSION\tNAME\n1.15\t\t\ttest-cluster\n"))
+		})
+
+		It("fails on unknown columns and printers without columns", func() {
+			printer := NewTablePrinter()
+			Expect(SelectColumns(printer, []string{"NAME", "STATUS"})).To(Succeed())
+			addColumns(printer)
+			_, err := render(printer)
+			Expect(err).To(MatchError(`unknown column "STATUS", valid columns are: NAME, ARN, PLATFORM VERSION`))
+
+			Expect(SelectColumns(NewJSONPrinter(), []string{"NAME"})).To(MatchError(ContainSubstring("columns can only be selected")))
+			Expect(SelectColumns(NewJSONPrinter(), nil)).To(Succeed())
+		})
+	})
 })
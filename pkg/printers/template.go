@@ -0,0 +1,123 @@
+package printers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// JSONPathPrinter is a printer that outputs the fields of an object
+// selected by a JSONPath template, e.g. `{.Name}`
+type JSONPathPrinter struct {
+	jsonPath *jsonpath.JSONPath
+}
+
+// NewJSONPathPrinter creates a new JSONPathPrinter with the given template
+func NewJSONPathPrinter(tmpl string) (OutputPrinter, error) {
+	jsonPath := jsonpath.New("output").AllowMissingKeys(true)
+	if err := jsonPath.Parse(tmpl); err != nil {
+		return nil, errors.Wrapf(err, "parsing JSONPath template %q", tmpl)
+	}
+	return &JSONPathPrinter{jsonPath: jsonPath}, nil
+}
+
+// PrintObj will print the fields of the passed object selected by the
+// template to the supplied writer.
+func (j *JSONPathPrinter) PrintObj(obj interface{}, writer io.Writer) error {
+	data, err := toJSONData(obj)
+	if err != nil {
+		return err
+	}
+	if err := j.jsonPath.Execute(writer, data); err != nil {
+		return errors.Wrap(err, "executing JSONPath template")
+	}
+	return nil
+}
+
+// PrintObjWithKind will print the fields of the passed object selected by
+// the template to the supplied writer. This printer ignores kind argument.
+func (j *JSONPathPrinter) PrintObjWithKind(kind string, obj interface{}, writer io.Writer) error {
+	return j.PrintObj(obj, writer)
+}
+
+// LogObj will print the fields of the passed object selected by the
+// template to the logger.
+func (j *JSONPathPrinter) LogObj(log logger.Logger, msgFmt string, obj interface{}) error {
+	b := &bytes.Buffer{}
+	if err := j.PrintObj(obj, b); err != nil {
+		return err
+	}
+
+	log(msgFmt, strings.ReplaceAll(b.String(), "%", "%%"))
+
+	return nil
+}
+
+// GoTemplatePrinter is a printer that outputs an object formatted
+// with a Go template, e.g. `{{.Name}}`
+type GoTemplatePrinter struct {
+	template *template.Template
+}
+
+// NewGoTemplatePrinter creates a new GoTemplatePrinter with the given template
+func NewGoTemplatePrinter(tmpl string) (OutputPrinter, error) {
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing Go template %q", tmpl)
+	}
+	return &GoTemplatePrinter{template: t}, nil
+}
+
+// PrintObj will print the passed object formatted with the template
+// to the supplied writer.
+func (g *GoTemplatePrinter) PrintObj(obj interface{}, writer io.Writer) error {
+	data, err := toJSONData(obj)
+	if err != nil {
+		return err
+	}
+	if err := g.template.Execute(writer, data); err != nil {
+		return errors.Wrap(err, "executing Go template")
+	}
+	return nil
+}
+
+// PrintObjWithKind will print the passed object formatted with the template
+// to the supplied writer. This printer ignores kind argument.
+func (g *GoTemplatePrinter) PrintObjWithKind(kind string, obj interface{}, writer io.Writer) error {
+	return g.PrintObj(obj, writer)
+}
+
+// LogObj will print the passed object formatted with the template
+// to the logger.
+func (g *GoTemplatePrinter) LogObj(log logger.Logger, msgFmt string, obj interface{}) error {
+	b := &bytes.Buffer{}
+	if err := g.PrintObj(obj, b); err != nil {
+		return err
+	}
+
+	log(msgFmt, strings.ReplaceAll(b.String(), "%", "%%"))
+
+	return nil
+}
+
+// toJSONData converts an object to the generic maps and slices it's decoded
+// to from JSON, so that templates refer to fields by their JSON names, like the
+// JSON printer prints them
+func toJSONData(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
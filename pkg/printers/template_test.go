@@ -0,0 +1,53 @@
+package printers_test
+
+import (
+	"bytes"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/printers"
+)
+
+var _ = Describe("Template printers", func() {
+	var clusters []*awseks.Cluster
+
+	BeforeEach(func() {
+		clusters = []*awseks.Cluster{
+			{Name: aws.String("cluster-1"), Version: aws.String("1.15")},
+			{Name: aws.String("cluster-2"), Version: aws.String("1.14")},
+		}
+	})
+
+	printWith := func(printerType Type) string {
+		printer, err := NewPrinter(printerType)
+		Expect(err).NotTo(HaveOccurred())
+
+		var out bytes.Buffer
+		Expect(printer.PrintObjWithKind("clusters", clusters, &out)).To(Succeed())
+		return out.String()
+	}
+
+	It("prints the fields selected by a JSONPath template", func() {
+		Expect(printWith("jsonpath={[*].Name}")).To(Equal("cluster-1 cluster-2"))
+		Expect(printWith("jsonpath={[1].Version}")).To(Equal("1.14"))
+		Expect(printWith("jsonpath={[0].Missing}")).To(BeEmpty())
+	})
+
+	It("prints objects formatted with a Go template", func() {
+		Expect(printWith(`go-template={{range .}}{{.Name}}={{.Version}};{{end}}`)).To(Equal("cluster-1=1.15;cluster-2=1.14;"))
+	})
+
+	It("fails on invalid templates", func() {
+		_, err := NewPrinter("jsonpath={[0}")
+		Expect(err).To(MatchError(ContainSubstring("parsing JSONPath template")))
+
+		_, err = NewPrinter("go-template={{.Name")
+		Expect(err).To(MatchError(ContainSubstring("parsing Go template")))
+
+		_, err = NewPrinter("jsonpath")
+		Expect(err).To(MatchError(ContainSubstring("unknown output printer type")))
+	})
+})
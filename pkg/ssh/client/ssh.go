@@ -7,9 +7,9 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/file"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -97,6 +97,19 @@ func DeleteKeys(clusterName string, ec2API ec2iface.EC2API) {
 	}
 }
 
+// DeleteNodeGroupKey deletes the key pair keyName if it was imported by eksctl for the nodegroup,
+// key pairs created outside of eksctl are kept; it returns whether the key pair was deleted
+func DeleteNodeGroupKey(keyName, clusterName, nodeGroupName string, ec2API ec2iface.EC2API) (bool, error) {
+	if !strings.HasPrefix(keyName, getKeyName(clusterName, nodeGroupName, "")+"-") {
+		return false, nil
+	}
+	logger.Debug("deleting key %q", keyName)
+	if _, err := ec2API.DeleteKeyPair(&ec2.DeleteKeyPairInput{KeyName: &keyName}); err != nil {
+		return false, errors.Wrapf(err, "deleting key pair %q", keyName)
+	}
+	return true, nil
+}
+
 // CheckKeyExistsInEC2 returns whether a public ssh key already exists in EC2 or error if it couldn't be checked
 func CheckKeyExistsInEC2(sshKeyName string, ec2API ec2iface.EC2API) error {
 	existing, err := findKeyInEc2(sshKeyName, ec2API)
@@ -169,6 +169,31 @@ var _ = Describe("ssh public key", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	Describe("deleting the key of a nodegroup", func() {
+
+		It("should delete a key imported for the nodegroup", func() {
+			mockDeleteKeyPair(mockEC2)
+
+			deleted, err := DeleteNodeGroupKey(keyName, clusterName, ngName, mockEC2)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+			mockEC2.AssertCalled(GinkgoT(), "DeleteKeyPair", &ec2.DeleteKeyPairInput{KeyName: &keyName})
+		})
+
+		It("should keep keys that weren't imported for the nodegroup", func() {
+			mockDeleteKeyPair(mockEC2)
+
+			for _, name := range []string{"my-key", "eksctl-sshtestcluster-nodegroup-ng10-" + fingerprint} {
+				deleted, err := DeleteNodeGroupKey(name, clusterName, ngName, mockEC2)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deleted).To(BeFalse())
+			}
+			mockEC2.AssertNotCalled(GinkgoT(), "DeleteKeyPair", mock.Anything)
+		})
+	})
 })
 
 func mockDeleteKeyPair(mockEC2 *mocks.EC2API) {
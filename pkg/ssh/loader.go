@@ -2,8 +2,8 @@ package ssh
 
 import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
-	"github.com/kris-nova/logger"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/ssh/client"
 	"github.com/weaveworks/eksctl/pkg/utils/file"
 )
@@ -11,9 +11,9 @@ import (
 // LoadKey loads the SSH public key specified in NodeGroupSSH and returns it. The key should be specified
 // in only one way: by name (for a key existing in EC2), by path (for a key in a local file)
 // or by its contents (in the config-file). It also assumes that if ssh is enabled (SSH.Allow
-// == true) then one key was specified
+// == true) then one key was specified. No key is loaded when the nodes are accessed through SSM
 func LoadKey(sshConfig *api.NodeGroupSSH, clusterName, nodeGroupName string, ec2API ec2iface.EC2API) (string, error) {
-	if sshConfig.Allow == nil || !*sshConfig.Allow {
+	if sshConfig.Allow == nil || !*sshConfig.Allow || api.IsEnabled(sshConfig.EnableSSM) {
 		return "", nil
 	}
 
@@ -0,0 +1,85 @@
+package ssm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	awsssm "github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// SessionManagerPlugin is the binary that connects to SSM sessions, as used by the AWS CLI
+const SessionManagerPlugin = "session-manager-plugin"
+
+// InstanceID returns the EC2 instance ID of a node, which is the last part
+// of its provider ID, e.g. aws:///us-west-2a/i-0123456789abcdef0
+func InstanceID(clientSet kubernetes.Interface, nodeName string) (string, error) {
+	node, err := clientSet.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "getting node %q", nodeName)
+	}
+	providerID := node.Spec.ProviderID
+	if !strings.HasPrefix(providerID, "aws://") {
+		return "", fmt.Errorf("node %q is not an EC2 instance (provider ID %q)", nodeName, providerID)
+	}
+	return providerID[strings.LastIndex(providerID, "/")+1:], nil
+}
+
+// StartSession starts an SSM session to an instance and connects to it with
+// the session manager plugin, until the session is closed
+func StartSession(provider api.ClusterProvider, instanceID string) error {
+	pluginPath, err := exec.LookPath(SessionManagerPlugin)
+	if err != nil {
+		return errors.Wrapf(err, "%s must be installed to start SSM sessions, "+
+			"see https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html", SessionManagerPlugin)
+	}
+
+	endpoint, err := endpoints.DefaultResolver().EndpointFor(awsssm.EndpointsID, provider.Region())
+	if err != nil {
+		return errors.Wrapf(err, "resolving SSM endpoint of region %q", provider.Region())
+	}
+
+	input := &awsssm.StartSessionInput{
+		Target: &instanceID,
+	}
+	output, err := provider.SSM().StartSession(input)
+	if err != nil {
+		return errors.Wrapf(err, "starting SSM session to instance %q", instanceID)
+	}
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	outputJSON, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(pluginPath, string(outputJSON), provider.Region(), "StartSession", provider.Profile(), string(inputJSON), endpoint.URL)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// interrupts go to the session, the plugin exits when it ends
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	if err := cmd.Run(); err != nil {
+		if _, err := provider.SSM().TerminateSession(&awsssm.TerminateSessionInput{SessionId: output.SessionId}); err != nil {
+			logger.Debug("ignoring error terminating SSM session: %s", err.Error())
+		}
+		return errors.Wrapf(err, "running %s", SessionManagerPlugin)
+	}
+	return nil
+}
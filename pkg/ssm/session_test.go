@@ -0,0 +1,36 @@
+package ssm
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("SSM sessions", func() {
+	newNode := func(name, providerID string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+		}
+	}
+
+	It("resolves the instance ID of a node", func() {
+		clientSet := fake.NewSimpleClientset(newNode("ip-192-168-1-1.ec2.internal", "aws:///us-west-2a/i-0123456789abcdef0"))
+		instanceID, err := InstanceID(clientSet, "ip-192-168-1-1.ec2.internal")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instanceID).To(Equal("i-0123456789abcdef0"))
+	})
+
+	It("rejects nodes that aren't EC2 instances", func() {
+		clientSet := fake.NewSimpleClientset(newNode("fargate-ip-192-168-1-2", "fargate://192.168.1.2"))
+		_, err := InstanceID(clientSet, "fargate-ip-192-168-1-2")
+		Expect(err).To(MatchError(`node "fargate-ip-192-168-1-2" is not an EC2 instance (provider ID "fargate://192.168.1.2")`))
+	})
+
+	It("fails for unknown nodes", func() {
+		_, err := InstanceID(fake.NewSimpleClientset(), "unknown")
+		Expect(err).To(HaveOccurred())
+	})
+})
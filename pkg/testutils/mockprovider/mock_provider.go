@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
@@ -30,6 +31,8 @@ var ProviderConfig = &api.ProviderConfig{
 	Region:      api.DefaultRegion,
 	Profile:     "default",
 	WaitTimeout: 1200000000000,
+	// all phases use WaitTimeout
+	WaitTimeoutSet: true,
 }
 
 type MockAWSClient struct {
@@ -58,6 +61,7 @@ type MockProvider struct {
 	ssm        *mocks.SSMAPI
 	iam        *mocks.IAMAPI
 	cloudtrail *mocks.CloudTrailAPI
+	asg        *mocks.AutoScalingAPI
 }
 
 // NewMockProvider returns a new MockProvider
@@ -74,6 +78,7 @@ func NewMockProvider() *MockProvider {
 		ssm:        &mocks.SSMAPI{},
 		iam:        &mocks.IAMAPI{},
 		cloudtrail: &mocks.CloudTrailAPI{},
+		asg:        &mocks.AutoScalingAPI{},
 	}
 }
 
@@ -132,6 +137,12 @@ func (m MockProvider) MockCloudTrail() *mocks.CloudTrailAPI {
 	return m.CloudTrail().(*mocks.CloudTrailAPI)
 }
 
+// ASG returns a representation of the AutoScaling API
+func (m MockProvider) ASG() autoscalingiface.AutoScalingAPI { return m.asg }
+
+// MockASG returns a mocked AutoScaling API
+func (m MockProvider) MockASG() *mocks.AutoScalingAPI { return m.ASG().(*mocks.AutoScalingAPI) }
+
 // Profile returns current profile setting
 func (m MockProvider) Profile() string { return ProviderConfig.Profile }
 
@@ -141,6 +152,14 @@ func (m MockProvider) Region() string { return ProviderConfig.Region }
 // WaitTimeout returns current timeout setting
 func (m MockProvider) WaitTimeout() time.Duration { return ProviderConfig.WaitTimeout }
 
+// Timeout returns current timeout setting of a phase
+func (m MockProvider) Timeout(phase api.TimeoutPhase) time.Duration {
+	return ProviderConfig.Timeout(phase)
+}
+
+// StackOnFailure returns what happens to the stacks that fail to be created
+func (m MockProvider) StackOnFailure() string { return ProviderConfig.StackOnFailure }
+
 func NewMockAWSClient() *MockAWSClient {
 	m := &MockAWSClient{
 		Client: awstesting.NewClient(&aws.Config{
@@ -0,0 +1,103 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ContextNameFields are the values of the placeholders of context name templates
+type ContextNameFields struct {
+	Cluster string
+	Region  string
+	Account string
+	User    string
+}
+
+var contextNamePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+func (f ContextNameFields) placeholders() map[string]string {
+	return map[string]string{
+		"cluster": f.Cluster,
+		"region":  f.Region,
+		"account": f.Account,
+		"user":    f.User,
+	}
+}
+
+// ValidateContextNameTemplate checks that a context name template, e.g. `{cluster}.{region}.{account}`,
+// only uses the placeholders of ContextNameFields
+func ValidateContextNameTemplate(template string) error {
+	placeholders := ContextNameFields{}.placeholders()
+	for _, match := range contextNamePlaceholder.FindAllStringSubmatch(template, -1) {
+		if _, ok := placeholders[match[1]]; !ok {
+			return fmt.Errorf("unknown placeholder %q in context name template %q, valid placeholders are: {cluster}, {region}, {account}, {user}", match[0], template)
+		}
+	}
+	return nil
+}
+
+// ContextName returns the name of a context from a template validated with ValidateContextNameTemplate
+func ContextName(template string, fields ContextNameFields) string {
+	placeholders := fields.placeholders()
+	return contextNamePlaceholder.ReplaceAllStringFunc(template, func(match string) string {
+		return placeholders[strings.Trim(match, "{}")]
+	})
+}
+
+// RenameContext renames the current context of a config created by New, along with its user
+func RenameContext(config *clientcmdapi.Config, name string) {
+	current := config.CurrentContext
+	if name == current {
+		return
+	}
+	context := config.Contexts[current]
+	delete(config.Contexts, current)
+	if authInfo, ok := config.AuthInfos[context.AuthInfo]; ok {
+		delete(config.AuthInfos, context.AuthInfo)
+		config.AuthInfos[name] = authInfo
+		context.AuthInfo = name
+	}
+	config.Contexts[name] = context
+	config.CurrentContext = name
+}
+
+// ContextInfo describes a context of a kubeconfig file
+type ContextInfo struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string
+	Current   bool
+	// Eksctl is true for the contexts of clusters whose kubeconfig was written by eksctl
+	Eksctl bool
+}
+
+// ListContexts returns the contexts of the kubeconfig at path, sorted by name; the path is
+// determined by client-go when it's empty
+func ListContexts(path string) ([]ContextInfo, error) {
+	config, err := getConfigAccess(path).GetStartingConfig()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read kubeconfig file %q", path)
+	}
+
+	var contexts []ContextInfo
+	for name, context := range config.Contexts {
+		contexts = append(contexts, ContextInfo{
+			Name:      name,
+			Cluster:   context.Cluster,
+			User:      context.AuthInfo,
+			Namespace: context.Namespace,
+			Current:   name == config.CurrentContext,
+			Eksctl:    strings.HasSuffix(context.Cluster, ".eksctl.io"),
+		})
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
+	return contexts, nil
+}
@@ -0,0 +1,92 @@
+package kubeconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+)
+
+var _ = Describe("Kubeconfig contexts", func() {
+	fields := kubeconfig.ContextNameFields{
+		Cluster: "cluster-1",
+		Region:  "us-west-2",
+		Account: "123456789012",
+		User:    "admin",
+	}
+
+	newConfig := func(name, region string) *api.ClusterConfig {
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = name
+		cfg.Metadata.Region = region
+		cfg.Status = &api.ClusterStatus{Endpoint: "https://" + name + ".example.com"}
+		return cfg
+	}
+
+	It("names contexts from templates", func() {
+		Expect(kubeconfig.ValidateContextNameTemplate("{cluster}.{region}.{account}")).To(Succeed())
+		Expect(kubeconfig.ContextName("{cluster}.{region}.{account}", fields)).To(Equal("cluster-1.us-west-2.123456789012"))
+		Expect(kubeconfig.ContextName("{user}@{cluster}", fields)).To(Equal("admin@cluster-1"))
+		Expect(kubeconfig.ValidateContextNameTemplate("{cluster}-{stage}")).To(MatchError(ContainSubstring(`unknown placeholder "{stage}"`)))
+	})
+
+	It("renames the context and the user of a config", func() {
+		config := kubeconfig.NewForKubectl(newConfig("cluster-1", "us-west-2"), "admin", kubeconfig.AWSEKSAuthenticator, "", "")
+		kubeconfig.RenameContext(config, "cluster-1.us-west-2")
+
+		Expect(config.CurrentContext).To(Equal("cluster-1.us-west-2"))
+		Expect(config.Contexts).To(HaveLen(1))
+		Expect(config.Contexts["cluster-1.us-west-2"].Cluster).To(Equal("cluster-1.us-west-2.eksctl.io"))
+		Expect(config.Contexts["cluster-1.us-west-2"].AuthInfo).To(Equal("cluster-1.us-west-2"))
+		Expect(config.AuthInfos).To(HaveKey("cluster-1.us-west-2"))
+	})
+
+	Context("writing contexts", func() {
+		var configFile *os.File
+
+		BeforeEach(func() {
+			var err error
+			configFile, err = ioutil.TempFile("", "")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.Remove(configFile.Name())
+		})
+
+		write := func(cfg *api.ClusterConfig, contextName string) error {
+			config := kubeconfig.NewForKubectl(cfg, "admin", kubeconfig.AWSEKSAuthenticator, "", "")
+			kubeconfig.RenameContext(config, contextName)
+			_, err := kubeconfig.Write(configFile.Name(), *config, true)
+			return err
+		}
+
+		It("doesn't replace the contexts of other clusters", func() {
+			Expect(write(newConfig("cluster-1", "us-west-2"), "cluster-1")).To(Succeed())
+			Expect(write(newConfig("cluster-1", "us-west-2"), "cluster-1")).To(Succeed())
+			Expect(write(newConfig("cluster-1", "eu-west-1"), "cluster-1")).To(MatchError(ContainSubstring(`context "cluster-1" already exists for cluster "cluster-1.us-west-2.eksctl.io"`)))
+
+			config, err := clientcmd.LoadFromFile(configFile.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Contexts).To(HaveLen(1))
+			Expect(config.Clusters).To(HaveKey("cluster-1.us-west-2.eksctl.io"))
+		})
+
+		It("lists the contexts", func() {
+			Expect(write(newConfig("cluster-1", "us-west-2"), "cluster-1")).To(Succeed())
+			Expect(write(newConfig("cluster-2", "us-west-2"), "cluster-2")).To(Succeed())
+
+			contexts, err := kubeconfig.ListContexts(configFile.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contexts).To(Equal([]kubeconfig.ContextInfo{
+				{Name: "cluster-1", Cluster: "cluster-1.us-west-2.eksctl.io", User: "cluster-1", Eksctl: true},
+				{Name: "cluster-2", Cluster: "cluster-2.us-west-2.eksctl.io", User: "cluster-2", Current: true, Eksctl: true},
+			}))
+		})
+	})
+})
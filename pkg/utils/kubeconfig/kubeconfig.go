@@ -10,9 +10,9 @@ import (
 
 	"os/exec"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
@@ -27,6 +27,8 @@ const (
 	HeptioAuthenticatorAWS = "heptio-authenticator-aws"
 	// AWSEKSAuthenticator defines the recently added `aws eks get-token` command
 	AWSEKSAuthenticator = "aws"
+	// AutoAuthenticator selects the first authenticator of AuthenticatorCommands found on PATH
+	AutoAuthenticator = "auto"
 )
 
 // AuthenticatorCommands returns all of authenticator commands
@@ -72,21 +74,40 @@ func New(spec *api.ClusterConfig, username, certificateAuthorityPath string) (*c
 	return c, clusterName, contextName
 }
 
-// NewForKubectl creates configuration for kubectl using a suitable authenticator
-func NewForKubectl(spec *api.ClusterConfig, username, roleARN, profile string) *clientcmdapi.Config {
+// NewForKubectl creates configuration for kubectl using the given authenticator, as returned
+// by ResolveAuthenticator
+func NewForKubectl(spec *api.ClusterConfig, username, authenticator, roleARN, profile string) *clientcmdapi.Config {
 	config, _, _ := New(spec, username, "")
-	authenticator, found := LookupAuthenticator()
-	if !found {
-		// fall back to aws-iam-authenticator
-		authenticator = AWSIAMAuthenticator
-	}
 	AppendAuthenticator(config, spec, authenticator, roleARN, profile)
 	return config
 }
 
+// ResolveAuthenticator returns the command of the given authenticator, looking it up on PATH
+// when it's empty or AutoAuthenticator, aws-iam-authenticator being the fallback
+func ResolveAuthenticator(authenticator string) (string, error) {
+	if authenticator == "" || authenticator == AutoAuthenticator {
+		found, ok := LookupAuthenticator()
+		if !ok {
+			logger.Warning("none of %s found on PATH, the kubeconfig will use %s", strings.Join(AuthenticatorCommands(), ", "), AWSIAMAuthenticator)
+			return AWSIAMAuthenticator, nil
+		}
+		logger.Debug("using authenticator %q found on PATH", found)
+		return found, nil
+	}
+	for _, cmd := range AuthenticatorCommands() {
+		if authenticator == cmd {
+			if _, err := exec.LookPath(cmd); err != nil {
+				logger.Warning("authenticator %q isn't found on PATH, kubectl will fail until it's installed", cmd)
+			}
+			return cmd, nil
+		}
+	}
+	return "", fmt.Errorf("unknown authenticator %q, valid options are: %s, %s", authenticator, strings.Join(AuthenticatorCommands(), ", "), AutoAuthenticator)
+}
+
 // AppendAuthenticator appends the AWS IAM  authenticator, and
-// if profile is non-empty string it sets AWS_PROFILE environment
-// variable also
+// if profile is non-empty string it passes --profile to the AWS CLI,
+// or sets AWS_PROFILE environment variable for other authenticators
 func AppendAuthenticator(config *clientcmdapi.Config, spec *api.ClusterConfig, authenticatorCMD, roleARN, profile string) {
 	var (
 		args        []string
@@ -127,7 +148,9 @@ func AppendAuthenticator(config *clientcmdapi.Config, spec *api.ClusterConfig, a
 
 	execConfig.Args = args
 
-	if profile != "" {
+	if profile != "" && authenticatorCMD == AWSEKSAuthenticator {
+		execConfig.Args = append(execConfig.Args, "--profile", profile)
+	} else if profile != "" {
 		execConfig.Env = append(execConfig.Env, clientcmdapi.ExecEnvVar{
 			Name:  "AWS_PROFILE",
 			Value: profile,
@@ -152,7 +175,10 @@ func Write(path string, newConfig clientcmdapi.Config, setContext bool) (string,
 	}
 
 	logger.Debug("merging kubeconfig files")
-	merged := merge(config, &newConfig)
+	merged, err := merge(config, &newConfig)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to merge kubeconfig %q", configAccess.GetDefaultFilename())
+	}
 
 	if setContext && newConfig.CurrentContext != "" {
 		logger.Debug("setting current-context to %s", newConfig.CurrentContext)
@@ -174,7 +200,24 @@ func getConfigAccess(explicitPath string) clientcmd.ConfigAccess {
 
 	return interface{}(pathOptions).(clientcmd.ConfigAccess)
 }
-func merge(existing *clientcmdapi.Config, tomerge *clientcmdapi.Config) *clientcmdapi.Config {
+
+// merge adds the clusters, users and contexts of tomerge to existing, replacing the ones with the same
+// names; it fails instead of replacing contexts and users of other clusters, which the user may have
+// written or renamed
+func merge(existing *clientcmdapi.Config, tomerge *clientcmdapi.Config) (*clientcmdapi.Config, error) {
+	for k, v := range tomerge.Contexts {
+		if context, ok := existing.Contexts[k]; ok && context.Cluster != v.Cluster {
+			return nil, fmt.Errorf("context %q already exists for cluster %q, use another context name", k, context.Cluster)
+		}
+	}
+	for k := range tomerge.AuthInfos {
+		for name, context := range existing.Contexts {
+			if _, ok := tomerge.Clusters[context.Cluster]; context.AuthInfo == k && !ok {
+				return nil, fmt.Errorf("user %q already exists for context %q of cluster %q, use another context name", k, name, context.Cluster)
+			}
+		}
+	}
+
 	for k, v := range tomerge.Clusters {
 		existing.Clusters[k] = v
 	}
@@ -185,7 +228,7 @@ func merge(existing *clientcmdapi.Config, tomerge *clientcmdapi.Config) *clientc
 		existing.Contexts[k] = v
 	}
 
-	return existing
+	return existing, nil
 }
 
 // AutoPath returns the path for the auto-generated kubeconfig
@@ -208,11 +251,12 @@ func isValidConfig(p, name string) error {
 	// as we don't want to delete any files by accident that didn't belong to us
 	ctxFmtErr := fmt.Errorf("unable to verify ownership of config %q, unexpected contex name %q", p, clientConfig.CurrentContext)
 
-	ctx := strings.Split(clientConfig.CurrentContext, "@")
-	if len(ctx) != 2 {
+	// the context may have been renamed with a context name template, so its cluster is checked
+	ctx, ok := clientConfig.Contexts[clientConfig.CurrentContext]
+	if !ok {
 		return ctxFmtErr
 	}
-	if strings.HasPrefix(ctx[1], name+".") && strings.HasSuffix(ctx[1], ".eksctl.io") {
+	if strings.HasPrefix(ctx.Cluster, name+".") && strings.HasSuffix(ctx.Cluster, ".eksctl.io") {
 		return nil
 	}
 	return ctxFmtErr
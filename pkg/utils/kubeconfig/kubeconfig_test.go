@@ -297,4 +297,38 @@ var _ = Describe("Kubeconfig", func() {
 			Expect(configFileAsBytes).To(MatchYAML(twoClustersAsBytes), "Should not change")
 		})
 	})
+
+	Context("authenticators", func() {
+		var cfg *eksctlapi.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = eksctlapi.NewClusterConfig()
+			cfg.Metadata.Name = "cluster-1"
+			cfg.Metadata.Region = "us-west-2"
+		})
+
+		It("passes the role and the profile to the AWS CLI", func() {
+			config := kubeconfig.NewForKubectl(cfg, "admin", kubeconfig.AWSEKSAuthenticator, "arn:aws:iam::123456789012:role/admin", "prod")
+			exec := config.AuthInfos[config.CurrentContext].Exec
+			Expect(exec.Command).To(Equal("aws"))
+			Expect(exec.Args).To(Equal([]string{
+				"eks", "get-token", "--cluster-name", "cluster-1", "--region", "us-west-2",
+				"--role-arn", "arn:aws:iam::123456789012:role/admin", "--profile", "prod",
+			}))
+		})
+
+		It("sets the profile of aws-iam-authenticator in its environment", func() {
+			config := kubeconfig.NewForKubectl(cfg, "admin", kubeconfig.AWSIAMAuthenticator, "arn:aws:iam::123456789012:role/admin", "prod")
+			exec := config.AuthInfos[config.CurrentContext].Exec
+			Expect(exec.Args).To(Equal([]string{"token", "-i", "cluster-1", "-r", "arn:aws:iam::123456789012:role/admin"}))
+			Expect(exec.Env).To(ContainElement(api.ExecEnvVar{Name: "AWS_PROFILE", Value: "prod"}))
+		})
+
+		It("resolves the authenticator", func() {
+			Expect(kubeconfig.ResolveAuthenticator(kubeconfig.AWSEKSAuthenticator)).To(Equal("aws"))
+			Expect(kubeconfig.ResolveAuthenticator(kubeconfig.AutoAuthenticator)).To(BeElementOf(kubeconfig.AuthenticatorCommands()))
+			_, err := kubeconfig.ResolveAuthenticator("gcloud")
+			Expect(err).To(MatchError(ContainSubstring(`unknown authenticator "gcloud"`)))
+		})
+	})
 })
@@ -0,0 +1,273 @@
+// Package oci pulls single-file artifacts from OCI registries, such as the
+// nodegroup templates that config files reference with oci:// URLs.
+//
+// See https://github.com/opencontainers/distribution-spec/blob/master/spec.md
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Scheme is the URL scheme of artifact references
+const Scheme = "oci://"
+
+const (
+	manifestMediaType       = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Reference is a parsed oci:// reference, e.g. oci://registry.example.com/org/ng-template:v1
+type Reference struct {
+	Registry   string
+	Repository string
+	// Tag is empty when the reference is pinned to a Digest
+	Tag    string
+	Digest string
+}
+
+// ParseReference parses an oci:// reference, which must have either a tag or a digest
+func ParseReference(ref string) (*Reference, error) {
+	if !strings.HasPrefix(ref, Scheme) {
+		return nil, fmt.Errorf("invalid OCI reference %q: expected the %s scheme", ref, Scheme)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(ref, Scheme), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid OCI reference %q: expected %sregistry/repository:tag", ref, Scheme)
+	}
+
+	r := &Reference{Registry: parts[0]}
+	repository := parts[1]
+	if i := strings.Index(repository, "@"); i >= 0 {
+		r.Digest = repository[i+1:]
+		repository = repository[:i]
+		if !digestRegexp.MatchString(r.Digest) {
+			return nil, fmt.Errorf("invalid OCI reference %q: unsupported digest %q", ref, r.Digest)
+		}
+	} else if i := strings.LastIndex(repository, ":"); i >= 0 {
+		r.Tag = repository[i+1:]
+		repository = repository[:i]
+	}
+	if r.Tag == "" && r.Digest == "" {
+		return nil, fmt.Errorf("invalid OCI reference %q: a tag or a digest must be set", ref)
+	}
+	r.Repository = repository
+	return r, nil
+}
+
+func (r *Reference) String() string {
+	if r.Digest != "" {
+		return fmt.Sprintf("%s%s/%s@%s", Scheme, r.Registry, r.Repository, r.Digest)
+	}
+	return fmt.Sprintf("%s%s/%s:%s", Scheme, r.Registry, r.Repository, r.Tag)
+}
+
+// Client pulls artifacts from OCI registries
+type Client struct {
+	HTTPClient *http.Client
+	// DockerConfigFile holds the credentials of the registries, as written by `docker login`
+	DockerConfigFile string
+}
+
+// NewClient returns a client that uses the credentials of the docker config file
+func NewClient() *Client {
+	dockerConfigDir := os.Getenv("DOCKER_CONFIG")
+	if dockerConfigDir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dockerConfigDir = filepath.Join(home, ".docker")
+		}
+	}
+	return &Client{
+		HTTPClient:       &http.Client{Timeout: 30 * time.Second},
+		DockerConfigFile: filepath.Join(dockerConfigDir, "config.json"),
+	}
+}
+
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+type manifest struct {
+	Layers []descriptor `json:"layers"`
+}
+
+// Pull returns the content of the single YAML layer of the artifact, the digests
+// of the manifest (when the reference is pinned) and of the layer are verified
+func (c *Client) Pull(ref *Reference) ([]byte, error) {
+	reference := ref.Tag
+	if ref.Digest != "" {
+		reference = ref.Digest
+	}
+	manifestData, err := c.get(ref, "manifests/"+reference, manifestMediaType+", "+dockerManifestMediaType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching manifest of %s", ref)
+	}
+	if ref.Digest != "" {
+		if err := verifyDigest(manifestData, ref.Digest); err != nil {
+			return nil, errors.Wrapf(err, "verifying manifest of %s", ref)
+		}
+	}
+
+	var m manifest
+	if err := json.Unmarshal(manifestData, &m); err != nil {
+		return nil, errors.Wrapf(err, "decoding manifest of %s", ref)
+	}
+	var layers []descriptor
+	for _, layer := range m.Layers {
+		if strings.Contains(layer.MediaType, "yaml") {
+			layers = append(layers, layer)
+		}
+	}
+	if len(layers) != 1 {
+		return nil, fmt.Errorf("expected %s to have exactly one YAML layer, found %d", ref, len(layers))
+	}
+
+	data, err := c.get(ref, "blobs/"+layers[0].Digest, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching layer of %s", ref)
+	}
+	if err := verifyDigest(data, layers[0].Digest); err != nil {
+		return nil, errors.Wrapf(err, "verifying layer of %s", ref)
+	}
+	return data, nil
+}
+
+func (c *Client) get(ref *Reference, path, accept string) ([]byte, error) {
+	scheme := "https"
+	if host := strings.Split(ref.Registry, ":")[0]; host == "localhost" || host == "127.0.0.1" {
+		scheme = "http"
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", scheme, ref.Registry, ref.Repository, path)
+
+	resp, err := c.do(u, accept, c.basicAuth(ref.Registry))
+	if err != nil {
+		return nil, err
+	}
+	// registries that require a token for the repository, even to pull anonymously,
+	// tell where to get it from
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		token, err := c.token(ref, challenge)
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(u, accept, "Bearer "+token); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q from %s", resp.Status, u)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (c *Client) do(u, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return c.HTTPClient.Do(req)
+}
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// token gets a bearer token from the realm of the challenge of the registry
+func (c *Client) token(ref *Reference, challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unauthorized to pull %s, check the credentials of %s in %s", ref, ref.Registry, c.DockerConfigFile)
+	}
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid authentication challenge %q from %s", challenge, ref.Registry)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.do(realm.String(), "", c.basicAuth(ref.Registry))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %q getting a token to pull %s", resp.Status, ref)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", errors.Wrapf(err, "decoding token to pull %s", ref)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// basicAuth returns the credentials of the registry from the docker config file, credential
+// helpers aren't supported
+func (c *Client) basicAuth(registry string) string {
+	data, err := ioutil.ReadFile(c.DockerConfigFile)
+	if err != nil {
+		return ""
+	}
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+	for _, key := range []string{registry, "https://" + registry} {
+		if auth, ok := config.Auths[key]; ok && auth.Auth != "" {
+			if _, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil {
+				return "Basic " + auth.Auth
+			}
+		}
+	}
+	return ""
+}
+
+func verifyDigest(data []byte, digest string) error {
+	if !digestRegexp.MatchString(digest) {
+		return fmt.Errorf("unsupported digest %q", digest)
+	}
+	if actual := fmt.Sprintf("sha256:%x", sha256.Sum256(data)); actual != digest {
+		return fmt.Errorf("digest mismatch: expected %s, got %s", digest, actual)
+	}
+	return nil
+}
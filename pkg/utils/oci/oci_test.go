@@ -0,0 +1,128 @@
+package oci_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/utils/oci"
+)
+
+func digestOf(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+var _ = Describe("OCI", func() {
+	Describe("ParseReference", func() {
+		It("parses references with a tag", func() {
+			ref, err := ParseReference("oci://registry.example.com/org/ng-template:v1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*ref).To(Equal(Reference{Registry: "registry.example.com", Repository: "org/ng-template", Tag: "v1"}))
+		})
+
+		It("parses references with a digest and a registry port", func() {
+			digest := "sha256:" + strings.Repeat("a", 64)
+			ref, err := ParseReference("oci://localhost:5000/ng-template@" + digest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*ref).To(Equal(Reference{Registry: "localhost:5000", Repository: "ng-template", Digest: digest}))
+			Expect(ref.String()).To(Equal("oci://localhost:5000/ng-template@" + digest))
+		})
+
+		It("rejects invalid references", func() {
+			for _, ref := range []string{
+				"registry.example.com/org/ng-template:v1",
+				"oci://registry.example.com",
+				"oci://registry.example.com/org/ng-template",
+				"oci://registry.example.com/org/ng-template@md5:abc",
+			} {
+				_, err := ParseReference(ref)
+				Expect(err).To(HaveOccurred(), ref)
+			}
+		})
+	})
+
+	Describe("Pull", func() {
+		var (
+			layer, manifestData []byte
+			server              *httptest.Server
+			client              *Client
+			requireToken        bool
+		)
+
+		BeforeEach(func() {
+			layer = []byte("instanceType: m5.large\n")
+			manifestData, _ = json.Marshal(map[string]interface{}{
+				"schemaVersion": 2,
+				"layers": []map[string]string{
+					{"mediaType": "application/yaml", "digest": digestOf(layer)},
+				},
+			})
+			requireToken = false
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					Expect(r.URL.Query().Get("scope")).To(Equal("repository:org/ng-template:pull"))
+					_, _ = w.Write([]byte(`{"token": "secret"}`))
+					return
+				}
+				if requireToken && r.Header.Get("Authorization") != "Bearer secret" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry"`, r.Host))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				switch r.URL.Path {
+				case "/v2/org/ng-template/manifests/v1", "/v2/org/ng-template/manifests/" + digestOf(manifestData):
+					_, _ = w.Write(manifestData)
+				default:
+					if strings.HasPrefix(r.URL.Path, "/v2/org/ng-template/blobs/") {
+						_, _ = w.Write(layer)
+						return
+					}
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			client = NewClient()
+			client.DockerConfigFile = ""
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		ref := func(suffix string) *Reference {
+			r, err := ParseReference(Scheme + strings.TrimPrefix(server.URL, "http://") + "/org/ng-template" + suffix)
+			Expect(err).NotTo(HaveOccurred())
+			return r
+		}
+
+		It("pulls the YAML layer of a tag", func() {
+			Expect(client.Pull(ref(":v1"))).To(Equal(layer))
+		})
+
+		It("pulls the YAML layer of a digest", func() {
+			Expect(client.Pull(ref("@" + digestOf(manifestData)))).To(Equal(layer))
+		})
+
+		It("gets a token when the registry requires one", func() {
+			requireToken = true
+			Expect(client.Pull(ref(":v1"))).To(Equal(layer))
+		})
+
+		It("fails when the content doesn't match its digest", func() {
+			layer = []byte("instanceType: m5.xlarge\n")
+			_, err := client.Pull(ref(":v1"))
+			Expect(err).To(MatchError(ContainSubstring("digest mismatch")))
+		})
+
+		It("fails when the tag doesn't exist", func() {
+			_, err := client.Pull(ref(":v2"))
+			Expect(err).To(MatchError(ContainSubstring("404")))
+		})
+	})
+})
@@ -7,10 +7,10 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func fmtSecurityGroupNameRegexForCluster(name string) string {
@@ -42,6 +42,10 @@ func findDanglingENIs(ec2API ec2iface.EC2API, spec *api.ClusterConfig) ([]string
 	err = ec2API.DescribeNetworkInterfacesPages(input, func(output *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
 		for _, eni := range output.NetworkInterfaces {
 			id := *eni.NetworkInterfaceId
+			if isOwnedBySharedVPCOwner(spec, eni.OwnerId) {
+				logger.Debug("found %q, but it belongs to account %s, which shares the VPC", id, spec.VPC.OwnerAccountID)
+				continue
+			}
 			for _, sg := range eni.Groups {
 				if securityGroupRE.MatchString(*sg.GroupName) {
 					logger.Debug("found %q, which belongs to our security group %q (%s)", id, *sg.GroupName, *sg.GroupId)
@@ -61,6 +65,11 @@ func findDanglingENIs(ec2API ec2iface.EC2API, spec *api.ClusterConfig) ([]string
 	return eniIDs, nil
 }
 
+// isOwnedBySharedVPCOwner returns true if a resource belongs to the account that shares the VPC
+func isOwnedBySharedVPCOwner(spec *api.ClusterConfig, ownerID *string) bool {
+	return spec.IsSharedVPC() && aws.StringValue(ownerID) == spec.VPC.OwnerAccountID
+}
+
 // CleanupNetworkInterfaces finds and deletes any dangling ENIs
 func CleanupNetworkInterfaces(ec2API ec2iface.EC2API, spec *api.ClusterConfig) error {
 	eniIDs, err := findDanglingENIs(ec2API, spec)
@@ -78,3 +87,133 @@ func CleanupNetworkInterfaces(ec2API ec2iface.EC2API, spec *api.ClusterConfig) e
 	}
 	return nil
 }
+
+// CleanupSecurityGroupReferences revokes the rules of security groups outside of
+// the cluster stack that refer to the cluster's security groups, as these
+// references prevent CloudFormation from deleting the cluster stack
+func CleanupSecurityGroupReferences(ec2API ec2iface.EC2API, spec *api.ClusterConfig) error {
+	clusterGroupIDs := map[string]struct{}{}
+	for _, id := range []string{spec.VPC.SecurityGroup, spec.VPC.SharedNodeSecurityGroup} {
+		if id != "" {
+			clusterGroupIDs[id] = struct{}{}
+		}
+	}
+	if len(clusterGroupIDs) == 0 {
+		return nil
+	}
+
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{&spec.VPC.ID},
+			},
+		},
+	}
+
+	var securityGroups []*ec2.SecurityGroup
+	err := ec2API.DescribeSecurityGroupsPages(input, func(output *ec2.DescribeSecurityGroupsOutput, _ bool) bool {
+		securityGroups = append(securityGroups, output.SecurityGroups...)
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list security groups in %q", spec.VPC.ID)
+	}
+
+	for _, sg := range securityGroups {
+		if _, ok := clusterGroupIDs[*sg.GroupId]; ok {
+			continue
+		}
+		if isOwnedBySharedVPCOwner(spec, sg.OwnerId) {
+			// security groups of the owner of a shared VPC can't be modified by other accounts
+			continue
+		}
+
+		if ingress := permissionsReferringTo(sg.IpPermissions, clusterGroupIDs); len(ingress) > 0 {
+			logger.Info("revoking %d ingress rule(s) of security group %q referring to cluster security groups", len(ingress), *sg.GroupId)
+			if _, err := ec2API.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+				GroupId:       sg.GroupId,
+				IpPermissions: ingress,
+			}); err != nil {
+				return errors.Wrapf(err, "unable to revoke ingress rules of security group %q", *sg.GroupId)
+			}
+		}
+
+		if egress := permissionsReferringTo(sg.IpPermissionsEgress, clusterGroupIDs); len(egress) > 0 {
+			logger.Info("revoking %d egress rule(s) of security group %q referring to cluster security groups", len(egress), *sg.GroupId)
+			if _, err := ec2API.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+				GroupId:       sg.GroupId,
+				IpPermissions: egress,
+			}); err != nil {
+				return errors.Wrapf(err, "unable to revoke egress rules of security group %q", *sg.GroupId)
+			}
+		}
+	}
+	return nil
+}
+
+// permissionsReferringTo returns copies of the given permissions only keeping
+// the group pairs that refer to one of the given security groups
+func permissionsReferringTo(permissions []*ec2.IpPermission, groupIDs map[string]struct{}) []*ec2.IpPermission {
+	var matching []*ec2.IpPermission
+	for _, permission := range permissions {
+		var pairs []*ec2.UserIdGroupPair
+		for _, pair := range permission.UserIdGroupPairs {
+			if _, ok := groupIDs[aws.StringValue(pair.GroupId)]; ok {
+				pairs = append(pairs, &ec2.UserIdGroupPair{GroupId: pair.GroupId, UserId: pair.UserId})
+			}
+		}
+		if len(pairs) == 0 {
+			continue
+		}
+		matching = append(matching, &ec2.IpPermission{
+			IpProtocol:       permission.IpProtocol,
+			FromPort:         permission.FromPort,
+			ToPort:           permission.ToPort,
+			UserIdGroupPairs: pairs,
+		})
+	}
+	return matching
+}
+
+// CleanupCNINetworkInterfaces finds and deletes any detached ENIs that were
+// created by the VPC CNI plugin on nodes of the cluster
+func CleanupCNINetworkInterfaces(ec2API ec2iface.EC2API, spec *api.ClusterConfig) error {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{&spec.VPC.ID},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []*string{aws.String("available")},
+			},
+			{
+				Name:   aws.String("tag:cluster.k8s.amazonaws.com/name"),
+				Values: []*string{&spec.Metadata.Name},
+			},
+		},
+	}
+
+	var eniIDs []string
+	err := ec2API.DescribeNetworkInterfacesPages(input, func(output *ec2.DescribeNetworkInterfacesOutput, _ bool) bool {
+		for _, eni := range output.NetworkInterfaces {
+			eniIDs = append(eniIDs, *eni.NetworkInterfaceId)
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list CNI network interfaces in %q", spec.VPC.ID)
+	}
+
+	for _, eniID := range eniIDs {
+		if _, err := ec2API.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String(eniID),
+		}); err != nil {
+			return errors.Wrapf(err, "unable to delete network interface %q", eniID)
+		}
+		logger.Debug("deleted CNI network interface %q", eniID)
+	}
+	return nil
+}
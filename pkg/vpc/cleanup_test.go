@@ -0,0 +1,52 @@
+package vpc
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VPC cleanup", func() {
+	Describe("permissionsReferringTo", func() {
+		clusterGroupIDs := map[string]struct{}{
+			"sg-cluster": {},
+		}
+
+		It("only keeps group pairs referring to the cluster security groups", func() {
+			permissions := []*ec2.IpPermission{
+				{
+					IpProtocol: aws.String("tcp"),
+					FromPort:   aws.Int64(443),
+					ToPort:     aws.Int64(443),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{
+						{GroupId: aws.String("sg-cluster"), UserId: aws.String("123456789012")},
+						{GroupId: aws.String("sg-other")},
+					},
+				},
+				{
+					IpProtocol: aws.String("-1"),
+					IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+				},
+			}
+
+			matching := permissionsReferringTo(permissions, clusterGroupIDs)
+			Expect(matching).To(HaveLen(1))
+			Expect(*matching[0].FromPort).To(Equal(int64(443)))
+			Expect(matching[0].IpRanges).To(BeEmpty())
+			Expect(matching[0].UserIdGroupPairs).To(HaveLen(1))
+			Expect(*matching[0].UserIdGroupPairs[0].GroupId).To(Equal("sg-cluster"))
+		})
+
+		It("returns nothing when no rule refers to the cluster security groups", func() {
+			permissions := []*ec2.IpPermission{
+				{
+					IpProtocol:       aws.String("tcp"),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-other")}},
+				},
+			}
+			Expect(permissionsReferringTo(permissions, clusterGroupIDs)).To(BeEmpty())
+		})
+	})
+})
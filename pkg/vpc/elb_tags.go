@@ -0,0 +1,132 @@
+package vpc
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	// SubnetRoleELBTag marks public subnets that can be used for internet-facing load balancers
+	SubnetRoleELBTag = "kubernetes.io/role/elb"
+	// SubnetRoleInternalELBTag marks private subnets that can be used for internal load balancers
+	SubnetRoleInternalELBTag = "kubernetes.io/role/internal-elb"
+)
+
+// SubnetTagChange describes a load balancer role tag that is missing on a subnet
+type SubnetTagChange struct {
+	SubnetID         string
+	AvailabilityZone string
+	Topology         api.SubnetTopology
+	Tag              string
+}
+
+func (c SubnetTagChange) String() string {
+	return fmt.Sprintf("%s subnet %s (%s) is missing tag %s=1", c.Topology, c.SubnetID, c.AvailabilityZone, c.Tag)
+}
+
+func subnetRoleTag(topology api.SubnetTopology) (tag, conflictingTag string) {
+	if topology == api.SubnetTopologyPrivate {
+		return SubnetRoleInternalELBTag, SubnetRoleELBTag
+	}
+	return SubnetRoleELBTag, SubnetRoleInternalELBTag
+}
+
+func hasTag(tags []*ec2.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanSubnetTagsForELB checks the tags that load balancer controllers use to
+// discover the subnets of the cluster, and returns the tags that are missing;
+// subnets that carry the tag of the other topology are reported as warnings
+func PlanSubnetTagsForELB(provider api.ClusterProvider, spec *api.ClusterConfig) ([]SubnetTagChange, error) {
+	changes := []SubnetTagChange{}
+
+	for _, topology := range api.SubnetTopologies() {
+		subnetIDs := spec.PrivateSubnetIDs()
+		if topology == api.SubnetTopologyPublic {
+			subnetIDs = spec.PublicSubnetIDs()
+		}
+		if len(subnetIDs) == 0 {
+			continue
+		}
+
+		subnets, err := describeSubnets(provider, subnetIDs...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing %s subnets", topology)
+		}
+
+		tag, conflictingTag := subnetRoleTag(topology)
+		for _, subnet := range subnets {
+			if hasTag(subnet.Tags, conflictingTag) {
+				logger.Warning("%s subnet %s is tagged with %s, load balancers may be placed in the wrong subnets", topology, *subnet.SubnetId, conflictingTag)
+			}
+			if !hasTag(subnet.Tags, tag) {
+				changes = append(changes, SubnetTagChange{
+					SubnetID:         *subnet.SubnetId,
+					AvailabilityZone: *subnet.AvailabilityZone,
+					Topology:         topology,
+					Tag:              tag,
+				})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].SubnetID < changes[j].SubnetID
+	})
+	return changes, nil
+}
+
+// ApplySubnetTagsForELB adds the missing tags returned by PlanSubnetTagsForELB
+func ApplySubnetTagsForELB(provider api.ClusterProvider, changes []SubnetTagChange) error {
+	subnetsByTag := map[string][]string{}
+	for _, change := range changes {
+		subnetsByTag[change.Tag] = append(subnetsByTag[change.Tag], change.SubnetID)
+	}
+
+	for tag, subnetIDs := range subnetsByTag {
+		input := &ec2.CreateTagsInput{
+			Resources: aws.StringSlice(subnetIDs),
+			Tags: []*ec2.Tag{{
+				Key:   aws.String(tag),
+				Value: aws.String("1"),
+			}},
+		}
+		if _, err := provider.EC2().CreateTags(input); err != nil {
+			return errors.Wrapf(err, "tagging subnets %v with %s", subnetIDs, tag)
+		}
+	}
+	return nil
+}
+
+// EnsureSubnetTagsForELB adds the load balancer role tags that are missing on the
+// subnets of the cluster, and logs every change it made
+func EnsureSubnetTagsForELB(provider api.ClusterProvider, spec *api.ClusterConfig) error {
+	changes, err := PlanSubnetTagsForELB(provider, spec)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		logger.Info("all subnets of cluster %q are tagged for load balancers", spec.Metadata.Name)
+		return nil
+	}
+	if err := ApplySubnetTagsForELB(provider, changes); err != nil {
+		return err
+	}
+	for _, change := range changes {
+		logger.Info("tagged %s subnet %s (%s) with %s=1", change.Topology, change.SubnetID, change.AvailabilityZone, change.Tag)
+	}
+	return nil
+}
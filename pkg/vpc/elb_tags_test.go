@@ -0,0 +1,81 @@
+package vpc
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("VPC - subnet tags for load balancers", func() {
+	var (
+		provider *mockprovider.MockProvider
+		cfg      *api.ClusterConfig
+	)
+
+	newSubnet := func(id, az string, tags ...string) *ec2.Subnet {
+		subnet := &ec2.Subnet{
+			SubnetId:         aws.String(id),
+			AvailabilityZone: aws.String(az),
+		}
+		for _, tag := range tags {
+			subnet.Tags = append(subnet.Tags, &ec2.Tag{Key: aws.String(tag), Value: aws.String("1")})
+		}
+		return subnet
+	}
+
+	BeforeEach(func() {
+		provider = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.VPC.Subnets = &api.ClusterSubnets{
+			Private: map[string]api.Network{
+				"us-west-2a": {ID: "subnet-private-a"},
+				"us-west-2b": {ID: "subnet-private-b"},
+			},
+			Public: map[string]api.Network{
+				"us-west-2a": {ID: "subnet-public-a"},
+			},
+		}
+
+		provider.MockEC2().On("DescribeSubnets", MatchedBy(func(input *ec2.DescribeSubnetsInput) bool {
+			return len(input.SubnetIds) == 2
+		})).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				newSubnet("subnet-private-a", "us-west-2a", SubnetRoleInternalELBTag),
+				newSubnet("subnet-private-b", "us-west-2b"),
+			},
+		}, nil)
+		provider.MockEC2().On("DescribeSubnets", MatchedBy(func(input *ec2.DescribeSubnetsInput) bool {
+			return len(input.SubnetIds) == 1
+		})).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				newSubnet("subnet-public-a", "us-west-2a", SubnetRoleInternalELBTag),
+			},
+		}, nil)
+	})
+
+	It("reports the missing tags of each topology", func() {
+		changes, err := PlanSubnetTagsForELB(provider, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]SubnetTagChange{
+			{SubnetID: "subnet-private-b", AvailabilityZone: "us-west-2b", Topology: api.SubnetTopologyPrivate, Tag: SubnetRoleInternalELBTag},
+			{SubnetID: "subnet-public-a", AvailabilityZone: "us-west-2a", Topology: api.SubnetTopologyPublic, Tag: SubnetRoleELBTag},
+		}))
+	})
+
+	It("tags the subnets grouped by tag", func() {
+		provider.MockEC2().On("CreateTags", MatchedBy(func(input *ec2.CreateTagsInput) bool {
+			return len(input.Resources) == 1
+		})).Return(&ec2.CreateTagsOutput{}, nil)
+
+		changes, err := PlanSubnetTagsForELB(provider, cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ApplySubnetTagsForELB(provider, changes)).To(Succeed())
+		Expect(provider.MockEC2().AssertNumberOfCalls(GinkgoT(), "CreateTags", 2)).To(BeTrue())
+	})
+})
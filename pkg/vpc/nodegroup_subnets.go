@@ -0,0 +1,90 @@
+package vpc
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// ValidateNodeGroupSubnets checks the subnets given explicitly to a nodegroup and sets its
+// availability zones to the zones of the subnets, see validateSubnetsOfNodeGroup
+func ValidateNodeGroupSubnets(ec2API ec2iface.EC2API, spec *api.ClusterConfig, ng *api.NodeGroup) error {
+	zones, err := validateSubnetsOfNodeGroup(ec2API, spec, ng.Name, ng.Subnets, ng.AvailabilityZones, ng.PrivateNetworking)
+	if err != nil {
+		return err
+	}
+	if zones != nil {
+		ng.AvailabilityZones = zones
+	}
+	return nil
+}
+
+// ValidateManagedNodeGroupSubnets checks the subnets given explicitly to a managed nodegroup and sets
+// its availability zones to the zones of the subnets, see validateSubnetsOfNodeGroup
+func ValidateManagedNodeGroupSubnets(ec2API ec2iface.EC2API, spec *api.ClusterConfig, ng *api.ManagedNodeGroup) error {
+	zones, err := validateSubnetsOfNodeGroup(ec2API, spec, ng.Name, ng.Subnets, ng.AvailabilityZones, ng.PrivateNetworking)
+	if err != nil {
+		return err
+	}
+	if zones != nil {
+		ng.AvailabilityZones = zones
+	}
+	return nil
+}
+
+// validateSubnetsOfNodeGroup checks that the subnets are in the VPC of the cluster, are tagged for the cluster,
+// are in the availability zones of the nodegroup when it sets them, and map public IPs unless the nodegroup uses
+// private networking; it returns the availability zones of the subnets
+func validateSubnetsOfNodeGroup(ec2API ec2iface.EC2API, spec *api.ClusterConfig, name string, subnetIDs, availabilityZones []string, privateNetworking bool) ([]string, error) {
+	if len(subnetIDs) == 0 {
+		return nil, nil
+	}
+
+	output, err := ec2API.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnetIDs),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing subnets of nodegroup %q", name)
+	}
+
+	allowedZones := map[string]bool{}
+	for _, az := range availabilityZones {
+		allowedZones[az] = true
+	}
+	clusterTag := "kubernetes.io/cluster/" + spec.Metadata.Name
+
+	zones := map[string]bool{}
+	for _, subnet := range output.Subnets {
+		subnetID, az := aws.StringValue(subnet.SubnetId), aws.StringValue(subnet.AvailabilityZone)
+		if vpcID := aws.StringValue(subnet.VpcId); vpcID != spec.VPC.ID {
+			return nil, fmt.Errorf("subnet %q of nodegroup %q is in VPC %q, not in the VPC of the cluster (%s)",
+				subnetID, name, vpcID, spec.VPC.ID)
+		}
+		if !hasTag(subnet.Tags, clusterTag) {
+			return nil, fmt.Errorf("subnet %q of nodegroup %q doesn't have the tag %q, add it with the value %q",
+				subnetID, name, clusterTag, "shared")
+		}
+		if len(allowedZones) > 0 && !allowedZones[az] {
+			return nil, fmt.Errorf("subnet %q of nodegroup %q is in %s, which is not one of its availabilityZones %v",
+				subnetID, name, az, availabilityZones)
+		}
+		if !privateNetworking && !aws.BoolValue(subnet.MapPublicIpOnLaunch) {
+			return nil, fmt.Errorf("subnet %q of nodegroup %q doesn't assign public IPs to instances, "+
+				"enable privateNetworking to use it", subnetID, name)
+		}
+		zones[az] = true
+	}
+
+	var zoneList []string
+	for az := range zones {
+		zoneList = append(zoneList, az)
+	}
+	sort.Strings(zoneList)
+	return zoneList, nil
+}
@@ -0,0 +1,103 @@
+package vpc
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("VPC - nodegroup subnets", func() {
+	var (
+		provider *mockprovider.MockProvider
+		cfg      *api.ClusterConfig
+		ng       *api.NodeGroup
+	)
+
+	clusterTag := &ec2.Tag{Key: aws.String("kubernetes.io/cluster/test"), Value: aws.String("shared")}
+
+	mockSubnets := func(subnets ...*ec2.Subnet) {
+		provider.MockEC2().On("DescribeSubnets", MatchedBy(func(input *ec2.DescribeSubnetsInput) bool {
+			return len(input.SubnetIds) == len(ng.Subnets)
+		})).Return(&ec2.DescribeSubnetsOutput{Subnets: subnets}, nil)
+	}
+
+	newSubnet := func(id, az string) *ec2.Subnet {
+		return &ec2.Subnet{
+			SubnetId:         aws.String(id),
+			AvailabilityZone: aws.String(az),
+			VpcId:            aws.String("vpc-1"),
+			Tags:             []*ec2.Tag{clusterTag},
+		}
+	}
+
+	BeforeEach(func() {
+		provider = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		cfg.VPC.ID = "vpc-1"
+		ng = cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.PrivateNetworking = true
+		ng.Subnets = []string{"subnet-1"}
+	})
+
+	It("does nothing when no subnet is given", func() {
+		ng.Subnets = nil
+		Expect(ValidateNodeGroupSubnets(provider.EC2(), cfg, ng)).To(Succeed())
+		provider.MockEC2().AssertNotCalled(GinkgoT(), "DescribeSubnets", Anything)
+	})
+
+	It("sets the availability zone of a single-subnet nodegroup", func() {
+		mockSubnets(newSubnet("subnet-1", "us-west-2b"))
+		Expect(ValidateNodeGroupSubnets(provider.EC2(), cfg, ng)).To(Succeed())
+		Expect(ng.AvailabilityZones).To(Equal([]string{"us-west-2b"}))
+		Expect(ng.Zone()).To(Equal("us-west-2b"))
+	})
+
+	It("sets the availability zones of the subnets of a managed nodegroup", func() {
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng-1"
+		mng.PrivateNetworking = true
+		mng.Subnets = []string{"subnet-1", "subnet-2"}
+		ng.Subnets = mng.Subnets
+		mockSubnets(newSubnet("subnet-2", "us-west-2c"), newSubnet("subnet-1", "us-west-2a"))
+		Expect(ValidateManagedNodeGroupSubnets(provider.EC2(), cfg, mng)).To(Succeed())
+		Expect(mng.AvailabilityZones).To(Equal([]string{"us-west-2a", "us-west-2c"}))
+	})
+
+	It("rejects subnets of another VPC", func() {
+		subnet := newSubnet("subnet-1", "us-west-2b")
+		subnet.VpcId = aws.String("vpc-2")
+		mockSubnets(subnet)
+		err := ValidateNodeGroupSubnets(provider.EC2(), cfg, ng)
+		Expect(err).To(MatchError(`subnet "subnet-1" of nodegroup "ng-1" is in VPC "vpc-2", not in the VPC of the cluster (vpc-1)`))
+	})
+
+	It("rejects subnets without the cluster tag", func() {
+		subnet := newSubnet("subnet-1", "us-west-2b")
+		subnet.Tags = nil
+		mockSubnets(subnet)
+		err := ValidateNodeGroupSubnets(provider.EC2(), cfg, ng)
+		Expect(err).To(MatchError(ContainSubstring(`doesn't have the tag "kubernetes.io/cluster/test"`)))
+	})
+
+	It("rejects subnets outside of the availability zones of the nodegroup", func() {
+		ng.AvailabilityZones = []string{"us-west-2a"}
+		mockSubnets(newSubnet("subnet-1", "us-west-2b"))
+		err := ValidateNodeGroupSubnets(provider.EC2(), cfg, ng)
+		Expect(err).To(MatchError(ContainSubstring("is in us-west-2b, which is not one of its availabilityZones [us-west-2a]")))
+	})
+
+	It("rejects subnets without public IPs for nodegroups without private networking", func() {
+		ng.PrivateNetworking = false
+		mockSubnets(newSubnet("subnet-1", "us-west-2b"))
+		err := ValidateNodeGroupSubnets(provider.EC2(), cfg, ng)
+		Expect(err).To(MatchError(ContainSubstring("doesn't assign public IPs to instances, enable privateNetworking to use it")))
+	})
+})
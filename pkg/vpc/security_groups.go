@@ -0,0 +1,75 @@
+package vpc
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// kubeletPort is the port of kubelet, which the control plane reaches for logs, exec and port forwarding
+const kubeletPort = 10250
+
+// ValidateNodeGroupSecurityGroups checks that the security groups attached to a nodegroup are in the
+// VPC of the cluster and, when the nodegroup opts out of the security groups created by eksctl, that
+// one of them still allows the control plane to reach kubelet
+func ValidateNodeGroupSecurityGroups(ec2API ec2iface.EC2API, spec *api.ClusterConfig, ng *api.NodeGroup) error {
+	if ng.SecurityGroups == nil || len(ng.SecurityGroups.AttachIDs) == 0 {
+		return nil
+	}
+
+	output, err := ec2API.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: aws.StringSlice(ng.SecurityGroups.AttachIDs),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "describing security groups of nodegroup %q", ng.Name)
+	}
+
+	for _, sg := range output.SecurityGroups {
+		if aws.StringValue(sg.VpcId) != spec.VPC.ID {
+			return fmt.Errorf("security group %q of nodegroup %q is in VPC %q, not in the VPC of the cluster (%s)",
+				aws.StringValue(sg.GroupId), ng.Name, aws.StringValue(sg.VpcId), spec.VPC.ID)
+		}
+	}
+
+	if !api.IsDisabled(ng.SecurityGroups.WithLocal) || !api.IsDisabled(ng.SecurityGroups.WithShared) {
+		return nil
+	}
+	for _, sg := range output.SecurityGroups {
+		for _, permission := range sg.IpPermissions {
+			if allowsKubeletAccess(permission, spec.VPC.SecurityGroup) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("none of the security groups of nodegroup %q allow the control plane (%s) to reach kubelet on port %d, "+
+		"add such a rule to one of securityGroups.attachIDs or enable securityGroups.withLocal", ng.Name, spec.VPC.SecurityGroup, kubeletPort)
+}
+
+// allowsKubeletAccess returns true if an ingress rule allows the control plane security group,
+// or any address range, to reach the kubelet port
+func allowsKubeletAccess(permission *ec2.IpPermission, controlPlaneSecurityGroup string) bool {
+	switch aws.StringValue(permission.IpProtocol) {
+	case "-1":
+	case "tcp", "6":
+		if aws.Int64Value(permission.FromPort) > kubeletPort || aws.Int64Value(permission.ToPort) < kubeletPort {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if len(permission.IpRanges) > 0 {
+		return true
+	}
+	for _, pair := range permission.UserIdGroupPairs {
+		if aws.StringValue(pair.GroupId) == controlPlaneSecurityGroup {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package vpc
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("VPC - nodegroup security groups", func() {
+	var (
+		provider *mockprovider.MockProvider
+		cfg      *api.ClusterConfig
+		ng       *api.NodeGroup
+	)
+
+	mockSecurityGroups := func(sgs ...*ec2.SecurityGroup) {
+		provider.MockEC2().On("DescribeSecurityGroups", MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+			return len(input.GroupIds) == len(ng.SecurityGroups.AttachIDs)
+		})).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: sgs}, nil)
+	}
+
+	kubeletIngress := &ec2.IpPermission{
+		IpProtocol: aws.String("tcp"),
+		FromPort:   aws.Int64(1025),
+		ToPort:     aws.Int64(65535),
+		UserIdGroupPairs: []*ec2.UserIdGroupPair{
+			{GroupId: aws.String("sg-control-plane")},
+		},
+	}
+
+	BeforeEach(func() {
+		provider = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.VPC.ID = "vpc-1"
+		cfg.VPC.SecurityGroup = "sg-control-plane"
+		ng = cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.SecurityGroups = &api.NodeGroupSGs{
+			AttachIDs:  []string{"sg-attached"},
+			WithShared: api.Disabled(),
+			WithLocal:  api.Disabled(),
+		}
+	})
+
+	It("does nothing when no security group is attached", func() {
+		ng.SecurityGroups = &api.NodeGroupSGs{}
+		Expect(ValidateNodeGroupSecurityGroups(provider.EC2(), cfg, ng)).To(Succeed())
+		provider.MockEC2().AssertNotCalled(GinkgoT(), "DescribeSecurityGroups", Anything)
+	})
+
+	It("accepts attached security groups allowing the control plane to reach kubelet", func() {
+		mockSecurityGroups(&ec2.SecurityGroup{
+			GroupId:       aws.String("sg-attached"),
+			VpcId:         aws.String("vpc-1"),
+			IpPermissions: []*ec2.IpPermission{kubeletIngress},
+		})
+		Expect(ValidateNodeGroupSecurityGroups(provider.EC2(), cfg, ng)).To(Succeed())
+	})
+
+	It("rejects security groups of another VPC", func() {
+		mockSecurityGroups(&ec2.SecurityGroup{
+			GroupId:       aws.String("sg-attached"),
+			VpcId:         aws.String("vpc-2"),
+			IpPermissions: []*ec2.IpPermission{kubeletIngress},
+		})
+		err := ValidateNodeGroupSecurityGroups(provider.EC2(), cfg, ng)
+		Expect(err).To(MatchError(ContainSubstring(`security group "sg-attached" of nodegroup "ng-1"`)))
+		Expect(err).To(MatchError(ContainSubstring(`is in VPC "vpc-2"`)))
+	})
+
+	It("rejects opting out of the default security groups when kubelet isn't reachable", func() {
+		mockSecurityGroups(&ec2.SecurityGroup{
+			GroupId: aws.String("sg-attached"),
+			VpcId:   aws.String("vpc-1"),
+			IpPermissions: []*ec2.IpPermission{
+				{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int64(443),
+					ToPort:           aws.Int64(443),
+					UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-control-plane")}},
+				},
+			},
+		})
+		err := ValidateNodeGroupSecurityGroups(provider.EC2(), cfg, ng)
+		Expect(err).To(MatchError(ContainSubstring("allow the control plane (sg-control-plane) to reach kubelet on port 10250")))
+	})
+
+	It("doesn't require kubelet access when the local security group is kept", func() {
+		ng.SecurityGroups.WithLocal = api.Enabled()
+		mockSecurityGroups(&ec2.SecurityGroup{
+			GroupId: aws.String("sg-attached"),
+			VpcId:   aws.String("vpc-1"),
+		})
+		Expect(ValidateNodeGroupSecurityGroups(provider.EC2(), cfg, ng)).To(Succeed())
+	})
+})
@@ -0,0 +1,73 @@
+package vpc
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// UseSharedVPCOwner records the account that owns the subnets of the cluster when they're shared
+// with the account of the current session through AWS RAM, and checks that the account can
+// create the resources of the cluster in the shared VPC
+func UseSharedVPCOwner(provider api.ClusterProvider, spec *api.ClusterConfig) error {
+	subnetIDs := append(spec.PrivateSubnetIDs(), spec.PublicSubnetIDs()...)
+	for _, zone := range spec.LocalZones() {
+		if id := spec.VPC.LocalZoneSubnets[zone].ID; id != "" {
+			subnetIDs = append(subnetIDs, id)
+		}
+	}
+	if len(subnetIDs) == 0 {
+		return nil
+	}
+
+	subnets, err := describeSubnets(provider, subnetIDs...)
+	if err != nil {
+		return err
+	}
+	owner := ""
+	for _, sn := range subnets {
+		if owner == "" {
+			owner = aws.StringValue(sn.OwnerId)
+		} else if subnetOwner := aws.StringValue(sn.OwnerId); subnetOwner != owner {
+			return fmt.Errorf("subnet %s is owned by account %s, but other subnets of the cluster are owned by account %s", *sn.SubnetId, subnetOwner, owner)
+		}
+	}
+
+	identity, err := provider.STS().GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Wrap(err, "getting the account of the current session")
+	}
+	account := aws.StringValue(identity.Account)
+	if owner == "" || owner == account {
+		return nil
+	}
+
+	spec.VPC.OwnerAccountID = owner
+	logger.Info("subnets of VPC %q are shared by account %s, eksctl will not modify them", spec.VPC.ID, owner)
+	return validateSharedVPCPermissions(provider, spec, account)
+}
+
+// validateSharedVPCPermissions checks that the account can create security groups in the
+// shared VPC, which participants of a shared VPC need for the cluster and its nodegroups
+func validateSharedVPCPermissions(provider api.ClusterProvider, spec *api.ClusterConfig, account string) error {
+	_, err := provider.EC2().CreateSecurityGroup(&ec2.CreateSecurityGroupInput{
+		DryRun:      aws.Bool(true),
+		GroupName:   aws.String(fmt.Sprintf("eksctl-%s-permissions-check", spec.Metadata.Name)),
+		Description: aws.String("Checks the permissions of the account in the shared VPC"),
+		VpcId:       aws.String(spec.VPC.ID),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "DryRunOperation" {
+		return nil
+	}
+	if err == nil {
+		return fmt.Errorf("unexpected response from dry run of creating a security group in VPC %q", spec.VPC.ID)
+	}
+	return errors.Wrapf(err, "account %s is not allowed to create security groups in VPC %q shared by account %s", account, spec.VPC.ID, spec.VPC.OwnerAccountID)
+}
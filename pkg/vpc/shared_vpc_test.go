@@ -0,0 +1,80 @@
+package vpc
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("VPC - shared VPC", func() {
+	var (
+		provider *mockprovider.MockProvider
+		cfg      *api.ClusterConfig
+	)
+
+	mockSubnetOwners := func(owners ...string) {
+		var subnets []*ec2.Subnet
+		for i, owner := range owners {
+			subnets = append(subnets, &ec2.Subnet{
+				SubnetId: aws.String([]string{"subnet-a", "subnet-b"}[i]),
+				OwnerId:  aws.String(owner),
+			})
+		}
+		provider.MockEC2().On("DescribeSubnets", Anything).Return(&ec2.DescribeSubnetsOutput{Subnets: subnets}, nil)
+	}
+
+	BeforeEach(func() {
+		provider = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "shared"
+		cfg.VPC.ID = "vpc-1"
+		cfg.VPC.Subnets = &api.ClusterSubnets{
+			Private: map[string]api.Network{
+				"us-west-2a": {ID: "subnet-a"},
+				"us-west-2b": {ID: "subnet-b"},
+			},
+		}
+		provider.MockSTS().On("GetCallerIdentity", Anything).Return(&sts.GetCallerIdentityOutput{
+			Account: aws.String("111111111111"),
+		}, nil)
+	})
+
+	It("doesn't record an owner for subnets of the account", func() {
+		mockSubnetOwners("111111111111", "111111111111")
+		Expect(UseSharedVPCOwner(provider, cfg)).To(Succeed())
+		Expect(cfg.IsSharedVPC()).To(BeFalse())
+	})
+
+	It("records the owner of shared subnets after checking the permissions", func() {
+		mockSubnetOwners("222222222222", "222222222222")
+		provider.MockEC2().On("CreateSecurityGroup", MatchedBy(func(input *ec2.CreateSecurityGroupInput) bool {
+			return aws.BoolValue(input.DryRun) && *input.VpcId == "vpc-1"
+		})).Return(nil, awserr.New("DryRunOperation", "Request would have succeeded", nil))
+
+		Expect(UseSharedVPCOwner(provider, cfg)).To(Succeed())
+		Expect(cfg.IsSharedVPC()).To(BeTrue())
+		Expect(cfg.VPC.OwnerAccountID).To(Equal("222222222222"))
+	})
+
+	It("fails when the account can't create security groups in the shared VPC", func() {
+		mockSubnetOwners("222222222222", "222222222222")
+		provider.MockEC2().On("CreateSecurityGroup", Anything).Return(nil, awserr.New("UnauthorizedOperation", "not authorized", errors.New("denied")))
+
+		Expect(UseSharedVPCOwner(provider, cfg)).To(MatchError(ContainSubstring("account 111111111111 is not allowed to create security groups")))
+	})
+
+	It("fails when the subnets have different owners", func() {
+		mockSubnetOwners("111111111111", "222222222222")
+		Expect(UseSharedVPCOwner(provider, cfg)).To(MatchError(ContainSubstring("subnet subnet-b is owned by account 222222222222")))
+	})
+})
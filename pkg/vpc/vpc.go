@@ -8,8 +8,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kops/util/pkg/slice"
 
-	"github.com/kris-nova/logger"
-
 	"github.com/aws/aws-sdk-go/aws"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -17,6 +15,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/ipnet"
 
 	"k8s.io/kops/pkg/util/subnet"
@@ -64,6 +63,22 @@ func SetSubnets(spec *api.ClusterConfig) error {
 		logger.Info("subnets for %s - public:%s private:%s", zone, public.String(), private.String())
 	}
 
+	// subnets of local zones use the blocks left after the availability zones, unless set
+	next := 2 * zonesTotal
+	for _, zone := range spec.LocalZones() {
+		subnet := vpc.LocalZoneSubnets[zone]
+		if subnet.CIDR != nil || subnet.ID != "" {
+			continue
+		}
+		if next >= len(zoneCIDRs) {
+			return fmt.Errorf("insufficient number of subnets (have %d, but need %d) for %d availability zones and %d local zones", len(zoneCIDRs), next+1, zonesTotal, len(vpc.LocalZoneSubnets))
+		}
+		subnet.CIDR = &ipnet.IPNet{IPNet: *zoneCIDRs[next]}
+		vpc.LocalZoneSubnets[zone] = subnet
+		logger.Info("subnet for local zone %s - %s", zone, zoneCIDRs[next].String())
+		next++
+	}
+
 	return nil
 }
 
@@ -130,6 +145,13 @@ func UseFromCluster(provider api.ClusterProvider, stack *cfn.Stack, spec *api.Cl
 		outputs.ClusterSubnetsPublic: func(v string) error {
 			return ImportSubnetsFromList(provider, spec, api.SubnetTopologyPublic, strings.Split(v, ","))
 		},
+		outputs.ClusterSharedVPCOwnerAccount: func(v string) error {
+			spec.VPC.OwnerAccountID = v
+			return nil
+		},
+		outputs.ClusterSubnetsLocalZones: func(v string) error {
+			return ImportLocalZoneSubnetsFromList(provider, spec, strings.Split(v, ","))
+		},
 	}
 
 	if !outputs.Exists(*stack, outputs.ClusterSubnetsPublic) &&
@@ -142,6 +164,60 @@ func UseFromCluster(provider api.ClusterProvider, stack *cfn.Stack, spec *api.Cl
 	return outputs.Collect(*stack, requiredCollectors, optionalCollectors)
 }
 
+// UseFromUnownedCluster retrieves the VPC configuration from a cluster
+// that was not created by eksctl, based on the EKS API description of it;
+// subnets are treated as public when they map public IPs on launch
+// NOTE: it doesn't expect any fields in spec.VPC to be set, the remote state
+// is treated as the source of truth
+func UseFromUnownedCluster(provider api.ClusterProvider, cluster *awseks.Cluster, spec *api.ClusterConfig) error {
+	if spec.VPC == nil {
+		spec.VPC = api.NewClusterVPC()
+	}
+	spec.VPC.CIDR = nil
+
+	vpcConfig := cluster.ResourcesVpcConfig
+	if vpcConfig == nil || vpcConfig.VpcId == nil {
+		return fmt.Errorf("cluster %q has no VPC configuration", spec.Metadata.Name)
+	}
+	spec.VPC.ID = *vpcConfig.VpcId
+
+	spec.VPC.ClusterEndpoints = &api.ClusterEndpoints{
+		PublicAccess:  vpcConfig.EndpointPublicAccess,
+		PrivateAccess: vpcConfig.EndpointPrivateAccess,
+	}
+
+	if vpcConfig.ClusterSecurityGroupId != nil {
+		spec.VPC.SharedNodeSecurityGroup = *vpcConfig.ClusterSecurityGroupId
+	}
+	switch {
+	case len(vpcConfig.SecurityGroupIds) > 0:
+		spec.VPC.SecurityGroup = *vpcConfig.SecurityGroupIds[0]
+	case spec.VPC.SharedNodeSecurityGroup != "":
+		spec.VPC.SecurityGroup = spec.VPC.SharedNodeSecurityGroup
+	default:
+		return fmt.Errorf("cluster %q has no security groups", spec.Metadata.Name)
+	}
+
+	subnets, err := describeSubnets(provider, aws.StringValueSlice(vpcConfig.SubnetIds)...)
+	if err != nil {
+		return err
+	}
+
+	var public, private []*ec2.Subnet
+	for _, sn := range subnets {
+		if aws.BoolValue(sn.MapPublicIpOnLaunch) {
+			public = append(public, sn)
+		} else {
+			private = append(private, sn)
+		}
+	}
+
+	if err := ImportSubnets(provider, spec, api.SubnetTopologyPrivate, private); err != nil {
+		return err
+	}
+	return ImportSubnets(provider, spec, api.SubnetTopologyPublic, public)
+}
+
 // importVPC will update spec with VPC ID/CIDR
 // NOTE: it does respect all fields set in spec.VPC, and will error if
 // there is a mismatch of local vs remote states
@@ -226,6 +302,28 @@ func ImportSubnetsFromList(provider api.ClusterProvider, spec *api.ClusterConfig
 	return ImportSubnets(provider, spec, topology, subnets)
 }
 
+// ImportLocalZoneSubnetsFromList will update spec with the subnets of Local Zones and Wavelength Zones,
+// keyed by their zone; they must be in the VPC of the cluster
+func ImportLocalZoneSubnetsFromList(provider api.ClusterProvider, spec *api.ClusterConfig, subnetIDs []string) error {
+	if len(subnetIDs) == 0 {
+		return nil
+	}
+	subnets, err := describeSubnets(provider, subnetIDs...)
+	if err != nil {
+		return err
+	}
+
+	for _, sn := range subnets {
+		if spec.VPC.ID != "" && spec.VPC.ID != *sn.VpcId {
+			return fmt.Errorf("given %s is in %s, not in %s", *sn.SubnetId, *sn.VpcId, spec.VPC.ID)
+		}
+		if err := spec.ImportLocalZoneSubnet(*sn.AvailabilityZone, *sn.SubnetId, *sn.CidrBlock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func ValidateLegacySubnetsForNodeGroups(spec *api.ClusterConfig, provider api.ClusterProvider) error {
 	subnetsToValidate := sets.NewString()
 
@@ -7,7 +7,7 @@
 // assets/vpc-resource-controller-dep.yaml (1.101kB)
 // assets/vpc-resource-controller.yaml (673B)
 
-package addons
+package windows
 
 import (
 	"bytes"
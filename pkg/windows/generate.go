@@ -1,3 +1,3 @@
-package addons
+package windows
 
 //go:generate ${GOBIN}/go-bindata -pkg ${GOPACKAGE} -prefix assets -nometadata -o assets.go assets
@@ -1,14 +1,18 @@
-package addons
+package windows
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"time"
 
 	"github.com/cloudflare/cfssl/csr"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+	"github.com/weaveworks/eksctl/pkg/addons"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
 
@@ -24,8 +28,17 @@ import (
 const (
 	vpcControllerNamespace = metav1.NamespaceSystem
 	webhookServiceName     = "vpc-admission-webhook"
+	webhookCertsSecretName = "vpc-admission-webhook-certs"
 
 	certWaitTimeout = 45 * time.Second
+
+	// CertificateRenewBefore is how long before its expiry the certificate of the VPC admission
+	// webhook is rotated when the VPC controller is installed again
+	CertificateRenewBefore = 30 * 24 * time.Hour
+
+	// certificateChecksumAnnotation restarts the webhook when its certificate changes, as it only
+	// loads it when it starts
+	certificateChecksumAnnotation = "eksctl.io/certificate-checksum"
 )
 
 // NewVPCController creates a new VPCController
@@ -38,7 +51,8 @@ func NewVPCController(rawClient kubernetes.RawClientInterface, clusterStatus *ap
 	}
 }
 
-// A VPCController deploys Windows VPC controller to a cluster
+// A VPCController manages the lifecycle of the Windows VPC controller of a cluster, made of the VPC
+// resource controller and of the VPC admission webhook
 type VPCController struct {
 	rawClient     kubernetes.RawClientInterface
 	clusterStatus *api.ClusterStatus
@@ -46,27 +60,58 @@ type VPCController struct {
 	planMode      bool
 }
 
-// Deploy deploys VPC controller to the specified cluster
-func (v *VPCController) Deploy() (err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			if ae, ok := r.(*assetError); ok {
-				err = ae
-			} else {
-				panic(r)
-			}
-		}
-	}()
+// Deploy installs the VPC controller, or upgrades it when it's already installed; the certificate of
+// the webhook is issued when it's missing, and rotated when it expires within CertificateRenewBefore
+func (v *VPCController) Deploy() error {
+	return v.deploy(false)
+}
+
+// RotateCertificate deploys the VPC controller like Deploy, but issues a new certificate for the
+// webhook regardless of the expiry of the current one
+func (v *VPCController) RotateCertificate() error {
+	return v.deploy(true)
+}
+
+func (v *VPCController) deploy(rotateCert bool) (err error) {
+	defer recoverAssetError(&err)
 
 	if err := v.deployVPCResourceController(); err != nil {
 		return err
 	}
 
-	if err := v.generateCert(); err != nil {
+	cert, err := v.ensureCert(rotateCert)
+	if err != nil {
 		return err
 	}
 
-	return v.deployVPCWebhook()
+	return v.deployVPCWebhook(cert)
+}
+
+// Delete uninstalls the VPC controller; the webhook configuration is deleted first so that the API
+// server stops calling the webhook before it's gone
+func (v *VPCController) Delete() (err error) {
+	defer recoverAssetError(&err)
+
+	manifests := [][]byte{
+		mustGenerateAsset(vpcAdmissionWebhookConfigYamlBytes),
+		mustGenerateAsset(vpcAdmissionWebhookDepYamlBytes),
+		mustGenerateAsset(vpcAdmissionWebhookYamlBytes),
+		mustGenerateAsset(vpcAdmissionWebhookCsrYamlBytes),
+		mustGenerateAsset(vpcResourceControllerDepYamlBytes),
+		mustGenerateAsset(vpcResourceControllerYamlBytes),
+	}
+	for _, manifest := range manifests {
+		list, err := kubernetes.NewList(manifest)
+		if err != nil {
+			return err
+		}
+		for _, item := range list.Items {
+			if err := v.deleteRawResource(item.Object); err != nil {
+				return err
+			}
+		}
+	}
+	return v.deleteRawResource(newCertSecret(nil, nil))
 }
 
 type typeAssertionError struct {
@@ -78,51 +123,79 @@ func (t *typeAssertionError) Error() string {
 	return fmt.Sprintf("expected type to be %T; got %T", t.expected, t.got)
 }
 
-func (v *VPCController) generateCert() error {
+// ensureCert returns the certificate of the webhook, issuing a new one when it's missing, invalid,
+// about to expire or when rotate is true
+func (v *VPCController) ensureCert(rotate bool) ([]byte, error) {
+	secret, err := v.rawClient.ClientSet().CoreV1().Secrets(vpcControllerNamespace).Get(webhookCertsSecretName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil && !rotate {
+		cert := secret.Data["cert.pem"]
+		expiry, err := CertificateExpiry(cert)
+		switch {
+		case err != nil:
+			logger.Warning("issuing a new certificate for %s: %v", webhookServiceName, err)
+		case time.Until(expiry) > CertificateRenewBefore:
+			logger.Info("certificate of %s is valid until %s", webhookServiceName, expiry.Format(time.RFC3339))
+			return cert, nil
+		default:
+			logger.Info("certificate of %s expires at %s, rotating it", webhookServiceName, expiry.Format(time.RFC3339))
+		}
+	}
+
+	if v.planMode {
+		logger.Info("(plan) would have issued a new certificate for %s", webhookServiceName)
+		return nil, nil
+	}
+	return v.issueCert()
+}
+
+// CertificateExpiry returns the expiry of a PEM encoded certificate
+func CertificateExpiry(certPEM []byte) (time.Time, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, errors.New("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "parsing certificate")
+	}
+	return cert.NotAfter, nil
+}
+
+func (v *VPCController) issueCert() ([]byte, error) {
 	var (
 		csrName      = fmt.Sprintf("%s.%s", webhookServiceName, vpcControllerNamespace)
 		csrClientSet = v.rawClient.ClientSet().CertificatesV1beta1().CertificateSigningRequests()
 	)
 
-	hasApprovedCert, err := v.hasApprovedCert()
-	if err != nil {
-		return err
-	}
-	if hasApprovedCert {
-		// Delete existing CSR if the secret is missing
-		_, err := v.rawClient.ClientSet().CoreV1().Secrets(vpcControllerNamespace).Get("vpc-admission-webhook-certs", metav1.GetOptions{})
-		if err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-			if err := csrClientSet.Delete(csrName, &metav1.DeleteOptions{}); err != nil {
-				return err
-			}
-		}
-		return nil
+	// a CSR can only be approved once, the previous one is deleted to request a new certificate
+	if err := csrClientSet.Delete(csrName, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "deleting previous CertificateSigningRequest")
 	}
 
 	csrPEM, privateKey, err := generateCertReq(webhookServiceName, vpcControllerNamespace)
 	if err != nil {
-		return errors.Wrap(err, "generating CSR")
+		return nil, errors.Wrap(err, "generating CSR")
 	}
 
 	manifest := mustGenerateAsset(vpcAdmissionWebhookCsrYamlBytes)
 	rawExtension, err := kubernetes.NewRawExtension(manifest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	certificateSigningRequest, ok := rawExtension.Object.(*certsv1beta1.CertificateSigningRequest)
 	if !ok {
-		return &typeAssertionError{&certsv1beta1.CertificateSigningRequest{}, rawExtension.Object}
+		return nil, &typeAssertionError{&certsv1beta1.CertificateSigningRequest{}, rawExtension.Object}
 	}
 
 	certificateSigningRequest.Spec.Request = csrPEM
 	certificateSigningRequest.Name = csrName
 
 	if err := v.applyRawResource(certificateSigningRequest); err != nil {
-		return errors.Wrap(err, "creating CertificateSigningRequest")
+		return nil, errors.Wrap(err, "creating CertificateSigningRequest")
 	}
 
 	certificateSigningRequest.Status.Conditions = []certsv1beta1.CertificateSigningRequestCondition{
@@ -135,17 +208,20 @@ func (v *VPCController) generateCert() error {
 	}
 
 	if _, err := csrClientSet.UpdateApproval(certificateSigningRequest); err != nil {
-		return errors.Wrap(err, "updating approval")
+		return nil, errors.Wrap(err, "updating approval")
 	}
 
 	logger.Info("waiting for certificate to be available")
 
 	cert, err := watchCSRApproval(csrClientSet, csrName, certWaitTimeout)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return v.createCertSecrets(privateKey, cert)
+	if err := v.applyRawResource(newCertSecret(privateKey, cert)); err != nil {
+		return nil, errors.Wrap(err, "error creating secret")
+	}
+	return cert, nil
 }
 
 func watchCSRApproval(csrClientSet v1beta1.CertificateSigningRequestInterface, csrName string, timeout time.Duration) ([]byte, error) {
@@ -183,14 +259,14 @@ func watchCSRApproval(csrClientSet v1beta1.CertificateSigningRequestInterface, c
 	}
 }
 
-func (v *VPCController) createCertSecrets(key, cert []byte) error {
-	secret := &corev1.Secret{
+func newCertSecret(key, cert []byte) *corev1.Secret {
+	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
-			Kind:       "secret",
+			Kind:       "Secret",
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "vpc-admission-webhook-certs",
+			Name:      webhookCertsSecretName,
 			Namespace: vpcControllerNamespace,
 		},
 		Data: map[string][]byte{
@@ -198,26 +274,26 @@ func (v *VPCController) createCertSecrets(key, cert []byte) error {
 			"cert.pem": cert,
 		},
 	}
-
-	err := v.applyRawResource(secret)
-	if err != nil {
-		return errors.Wrap(err, "error creating secret")
-	}
-	return err
 }
 
 func (v *VPCController) deployVPCResourceController() error {
 	if err := v.applyResources(mustGenerateAsset(vpcResourceControllerYamlBytes)); err != nil {
 		return err
 	}
-	return v.applyDeployment(mustGenerateAsset(vpcResourceControllerDepYamlBytes))
+	return v.applyDeployment(mustGenerateAsset(vpcResourceControllerDepYamlBytes), nil)
 }
 
-func (v *VPCController) deployVPCWebhook() error {
+func (v *VPCController) deployVPCWebhook(cert []byte) error {
 	if err := v.applyResources(mustGenerateAsset(vpcAdmissionWebhookYamlBytes)); err != nil {
 		return err
 	}
-	if err := v.applyDeployment(mustGenerateAsset(vpcAdmissionWebhookDepYamlBytes)); err != nil {
+	var podAnnotations map[string]string
+	if cert != nil {
+		podAnnotations = map[string]string{
+			certificateChecksumAnnotation: fmt.Sprintf("%x", sha256.Sum256(cert)),
+		}
+	}
+	if err := v.applyDeployment(mustGenerateAsset(vpcAdmissionWebhookDepYamlBytes), podAnnotations); err != nil {
 		return err
 	}
 
@@ -236,31 +312,6 @@ func (v *VPCController) deployVPCWebhook() error {
 	return v.applyRawResource(rawExtension.Object)
 }
 
-func (v *VPCController) hasApprovedCert() (bool, error) {
-	csrClientSet := v.rawClient.ClientSet().CertificatesV1beta1().CertificateSigningRequests()
-	request, err := csrClientSet.Get(fmt.Sprintf("%s.%s", webhookServiceName, vpcControllerNamespace), metav1.GetOptions{})
-	if err != nil {
-		if !apierrors.IsNotFound(err) {
-			return false, err
-		}
-		return false, nil
-	}
-
-	conditions := request.Status.Conditions
-	switch len(conditions) {
-	case 1:
-		if conditions[0].Type == certsv1beta1.CertificateApproved {
-			return true, nil
-		}
-		return false, fmt.Errorf("expected certificate to be approved; got %q", conditions[0].Type)
-
-	case 0:
-		return false, nil
-	default:
-		return false, fmt.Errorf("unexpected number of request conditions: %d", len(conditions))
-	}
-}
-
 func (v *VPCController) applyResources(manifests []byte) error {
 	list, err := kubernetes.NewList(manifests)
 	if err != nil {
@@ -275,7 +326,7 @@ func (v *VPCController) applyResources(manifests []byte) error {
 	return nil
 }
 
-func (v *VPCController) applyDeployment(manifests []byte) error {
+func (v *VPCController) applyDeployment(manifests []byte, podAnnotations map[string]string) error {
 	rawExtension, err := kubernetes.NewRawExtension(manifests)
 	if err != nil {
 		return err
@@ -285,9 +336,15 @@ func (v *VPCController) applyDeployment(manifests []byte) error {
 	if !ok {
 		return &typeAssertionError{&appsv1.Deployment{}, rawExtension.Object}
 	}
-	if err := UseRegionalImage(&deployment.Spec.Template, v.region); err != nil {
+	if err := addons.UseRegionalImage(&deployment.Spec.Template, v.region); err != nil {
 		return err
 	}
+	for key, value := range podAnnotations {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[key] = value
+	}
 	return v.applyRawResource(rawExtension.Object)
 }
 
@@ -333,6 +390,28 @@ func (v *VPCController) applyRawResource(object runtime.Object) error {
 	return nil
 }
 
+func (v *VPCController) deleteRawResource(object runtime.Object) error {
+	rawResource, err := v.rawClient.NewRawResource(object)
+	if err != nil {
+		return err
+	}
+	if v.planMode {
+		if exists, err := rawResource.Exists(); err != nil || !exists {
+			return err
+		}
+		logger.Info(rawResource.LogAction(true, "deleted"))
+		return nil
+	}
+	msg, err := rawResource.DeleteSync()
+	if err != nil {
+		return err
+	}
+	if msg != "" {
+		logger.Info(msg)
+	}
+	return nil
+}
+
 type assetError struct {
 	error
 }
@@ -341,6 +420,17 @@ func (ae *assetError) Error() string {
 	return fmt.Sprintf("unexpected error generating assets: %v", ae.error.Error())
 }
 
+// recoverAssetError turns the panics of mustGenerateAsset into the error returned by the caller
+func recoverAssetError(err *error) {
+	if r := recover(); r != nil {
+		if ae, ok := r.(*assetError); ok {
+			*err = ae
+		} else {
+			panic(r)
+		}
+	}
+}
+
 type assetFunc func() ([]byte, error)
 
 func mustGenerateAsset(assetFunc assetFunc) []byte {
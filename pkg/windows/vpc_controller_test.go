@@ -0,0 +1,79 @@
+package windows
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+type fakeRawClient struct {
+	*testutils.FakeRawClient
+	clientSet *fake.Clientset
+}
+
+func (c *fakeRawClient) ClientSet() kubernetes.Interface { return c.clientSet }
+
+func newCertPEM(notAfter time.Time) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "vpc-admission-webhook.kube-system.svc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+var _ = Describe("VPC controller", func() {
+	newController := func(cert []byte) *VPCController {
+		var objects []runtime.Object
+		if cert != nil {
+			objects = append(objects, newCertSecret([]byte("key"), cert))
+		}
+		rawClient := &fakeRawClient{
+			FakeRawClient: testutils.NewFakeRawClient(),
+			clientSet:     fake.NewSimpleClientset(objects...),
+		}
+		return NewVPCController(rawClient, nil, "us-west-2", true)
+	}
+
+	It("reads the expiry of certificates", func() {
+		notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+		expiry, err := CertificateExpiry(newCertPEM(notAfter))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expiry).To(Equal(notAfter))
+
+		_, err = CertificateExpiry([]byte("not a certificate"))
+		Expect(err).To(MatchError("no PEM encoded certificate found"))
+	})
+
+	It("keeps the certificate of the webhook until it's about to expire", func() {
+		cert := newCertPEM(time.Now().Add(365 * 24 * time.Hour))
+		Expect(newController(cert).ensureCert(false)).To(Equal(cert))
+
+		By("issuing a new certificate when rotation is requested")
+		Expect(newController(cert).ensureCert(true)).To(BeNil())
+	})
+
+	It("issues a new certificate when it's missing or about to expire", func() {
+		Expect(newController(nil).ensureCert(false)).To(BeNil())
+
+		cert := newCertPEM(time.Now().Add(CertificateRenewBefore - time.Hour))
+		Expect(newController(cert).ensureCert(false)).To(BeNil())
+	})
+})